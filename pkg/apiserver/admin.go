@@ -0,0 +1,291 @@
+package apiserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/featureflag"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+const (
+	BaseAdmin = "/admin"
+
+	redactedValue = "***"
+)
+
+type AdminAuthSettings struct {
+	Username string `cfg:"username"`
+	Password string `cfg:"password"`
+	// if set, requests can authenticate with an "Authorization: Bearer <token>" header instead of basic auth
+	Token string `cfg:"token"`
+}
+
+type AdminSettings struct {
+	Enabled bool              `cfg:"enabled" default:"false"`
+	Port    int               `cfg:"port" default:"8092"`
+	Auth    AdminAuthSettings `cfg:"auth"`
+	// config values with a key containing one of these words are redacted in the config dump
+	Redact []string `cfg:"redact" default:"password,secret,token,credential,private"`
+}
+
+type AdminBuildInfo struct {
+	AppId     cfg.AppId `json:"appId"`
+	Version   string    `json:"version"`
+	GoVersion string    `json:"goVersion"`
+	Module    string    `json:"module"`
+}
+
+type adminLogLevel struct {
+	Level string `json:"level" binding:"required"`
+}
+
+// Admin exposes operational endpoints on a separate port: a redacted dump of the config, the current log level
+// (which can be switched at runtime), the feature flags of the featureflag client, build information
+// and the metrics most recently published by the metric daemon. Like the profiling endpoints, the port is not
+// meant to be exposed by your load balancer.
+type Admin struct {
+	kernel.BackgroundModule
+	kernel.ServiceStage
+
+	logger   mon.Logger
+	server   *http.Server
+	settings *AdminSettings
+}
+
+func NewAdmin() kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		settings := &AdminSettings{}
+		config.UnmarshalKey("api.admin", settings)
+
+		flags, err := featureflag.ProvideClient(config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("can not create feature flag client: %w", err)
+		}
+
+		gin.SetMode(gin.ReleaseMode)
+		router := gin.New()
+
+		return NewAdminWithInterfaces(config, logger, router, flags, settings), nil
+	}
+}
+
+func NewAdminWithInterfaces(config cfg.Config, logger mon.Logger, router *gin.Engine, flags featureflag.Client, settings *AdminSettings) *Admin {
+	logger = logger.WithChannel("admin")
+
+	router.Use(adminAuth(settings.Auth))
+	AddAdminEndpoints(router, config, logger, flags, settings)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", settings.Port),
+		Handler: router,
+	}
+
+	return &Admin{
+		logger:   logger,
+		server:   server,
+		settings: settings,
+	}
+}
+
+func AddAdminEndpoints(router gin.IRouter, config cfg.Config, logger mon.Logger, flags featureflag.Client, settings *AdminSettings) {
+	group := router.Group(BaseAdmin)
+
+	group.GET("/config", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, redactSettings(config, "", config.AllSettings(), settings.Redact))
+	})
+
+	group.GET("/config/files", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, cfg.ConfigFiles(config))
+	})
+
+	group.GET("/log-level", func(ginCtx *gin.Context) {
+		switcher, ok := logger.(mon.LevelSwitcher)
+
+		if !ok {
+			abortWithError(ginCtx, http.StatusNotImplemented, fmt.Errorf("logger of type %T can not switch its level", logger))
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, adminLogLevel{Level: switcher.Level()})
+	})
+
+	group.PUT("/log-level", func(ginCtx *gin.Context) {
+		switcher, ok := logger.(mon.LevelSwitcher)
+
+		if !ok {
+			abortWithError(ginCtx, http.StatusNotImplemented, fmt.Errorf("logger of type %T can not switch its level", logger))
+			return
+		}
+
+		input := &adminLogLevel{}
+
+		if err := ginCtx.ShouldBindJSON(input); err != nil {
+			abortWithError(ginCtx, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := switcher.SetLevel(input.Level); err != nil {
+			abortWithError(ginCtx, http.StatusBadRequest, err)
+			return
+		}
+
+		logger.Infof("switched log level to %s", input.Level)
+		ginCtx.JSON(http.StatusOK, adminLogLevel{Level: switcher.Level()})
+	})
+
+	group.GET("/features", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, flags.Flags(ginCtx.Request.Context()))
+	})
+
+	group.GET("/build", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, getAdminBuildInfo(config))
+	})
+
+	group.GET("/metrics", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, mon.GetMetricSnapshot())
+	})
+}
+
+func (a *Admin) Run(ctx context.Context) error {
+	if !a.settings.Enabled {
+		a.logger.Info("admin endpoints not enabled..")
+		return nil
+	}
+
+	go a.waitForStop(ctx)
+
+	a.logger.Infof("serving admin endpoints on port %d", a.settings.Port)
+	err := a.server.ListenAndServe()
+
+	if err != http.ErrServerClosed {
+		a.logger.Error(err, "admin server closed unexpected")
+		return err
+	}
+
+	return nil
+}
+
+func (a *Admin) waitForStop(ctx context.Context) {
+	<-ctx.Done()
+	err := a.server.Close()
+
+	if err != nil {
+		a.logger.Error(err, "admin server close")
+	}
+}
+
+func adminAuth(settings AdminAuthSettings) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if settings.Username == "" && settings.Token == "" {
+			return
+		}
+
+		if settings.Token != "" {
+			header := ginCtx.GetHeader("Authorization")
+
+			if subtle.ConstantTimeCompare([]byte(header), []byte("Bearer "+settings.Token)) == 1 {
+				return
+			}
+		}
+
+		if settings.Username != "" {
+			username, password, ok := ginCtx.Request.BasicAuth()
+
+			if ok && subtle.ConstantTimeCompare([]byte(username), []byte(settings.Username)) == 1 &&
+				subtle.ConstantTimeCompare([]byte(password), []byte(settings.Password)) == 1 {
+				return
+			}
+
+			ginCtx.Header("WWW-Authenticate", `Basic realm="Authorization Required"`)
+		}
+
+		ginCtx.AbortWithStatus(http.StatusUnauthorized)
+	}
+}
+
+func redactSettings(config cfg.Config, prefix string, settings map[string]interface{}, redact []string) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(settings))
+
+	for key, value := range settings {
+		if shouldRedact(key, redact) {
+			redacted[key] = redactedValue
+			continue
+		}
+
+		redacted[key] = redactValue(config, joinSettingsPath(prefix, key), value, redact)
+	}
+
+	return redacted
+}
+
+func redactValue(config cfg.Config, path string, value interface{}, redact []string) interface{} {
+	if cfg.IsSensitiveKey(config, path) {
+		return redactedValue
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactSettings(config, path, v, redact)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+
+		for i := range v {
+			values[i] = redactValue(config, joinSettingsPath(path, strconv.Itoa(i)), v[i], redact)
+		}
+
+		return values
+	default:
+		if cfg.IsEncryptedValue(value) {
+			return redactedValue
+		}
+
+		return value
+	}
+}
+
+func joinSettingsPath(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+func shouldRedact(key string, redact []string) bool {
+	key = strings.ToLower(key)
+
+	for _, word := range redact {
+		if word != "" && strings.Contains(key, strings.ToLower(word)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getAdminBuildInfo(config cfg.Config) *AdminBuildInfo {
+	info := &AdminBuildInfo{
+		AppId:     cfg.GetAppIdFromConfig(config),
+		Version:   config.GetString("app_version", ""),
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		info.Module = buildInfo.Main.Path
+
+		if info.Version == "" {
+			info.Version = buildInfo.Main.Version
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,126 @@
+package apiserver_test
+
+import (
+	"bytes"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/featureflag"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getAdminRouter(t *testing.T, settings *apiserver.AdminSettings) (*gin.Engine, mon.GosoLog) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"env":         "test",
+		"app_project": "project",
+		"app_family":  "family",
+		"app_name":    "admin-test",
+		"api_key":     "enc:kms:Y2lwaGVydGV4dA==",
+		"db": map[string]interface{}{
+			"default": map[string]interface{}{
+				"uri": map[string]interface{}{
+					"host":     "localhost",
+					"password": "gosoline",
+				},
+			},
+		},
+	}))
+	assert.NoError(t, err)
+
+	err = config.Option(cfg.WithSensitiveSetting("payment.api_key_id", "pk_live_resolved"))
+	assert.NoError(t, err)
+
+	flags := featureflag.NewClientWithInterfaces(mocks.NewLoggerMockedAll(), clock.NewFakeClock(), featureflag.NewConfigProviderWithInterfaces(map[string]*featureflag.Flag{
+		"new_checkout": {
+			Key:     "new_checkout",
+			Enabled: true,
+		},
+	}), &featureflag.Settings{
+		CacheTtl: time.Minute,
+	})
+
+	logger := mon.NewLoggerWithInterfaces(clockwork.NewFakeClock(), &bytes.Buffer{})
+	apiserver.NewAdminWithInterfaces(config, logger, ginEngine, flags, settings)
+
+	return ginEngine, logger
+}
+
+func TestAdmin_Endpoints(t *testing.T) {
+	ginEngine, logger := getAdminRouter(t, &apiserver.AdminSettings{
+		Redact: []string{"password"},
+	})
+
+	httpRecorder := httptest.NewRecorder()
+	ginEngine.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodGet, apiserver.BaseAdmin+"/config", nil))
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+	assert.Contains(t, httpRecorder.Body.String(), `"host":"localhost"`)
+	assert.Contains(t, httpRecorder.Body.String(), `"password":"***"`)
+	assert.Contains(t, httpRecorder.Body.String(), `"api_key":"***"`)
+	assert.Contains(t, httpRecorder.Body.String(), `"payment":{"api_key_id":"***"}`)
+	assert.NotContains(t, httpRecorder.Body.String(), "gosoline")
+	assert.NotContains(t, httpRecorder.Body.String(), "pk_live_resolved")
+
+	httpRecorder = httptest.NewRecorder()
+	ginEngine.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodGet, apiserver.BaseAdmin+"/config/files", nil))
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+	assert.JSONEq(t, `[]`, httpRecorder.Body.String())
+
+	httpRecorder = httptest.NewRecorder()
+	ginEngine.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodGet, apiserver.BaseAdmin+"/features", nil))
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+	assert.JSONEq(t, `{"new_checkout":{"key":"new_checkout","enabled":true,"value":null,"tenants":null}}`, httpRecorder.Body.String())
+
+	httpRecorder = httptest.NewRecorder()
+	ginEngine.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodGet, apiserver.BaseAdmin+"/build", nil))
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+	assert.Contains(t, httpRecorder.Body.String(), `"Application":"admin-test"`)
+
+	httpRecorder = httptest.NewRecorder()
+	ginEngine.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodPut, apiserver.BaseAdmin+"/log-level", strings.NewReader(`{"level":"debug"}`)))
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+	assert.JSONEq(t, `{"level":"debug"}`, httpRecorder.Body.String())
+	assert.Equal(t, mon.Debug, logger.(mon.LevelSwitcher).Level())
+
+	httpRecorder = httptest.NewRecorder()
+	ginEngine.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodPut, apiserver.BaseAdmin+"/log-level", strings.NewReader(`{"level":"verbose"}`)))
+	assert.Equal(t, http.StatusBadRequest, httpRecorder.Code)
+}
+
+func TestAdmin_Auth(t *testing.T) {
+	ginEngine, _ := getAdminRouter(t, &apiserver.AdminSettings{
+		Auth: apiserver.AdminAuthSettings{
+			Username: "admin",
+			Password: "secret",
+			Token:    "token",
+		},
+	})
+
+	httpRecorder := httptest.NewRecorder()
+	ginEngine.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodGet, apiserver.BaseAdmin+"/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, httpRecorder.Code)
+
+	httpRecorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, apiserver.BaseAdmin+"/metrics", nil)
+	request.SetBasicAuth("admin", "secret")
+	ginEngine.ServeHTTP(httpRecorder, request)
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+
+	httpRecorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, apiserver.BaseAdmin+"/metrics", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	ginEngine.ServeHTTP(httpRecorder, request)
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+}
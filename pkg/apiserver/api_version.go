@@ -0,0 +1,111 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type apiVersionCtxKey int
+
+const apiVersionKey apiVersionCtxKey = 0
+
+// VersionTransformer converts the body of a response written by a handler shared between several versions
+// into the representation of a specific version.
+type VersionTransformer func(ctx context.Context, body interface{}) (interface{}, error)
+
+// VersionSettings are read from api.versions.<version> when the server is created.
+type VersionSettings struct {
+	Deprecated bool `cfg:"deprecated" default:"false"`
+	// the date after which the version will be removed, emitted as Sunset header
+	Sunset time.Time `cfg:"sunset"`
+	// link to the documentation of the deprecation or the successor version
+	Link string `cfg:"link"`
+}
+
+type apiVersion struct {
+	name         string
+	transformers []VersionTransformer
+	settings     *VersionSettings
+}
+
+// Version creates a route group for the given version (e.g. v1) with the version as base path. The same handlers can
+// be added to several version groups, the transformers of a group are applied to the response bodies of all of its
+// routes. Use ApiVersionFromContext to branch on the version inside of a shared handler. Deprecation and sunset
+// headers are configured at api.versions.<version>.
+func (d *Definitions) Version(version string, transformers ...VersionTransformer) *Definitions {
+	group := d.Group(fmt.Sprintf("/%s", version))
+	group.version = &apiVersion{
+		name:         version,
+		transformers: transformers,
+		settings:     &VersionSettings{},
+	}
+
+	group.Use(versionMiddleware(group.version))
+
+	return group
+}
+
+// ApiVersionFromContext returns the version of the route group handling the request.
+func ApiVersionFromContext(ctx context.Context) (string, bool) {
+	version, ok := ctx.Value(apiVersionKey).(*apiVersion)
+
+	if !ok {
+		return "", false
+	}
+
+	return version.name, true
+}
+
+func versionMiddleware(version *apiVersion) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		settings := version.settings
+
+		if settings.Deprecated {
+			ginCtx.Header("Deprecation", "true")
+		}
+
+		if !settings.Sunset.IsZero() {
+			ginCtx.Header("Sunset", settings.Sunset.UTC().Format(http.TimeFormat))
+		}
+
+		if settings.Link != "" && (settings.Deprecated || !settings.Sunset.IsZero()) {
+			ginCtx.Header("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, settings.Link))
+		}
+
+		ctx := context.WithValue(ginCtx.Request.Context(), apiVersionKey, version)
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+	}
+}
+
+func transformVersionedResponse(ctx context.Context, resp *Response) error {
+	version, ok := ctx.Value(apiVersionKey).(*apiVersion)
+
+	if !ok || resp.Body == nil {
+		return nil
+	}
+
+	var err error
+
+	for _, transformer := range version.transformers {
+		if resp.Body, err = transformer(ctx, resp.Body); err != nil {
+			return fmt.Errorf("can not transform response for version %s: %w", version.name, err)
+		}
+	}
+
+	return nil
+}
+
+func configureVersions(config cfg.Config, definitions *Definitions) {
+	if definitions.version != nil {
+		key := fmt.Sprintf("api.versions.%s", definitions.version.name)
+		config.UnmarshalKey(key, definitions.version.settings)
+	}
+
+	for _, child := range definitions.children {
+		configureVersions(config, child)
+	}
+}
@@ -0,0 +1,64 @@
+package apiserver
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type versionedHandler struct{}
+
+func (h versionedHandler) Handle(ctx context.Context, _ *Request) (*Response, error) {
+	version, _ := ApiVersionFromContext(ctx)
+
+	return NewJsonResponse(map[string]string{
+		"name":    "foo",
+		"version": version,
+	}), nil
+}
+
+func TestDefinitions_Version(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	definitions := &Definitions{}
+	v1 := definitions.Version("v1", func(ctx context.Context, body interface{}) (interface{}, error) {
+		return map[string]string{
+			"title": body.(map[string]string)["name"],
+		}, nil
+	})
+	v2 := definitions.Version("v2")
+
+	v1.version.settings = &VersionSettings{
+		Deprecated: true,
+		Sunset:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		Link:       "https://example.com/migrate-to-v2",
+	}
+
+	for _, group := range []*Definitions{v1, v2} {
+		group.GET("/item", CreateHandler(versionedHandler{}))
+	}
+
+	router := gin.New()
+	buildRouter(definitions, router)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/v1/item", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"title":"foo"}`, response.Body.String())
+	assert.Equal(t, "true", response.Header().Get("Deprecation"))
+	assert.Equal(t, "Tue, 01 Jan 2030 00:00:00 GMT", response.Header().Get("Sunset"))
+	assert.Equal(t, `<https://example.com/migrate-to-v2>; rel="deprecation"`, response.Header().Get("Link"))
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/v2/item", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"name":"foo","version":"v2"}`, response.Body.String())
+	assert.Empty(t, response.Header().Get("Deprecation"))
+	assert.Empty(t, response.Header().Get("Sunset"))
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const (
+	ByNamedApiKey       = "namedApiKey"
+	configNamedApiKeys  = "api_auth_named_keys"
+	AttributeIdentifier = "identifier"
+)
+
+// ApiKeyRecord is the value stored in a kvstore to look up an api key by its value.
+type ApiKeyRecord struct {
+	Identifier string `json:"identifier"`
+	Key        string `json:"key"`
+}
+
+// ApiKeyLookup returns the identifier of the given api key. If the key is not known, the returned bool is false.
+type ApiKeyLookup func(ctx context.Context, key string) (identifier string, found bool, err error)
+
+type apiKeyAuthenticator struct {
+	logger   mon.Logger
+	provider ApiKeyProvider
+	lookup   ApiKeyLookup
+}
+
+func NewApiKeyHandler(config cfg.Config, logger mon.Logger, provider ApiKeyProvider) gin.HandlerFunc {
+	lookup := ProvideApiKeyLookupFromConfig(config)
+	auth := NewApiKeyAuthenticatorWithInterfaces(logger, provider, lookup)
+
+	return func(ginCtx *gin.Context) {
+		valid, err := auth.IsValid(ginCtx)
+
+		if valid {
+			return
+		}
+
+		if err == nil {
+			err = fmt.Errorf("the api key wasn't valid nor was there an error")
+		}
+
+		ginCtx.JSON(http.StatusUnauthorized, gin.H{"err": err.Error()})
+		ginCtx.Abort()
+	}
+}
+
+// NewApiKeyAuthenticator authenticates requests by the api key provided in the X-API-KEY header. The keys are
+// configured as a map of identifier to key in api_auth_named_keys. In contrast to the config key authenticator
+// the identifier of the key is added to the subject and the logger context, so you can tell your clients apart.
+func NewApiKeyAuthenticator(config cfg.Config, logger mon.Logger) Authenticator {
+	lookup := ProvideApiKeyLookupFromConfig(config)
+
+	return NewApiKeyAuthenticatorWithInterfaces(logger, ProvideValueFromHeader(HeaderApiKey), lookup)
+}
+
+func NewApiKeyAuthenticatorWithInterfaces(logger mon.Logger, provider ApiKeyProvider, lookup ApiKeyLookup) Authenticator {
+	return &apiKeyAuthenticator{
+		logger:   logger,
+		provider: provider,
+		lookup:   lookup,
+	}
+}
+
+func (a *apiKeyAuthenticator) IsValid(ginCtx *gin.Context) (bool, error) {
+	apiKey := a.provider(ginCtx)
+
+	if apiKey == "" {
+		return false, fmt.Errorf("no api key provided")
+	}
+
+	identifier, found, err := a.lookup(ginCtx.Request.Context(), apiKey)
+
+	if err != nil {
+		return false, fmt.Errorf("can not lookup api key: %w", err)
+	}
+
+	if !found {
+		return false, fmt.Errorf("api key does not match")
+	}
+
+	user := &Subject{
+		Name:            identifier,
+		Anonymous:       false,
+		AuthenticatedBy: ByNamedApiKey,
+		Attributes: map[string]interface{}{
+			AttributeApiKey:     apiKey,
+			AttributeIdentifier: identifier,
+		},
+	}
+
+	RequestWithSubject(ginCtx, user)
+	requestWithIdentifier(ginCtx, identifier)
+
+	return true, nil
+}
+
+func ProvideApiKeyLookupFromConfig(config cfg.Config) ApiKeyLookup {
+	keys := config.GetStringMapString(configNamedApiKeys, map[string]string{})
+
+	return ProvideApiKeyLookupFromMap(keys)
+}
+
+// ProvideApiKeyLookupFromMap looks up api keys in a map of identifier to key.
+func ProvideApiKeyLookupFromMap(keys map[string]string) ApiKeyLookup {
+	return func(_ context.Context, key string) (string, bool, error) {
+		for identifier, expected := range keys {
+			if expected != "" && subtle.ConstantTimeCompare([]byte(key), []byte(expected)) == 1 {
+				return identifier, true, nil
+			}
+		}
+
+		return "", false, nil
+	}
+}
+
+// ProvideApiKeyLookupFromGetter looks up api keys in a kvstore containing ApiKeyRecords keyed by the api key.
+func ProvideApiKeyLookupFromGetter(getter Getter) ApiKeyLookup {
+	return func(ctx context.Context, key string) (string, bool, error) {
+		record := &ApiKeyRecord{}
+		found, err := getter.Get(ctx, key, record)
+
+		if err != nil || !found {
+			return "", false, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(key), []byte(record.Key)) != 1 {
+			return "", false, nil
+		}
+
+		return record.Identifier, true, nil
+	}
+}
+
+func requestWithIdentifier(ginCtx *gin.Context, identifier string) {
+	reqCtx := mon.AppendLoggerContextField(ginCtx.Request.Context(), map[string]interface{}{
+		"auth_identifier": identifier,
+	})
+
+	ginCtx.Request = ginCtx.Request.WithContext(reqCtx)
+}
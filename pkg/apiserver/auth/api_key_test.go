@@ -0,0 +1,40 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver/auth"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApiKey_Authenticate_InvalidKey(t *testing.T) {
+	logger, ginCtx := getHeaderKeyMocks("t")
+
+	lookup := auth.ProvideApiKeyLookupFromMap(map[string]string{"client": "a"})
+	a := auth.NewApiKeyAuthenticatorWithInterfaces(logger, auth.ProvideValueFromHeader(auth.HeaderApiKey), lookup)
+	_, err := a.IsValid(ginCtx)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "api key does not match", err.Error())
+	}
+}
+
+func TestApiKey_Authenticate_ValidKey(t *testing.T) {
+	logger, ginCtx := getHeaderKeyMocks("t")
+	ginCtx.Request = ginCtx.Request.WithContext(context.Background())
+
+	lookup := auth.ProvideApiKeyLookupFromMap(map[string]string{"client": "t"})
+	a := auth.NewApiKeyAuthenticatorWithInterfaces(logger, auth.ProvideValueFromHeader(auth.HeaderApiKey), lookup)
+	valid, err := a.IsValid(ginCtx)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	subject := auth.GetSubject(ginCtx.Request.Context())
+	assert.Equal(t, "client", subject.Name)
+	assert.Equal(t, "client", subject.Attributes[auth.AttributeIdentifier])
+
+	fields := mon.ContextLoggerFieldsResolver(ginCtx.Request.Context())
+	assert.Equal(t, "client", fields["auth_identifier"])
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"github.com/applike/gosoline/pkg/reqctx"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,26 +13,24 @@ type Authenticator interface {
 	IsValid(ginCtx *gin.Context) (bool, error)
 }
 
-type subjectKeyType int
-
-var subjectKey = new(subjectKeyType)
-
-type Subject struct {
-	Name            string
-	Anonymous       bool
-	AuthenticatedBy string
-	Attributes      map[string]interface{}
-}
+// Subject is stored in the context with reqctx.WithSubject, so packages which don't depend on the api server can
+// read it with reqctx.LookupSubject
+type Subject = reqctx.Subject
 
 func RequestWithSubject(ginCtx *gin.Context, subject *Subject) {
 	reqCtx := ginCtx.Request.Context()
-	newCtx := context.WithValue(reqCtx, subjectKey, subject)
+	newCtx := reqctx.WithSubject(reqCtx, subject)
 
 	ginCtx.Request = ginCtx.Request.WithContext(newCtx)
 }
 
+// LookupSubject returns the subject of the request or false if the request was not authenticated.
+func LookupSubject(ctx context.Context) (*Subject, bool) {
+	return reqctx.LookupSubject(ctx)
+}
+
 func GetSubject(ctx context.Context) *Subject {
-	if user, ok := ctx.Value(subjectKey).(*Subject); ok {
+	if user, ok := reqctx.LookupSubject(ctx); ok {
 		return user
 	}
 
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	baseErrors "errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -12,6 +13,13 @@ func NewChainHandler(authenticators map[string]Authenticator) gin.HandlerFunc {
 		for n, a := range authenticators {
 			valid, err := a.IsValid(ginCtx)
 
+			if baseErrors.Is(err, ErrHmacBodyTooLarge) {
+				ginCtx.JSON(http.StatusRequestEntityTooLarge, gin.H{"err": err.Error()})
+				ginCtx.Abort()
+
+				return
+			}
+
 			if err != nil {
 				errors[n] = err.Error()
 				continue
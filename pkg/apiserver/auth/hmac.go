@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	ByHmac             = "hmac"
+	HeaderHmacKeyId    = "X-AUTH-KEY-ID"
+	HeaderHmacTime     = "X-AUTH-TIMESTAMP"
+	HeaderHmacSign     = "X-AUTH-SIGNATURE"
+	configHmacKeys     = "api_auth_hmac_keys"
+	configHmacMaxSkew  = "api_auth_hmac_max_skew"
+	configHmacMaxBody  = "api_auth_hmac_max_body_size"
+	AttributeHmacKeyId = "hmacKeyId"
+
+	// errBodyTooLarge is the (unexported) error message of the reader returned by http.MaxBytesReader
+	errBodyTooLarge = "http: request body too large"
+)
+
+// ErrHmacBodyTooLarge is returned for requests with a larger body than allowed. The body has to be read
+// completely before the signature can be checked, so it is limited to keep unauthenticated clients from
+// making the server buffer arbitrary amounts of data.
+var ErrHmacBodyTooLarge = errors.New("the request body is too large")
+
+// HmacKeyRecord is the value stored in a kvstore to look up a hmac secret by its key id.
+type HmacKeyRecord struct {
+	KeyId  string `json:"keyId"`
+	Secret string `json:"secret"`
+}
+
+// HmacSecretLookup returns the secret of the given key id. If the key id is not known, the returned bool is false.
+type HmacSecretLookup func(ctx context.Context, keyId string) (secret string, found bool, err error)
+
+type hmacAuthenticator struct {
+	logger      mon.Logger
+	clock       func() time.Time
+	maxSkew     time.Duration
+	maxBodySize int64
+	lookup      HmacSecretLookup
+}
+
+func NewHmacHandler(config cfg.Config, logger mon.Logger) gin.HandlerFunc {
+	auth := NewHmacAuthenticator(config, logger)
+
+	return func(ginCtx *gin.Context) {
+		valid, err := auth.IsValid(ginCtx)
+
+		if valid {
+			return
+		}
+
+		if err == nil {
+			err = fmt.Errorf("the signature wasn't valid nor was there an error")
+		}
+
+		if errors.Is(err, ErrHmacBodyTooLarge) {
+			ginCtx.JSON(http.StatusRequestEntityTooLarge, gin.H{"err": err.Error()})
+			ginCtx.Abort()
+
+			return
+		}
+
+		ginCtx.JSON(http.StatusUnauthorized, gin.H{"err": err.Error()})
+		ginCtx.Abort()
+	}
+}
+
+// NewHmacAuthenticator authenticates requests signed with a shared secret. A client has to send
+// its key id, the current unix timestamp and the signature of the request (see SignRequest) in the
+// X-AUTH-KEY-ID, X-AUTH-TIMESTAMP and X-AUTH-SIGNATURE headers. The secrets are configured as a
+// map of key id to secret in api_auth_hmac_keys. Bodies larger than api_auth_hmac_max_body_size
+// bytes (1MiB by default) are rejected with ErrHmacBodyTooLarge.
+func NewHmacAuthenticator(config cfg.Config, logger mon.Logger) Authenticator {
+	maxSkew := config.GetDuration(configHmacMaxSkew, 5*time.Minute)
+	maxBodySize := int64(config.GetInt(configHmacMaxBody, 1<<20))
+	lookup := ProvideHmacSecretLookupFromConfig(config)
+
+	return NewHmacAuthenticatorWithInterfaces(logger, time.Now, maxSkew, maxBodySize, lookup)
+}
+
+func NewHmacAuthenticatorWithInterfaces(logger mon.Logger, clock func() time.Time, maxSkew time.Duration, maxBodySize int64, lookup HmacSecretLookup) Authenticator {
+	return &hmacAuthenticator{
+		logger:      logger,
+		clock:       clock,
+		maxSkew:     maxSkew,
+		maxBodySize: maxBodySize,
+		lookup:      lookup,
+	}
+}
+
+func (a *hmacAuthenticator) IsValid(ginCtx *gin.Context) (bool, error) {
+	keyId := ginCtx.GetHeader(HeaderHmacKeyId)
+	timestamp := ginCtx.GetHeader(HeaderHmacTime)
+	signature := ginCtx.GetHeader(HeaderHmacSign)
+
+	if keyId == "" || timestamp == "" || signature == "" {
+		return false, fmt.Errorf("no signature provided")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid timestamp provided")
+	}
+
+	skew := a.clock().Sub(time.Unix(unix, 0))
+	if skew > a.maxSkew || skew < -a.maxSkew {
+		return false, fmt.Errorf("timestamp is outside of the allowed time window")
+	}
+
+	secret, found, err := a.lookup(ginCtx.Request.Context(), keyId)
+
+	if err != nil {
+		return false, fmt.Errorf("can not lookup hmac key: %w", err)
+	}
+
+	if !found {
+		return false, fmt.Errorf("invalid signature provided")
+	}
+
+	body, err := a.readBody(ginCtx)
+	if err != nil {
+		return false, err
+	}
+
+	expected := SignRequest(secret, ginCtx.Request.Method, ginCtx.Request.URL.RequestURI(), timestamp, body)
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return false, fmt.Errorf("invalid signature provided")
+	}
+
+	user := &Subject{
+		Name:            keyId,
+		Anonymous:       false,
+		AuthenticatedBy: ByHmac,
+		Attributes: map[string]interface{}{
+			AttributeHmacKeyId:  keyId,
+			AttributeIdentifier: keyId,
+		},
+	}
+
+	RequestWithSubject(ginCtx, user)
+	requestWithIdentifier(ginCtx, keyId)
+
+	return true, nil
+}
+
+// SignRequest computes the hex encoded HMAC-SHA256 signature of a request. The signed
+// payload consists of the method, the request uri (path and query), the timestamp and
+// the hex encoded SHA256 hash of the body, separated by new lines.
+func SignRequest(secret string, method string, requestUri string, timestamp string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	payload := fmt.Sprintf("%s\n%s\n%s\n%s", method, requestUri, timestamp, hex.EncodeToString(bodyHash[:]))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func ProvideHmacSecretLookupFromConfig(config cfg.Config) HmacSecretLookup {
+	secrets := config.GetStringMapString(configHmacKeys, map[string]string{})
+
+	return ProvideHmacSecretLookupFromMap(secrets)
+}
+
+// ProvideHmacSecretLookupFromMap looks up secrets in a map of key id to secret.
+func ProvideHmacSecretLookupFromMap(secrets map[string]string) HmacSecretLookup {
+	return func(_ context.Context, keyId string) (string, bool, error) {
+		secret, ok := secrets[keyId]
+
+		return secret, ok && secret != "", nil
+	}
+}
+
+// ProvideHmacSecretLookupFromGetter looks up secrets in a kvstore containing HmacKeyRecords keyed by the key id.
+func ProvideHmacSecretLookupFromGetter(getter Getter) HmacSecretLookup {
+	return func(ctx context.Context, keyId string) (string, bool, error) {
+		record := &HmacKeyRecord{}
+		found, err := getter.Get(ctx, keyId, record)
+
+		if err != nil || !found {
+			return "", false, err
+		}
+
+		return record.Secret, record.Secret != "", nil
+	}
+}
+
+func (a *hmacAuthenticator) readBody(ginCtx *gin.Context) ([]byte, error) {
+	request := ginCtx.Request
+
+	if request.Body == nil {
+		return []byte{}, nil
+	}
+
+	if request.ContentLength > a.maxBodySize {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrHmacBodyTooLarge, a.maxBodySize)
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(ginCtx.Writer, request.Body, a.maxBodySize))
+
+	if err != nil && err.Error() == errBodyTooLarge {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrHmacBodyTooLarge, a.maxBodySize)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not read request body: %w", err)
+	}
+
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
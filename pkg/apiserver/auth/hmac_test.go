@@ -0,0 +1,92 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/applike/gosoline/pkg/apiserver/auth"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func getHmacMocks(keyId string, secret string, timestamp time.Time, body string) *gin.Context {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	request := httptest.NewRequest(http.MethodPost, "/orders?dry=1", bytes.NewBufferString(body))
+	request = request.WithContext(context.Background())
+	request.Header.Set(auth.HeaderHmacKeyId, keyId)
+	request.Header.Set(auth.HeaderHmacTime, ts)
+	request.Header.Set(auth.HeaderHmacSign, auth.SignRequest(secret, http.MethodPost, "/orders?dry=1", ts, []byte(body)))
+
+	return &gin.Context{
+		Request: request,
+	}
+}
+
+func TestHmac_Authenticate_Valid(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	ginCtx := getHmacMocks("client", "secret", now, `{"id":1}`)
+
+	lookup := auth.ProvideHmacSecretLookupFromMap(map[string]string{"client": "secret"})
+	a := auth.NewHmacAuthenticatorWithInterfaces(mocks.NewLoggerMockedAll(), func() time.Time { return now }, time.Minute, 1024, lookup)
+	valid, err := a.IsValid(ginCtx)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, "client", auth.GetSubject(ginCtx.Request.Context()).Name)
+
+	body, err := ioutil.ReadAll(ginCtx.Request.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(body), "the body has to be readable by the handler")
+}
+
+func TestHmac_Authenticate_InvalidSecret(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	ginCtx := getHmacMocks("client", "wrong", now, `{"id":1}`)
+
+	lookup := auth.ProvideHmacSecretLookupFromMap(map[string]string{"client": "secret"})
+	a := auth.NewHmacAuthenticatorWithInterfaces(mocks.NewLoggerMockedAll(), func() time.Time { return now }, time.Minute, 1024, lookup)
+	_, err := a.IsValid(ginCtx)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid signature provided", err.Error())
+	}
+}
+
+func TestHmac_Authenticate_Expired(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	ginCtx := getHmacMocks("client", "secret", now.Add(-time.Hour), `{"id":1}`)
+
+	lookup := auth.ProvideHmacSecretLookupFromMap(map[string]string{"client": "secret"})
+	a := auth.NewHmacAuthenticatorWithInterfaces(mocks.NewLoggerMockedAll(), func() time.Time { return now }, time.Minute, 1024, lookup)
+	_, err := a.IsValid(ginCtx)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "timestamp is outside of the allowed time window", err.Error())
+	}
+}
+
+func TestHmac_Authenticate_BodyTooLarge(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	lookup := auth.ProvideHmacSecretLookupFromMap(map[string]string{"client": "secret"})
+	a := auth.NewHmacAuthenticatorWithInterfaces(mocks.NewLoggerMockedAll(), func() time.Time { return now }, time.Minute, 8, lookup)
+
+	ginCtx := getHmacMocks("client", "secret", now, `{"id":12345}`)
+	_, err := a.IsValid(ginCtx)
+
+	assert.True(t, errors.Is(err, auth.ErrHmacBodyTooLarge), "a body announced as too large should be rejected")
+
+	// without a content length the body is only cut off while reading it
+	ginCtx = getHmacMocks("client", "secret", now, `{"id":12345}`)
+	ginCtx.Request.ContentLength = -1
+	_, err = a.IsValid(ginCtx)
+
+	assert.True(t, errors.Is(err, auth.ErrHmacBodyTooLarge), "a body exceeding the limit should be rejected")
+}
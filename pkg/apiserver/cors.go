@@ -8,20 +8,61 @@ import (
 	"time"
 )
 
+type CorsSettings struct {
+	Enabled              bool
+	AllowedOrigins       []string
+	AllowedOriginPattern string
+	AllowedHeaders       []string
+	AllowedMethods       []string
+	ExposedHeaders       []string
+	AllowCredentials     bool
+	MaxAge               time.Duration
+}
+
+func ReadCorsSettings(config cfg.Config) *CorsSettings {
+	return &CorsSettings{
+		Enabled:              config.GetBool("api_cors_enabled", false),
+		AllowedOrigins:       config.GetStringSlice("api_cors_allowed_origins", []string{}),
+		AllowedOriginPattern: config.GetString("api_cors_allowed_origin_pattern", ""),
+		AllowedHeaders:       config.GetStringSlice("api_cors_allowed_headers", []string{}),
+		AllowedMethods:       config.GetStringSlice("api_cors_allowed_methods", []string{}),
+		ExposedHeaders:       config.GetStringSlice("api_cors_exposed_headers", []string{}),
+		AllowCredentials:     config.GetBool("api_cors_allow_credentials", true),
+		MaxAge:               config.GetDuration("api_cors_max_age", 12*time.Hour),
+	}
+}
+
 func Cors(config cfg.Config) gin.HandlerFunc {
-	allowedOriginPattern := config.GetString("api_cors_allowed_origin_pattern")
-	validOrigin := regexp.MustCompile(allowedOriginPattern)
+	settings := ReadCorsSettings(config)
 
-	allowedHeaders := config.GetStringSlice("api_cors_allowed_headers")
-	allowedMethods := config.GetStringSlice("api_cors_allowed_methods")
+	return CorsWithSettings(settings)
+}
+
+func CorsWithSettings(settings *CorsSettings) gin.HandlerFunc {
+	var validOrigin *regexp.Regexp
+	allowedOrigins := make(map[string]bool, len(settings.AllowedOrigins))
+
+	// without any explicit origins the (possibly empty) pattern decides, matching every origin if unset
+	if settings.AllowedOriginPattern != "" || len(settings.AllowedOrigins) == 0 {
+		validOrigin = regexp.MustCompile(settings.AllowedOriginPattern)
+	}
+
+	for _, origin := range settings.AllowedOrigins {
+		allowedOrigins[origin] = true
+	}
 
 	return cors.New(cors.Config{
 		AllowOriginFunc: func(origin string) bool {
-			return validOrigin.MatchString(origin)
+			if allowedOrigins["*"] || allowedOrigins[origin] {
+				return true
+			}
+
+			return validOrigin != nil && validOrigin.MatchString(origin)
 		},
-		AllowHeaders:     allowedHeaders,
-		AllowMethods:     allowedMethods,
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
+		AllowHeaders:     settings.AllowedHeaders,
+		AllowMethods:     settings.AllowedMethods,
+		ExposeHeaders:    settings.ExposedHeaders,
+		AllowCredentials: settings.AllowCredentials,
+		MaxAge:           settings.MaxAge,
 	})
 }
@@ -0,0 +1,53 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCorsWithSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(apiserver.CorsWithSettings(&apiserver.CorsSettings{
+		AllowedOrigins:       []string{"https://app.example.com"},
+		AllowedOriginPattern: `^https://[a-z]+\.example\.org$`,
+		AllowedHeaders:       []string{"Authorization"},
+		AllowedMethods:       []string{http.MethodGet, http.MethodPost},
+		AllowCredentials:     true,
+		MaxAge:               time.Hour,
+	}))
+	router.GET("/", func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusOK)
+	})
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodOptions, "/", nil)
+		request.Header.Set("Origin", origin)
+		request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+
+		return response
+	}
+
+	response := preflight("https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, "https://app.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", response.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "3600", response.Header().Get("Access-Control-Max-Age"))
+
+	response = preflight("https://shop.example.org")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, "https://shop.example.org", response.Header().Get("Access-Control-Allow-Origin"))
+
+	response = preflight("https://evil.example.net")
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	assert.Empty(t, response.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -41,11 +41,11 @@ func (ch createHandler) Handle(ctx context.Context, request *apiserver.Request)
 	err = repo.Create(ctx, model)
 
 	if db.IsDuplicateEntryError(err) {
-		return apiserver.NewStatusResponse(http.StatusConflict), nil
+		return apiserver.NewErrorResponse(http.StatusConflict, apiserver.NewCodedError(http.StatusConflict, ErrorCodeDuplicateEntry, err)), nil
 	}
 
 	if errors.Is(err, &validation.Error{}) {
-		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+		return apiserver.NewErrorResponse(http.StatusBadRequest, apiserver.NewCodedError(http.StatusBadRequest, ErrorCodeValidationFailed, err)), nil
 	}
 
 	if err != nil {
@@ -6,13 +6,17 @@ import (
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/apiserver/crud"
 	"github.com/applike/gosoline/pkg/apiserver/crud/mocks"
+	"github.com/applike/gosoline/pkg/apiserver/sql"
 	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mdl"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/validation"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -370,3 +374,145 @@ func TestListHandler_Handle(t *testing.T) {
 
 	transformer.Repo.AssertExpectations(t)
 }
+
+func TestListHandler_Handle_Cursors(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := crud.NewListHandler(logger, transformer)
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Table("footable")
+	qb.Where("", []interface{}{}...)
+	qb.GroupBy("id")
+	qb.Page(2, 2)
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+	})
+	transformer.Repo.On("Count", mock.AnythingOfType("*context.emptyCtx"), qb, &Model{}).Return(5, nil)
+
+	body := fmt.Sprintf(`{"page":{"cursor":"%s"}}`, sql.EncodeCursor(sql.NewInput(), 2, 2))
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"total":5,"results":[{"Id":1,"UpdatedAt":"2006-01-02T15:04:05Z","CreatedAt":"2006-01-02T15:04:05Z","name":"foobar"}],"cursors":{"next":"%s","previous":"%s"}}`, sql.EncodeCursor(sql.NewInput(), 4, 2), sql.EncodeCursor(sql.NewInput(), 0, 2)), response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestListHandler_Handle_InvalidInput(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := crud.NewListHandler(logger, transformer)
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+	})
+
+	body := `{"order":[{"field":"name","direction":"ASC"}]}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"err":"no list mapping found for dimension name"}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+type VersionedModel struct {
+	db_repo.Model
+	db_repo.Versioned
+	Name *string `json:"name"`
+}
+
+type VersionedOutput struct {
+	Id      *uint   `json:"id"`
+	Version uint    `json:"version"`
+	Name    *string `json:"name"`
+}
+
+type VersionedHandler struct {
+	Handler
+}
+
+func (h VersionedHandler) GetModel() db_repo.ModelBased {
+	return &VersionedModel{}
+}
+
+func (h VersionedHandler) TransformUpdate(inp interface{}, model db_repo.ModelBased) (err error) {
+	model.(*VersionedModel).Name = inp.(*UpdateInput).Name
+
+	return nil
+}
+
+func (h VersionedHandler) TransformOutput(model db_repo.ModelBased, _ string) (interface{}, error) {
+	m := model.(*VersionedModel)
+
+	return &VersionedOutput{
+		Id:      m.Id,
+		Version: m.Version,
+		Name:    m.Name,
+	}, nil
+}
+
+func versionedUpdateRequest(handler gin.HandlerFunc, ifMatch string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.PUT("/:id", handler)
+
+	request := httptest.NewRequest(http.MethodPut, "/1", strings.NewReader(`{"name": "updated"}`))
+	request.Header.Set(crud.HeaderIfMatch, ifMatch)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestUpdateHandler_Handle_Version(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := VersionedHandler{Handler: NewTransformer()}
+
+	transformer.Repo.On("Read", mock.AnythingOfType("*context.emptyCtx"), mdl.Uint(1), &VersionedModel{}).Run(func(args mock.Arguments) {
+		model := args.Get(2).(*VersionedModel)
+		model.Id = mdl.Uint(1)
+		model.Version = 3
+		model.Name = mdl.String("updated")
+	}).Return(nil)
+	transformer.Repo.On("Update", mock.AnythingOfType("*context.emptyCtx"), mock.AnythingOfType("*crud_test.VersionedModel")).Return(nil).Once()
+
+	handler := crud.NewUpdateHandler(logger, transformer)
+	response := versionedUpdateRequest(handler, `"3"`)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `"3"`, response.Header().Get(crud.HeaderETag))
+	assert.JSONEq(t, `{"id":1,"version":3,"name":"updated"}`, response.Body.String())
+
+	transformer.Repo.AssertExpectations(t)
+}
+
+func TestUpdateHandler_Handle_VersionConflict(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := VersionedHandler{Handler: NewTransformer()}
+
+	transformer.Repo.On("Read", mock.AnythingOfType("*context.emptyCtx"), mdl.Uint(1), &VersionedModel{}).Run(func(args mock.Arguments) {
+		model := args.Get(2).(*VersionedModel)
+		model.Id = mdl.Uint(1)
+		model.Version = 4
+	}).Return(nil)
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		ModelId: mdl.ModelId{
+			Name: "versionedModel",
+		},
+	})
+
+	handler := crud.NewUpdateHandler(logger, transformer)
+
+	response := versionedUpdateRequest(handler, `W/"3"`)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	response = versionedUpdateRequest(handler, `"abc"`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	transformer.Repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -40,7 +40,7 @@ func (dh deleteHandler) Handle(ctx context.Context, request *apiserver.Request)
 	var notFound db_repo.RecordNotFoundError
 	if errors.As(err, &notFound) {
 		dh.logger.WithContext(ctx).Warnf("failed to delete model: %s", err)
-		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+		return apiserver.NewErrorResponse(http.StatusNotFound, apiserver.NewCodedError(http.StatusNotFound, ErrorCodeRecordNotFound, err)), nil
 	}
 
 	if err != nil {
@@ -50,7 +50,7 @@ func (dh deleteHandler) Handle(ctx context.Context, request *apiserver.Request)
 	err = repo.Delete(ctx, model)
 
 	if errors.Is(err, &validation.Error{}) {
-		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+		return apiserver.NewErrorResponse(http.StatusBadRequest, apiserver.NewCodedError(http.StatusBadRequest, ErrorCodeValidationFailed, err)), nil
 	}
 
 	if err != nil {
@@ -2,4 +2,13 @@ package crud
 
 import "fmt"
 
+const (
+	ErrorCodeDuplicateEntry   = "duplicate_entry"
+	ErrorCodeInvalidListInput = "invalid_list_input"
+	ErrorCodeInvalidVersion   = "invalid_version"
+	ErrorCodeRecordNotFound   = "record_not_found"
+	ErrorCodeValidationFailed = "validation_failed"
+	ErrorCodeVersionConflict  = "version_conflict"
+)
+
 var ErrModelNotChanged = fmt.Errorf("nothing has changed on model")
@@ -4,13 +4,22 @@ import (
 	"context"
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/apiserver/sql"
+	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
 type Output struct {
 	Total   int         `json:"total"`
 	Results interface{} `json:"results"`
+	Cursors *Cursors    `json:"cursors,omitempty"`
+}
+
+// Cursors point to the pages around the returned one and can be passed as page.cursor to the next list request
+type Cursors struct {
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"previous,omitempty"`
 }
 
 type listHandler struct {
@@ -41,7 +50,7 @@ func (lh listHandler) Handle(ctx context.Context, request *apiserver.Request) (*
 	qb, err := lqb.Build(inp)
 
 	if err != nil {
-		return nil, err
+		return apiserver.NewErrorResponse(http.StatusBadRequest, apiserver.NewCodedError(http.StatusBadRequest, ErrorCodeInvalidListInput, err)), nil
 	}
 
 	apiView := GetApiViewFromHeader(request.Header)
@@ -63,8 +72,57 @@ func (lh listHandler) Handle(ctx context.Context, request *apiserver.Request) (*
 		Results: results,
 	}
 
+	if inp.Page != nil && inp.Page.IsKeyset() {
+		// the page was already validated by the query builder
+		_, limit, _ := inp.Page.ResolveKeyset()
+		out.Cursors = getKeysetCursors(inp, qb, limit)
+	} else if inp.Page != nil {
+		offset, limit, _ := inp.Page.Resolve()
+		out.Cursors = getCursors(inp, offset, limit, total)
+	}
+
 	resp := apiserver.NewJsonResponse(out)
 	resp.AddHeader(apiserver.ApiViewKey, apiView)
 
 	return resp, nil
 }
+
+func getCursors(inp *sql.Input, offset int, limit int, total int) *Cursors {
+	if limit <= 0 {
+		return nil
+	}
+
+	cursors := &Cursors{}
+
+	if offset+limit < total {
+		cursors.Next = sql.EncodeCursor(inp, offset+limit, limit)
+	}
+
+	if offset > 0 {
+		previous := offset - limit
+
+		if previous < 0 {
+			previous = 0
+		}
+
+		cursors.Previous = sql.EncodeCursor(inp, previous, limit)
+	}
+
+	if cursors.Next == "" && cursors.Previous == "" {
+		return nil
+	}
+
+	return cursors
+}
+
+func getKeysetCursors(inp *sql.Input, qb *db_repo.QueryBuilder, limit int) *Cursors {
+	keys, ok := qb.NextKeyset()
+
+	if !ok {
+		return nil
+	}
+
+	return &Cursors{
+		Next: sql.EncodeKeysetCursor(inp, keys, limit),
+	}
+}
@@ -38,7 +38,7 @@ func (rh readHandler) Handle(ctx context.Context, request *apiserver.Request) (*
 	var notFound db_repo.RecordNotFoundError
 	if errors.As(err, &notFound) {
 		rh.logger.WithContext(ctx).Warnf("failed to read model: %s", err)
-		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+		return apiserver.NewErrorResponse(http.StatusNotFound, apiserver.NewCodedError(http.StatusNotFound, ErrorCodeRecordNotFound, err)), nil
 	}
 
 	if err != nil {
@@ -52,5 +52,8 @@ func (rh readHandler) Handle(ctx context.Context, request *apiserver.Request) (*
 		return nil, err
 	}
 
-	return apiserver.NewJsonResponse(out), nil
+	response := apiserver.NewJsonResponse(out)
+	addVersionHeader(response, model)
+
+	return response, nil
 }
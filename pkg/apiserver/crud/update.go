@@ -44,13 +44,29 @@ func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request)
 	var notFound db_repo.RecordNotFoundError
 	if errors.As(err, &notFound) {
 		uh.logger.WithContext(ctx).Warnf("failed to update model: %s", err)
-		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+		return apiserver.NewErrorResponse(http.StatusNotFound, apiserver.NewCodedError(http.StatusNotFound, ErrorCodeRecordNotFound, err)), nil
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	// the model was just read, so its version is the current one. it has to match the version the client read,
+	// otherwise the update would silently overwrite the changes of another client.
+	if versionable, ok := model.(db_repo.Versionable); ok {
+		version, expected, err := expectedVersion(request)
+
+		if err != nil {
+			return apiserver.NewErrorResponse(http.StatusBadRequest, apiserver.NewCodedError(http.StatusBadRequest, ErrorCodeInvalidVersion, err)), nil
+		}
+
+		if expected && version != versionable.GetVersion() {
+			modelId := repo.GetMetadata().ModelId
+			err = db_repo.NewVersionConflictError(*id, modelId.String(), version)
+			return apiserver.NewErrorResponse(http.StatusConflict, apiserver.NewCodedError(http.StatusConflict, ErrorCodeVersionConflict, err)), nil
+		}
+	}
+
 	err = uh.transformer.TransformUpdate(request.Body, model)
 
 	if modelNotChanged(err) {
@@ -64,11 +80,15 @@ func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request)
 	err = repo.Update(ctx, model)
 
 	if db.IsDuplicateEntryError(err) {
-		return apiserver.NewStatusResponse(http.StatusConflict), nil
+		return apiserver.NewErrorResponse(http.StatusConflict, apiserver.NewCodedError(http.StatusConflict, ErrorCodeDuplicateEntry, err)), nil
+	}
+
+	if db_repo.IsVersionConflictError(err) {
+		return apiserver.NewErrorResponse(http.StatusConflict, apiserver.NewCodedError(http.StatusConflict, ErrorCodeVersionConflict, err)), nil
 	}
 
 	if errors.Is(err, &validation.Error{}) {
-		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
+		return apiserver.NewErrorResponse(http.StatusBadRequest, apiserver.NewCodedError(http.StatusBadRequest, ErrorCodeValidationFailed, err)), nil
 	}
 
 	if err != nil {
@@ -89,7 +109,10 @@ func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request)
 		return nil, err
 	}
 
-	return apiserver.NewJsonResponse(out), nil
+	response := apiserver.NewJsonResponse(out)
+	addVersionHeader(response, reload)
+
+	return response, nil
 }
 
 func modelNotChanged(err error) bool {
@@ -0,0 +1,43 @@
+package crud
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// expectedVersion returns the version of the model the client based its update on. It is taken from the If-Match
+// header, e.g. If-Match: "3", or from the update input if the input is Versionable. If the client sent neither,
+// false is returned and the update isn't checked against the version the client read.
+func expectedVersion(request *apiserver.Request) (uint, bool, error) {
+	if ifMatch := request.Header.Get(HeaderIfMatch); ifMatch != "" {
+		value := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+		version, err := strconv.ParseUint(value, 10, 64)
+
+		if err != nil {
+			return 0, false, fmt.Errorf("the If-Match header %s is not a version of the model: %w", ifMatch, err)
+		}
+
+		return uint(version), true, nil
+	}
+
+	if versionable, ok := request.Body.(db_repo.Versionable); ok && versionable.GetVersion() > 0 {
+		return versionable.GetVersion(), true, nil
+	}
+
+	return 0, false, nil
+}
+
+// addVersionHeader sets the version of a versionable model as ETag, so clients can send it back with If-Match
+func addVersionHeader(response *apiserver.Response, model db_repo.ModelBased) {
+	if versionable, ok := model.(db_repo.Versionable); ok {
+		response.AddHeader(HeaderETag, strconv.Quote(strconv.FormatUint(uint64(versionable.GetVersion()), 10)))
+	}
+}
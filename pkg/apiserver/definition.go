@@ -7,8 +7,11 @@ import (
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
 	"strings"
+	"time"
 )
 
+const routeTemplateKey = "gosoline.apiserver.routeTemplate"
+
 type Definer func(ctx context.Context, config cfg.Config, logger mon.Logger) (*Definitions, error)
 
 type Definition struct {
@@ -16,6 +19,7 @@ type Definition struct {
 	httpMethod   string
 	relativePath string
 	handlers     []gin.HandlerFunc
+	doc          *RouteDoc
 }
 
 func (d *Definition) getAbsolutePath() string {
@@ -34,6 +38,7 @@ type Definitions struct {
 
 	children []*Definitions
 	parent   *Definitions
+	version  *apiVersion
 }
 
 func (d *Definitions) getAbsolutePath() string {
@@ -64,31 +69,47 @@ func (d *Definitions) Use(middleware ...gin.HandlerFunc) {
 	d.middleware = append(d.middleware, middleware...)
 }
 
-func (d *Definitions) Handle(httpMethod, relativePath string, handlers ...gin.HandlerFunc) {
+// Timeout sets a deadline for all requests of the group (see TimeoutMiddleware).
+func (d *Definitions) Timeout(timeout time.Duration) {
+	d.Use(TimeoutMiddleware(timeout))
+}
+
+// BodySizeLimit rejects requests of the group with a body larger than maxBytes (see BodySizeLimitMiddleware).
+func (d *Definitions) BodySizeLimit(maxBytes int64) {
+	d.Use(BodySizeLimitMiddleware(maxBytes))
+}
+
+// Handle adds a new route to the definitions. The returned RouteDoc can be used to document the route
+// for the generated OpenAPI specification.
+func (d *Definitions) Handle(httpMethod, relativePath string, handlers ...gin.HandlerFunc) *RouteDoc {
 	relativePath = strings.TrimRight(relativePath, "/")
+	doc := &RouteDoc{}
 
 	d.routes = append(d.routes, Definition{
 		group:        d,
 		httpMethod:   httpMethod,
 		relativePath: relativePath,
 		handlers:     handlers,
+		doc:          doc,
 	})
+
+	return doc
 }
 
-func (d *Definitions) POST(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("POST", relativePath, handlers...)
+func (d *Definitions) POST(relativePath string, handlers ...gin.HandlerFunc) *RouteDoc {
+	return d.Handle("POST", relativePath, handlers...)
 }
 
-func (d *Definitions) GET(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("GET", relativePath, handlers...)
+func (d *Definitions) GET(relativePath string, handlers ...gin.HandlerFunc) *RouteDoc {
+	return d.Handle("GET", relativePath, handlers...)
 }
 
-func (d *Definitions) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("DELETE", relativePath, handlers...)
+func (d *Definitions) DELETE(relativePath string, handlers ...gin.HandlerFunc) *RouteDoc {
+	return d.Handle("DELETE", relativePath, handlers...)
 }
 
-func (d *Definitions) PUT(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("PUT", relativePath, handlers...)
+func (d *Definitions) PUT(relativePath string, handlers ...gin.HandlerFunc) *RouteDoc {
+	return d.Handle("PUT", relativePath, handlers...)
 }
 
 func buildRouter(definitions *Definitions, router gin.IRouter) {
@@ -103,11 +124,7 @@ func buildRouter(definitions *Definitions, router gin.IRouter) {
 	}
 
 	for _, d := range definitions.routes {
-		metricHandler := CreateMetricHandler(d)
-		handlers := make([]gin.HandlerFunc, 0, len(d.handlers)+1)
-		handlers = append(handlers, metricHandler)
-		handlers = append(handlers, d.handlers...)
-
+		handlers := append([]gin.HandlerFunc{routeTemplateHandler(d.getAbsolutePath())}, d.handlers...)
 		grp.Handle(d.httpMethod, d.relativePath, handlers...)
 	}
 
@@ -116,6 +133,24 @@ func buildRouter(definitions *Definitions, router gin.IRouter) {
 	}
 }
 
+// routeTemplateHandler is added in front of the handlers of every route and stores the template of the route,
+// e.g. /users/:id, in the context. Middleware reading it after calling Next gets the template of the matched route.
+func routeTemplateHandler(template string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		ginCtx.Set(routeTemplateKey, template)
+	}
+}
+
+// getRouteTemplate returns the template of the route which handled the request. Requests not handled by a defined
+// route return the path with the param values replaced by their names.
+func getRouteTemplate(ginCtx *gin.Context) (string, bool) {
+	if template, ok := ginCtx.Get(routeTemplateKey); ok {
+		return template.(string), true
+	}
+
+	return getPathRaw(ginCtx), false
+}
+
 func removeDuplicates(s string) string {
 	var buf strings.Builder
 	var last rune
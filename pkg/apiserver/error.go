@@ -1,17 +1,158 @@
 package apiserver
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/applike/gosoline/pkg/mdl"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
 )
 
+const ContentTypeProblemJson = "application/problem+json; charset=utf-8"
+
 type ErrorHandler func(statusCode int, err error) *Response
 
+// CodedError can be implemented by errors returned from handlers to control the status code and the error
+// code of the error response.
+type CodedError interface {
+	error
+	StatusCode() int
+	ErrorCode() string
+}
+
+type codedError struct {
+	statusCode int
+	code       string
+	err        error
+}
+
+// NewCodedError wraps err so the error response is written with the given status code and error code.
+func NewCodedError(statusCode int, code string, err error) error {
+	return &codedError{
+		statusCode: statusCode,
+		code:       code,
+		err:        err,
+	}
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+func (e *codedError) StatusCode() int {
+	return e.statusCode
+}
+
+func (e *codedError) ErrorCode() string {
+	return e.code
+}
+
+// Problem is the body of an error response as defined by RFC 7807
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Fields   []FieldError `json:"fields,omitempty"`
+}
+
+// GetErrorStatusCode maps the known errors to their status code and returns the fallback for all other errors.
+func GetErrorStatusCode(err error, fallback int) int {
+	codedErr := CodedError(nil)
+	validationErr := &ValidationError{}
+
+	switch {
+	case errors.As(err, &codedErr):
+		return codedErr.StatusCode()
+	case errors.Is(err, ErrAccessForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case isBodyTooLarge(err):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	default:
+		return fallback
+	}
+}
+
+// GetErrorCode returns the code of a CodedError or a code derived from the status code (e.g. not_found) otherwise.
+func GetErrorCode(statusCode int, err error) string {
+	codedErr := CodedError(nil)
+
+	if errors.As(err, &codedErr) {
+		return codedErr.ErrorCode()
+	}
+
+	validationErr := &ValidationError{}
+
+	if errors.As(err, &validationErr) {
+		return "validation_failed"
+	}
+
+	text := http.StatusText(statusCode)
+
+	if text == "" {
+		return fmt.Sprintf("status_%d", statusCode)
+	}
+
+	text = strings.ToLower(text)
+	text = strings.Replace(text, "-", "_", -1)
+	text = strings.Replace(text, "'", "", -1)
+
+	return strings.Replace(text, " ", "_", -1)
+}
+
+// NewErrorResponse creates an error response with the configured error handler (see WithErrorHandler).
+func NewErrorResponse(statusCode int, err error) *Response {
+	return defaultErrorHandler(statusCode, err)
+}
+
 func errorHandlerJson(statusCode int, err error) *Response {
+	body := gin.H{"err": err.Error()}
+
+	validationErr := &ValidationError{}
+	if errors.As(err, &validationErr) {
+		body["fields"] = validationErr.Fields
+	}
+
 	return &Response{
 		StatusCode:  statusCode,
 		ContentType: mdl.String(ContentTypeJson),
-		Body:        gin.H{"err": err.Error()},
+		Body:        body,
+	}
+}
+
+// ErrorHandlerProblemJson writes errors as application/problem+json (RFC 7807). Enable it with
+// WithErrorHandler(ErrorHandlerProblemJson) or by setting api_error_format to problem.
+func ErrorHandlerProblemJson(statusCode int, err error) *Response {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: err.Error(),
+		Code:   GetErrorCode(statusCode, err),
+	}
+
+	validationErr := &ValidationError{}
+	if errors.As(err, &validationErr) {
+		problem.Fields = validationErr.Fields
+	}
+
+	return &Response{
+		StatusCode:  statusCode,
+		ContentType: mdl.String(ContentTypeProblemJson),
+		Body:        problem,
+		Header:      make(http.Header),
 	}
 }
 
@@ -24,3 +165,18 @@ func GetErrorHandler() ErrorHandler {
 }
 
 var defaultErrorHandler = errorHandlerJson
+
+// abortWithError writes an error response with the configured error handler and stops the middleware chain
+func abortWithError(ginCtx *gin.Context, statusCode int, err error) {
+	resp := defaultErrorHandler(statusCode, err)
+
+	writer, writerErr := mkResponseBodyWriter(resp)
+
+	if writerErr != nil {
+		panic(fmt.Errorf("error creating writer for error handler: %w", writerErr))
+	}
+
+	writeResponseHeaders(ginCtx, resp)
+	writer(ginCtx)
+	ginCtx.Abort()
+}
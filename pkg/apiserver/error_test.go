@@ -0,0 +1,53 @@
+package apiserver_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type FailingHandler struct {
+	err error
+}
+
+func (h FailingHandler) Handle(_ context.Context, _ *apiserver.Request) (*apiserver.Response, error) {
+	return nil, h.err
+}
+
+func TestGetErrorStatusCode(t *testing.T) {
+	for name, test := range map[string]struct {
+		err    error
+		status int
+	}{
+		"coded":     {apiserver.NewCodedError(http.StatusNotFound, "order_not_found", fmt.Errorf("no order")), http.StatusNotFound},
+		"forbidden": {fmt.Errorf("nope: %w", apiserver.ErrAccessForbidden), http.StatusForbidden},
+		"deadline":  {fmt.Errorf("slow: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		"unknown":   {fmt.Errorf("boom"), http.StatusInternalServerError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.status, apiserver.GetErrorStatusCode(test.err, http.StatusInternalServerError))
+		})
+	}
+}
+
+func TestGetErrorCode(t *testing.T) {
+	assert.Equal(t, "order_not_found", apiserver.GetErrorCode(http.StatusNotFound, apiserver.NewCodedError(http.StatusNotFound, "order_not_found", fmt.Errorf("no order"))))
+	assert.Equal(t, "not_found", apiserver.GetErrorCode(http.StatusNotFound, fmt.Errorf("no order")))
+	assert.Equal(t, "im_a_teapot", apiserver.GetErrorCode(http.StatusTeapot, fmt.Errorf("tea")))
+}
+
+func TestErrorHandlerProblemJson(t *testing.T) {
+	defer apiserver.WithErrorHandler(apiserver.GetErrorHandler())
+	apiserver.WithErrorHandler(apiserver.ErrorHandlerProblemJson)
+
+	err := apiserver.NewCodedError(http.StatusConflict, "order_locked", fmt.Errorf("order 1 is locked"))
+	handler := apiserver.CreateHandler(FailingHandler{err: err})
+	response := apiserver.HttpTest("GET", "/orders", "/orders", "", handler)
+
+	assert.Equal(t, http.StatusConflict, response.Code)
+	assert.Equal(t, apiserver.ContentTypeProblemJson, response.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"about:blank","title":"Conflict","status":409,"detail":"order 1 is locked","code":"order_locked"}`, response.Body.String())
+}
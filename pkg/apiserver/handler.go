@@ -151,8 +151,8 @@ func handleWithInput(handler HandlerWithInput, binding binding.Binding, errHandl
 		err := binding.Bind(ginCtx.Request, input)
 
 		if err != nil {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
-				Err:  err,
+			handleError(ginCtx, errHandler, GetErrorStatusCode(err, http.StatusBadRequest), gin.Error{
+				Err:  newValidationError(err, input, binding),
 				Type: gin.ErrorTypeBind,
 			})
 			return
@@ -174,8 +174,8 @@ func handleWithMultiPartFormInput(handler HandlerWithInput, errHandler ErrorHand
 		err := binding.FormMultipart.Bind(ginCtx.Request, input)
 
 		if err != nil && !errors.Is(err, http.ErrMissingFile) {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
-				Err:  err,
+			handleError(ginCtx, errHandler, GetErrorStatusCode(err, http.StatusBadRequest), gin.Error{
+				Err:  newValidationError(err, input, binding.FormMultipart),
 				Type: gin.ErrorTypeBind,
 			})
 			return
@@ -191,8 +191,8 @@ func handleWithStream(handler HandlerWithStream, binding binding.Binding, errHan
 		err := binding.Bind(ginCtx.Request, input)
 
 		if err != nil {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
-				Err:  err,
+			handleError(ginCtx, errHandler, GetErrorStatusCode(err, http.StatusBadRequest), gin.Error{
+				Err:  newValidationError(err, input, binding),
 				Type: gin.ErrorTypeBind,
 			})
 			return
@@ -210,7 +210,7 @@ func handleWithStream(handler HandlerWithStream, binding binding.Binding, errHan
 		err = handler.Handle(ginCtx, reqCtx, request)
 
 		if err != nil {
-			handleError(ginCtx, errHandler, http.StatusInternalServerError, gin.Error{
+			handleError(ginCtx, errHandler, GetErrorStatusCode(err, http.StatusInternalServerError), gin.Error{
 				Err:  err,
 				Type: gin.ErrorTypePrivate,
 			})
@@ -228,8 +228,8 @@ func handleWithMultipleBindings(handler HandlerWithMultipleBindings, errHandler
 			err := bindings[i].Bind(ginCtx.Request, input)
 
 			if err != nil {
-				handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
-					Err:  err,
+				handleError(ginCtx, errHandler, GetErrorStatusCode(err, http.StatusBadRequest), gin.Error{
+					Err:  newValidationError(err, input, bindings[i]),
 					Type: gin.ErrorTypeBind,
 				})
 				return
@@ -246,7 +246,7 @@ func handleRaw(handler HandlerWithoutInput, errHandler ErrorHandler) gin.Handler
 		body, err := ioutil.ReadAll(ginCtx.Request.Body)
 
 		if err != nil {
-			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
+			handleError(ginCtx, errHandler, GetErrorStatusCode(err, http.StatusBadRequest), gin.Error{
 				Err:  err,
 				Type: gin.ErrorTypeBind,
 			})
@@ -285,6 +285,14 @@ func handle(ginCtx *gin.Context, handler HandlerWithoutInput, input interface{},
 	}
 
 	if err != nil {
+		handleError(ginCtx, errHandler, GetErrorStatusCode(err, http.StatusInternalServerError), gin.Error{
+			Err:  err,
+			Type: gin.ErrorTypePrivate,
+		})
+		return
+	}
+
+	if err = transformVersionedResponse(reqCtx, resp); err != nil {
 		handleError(ginCtx, errHandler, http.StatusInternalServerError, gin.Error{
 			Err:  err,
 			Type: gin.ErrorTypePrivate,
@@ -352,6 +360,14 @@ func mkResponseBodyWriter(resp *Response) (func(ginCtx *gin.Context), error) {
 		}), nil
 	}
 
+	if *resp.ContentType == ContentTypeProblemJson {
+		return withRecover(func(ginCtx *gin.Context) {
+			// gin keeps an already set content type when rendering json
+			ginCtx.Header("Content-Type", ContentTypeProblemJson)
+			ginCtx.JSON(resp.StatusCode, resp.Body)
+		}), nil
+	}
+
 	if b, ok := resp.Body.([]byte); ok {
 		return withRecover(func(ginCtx *gin.Context) {
 			ginCtx.Data(resp.StatusCode, *resp.ContentType, b)
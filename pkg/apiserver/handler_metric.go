@@ -10,68 +10,139 @@ import (
 const (
 	MetricApiRequestCount        = "ApiRequestCount"
 	MetricApiRequestResponseTime = "ApiRequestResponseTime"
+	MetricApiStatus2XX           = "ApiStatus2XX"
+	MetricApiStatus3XX           = "ApiStatus3XX"
+	MetricApiStatus4XX           = "ApiStatus4XX"
+	MetricApiStatus5XX           = "ApiStatus5XX"
 )
 
+// MetricMiddleware writes the request count, the response time and the response class (2XX, 4XX, ...) of every
+// request handled by one of the given route definitions. The metrics are dimensioned by the route template
+// (e.g. /users/:id) instead of the actual path to keep the number of dimensions bounded. Requests not handled by
+// a defined route (404s, health checks, ...) are not recorded.
+func MetricMiddleware(definitions *Definitions) gin.HandlerFunc {
+	paths := getMetricPaths(definitions)
+	writer := mon.NewMetricDaemonWriter(getMetricMiddlewareDefaults(paths)...)
+
+	return NewMetricMiddlewareWithInterfaces(writer)
+}
+
+func NewMetricMiddlewareWithInterfaces(writer mon.MetricWriter) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		start := time.Now()
+
+		ginCtx.Next()
+
+		path, ok := getRouteTemplate(ginCtx)
+
+		if !ok {
+			return
+		}
+
+		writeRequestMetrics(writer, ginCtx, path, start)
+	}
+}
+
+// CreateMetricHandler writes the request metrics of a single route.
+//
+// Deprecated: the server records the metrics of all defined routes with MetricMiddleware, adding this handler to a
+// route as well records its requests twice.
 func CreateMetricHandler(definition Definition) gin.HandlerFunc {
-	defaults := getMetricMiddlewareDefaults(definition)
-	writer := mon.NewMetricDaemonWriter(defaults...)
+	path := definition.getAbsolutePath()
+	writer := mon.NewMetricDaemonWriter(getMetricMiddlewareDefaults([]string{path})...)
 
 	return func(ginCtx *gin.Context) {
 		start := time.Now()
 
 		ginCtx.Next()
 
-		requestTimeNano := time.Since(start)
-		requestTimeMillisecond := float64(requestTimeNano) / float64(time.Millisecond)
+		writeRequestMetrics(writer, ginCtx, path, start)
+	}
+}
+
+func writeRequestMetrics(writer mon.MetricWriter, ginCtx *gin.Context, path string, start time.Time) {
+	requestTimeNano := time.Since(start)
+	requestTimeMillisecond := float64(requestTimeNano) / float64(time.Millisecond)
 
-		writer.WriteOne(&mon.MetricDatum{
+	status := ginCtx.Writer.Status() / 100
+	statusMetric := fmt.Sprintf("ApiStatus%dXX", status)
+
+	writer.Write(mon.MetricData{
+		{
 			Priority:   mon.PriorityHigh,
 			MetricName: MetricApiRequestResponseTime,
 			Dimensions: mon.MetricDimensions{
-				"path": definition.getAbsolutePath(),
+				"path": path,
 			},
 			Unit:  mon.UnitMillisecondsAverage,
 			Value: requestTimeMillisecond,
-		})
-
-		writer.WriteOne(&mon.MetricDatum{
+		},
+		{
 			Priority:   mon.PriorityHigh,
 			MetricName: MetricApiRequestCount,
 			Dimensions: mon.MetricDimensions{
-				"path": definition.getAbsolutePath(),
+				"path": path,
 			},
 			Unit:  mon.UnitCount,
 			Value: 1.0,
-		})
-
-		status := ginCtx.Writer.Status() / 100
-		statusMetric := fmt.Sprintf("ApiStatus%dXX", status)
-
-		writer.WriteOne(&mon.MetricDatum{
+		},
+		{
 			Priority:   mon.PriorityHigh,
 			MetricName: statusMetric,
 			Dimensions: mon.MetricDimensions{
-				"path": definition.getAbsolutePath(),
+				"path": path,
 			},
 			Unit:  mon.UnitCount,
 			Value: 1.0,
-		})
+		},
+	})
+}
+
+// getMetricPaths returns the route templates of all definitions, they are the same the router attaches to the
+// requests handled by the routes
+func getMetricPaths(definitions *Definitions) []string {
+	paths := make([]string, 0, len(definitions.routes))
+	seen := make(map[string]bool)
+
+	var collect func(definitions *Definitions)
+	collect = func(definitions *Definitions) {
+		for _, d := range definitions.routes {
+			path := d.getAbsolutePath()
+
+			if seen[path] {
+				continue
+			}
+
+			seen[path] = true
+			paths = append(paths, path)
+		}
+
+		for _, c := range definitions.children {
+			collect(c)
+		}
 	}
+
+	collect(definitions)
+
+	return paths
 }
 
-func getMetricMiddlewareDefaults(definition Definition) mon.MetricData {
-	defaults := make(mon.MetricData, 0)
+func getMetricMiddlewareDefaults(paths []string) mon.MetricData {
+	defaults := make(mon.MetricData, 0, len(paths)*4)
 
-	metric := &mon.MetricDatum{
-		Priority:   mon.PriorityHigh,
-		MetricName: MetricApiRequestCount,
-		Dimensions: mon.MetricDimensions{
-			"path": definition.getAbsolutePath(),
-		},
-		Unit:  mon.UnitCount,
-		Value: 0.0,
+	for _, path := range paths {
+		for _, metricName := range []string{MetricApiRequestCount, MetricApiStatus2XX, MetricApiStatus4XX, MetricApiStatus5XX} {
+			defaults = append(defaults, &mon.MetricDatum{
+				Priority:   mon.PriorityHigh,
+				MetricName: metricName,
+				Dimensions: mon.MetricDimensions{
+					"path": path,
+				},
+				Unit:  mon.UnitCount,
+				Value: 0.0,
+			})
+		}
 	}
-	defaults = append(defaults, metric)
 
 	return defaults
 }
@@ -0,0 +1,58 @@
+package apiserver
+
+import (
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	written := make([]mon.MetricData, 0)
+
+	writer := new(monMocks.MetricWriter)
+	writer.On("Write", mock.AnythingOfType("mon.MetricData")).Run(func(args mock.Arguments) {
+		written = append(written, args.Get(0).(mon.MetricData))
+	})
+
+	definitions := &Definitions{}
+	definitions.Group("/v1").GET("/users/:id", func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusNotFound)
+	})
+
+	assert.Equal(t, []string{"/v1/users/:id"}, getMetricPaths(definitions))
+
+	router := gin.New()
+	router.Use(NewMetricMiddlewareWithInterfaces(writer))
+	buildRouter(definitions, router)
+
+	// the param value is part of the static path as well, the template has to be the one of the route anyway
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/users/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	if !assert.Len(t, written, 1) {
+		return
+	}
+
+	data := written[0]
+	assert.Len(t, data, 3)
+
+	assert.Equal(t, MetricApiRequestResponseTime, data[0].MetricName)
+	assert.Equal(t, mon.UnitMillisecondsAverage, data[0].Unit)
+
+	assert.Equal(t, MetricApiRequestCount, data[1].MetricName)
+	assert.Equal(t, 1.0, data[1].Value)
+
+	assert.Equal(t, MetricApiStatus4XX, data[2].MetricName)
+	assert.Equal(t, 1.0, data[2].Value)
+
+	for _, datum := range data {
+		assert.Equal(t, mon.MetricDimensions{"path": "/v1/users/:id"}, datum.Dimensions)
+	}
+}
@@ -0,0 +1,213 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ContentTypeEventStream = "text/event-stream"
+
+	defaultSseHeartbeatInterval = 15 * time.Second
+)
+
+type SseEvent struct {
+	// optional id of the event, the client sends it back in the Last-Event-ID header when reconnecting
+	Id string
+	// optional name of the event, clients listen for "message" if it is empty
+	Event string
+	// strings are written as they are, everything else is encoded as json
+	Data interface{}
+	// optional time the client should wait before reconnecting
+	Retry time.Duration
+}
+
+//go:generate mockery -name SseWriter
+type SseWriter interface {
+	// Write sends a single event to the client and flushes it immediately. It fails as soon
+	// as the client disconnected.
+	Write(event SseEvent) error
+}
+
+// An SseHandler writes events to the client until it returns or the client disconnects, in which case
+// the context passed to Handle is canceled. Comments are sent to the client periodically to keep idle
+// connections open.
+type SseHandler interface {
+	GetInput() interface{}
+	Handle(requestContext context.Context, request *Request, writer SseWriter) error
+}
+
+// SseHandlerWithHeartbeat can be implemented by an SseHandler to change the heartbeat interval.
+type SseHandlerWithHeartbeat interface {
+	GetHeartbeatInterval() time.Duration
+}
+
+type sseWriter struct {
+	lck     sync.Mutex
+	ctx     context.Context
+	writer  gin.ResponseWriter
+	flusher http.Flusher
+}
+
+func NewSseWriter(ctx context.Context, writer gin.ResponseWriter) *sseWriter {
+	writer.Header().Set("Content-Type", ContentTypeEventStream)
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+
+	return &sseWriter{
+		ctx:     ctx,
+		writer:  writer,
+		flusher: writer,
+	}
+}
+
+func (w *sseWriter) Write(event SseEvent) error {
+	var err error
+	var data string
+
+	switch d := event.Data.(type) {
+	case string:
+		data = d
+	default:
+		var encoded []byte
+
+		if encoded, err = json.Marshal(d); err != nil {
+			return fmt.Errorf("can not encode event data: %w", err)
+		}
+
+		data = string(encoded)
+	}
+
+	builder := &strings.Builder{}
+
+	if event.Id != "" {
+		builder.WriteString(fmt.Sprintf("id: %s\n", event.Id))
+	}
+
+	if event.Event != "" {
+		builder.WriteString(fmt.Sprintf("event: %s\n", event.Event))
+	}
+
+	if event.Retry > 0 {
+		builder.WriteString(fmt.Sprintf("retry: %d\n", event.Retry.Milliseconds()))
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		builder.WriteString(fmt.Sprintf("data: %s\n", line))
+	}
+
+	builder.WriteString("\n")
+
+	return w.write(builder.String())
+}
+
+func (w *sseWriter) heartbeat() error {
+	return w.write(": heartbeat\n\n")
+}
+
+func (w *sseWriter) write(payload string) error {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	if err := w.ctx.Err(); err != nil {
+		return fmt.Errorf("client disconnected: %w", err)
+	}
+
+	if _, err := w.writer.WriteString(payload); err != nil {
+		return fmt.Errorf("can not write event: %w", err)
+	}
+
+	w.flusher.Flush()
+
+	return nil
+}
+
+func CreateEventStreamHandler(handler SseHandler) gin.HandlerFunc {
+	return handleEventStream(handler, binding.Query, defaultErrorHandler)
+}
+
+func handleEventStream(handler SseHandler, binding binding.Binding, errHandler ErrorHandler) gin.HandlerFunc {
+	heartbeatInterval := defaultSseHeartbeatInterval
+
+	if h, ok := handler.(SseHandlerWithHeartbeat); ok {
+		heartbeatInterval = h.GetHeartbeatInterval()
+	}
+
+	return func(ginCtx *gin.Context) {
+		input := handler.GetInput()
+		err := binding.Bind(ginCtx.Request, input)
+
+		if err != nil {
+			handleError(ginCtx, errHandler, http.StatusBadRequest, gin.Error{
+				Err:  newValidationError(err, input, binding),
+				Type: gin.ErrorTypeBind,
+			})
+			return
+		}
+
+		reqCtx, cancel := context.WithCancel(ginCtx.Request.Context())
+		defer cancel()
+
+		request := &Request{
+			Header:   ginCtx.Request.Header,
+			Params:   ginCtx.Params,
+			Url:      ginCtx.Request.URL,
+			Body:     input,
+			ClientIp: ginCtx.ClientIP(),
+		}
+
+		writer := NewSseWriter(reqCtx, ginCtx.Writer)
+		heartbeatDone := make(chan struct{})
+
+		go func() {
+			defer close(heartbeatDone)
+			runSseHeartbeat(reqCtx, cancel, writer, heartbeatInterval)
+		}()
+
+		// gin reuses the response writer once we returned, so the heartbeat must not write anything afterwards
+		defer func() {
+			cancel()
+			<-heartbeatDone
+		}()
+
+		if err = handler.Handle(reqCtx, request, writer); err != nil && reqCtx.Err() == nil {
+			// the status is already written, so we can only tell the client by an error event
+			_ = ginCtx.Error(err)
+			_ = writer.Write(SseEvent{
+				Event: "error",
+				Data:  gin.H{"err": err.Error()},
+			})
+		}
+	}
+}
+
+func runSseHeartbeat(ctx context.Context, cancel context.CancelFunc, writer *sseWriter, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// a failing heartbeat means the client is gone, so we stop the handler, too
+			if err := writer.heartbeat(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
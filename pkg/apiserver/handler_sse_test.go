@@ -0,0 +1,70 @@
+package apiserver_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type SseInput struct {
+	Count int `form:"count"`
+}
+
+type SseHandler struct {
+}
+
+func (h SseHandler) GetInput() interface{} {
+	return &SseInput{}
+}
+
+func (h SseHandler) Handle(_ context.Context, request *apiserver.Request, writer apiserver.SseWriter) error {
+	input := request.Body.(*SseInput)
+
+	for i := 0; i < input.Count; i++ {
+		err := writer.Write(apiserver.SseEvent{
+			Id:    "1",
+			Event: "update",
+			Data:  map[string]int{"i": i},
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Write(apiserver.SseEvent{
+		Data: "done\nbye",
+	})
+}
+
+func TestCreateEventStreamHandler(t *testing.T) {
+	handler := apiserver.CreateEventStreamHandler(SseHandler{})
+	response := apiserver.HttpTest("GET", "/events", "/events?count=2", "", handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, apiserver.ContentTypeEventStream, response.Header().Get("Content-Type"))
+	assert.Equal(t, "id: 1\nevent: update\ndata: {\"i\":0}\n\nid: 1\nevent: update\ndata: {\"i\":1}\n\ndata: done\ndata: bye\n\n", response.Body.String())
+}
+
+type SseHeartbeatHandler struct {
+	SseHandler
+}
+
+func (h SseHeartbeatHandler) GetHeartbeatInterval() time.Duration {
+	return time.Millisecond
+}
+
+func TestCreateEventStreamHandler_HeartbeatStopped(t *testing.T) {
+	handler := apiserver.CreateEventStreamHandler(SseHeartbeatHandler{})
+	response := apiserver.HttpTest("GET", "/events", "/events?count=2", "", handler)
+	body := response.Body.String()
+
+	// run with -race to detect a heartbeat written after the handler returned
+	time.Sleep(time.Millisecond * 10)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, body, response.Body.String(), "nothing should be written after the handler returned")
+}
@@ -42,6 +42,21 @@ func (h JsonHandler) Handle(_ context.Context, request *apiserver.Request) (*api
 	return apiserver.NewJsonResponse(out), nil
 }
 
+type QueryInput struct {
+	Limit int `form:"limit" binding:"min=1"`
+}
+
+type QueryHandler struct {
+}
+
+func (h QueryHandler) GetInput() interface{} {
+	return &QueryInput{}
+}
+
+func (h QueryHandler) Handle(_ context.Context, _ *apiserver.Request) (*apiserver.Response, error) {
+	return apiserver.NewStatusResponse(http.StatusNoContent), nil
+}
+
 type RedirectHandler struct {
 }
 
@@ -70,7 +85,15 @@ func TestCreateIoHandler_InputFailure(t *testing.T) {
 	response := apiserver.HttpTest("PUT", "/action", "/action", `{}`, handler)
 
 	assert.Equal(t, http.StatusBadRequest, response.Code)
-	assert.Equal(t, `{"err":"Key: 'Input.Text' Error:Field validation for 'Text' failed on the 'required' tag"}`, response.Body.String())
+	assert.Equal(t, `{"err":"Key: 'Input.Text' Error:Field validation for 'Text' failed on the 'required' tag","fields":[{"field":"text","tag":"required","message":"validation for 'text' failed on the 'required' tag"}]}`, response.Body.String())
+}
+
+func TestCreateQueryHandler_InputFailure(t *testing.T) {
+	handler := apiserver.CreateQueryHandler(QueryHandler{})
+	response := apiserver.HttpTest("GET", "/action", "/action?limit=0", ``, handler)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), `"fields":[{"field":"limit","tag":"min","param":"1","message":"validation for 'limit' failed on the 'min' tag"}]`)
 }
 
 func TestCreateIoHandler(t *testing.T) {
@@ -8,11 +8,14 @@ import (
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
 type ApiHealthCheckSettings struct {
 	Port int    `cfg:"port" default:"8090"`
 	Path string `cfg:"path" default:"/health"`
+	// maximum duration of a single dependency check of the readiness endpoint
+	CheckTimeout time.Duration `cfg:"check_timeout" default:"2s"`
 }
 
 type ApiHealthCheck struct {
@@ -39,9 +42,7 @@ func NewApiHealthCheck() kernel.ModuleFactory {
 
 func NewApiHealthCheckWithInterfaces(logger mon.Logger, router *gin.Engine, settings *ApiHealthCheckSettings) *ApiHealthCheck {
 	router.Use(LoggingMiddleware(logger))
-	router.GET(settings.Path, func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{})
-	})
+	addHealthEndpoints(router, settings.Path, settings.CheckTimeout, nil)
 
 	addr := fmt.Sprintf(":%d", settings.Port)
 
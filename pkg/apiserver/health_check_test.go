@@ -20,4 +20,10 @@ func TestNewApiHealthCheck(t *testing.T) {
 
 	httpRecorder := httptest.NewRecorder()
 	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/health", http.StatusOK)
+
+	httpRecorder = httptest.NewRecorder()
+	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/health/live", http.StatusOK)
+
+	httpRecorder = httptest.NewRecorder()
+	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/health/ready", http.StatusOK)
 }
@@ -0,0 +1,206 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	HealthStatusOk       = "ok"
+	HealthStatusFailed   = "failed"
+	HealthStatusDraining = "draining"
+
+	healthCheckKey = "gosoline-health-check"
+)
+
+// HealthChecker checks a single dependency of the application (a database, a kvstore, a stream connection, ...).
+// It should return as soon as the context is canceled.
+type HealthChecker func(ctx context.Context) error
+
+type HealthCheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status string                        `json:"status"`
+	Checks map[string]*HealthCheckResult `json:"checks,omitempty"`
+}
+
+var healthCheckers = struct {
+	sync.Mutex
+	checkers map[string]HealthChecker
+}{
+	checkers: make(map[string]HealthChecker),
+}
+
+// AddHealthChecker registers a checker which has to succeed for the application to report being ready on
+// /health/ready. Registering a checker with an existing name replaces the previous one.
+func AddHealthChecker(name string, checker HealthChecker) {
+	healthCheckers.Lock()
+	defer healthCheckers.Unlock()
+
+	healthCheckers.checkers[name] = checker
+}
+
+// Pinger is implemented by *sql.DB and *sqlx.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingHealthChecker checks a database connection by pinging it.
+func PingHealthChecker(pinger Pinger) HealthChecker {
+	return func(ctx context.Context) error {
+		if err := pinger.PingContext(ctx); err != nil {
+			return fmt.Errorf("can not ping database: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// KvStoreHealthChecker checks a kvstore by performing a lookup of a key which does not need to exist.
+func KvStoreHealthChecker(store kvstore.KvStore) HealthChecker {
+	return func(ctx context.Context) error {
+		if _, err := store.Contains(ctx, healthCheckKey); err != nil {
+			return fmt.Errorf("can not access kvstore: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// ReadySignalHealthChecker fails until the ready channel is closed, e.g. until the kernel reports all essential
+// modules as ready.
+func ReadySignalHealthChecker(ready <-chan struct{}) HealthChecker {
+	return func(ctx context.Context) error {
+		select {
+		case <-ready:
+			return nil
+		default:
+			return fmt.Errorf("not ready yet")
+		}
+	}
+}
+
+func livenessHandler(ginCtx *gin.Context) {
+	ginCtx.JSON(http.StatusOK, HealthResponse{
+		Status: HealthStatusOk,
+	})
+}
+
+// readinessHandler runs all registered health checkers in parallel, each of them limited to checkTimeout.
+// It responds with 503 if the server is draining or any of the checks failed.
+func readinessHandler(checkTimeout time.Duration, drain *drainFlag) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if drain.isDraining() {
+			ginCtx.JSON(http.StatusServiceUnavailable, HealthResponse{
+				Status: HealthStatusDraining,
+			})
+			return
+		}
+
+		response := runHealthCheckers(ginCtx.Request.Context(), checkTimeout)
+		statusCode := http.StatusOK
+
+		if response.Status != HealthStatusOk {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		ginCtx.JSON(statusCode, response)
+	}
+}
+
+func runHealthCheckers(ctx context.Context, checkTimeout time.Duration) *HealthResponse {
+	healthCheckers.Lock()
+	checkers := make(map[string]HealthChecker, len(healthCheckers.checkers))
+	names := make([]string, 0, len(healthCheckers.checkers))
+
+	for name, checker := range healthCheckers.checkers {
+		checkers[name] = checker
+		names = append(names, name)
+	}
+	healthCheckers.Unlock()
+
+	sort.Strings(names)
+
+	response := &HealthResponse{
+		Status: HealthStatusOk,
+		Checks: make(map[string]*HealthCheckResult, len(names)),
+	}
+	results := make([]*HealthCheckResult, len(names))
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(names))
+
+	for i, name := range names {
+		go func(i int, checker HealthChecker) {
+			defer wg.Done()
+			results[i] = runHealthChecker(ctx, checkTimeout, checker)
+		}(i, checkers[name])
+	}
+
+	wg.Wait()
+
+	for i, name := range names {
+		response.Checks[name] = results[i]
+
+		if results[i].Status != HealthStatusOk {
+			response.Status = HealthStatusFailed
+		}
+	}
+
+	return response
+}
+
+func runHealthChecker(ctx context.Context, checkTimeout time.Duration, checker HealthChecker) *HealthCheckResult {
+	var cancel context.CancelFunc
+
+	if checkTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, checkTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	defer cancel()
+
+	start := time.Now()
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- checker(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not finish within %s: %w", checkTimeout, ctx.Err())
+	}
+
+	result := &HealthCheckResult{
+		Status:   HealthStatusOk,
+		Duration: time.Since(start).String(),
+	}
+
+	if err != nil {
+		result.Status = HealthStatusFailed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func addHealthEndpoints(router gin.IRouter, path string, checkTimeout time.Duration, drain *drainFlag) {
+	router.GET(path, healthHandler(drain))
+	router.GET(path+"/live", livenessHandler)
+	router.GET(path+"/ready", readinessHandler(checkTimeout, drain))
+}
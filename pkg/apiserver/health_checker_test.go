@@ -0,0 +1,84 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func resetHealthCheckers() {
+	healthCheckers.Lock()
+	defer healthCheckers.Unlock()
+
+	healthCheckers.checkers = make(map[string]HealthChecker)
+}
+
+func serveReadiness(timeout time.Duration) *httptest.ResponseRecorder {
+	return serveHealth(timeout, &drainFlag{}, "/health/ready")
+}
+
+func serveHealth(timeout time.Duration, drain *drainFlag, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	addHealthEndpoints(router, "/health", timeout, drain)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return response
+}
+
+func TestReadiness_Ok(t *testing.T) {
+	resetHealthCheckers()
+	defer resetHealthCheckers()
+
+	AddHealthChecker("kvstore", KvStoreHealthChecker(kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{})))
+
+	response := serveReadiness(time.Second)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"status":"ok"`)
+	assert.Contains(t, response.Body.String(), `"kvstore":{"status":"ok"`)
+}
+
+func TestReadiness_Failed(t *testing.T) {
+	resetHealthCheckers()
+	defer resetHealthCheckers()
+
+	AddHealthChecker("db", func(ctx context.Context) error {
+		return fmt.Errorf("connection refused")
+	})
+	AddHealthChecker("stream", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	response := serveReadiness(time.Millisecond)
+	body := response.Body.String()
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Contains(t, body, `"status":"failed"`)
+	assert.Contains(t, body, `"error":"connection refused"`)
+	assert.Contains(t, body, "context deadline exceeded")
+}
+
+func TestHealth_DrainingOnlyAffectsItsServer(t *testing.T) {
+	resetHealthCheckers()
+	defer resetHealthCheckers()
+
+	draining := &drainFlag{}
+	draining.start()
+	other := &drainFlag{}
+
+	for _, path := range []string{"/health", "/health/ready"} {
+		assert.Equal(t, http.StatusServiceUnavailable, serveHealth(time.Second, draining, path).Code, path)
+		assert.Equal(t, http.StatusOK, serveHealth(time.Second, other, path).Code, path)
+		assert.Equal(t, http.StatusOK, serveHealth(time.Second, nil, path).Code, path)
+	}
+}
@@ -0,0 +1,41 @@
+package apiserver
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// errBodyTooLarge is the (unexported) error message of the reader returned by http.MaxBytesReader
+const errBodyTooLarge = "http: request body too large"
+
+// BodySizeLimitMiddleware rejects requests with a body larger than maxBytes with 413. Requests announcing a larger
+// Content-Length are rejected right away, all other bodies are cut off after maxBytes and fail to bind. Use it for
+// a single route by adding it in front of the handler or for a whole group by calling Definitions.BodySizeLimit.
+func BodySizeLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if ginCtx.Request.ContentLength > maxBytes {
+			abortWithError(ginCtx, http.StatusRequestEntityTooLarge, fmt.Errorf("%s: the limit is %d bytes", errBodyTooLarge, maxBytes))
+			return
+		}
+
+		ginCtx.Request.Body = http.MaxBytesReader(ginCtx.Writer, ginCtx.Request.Body, maxBytes)
+	}
+}
+
+func isBodyTooLarge(err error) bool {
+	for err != nil {
+		if err.Error() == errBodyTooLarge {
+			return true
+		}
+
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return false
+		}
+
+		err = unwrapper.Unwrap()
+	}
+
+	return false
+}
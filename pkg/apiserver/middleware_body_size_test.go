@@ -0,0 +1,40 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodySizeLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.PUT("/action", apiserver.BodySizeLimitMiddleware(20), apiserver.CreateJsonHandler(JsonHandler{}))
+
+	request := func(body string, contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/action", strings.NewReader(body))
+		req.ContentLength = contentLength
+
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+
+		return response
+	}
+
+	response := request(`{"text":"foobar"}`, 17)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, `{"text":"foobar"}`, response.Body.String())
+
+	response = request(`{"text":"foobar foobar"}`, 24)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+	assert.JSONEq(t, `{"err":"http: request body too large: the limit is 20 bytes"}`, response.Body.String())
+
+	// chunked requests don't announce their size and are cut off while reading the body
+	response = request(`{"text":"foobar foobar"}`, -1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+}
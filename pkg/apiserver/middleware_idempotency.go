@@ -0,0 +1,254 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotent-Replayed"
+
+	MetricApiRequestIdempotentReplay = "ApiRequestIdempotentReplay"
+
+	IdempotencyBackendInMemory = kvstore.TypeInMemory
+	IdempotencyBackendRedis    = kvstore.TypeRedis
+
+	idempotencyStateProcessing = "processing"
+	idempotencyStateDone       = "done"
+)
+
+type IdempotencySettings struct {
+	// how long a stored response is replayed for retries of a request
+	Ttl time.Duration `cfg:"ttl" default:"24h"`
+	// how long a key stays locked by a request in progress. If the instance processing the request dies, retries
+	// are rejected with 409 until the lock expires, so it should only be a bit longer than the slowest request.
+	LockTtl time.Duration `cfg:"lock_ttl" default:"1m"`
+	// requests with a larger body are rejected with 413, as the body has to be read completely to build the key
+	MaxBodySize int64  `cfg:"max_body_size" default:"1048576"`
+	Backend     string `cfg:"backend" default:"inMemory" validate:"oneof=inMemory redis"`
+}
+
+// An IdempotencyStore keeps the responses for the idempotency keys. It has to be able to put a value
+// conditionally, so only one of several concurrent requests with the same key marks it as being processed.
+type IdempotencyStore interface {
+	kvstore.KvStore
+	kvstore.ConditionalPutter
+}
+
+type idempotencyRecord struct {
+	State       string `json:"state"`
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+
+	return w.ResponseWriter.WriteString(s)
+}
+
+// NewIdempotencyHandler creates a middleware configured at api.idempotency.<name> which handles the Idempotency-Key
+// header for unsafe methods. The first response for a key, route and request body is stored and replayed for every
+// retry of the same request. Retries arriving while the first request is still in progress are rejected with 409.
+// Responses with a 5XX status are not stored, so the client can retry them.
+func NewIdempotencyHandler(config cfg.Config, logger mon.Logger, name string) (gin.HandlerFunc, error) {
+	settings := &IdempotencySettings{}
+	config.UnmarshalKey(fmt.Sprintf("api.idempotency.%s", name), settings)
+
+	storeSettings := &kvstore.Settings{
+		Name: fmt.Sprintf("idempotency_%s", name),
+		Ttl:  settings.Ttl,
+	}
+
+	var err error
+	var store kvstore.KvStore
+
+	switch settings.Backend {
+	case IdempotencyBackendInMemory:
+		store, err = kvstore.NewInMemoryKvStore(config, logger, storeSettings)
+	case IdempotencyBackendRedis:
+		store, err = kvstore.NewRedisKvStore(config, logger, storeSettings)
+	default:
+		err = fmt.Errorf("unknown backend %s", settings.Backend)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create idempotency store: %w", err)
+	}
+
+	idempotencyStore, ok := store.(IdempotencyStore)
+
+	if !ok {
+		return nil, fmt.Errorf("the idempotency store %T can not put values conditionally", store)
+	}
+
+	return NewIdempotencyHandlerWithInterfaces(logger, idempotencyStore, settings), nil
+}
+
+func NewIdempotencyHandlerWithInterfaces(logger mon.Logger, store IdempotencyStore, settings *IdempotencySettings) gin.HandlerFunc {
+	logger = logger.WithChannel("idempotency")
+	writer := mon.NewMetricDaemonWriter()
+
+	return func(ginCtx *gin.Context) {
+		idempotencyKey := ginCtx.GetHeader(HeaderIdempotencyKey)
+
+		if idempotencyKey == "" || !isUnsafeMethod(ginCtx.Request.Method) {
+			return
+		}
+
+		ctx := ginCtx.Request.Context()
+		log := logger.WithContext(ctx)
+
+		if ginCtx.Request.ContentLength > settings.MaxBodySize {
+			abortWithError(ginCtx, http.StatusRequestEntityTooLarge, fmt.Errorf("%s: the limit is %d bytes", errBodyTooLarge, settings.MaxBodySize))
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(ginCtx.Writer, ginCtx.Request.Body, settings.MaxBodySize))
+
+		if err != nil && isBodyTooLarge(err) {
+			abortWithError(ginCtx, http.StatusRequestEntityTooLarge, fmt.Errorf("%s: the limit is %d bytes", errBodyTooLarge, settings.MaxBodySize))
+			return
+		}
+
+		if err != nil {
+			abortWithError(ginCtx, http.StatusBadRequest, fmt.Errorf("can not read request body: %w", err))
+			return
+		}
+
+		ginCtx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		bodyHash := sha256.Sum256(body)
+		key := fmt.Sprintf("%s-%s-%s-%s", idempotencyKey, ginCtx.Request.Method, getPathRaw(ginCtx), hex.EncodeToString(bodyHash[:]))
+
+		record, found, err := idempotencyAcquire(ctx, store, key, settings.LockTtl)
+		if err != nil {
+			// we rather process a request twice than failing because of our idempotency store
+			log.Error(err, "can not check idempotency key")
+			return
+		}
+
+		if found && record.State == idempotencyStateProcessing {
+			abortWithError(ginCtx, http.StatusConflict, fmt.Errorf("a request with the same idempotency key is still in progress"))
+			return
+		}
+
+		if found {
+			writer.WriteOne(&mon.MetricDatum{
+				Priority:   mon.PriorityHigh,
+				MetricName: MetricApiRequestIdempotentReplay,
+				Dimensions: mon.MetricDimensions{
+					"path": getPathRaw(ginCtx),
+				},
+				Unit:  mon.UnitCount,
+				Value: 1.0,
+			})
+
+			ginCtx.Header(HeaderIdempotencyReplayed, "true")
+			ginCtx.Data(record.StatusCode, record.ContentType, record.Body)
+			ginCtx.Abort()
+			return
+		}
+
+		responseWriter := &idempotencyResponseWriter{
+			ResponseWriter: ginCtx.Writer,
+			body:           &bytes.Buffer{},
+		}
+		ginCtx.Writer = responseWriter
+
+		completed := false
+		release := func() {
+			if err := store.Delete(ctx, key); err != nil {
+				log.Error(err, "can not release idempotency key")
+			}
+		}
+
+		// a panicking handler would leave the key in processing until it expires, so the client couldn't retry
+		defer func() {
+			if !completed {
+				release()
+			}
+		}()
+
+		ginCtx.Next()
+		completed = true
+
+		if ginCtx.Writer.Status() >= http.StatusInternalServerError {
+			release()
+			return
+		}
+
+		record = &idempotencyRecord{
+			State:       idempotencyStateDone,
+			StatusCode:  ginCtx.Writer.Status(),
+			ContentType: ginCtx.Writer.Header().Get("Content-Type"),
+			Body:        responseWriter.body.Bytes(),
+		}
+
+		if err := store.PutWithTtl(ctx, key, record, settings.Ttl); err != nil {
+			log.Error(err, "can not store response for idempotency key")
+		}
+	}
+}
+
+// idempotencyAcquire marks the key as being processed by the current request for the lock ttl if there is no record
+// for it yet. Otherwise it returns the stored record.
+func idempotencyAcquire(ctx context.Context, store IdempotencyStore, key string, lockTtl time.Duration) (*idempotencyRecord, bool, error) {
+	record := &idempotencyRecord{
+		State: idempotencyStateProcessing,
+	}
+
+	written, err := store.PutIfAbsent(ctx, key, record, lockTtl)
+	if err != nil {
+		return nil, false, fmt.Errorf("can not write idempotency record: %w", err)
+	}
+
+	if written {
+		return record, false, nil
+	}
+
+	found, err := store.Get(ctx, key, record)
+	if err != nil {
+		return nil, false, fmt.Errorf("can not read idempotency record: %w", err)
+	}
+
+	if !found {
+		// the request holding the key released it in between, so it is treated as still in progress
+		// and the client retries it
+		record.State = idempotencyStateProcessing
+	}
+
+	return record, true, nil
+}
+
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,186 @@
+package apiserver_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func idempotencySettings() *apiserver.IdempotencySettings {
+	return &apiserver.IdempotencySettings{
+		Ttl:         time.Hour,
+		LockTtl:     time.Minute,
+		MaxBodySize: 64,
+	}
+}
+
+type ttlRecordingStore struct {
+	apiserver.IdempotencyStore
+	lockTtl     time.Duration
+	responseTtl time.Duration
+}
+
+func (s *ttlRecordingStore) PutIfAbsent(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) (bool, error) {
+	s.lockTtl = ttl
+
+	return s.IdempotencyStore.PutIfAbsent(ctx, key, value, ttl)
+}
+
+func (s *ttlRecordingStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	s.responseTtl = ttl
+
+	return s.IdempotencyStore.PutWithTtl(ctx, key, value, ttl)
+}
+
+func TestIdempotencyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{}).(apiserver.IdempotencyStore)
+	handler := apiserver.NewIdempotencyHandlerWithInterfaces(mocks.NewLoggerMockedAll(), store, idempotencySettings())
+
+	var router *gin.Engine
+	var nested *httptest.ResponseRecorder
+
+	request := func(key string, body string) *httptest.ResponseRecorder {
+		httpRecorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set(apiserver.HeaderIdempotencyKey, key)
+		router.ServeHTTP(httpRecorder, req)
+
+		return httpRecorder
+	}
+
+	calls := 0
+	router = gin.New()
+	router.Use(handler)
+	router.POST("/orders", func(ginCtx *gin.Context) {
+		calls++
+
+		if calls == 1 {
+			// a retry arriving while the first request is still running
+			nested = request("a", `{"amount":1}`)
+		}
+
+		ginCtx.JSON(http.StatusCreated, gin.H{"order": calls})
+	})
+
+	first := request("a", `{"amount":1}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.JSONEq(t, `{"order":1}`, first.Body.String())
+	assert.Equal(t, http.StatusConflict, nested.Code)
+
+	replayed := request("a", `{"amount":1}`)
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.JSONEq(t, `{"order":1}`, replayed.Body.String())
+	assert.Equal(t, "true", replayed.Header().Get(apiserver.HeaderIdempotencyReplayed))
+	assert.Equal(t, 1, calls)
+
+	otherBody := request("a", `{"amount":2}`)
+	assert.JSONEq(t, `{"order":2}`, otherBody.Body.String())
+
+	otherKey := request("b", `{"amount":1}`)
+	assert.JSONEq(t, `{"order":3}`, otherKey.Body.String())
+	assert.Equal(t, 3, calls)
+}
+
+func TestIdempotencyHandler_Panic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{}).(apiserver.IdempotencyStore)
+	handler := apiserver.NewIdempotencyHandlerWithInterfaces(mocks.NewLoggerMockedAll(), store, idempotencySettings())
+
+	calls := 0
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(handler)
+	router.POST("/orders", func(ginCtx *gin.Context) {
+		calls++
+
+		if calls == 1 {
+			panic("boom")
+		}
+
+		ginCtx.JSON(http.StatusCreated, gin.H{"order": calls})
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		httpRecorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"amount":1}`))
+		req.Header.Set(apiserver.HeaderIdempotencyKey, "a")
+		router.ServeHTTP(httpRecorder, req)
+
+		return httpRecorder
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, request().Code)
+
+	retried := request()
+	assert.Equal(t, http.StatusCreated, retried.Code)
+	assert.JSONEq(t, `{"order":2}`, retried.Body.String())
+}
+
+func TestIdempotencyHandler_Ttl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &ttlRecordingStore{
+		IdempotencyStore: kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{}).(apiserver.IdempotencyStore),
+	}
+	handler := apiserver.NewIdempotencyHandlerWithInterfaces(mocks.NewLoggerMockedAll(), store, idempotencySettings())
+
+	router := gin.New()
+	router.Use(handler)
+	router.POST("/orders", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusCreated, gin.H{"order": 1})
+	})
+
+	httpRecorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"amount":1}`))
+	req.Header.Set(apiserver.HeaderIdempotencyKey, "a")
+	router.ServeHTTP(httpRecorder, req)
+
+	assert.Equal(t, http.StatusCreated, httpRecorder.Code)
+	assert.Equal(t, time.Minute, store.lockTtl, "the key should only be locked for the lock ttl while it is processed")
+	assert.Equal(t, time.Hour, store.responseTtl, "the response should be kept for the ttl")
+}
+
+func TestIdempotencyHandler_BodyTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{}).(apiserver.IdempotencyStore)
+	handler := apiserver.NewIdempotencyHandlerWithInterfaces(mocks.NewLoggerMockedAll(), store, idempotencySettings())
+
+	calls := 0
+	router := gin.New()
+	router.Use(handler)
+	router.POST("/orders", func(ginCtx *gin.Context) {
+		calls++
+	})
+
+	body := strings.Repeat("a", 65)
+
+	httpRecorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set(apiserver.HeaderIdempotencyKey, "a")
+	router.ServeHTTP(httpRecorder, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, httpRecorder.Code)
+
+	// without a content length the body is only cut off while reading it
+	httpRecorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/orders", ioutil.NopCloser(strings.NewReader(body)))
+	req.ContentLength = -1
+	req.Header.Set(apiserver.HeaderIdempotencyKey, "a")
+	router.ServeHTTP(httpRecorder, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, httpRecorder.Code)
+	assert.Equal(t, 0, calls)
+}
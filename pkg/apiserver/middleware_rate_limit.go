@@ -0,0 +1,152 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver/auth"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	MetricApiRequestThrottled = "ApiRequestThrottled"
+
+	RateLimitBackendInMemory = kvstore.TypeInMemory
+	RateLimitBackendRedis    = kvstore.TypeRedis
+)
+
+type RateLimitSettings struct {
+	// number of tokens added to the bucket of a client per second
+	Rate float64 `cfg:"rate" default:"10" validate:"gt=0"`
+	// maximum number of tokens in the bucket of a client and thus the maximum burst of requests
+	Burst   int    `cfg:"burst" default:"20" validate:"min=1"`
+	Backend string `cfg:"backend" default:"inMemory" validate:"oneof=inMemory redis"`
+	// name of the redis client used by the redis backend
+	Client string `cfg:"client" default:"default"`
+}
+
+// RateLimitKeyExtractor returns the key identifying the client of a request. Requests with the same
+// key share the same token bucket.
+type RateLimitKeyExtractor func(ginCtx *gin.Context) string
+
+// A RateLimitBackend takes a token from the bucket of a key. Refilling the bucket and taking the token has to
+// happen atomically per key, so concurrent requests of a client can't take the same token. It returns whether
+// a token was taken and the number of tokens left in the bucket.
+type RateLimitBackend interface {
+	Take(ctx context.Context, key string, now time.Time) (bool, float64, error)
+}
+
+// NewRateLimitHandler creates a token bucket rate limiting middleware configured at api.rate_limit.<name>.
+// Requests exceeding the limit are rejected with 429 and a Retry-After header. With the redis backend the
+// buckets are shared between all instances of your application.
+func NewRateLimitHandler(config cfg.Config, logger mon.Logger, name string, extractor RateLimitKeyExtractor) (gin.HandlerFunc, error) {
+	settings := &RateLimitSettings{}
+	config.UnmarshalKey(fmt.Sprintf("api.rate_limit.%s", name), settings)
+
+	var err error
+	var backend RateLimitBackend
+
+	switch settings.Backend {
+	case RateLimitBackendInMemory:
+		backend = NewRateLimitInMemoryBackend(settings)
+	case RateLimitBackendRedis:
+		backend, err = NewRateLimitRedisBackend(config, logger, name, settings)
+	default:
+		err = fmt.Errorf("unknown backend %s", settings.Backend)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create rate limit backend: %w", err)
+	}
+
+	return NewRateLimitHandlerWithInterfaces(logger, backend, time.Now, extractor, settings), nil
+}
+
+func NewRateLimitHandlerWithInterfaces(logger mon.Logger, backend RateLimitBackend, clock func() time.Time, extractor RateLimitKeyExtractor, settings *RateLimitSettings) gin.HandlerFunc {
+	logger = logger.WithChannel("rate_limit")
+	writer := mon.NewMetricDaemonWriter()
+
+	return func(ginCtx *gin.Context) {
+		key := extractor(ginCtx)
+		allowed, tokens, err := backend.Take(ginCtx.Request.Context(), key, clock())
+
+		if err != nil {
+			// we rather serve a request too much than failing because of our rate limit store
+			logger.WithContext(ginCtx.Request.Context()).Error(err, "can not check rate limit")
+			return
+		}
+
+		if allowed {
+			return
+		}
+
+		writer.WriteOne(&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: MetricApiRequestThrottled,
+			Dimensions: mon.MetricDimensions{
+				"path": getPathRaw(ginCtx),
+			},
+			Unit:  mon.UnitCount,
+			Value: 1.0,
+		})
+
+		retryAfter := (1 - tokens) / settings.Rate
+		seconds := int(math.Ceil(retryAfter))
+		ginCtx.Header("Retry-After", strconv.Itoa(seconds))
+		abortWithError(ginCtx, http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded"))
+	}
+}
+
+// time after which an untouched bucket is refilled completely and thus doesn't need to be stored anymore
+func rateLimitRefillDuration(settings *RateLimitSettings) time.Duration {
+	return time.Duration(float64(settings.Burst)/settings.Rate*float64(time.Second)) + time.Second
+}
+
+// RateLimitByClientIp uses the ip of the client as rate limit key.
+func RateLimitByClientIp() RateLimitKeyExtractor {
+	return func(ginCtx *gin.Context) string {
+		return fmt.Sprintf("ip:%s", ginCtx.ClientIP())
+	}
+}
+
+// RateLimitByApiKey uses the api key in the given header as rate limit key. It falls back to the client ip
+// for requests without an api key, so anonymous clients don't share a single bucket.
+func RateLimitByApiKey(header string) RateLimitKeyExtractor {
+	byIp := RateLimitByClientIp()
+
+	return func(ginCtx *gin.Context) string {
+		apiKey := ginCtx.GetHeader(header)
+
+		if apiKey == "" {
+			return byIp(ginCtx)
+		}
+
+		return fmt.Sprintf("key:%s", apiKey)
+	}
+}
+
+// RateLimitByAuthIdentifier uses the identifier set by the api key or hmac authenticator as rate
+// limit key. It falls back to the client ip if the request was not authenticated by any of them.
+func RateLimitByAuthIdentifier() RateLimitKeyExtractor {
+	byIp := RateLimitByClientIp()
+
+	return func(ginCtx *gin.Context) string {
+		subject, ok := auth.LookupSubject(ginCtx.Request.Context())
+
+		if !ok {
+			return byIp(ginCtx)
+		}
+
+		if identifier, ok := subject.Attributes[auth.AttributeIdentifier].(string); ok {
+			return fmt.Sprintf("id:%s", identifier)
+		}
+
+		return byIp(ginCtx)
+	}
+}
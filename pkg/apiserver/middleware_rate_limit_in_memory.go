@@ -0,0 +1,118 @@
+package apiserver
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type rateLimitBucket struct {
+	lck       sync.Mutex
+	tokens    float64
+	updatedAt time.Time
+}
+
+// refill adds the tokens for the time elapsed since the last update, the caller has to hold the lock of the bucket
+func (b *rateLimitBucket) refill(now time.Time, settings *RateLimitSettings) {
+	elapsed := math.Max(0, now.Sub(b.updatedAt).Seconds())
+	b.tokens = math.Min(float64(settings.Burst), b.tokens+elapsed*settings.Rate)
+	b.updatedAt = now
+}
+
+// rateLimitInMemoryBackend keeps a bucket per key in process. Every bucket has its own lock, so requests of
+// different clients don't wait for each other. Buckets which would be full again are removed from time to time.
+// A bucket is locked while the lock of the map is still held, so the cleanup can't remove a bucket which was
+// already looked up by a request.
+type rateLimitInMemoryBackend struct {
+	lck         sync.RWMutex
+	buckets     map[string]*rateLimitBucket
+	settings    *RateLimitSettings
+	cleanupAt   int64
+	cleanupTime time.Duration
+}
+
+func NewRateLimitInMemoryBackend(settings *RateLimitSettings) RateLimitBackend {
+	return &rateLimitInMemoryBackend{
+		buckets:     make(map[string]*rateLimitBucket),
+		settings:    settings,
+		cleanupTime: rateLimitRefillDuration(settings),
+	}
+}
+
+func (b *rateLimitInMemoryBackend) Take(_ context.Context, key string, now time.Time) (bool, float64, error) {
+	b.cleanup(now)
+
+	bucket := b.lockBucket(key, now)
+	defer bucket.lck.Unlock()
+
+	bucket.refill(now, b.settings)
+
+	if bucket.tokens < 1 {
+		return false, bucket.tokens, nil
+	}
+
+	bucket.tokens--
+
+	return true, bucket.tokens, nil
+}
+
+// lockBucket returns the locked bucket of the key and creates it if there is none yet
+func (b *rateLimitInMemoryBackend) lockBucket(key string, now time.Time) *rateLimitBucket {
+	b.lck.RLock()
+	bucket, ok := b.buckets[key]
+
+	if ok {
+		bucket.lck.Lock()
+		b.lck.RUnlock()
+
+		return bucket
+	}
+
+	b.lck.RUnlock()
+
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	if bucket, ok = b.buckets[key]; !ok {
+		bucket = &rateLimitBucket{
+			tokens:    float64(b.settings.Burst),
+			updatedAt: now,
+		}
+		b.buckets[key] = bucket
+	}
+
+	bucket.lck.Lock()
+
+	return bucket
+}
+
+// cleanup removes the full buckets once per refill duration. Removing a full bucket is the same as keeping it, as a
+// missing bucket is created full again. Only the caller winning the swap of the next cleanup time does the work.
+// Whether a bucket is full is checked while holding its lock, so a request which took a token in between keeps it.
+func (b *rateLimitInMemoryBackend) cleanup(now time.Time) {
+	cleanupAt := atomic.LoadInt64(&b.cleanupAt)
+
+	if now.UnixNano() < cleanupAt {
+		return
+	}
+
+	if !atomic.CompareAndSwapInt64(&b.cleanupAt, cleanupAt, now.Add(b.cleanupTime).UnixNano()) {
+		return
+	}
+
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	for key, bucket := range b.buckets {
+		bucket.lck.Lock()
+		bucket.refill(now, b.settings)
+		full := bucket.tokens >= float64(b.settings.Burst)
+		bucket.lck.Unlock()
+
+		if full {
+			delete(b.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/redis"
+	"strconv"
+	"time"
+)
+
+// refills the bucket at KEYS[1] and takes a token from it in one step, so concurrent requests of a client on
+// different instances can't take the same token. Lua numbers are truncated to integers when returned to redis,
+// so the remaining tokens are returned as a string.
+const rateLimitRedisTakeScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updatedAt")
+local tokens = tonumber(bucket[1])
+local updatedAt = tonumber(bucket[2])
+
+if tokens == nil or updatedAt == nil then
+	tokens = burst
+	updatedAt = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - updatedAt) / 1000 * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "updatedAt", tostring(now))
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+
+return {allowed, tostring(tokens)}
+`
+
+type rateLimitRedisBackend struct {
+	client   redis.Client
+	prefix   string
+	settings *RateLimitSettings
+}
+
+func NewRateLimitRedisBackend(config cfg.Config, logger mon.Logger, name string, settings *RateLimitSettings) (RateLimitBackend, error) {
+	client, err := redis.ProvideClient(config, logger, settings.Client)
+	if err != nil {
+		return nil, fmt.Errorf("can not create redis client %s: %w", settings.Client, err)
+	}
+
+	appId := cfg.GetAppIdFromConfig(config)
+	prefix := fmt.Sprintf("%s-%s-%s-rate_limit-%s", appId.Project, appId.Family, appId.Application, name)
+
+	return NewRateLimitRedisBackendWithInterfaces(client, prefix, settings), nil
+}
+
+func NewRateLimitRedisBackendWithInterfaces(client redis.Client, prefix string, settings *RateLimitSettings) RateLimitBackend {
+	return &rateLimitRedisBackend{
+		client:   client,
+		prefix:   prefix,
+		settings: settings,
+	}
+}
+
+func (b *rateLimitRedisBackend) Take(ctx context.Context, key string, now time.Time) (bool, float64, error) {
+	redisKey := fmt.Sprintf("%s-%s", b.prefix, key)
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	ttlMs := rateLimitRefillDuration(b.settings).Milliseconds()
+
+	result, err := b.client.Eval(ctx, rateLimitRedisTakeScript, []string{redisKey}, b.settings.Rate, b.settings.Burst, nowMs, ttlMs)
+	if err != nil {
+		return false, 0, fmt.Errorf("can not take a token from the bucket of %s: %w", key, err)
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected result %v of the rate limit script for %s", result, key)
+	}
+
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected allowed flag %v of the rate limit script for %s", values[0], key)
+	}
+
+	encodedTokens, ok := values[1].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected token count %v of the rate limit script for %s", values[1], key)
+	}
+
+	tokens, err := strconv.ParseFloat(encodedTokens, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("can not parse the token count of %s: %w", key, err)
+	}
+
+	return allowed == 1, tokens, nil
+}
@@ -0,0 +1,161 @@
+package apiserver_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	redisMocks "github.com/applike/gosoline/pkg/redis/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1600000000, 0)
+	clock := func() time.Time {
+		return now
+	}
+
+	settings := &apiserver.RateLimitSettings{
+		Rate:  1,
+		Burst: 2,
+	}
+
+	backend := apiserver.NewRateLimitInMemoryBackend(settings)
+	handler := apiserver.NewRateLimitHandlerWithInterfaces(mocks.NewLoggerMockedAll(), backend, clock, apiserver.RateLimitByClientIp(), settings)
+
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/", func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusOK)
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		httpRecorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		router.ServeHTTP(httpRecorder, req)
+
+		return httpRecorder
+	}
+
+	assert.Equal(t, http.StatusOK, request().Code)
+	assert.Equal(t, http.StatusOK, request().Code)
+
+	throttled := request()
+	assert.Equal(t, http.StatusTooManyRequests, throttled.Code)
+	assert.Equal(t, "1", throttled.Header().Get("Retry-After"))
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, request().Code)
+}
+
+func TestRateLimitInMemoryBackend_Concurrent(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	backend := apiserver.NewRateLimitInMemoryBackend(&apiserver.RateLimitSettings{
+		Rate:  1,
+		Burst: 10,
+	})
+
+	allowed := int32(0)
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ok, _, err := backend.Take(context.Background(), "client", now)
+			assert.NoError(t, err)
+
+			if ok {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(10), allowed)
+}
+
+func TestRateLimitInMemoryBackend_ConcurrentCleanup(t *testing.T) {
+	settings := &apiserver.RateLimitSettings{
+		Rate:  1,
+		Burst: 10,
+	}
+	backend := apiserver.NewRateLimitInMemoryBackend(settings)
+	now := time.Unix(1600000000, 0)
+
+	// every round starts after the buckets were refilled, so the first request of it removes all of them
+	for round := 0; round < 20; round++ {
+		now = now.Add(time.Minute)
+		allowed := int32(0)
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+
+			go func(now time.Time) {
+				defer wg.Done()
+
+				ok, _, err := backend.Take(context.Background(), "client", now)
+				assert.NoError(t, err)
+
+				if ok {
+					atomic.AddInt32(&allowed, 1)
+				}
+			}(now)
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(10), allowed, "round %d", round)
+	}
+}
+
+func TestRateLimitByApiKey(t *testing.T) {
+	extractor := apiserver.RateLimitByApiKey("X-API-KEY")
+
+	withKey, _ := gin.CreateTestContext(httptest.NewRecorder())
+	withKey.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	withKey.Request.Header.Set("X-API-KEY", "secret")
+	assert.Equal(t, "key:secret", extractor(withKey))
+
+	anonymous, _ := gin.CreateTestContext(httptest.NewRecorder())
+	anonymous.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	anonymous.Request.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "ip:10.0.0.1", extractor(anonymous))
+}
+
+func TestRateLimitRedisBackend_Take(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	client := new(redisMocks.Client)
+	backend := apiserver.NewRateLimitRedisBackendWithInterfaces(client, "prefix", &apiserver.RateLimitSettings{
+		Rate:  1,
+		Burst: 2,
+	})
+
+	client.On("Eval", context.Background(), mock.AnythingOfType("string"), []string{"prefix-client"}, 1.0, 2, int64(1600000000000), int64(3000)).Return([]interface{}{int64(1), "1"}, nil).Once()
+	client.On("Eval", context.Background(), mock.AnythingOfType("string"), []string{"prefix-client"}, 1.0, 2, int64(1600000000000), int64(3000)).Return([]interface{}{int64(0), "0.25"}, nil).Once()
+
+	allowed, tokens, err := backend.Take(context.Background(), "client", now)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 1.0, tokens)
+
+	allowed, tokens, err = backend.Take(context.Background(), "client", now)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 0.25, tokens)
+
+	client.AssertExpectations(t)
+}
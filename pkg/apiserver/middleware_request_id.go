@@ -0,0 +1,32 @@
+package apiserver
+
+import (
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/uuid"
+	"github.com/gin-gonic/gin"
+	"regexp"
+)
+
+// only accept request ids which can safely be logged and forwarded
+var validRequestId = regexp.MustCompile(`^[a-zA-Z0-9._:/+=-]{1,128}$`)
+
+// RequestIdMiddleware takes the request id from the X-Request-Id header or generates a new one if it is missing or
+// invalid. The id is stored on the request context (see mon.RequestIdFromContext), added to the logger context fields
+// and written to the response header. The gosoline http client and stream producers forward it automatically.
+func RequestIdMiddleware() gin.HandlerFunc {
+	return NewRequestIdMiddlewareWithInterfaces(uuid.New())
+}
+
+func NewRequestIdMiddlewareWithInterfaces(uuidSource uuid.Uuid) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		requestId := ginCtx.GetHeader(mon.HeaderRequestId)
+
+		if !validRequestId.MatchString(requestId) {
+			requestId = uuidSource.NewV4()
+		}
+
+		ctx := mon.WithRequestId(ginCtx.Request.Context(), requestId)
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+		ginCtx.Header(mon.HeaderRequestId, requestId)
+	}
+}
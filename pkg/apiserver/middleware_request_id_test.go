@@ -0,0 +1,48 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/uuid/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIdMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uuidSource := new(mocks.Uuid)
+	uuidSource.On("NewV4").Return("generated-id")
+
+	var contextId string
+
+	router := gin.New()
+	router.Use(apiserver.NewRequestIdMiddlewareWithInterfaces(uuidSource))
+	router.GET("/", func(ginCtx *gin.Context) {
+		contextId, _ = mon.RequestIdFromContext(ginCtx.Request.Context())
+	})
+
+	request := func(requestId string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(mon.HeaderRequestId, requestId)
+
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+
+		return response
+	}
+
+	response := request("abc-123")
+	assert.Equal(t, "abc-123", response.Header().Get(mon.HeaderRequestId))
+	assert.Equal(t, "abc-123", contextId)
+
+	response = request("")
+	assert.Equal(t, "generated-id", response.Header().Get(mon.HeaderRequestId))
+	assert.Equal(t, "generated-id", contextId)
+
+	response = request("<script>")
+	assert.Equal(t, "generated-id", response.Header().Get(mon.HeaderRequestId))
+}
@@ -0,0 +1,16 @@
+package apiserver
+
+import (
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionMiddleware starts a read your own writes session for every request (see reqctx.WithSession). Once a
+// repository wrote something during the request, the following reads of the request go to the primary instead of
+// a replica which might not know the write yet.
+func SessionMiddleware() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		ctx := reqctx.WithSession(ginCtx.Request.Context())
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+	}
+}
@@ -0,0 +1,29 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSessionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var written bool
+
+	router := gin.New()
+	router.Use(apiserver.SessionMiddleware())
+	router.GET("/", func(ginCtx *gin.Context) {
+		ctx := ginCtx.Request.Context()
+		reqctx.MarkWritten(ctx)
+		written = reqctx.HasWritten(ctx)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, written, "the write should be recorded in the session of the request")
+}
@@ -0,0 +1,51 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+const MetricApiRequestTimeout = "ApiRequestTimeout"
+
+// TimeoutMiddleware sets a deadline on the context of the request. Handlers have to honor the
+// deadline of their context; if the deadline is exceeded and the handler did not write a response
+// yet, a 504 is returned. Use it for a single route by adding it in front of the handler or for a
+// whole group by calling Definitions.Timeout.
+func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	writer := mon.NewMetricDaemonWriter()
+
+	return func(ginCtx *gin.Context) {
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), timeout)
+		defer cancel()
+
+		ginCtx.Request = ginCtx.Request.WithContext(ctx)
+		ginCtx.Next()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+
+		path, _ := getRouteTemplate(ginCtx)
+
+		writer.WriteOne(&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: MetricApiRequestTimeout,
+			Dimensions: mon.MetricDimensions{
+				"path": path,
+			},
+			Unit:  mon.UnitCount,
+			Value: 1.0,
+		})
+
+		if ginCtx.Writer.Written() {
+			return
+		}
+
+		abortWithError(ginCtx, http.StatusGatewayTimeout, fmt.Errorf("request timed out after %s", timeout))
+	}
+}
@@ -0,0 +1,43 @@
+package apiserver_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type SlowHandler struct {
+}
+
+func (h SlowHandler) Handle(ctx context.Context, _ *apiserver.Request) (*apiserver.Response, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/slow", apiserver.TimeoutMiddleware(time.Millisecond), apiserver.CreateHandler(SlowHandler{}))
+	router.GET("/ignoring", apiserver.TimeoutMiddleware(time.Millisecond), func(ginCtx *gin.Context) {
+		time.Sleep(5 * time.Millisecond)
+	})
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, response.Code)
+	assert.JSONEq(t, `{"err":"context deadline exceeded"}`, response.Body.String())
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/ignoring", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, response.Code)
+	assert.JSONEq(t, `{"err":"request timed out after 1ms"}`, response.Body.String())
+}
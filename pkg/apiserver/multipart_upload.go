@@ -0,0 +1,114 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/blob"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
+)
+
+// maxMultipartFieldSize limits the size of the non-file fields of a form as those are kept in memory
+const maxMultipartFieldSize = 1 << 20
+
+type MultipartUpload struct {
+	FieldName   string
+	FileName    string
+	ContentType string
+	Key         string
+	Size        int64
+}
+
+type MultipartUploadResult struct {
+	Fields  map[string]string
+	Uploads []*MultipartUpload
+}
+
+// MultipartKeyFactory returns the blob key a file part is uploaded to
+type MultipartKeyFactory func(part *multipart.Part) string
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+
+	return n, err
+}
+
+// StreamMultipartUploads reads a multipart/form-data request part by part and streams every file part to the
+// uploader without buffering the whole file in memory. The body of the request has to be the unread request body,
+// so use it together with CreateReaderHandler. If no key factory is given, the keys are created by blob.CreateKey.
+// Combine it with BodySizeLimitMiddleware to restrict the size of the uploads.
+func StreamMultipartUploads(ctx context.Context, request *Request, uploader blob.Uploader, keyFactory MultipartKeyFactory) (*MultipartUploadResult, error) {
+	body, ok := request.Body.(io.Reader)
+	if !ok {
+		return nil, fmt.Errorf("the request body has to be an io.Reader, got %T", request.Body)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("can not parse content type of request: %w", err)
+	}
+
+	if !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, fmt.Errorf("the request has to be a multipart request, got %s", mediaType)
+	}
+
+	if keyFactory == nil {
+		keyFactory = func(_ *multipart.Part) string {
+			return blob.CreateKey()
+		}
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	result := &MultipartUploadResult{
+		Fields:  make(map[string]string),
+		Uploads: make([]*MultipartUpload, 0),
+	}
+
+	for {
+		part, err := reader.NextPart()
+
+		if err == io.EOF {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("can not read next part of multipart request: %w", err)
+		}
+
+		if part.FileName() == "" {
+			value, err := ioutil.ReadAll(io.LimitReader(part, maxMultipartFieldSize))
+			if err != nil {
+				return nil, fmt.Errorf("can not read field %s of multipart request: %w", part.FormName(), err)
+			}
+
+			result.Fields[part.FormName()] = string(value)
+
+			continue
+		}
+
+		upload := &MultipartUpload{
+			FieldName:   part.FormName(),
+			FileName:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Key:         keyFactory(part),
+		}
+
+		counter := &countingReader{reader: part}
+
+		if err := uploader.Upload(ctx, upload.Key, upload.ContentType, counter); err != nil {
+			return nil, fmt.Errorf("can not upload file %s of field %s: %w", upload.FileName, upload.FieldName, err)
+		}
+
+		upload.Size = counter.count
+		result.Uploads = append(result.Uploads, upload)
+	}
+}
@@ -0,0 +1,64 @@
+package apiserver_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/blob/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"testing"
+)
+
+func TestStreamMultipartUploads(t *testing.T) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+
+	err := form.WriteField("title", "holiday")
+	assert.NoError(t, err)
+
+	file, err := form.CreateFormFile("image", "beach.jpg")
+	assert.NoError(t, err)
+
+	_, err = file.Write([]byte("jpeg data"))
+	assert.NoError(t, err)
+
+	err = form.Close()
+	assert.NoError(t, err)
+
+	uploaded := ""
+	uploader := new(mocks.Uploader)
+	uploader.On("Upload", mock.Anything, "images/beach.jpg", "application/octet-stream", mock.Anything).Run(func(args mock.Arguments) {
+		data, err := ioutil.ReadAll(args.Get(3).(io.Reader))
+		assert.NoError(t, err)
+
+		uploaded = string(data)
+	}).Return(nil)
+
+	request := &apiserver.Request{
+		Header: http.Header{"Content-Type": []string{form.FormDataContentType()}},
+		Body:   ioutil.NopCloser(body),
+	}
+
+	result, err := apiserver.StreamMultipartUploads(context.Background(), request, uploader, func(part *multipart.Part) string {
+		return "images/" + part.FileName()
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg data", uploaded)
+	assert.Equal(t, map[string]string{"title": "holiday"}, result.Fields)
+	assert.Equal(t, []*apiserver.MultipartUpload{
+		{
+			FieldName:   "image",
+			FileName:    "beach.jpg",
+			ContentType: "application/octet-stream",
+			Key:         "images/beach.jpg",
+			Size:        9,
+		},
+	}, result.Uploads)
+	uploader.AssertExpectations(t)
+}
@@ -0,0 +1,359 @@
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const openApiVersion = "3.0.3"
+
+var pathParamRegexp = regexp.MustCompile(`[:*]([^/]+)`)
+
+type OpenApiInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type OpenApiSchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *OpenApiSchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenApiSchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenApiSchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+}
+
+type OpenApiParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *OpenApiSchema `json:"schema"`
+}
+
+type OpenApiMediaType struct {
+	Schema *OpenApiSchema `json:"schema"`
+}
+
+type OpenApiRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenApiMediaType `json:"content"`
+}
+
+type OpenApiResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenApiMediaType `json:"content,omitempty"`
+}
+
+type OpenApiOperation struct {
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenApiParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenApiRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenApiResponse `json:"responses"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+}
+
+type OpenApiComponents struct {
+	Schemas map[string]*OpenApiSchema `json:"schemas,omitempty"`
+}
+
+type OpenApi struct {
+	OpenApi    string                                  `json:"openapi"`
+	Info       OpenApiInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenApiOperation `json:"paths"`
+	Components OpenApiComponents                       `json:"components"`
+}
+
+// BuildOpenApi generates an OpenAPI 3 specification of all routes in the given definitions. Routes can
+// be documented further by the RouteDoc returned when defining them, undocumented routes are still
+// listed with their path parameters.
+func BuildOpenApi(info OpenApiInfo, definitions *Definitions) *OpenApi {
+	spec := &OpenApi{
+		OpenApi: openApiVersion,
+		Info:    info,
+		Paths:   make(map[string]map[string]*OpenApiOperation),
+		Components: OpenApiComponents{
+			Schemas: make(map[string]*OpenApiSchema),
+		},
+	}
+
+	builder := &openApiSchemaBuilder{
+		schemas: spec.Components.Schemas,
+	}
+
+	addOpenApiPaths(spec, builder, definitions)
+
+	return spec
+}
+
+func addOpenApiPaths(spec *OpenApi, builder *openApiSchemaBuilder, definitions *Definitions) {
+	for _, d := range definitions.routes {
+		path, params := convertOpenApiPath(d.getAbsolutePath())
+
+		if path == "" {
+			path = "/"
+		}
+
+		if _, ok := spec.Paths[path]; !ok {
+			spec.Paths[path] = make(map[string]*OpenApiOperation)
+		}
+
+		spec.Paths[path][strings.ToLower(d.httpMethod)] = buildOpenApiOperation(builder, d, params)
+	}
+
+	for _, c := range definitions.children {
+		addOpenApiPaths(spec, builder, c)
+	}
+}
+
+func buildOpenApiOperation(builder *openApiSchemaBuilder, d Definition, pathParams []string) *OpenApiOperation {
+	doc := d.doc
+	if doc == nil {
+		doc = &RouteDoc{}
+	}
+
+	operation := &OpenApiOperation{
+		Summary:     doc.summary,
+		Description: doc.description,
+		Tags:        doc.tags,
+		Parameters:  make([]OpenApiParameter, 0, len(pathParams)),
+		Responses:   make(map[string]OpenApiResponse),
+		Deprecated:  doc.deprecated,
+	}
+
+	for _, param := range pathParams {
+		operation.Parameters = append(operation.Parameters, OpenApiParameter{
+			Name:     param,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenApiSchema{Type: "string"},
+		})
+	}
+
+	if doc.input != nil {
+		switch d.httpMethod {
+		case http.MethodGet, http.MethodDelete:
+			operation.Parameters = append(operation.Parameters, builder.queryParameters(reflect.TypeOf(doc.input))...)
+		default:
+			operation.RequestBody = &OpenApiRequestBody{
+				Required: true,
+				Content: map[string]OpenApiMediaType{
+					"application/json": {Schema: builder.schema(reflect.TypeOf(doc.input))},
+				},
+			}
+		}
+	}
+
+	success := OpenApiResponse{
+		Description: http.StatusText(http.StatusOK),
+	}
+
+	if doc.output != nil {
+		success.Content = map[string]OpenApiMediaType{
+			"application/json": {Schema: builder.schema(reflect.TypeOf(doc.output))},
+		}
+	}
+
+	operation.Responses[strconv.Itoa(http.StatusOK)] = success
+
+	for statusCode, description := range doc.responses {
+		operation.Responses[strconv.Itoa(statusCode)] = OpenApiResponse{
+			Description: description,
+		}
+	}
+
+	return operation
+}
+
+// convertOpenApiPath converts gin path parameters (:id, *path) to the OpenAPI syntax ({id}, {path})
+func convertOpenApiPath(path string) (string, []string) {
+	params := make([]string, 0)
+
+	converted := pathParamRegexp.ReplaceAllStringFunc(path, func(match string) string {
+		params = append(params, match[1:])
+
+		return fmt.Sprintf("{%s}", match[1:])
+	})
+
+	return converted, params
+}
+
+type openApiSchemaBuilder struct {
+	schemas map[string]*OpenApiSchema
+}
+
+func (b *openApiSchemaBuilder) schema(typ reflect.Type) *OpenApiSchema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == reflect.TypeOf(time.Time{}) {
+		return &OpenApiSchema{Type: "string", Format: "date-time"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &OpenApiSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenApiSchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenApiSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenApiSchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenApiSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenApiSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &OpenApiSchema{Type: "string", Format: "byte"}
+		}
+
+		return &OpenApiSchema{Type: "array", Items: b.schema(typ.Elem())}
+	case reflect.Map:
+		return &OpenApiSchema{Type: "object", AdditionalProperties: b.schema(typ.Elem())}
+	case reflect.Struct:
+		return b.structSchema(typ)
+	default:
+		return &OpenApiSchema{}
+	}
+}
+
+func (b *openApiSchemaBuilder) structSchema(typ reflect.Type) *OpenApiSchema {
+	if typ.Name() == "" {
+		return b.buildStructSchema(typ)
+	}
+
+	name := typ.Name()
+	ref := &OpenApiSchema{Ref: fmt.Sprintf("#/components/schemas/%s", name)}
+
+	if _, ok := b.schemas[name]; ok {
+		return ref
+	}
+
+	// register the name before building the schema to support recursive types
+	b.schemas[name] = &OpenApiSchema{}
+	*b.schemas[name] = *b.buildStructSchema(typ)
+
+	return ref
+}
+
+func (b *openApiSchemaBuilder) buildStructSchema(typ reflect.Type) *OpenApiSchema {
+	schema := &OpenApiSchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenApiSchema),
+	}
+
+	b.addStructFields(schema, typ)
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+func (b *openApiSchemaBuilder) addStructFields(schema *OpenApiSchema, typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				b.addStructFields(schema, embedded)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := openApiFieldName(field, "json")
+		if !ok {
+			continue
+		}
+
+		fieldSchema := b.schema(field.Type)
+		if field.Type.Kind() == reflect.Ptr && fieldSchema.Ref == "" {
+			fieldSchema.Nullable = true
+		}
+
+		schema.Properties[name] = fieldSchema
+
+		if isOpenApiRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func (b *openApiSchemaBuilder) queryParameters(typ reflect.Type) []OpenApiParameter {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	params := make([]OpenApiParameter, 0)
+
+	if typ.Kind() != reflect.Struct {
+		return params
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := openApiFieldName(field, "form")
+		if !ok {
+			continue
+		}
+
+		params = append(params, OpenApiParameter{
+			Name:     name,
+			In:       "query",
+			Required: isOpenApiRequired(field),
+			Schema:   b.schema(field.Type),
+		})
+	}
+
+	return params
+}
+
+func openApiFieldName(field reflect.StructField, tagName string) (string, bool) {
+	tag := field.Tag.Get(tagName)
+
+	if tag == "-" {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+func isOpenApiRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package apiserver
+
+// RouteDoc describes a route for the generated OpenAPI specification. All methods
+// return the RouteDoc itself, so you can chain them when defining a route:
+//
+// d.POST("/orders", CreateJsonHandler(handler)).Summary("create an order").Input(&Order{}).Output(&Order{})
+type RouteDoc struct {
+	summary     string
+	description string
+	tags        []string
+	input       interface{}
+	output      interface{}
+	responses   map[int]string
+	deprecated  bool
+}
+
+func (d *RouteDoc) Summary(summary string) *RouteDoc {
+	d.summary = summary
+
+	return d
+}
+
+func (d *RouteDoc) Description(description string) *RouteDoc {
+	d.description = description
+
+	return d
+}
+
+func (d *RouteDoc) Tags(tags ...string) *RouteDoc {
+	d.tags = append(d.tags, tags...)
+
+	return d
+}
+
+// Input sets the model of the request. For GET and DELETE routes the fields of the
+// model are documented as query parameters (using the form tag), otherwise the model
+// is documented as JSON request body.
+func (d *RouteDoc) Input(input interface{}) *RouteDoc {
+	d.input = input
+
+	return d
+}
+
+// Output sets the model of a successful JSON response.
+func (d *RouteDoc) Output(output interface{}) *RouteDoc {
+	d.output = output
+
+	return d
+}
+
+// Response documents an additional status code the route might respond with.
+func (d *RouteDoc) Response(statusCode int, description string) *RouteDoc {
+	if d.responses == nil {
+		d.responses = make(map[int]string)
+	}
+
+	d.responses[statusCode] = description
+
+	return d
+}
+
+func (d *RouteDoc) Deprecated() *RouteDoc {
+	d.deprecated = true
+
+	return d
+}
@@ -0,0 +1,60 @@
+package apiserver
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const (
+	OpenApiPath   = "/openapi.json"
+	SwaggerUiPath = "/swagger"
+)
+
+const swaggerUiTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@3/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@3/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = function () {
+      SwaggerUIBundle({url: "%s", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>`
+
+type OpenApiSettings struct {
+	Enabled   bool
+	SwaggerUi bool
+	Title     string
+	Version   string
+}
+
+// AddOpenApiEndpoints serves the OpenAPI specification of the given definitions at /openapi.json and,
+// if enabled, a Swagger UI at /swagger. The specification is built once when adding the endpoints.
+func AddOpenApiEndpoints(router gin.IRouter, definitions *Definitions, settings *OpenApiSettings) {
+	spec := BuildOpenApi(OpenApiInfo{
+		Title:   settings.Title,
+		Version: settings.Version,
+	}, definitions)
+
+	router.GET(OpenApiPath, func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, spec)
+	})
+
+	if !settings.SwaggerUi {
+		return
+	}
+
+	page := fmt.Sprintf(swaggerUiTemplate, settings.Title, OpenApiPath)
+
+	router.GET(SwaggerUiPath, func(ginCtx *gin.Context) {
+		ginCtx.Data(http.StatusOK, ContentTypeHtml, []byte(page))
+	})
+}
@@ -0,0 +1,92 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type openApiTestAddress struct {
+	City string `json:"city"`
+}
+
+type openApiTestInput struct {
+	Name      string              `json:"name" binding:"required"`
+	Tags      []string            `json:"tags"`
+	Address   *openApiTestAddress `json:"address"`
+	CreatedAt time.Time           `json:"createdAt"`
+	Ignored   string              `json:"-"`
+}
+
+type openApiTestQuery struct {
+	Limit int    `form:"limit" binding:"required"`
+	Query string `form:"q"`
+}
+
+func TestBuildOpenApi(t *testing.T) {
+	d := &apiserver.Definitions{}
+	group := d.Group("/v1")
+	group.POST("/items", func(*gin.Context) {}).Summary("create an item").Input(&openApiTestInput{}).Output(&openApiTestInput{}).Response(http.StatusBadRequest, "invalid input")
+	group.GET("/items/:id", func(*gin.Context) {}).Input(&openApiTestQuery{})
+
+	spec := apiserver.BuildOpenApi(apiserver.OpenApiInfo{Title: "test", Version: "1.0.0"}, d)
+	serialized, err := json.Marshal(spec)
+	assert.NoError(t, err)
+
+	expected := `{
+		"openapi": "3.0.3",
+		"info": {"title": "test", "version": "1.0.0"},
+		"paths": {
+			"/v1/items": {
+				"post": {
+					"summary": "create an item",
+					"requestBody": {
+						"required": true,
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/openApiTestInput"}}}
+					},
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/openApiTestInput"}}}
+						},
+						"400": {"description": "invalid input"}
+					}
+				}
+			},
+			"/v1/items/{id}": {
+				"get": {
+					"parameters": [
+						{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+						{"name": "limit", "in": "query", "required": true, "schema": {"type": "integer", "format": "int32"}},
+						{"name": "q", "in": "query", "required": false, "schema": {"type": "string"}}
+					],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"openApiTestAddress": {
+					"type": "object",
+					"properties": {"city": {"type": "string"}}
+				},
+				"openApiTestInput": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string"},
+						"tags": {"type": "array", "items": {"type": "string"}},
+						"address": {"$ref": "#/components/schemas/openApiTestAddress"},
+						"createdAt": {"type": "string", "format": "date-time"}
+					},
+					"required": ["name"]
+				}
+			}
+		}
+	}`
+
+	assert.JSONEq(t, expected, string(serialized))
+}
@@ -1,6 +1,7 @@
 package apiserver
 
 import (
+	"expvar"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"net/http/pprof"
@@ -8,6 +9,8 @@ import (
 
 const (
 	BaseProfiling = "/debug/profiling"
+	BaseVars      = "/debug/vars"
+	Allocs        = "/allocs"
 	CmdLine       = "/cmdline"
 	Profile       = "/profile"
 	Symbol        = "/symbol"
@@ -19,6 +22,9 @@ const (
 	ThreadCreate  = "/threadcreate"
 )
 
+// AddProfilingEndpoints adds the pprof and expvar endpoints to the router. They leak internals like the command line
+// and memory contents of your application, so only add them to a router not reachable from the outside, like the
+// one of the Profiling module.
 func AddProfilingEndpoints(r *gin.Engine) {
 	pr := r.Group(BaseProfiling)
 	pr.GET("/", profilingHandler(pprof.Index))
@@ -27,11 +33,14 @@ func AddProfilingEndpoints(r *gin.Engine) {
 	pr.POST(Symbol, profilingHandler(pprof.Symbol))
 	pr.GET(Symbol, profilingHandler(pprof.Symbol))
 	pr.GET(Trace, profilingHandler(pprof.Trace))
+	pr.GET(Allocs, profilingHandler(pprof.Handler("allocs").ServeHTTP))
 	pr.GET(Block, profilingHandler(pprof.Handler("block").ServeHTTP))
 	pr.GET(GoRoutine, profilingHandler(pprof.Handler("goroutine").ServeHTTP))
 	pr.GET(Heap, profilingHandler(pprof.Handler("heap").ServeHTTP))
 	pr.GET(Mutex, profilingHandler(pprof.Handler("mutex").ServeHTTP))
 	pr.GET(ThreadCreate, profilingHandler(pprof.Handler("threadcreate").ServeHTTP))
+
+	r.GET(BaseVars, profilingHandler(expvar.Handler().ServeHTTP))
 }
 
 func profilingHandler(handler http.HandlerFunc) gin.HandlerFunc {
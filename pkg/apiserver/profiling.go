@@ -0,0 +1,98 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ProfilingAuthSettings struct {
+	Username string `cfg:"username"`
+	Password string `cfg:"password"`
+}
+
+type ProfilingSettings struct {
+	Enabled bool                  `cfg:"enabled" default:"false"`
+	Port    int                   `cfg:"port" default:"8091"`
+	Auth    ProfilingAuthSettings `cfg:"auth"`
+}
+
+// Profiling exposes the pprof and expvar endpoints on a separate port. The api server doesn't
+// serve them, so enable this module to profile your application. As the port is not meant
+// to be reachable from the outside, it should not be exposed by your load balancer. If you
+// configure a username and password, every request has to provide them via basic auth.
+type Profiling struct {
+	kernel.BackgroundModule
+	kernel.ServiceStage
+
+	logger   mon.Logger
+	server   *http.Server
+	settings *ProfilingSettings
+}
+
+func NewProfiling() kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		settings := &ProfilingSettings{}
+		config.UnmarshalKey("api.profiling", settings)
+
+		gin.SetMode(gin.ReleaseMode)
+		router := gin.New()
+
+		return NewProfilingWithInterfaces(logger, router, settings), nil
+	}
+}
+
+func NewProfilingWithInterfaces(logger mon.Logger, router *gin.Engine, settings *ProfilingSettings) *Profiling {
+	logger = logger.WithChannel("profiling")
+
+	if settings.Auth.Username != "" {
+		router.Use(gin.BasicAuth(gin.Accounts{
+			settings.Auth.Username: settings.Auth.Password,
+		}))
+	}
+
+	AddProfilingEndpoints(router)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", settings.Port),
+		Handler: router,
+	}
+
+	return &Profiling{
+		logger:   logger,
+		server:   server,
+		settings: settings,
+	}
+}
+
+func (p *Profiling) Run(ctx context.Context) error {
+	if !p.settings.Enabled {
+		p.logger.Info("profiling not enabled..")
+		return nil
+	}
+
+	go p.waitForStop(ctx)
+
+	p.logger.Infof("serving profiling endpoints on port %d", p.settings.Port)
+	err := p.server.ListenAndServe()
+
+	if err != http.ErrServerClosed {
+		p.logger.Error(err, "profiling server closed unexpected")
+		return err
+	}
+
+	return nil
+}
+
+func (p *Profiling) waitForStop(ctx context.Context) {
+	<-ctx.Done()
+	err := p.server.Close()
+
+	if err != nil {
+		p.logger.Error(err, "profiling server close")
+	}
+}
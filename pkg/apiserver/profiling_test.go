@@ -0,0 +1,46 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProfiling_Vars(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	logger := mocks.NewLoggerMockedAll()
+
+	apiserver.NewProfilingWithInterfaces(logger, ginEngine, &apiserver.ProfilingSettings{})
+
+	httpRecorder := httptest.NewRecorder()
+	assertRouteReturnsResponse(t, ginEngine, httpRecorder, apiserver.BaseVars, http.StatusOK)
+}
+
+func TestProfiling_BasicAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	logger := mocks.NewLoggerMockedAll()
+
+	apiserver.NewProfilingWithInterfaces(logger, ginEngine, &apiserver.ProfilingSettings{
+		Auth: apiserver.ProfilingAuthSettings{
+			Username: "admin",
+			Password: "secret",
+		},
+	})
+
+	httpRecorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, apiserver.BaseVars, nil)
+	ginEngine.ServeHTTP(httpRecorder, request)
+	assert.Equal(t, http.StatusUnauthorized, httpRecorder.Code)
+
+	httpRecorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, apiserver.BaseVars, nil)
+	request.SetBasicAuth("admin", "secret")
+	ginEngine.ServeHTTP(httpRecorder, request)
+	assert.Equal(t, http.StatusOK, httpRecorder.Code)
+}
@@ -12,6 +12,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,6 +22,36 @@ type Settings struct {
 	TimeoutRead  time.Duration
 	TimeoutWrite time.Duration
 	TimeoutIdle  time.Duration
+	// time the health endpoints report unhealthy before the server stops accepting new connections
+	DrainDelay time.Duration
+	// maximum time to wait for in-flight requests to finish on shutdown
+	DrainTimeout time.Duration
+}
+
+// A drainFlag is set as soon as an api server starts to shut down. From then on the health endpoints of that
+// server report the application as unhealthy so load balancers stop routing new requests to it. A nil flag is
+// never set, e.g. for health endpoints not belonging to an api server.
+type drainFlag struct {
+	draining int32
+}
+
+func (f *drainFlag) start() {
+	atomic.StoreInt32(&f.draining, 1)
+}
+
+func (f *drainFlag) isDraining() bool {
+	return f != nil && atomic.LoadInt32(&f.draining) == 1
+}
+
+func healthHandler(drain *drainFlag) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if drain.isDraining() {
+			ginCtx.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, gin.H{})
+	}
 }
 
 type ApiServer struct {
@@ -30,6 +61,9 @@ type ApiServer struct {
 	logger   mon.Logger
 	server   *http.Server
 	listener net.Listener
+	settings *Settings
+	drain    *drainFlag
+	stopped  chan struct{}
 }
 
 func New(definer Definer) kernel.ModuleFactory {
@@ -40,40 +74,67 @@ func New(definer Definer) kernel.ModuleFactory {
 			TimeoutRead:  config.GetDuration("api_timeout_read"),
 			TimeoutWrite: config.GetDuration("api_timeout_write"),
 			TimeoutIdle:  config.GetDuration("api_timeout_idle"),
+			DrainDelay:   config.GetDuration("api_drain_delay", 3*time.Second),
+			DrainTimeout: config.GetDuration("api_drain_timeout", 5*time.Second),
 		}
 
 		gin.SetMode(settings.Mode)
 
+		if config.GetString("api_error_format", "json") == "problem" {
+			WithErrorHandler(ErrorHandlerProblemJson)
+		}
+
 		tracer, err := tracing.ProvideTracer(config, logger)
 		if err != nil {
 			return nil, fmt.Errorf("can not create tracer: %w", err)
 		}
 
 		router := gin.New()
-		AddProfilingEndpoints(router)
+		drain := &drainFlag{}
 
-		router.GET("/health", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{})
-		})
+		addHealthEndpoints(router, "/health", config.GetDuration("api_health_check_timeout", 2*time.Second), drain)
 
 		definitions, err := definer(ctx, config, logger)
 		if err != nil {
 			return nil, fmt.Errorf("could not define routes: %w", err)
 		}
 
+		router.Use(MetricMiddleware(definitions))
+		router.Use(RequestIdMiddleware())
+		router.Use(SessionMiddleware())
 		router.Use(RecoveryWithSentry(logger))
 		router.Use(LoggingMiddleware(logger))
 
+		if corsSettings := ReadCorsSettings(config); corsSettings.Enabled {
+			router.Use(CorsWithSettings(corsSettings))
+		}
+
+		configureVersions(config, definitions)
 		buildRouter(definitions, router)
 
-		return NewWithInterfaces(logger, router, tracer, settings)
+		openApiSettings := &OpenApiSettings{
+			Enabled:   config.GetBool("api_openapi_enabled", false),
+			SwaggerUi: config.GetBool("api_openapi_swagger_ui", false),
+			Title:     config.GetString("app_name"),
+			Version:   config.GetString("api_openapi_version", "1.0.0"),
+		}
+
+		if openApiSettings.Enabled {
+			AddOpenApiEndpoints(router, definitions, openApiSettings)
+		}
+
+		return newApiServer(logger, router, tracer, settings, drain)
 	}
 }
 
 func NewWithInterfaces(logger mon.Logger, router *gin.Engine, tracer tracing.Tracer, s *Settings) (*ApiServer, error) {
+	return newApiServer(logger, router, tracer, s, &drainFlag{})
+}
+
+func newApiServer(logger mon.Logger, router *gin.Engine, tracer tracing.Tracer, s *Settings, drain *drainFlag) (*ApiServer, error) {
 	server := &http.Server{
 		Addr:         ":" + s.Port,
-		Handler:      tracer.HttpHandler(router),
+		Handler:      tracer.HttpHandler(tracing.PropagationHandler(router)),
 		ReadTimeout:  s.TimeoutRead * time.Second,
 		WriteTimeout: s.TimeoutWrite * time.Second,
 		IdleTimeout:  s.TimeoutIdle * time.Second,
@@ -99,6 +160,9 @@ func NewWithInterfaces(logger mon.Logger, router *gin.Engine, tracer tracing.Tra
 		logger:   logger,
 		server:   server,
 		listener: listener,
+		settings: s,
+		drain:    drain,
+		stopped:  make(chan struct{}),
 	}
 
 	return apiServer, nil
@@ -115,15 +179,32 @@ func (a *ApiServer) Run(ctx context.Context) error {
 		return err
 	}
 
+	// serve returns as soon as the shutdown started, so we have to wait for the in-flight requests
+	<-a.stopped
+
 	return nil
 }
 
 func (a *ApiServer) waitForStop(ctx context.Context) {
+	defer close(a.stopped)
+
 	<-ctx.Done()
-	err := a.server.Close()
+	a.drain.start()
 
-	if err != nil {
-		a.logger.Error(err, "Server Close")
+	if a.settings.DrainDelay > 0 {
+		a.logger.Infof("reporting unhealthy for %s before draining the api", a.settings.DrainDelay)
+		time.Sleep(a.settings.DrainDelay)
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), a.settings.DrainTimeout)
+	defer cancel()
+
+	if err := a.server.Shutdown(drainCtx); err != nil {
+		a.logger.Warnf("could not drain all in-flight requests within %s: %s", a.settings.DrainTimeout, err)
+
+		if err := a.server.Close(); err != nil {
+			a.logger.Error(err, "Server Close")
+		}
 	}
 
 	a.logger.Info("leaving api")
@@ -10,11 +10,14 @@ import (
 	tracingMocks "github.com/applike/gosoline/pkg/tracing/mocks"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 type ServerTestSuite struct {
@@ -36,7 +39,7 @@ func (s *ServerTestSuite) SetupTest() {
 	s.router = gin.New()
 
 	tracer := new(tracingMocks.Tracer)
-	tracer.On("HttpHandler", s.router).Return(s.router)
+	tracer.On("HttpHandler", mock.AnythingOfType("http.HandlerFunc")).Return(s.router)
 	s.tracer = tracer
 
 	server, err := apiserver.NewWithInterfaces(s.logger, s.router, s.tracer, &apiserver.Settings{})
@@ -55,6 +58,52 @@ func (s *ServerTestSuite) TestLifecycle_Cancel() {
 	})
 }
 
+func (s *ServerTestSuite) TestLifecycle_Drain() {
+	started := make(chan struct{})
+	router := gin.New()
+	router.GET("/slow", func(ginCtx *gin.Context) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		ginCtx.String(http.StatusOK, "done")
+	})
+
+	tracer := new(tracingMocks.Tracer)
+	tracer.On("HttpHandler", mock.AnythingOfType("http.HandlerFunc")).Return(router)
+
+	server, err := apiserver.NewWithInterfaces(s.logger, router, tracer, &apiserver.Settings{
+		DrainTimeout: time.Second,
+	})
+	s.NoError(err)
+
+	port, err := server.GetPort()
+	s.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+
+	go func() {
+		stopped <- server.Run(ctx)
+	}()
+
+	responses := make(chan string)
+
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/slow", *port))
+		s.NoError(err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		s.NoError(err)
+
+		responses <- string(body)
+	}()
+
+	<-started
+	cancel()
+
+	s.Equal("done", <-responses)
+	s.NoError(<-stopped)
+}
+
 func (s *ServerTestSuite) TestGetPort() {
 	s.NotPanics(func() {
 		port, err := s.server.GetPort()
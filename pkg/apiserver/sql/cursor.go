@@ -0,0 +1,154 @@
+package sql
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+type cursor struct {
+	Offset int           `json:"o"`
+	Limit  int           `json:"l"`
+	Keys   []interface{} `json:"k,omitempty"`
+	Query  string        `json:"q"`
+}
+
+// EncodeCursor creates an opaque cursor pointing to the page with the given offset and limit of the list request
+// inp. Clients should pass it back unchanged in Page.Cursor instead of computing offsets on their own. The cursor
+// is bound to the filter, order and group by of inp and is rejected for any other list request.
+func EncodeCursor(inp *Input, offset int, limit int) string {
+	data, _ := json.Marshal(cursor{
+		Offset: offset,
+		Limit:  limit,
+		Query:  queryFingerprint(inp),
+	})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// EncodeKeysetCursor creates an opaque cursor pointing to the page after the row with the given values of the order
+// fields. Like the cursors of EncodeCursor it is bound to the list request inp.
+func EncodeKeysetCursor(inp *Input, keys []interface{}, limit int) string {
+	data, _ := json.Marshal(cursor{
+		Limit: limit,
+		Keys:  keys,
+		Query: queryFingerprint(inp),
+	})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// checkCursorQuery returns an error if the cursor of the page of inp was created for another filter, order or group by
+func checkCursorQuery(inp *Input) error {
+	if inp.Page == nil || inp.Page.Cursor == "" {
+		return nil
+	}
+
+	c, err := decodeCursor(inp.Page.Cursor)
+	if err != nil {
+		return err
+	}
+
+	if c.Query != queryFingerprint(inp) {
+		return fmt.Errorf("invalid cursor %s: it was created for another filter, order or group by", inp.Page.Cursor)
+	}
+
+	return nil
+}
+
+// DecodeCursor returns the offset and limit of a cursor created by EncodeCursor.
+func DecodeCursor(encoded string) (offset int, limit int, err error) {
+	c, err := decodeCursor(encoded)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if c.Offset < 0 || len(c.Keys) > 0 {
+		return 0, 0, fmt.Errorf("invalid cursor %s: not an offset cursor", encoded)
+	}
+
+	return c.Offset, c.Limit, nil
+}
+
+// DecodeKeysetCursor returns the values of the order fields and the limit of a cursor created by EncodeKeysetCursor.
+func DecodeKeysetCursor(encoded string) (keys []interface{}, limit int, err error) {
+	c, err := decodeCursor(encoded)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(c.Keys) == 0 {
+		return nil, 0, fmt.Errorf("invalid cursor %s: not a keyset cursor", encoded)
+	}
+
+	return c.Keys, c.Limit, nil
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %s: %w", encoded, err)
+	}
+
+	c := &cursor{}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid cursor %s: %w", encoded, err)
+	}
+
+	if c.Limit <= 0 {
+		return nil, fmt.Errorf("invalid cursor %s: offset or limit out of range", encoded)
+	}
+
+	return c, nil
+}
+
+// isKeysetCursor returns true if the cursor was created by EncodeKeysetCursor
+func isKeysetCursor(encoded string) bool {
+	c, err := decodeCursor(encoded)
+
+	return err == nil && len(c.Keys) > 0
+}
+
+// queryFingerprint hashes everything but the page of the list request. Empty and missing lists are treated the same,
+// so requests decoded from json match the ones built in code.
+func queryFingerprint(inp *Input) string {
+	h := sha256.New()
+
+	if inp != nil {
+		writeFilterFingerprint(h, inp.Filter)
+
+		for _, o := range inp.Order {
+			_, _ = fmt.Fprintf(h, "o:%q %s;", o.Field, strings.ToUpper(o.Direction))
+		}
+
+		for _, g := range inp.GroupBy {
+			_, _ = fmt.Fprintf(h, "g:%q;", g)
+		}
+	}
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
+
+func writeFilterFingerprint(h hash.Hash, filter Filter) {
+	_, _ = fmt.Fprintf(h, "b:%s;", strings.ToUpper(filter.Bool))
+
+	for _, m := range filter.Matches {
+		_, _ = fmt.Fprintf(h, "m:%q %q", m.Dimension, m.Operator)
+
+		for _, v := range m.Values {
+			value, _ := json.Marshal(v)
+			_, _ = fmt.Fprintf(h, " %s", value)
+		}
+
+		_, _ = h.Write([]byte(";"))
+	}
+
+	for _, g := range filter.Groups {
+		_, _ = h.Write([]byte("("))
+		writeFilterFingerprint(h, g)
+		_, _ = h.Write([]byte(")"))
+	}
+}
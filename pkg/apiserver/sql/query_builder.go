@@ -13,8 +13,35 @@ const (
 	OpLike  = "~"
 	OpIs    = "is"
 	OpIsNot = "is not"
+	OpLt    = "<"
+	OpLte   = "<="
+	OpGt    = ">"
+	OpGte   = ">="
+
+	DirectionAsc  = "ASC"
+	DirectionDesc = "DESC"
 )
 
+// values of is and is not are written into the query directly, so only literals are allowed
+var allowedIsValues = map[string]bool{
+	"null":    true,
+	"true":    true,
+	"false":   true,
+	"unknown": true,
+}
+
+var allowedOperators = map[string]bool{
+	OpEq:    true,
+	OpNeq:   true,
+	OpLike:  true,
+	OpIs:    true,
+	OpIsNot: true,
+	OpLt:    true,
+	OpLte:   true,
+	OpGt:    true,
+	OpGte:   true,
+}
+
 type Order struct {
 	Field     string `json:"field"`
 	Direction string `json:"direction"`
@@ -23,6 +50,46 @@ type Order struct {
 type Page struct {
 	Offset int `json:"offset"`
 	Limit  int `json:"limit"`
+	// Cursor as returned by a previous list request, it takes precedence over Offset and Limit
+	Cursor string `json:"cursor,omitempty"`
+	// Keyset requests the first page of a keyset pagination, the cursors of the response continue it. Keyset pages
+	// are ordered by the primary key after the given order and only offer a cursor to the next page.
+	Keyset bool `json:"keyset,omitempty"`
+}
+
+// IsKeyset returns true if the page is the first page of a keyset pagination or its cursor continues one.
+func (p Page) IsKeyset() bool {
+	if p.Cursor != "" {
+		return isKeysetCursor(p.Cursor)
+	}
+
+	return p.Keyset
+}
+
+// ResolveKeyset returns the values of the order fields to continue after and the limit of a keyset page.
+func (p Page) ResolveKeyset() (keys []interface{}, limit int, err error) {
+	if p.Cursor != "" {
+		return DecodeKeysetCursor(p.Cursor)
+	}
+
+	if p.Limit <= 0 {
+		return nil, 0, fmt.Errorf("the limit of a keyset page must be positive")
+	}
+
+	return nil, p.Limit, nil
+}
+
+// Resolve returns the offset and limit of the page, decoding the cursor if one is given.
+func (p Page) Resolve() (offset int, limit int, err error) {
+	if p.Cursor != "" {
+		return DecodeCursor(p.Cursor)
+	}
+
+	if p.Offset < 0 || p.Limit < 0 {
+		return 0, 0, fmt.Errorf("page offset and limit must not be negative")
+	}
+
+	return p.Offset, p.Limit, nil
 }
 
 type Filter struct {
@@ -139,14 +206,77 @@ func (qb baseQueryBuilder) build(inp *Input, dbQb db.QueryBuilder) error {
 			return fmt.Errorf("no list mapping found for order field %s", o.Field)
 		}
 
+		direction := strings.ToUpper(o.Direction)
+
+		if direction != DirectionAsc && direction != DirectionDesc {
+			return fmt.Errorf("invalid direction %s for order field %s", o.Direction, o.Field)
+		}
+
 		columns := strings.Join(qb.mapping[o.Field].ColumnNames(), ", ")
-		dbQb.OrderBy(columns, o.Direction)
+		dbQb.OrderBy(columns, direction)
+	}
+
+	if err := checkCursorQuery(inp); err != nil {
+		return err
+	}
+
+	if inp.Page != nil && inp.Page.IsKeyset() {
+		return qb.buildKeyset(inp, dbQb)
 	}
 
 	if inp.Page != nil {
-		dbQb.Page(inp.Page.Offset, inp.Page.Limit)
+		offset, limit, err := inp.Page.Resolve()
+
+		if err != nil {
+			return err
+		}
+
+		dbQb.Page(offset, limit)
+	}
+
+	return nil
+}
+
+// buildKeyset orders by the primary key after the given order, so every row has a distinct position to continue after
+func (qb baseQueryBuilder) buildKeyset(inp *Input, dbQb db.QueryBuilder) error {
+	ormQb, ok := dbQb.(*db_repo.QueryBuilder)
+
+	if !ok {
+		return fmt.Errorf("keyset pages are only supported by the orm")
+	}
+
+	ordered := len(inp.Order)
+	orderedByPrimaryKey := false
+
+	for _, o := range inp.Order {
+		columns := qb.mapping[o.Field].ColumnNames()
+
+		if len(columns) != 1 {
+			return fmt.Errorf("can not page by the order field %s with multiple columns", o.Field)
+		}
+
+		if columns[0] == qb.metadata.PrimaryKey {
+			orderedByPrimaryKey = true
+		}
 	}
 
+	if !orderedByPrimaryKey {
+		ormQb.OrderBy(qb.metadata.PrimaryKey, DirectionAsc)
+		ordered++
+	}
+
+	keys, limit, err := inp.Page.ResolveKeyset()
+
+	if err != nil {
+		return err
+	}
+
+	if len(keys) > 0 && len(keys) != ordered {
+		return fmt.Errorf("the cursor does not match the order of the page")
+	}
+
+	ormQb.Keyset(keys, limit)
+
 	return nil
 }
 
@@ -268,6 +398,18 @@ func (qb baseQueryBuilder) buildFilterValues(match FilterMatch) (string, []inter
 		return "", []interface{}{}, fmt.Errorf("no list mapping found for dimension %s", match.Dimension)
 	}
 
+	if !allowedOperators[strings.ToLower(match.Operator)] {
+		return "", []interface{}{}, fmt.Errorf("invalid operator %s for dimension %s", match.Operator, match.Dimension)
+	}
+
+	if strings.EqualFold(OpIs, match.Operator) || strings.EqualFold(OpIsNot, match.Operator) {
+		for _, v := range match.Values {
+			if literal, ok := v.(string); !ok || !allowedIsValues[strings.ToLower(literal)] {
+				return "", []interface{}{}, fmt.Errorf("invalid value %v for operator %s of dimension %s", v, match.Operator, match.Dimension)
+			}
+		}
+	}
+
 	if len(match.Values) == 0 {
 		return "(1 = 2)", []interface{}{}, nil
 	}
@@ -1,6 +1,7 @@
 package sql_test
 
 import (
+	"fmt"
 	"github.com/applike/gosoline/pkg/apiserver/sql"
 	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/stretchr/testify/assert"
@@ -340,3 +341,186 @@ func TestListQueryBuilder_Build_NullFilter(t *testing.T) {
 
 	assert.Equal(t, expected, qb)
 }
+
+func TestListQueryBuilder_Build_InvalidInput(t *testing.T) {
+	metadata := db_repo.Metadata{
+		TableName:  "tablename",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id": db_repo.NewFieldMapping("id"),
+		},
+	}
+
+	for name, test := range map[string]struct {
+		inp *sql.Input
+		err string
+	}{
+		"operator": {
+			inp: &sql.Input{Filter: sql.Filter{Matches: []sql.FilterMatch{{Dimension: "id", Operator: "= 1 OR 1 =", Values: []interface{}{1}}}}},
+			err: "invalid operator = 1 OR 1 = for dimension id",
+		},
+		"is value": {
+			inp: &sql.Input{Filter: sql.Filter{Matches: []sql.FilterMatch{{Dimension: "id", Operator: "is", Values: []interface{}{"null OR 1 = 1"}}}}},
+			err: "invalid value null OR 1 = 1 for operator is of dimension id",
+		},
+		"direction": {
+			inp: &sql.Input{Order: []sql.Order{{Field: "id", Direction: "ASC; DROP TABLE tablename"}}},
+			err: "invalid direction ASC; DROP TABLE tablename for order field id",
+		},
+		"cursor": {
+			inp: &sql.Input{Page: &sql.Page{Cursor: "not a cursor"}},
+			err: "invalid cursor not a cursor: illegal base64 data at input byte 3",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lqb := sql.NewOrmQueryBuilder(metadata)
+			_, err := lqb.Build(test.inp)
+
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestListQueryBuilder_Build_Cursor(t *testing.T) {
+	metadata := db_repo.Metadata{
+		TableName:  "tablename",
+		PrimaryKey: "id",
+	}
+
+	inp := &sql.Input{
+		Page: &sql.Page{
+			Cursor: sql.EncodeCursor(&sql.Input{}, 20, 10),
+		},
+	}
+
+	lqb := sql.NewOrmQueryBuilder(metadata)
+	qb, err := lqb.Build(inp)
+
+	assert.NoError(t, err)
+
+	expected := db_repo.NewQueryBuilder()
+	expected.Table("tablename")
+	expected.Where("", []interface{}{}...)
+	expected.GroupBy("id")
+	expected.Page(20, 10)
+
+	assert.Equal(t, expected, qb)
+}
+
+func TestListQueryBuilder_Build_Keyset(t *testing.T) {
+	metadata := db_repo.Metadata{
+		TableName:  "tablename",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	}
+
+	inp := &sql.Input{
+		Order: []sql.Order{
+			{
+				Field:     "name",
+				Direction: "desc",
+			},
+		},
+	}
+	inp.Page = &sql.Page{
+		Cursor: sql.EncodeKeysetCursor(inp, []interface{}{"foo", 3}, 10),
+	}
+
+	lqb := sql.NewOrmQueryBuilder(metadata)
+	qb, err := lqb.Build(inp)
+
+	assert.NoError(t, err)
+
+	expected := db_repo.NewQueryBuilder()
+	expected.Table("tablename")
+	expected.Where("", []interface{}{}...)
+	expected.GroupBy("id")
+	expected.OrderBy("name", "DESC")
+	expected.OrderBy("id", "ASC")
+	expected.Keyset([]interface{}{"foo", float64(3)}, 10)
+
+	assert.Equal(t, expected, qb)
+}
+
+func TestListQueryBuilder_Build_KeysetInvalid(t *testing.T) {
+	metadata := db_repo.Metadata{
+		TableName:  "tablename",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"name": db_repo.NewFieldMapping("name").WithColumn("title"),
+		},
+	}
+
+	for name, test := range map[string]struct {
+		inp *sql.Input
+		err string
+	}{
+		"no limit": {
+			inp: &sql.Input{Page: &sql.Page{Keyset: true}},
+			err: "the limit of a keyset page must be positive",
+		},
+		"cursor of another order": {
+			inp: &sql.Input{Page: &sql.Page{Cursor: sql.EncodeKeysetCursor(&sql.Input{}, []interface{}{"foo", 3}, 10)}},
+			err: "the cursor does not match the order of the page",
+		},
+		"multiple columns": {
+			inp: &sql.Input{Order: []sql.Order{{Field: "name", Direction: "asc"}}, Page: &sql.Page{Keyset: true, Limit: 10}},
+			err: "can not page by the order field name with multiple columns",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lqb := sql.NewOrmQueryBuilder(metadata)
+			_, err := lqb.Build(test.inp)
+
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestListQueryBuilder_Build_CursorOfAnotherQuery(t *testing.T) {
+	metadata := db_repo.Metadata{
+		TableName:  "tablename",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	}
+
+	filtered := &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{{Dimension: "name", Operator: "=", Values: []interface{}{"foo"}}},
+		},
+	}
+	cursor := sql.EncodeCursor(filtered, 20, 10)
+
+	for name, inp := range map[string]*sql.Input{
+		"filter": {
+			Filter: sql.Filter{
+				Matches: []sql.FilterMatch{{Dimension: "name", Operator: "=", Values: []interface{}{"bar"}}},
+			},
+		},
+		"order": {
+			Filter: filtered.Filter,
+			Order:  []sql.Order{{Field: "name", Direction: "asc"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			inp.Page = &sql.Page{Cursor: cursor}
+
+			lqb := sql.NewOrmQueryBuilder(metadata)
+			_, err := lqb.Build(inp)
+
+			assert.EqualError(t, err, fmt.Sprintf("invalid cursor %s: it was created for another filter, order or group by", cursor))
+		})
+	}
+
+	filtered.Page = &sql.Page{Cursor: cursor}
+	filtered.Order = make([]sql.Order, 0)
+
+	lqb := sql.NewOrmQueryBuilder(metadata)
+	_, err := lqb.Build(filtered)
+
+	assert.NoError(t, err)
+}
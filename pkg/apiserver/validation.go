@@ -0,0 +1,113 @@
+package apiserver
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"gopkg.in/go-playground/validator.v8"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned if the input of a request violates the constraints defined
+// by the binding tags of the input struct. The fields are named like they were sent by
+// the client, so a json input reports the json names and a query or form input the form names.
+type ValidationError struct {
+	err    error
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+func newValidationError(err error, input interface{}, b binding.Binding) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+
+	if !ok {
+		return err
+	}
+
+	tagName := "form"
+	if b.Name() == binding.JSON.Name() {
+		tagName = "json"
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+
+	for _, fieldErr := range validationErrors {
+		field := getInputFieldName(reflect.TypeOf(input), fieldErr.FieldNamespace, tagName)
+
+		fields = append(fields, FieldError{
+			Field:   field,
+			Tag:     fieldErr.Tag,
+			Param:   fieldErr.Param,
+			Message: fmt.Sprintf("validation for '%s' failed on the '%s' tag", field, fieldErr.Tag),
+		})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+
+	return &ValidationError{
+		err:    err,
+		Fields: fields,
+	}
+}
+
+// getInputFieldName translates the namespace of a struct field (like Input.Address.City) to the
+// path of the field as seen by the client (like address.city).
+func getInputFieldName(typ reflect.Type, namespace string, tagName string) string {
+	parts := strings.Split(namespace, ".")
+	names := make([]string, 0, len(parts))
+
+	// the first part is the name of the input struct itself
+	for _, part := range parts[1:] {
+		for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map) {
+			typ = typ.Elem()
+		}
+
+		// slice and map elements are reported as Field[0] or Field[key]
+		fieldName := part
+		suffix := ""
+
+		if idx := strings.Index(part, "["); idx != -1 {
+			fieldName, suffix = part[:idx], part[idx:]
+		}
+
+		if typ == nil || typ.Kind() != reflect.Struct {
+			names = append(names, part)
+			typ = nil
+			continue
+		}
+
+		field, ok := typ.FieldByName(fieldName)
+		if !ok {
+			names = append(names, part)
+			typ = nil
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get(tagName), ",")[0]
+		if name == "" || name == "-" {
+			name = fieldName
+		}
+
+		names = append(names, name+suffix)
+		typ = field.Type
+	}
+
+	return strings.Join(names, ".")
+}
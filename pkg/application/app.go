@@ -35,23 +35,29 @@ func Default(options ...Option) kernel.Kernel {
 	defaults := []Option{
 		WithApiHealthCheck,
 		WithConfigErrorHandlers(defaultErrorHandler),
-		WithConfigFile("./config.dist.yml", "yml"),
+		WithConfigFileLayers("./config.dist.yml", "yml"),
 		WithConfigFileFlag,
 		WithConfigEnvKeyReplacer(cfg.DefaultEnvKeyReplacer),
+		WithConfigKmsDecryption,
 		WithConfigSanitizers(cfg.TimeSanitizer),
+		WithConfigSecretsRefresh,
 		WithConfigServer,
 		WithConsumerMessagesPerRunnerMetrics,
 		WithKernelSettingsFromConfig,
+		WithKvStoreInvalidation,
 		WithLoggerFormat(mon.FormatGelfFields),
 		WithLoggerApplicationTag,
 		WithLoggerTagsFromConfig,
 		WithLoggerSettingsFromConfig,
+		WithLoggerContainerMetadata,
 		WithLoggerContextFieldsMessageEncoder(),
 		WithLoggerContextFieldsResolver(mon.ContextLoggerFieldsResolver),
 		WithLoggerMetricHook,
 		WithLoggerSentryHook(mon.SentryExtraConfigProvider, mon.SentryExtraEcsMetadataProvider),
 		WithMetricDaemon,
+		WithPanicHandler,
 		WithProducerDaemon,
+		WithRequestIdMessageEncoder,
 		WithTracing,
 		WithUTCClock(true),
 	}
@@ -6,8 +6,12 @@ import (
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/cloud"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/dynsettings"
 	"github.com/applike/gosoline/pkg/fixtures"
 	kernelPkg "github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/kvstore"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/stream"
 	"github.com/applike/gosoline/pkg/tracing"
@@ -25,7 +29,10 @@ type KernelOption func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error
 type SetupOption func(config cfg.GosoConf, logger mon.GosoLog) error
 
 type kernelSettings struct {
-	KillTimeout time.Duration `cfg:"killTimeout" default:"10s"`
+	KillTimeout       time.Duration `cfg:"killTimeout" default:"10s"`
+	HealthInterval    time.Duration `cfg:"healthInterval" default:"1m"`
+	SingletonInterval time.Duration `cfg:"singletonInterval" default:"10s"`
+	DryRun            bool          `cfg:"dryRun" default:"false"`
 }
 
 type loggerSettings struct {
@@ -35,9 +42,18 @@ type loggerSettings struct {
 	Tags            map[string]interface{} `cfg:"tags"`
 }
 
+func WithAdmin(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.Add("admin", apiserver.NewAdmin())
+		return nil
+	})
+}
+
 func WithApiHealthCheck(app *App) {
 	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
 		kernel.Add("api-health-check", apiserver.NewApiHealthCheck())
+		apiserver.AddHealthChecker("kernel", apiserver.ReadySignalHealthChecker(kernel.Ready()))
+
 		return nil
 	})
 }
@@ -80,6 +96,14 @@ func WithConfigFile(filePath string, fileType string) Option {
 	}
 }
 
+func WithConfigFileLayers(filePath string, fileType string) Option {
+	return func(app *App) {
+		app.addConfigOption(func(config cfg.GosoConf) error {
+			return config.Option(cfg.WithConfigFileLayers(filePath, fileType))
+		})
+	}
+}
+
 func WithConfigFileFlag(app *App) {
 	app.addConfigOption(func(config cfg.GosoConf) error {
 		flags := flag.NewFlagSet("cfg", flag.ContinueOnError)
@@ -117,6 +141,30 @@ func WithConfigSanitizers(sanitizers ...cfg.Sanitizer) Option {
 	}
 }
 
+// WithConfigKmsDecryption decrypts config values of the form enc:kms:<ciphertext> with KMS when they are read
+func WithConfigKmsDecryption(app *App) {
+	app.addSetupOption(func(config cfg.GosoConf, _ mon.GosoLog) error {
+		return config.Option(cfg.WithDecrypter(cloud.ConfigEncryptionSchemeKms, cloud.NewKmsDecrypter(config)))
+	})
+}
+
+func WithConfigSecretsRefresh(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.AddFactory(func(config cfg.Config, logger mon.Logger) (map[string]kernelPkg.ModuleFactory, error) {
+			modules := map[string]kernelPkg.ModuleFactory{}
+
+			if refresher, ok := cloud.NewConfigSecretsRefresher(config, logger); ok {
+				modules["config-secrets-refresher"] = func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernelPkg.Module, error) {
+					return refresher, nil
+				}
+			}
+
+			return modules, nil
+		})
+		return nil
+	})
+}
+
 func WithConfigServer(app *App) {
 	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
 		kernel.Add("config-server", NewConfigServer())
@@ -139,6 +187,20 @@ func WithConsumerMessagesPerRunnerMetrics(app *App) {
 	})
 }
 
+// WithDynamicSettings merges the dynamic settings of the keys configured at dynsettings.keys into the config
+func WithDynamicSettings(app *App) {
+	app.addSetupOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
+		service, err := dynsettings.ProvideService(config, logger)
+		if err != nil {
+			return errors.Wrap(err, "can not create dynamic settings service")
+		}
+
+		keys := config.GetStringSlice("dynsettings.keys", []string{})
+
+		return dynsettings.ApplyConfigOverlay(context.Background(), config, service, keys)
+	})
+}
+
 func WithFixtures(fixtureSets []*fixtures.FixtureSet) Option {
 	return func(app *App) {
 		app.addSetupOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
@@ -153,7 +215,32 @@ func WithKernelSettingsFromConfig(app *App) {
 		settings := &kernelSettings{}
 		config.UnmarshalKey("kernel", settings)
 
-		return k.Option(kernelPkg.KillTimeout(settings.KillTimeout))
+		if settings.DryRun {
+			// a dry run has to validate the existing resources instead of creating missing ones
+			err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+				"aws_dynamoDb_autoCreate": false,
+				"aws_kinesis_autoCreate":  false,
+				"aws_s3_autoCreate":       false,
+				"aws_sqs_autoCreate":      false,
+			}))
+			if err != nil {
+				return errors.Wrap(err, "can not disable the auto creation of resources for the dry run")
+			}
+		}
+
+		return k.Option(
+			kernelPkg.KillTimeout(settings.KillTimeout),
+			kernelPkg.HealthInterval(settings.HealthInterval),
+			kernelPkg.SingletonInterval(settings.SingletonInterval),
+			kernelPkg.DryRun(settings.DryRun),
+		)
+	})
+}
+
+func WithKvStoreInvalidation(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.AddFactory(kvstore.NewInvalidationModuleFactory)
+		return nil
 	})
 }
 
@@ -169,6 +256,24 @@ func WithLoggerApplicationTag(app *App) {
 	})
 }
 
+func WithLoggerContainerMetadata(app *App) {
+	app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
+		settings := &mon.ContainerMetadataSettings{}
+		config.UnmarshalKey("mon.logger.container_metadata", settings)
+
+		if !settings.Enabled {
+			return nil
+		}
+
+		resolver, err := mon.NewContainerMetadataFieldsResolver(settings)
+		if err != nil {
+			return errors.Wrap(err, "can not configure LoggerContainerMetadata")
+		}
+
+		return logger.Option(mon.WithContextFieldsResolver(resolver))
+	})
+}
+
 func WithLoggerContextFieldsMessageEncoder() Option {
 	return func(app *App) {
 		app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
@@ -194,6 +299,20 @@ func WithLoggerFormat(format string) Option {
 	}
 }
 
+func WithLoggerGelfHook(app *App) {
+	app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
+		settings := &mon.GelfSettings{}
+		config.UnmarshalKey("mon.logger.gelf", settings)
+
+		gelfHook, err := mon.NewGelfHook(settings)
+		if err != nil {
+			return errors.Wrap(err, "can not configure LoggerGelfHook")
+		}
+
+		return logger.Option(mon.WithHook(gelfHook))
+	})
+}
+
 func WithLoggerHook(hook mon.LoggerHook) Option {
 	return func(app *App) {
 		app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
@@ -280,6 +399,20 @@ func WithMetricDaemon(app *App) {
 	})
 }
 
+func WithProfiling(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.Add("profiling", apiserver.NewProfiling())
+		return nil
+	})
+}
+
+func WithPanicHandler(app *App) {
+	app.addSetupOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
+		coffin.AddPanicHandler(kernelPkg.NewPanicHandler(logger))
+		return nil
+	})
+}
+
 func WithProducerDaemon(app *App) {
 	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
 		kernel.AddFactory(stream.ProducerDaemonFactory)
@@ -287,12 +420,21 @@ func WithProducerDaemon(app *App) {
 	})
 }
 
+func WithRequestIdMessageEncoder(app *App) {
+	app.addSetupOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
+		stream.AddDefaultEncodeHandler(mon.NewMessageWithRequestIdEncoder())
+		return nil
+	})
+}
+
 func WithTracing(app *App) {
 	app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
 		tracingHook := tracing.NewLoggerErrorHook()
+		fieldsHook := tracing.NewLoggerFieldsHook(config.GetStringSlice("tracing.logger_fields", []string{}))
 
 		options := []mon.LoggerOption{
 			mon.WithHook(tracingHook),
+			mon.WithHook(fieldsHook),
 			mon.WithContextFieldsResolver(tracing.ContextTraceFieldsResolver),
 		}
 
@@ -303,6 +445,9 @@ func WithTracing(app *App) {
 		strategy := tracing.NewTraceIdErrorWarningStrategy(logger)
 		stream.AddDefaultEncodeHandler(tracing.NewMessageWithTraceEncoder(strategy))
 
+		// the spans of all modules have to be flushed, so the tracer is the last thing to stop
+		kernelPkg.OnShutdown("tracer", tracing.ShutdownTracer, kernelPkg.StageEssential)
+
 		return nil
 	})
 }
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"github.com/applike/gosoline/pkg/kernel"
 	"github.com/applike/gosoline/pkg/mdlsub"
 	"github.com/applike/gosoline/pkg/stream"
 )
@@ -23,3 +24,11 @@ func RunMdlSubscriber(transformers mdlsub.TransformerMapTypeVersionFactories) {
 
 	app.Run()
 }
+
+// RunTask runs the module as a task with the default application setup and exits once it is done. If the task
+// fails, the application exits with code 1.
+func RunTask(task kernel.ModuleFactory, options ...Option) {
+	app := Default(options...)
+	app.Add("task", task, kernel.ModuleType(kernel.TypeTask))
+	app.Run()
+}
@@ -0,0 +1,78 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"io"
+)
+
+//go:generate mockery --name Uploader
+type Uploader interface {
+	BucketName() string
+	// Upload streams the body to the given key using a multipart upload. In contrast to Store.Write the body
+	// does not have to be seekable and is never buffered completely in memory.
+	Upload(ctx context.Context, key string, contentType string, body io.Reader) error
+}
+
+type s3Uploader struct {
+	logger   mon.Logger
+	uploader s3manageriface.UploaderAPI
+
+	bucket *string
+	prefix *string
+}
+
+func NewUploader(config cfg.Config, logger mon.Logger, name string) *s3Uploader {
+	client := ProvideS3Client(config)
+	uploader := s3manager.NewUploaderWithClient(client)
+
+	var settings Settings
+	key := fmt.Sprintf("blobstore.%s", name)
+	config.UnmarshalKey(key, &settings)
+	settings.AppId.PadFromConfig(config)
+
+	if settings.Bucket == "" {
+		settings.Bucket = fmt.Sprintf("%s-%s-%s", settings.Project, settings.Environment, settings.Family)
+	}
+
+	return NewUploaderWithInterfaces(logger, uploader, settings)
+}
+
+func NewUploaderWithInterfaces(logger mon.Logger, uploader s3manageriface.UploaderAPI, settings Settings) *s3Uploader {
+	return &s3Uploader{
+		logger:   logger,
+		uploader: uploader,
+		bucket:   mdl.String(settings.Bucket),
+		prefix:   mdl.String(settings.Prefix),
+	}
+}
+
+func (u *s3Uploader) BucketName() string {
+	return *u.bucket
+}
+
+func (u *s3Uploader) Upload(ctx context.Context, key string, contentType string, body io.Reader) error {
+	fullKey := getFullKey(u.prefix, mdl.String(key))
+
+	input := &s3manager.UploadInput{
+		Bucket: u.bucket,
+		Key:    aws.String(fullKey),
+		Body:   body,
+	}
+
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	if _, err := u.uploader.UploadWithContext(ctx, input); err != nil {
+		return fmt.Errorf("can not upload %s to bucket %s: %w", fullKey, *u.bucket, err)
+	}
+
+	return nil
+}
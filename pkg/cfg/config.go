@@ -36,6 +36,7 @@ type Config interface {
 	IsSet(string) bool
 	UnmarshalDefaults(val interface{}, additionalDefaults ...UnmarshalDefaults)
 	UnmarshalKey(key string, val interface{}, additionalDefaults ...UnmarshalDefaults)
+	UnmarshalKeyStrict(key string, val interface{}, additionalDefaults ...UnmarshalDefaults) error
 }
 
 //go:generate mockery -name GosoConf
@@ -46,9 +47,13 @@ type GosoConf interface {
 
 type config struct {
 	lookupEnv      LookupEnv
+	decrypters     map[string]Decrypter
 	errorHandlers  []ErrorHandler
+	files          []string
 	sanitizers     []Sanitizer
+	sensitiveKeys  map[string]bool
 	settings       *mapx.MapX
+	usage          *configUsage
 	envKeyPrefix   string
 	envKeyReplacer *strings.Replacer
 }
@@ -63,9 +68,12 @@ func New() GosoConf {
 func NewWithInterfaces(lookupEnv LookupEnv) GosoConf {
 	cfg := &config{
 		lookupEnv:     lookupEnv,
+		decrypters:    make(map[string]Decrypter),
 		errorHandlers: []ErrorHandler{defaultErrorHandler},
 		sanitizers:    make([]Sanitizer, 0),
+		sensitiveKeys: make(map[string]bool),
 		settings:      mapx.NewMapX(),
+		usage:         newConfigUsage(),
 	}
 
 	return cfg
@@ -360,7 +368,7 @@ func (c *config) augmentString(str string) string {
 		str = strings.Replace(str, m[0], replace, -1)
 	}
 
-	return str
+	return c.decrypt(str)
 }
 
 func (c *config) err(err error, msg string, args ...interface{}) {
@@ -441,11 +449,14 @@ func (c *config) isSet(key string) bool {
 }
 
 func (c *config) keyCheck(key string, defaults int) bool {
+	c.usage.markRead(key)
+
 	if c.isSet(key) {
 		return true
 	}
 
 	if defaults > 0 {
+		c.usage.markDefaulted(key)
 		return false
 	}
 
@@ -547,6 +558,22 @@ func (c *config) resolveEnvKey(prefix string, key string) string {
 	return strings.ToUpper(key)
 }
 
+func (c *config) markStructUsage(key string, zeroSettings *mapx.MapX, defaults *mapx.MapX) {
+	for _, field := range usageLeafKeys(key, zeroSettings.Msi()) {
+		c.usage.markRead(field)
+	}
+
+	if len(defaults.Msi()) == 0 {
+		return
+	}
+
+	for _, field := range usageLeafKeys(key, defaults.Msi()) {
+		if !c.isSet(field) {
+			c.usage.markDefaulted(field)
+		}
+	}
+}
+
 func (c *config) unmarshalMap(key string, output interface{}, defaults []UnmarshalDefaults) {
 	names := c.GetStringMap(key)
 	m, err := refl.MapOf(output)
@@ -616,6 +643,8 @@ func (c *config) unmarshalStruct(key string, output interface{}, additionalDefau
 		def(c, finalSettings)
 	}
 
+	c.markStructUsage(key, zeroSettings, defaults)
+
 	if c.settings.Has(key) {
 		settings, err := c.settings.Get(key).Map()
 
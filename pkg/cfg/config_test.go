@@ -500,6 +500,64 @@ func (s *ConfigTestSuite) TestConfig_UnmarshalKeyValidation() {
 	s.EqualError(cfgErr, "2 errors occurred:\n\t* the setting Foo with value bar does not match its requirement\n\t* the setting A with value 0 does not match its requirement\n\n")
 }
 
+func (s *ConfigTestSuite) TestConfig_UnmarshalKeyStrict() {
+	type configItem struct {
+		Name string `cfg:"name" validate:"required"`
+	}
+
+	type configMap struct {
+		Foo    string `cfg:"foo" validate:"oneof=baz"`
+		Nested struct {
+			A int `cfg:"a" validate:"gt=3"`
+		} `cfg:"nested"`
+		Items []configItem `cfg:"items"`
+	}
+
+	s.setupConfigValues(map[string]interface{}{
+		"key": map[string]interface{}{
+			"foo":  "bar",
+			"fooo": "baz",
+			"nested": map[string]interface{}{
+				"a": 1,
+				"b": 2,
+			},
+			"items": []interface{}{
+				map[string]interface{}{
+					"name": "item",
+					"size": 3,
+				},
+			},
+		},
+	})
+
+	cm := configMap{}
+	err := s.config.UnmarshalKeyStrict("key", &cm)
+
+	s.EqualError(err, "5 errors occurred:\n"+
+		"\t* the setting key.fooo is unknown\n"+
+		"\t* the setting key.items[0].size is unknown\n"+
+		"\t* the setting key.nested.b is unknown\n"+
+		"\t* validation failed for key: key: the setting Foo with value bar does not match its requirement\n"+
+		"\t* validation failed for key: key: the setting A with value 1 does not match its requirement\n\n")
+	s.Equal("bar", cm.Foo)
+}
+
+func (s *ConfigTestSuite) TestConfig_UnmarshalKeyStrictValid() {
+	type configMap struct {
+		Foo string `cfg:"foo" default:"baz" validate:"oneof=baz"`
+	}
+
+	s.setupConfigValues(map[string]interface{}{
+		"key": map[string]interface{}{},
+	})
+
+	cm := configMap{}
+	err := s.config.UnmarshalKeyStrict("key", &cm)
+
+	s.NoError(err)
+	s.Equal("baz", cm.Foo)
+}
+
 func (s *ConfigTestSuite) TestConfig_UnmarshalKeyWithDefaultsFromKey() {
 	type ConfigNested struct {
 		I int  `cfg:"i" default:"1"`
@@ -9,6 +9,8 @@ import (
 	"strings"
 )
 
+const redactedValue = "***"
+
 func DebugConfig(config Config, logger Logger) error {
 	settings := config.AllSettings()
 	flattened, err := flatten.Flatten(settings, "", flatten.DotStyle)
@@ -23,6 +25,12 @@ func DebugConfig(config Config, logger Logger) error {
 
 	for i, key := range keys {
 		hashValues[i] = fmt.Sprintf("%v=%v", key, flattened[key])
+
+		if IsEncryptedValue(flattened[key]) || IsSensitiveKey(config, key) {
+			logger.Infof("cfg %s=%s", key, redactedValue)
+			continue
+		}
+
 		logger.Infof("cfg %s", hashValues[i])
 	}
 
@@ -33,3 +41,20 @@ func DebugConfig(config Config, logger Logger) error {
 
 	return nil
 }
+
+// IsSensitiveKey reports if the value of the key or of one of its parents has been set by WithSensitiveSetting
+func IsSensitiveKey(cfg Config, key string) bool {
+	c, ok := cfg.(*config)
+
+	if !ok {
+		return false
+	}
+
+	for sensitiveKey := range c.sensitiveKeys {
+		if key == sensitiveKey || strings.HasPrefix(key, sensitiveKey+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,46 @@
+package cfg
+
+import (
+	"strings"
+)
+
+// EncryptedValuePrefix marks encrypted config values, followed by the scheme of the Decrypter, like enc:kms:<ciphertext>
+const EncryptedValuePrefix = "enc:"
+
+//go:generate mockery -name Decrypter
+type Decrypter interface {
+	Decrypt(ciphertext string) (string, error)
+}
+
+// IsEncryptedValue reports if the value has to be decrypted before it can be used. Such values should be hidden
+// in config dumps, even though the decrypted value is never written back into the settings.
+func IsEncryptedValue(value interface{}) bool {
+	str, ok := value.(string)
+
+	return ok && strings.HasPrefix(str, EncryptedValuePrefix)
+}
+
+func (c *config) decrypt(str string) string {
+	if !strings.HasPrefix(str, EncryptedValuePrefix) {
+		return str
+	}
+
+	for scheme, decrypter := range c.decrypters {
+		prefix := EncryptedValuePrefix + scheme + ":"
+
+		if !strings.HasPrefix(str, prefix) {
+			continue
+		}
+
+		plaintext, err := decrypter.Decrypt(strings.TrimPrefix(str, prefix))
+
+		if err != nil {
+			c.err(err, "can not decrypt config value of scheme %s", scheme)
+			return str
+		}
+
+		return plaintext
+	}
+
+	return str
+}
@@ -2,5 +2,6 @@ package cfg
 
 type Logger interface {
 	Infof(msg string, args ...interface{})
+	Warnf(msg string, args ...interface{})
 	Errorf(err error, msg string, args ...interface{})
 }
@@ -355,3 +355,24 @@ func (_m *GosoConf) UnmarshalKey(key string, val interface{}, additionalDefaults
 	_ca = append(_ca, _va...)
 	_m.Called(_ca...)
 }
+
+// UnmarshalKeyStrict provides a mock function with given fields: key, val, additionalDefaults
+func (_m *GosoConf) UnmarshalKeyStrict(key string, val interface{}, additionalDefaults ...cfg.UnmarshalDefaults) error {
+	_va := make([]interface{}, len(additionalDefaults))
+	for _i := range additionalDefaults {
+		_va[_i] = additionalDefaults[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, key, val)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, interface{}, ...cfg.UnmarshalDefaults) error); ok {
+		r0 = rf(key, val, additionalDefaults...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -14,6 +14,14 @@ func WithConfigFile(filePath string, fileType string) Option {
 	}
 }
 
+// WithConfigFileLayers reads the base config file followed by the optional overlays for the env and for local
+// development next to it, e.g. config.dist.yml, config.dist.prod.yml and config.dist.local.yml
+func WithConfigFileLayers(filePath string, fileType string) Option {
+	return func(cfg *config) error {
+		return readConfigFileLayers(cfg, filePath, fileType)
+	}
+}
+
 func WithConfigFileFlag(flagName string) Option {
 	return func(cfg *config) error {
 		flags := flag.NewFlagSet("cfg", flag.ContinueOnError)
@@ -41,6 +49,30 @@ func WithConfigSetting(key string, settings interface{}, mergeOptions ...MergeOp
 	}
 }
 
+// WithSensitiveSetting merges the value like WithConfigSetting and marks the key as sensitive, so the value is redacted
+// whenever the config is dumped.
+func WithSensitiveSetting(key string, value interface{}) Option {
+	return func(cfg *config) error {
+		if err := cfg.merge(key, value); err != nil {
+			return err
+		}
+
+		cfg.sensitiveKeys[key] = true
+
+		return nil
+	}
+}
+
+// WithDecrypter decrypts values of the form enc:<scheme>:<ciphertext> whenever they are read. The decrypted value is
+// only handed to the reader and never stored in the settings.
+func WithDecrypter(scheme string, decrypter Decrypter) Option {
+	return func(cfg *config) error {
+		cfg.decrypters[scheme] = decrypter
+
+		return nil
+	}
+}
+
 func WithEnvKeyPrefix(prefix string) Option {
 	return func(cfg *config) error {
 		cfg.envKeyPrefix = prefix
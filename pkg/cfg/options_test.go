@@ -1,12 +1,29 @@
 package cfg_test
 
 import (
+	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mapx"
 	"github.com/stretchr/testify/suite"
 	"testing"
 )
 
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Infof(msg string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(msg, args...))
+}
+
+func (l *recordingLogger) Warnf(msg string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(msg, args...))
+}
+
+func (l *recordingLogger) Errorf(_ error, msg string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(msg, args...))
+}
+
 type OptionsTestSuite struct {
 	suite.Suite
 	config cfg.GosoConf
@@ -77,6 +94,65 @@ func (s *OptionsTestSuite) TestWithConfigSetting() {
 	s.Equal(expectedMsi, actual)
 }
 
+func (s *OptionsTestSuite) TestWithConfigFileLayers() {
+	s.apply(cfg.WithConfigFileLayers("./testdata/layers/config.dist.yml", "yml"))
+
+	s.Equal("env", s.config.GetString("name"))
+	s.Equal("local", s.config.GetString("region"))
+	s.False(s.config.IsSet("include"))
+
+	s.Equal(map[string]interface{}{
+		"timeout": "1s",
+		"retries": 5,
+	}, s.config.GetStringMap("clients.a"))
+	s.Equal(map[string]interface{}{
+		"timeout": "1s",
+		"retries": 3,
+	}, s.config.GetStringMap("clients.b"))
+
+	s.Equal([]string{
+		"testdata/layers/includes/defaults.yml",
+		"./testdata/layers/config.dist.yml",
+		"./testdata/layers/config.dist.test.yml",
+		"./testdata/layers/config.dist.local.yml",
+	}, cfg.ConfigFiles(s.config))
+}
+
+func (s *OptionsTestSuite) TestWithConfigFileIncludeCycle() {
+	err := s.config.Option(cfg.WithConfigFile("./testdata/layers/cycle.yml", "yml"))
+	s.EqualError(err, "config file testdata/layers/cycle.yml includes itself")
+}
+
+func (s *OptionsTestSuite) TestWithConfigFileProfiles() {
+	s.apply(cfg.WithConfigFile("./testdata/profiles.yml", "yml"))
+
+	s.Equal(80, s.config.GetInt("api.port"))
+	s.Equal("2s", s.config.GetString("api.timeout"))
+	s.False(s.config.IsSet("profiles"))
+}
+
+func (s *OptionsTestSuite) TestWithSensitiveSetting() {
+	s.apply(cfg.WithConfigMap(map[string]interface{}{
+		"plain": "value",
+	}))
+	s.apply(cfg.WithSensitiveSetting("db.default.uri", map[string]interface{}{
+		"user": "gosoline",
+	}))
+
+	s.Equal("gosoline", s.config.GetString("db.default.uri.user"))
+	s.True(cfg.IsSensitiveKey(s.config, "db.default.uri"))
+	s.True(cfg.IsSensitiveKey(s.config, "db.default.uri.user"))
+	s.False(cfg.IsSensitiveKey(s.config, "db.default"))
+	s.False(cfg.IsSensitiveKey(s.config, "db.default.uri_pool"))
+
+	logger := &recordingLogger{}
+	s.NoError(cfg.DebugConfig(s.config, logger))
+
+	s.Contains(logger.lines, "cfg plain=value")
+	s.Contains(logger.lines, "cfg db.default.uri.user=***")
+	s.NotContains(fmt.Sprint(logger.lines), "gosoline")
+}
+
 func TestOptionsTestSuite(t *testing.T) {
 	suite.Run(t, new(OptionsTestSuite))
 }
@@ -1,16 +1,60 @@
 package cfg
 
 import (
+	"fmt"
 	"github.com/applike/gosoline/pkg/encoding/yaml"
 	"github.com/pkg/errors"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
+// configProfilesKey lists blocks of settings which are merged after the settings of the file if all of their
+// conditions match the config, like
+//
+//	profiles:
+//	  - when: { env: [staging, prod] }
+//	    settings: { api: { port: 80 } }
+const configProfilesKey = "profiles"
+
+// configIncludeKey lists further config files which are read before the settings of the including file. Relative
+// paths are resolved against the directory of the including file.
+const configIncludeKey = "include"
+
+// ConfigFiles returns the config files in the order they have been merged into the config
+func ConfigFiles(cfg Config) []string {
+	c, ok := cfg.(*config)
+
+	if !ok {
+		return []string{}
+	}
+
+	return append([]string{}, c.files...)
+}
+
 func readConfigFromFile(cfg *config, filePath string, fileType string) error {
 	if filePath == "" {
 		return nil
 	}
 
+	return readConfigFile(cfg, filePath, fileType, map[string]bool{})
+}
+
+func readConfigFile(cfg *config, filePath string, fileType string, including map[string]bool) error {
+	absPath, err := filepath.Abs(filePath)
+
+	if err != nil {
+		return errors.Wrapf(err, "can not resolve path of config file %s", filePath)
+	}
+
+	if including[absPath] {
+		return fmt.Errorf("config file %s includes itself", filePath)
+	}
+
+	including[absPath] = true
+	defer delete(including, absPath)
+
 	bytes, err := ioutil.ReadFile(filePath)
 
 	if err != nil {
@@ -24,5 +68,164 @@ func readConfigFromFile(cfg *config, filePath string, fileType string) error {
 		return errors.Wrapf(err, "can not unmarshal config file %s", filePath)
 	}
 
-	return cfg.mergeMsi(".", settings)
+	includes, err := readConfigIncludes(settings)
+
+	if err != nil {
+		return errors.Wrapf(err, "can not read includes of config file %s", filePath)
+	}
+
+	profiles, err := readConfigProfiles(settings)
+
+	if err != nil {
+		return errors.Wrapf(err, "can not read profiles of config file %s", filePath)
+	}
+
+	delete(settings, configIncludeKey)
+	delete(settings, configProfilesKey)
+
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(filePath), include)
+		}
+
+		if err := readConfigFile(cfg, include, fileType, including); err != nil {
+			return err
+		}
+	}
+
+	cfg.files = append(cfg.files, filePath)
+
+	if err := cfg.mergeMsi(".", settings); err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		if !profile.matches(cfg) {
+			continue
+		}
+
+		if err := cfg.mergeMsi(".", profile.Settings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readConfigIncludes(settings map[string]interface{}) ([]string, error) {
+	switch includes := settings[configIncludeKey].(type) {
+	case nil:
+		return []string{}, nil
+	case string:
+		return []string{includes}, nil
+	case []interface{}:
+		paths := make([]string, len(includes))
+
+		for i, include := range includes {
+			path, ok := include.(string)
+
+			if !ok {
+				return nil, fmt.Errorf("include %v is not a string", include)
+			}
+
+			paths[i] = path
+		}
+
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("include has to be a string or a list of strings but is %T", includes)
+	}
+}
+
+type configProfile struct {
+	When     map[string]interface{}
+	Settings map[string]interface{}
+}
+
+// matches reports if every condition matches the value of its key. A condition with a list of values matches any
+// of them.
+func (p configProfile) matches(cfg *config) bool {
+	for key, condition := range p.When {
+		value := cfg.GetString(key, "")
+		candidates, ok := condition.([]interface{})
+
+		if !ok {
+			candidates = []interface{}{condition}
+		}
+
+		matched := false
+
+		for _, candidate := range candidates {
+			if fmt.Sprint(candidate) == value {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func readConfigProfiles(settings map[string]interface{}) ([]configProfile, error) {
+	if settings[configProfilesKey] == nil {
+		return []configProfile{}, nil
+	}
+
+	items, ok := settings[configProfilesKey].([]interface{})
+
+	if !ok {
+		return nil, fmt.Errorf("profiles have to be a list but are %T", settings[configProfilesKey])
+	}
+
+	profiles := make([]configProfile, len(items))
+
+	for i, item := range items {
+		profile, ok := item.(map[string]interface{})
+
+		if !ok {
+			return nil, fmt.Errorf("profile %d has to be a map but is %T", i, item)
+		}
+
+		if profiles[i].When, ok = profile["when"].(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("profile %d needs a map of conditions at when", i)
+		}
+
+		if profiles[i].Settings, ok = profile["settings"].(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("profile %d needs a map of settings at settings", i)
+		}
+	}
+
+	return profiles, nil
+}
+
+func readConfigFileLayers(cfg *config, filePath string, fileType string) error {
+	if err := readConfigFromFile(cfg, filePath, fileType); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(filePath)
+	base := strings.TrimSuffix(filePath, ext)
+	overlays := []string{"local"}
+
+	if env := cfg.GetString("env", ""); env != "" && env != "local" {
+		overlays = []string{env, "local"}
+	}
+
+	for _, overlay := range overlays {
+		overlayPath := fmt.Sprintf("%s.%s%s", base, overlay, ext)
+
+		if _, err := os.Stat(overlayPath); os.IsNotExist(err) {
+			continue
+		}
+
+		if err := readConfigFromFile(cfg, overlayPath, fileType); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
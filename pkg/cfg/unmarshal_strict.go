@@ -0,0 +1,151 @@
+package cfg
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/refl"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// UnmarshalKeyStrict unmarshals the settings of the key into a struct like UnmarshalKey. Instead of handing
+// problems to the error handlers, settings without a matching field and all failed validations are collected
+// and returned as a single error, so they can be reported at once while booting the application.
+func (c *config) UnmarshalKeyStrict(key string, output interface{}, defaults ...UnmarshalDefaults) error {
+	if !refl.IsPointerToStruct(output) {
+		return fmt.Errorf("output should be a pointer to struct but instead is %T", output)
+	}
+
+	errs := &multierror.Error{}
+
+	if c.settings.Has(key) {
+		if settings, err := c.settings.Get(key).Map(); err == nil {
+			for _, field := range unknownConfigFields(key, settings.Msi(), reflect.TypeOf(output).Elem()) {
+				errs = multierror.Append(errs, fmt.Errorf("the setting %s is unknown", field))
+			}
+		}
+	}
+
+	errorHandlers := c.errorHandlers
+	defer func() {
+		c.errorHandlers = errorHandlers
+	}()
+
+	c.errorHandlers = []ErrorHandler{func(err error, msg string, args ...interface{}) {
+		if multiErr, ok := err.(*multierror.Error); ok {
+			for _, err := range multiErr.Errors {
+				errs = multierror.Append(errs, errors.Wrapf(err, msg, args...))
+			}
+
+			return
+		}
+
+		errs = multierror.Append(errs, errors.Wrapf(err, msg, args...))
+	}}
+
+	c.unmarshalStruct(key, output, defaults)
+
+	return errs.ErrorOrNil()
+}
+
+func unknownConfigFields(prefix string, settings map[string]interface{}, st reflect.Type) []string {
+	fields := configFields(st)
+	unknown := make([]string, 0)
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := fmt.Sprintf("%s.%s", prefix, key)
+		fieldType, ok := fields[key]
+
+		if !ok {
+			unknown = append(unknown, path)
+			continue
+		}
+
+		unknown = append(unknown, unknownConfigFieldsOfValue(path, settings[key], fieldType)...)
+	}
+
+	return unknown
+}
+
+func unknownConfigFieldsOfValue(path string, value interface{}, fieldType reflect.Type) []string {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	unknown := make([]string, 0)
+
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		if fieldType == reflect.TypeOf(time.Time{}) {
+			return unknown
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			unknown = append(unknown, unknownConfigFields(path, nested, fieldType)...)
+		}
+
+	case reflect.Map:
+		nested, ok := value.(map[string]interface{})
+
+		if !ok {
+			return unknown
+		}
+
+		keys := make([]string, 0, len(nested))
+		for key := range nested {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			unknown = append(unknown, unknownConfigFieldsOfValue(fmt.Sprintf("%s.%s", path, key), nested[key], fieldType.Elem())...)
+		}
+
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+
+		if !ok {
+			return unknown
+		}
+
+		for i, item := range items {
+			unknown = append(unknown, unknownConfigFieldsOfValue(fmt.Sprintf("%s[%d]", path, i), item, fieldType.Elem())...)
+		}
+	}
+
+	return unknown
+}
+
+func configFields(st reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+
+		if len(field.PkgPath) != 0 {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, fieldType := range configFields(field.Type) {
+				fields[name] = fieldType
+			}
+
+			continue
+		}
+
+		if name, ok := field.Tag.Lookup("cfg"); ok {
+			fields[name] = field.Type
+		}
+	}
+
+	return fields
+}
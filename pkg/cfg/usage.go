@@ -0,0 +1,183 @@
+package cfg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	UsageAuditOff  = "off"
+	UsageAuditWarn = "warn"
+	UsageAuditFail = "fail"
+)
+
+type UsageAuditSettings struct {
+	// one of off, warn or fail for settings which have never been read
+	Unused string `cfg:"unused" default:"off" validate:"oneof=off warn fail"`
+	// one of off, warn or fail for defaults which have been used as the key has not been set
+	Defaults string `cfg:"defaults" default:"off" validate:"oneof=off warn fail"`
+	// keys excluded from the audit, including their nested settings
+	Ignore []string `cfg:"ignore"`
+}
+
+type UsageReport struct {
+	Unused    []string
+	Defaulted []string
+}
+
+type configUsage struct {
+	lck       sync.Mutex
+	read      map[string]bool
+	defaulted map[string]bool
+}
+
+var usageIndexRegex = regexp.MustCompile(`\[(\d+)\]`)
+
+func newConfigUsage() *configUsage {
+	return &configUsage{
+		read:      make(map[string]bool),
+		defaulted: make(map[string]bool),
+	}
+}
+
+func (u *configUsage) markRead(key string) {
+	u.lck.Lock()
+	defer u.lck.Unlock()
+
+	u.read[normalizeUsageKey(key)] = true
+}
+
+func (u *configUsage) markDefaulted(key string) {
+	u.lck.Lock()
+	defer u.lck.Unlock()
+
+	u.defaulted[normalizeUsageKey(key)] = true
+}
+
+// AuditUsage reports the settings which have not been read so far and the keys which fell back to a default
+func AuditUsage(cfg Config, ignore ...string) *UsageReport {
+	report := &UsageReport{
+		Unused:    make([]string, 0),
+		Defaulted: make([]string, 0),
+	}
+
+	c, ok := cfg.(*config)
+
+	if !ok {
+		return report
+	}
+
+	c.usage.lck.Lock()
+	defer c.usage.lck.Unlock()
+
+	for _, key := range usageLeafKeys("", c.settings.Msi()) {
+		if !isUsageKeyCovered(key, ignore) && !isUsageKeyRead(key, c.usage.read) {
+			report.Unused = append(report.Unused, key)
+		}
+	}
+
+	for key := range c.usage.defaulted {
+		if !isUsageKeyCovered(key, ignore) {
+			report.Defaulted = append(report.Defaulted, key)
+		}
+	}
+
+	sort.Strings(report.Unused)
+	sort.Strings(report.Defaulted)
+
+	return report
+}
+
+// AuditConfig logs or fails for unused settings and used defaults as configured at config_audit. It should be called
+// once all modules have been created and read their settings.
+func AuditConfig(config Config, logger Logger) error {
+	settings := &UsageAuditSettings{}
+	config.UnmarshalKey("config_audit", settings)
+
+	if settings.Unused == UsageAuditOff && settings.Defaults == UsageAuditOff {
+		return nil
+	}
+
+	report := AuditUsage(config, settings.Ignore...)
+	failed := make([]string, 0)
+
+	audit := func(mode string, keys []string, msg string) {
+		if mode == UsageAuditOff {
+			return
+		}
+
+		for _, key := range keys {
+			logger.Warnf(msg, key)
+		}
+
+		if mode == UsageAuditFail && len(keys) > 0 {
+			failed = append(failed, keys...)
+		}
+	}
+
+	audit(settings.Unused, report.Unused, "cfg %s is set but has never been read")
+	audit(settings.Defaults, report.Defaulted, "cfg %s is not set, the default has been used")
+
+	if len(failed) > 0 {
+		return fmt.Errorf("config audit failed for the keys %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+func usageLeafKeys(prefix string, value interface{}) []string {
+	settings, ok := value.(map[string]interface{})
+
+	if !ok || len(settings) == 0 {
+		return []string{prefix}
+	}
+
+	keys := make([]string, 0, len(settings))
+
+	for key, nested := range settings {
+		// merging an empty map into the root of a mapx leaves an empty key behind which is no setting
+		if key == "" {
+			continue
+		}
+
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		keys = append(keys, usageLeafKeys(key, nested)...)
+	}
+
+	return keys
+}
+
+// isUsageKeyRead reports if the key, one of its parents or one of its children has been read
+func isUsageKeyRead(key string, read map[string]bool) bool {
+	if read[key] {
+		return true
+	}
+
+	for readKey := range read {
+		if strings.HasPrefix(key, readKey+".") || strings.HasPrefix(readKey, key+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isUsageKeyCovered(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeUsageKey(key string) string {
+	return usageIndexRegex.ReplaceAllString(key, ".$1")
+}
@@ -0,0 +1,64 @@
+package cfg_test
+
+import (
+	"github.com/applike/gosoline/pkg/cfg"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type usageSettings struct {
+	AggregationSize int    `cfg:"aggregation_size" default:"10"`
+	Name            string `cfg:"name"`
+}
+
+func buildUsageConfig(t *testing.T, audit map[string]interface{}) cfg.GosoConf {
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"app_name": "usage-test",
+		"consumer": map[string]interface{}{
+			"agregation_size": 20,
+			"name":            "consumer",
+		},
+		"unused":       "value",
+		"config_audit": audit,
+	}))
+	assert.NoError(t, err)
+
+	return config
+}
+
+func TestAuditUsage(t *testing.T) {
+	config := buildUsageConfig(t, map[string]interface{}{})
+
+	settings := &usageSettings{}
+	config.UnmarshalKey("consumer", settings)
+	config.GetString("app_name")
+	config.GetInt("retries", 3)
+
+	report := cfg.AuditUsage(config, "config_audit")
+
+	assert.Equal(t, []string{"consumer.agregation_size", "unused"}, report.Unused)
+	assert.Equal(t, []string{"consumer.aggregation_size", "retries"}, report.Defaulted)
+}
+
+func TestAuditConfig(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+
+	config := buildUsageConfig(t, map[string]interface{}{
+		"unused": "fail",
+		"ignore": []interface{}{"unused"},
+	})
+	config.UnmarshalKey("consumer", &usageSettings{})
+	config.GetString("app_name")
+
+	err := cfg.AuditConfig(config, logger)
+	assert.EqualError(t, err, "config audit failed for the keys consumer.agregation_size")
+
+	config = buildUsageConfig(t, map[string]interface{}{
+		"unused": "warn",
+	})
+
+	err = cfg.AuditConfig(config, logger)
+	assert.NoError(t, err)
+}
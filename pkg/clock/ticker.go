@@ -1,6 +1,7 @@
 package clock
 
 import (
+	"sync"
 	"time"
 )
 
@@ -38,6 +39,73 @@ func (t *realTicker) Tick() <-chan time.Time {
 	return t.ticker.C
 }
 
+// NewTicker returns a ticker driven by the clock Provider. With a fake clock as provider, the ticker ticks when the
+// fake clock is advanced by the duration of the ticker, so tests don't have to sleep.
+func NewTicker(duration time.Duration) Ticker {
+	return NewClockTicker(Provider, duration)
+}
+
+// NewClockTicker returns a ticker driven by the clock, a real clock uses a real ticker
+func NewClockTicker(clock Clock, duration time.Duration) Ticker {
+	if _, ok := clock.(realClock); ok {
+		return NewRealTicker(duration)
+	}
+
+	t := &clockTicker{
+		clock:    clock,
+		duration: duration,
+		ch:       make(chan time.Time, 1),
+		reset:    make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+
+	go t.run()
+
+	return t
+}
+
+type clockTicker struct {
+	clock    Clock
+	duration time.Duration
+	ch       chan time.Time
+	reset    chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *clockTicker) run() {
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-t.reset:
+		case now := <-t.clock.After(t.duration):
+			// drop the tick if the last one wasn't received yet, like a real ticker
+			select {
+			case t.ch <- now:
+			default:
+			}
+		}
+	}
+}
+
+func (t *clockTicker) Reset() {
+	select {
+	case t.reset <- struct{}{}:
+	case <-t.stop:
+	}
+}
+
+func (t *clockTicker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+func (t *clockTicker) Tick() <-chan time.Time {
+	return t.ch
+}
+
 type FakeTicker struct {
 	ch chan time.Time
 }
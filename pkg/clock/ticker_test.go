@@ -63,3 +63,47 @@ func TestRealTicker_Reset_DuringTick(t *testing.T) {
 	// wait a bit for all routines to exit
 	time.Sleep(time.Millisecond * 10)
 }
+
+func TestClockTicker_Tick(t *testing.T) {
+	fakeClock := clock.NewFakeClock()
+	ticker := clock.NewClockTicker(fakeClock, time.Minute)
+	defer ticker.Stop()
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Second * 59)
+
+	select {
+	case <-ticker.Tick():
+		assert.Fail(t, "there should be no tick before the duration passed")
+	case <-time.After(time.Millisecond * 10):
+		// nop
+	}
+
+	fakeClock.Advance(time.Second)
+	tick := <-ticker.Tick()
+
+	assert.Equal(t, fakeClock.Now(), tick)
+}
+
+func TestClockTicker_Reset(t *testing.T) {
+	fakeClock := clock.NewFakeClock()
+	ticker := clock.NewClockTicker(fakeClock, time.Minute)
+	defer ticker.Stop()
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Second * 30)
+	ticker.Reset()
+
+	fakeClock.BlockUntil(2)
+	fakeClock.Advance(time.Second * 30)
+
+	select {
+	case <-ticker.Tick():
+		assert.Fail(t, "there should be no tick after a reset before the duration passed")
+	case <-time.After(time.Millisecond * 10):
+		// nop
+	}
+
+	fakeClock.Advance(time.Second * 30)
+	<-ticker.Tick()
+}
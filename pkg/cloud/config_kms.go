@@ -0,0 +1,82 @@
+package cloud
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"sync"
+)
+
+// ConfigEncryptionSchemeKms is the scheme of config values encrypted with KMS, like enc:kms:<base64 ciphertext>
+const ConfigEncryptionSchemeKms = "kms"
+
+type ConfigKmsSettings struct {
+	// id or alias of the key the values have been encrypted with, only required for asymmetric keys
+	KeyId string `cfg:"key_id"`
+}
+
+type KmsDecrypter struct {
+	client kmsiface.KMSAPI
+	keyId  string
+
+	lck   sync.Mutex
+	cache map[string]string
+}
+
+func NewKmsDecrypter(config cfg.Config) *KmsDecrypter {
+	settings := &ConfigKmsSettings{}
+	config.UnmarshalKey("config_kms", settings)
+
+	kmsConfig := ConfigTemplate
+	kmsConfig.WithEndpoint(config.GetString("aws_kms_endpoint", ""))
+	kmsConfig.WithMaxRetries(config.GetInt("aws_sdk_retries", 10))
+
+	client := kms.New(session.Must(session.NewSession(&kmsConfig)))
+
+	return NewKmsDecrypterWithInterfaces(client, settings.KeyId)
+}
+
+func NewKmsDecrypterWithInterfaces(client kmsiface.KMSAPI, keyId string) *KmsDecrypter {
+	return &KmsDecrypter{
+		client: client,
+		keyId:  keyId,
+		cache:  make(map[string]string),
+	}
+}
+
+// Decrypt decrypts the base64 encoded ciphertext with KMS. Decrypted values are cached, so every value is only
+// sent to KMS once.
+func (d *KmsDecrypter) Decrypt(ciphertext string) (string, error) {
+	d.lck.Lock()
+	defer d.lck.Unlock()
+
+	if plaintext, ok := d.cache[ciphertext]; ok {
+		return plaintext, nil
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("can not decode kms ciphertext: %w", err)
+	}
+
+	input := &kms.DecryptInput{
+		CiphertextBlob: blob,
+	}
+
+	if d.keyId != "" {
+		input.KeyId = aws.String(d.keyId)
+	}
+
+	out, err := d.client.Decrypt(input)
+	if err != nil {
+		return "", fmt.Errorf("can not decrypt kms ciphertext: %w", err)
+	}
+
+	d.cache[ciphertext] = string(out.Plaintext)
+
+	return d.cache[ciphertext], nil
+}
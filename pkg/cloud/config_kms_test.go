@@ -0,0 +1,55 @@
+package cloud_test
+
+import (
+	"encoding/base64"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/cloud"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type kmsFake struct {
+	kmsiface.KMSAPI
+	calls int
+}
+
+func (f *kmsFake) Decrypt(input *kms.DecryptInput) (*kms.DecryptOutput, error) {
+	f.calls++
+
+	return &kms.DecryptOutput{
+		KeyId:     input.KeyId,
+		Plaintext: []byte("plain-" + string(input.CiphertextBlob)),
+	}, nil
+}
+
+func TestKmsDecrypter(t *testing.T) {
+	ciphertext := base64.StdEncoding.EncodeToString([]byte("password"))
+
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"db": map[string]interface{}{
+			"password": "enc:kms:" + ciphertext,
+		},
+		"dsn": "user:{db.password}@host",
+	}))
+	assert.NoError(t, err)
+
+	client := &kmsFake{}
+	decrypter := cloud.NewKmsDecrypterWithInterfaces(client, "alias/config")
+
+	err = config.Option(cfg.WithDecrypter(cloud.ConfigEncryptionSchemeKms, decrypter))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "plain-password", config.GetString("db.password"))
+	assert.Equal(t, "user:plain-password@host", config.GetString("dsn"))
+	assert.Equal(t, 1, client.calls)
+
+	settings := config.AllSettings()
+	assert.Equal(t, "enc:kms:"+ciphertext, settings["db"].(map[string]interface{})["password"])
+	assert.True(t, cfg.IsEncryptedValue(settings["db"].(map[string]interface{})["password"]))
+
+	_, err = decrypter.Decrypt("not base64!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ConfigSecretSchemeSsm references a parameter of the SSM parameter store, like ssm:///path/to/parameter
+	ConfigSecretSchemeSsm = "ssm://"
+	// ConfigSecretSchemeSecretsManager references a secret of the secrets manager, like secretsmanager://name. A
+	// single field of a JSON secret is referenced by appending it as fragment: secretsmanager://name#password
+	ConfigSecretSchemeSecretsManager = "secretsmanager://"
+)
+
+type ConfigSecretsSettings struct {
+	// interval in which the referenced parameters and secrets are resolved again, 0 disables the refresh
+	RefreshInterval time.Duration `cfg:"refresh_interval" default:"0"`
+	// decrypt SecureString parameters with their KMS key
+	Decrypt bool `cfg:"decrypt" default:"true"`
+}
+
+func init() {
+	cfg.AddPostProcessor(32, "gosoline.cloud.config_secrets", ConfigSecretsPostProcessor)
+}
+
+type ConfigSecretResolver struct {
+	ssm            ssmiface.SSMAPI
+	secretsManager secretsmanageriface.SecretsManagerAPI
+	decrypt        bool
+}
+
+func NewConfigSecretResolver(config cfg.Config, settings *ConfigSecretsSettings) *ConfigSecretResolver {
+	ssmConfig := ConfigTemplate
+	ssmConfig.WithEndpoint(config.GetString("aws_ssm_endpoint", ""))
+	ssmConfig.WithMaxRetries(config.GetInt("aws_sdk_retries", 10))
+
+	secretsManagerConfig := ConfigTemplate
+	secretsManagerConfig.WithEndpoint(config.GetString("aws_secretsmanager_endpoint", ""))
+	secretsManagerConfig.WithMaxRetries(config.GetInt("aws_sdk_retries", 10))
+
+	ssmClient := ssm.New(session.Must(session.NewSession(&ssmConfig)))
+	secretsManagerClient := secretsmanager.New(session.Must(session.NewSession(&secretsManagerConfig)))
+
+	return NewConfigSecretResolverWithInterfaces(ssmClient, secretsManagerClient, settings.Decrypt)
+}
+
+func NewConfigSecretResolverWithInterfaces(ssm ssmiface.SSMAPI, secretsManager secretsmanageriface.SecretsManagerAPI, decrypt bool) *ConfigSecretResolver {
+	return &ConfigSecretResolver{
+		ssm:            ssm,
+		secretsManager: secretsManager,
+		decrypt:        decrypt,
+	}
+}
+
+func IsConfigSecretReference(value string) bool {
+	return strings.HasPrefix(value, ConfigSecretSchemeSsm) || strings.HasPrefix(value, ConfigSecretSchemeSecretsManager)
+}
+
+func (r *ConfigSecretResolver) Resolve(reference string) (string, error) {
+	switch {
+	case strings.HasPrefix(reference, ConfigSecretSchemeSsm):
+		return r.resolveParameter(strings.TrimPrefix(reference, ConfigSecretSchemeSsm))
+	case strings.HasPrefix(reference, ConfigSecretSchemeSecretsManager):
+		return r.resolveSecret(strings.TrimPrefix(reference, ConfigSecretSchemeSecretsManager))
+	default:
+		return "", fmt.Errorf("unknown config secret reference %s", reference)
+	}
+}
+
+func (r *ConfigSecretResolver) resolveParameter(name string) (string, error) {
+	out, err := r.ssm.GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(r.decrypt),
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("can not get ssm parameter %s: %w", name, err)
+	}
+
+	return aws.StringValue(out.Parameter.Value), nil
+}
+
+func (r *ConfigSecretResolver) resolveSecret(reference string) (string, error) {
+	name, field := reference, ""
+
+	if i := strings.Index(reference, "#"); i >= 0 {
+		name, field = reference[:i], reference[i+1:]
+	}
+
+	out, err := r.secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("can not get secret %s: %w", name, err)
+	}
+
+	value := aws.StringValue(out.SecretString)
+
+	if field == "" {
+		return value, nil
+	}
+
+	fields := make(map[string]interface{})
+
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret %s has to be a json object to read the field %s: %w", name, field, err)
+	}
+
+	fieldValue, ok := fields[field]
+
+	if !ok {
+		return "", fmt.Errorf("secret %s has no field %s", name, field)
+	}
+
+	return fmt.Sprint(fieldValue), nil
+}
+
+type ConfigSecretListener func(value string)
+
+var configSecrets = struct {
+	sync.Mutex
+	resolver   *ConfigSecretResolver
+	references map[string]string
+	values     map[string]string
+	listeners  map[string][]ConfigSecretListener
+}{
+	listeners: make(map[string][]ConfigSecretListener),
+}
+
+// AddConfigSecretListener registers a listener which is called with the new value whenever the refresh notices
+// that the parameter or secret referenced by the config key changed.
+func AddConfigSecretListener(key string, listener ConfigSecretListener) {
+	configSecrets.Lock()
+	defer configSecrets.Unlock()
+
+	configSecrets.listeners[key] = append(configSecrets.listeners[key], listener)
+}
+
+// ConfigSecretsPostProcessor replaces all config values referencing a SSM parameter or a secret of the secrets
+// manager with their current value.
+func ConfigSecretsPostProcessor(config cfg.GosoConf) (bool, error) {
+	references := FindConfigSecretReferences(config.AllSettings())
+
+	if len(references) == 0 {
+		return false, nil
+	}
+
+	settings := &ConfigSecretsSettings{}
+	config.UnmarshalKey("config_secrets", settings)
+
+	resolver := NewConfigSecretResolver(config, settings)
+
+	return ApplyConfigSecrets(config, resolver, references)
+}
+
+func ApplyConfigSecrets(config cfg.GosoConf, resolver *ConfigSecretResolver, references map[string]string) (bool, error) {
+	values := make(map[string]string, len(references))
+	options := make([]cfg.Option, 0, len(references))
+
+	for key, reference := range references {
+		value, err := resolver.Resolve(reference)
+		if err != nil {
+			return false, fmt.Errorf("can not resolve config key %s: %w", key, err)
+		}
+
+		values[key] = value
+		options = append(options, cfg.WithSensitiveSetting(key, value))
+	}
+
+	if err := config.Option(options...); err != nil {
+		return false, fmt.Errorf("can not apply resolved config secrets: %w", err)
+	}
+
+	configSecrets.Lock()
+	defer configSecrets.Unlock()
+
+	configSecrets.resolver = resolver
+	configSecrets.references = references
+	configSecrets.values = values
+
+	return true, nil
+}
+
+// FindConfigSecretReferences returns the referenced parameters and secrets by their config key
+func FindConfigSecretReferences(settings map[string]interface{}) map[string]string {
+	references := make(map[string]string)
+	findConfigSecretReferences("", settings, references)
+
+	return references
+}
+
+func findConfigSecretReferences(prefix string, settings map[string]interface{}, references map[string]string) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = fmt.Sprintf("%s.%s", prefix, key)
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			findConfigSecretReferences(key, v, references)
+		case string:
+			if IsConfigSecretReference(v) {
+				references[key] = v
+			}
+		}
+	}
+}
+
+func configSecretKeys() []string {
+	configSecrets.Lock()
+	defer configSecrets.Unlock()
+
+	keys := make([]string, 0, len(configSecrets.references))
+
+	for key := range configSecrets.references {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
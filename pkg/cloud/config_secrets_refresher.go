@@ -0,0 +1,90 @@
+package cloud
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/kernel/common"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+// ConfigSecretsRefresher resolves the referenced parameters and secrets periodically and notifies the registered
+// listeners about changed values. The config itself keeps the values resolved at boot.
+type ConfigSecretsRefresher struct {
+	logger mon.Logger
+	ticker clock.Ticker
+}
+
+// NewConfigSecretsRefresher returns false if the refresh is disabled or there are no references to refresh.
+func NewConfigSecretsRefresher(config cfg.Config, logger mon.Logger) (*ConfigSecretsRefresher, bool) {
+	settings := &ConfigSecretsSettings{}
+	config.UnmarshalKey("config_secrets", settings)
+
+	if settings.RefreshInterval <= 0 || len(configSecretKeys()) == 0 {
+		return nil, false
+	}
+
+	ticker := clock.NewTicker(settings.RefreshInterval)
+
+	return NewConfigSecretsRefresherWithInterfaces(logger, ticker), true
+}
+
+func NewConfigSecretsRefresherWithInterfaces(logger mon.Logger, ticker clock.Ticker) *ConfigSecretsRefresher {
+	return &ConfigSecretsRefresher{
+		logger: logger.WithChannel("config_secrets"),
+		ticker: ticker,
+	}
+}
+
+func (r *ConfigSecretsRefresher) GetType() string {
+	return common.TypeBackground
+}
+
+func (r *ConfigSecretsRefresher) GetStage() int {
+	return common.StageService
+}
+
+func (r *ConfigSecretsRefresher) Run(ctx context.Context) error {
+	defer r.ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.ticker.Tick():
+			r.Refresh()
+		}
+	}
+}
+
+func (r *ConfigSecretsRefresher) Refresh() {
+	for _, key := range configSecretKeys() {
+		configSecrets.Lock()
+		resolver := configSecrets.resolver
+		reference := configSecrets.references[key]
+		configSecrets.Unlock()
+
+		value, err := resolver.Resolve(reference)
+
+		if err != nil {
+			r.logger.Warnf("can not refresh config key %s: %s", key, err.Error())
+			continue
+		}
+
+		configSecrets.Lock()
+		changed := configSecrets.values[key] != value
+		configSecrets.values[key] = value
+		listeners := configSecrets.listeners[key]
+		configSecrets.Unlock()
+
+		if !changed {
+			continue
+		}
+
+		r.logger.Infof("the value of config key %s changed", key)
+
+		for _, listener := range listeners {
+			listener(value)
+		}
+	}
+}
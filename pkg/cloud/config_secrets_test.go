@@ -0,0 +1,127 @@
+package cloud_test
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/cloud"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type secretsManagerFake struct {
+	secretsmanageriface.SecretsManagerAPI
+	secrets map[string]string
+}
+
+func (f *secretsManagerFake) GetSecretValue(input *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+	secret, ok := f.secrets[aws.StringValue(input.SecretId)]
+
+	if !ok {
+		return nil, fmt.Errorf("secret not found")
+	}
+
+	return &secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String(secret),
+	}, nil
+}
+
+func TestConfigSecrets(t *testing.T) {
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"db": map[string]interface{}{
+			"default": map[string]interface{}{
+				"uri": map[string]interface{}{
+					"user":     "secretsmanager://db-credentials#user",
+					"password": "secretsmanager://db-credentials#password",
+				},
+			},
+		},
+		"api_key": "ssm:///app/api-key",
+		"plain":   "value",
+	}))
+	assert.NoError(t, err)
+
+	ssmClient := new(cloudMocks.SSMAPI)
+	ssmClient.On("GetParameter", &ssm.GetParameterInput{
+		Name:           aws.String("/app/api-key"),
+		WithDecryption: aws.Bool(true),
+	}).Return(&ssm.GetParameterOutput{
+		Parameter: &ssm.Parameter{
+			Value: aws.String("key"),
+		},
+	}, nil).Once()
+
+	secretsManager := &secretsManagerFake{
+		secrets: map[string]string{
+			"db-credentials": `{"user":"gosoline","password":"secret"}`,
+		},
+	}
+
+	references := cloud.FindConfigSecretReferences(config.AllSettings())
+	assert.Len(t, references, 3)
+	assert.Equal(t, "ssm:///app/api-key", references["api_key"])
+
+	resolver := cloud.NewConfigSecretResolverWithInterfaces(ssmClient, secretsManager, true)
+	applied, err := cloud.ApplyConfigSecrets(config, resolver, references)
+
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, "key", config.GetString("api_key"))
+	assert.Equal(t, "gosoline", config.GetString("db.default.uri.user"))
+	assert.Equal(t, "secret", config.GetString("db.default.uri.password"))
+	assert.Equal(t, "value", config.GetString("plain"))
+	assert.True(t, cfg.IsSensitiveKey(config, "api_key"))
+	assert.True(t, cfg.IsSensitiveKey(config, "db.default.uri.password"))
+	assert.False(t, cfg.IsSensitiveKey(config, "plain"))
+	ssmClient.AssertExpectations(t)
+
+	ssmClient.On("GetParameter", &ssm.GetParameterInput{
+		Name:           aws.String("/app/api-key"),
+		WithDecryption: aws.Bool(true),
+	}).Return(&ssm.GetParameterOutput{
+		Parameter: &ssm.Parameter{
+			Value: aws.String("rotated"),
+		},
+	}, nil).Once()
+
+	changed := make([]string, 0)
+	cloud.AddConfigSecretListener("api_key", func(value string) {
+		changed = append(changed, value)
+	})
+	cloud.AddConfigSecretListener("db.default.uri.password", func(value string) {
+		changed = append(changed, value)
+	})
+
+	refresher := cloud.NewConfigSecretsRefresherWithInterfaces(monMocks.NewLoggerMockedAll(), clock.NewFakeTicker())
+	refresher.Refresh()
+
+	assert.Equal(t, []string{"rotated"}, changed, "only listeners of changed values should be notified")
+	ssmClient.AssertExpectations(t)
+}
+
+func TestConfigSecretResolver_MissingField(t *testing.T) {
+	secretsManager := &secretsManagerFake{
+		secrets: map[string]string{
+			"plain": "not json",
+		},
+	}
+
+	resolver := cloud.NewConfigSecretResolverWithInterfaces(new(cloudMocks.SSMAPI), secretsManager, true)
+
+	value, err := resolver.Resolve("secretsmanager://plain")
+	assert.NoError(t, err)
+	assert.Equal(t, "not json", value)
+
+	_, err = resolver.Resolve("secretsmanager://plain#password")
+	assert.Error(t, err)
+
+	_, err = resolver.Resolve("secretsmanager://missing")
+	assert.Error(t, err)
+}
@@ -83,6 +83,8 @@ type coffin struct {
 	started int32
 	// number of terminated go routines
 	terminated int32
+	// counts the go routines for the context the coffin was created with, if any
+	tracker *Tracker
 }
 
 func New() Coffin {
@@ -103,6 +105,7 @@ func WithContext(parent context.Context) (Coffin, context.Context) {
 		tomb:       tmb,
 		started:    0,
 		terminated: 0,
+		tracker:    TrackerFromContext(parent),
 	}
 
 	return cfn, ctx
@@ -129,6 +132,12 @@ func (c *coffin) Err() (reason error) {
 }
 
 func (c *coffin) Go(f func() error) {
+	c.goTracked(c.tracker, f)
+}
+
+func (c *coffin) goTracked(tracker *Tracker, f func() error) {
+	f = tracker.track(f)
+
 	atomic.AddInt32(&c.started, 1)
 	c.tomb.Go(func() (err error) {
 		defer atomic.AddInt32(&c.terminated, 1)
@@ -136,6 +145,7 @@ func (c *coffin) Go(f func() error) {
 			panicErr := ResolveRecovery(recover())
 
 			if panicErr != nil {
+				ReportPanic(panicErr)
 				err = panicErr
 			}
 		}()
@@ -145,6 +155,12 @@ func (c *coffin) Go(f func() error) {
 }
 
 func (c *coffin) Gof(f func() error, msg string, args ...interface{}) {
+	c.gofTracked(c.tracker, f, msg, args...)
+}
+
+func (c *coffin) gofTracked(tracker *Tracker, f func() error, msg string, args ...interface{}) {
+	f = tracker.track(f)
+
 	atomic.AddInt32(&c.started, 1)
 	c.tomb.Go(func() (err error) {
 		defer atomic.AddInt32(&c.terminated, 1)
@@ -153,6 +169,7 @@ func (c *coffin) Gof(f func() error, msg string, args ...interface{}) {
 
 			if panicErr != nil {
 				err = errors.Wrapf(panicErr, msg, args...)
+				ReportPanic(err)
 			}
 		}()
 
@@ -165,17 +182,25 @@ func (c *coffin) Gof(f func() error, msg string, args ...interface{}) {
 }
 
 func (c *coffin) GoWithContext(ctx context.Context, f func(ctx context.Context) error) {
-	c.Go(func() error {
+	c.goTracked(c.contextTracker(ctx), func() error {
 		return f(ctx)
 	})
 }
 
 func (c *coffin) GoWithContextf(ctx context.Context, f func(ctx context.Context) error, msg string, args ...interface{}) {
-	c.Gof(func() error {
+	c.gofTracked(c.contextTracker(ctx), func() error {
 		return f(ctx)
 	}, msg, args...)
 }
 
+func (c *coffin) contextTracker(ctx context.Context) *Tracker {
+	if tracker := TrackerFromContext(ctx); tracker != nil {
+		return tracker
+	}
+
+	return c.tracker
+}
+
 // Kill puts the coffin in a dying state for the given reason,
 // closes the Dying channel, and sets Alive to false.
 //
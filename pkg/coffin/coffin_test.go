@@ -109,3 +109,59 @@ func TestCoffin_Wait_Empty(t *testing.T) {
 	err := cfn.Wait()
 	assert.NoError(t, err)
 }
+
+func TestCoffin_PanicHandler(t *testing.T) {
+	reported := make([]error, 0)
+	remove := coffin.AddPanicHandler(func(err error) {
+		reported = append(reported, err)
+	})
+	defer remove()
+
+	cfn := coffin.New()
+	cfn.Gof(func() error {
+		panic("panic in routine")
+	}, "routine %d", 1)
+
+	err := cfn.Wait()
+
+	assert.Error(t, err)
+	assert.Len(t, reported, 1)
+	assert.Contains(t, reported[0].Error(), "routine 1")
+	assert.Contains(t, reported[0].Error(), "panic in routine")
+
+	remove()
+	coffin.ReportPanic(fmt.Errorf("another panic"))
+
+	assert.Len(t, reported, 1, "a removed handler should not be called anymore")
+}
+
+func TestCoffin_Tracker(t *testing.T) {
+	tracker := coffin.NewTracker()
+	ctx := coffin.WithTracker(context.Background(), tracker)
+
+	release := make(chan struct{})
+	wait := func() error {
+		<-release
+
+		return nil
+	}
+
+	cfn, cfnCtx := coffin.WithContext(ctx)
+	cfn.Go(wait)
+	cfn.GoWithContext(cfnCtx, func(ctx context.Context) error {
+		return wait()
+	})
+
+	untracked := coffin.New()
+	untracked.Go(wait)
+	untracked.GoWithContext(ctx, func(ctx context.Context) error {
+		return wait()
+	})
+
+	assert.Equal(t, 3, tracker.Running())
+
+	close(release)
+	assert.NoError(t, cfn.Wait())
+	assert.NoError(t, untracked.Wait())
+	assert.Equal(t, 0, tracker.Running())
+}
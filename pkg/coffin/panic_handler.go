@@ -0,0 +1,57 @@
+package coffin
+
+import "sync"
+
+// A PanicHandler is called with the recovered error (including the stack of the
+// panic) whenever a go routine managed by a coffin panics.
+type PanicHandler func(err error)
+
+type panicHandlerEntry struct {
+	handler PanicHandler
+}
+
+var panicHandlers = struct {
+	sync.RWMutex
+	handlers []*panicHandlerEntry
+}{}
+
+// AddPanicHandler registers a handler which gets notified about every panic recovered
+// by any coffin. Handlers are called synchronously before the coffin is killed, so
+// they should not block. The returned function removes the handler again.
+func AddPanicHandler(handler PanicHandler) (remove func()) {
+	panicHandlers.Lock()
+	defer panicHandlers.Unlock()
+
+	entry := &panicHandlerEntry{
+		handler: handler,
+	}
+	panicHandlers.handlers = append(panicHandlers.handlers, entry)
+
+	return func() {
+		panicHandlers.Lock()
+		defer panicHandlers.Unlock()
+
+		for i, registered := range panicHandlers.handlers {
+			if registered != entry {
+				continue
+			}
+
+			handlers := make([]*panicHandlerEntry, 0, len(panicHandlers.handlers)-1)
+			handlers = append(handlers, panicHandlers.handlers[:i]...)
+			panicHandlers.handlers = append(handlers, panicHandlers.handlers[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// ReportPanic notifies all registered panic handlers about the given error. Use it if you
+// recover from a panic yourself but still want it to be reported like any other panic.
+func ReportPanic(err error) {
+	panicHandlers.RLock()
+	defer panicHandlers.RUnlock()
+
+	for _, entry := range panicHandlers.handlers {
+		entry.handler(err)
+	}
+}
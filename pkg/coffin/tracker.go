@@ -0,0 +1,59 @@
+package coffin
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type trackerCtxKey struct{}
+
+// A Tracker counts the running go routines of all coffins using a context it is attached to. The kernel
+// attaches a tracker to the context of every module to attribute go routines to the module.
+type Tracker struct {
+	running int32
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// WithTracker returns a copy of the context with the tracker attached. Go routines of coffins created with
+// WithContext from the returned context or started with GoWithContext using it are counted by the tracker.
+func WithTracker(ctx context.Context, tracker *Tracker) context.Context {
+	return context.WithValue(ctx, trackerCtxKey{}, tracker)
+}
+
+// TrackerFromContext returns the tracker attached to the context or nil.
+func TrackerFromContext(ctx context.Context) *Tracker {
+	if ctx == nil {
+		return nil
+	}
+
+	tracker, _ := ctx.Value(trackerCtxKey{}).(*Tracker)
+
+	return tracker
+}
+
+// Running returns the number of currently running go routines. It is safe to call this on a nil tracker.
+func (t *Tracker) Running() int {
+	if t == nil {
+		return 0
+	}
+
+	return int(atomic.LoadInt32(&t.running))
+}
+
+func (t *Tracker) track(f func() error) func() error {
+	if t == nil {
+		return f
+	}
+
+	// count the go routine right away like the coffin does with started go routines
+	atomic.AddInt32(&t.running, 1)
+
+	return func() error {
+		defer atomic.AddInt32(&t.running, -1)
+
+		return f()
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 const (
 	LeaderElectionTypeDdb    = "ddb"
+	LeaderElectionTypeRedis  = "redis"
 	LeaderElectionTypeStatic = "static"
 )
 
@@ -22,6 +23,7 @@ type LeaderElectionFactory func(config cfg.Config, logger mon.Logger, name strin
 
 var leaderElectionFactories = map[string]LeaderElectionFactory{
 	LeaderElectionTypeDdb:    NewDdbLeaderElection,
+	LeaderElectionTypeRedis:  NewRedisLeaderElection,
 	LeaderElectionTypeStatic: NewStaticLeaderElection,
 }
 
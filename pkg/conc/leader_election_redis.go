@@ -0,0 +1,100 @@
+package conc
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/redis"
+	"time"
+)
+
+// acquires the lease if nobody holds it or extends it if the member holds it already. Both happen in one script, so
+// the lease can't expire and be taken over by another member between checking and extending it.
+const redisLeaderElectionAcquireScript = `
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+
+return 0
+`
+
+// releases the lease only if the member still holds it
+const redisLeaderElectionResignScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+
+return 0
+`
+
+type RedisLeaderElectionSettings struct {
+	Client        string        `cfg:"client" default:"default"`
+	GroupId       string        `cfg:"group_id" default:"{app_name}"`
+	LeaseDuration time.Duration `cfg:"lease_duration" default:"1m"`
+}
+
+type RedisLeaderElection struct {
+	logger   mon.Logger
+	client   redis.Client
+	settings *RedisLeaderElectionSettings
+}
+
+func NewRedisLeaderElection(config cfg.Config, logger mon.Logger, name string) (LeaderElection, error) {
+	key := GetLeaderElectionConfigKey(name)
+	settings := &RedisLeaderElectionSettings{}
+	config.UnmarshalKey(key, settings)
+
+	return NewRedisLeaderElectionWithSettings(config, logger, settings)
+}
+
+func NewRedisLeaderElectionWithSettings(config cfg.Config, logger mon.Logger, settings *RedisLeaderElectionSettings) (LeaderElection, error) {
+	client, err := redis.ProvideClient(config, logger, settings.Client)
+	if err != nil {
+		return nil, fmt.Errorf("can not create redis client %s: %w", settings.Client, err)
+	}
+
+	return NewRedisLeaderElectionWithInterfaces(logger, client, settings)
+}
+
+func NewRedisLeaderElectionWithInterfaces(logger mon.Logger, client redis.Client, settings *RedisLeaderElectionSettings) (*RedisLeaderElection, error) {
+	election := &RedisLeaderElection{
+		logger:   logger,
+		client:   client,
+		settings: settings,
+	}
+
+	return election, nil
+}
+
+func (e *RedisLeaderElection) IsLeader(ctx context.Context, memberId string) (bool, error) {
+	result, err := e.client.Eval(ctx, redisLeaderElectionAcquireScript, []string{e.key()}, memberId, e.settings.LeaseDuration.Milliseconds())
+	if err != nil {
+		return false, NewLeaderElectionTransientError(err)
+	}
+
+	return result == int64(1), nil
+}
+
+func (e *RedisLeaderElection) Resign(ctx context.Context, memberId string) error {
+	result, err := e.client.Eval(ctx, redisLeaderElectionResignScript, []string{e.key()}, memberId)
+	if err != nil {
+		return fmt.Errorf("can not resign as current leader: %w", err)
+	}
+
+	// every member resigns on shutdown, so not being the leader is the normal case for most of them
+	if result != int64(1) {
+		e.logger.Debugf("not resigning as leader as we're not the current one")
+	}
+
+	return nil
+}
+
+func (e *RedisLeaderElection) key() string {
+	return fmt.Sprintf("leader-election-%s", e.settings.GroupId)
+}
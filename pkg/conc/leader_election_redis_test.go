@@ -0,0 +1,83 @@
+package conc_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/conc"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	redisMocks "github.com/applike/gosoline/pkg/redis/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RedisLeaderElectionTestCase struct {
+	suite.Suite
+
+	ctx      context.Context
+	client   *redisMocks.Client
+	election *conc.RedisLeaderElection
+}
+
+func (s *RedisLeaderElectionTestCase) SetupTest() {
+	s.ctx = context.Background()
+	s.client = new(redisMocks.Client)
+
+	var err error
+	// warnings fail the tests, e.g. a member which isn't the leader has to resign quietly
+	s.election, err = conc.NewRedisLeaderElectionWithInterfaces(monMocks.NewLoggerMockedUntilLevel(mon.Debug), s.client, &conc.RedisLeaderElectionSettings{
+		GroupId:       "test",
+		LeaseDuration: time.Minute,
+	})
+	s.NoError(err)
+}
+
+func (s *RedisLeaderElectionTestCase) TestAcquire() {
+	s.client.On("Eval", s.ctx, mock.AnythingOfType("string"), []string{"leader-election-test"}, "member", int64(60000)).Return(int64(1), nil)
+
+	isLeader, err := s.election.IsLeader(s.ctx, "member")
+	s.NoError(err)
+	s.True(isLeader)
+	s.client.AssertExpectations(s.T())
+}
+
+func (s *RedisLeaderElectionTestCase) TestOtherLeader() {
+	s.client.On("Eval", s.ctx, mock.AnythingOfType("string"), []string{"leader-election-test"}, "member", int64(60000)).Return(int64(0), nil)
+
+	isLeader, err := s.election.IsLeader(s.ctx, "member")
+	s.NoError(err)
+	s.False(isLeader)
+	s.client.AssertExpectations(s.T())
+}
+
+func (s *RedisLeaderElectionTestCase) TestAcquireError() {
+	s.client.On("Eval", s.ctx, mock.AnythingOfType("string"), []string{"leader-election-test"}, "member", int64(60000)).Return(nil, fmt.Errorf("connection refused"))
+
+	isLeader, err := s.election.IsLeader(s.ctx, "member")
+	s.Error(err)
+	s.True(conc.IsLeaderElectionTransientError(err))
+	s.False(isLeader)
+	s.client.AssertExpectations(s.T())
+}
+
+func (s *RedisLeaderElectionTestCase) TestResign() {
+	s.client.On("Eval", s.ctx, mock.AnythingOfType("string"), []string{"leader-election-test"}, "member").Return(int64(1), nil)
+
+	err := s.election.Resign(s.ctx, "member")
+	s.NoError(err)
+	s.client.AssertExpectations(s.T())
+}
+
+func (s *RedisLeaderElectionTestCase) TestResignNotLeader() {
+	s.client.On("Eval", s.ctx, mock.AnythingOfType("string"), []string{"leader-election-test"}, "member").Return(int64(0), nil)
+
+	err := s.election.Resign(s.ctx, "member")
+	s.NoError(err)
+	s.client.AssertExpectations(s.T())
+}
+
+func TestRedisLeaderElection(t *testing.T) {
+	suite.Run(t, new(RedisLeaderElectionTestCase))
+}
@@ -0,0 +1,257 @@
+package conc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/hashicorp/go-multierror"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// number of tasks waiting for a free worker
+	metricNameWorkerPoolQueueDepth = "WorkerPoolQueueDepth"
+	// share of the workers busy with a task
+	metricNameWorkerPoolUtilization = "WorkerPoolUtilization"
+	// number of finished tasks
+	metricNameWorkerPoolTasks = "WorkerPoolTasks"
+	// number of tasks which returned an error or panicked
+	metricNameWorkerPoolTaskFailures = "WorkerPoolTaskFailures"
+	// duration of a single task
+	metricNameWorkerPoolTaskDuration = "WorkerPoolTaskDuration"
+)
+
+// you tried to submit a task after waiting for the pool
+var ErrWorkerPoolClosed = errors.New("worker pool is closed")
+
+// A Task is executed by one of the workers of a pool. The context is canceled once the context of the pool is.
+type Task func(ctx context.Context) error
+
+//go:generate mockery -name WorkerPool
+type WorkerPool interface {
+	// Submit queues the task for execution. If the queue is full, Submit blocks until a worker picks
+	// up a task or the context of the pool is canceled. Fails with ErrWorkerPoolClosed after Wait was called.
+	Submit(task Task) error
+	// Wait closes the pool and blocks until all submitted tasks are done. The errors of all
+	// failed tasks are returned combined, a panicking task counts as failed.
+	Wait() error
+}
+
+type WorkerPoolSettings struct {
+	// name of the pool used as metric dimension
+	Name string
+	// number of tasks executed in parallel
+	Workers int
+	// number of tasks which can be submitted without blocking while all workers are busy
+	QueueSize int
+	// interval in which the queue depth and utilization are written, defaults to 10 seconds
+	MetricInterval time.Duration
+}
+
+type workerPool struct {
+	logger   mon.Logger
+	metric   mon.MetricWriter
+	settings *WorkerPoolSettings
+
+	ctx     context.Context
+	cfn     coffin.Coffin
+	tasks   chan Task
+	lck     sync.RWMutex
+	closed  bool
+	stopped SignalOnce
+	busy    int32
+
+	errLck sync.Mutex
+	err    error
+}
+
+// NewWorkerPool starts the workers and the metric reporting of the pool, which run until Wait was called or the
+// context is canceled.
+func NewWorkerPool(ctx context.Context, logger mon.Logger, settings *WorkerPoolSettings) WorkerPool {
+	metric := mon.NewMetricDaemonWriter(getWorkerPoolDefaultMetrics(settings.Name)...)
+
+	return NewWorkerPoolWithInterfaces(ctx, logger, metric, settings)
+}
+
+func NewWorkerPoolWithInterfaces(ctx context.Context, logger mon.Logger, metric mon.MetricWriter, settings *WorkerPoolSettings) WorkerPool {
+	if settings.Workers <= 0 {
+		settings.Workers = 1
+	}
+
+	if settings.MetricInterval <= 0 {
+		settings.MetricInterval = time.Second * 10
+	}
+
+	cfn, ctx := coffin.WithContext(ctx)
+
+	pool := &workerPool{
+		logger:   logger.WithFields(mon.Fields{"worker_pool": settings.Name}),
+		metric:   metric,
+		settings: settings,
+		ctx:      ctx,
+		cfn:      cfn,
+		tasks:    make(chan Task, settings.QueueSize),
+		stopped:  NewSignalOnce(),
+	}
+
+	// like a tomb, a coffin must not start a go routine after all others returned, which
+	// could happen for an already canceled context, so we start working once all are scheduled
+	startWorking := NewSignalOnce()
+
+	for i := 0; i < settings.Workers; i++ {
+		cfn.GoWithContext(ctx, func(ctx context.Context) error {
+			<-startWorking.Channel()
+
+			return pool.work(ctx)
+		})
+	}
+
+	startWorking.Signal()
+
+	go pool.reportMetrics()
+
+	return pool
+}
+
+func (p *workerPool) Submit(task Task) error {
+	p.lck.RLock()
+	defer p.lck.RUnlock()
+
+	if p.closed {
+		return ErrWorkerPoolClosed
+	}
+
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-p.ctx.Done():
+		return fmt.Errorf("can not submit task to worker pool %s: %w", p.settings.Name, p.ctx.Err())
+	}
+}
+
+func (p *workerPool) Wait() error {
+	p.lck.Lock()
+
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+
+	p.lck.Unlock()
+
+	// the workers never fail, the errors of the tasks are collected on our own
+	_ = p.cfn.Wait()
+
+	p.stopped.Signal()
+	p.writeGauges()
+
+	p.errLck.Lock()
+	defer p.errLck.Unlock()
+
+	return p.err
+}
+
+func (p *workerPool) work(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case task, ok := <-p.tasks:
+			if !ok {
+				return nil
+			}
+
+			p.runTask(ctx, task)
+		}
+	}
+}
+
+func (p *workerPool) runTask(ctx context.Context, task Task) {
+	atomic.AddInt32(&p.busy, 1)
+	defer atomic.AddInt32(&p.busy, -1)
+
+	start := time.Now()
+	err := p.executeTask(ctx, task)
+	p.writeTask(time.Since(start), err)
+
+	if err == nil {
+		return
+	}
+
+	p.errLck.Lock()
+	defer p.errLck.Unlock()
+
+	p.err = multierror.Append(p.err, err)
+}
+
+func (p *workerPool) executeTask(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if panicErr := coffin.ResolveRecovery(recover()); panicErr != nil {
+			p.logger.Error(panicErr, "task of worker pool panicked")
+			err = panicErr
+		}
+	}()
+
+	return task(ctx)
+}
+
+func (p *workerPool) reportMetrics() {
+	ticker := time.NewTicker(p.settings.MetricInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopped.Channel():
+			return
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.writeGauges()
+		}
+	}
+}
+
+func (p *workerPool) writeGauges() {
+	utilization := float64(atomic.LoadInt32(&p.busy)) / float64(p.settings.Workers) * 100
+
+	p.metric.Write(mon.MetricData{
+		workerPoolDatum(metricNameWorkerPoolQueueDepth, p.settings.Name, float64(len(p.tasks)), mon.UnitCountAverage),
+		workerPoolDatum(metricNameWorkerPoolUtilization, p.settings.Name, utilization, mon.UnitPercentAverage),
+	})
+}
+
+func (p *workerPool) writeTask(duration time.Duration, err error) {
+	data := mon.MetricData{
+		workerPoolDatum(metricNameWorkerPoolTasks, p.settings.Name, 1.0, mon.UnitCount),
+		workerPoolDatum(metricNameWorkerPoolTaskDuration, p.settings.Name, float64(duration.Milliseconds()), mon.UnitMillisecondsAverage),
+	}
+
+	if err != nil {
+		data = append(data, workerPoolDatum(metricNameWorkerPoolTaskFailures, p.settings.Name, 1.0, mon.UnitCount))
+	}
+
+	p.metric.Write(data)
+}
+
+func workerPoolDatum(metricName string, pool string, value float64, unit string) *mon.MetricDatum {
+	return &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricName,
+		Dimensions: mon.MetricDimensions{
+			"WorkerPool": pool,
+		},
+		Value: value,
+		Unit:  unit,
+	}
+}
+
+func getWorkerPoolDefaultMetrics(pool string) mon.MetricData {
+	return mon.MetricData{
+		workerPoolDatum(metricNameWorkerPoolTasks, pool, 0.0, mon.UnitCount),
+		workerPoolDatum(metricNameWorkerPoolTaskFailures, pool, 0.0, mon.UnitCount),
+	}
+}
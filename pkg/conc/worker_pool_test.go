@@ -0,0 +1,139 @@
+package conc_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/conc"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newWorkerPool(ctx context.Context, workers int) conc.WorkerPool {
+	return conc.NewWorkerPoolWithInterfaces(ctx, monMocks.NewLoggerMockedAll(), monMocks.NewMetricWriterMockedAll(), &conc.WorkerPoolSettings{
+		Name:           "test",
+		Workers:        workers,
+		QueueSize:      2,
+		MetricInterval: time.Millisecond,
+	})
+}
+
+func TestWorkerPool_Wait(t *testing.T) {
+	pool := newWorkerPool(context.Background(), 3)
+
+	var done, running, maxRunning int32
+
+	for i := 0; i < 10; i++ {
+		err := pool.Submit(func(ctx context.Context) error {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond * 5)
+			atomic.AddInt32(&done, 1)
+
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, pool.Wait())
+	assert.Equal(t, int32(10), done)
+	assert.LessOrEqual(t, maxRunning, int32(3))
+
+	err := pool.Submit(func(ctx context.Context) error {
+		return nil
+	})
+	assert.Equal(t, conc.ErrWorkerPoolClosed, err)
+}
+
+func TestWorkerPool_Failures(t *testing.T) {
+	pool := newWorkerPool(context.Background(), 2)
+
+	assert.NoError(t, pool.Submit(func(ctx context.Context) error {
+		return fmt.Errorf("task failed")
+	}))
+	assert.NoError(t, pool.Submit(func(ctx context.Context) error {
+		panic("task panicked")
+	}))
+	assert.NoError(t, pool.Submit(func(ctx context.Context) error {
+		return nil
+	}))
+
+	err := pool.Wait()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "task failed")
+	assert.Contains(t, err.Error(), "task panicked")
+}
+
+func TestWorkerPool_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := newWorkerPool(ctx, 1)
+
+	assert.NoError(t, pool.Submit(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}))
+
+	cancel()
+
+	// the worker is blocked and the queue is filled up at some point
+	var err error
+	for i := 0; i < 4 && err == nil; i++ {
+		err = pool.Submit(func(ctx context.Context) error {
+			return nil
+		})
+	}
+
+	assert.Error(t, err)
+	assert.NoError(t, pool.Wait())
+}
+
+type countingMetricWriter struct {
+	writes int32
+}
+
+func (w *countingMetricWriter) GetPriority() int {
+	return mon.PriorityLow
+}
+
+func (w *countingMetricWriter) Write(_ mon.MetricData) {
+	atomic.AddInt32(&w.writes, 1)
+}
+
+func (w *countingMetricWriter) WriteOne(_ *mon.MetricDatum) {
+	atomic.AddInt32(&w.writes, 1)
+}
+
+func TestWorkerPool_MetricsStoppedByContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	metric := &countingMetricWriter{}
+
+	conc.NewWorkerPoolWithInterfaces(ctx, monMocks.NewLoggerMockedAll(), metric, &conc.WorkerPoolSettings{
+		Name:           "test",
+		Workers:        1,
+		MetricInterval: time.Millisecond,
+	})
+
+	time.Sleep(time.Millisecond * 10)
+	assert.Greater(t, atomic.LoadInt32(&metric.writes), int32(0), "the gauges should be written periodically")
+
+	// the pool is never waited for, so only the context stops the reporting
+	cancel()
+	time.Sleep(time.Millisecond * 10)
+
+	writes := atomic.LoadInt32(&metric.writes)
+	time.Sleep(time.Millisecond * 10)
+
+	assert.Equal(t, writes, atomic.LoadInt32(&metric.writes), "the gauges should not be written after the context was canceled")
+}
@@ -0,0 +1,95 @@
+package cron
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"time"
+)
+
+// A JobFunc performs a single run of a job. The context is canceled once the kernel stops.
+type JobFunc func(ctx context.Context) error
+
+type Definer func(ctx context.Context, config cfg.Config, logger mon.Logger) (*Definitions, error)
+
+type JobOption func(def *Definition)
+
+// Run the job on a random delay between 0 and jitter after every activation, e.g.
+// to spread the load of many instances. The jitter should be smaller than the
+// interval between two activations.
+func WithJitter(jitter time.Duration) JobOption {
+	return func(def *Definition) {
+		def.jitter = jitter
+	}
+}
+
+// Allow a new run of the job to start while the previous run is still in progress.
+// By default, an activation is skipped if the job is still running.
+func WithOverlap() JobOption {
+	return func(def *Definition) {
+		def.overlap = true
+	}
+}
+
+// Run every activation of the job on only one instance of the application by acquiring
+// a distributed lock (backed by ddb) for it. Instances failing to get the lock skip the run.
+func WithLock() JobOption {
+	return func(def *Definition) {
+		def.lock = true
+	}
+}
+
+type Definition struct {
+	name       string
+	expression string
+	schedule   Schedule
+	job        JobFunc
+	jitter     time.Duration
+	overlap    bool
+	lock       bool
+}
+
+type Definitions struct {
+	jobs []*Definition
+}
+
+// Cron adds a job running according to a cron expression (see ParseSchedule).
+func (d *Definitions) Cron(name string, expression string, job JobFunc, opts ...JobOption) {
+	d.add(&Definition{
+		name:       name,
+		expression: expression,
+		job:        job,
+	}, opts)
+}
+
+// Every adds a job running in a fixed interval.
+func (d *Definitions) Every(name string, interval time.Duration, job JobFunc, opts ...JobOption) {
+	d.Schedule(name, Every(interval), job, opts...)
+}
+
+// Schedule adds a job running according to a custom schedule.
+func (d *Definitions) Schedule(name string, schedule Schedule, job JobFunc, opts ...JobOption) {
+	d.add(&Definition{
+		name:     name,
+		schedule: schedule,
+		job:      job,
+	}, opts)
+}
+
+func (d *Definitions) add(def *Definition, opts []JobOption) {
+	for _, opt := range opts {
+		opt(def)
+	}
+
+	d.jobs = append(d.jobs, def)
+}
+
+func (d *Definitions) needsLock() bool {
+	for _, def := range d.jobs {
+		if def.lock {
+			return true
+		}
+	}
+
+	return false
+}
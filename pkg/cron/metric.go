@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"github.com/applike/gosoline/pkg/mon"
+	"time"
+)
+
+const (
+	// number of started runs of a job
+	metricNameJobRun = "CronJobRun"
+	// number of runs which returned an error or panicked
+	metricNameJobFailure = "CronJobFailure"
+	// number of activations skipped because the job was still running or another instance got the lock
+	metricNameJobSkipped = "CronJobSkipped"
+	// duration of a single run
+	metricNameJobDuration = "CronJobDuration"
+)
+
+type jobMetricWriter struct {
+	writer mon.MetricWriter
+}
+
+func newJobMetricWriter(writer mon.MetricWriter) *jobMetricWriter {
+	return &jobMetricWriter{
+		writer: writer,
+	}
+}
+
+func (w *jobMetricWriter) writeRun(name string, duration time.Duration, err error) {
+	data := mon.MetricData{
+		datum(metricNameJobRun, name, 1.0, mon.UnitCount),
+		datum(metricNameJobDuration, name, float64(duration.Milliseconds()), mon.UnitMillisecondsAverage),
+	}
+
+	if err != nil {
+		data = append(data, datum(metricNameJobFailure, name, 1.0, mon.UnitCount))
+	}
+
+	w.writer.Write(data)
+}
+
+func (w *jobMetricWriter) writeSkipped(name string) {
+	w.writer.WriteOne(datum(metricNameJobSkipped, name, 1.0, mon.UnitCount))
+}
+
+func datum(metricName string, job string, value float64, unit string) *mon.MetricDatum {
+	return &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricName,
+		Dimensions: mon.MetricDimensions{
+			"CronJob": job,
+		},
+		Value: value,
+		Unit:  unit,
+	}
+}
+
+func getJobDefaultMetrics(definitions *Definitions) mon.MetricData {
+	defaults := make(mon.MetricData, 0, len(definitions.jobs)*3)
+
+	for _, def := range definitions.jobs {
+		defaults = append(defaults,
+			datum(metricNameJobRun, def.name, 0.0, mon.UnitCount),
+			datum(metricNameJobFailure, def.name, 0.0, mon.UnitCount),
+			datum(metricNameJobSkipped, def.name, 0.0, mon.UnitCount),
+		)
+	}
+
+	return defaults
+}
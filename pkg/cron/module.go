@@ -0,0 +1,265 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/conc"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/uuid"
+	"github.com/cenkalti/backoff"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Settings struct {
+	// ttl of the lock of a single run, runs taking longer are no longer protected
+	LockTime time.Duration `cfg:"lock_time" default:"15m"`
+	// time to keep the lock after the last instance could have started a run to cover clock skew between instances
+	LockGrace time.Duration `cfg:"lock_grace" default:"30s"`
+}
+
+type job struct {
+	*Definition
+	running int32
+}
+
+type module struct {
+	kernel.DefaultModule
+
+	logger       mon.Logger
+	clock        clock.Clock
+	metric       *jobMetricWriter
+	lockProvider conc.DistributedLockProvider
+	settings     *Settings
+	jobs         []*job
+}
+
+// New creates a module running the jobs of the definer according to their schedules.
+func New(definer Definer) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		settings := &Settings{}
+		config.UnmarshalKey("cron", settings)
+
+		definitions, err := definer(ctx, config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("can not define cron jobs: %w", err)
+		}
+
+		var lockProvider conc.DistributedLockProvider
+
+		if definitions.needsLock() {
+			if lockProvider, err = newLockProvider(config, logger, settings); err != nil {
+				return nil, fmt.Errorf("can not create lock provider: %w", err)
+			}
+		}
+
+		metric := mon.NewMetricDaemonWriter(getJobDefaultMetrics(definitions)...)
+
+		return NewWithInterfaces(logger, clock.Provider, metric, lockProvider, settings, definitions)
+	}
+}
+
+func NewWithInterfaces(
+	logger mon.Logger,
+	clock clock.Clock,
+	metric mon.MetricWriter,
+	lockProvider conc.DistributedLockProvider,
+	settings *Settings,
+	definitions *Definitions,
+) (*module, error) {
+	jobs := make([]*job, 0, len(definitions.jobs))
+
+	for _, def := range definitions.jobs {
+		if def.schedule == nil {
+			schedule, err := ParseSchedule(def.expression)
+			if err != nil {
+				return nil, fmt.Errorf("can not parse schedule of cron job %s: %w", def.name, err)
+			}
+
+			def.schedule = schedule
+		}
+
+		if def.lock && lockProvider == nil {
+			return nil, fmt.Errorf("cron job %s needs a lock, but there is no lock provider", def.name)
+		}
+
+		jobs = append(jobs, &job{
+			Definition: def,
+		})
+	}
+
+	return &module{
+		logger:       logger.WithChannel("cron"),
+		clock:        clock,
+		metric:       newJobMetricWriter(metric),
+		lockProvider: lockProvider,
+		settings:     settings,
+		jobs:         jobs,
+	}, nil
+}
+
+func newLockProvider(config cfg.Config, logger mon.Logger, settings *Settings) (conc.DistributedLockProvider, error) {
+	repo, err := ddb.NewRepository(config, logger, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Name: "cron-locks",
+		},
+		Main: ddb.MainSettings{
+			Model:              &conc.DdbLockItem{},
+			ReadCapacityUnits:  1,
+			WriteCapacityUnits: 1,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can not create ddb repository: %w", err)
+	}
+
+	// we don't want to wait for a lock owned by another instance, it already runs the job
+	return conc.NewDdbLockProviderWithInterfaces(logger, repo, &backoff.StopBackOff{}, clock.Provider, uuid.New(), conc.DistributedLockSettings{
+		DefaultLockTime: settings.LockTime,
+		Domain:          "cron",
+	}), nil
+}
+
+func (m *module) Run(ctx context.Context) error {
+	cfn := coffin.New()
+
+	for _, j := range m.jobs {
+		cfn.GoWithContextf(ctx, func(j *job) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				m.runSchedule(ctx, j)
+
+				return nil
+			}
+		}(j), "panic during scheduling of cron job %s", j.name)
+	}
+
+	return cfn.Wait()
+}
+
+func (m *module) runSchedule(ctx context.Context, j *job) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	activation := m.clock.Now()
+
+	for {
+		now := m.clock.Now()
+		activation = j.schedule.Next(activation)
+
+		// if we are falling behind, we skip the missed activations
+		if activation.Before(now) {
+			activation = j.schedule.Next(now)
+		}
+
+		if activation.IsZero() {
+			m.logger.Warnf("cron job %s has no further activations", j.name)
+			return
+		}
+
+		delay := activation.Sub(now)
+
+		if j.jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(j.jitter)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.clock.After(delay):
+		}
+
+		if !j.overlap && !atomic.CompareAndSwapInt32(&j.running, 0, 1) {
+			m.logger.Warnf("skipping cron job %s as the previous run is still in progress", j.name)
+			m.metric.writeSkipped(j.name)
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(activation time.Time) {
+			defer wg.Done()
+			defer atomic.StoreInt32(&j.running, 0)
+
+			m.execute(ctx, j, activation)
+		}(activation)
+	}
+}
+
+func (m *module) execute(ctx context.Context, j *job, activation time.Time) {
+	if j.lock {
+		resource := fmt.Sprintf("%s-%d", j.name, activation.Unix())
+		lock, err := m.lockProvider.Acquire(ctx, resource)
+
+		if errors.Is(err, conc.ErrOwnedLock) {
+			m.logger.Debugf("skipping cron job %s as another instance is running it", j.name)
+			m.metric.writeSkipped(j.name)
+
+			return
+		}
+
+		if err != nil {
+			m.logger.Errorf(err, "can not acquire lock for cron job %s", j.name)
+			m.metric.writeRun(j.name, 0, err)
+
+			return
+		}
+
+		// releasing the lock right away would allow a later instance to acquire it again
+		// and run the same activation a second time
+		holdUntil := activation.Add(j.jitter).Add(m.settings.LockGrace)
+		defer m.releaseAt(lock, j.name, holdUntil)
+	}
+
+	start := m.clock.Now()
+	err := m.runJob(ctx, j)
+	duration := m.clock.Now().Sub(start)
+
+	m.metric.writeRun(j.name, duration, err)
+
+	if err != nil {
+		m.logger.Errorf(err, "error running cron job %s", j.name)
+		return
+	}
+
+	m.logger.Infof("finished cron job %s in %s", j.name, duration)
+}
+
+func (m *module) runJob(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if panicErr := coffin.ResolveRecovery(recover()); panicErr != nil {
+			err = panicErr
+		}
+	}()
+
+	return j.job(ctx)
+}
+
+func (m *module) releaseAt(lock conc.DistributedLock, name string, at time.Time) {
+	release := func() {
+		if err := lock.Release(); err != nil && !errors.Is(err, conc.ErrNotOwned) {
+			m.logger.Warnf("can not release lock of cron job %s: %s", name, err.Error())
+		}
+	}
+
+	delay := at.Sub(m.clock.Now())
+
+	if delay <= 0 {
+		release()
+		return
+	}
+
+	go func() {
+		<-m.clock.After(delay)
+		release()
+	}()
+}
@@ -0,0 +1,100 @@
+package cron_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/conc"
+	concMocks "github.com/applike/gosoline/pkg/conc/mocks"
+	"github.com/applike/gosoline/pkg/cron"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func runModule(t *testing.T, lockProvider conc.DistributedLockProvider, definitions *cron.Definitions) (clock.FakeClock, context.CancelFunc, chan error) {
+	fakeClock := clock.NewFakeClockAt(time.Date(2021, 3, 17, 10, 42, 13, 0, time.UTC))
+	settings := &cron.Settings{
+		LockTime:  time.Minute,
+		LockGrace: 0,
+	}
+
+	module, err := cron.NewWithInterfaces(monMocks.NewLoggerMockedAll(), fakeClock, monMocks.NewMetricWriterMockedAll(), lockProvider, settings, definitions)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- module.Run(ctx)
+	}()
+
+	fakeClock.BlockUntil(1)
+
+	return fakeClock, cancel, done
+}
+
+func TestModule_Run(t *testing.T) {
+	runs := make(chan time.Time, 1)
+
+	definitions := &cron.Definitions{}
+	definitions.Every("job", time.Minute, func(ctx context.Context) error {
+		runs <- time.Now()
+
+		return nil
+	})
+
+	fakeClock, cancel, done := runModule(t, nil, definitions)
+	fakeClock.Advance(47 * time.Second)
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		assert.Fail(t, "job did not run")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestModule_RunLocked(t *testing.T) {
+	acquired := make(chan struct{})
+	activation := time.Date(2021, 3, 17, 10, 43, 0, 0, time.UTC)
+
+	lockProvider := new(concMocks.DistributedLockProvider)
+	lockProvider.On("Acquire", mock.Anything, fmt.Sprintf("job-%d", activation.Unix())).Run(func(args mock.Arguments) {
+		close(acquired)
+	}).Return(nil, conc.ErrOwnedLock).Once()
+
+	definitions := &cron.Definitions{}
+	definitions.Cron("job", "* * * * *", func(ctx context.Context) error {
+		assert.Fail(t, "job should not run without the lock")
+
+		return nil
+	}, cron.WithLock())
+
+	fakeClock, cancel, done := runModule(t, lockProvider, definitions)
+	fakeClock.Advance(47 * time.Second)
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		assert.Fail(t, "lock was not acquired")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+	lockProvider.AssertExpectations(t)
+}
+
+func TestModule_InvalidSchedule(t *testing.T) {
+	definitions := &cron.Definitions{}
+	definitions.Cron("job", "* * *", func(ctx context.Context) error {
+		return nil
+	})
+
+	_, err := cron.NewWithInterfaces(monMocks.NewLoggerMockedAll(), clock.NewFakeClock(), monMocks.NewMetricWriterMockedAll(), nil, &cron.Settings{}, definitions)
+	assert.EqualError(t, err, `can not parse schedule of cron job job: expected 5 fields in cron expression "* * *", got 3`)
+}
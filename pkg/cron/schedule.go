@@ -0,0 +1,252 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Schedule computes the next activation of a job strictly after the given time.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type fieldBounds struct {
+	name string
+	min  uint
+	max  uint
+}
+
+var (
+	boundsMinute     = fieldBounds{name: "minute", min: 0, max: 59}
+	boundsHour       = fieldBounds{name: "hour", min: 0, max: 23}
+	boundsDayOfMonth = fieldBounds{name: "day of month", min: 1, max: 31}
+	boundsMonth      = fieldBounds{name: "month", min: 1, max: 12}
+	boundsDayOfWeek  = fieldBounds{name: "day of week", min: 0, max: 7}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a standard cron expression with the five fields minute, hour,
+// day of month, month and day of week. Every field supports "*", lists ("1,2"),
+// ranges ("1-5") and steps ("*/15", "0-30/10"). Additionally, the descriptors
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly as well as
+// "@every <duration>" (e.g. "@every 90s") are understood.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("can not parse interval of %q: %w", spec, err)
+		}
+
+		if interval <= 0 {
+			return nil, fmt.Errorf("interval of %q has to be positive", spec)
+		}
+
+		return Every(interval), nil
+	}
+
+	if expression, ok := descriptors[spec]; ok {
+		spec = expression
+	}
+
+	fields := strings.Fields(spec)
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in cron expression %q, got %d", spec, len(fields))
+	}
+
+	var err error
+	schedule := &cronSchedule{}
+
+	if schedule.minute, err = parseField(fields[0], boundsMinute); err != nil {
+		return nil, err
+	}
+
+	if schedule.hour, err = parseField(fields[1], boundsHour); err != nil {
+		return nil, err
+	}
+
+	if schedule.dayOfMonth, err = parseField(fields[2], boundsDayOfMonth); err != nil {
+		return nil, err
+	}
+
+	if schedule.month, err = parseField(fields[3], boundsMonth); err != nil {
+		return nil, err
+	}
+
+	if schedule.dayOfWeek, err = parseField(fields[4], boundsDayOfWeek); err != nil {
+		return nil, err
+	}
+
+	// 7 is an accepted alias for sunday
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	schedule.dayOfMonthStar = strings.HasPrefix(fields[2], "*")
+	schedule.dayOfWeekStar = strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+func parseField(field string, bounds fieldBounds) (uint64, error) {
+	var bitSet uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, step := part, uint(1)
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			parsedStep, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || parsedStep == 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", bounds.name, part)
+			}
+
+			rangeSpec, step = part[:i], uint(parsedStep)
+		}
+
+		start, end, err := parseRange(rangeSpec, bounds)
+		if err != nil {
+			return 0, err
+		}
+
+		// a step on a single value like 5/10 means "from 5 to the end every 10"
+		if step > 1 && start == end && rangeSpec != "*" {
+			end = bounds.max
+		}
+
+		for value := start; value <= end; value += step {
+			bitSet |= 1 << value
+		}
+	}
+
+	return bitSet, nil
+}
+
+func parseRange(rangeSpec string, bounds fieldBounds) (uint, uint, error) {
+	if rangeSpec == "*" {
+		return bounds.min, bounds.max, nil
+	}
+
+	parts := strings.SplitN(rangeSpec, "-", 2)
+
+	start, err := parseValue(parts[0], bounds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(parts) == 1 {
+		return start, start, nil
+	}
+
+	end, err := parseValue(parts[1], bounds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid range in %s field %q", bounds.name, rangeSpec)
+	}
+
+	return start, end, nil
+}
+
+func parseValue(value string, bounds fieldBounds) (uint, error) {
+	parsed, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field %q", bounds.name, value)
+	}
+
+	if uint(parsed) < bounds.min || uint(parsed) > bounds.max {
+		return 0, fmt.Errorf("value %d of %s field is out of range [%d, %d]", parsed, bounds.name, bounds.min, bounds.max)
+	}
+
+	return uint(parsed), nil
+}
+
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	dayOfMonthStar bool
+	dayOfWeekStar  bool
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, t.Location())
+
+	// no valid expression needs more than five years to match (e.g. 29th of february on a monday)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// like most cron implementations, we match if either the day of month or the day of week
+// match if both are restricted and require both to match otherwise
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := has(s.dayOfMonth, t.Day())
+	dayOfWeek := has(s.dayOfWeek, int(t.Weekday()))
+
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+func has(bitSet uint64, value int) bool {
+	return bitSet&(1<<uint(value)) != 0
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+// Every runs a job in a fixed interval, aligned to multiples of the interval.
+func Every(interval time.Duration) Schedule {
+	return everySchedule{
+		interval: interval,
+	}
+}
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
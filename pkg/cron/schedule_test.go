@@ -0,0 +1,96 @@
+package cron_test
+
+import (
+	"github.com/applike/gosoline/pkg/cron"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// a wednesday
+	now := time.Date(2021, 3, 17, 10, 42, 13, 0, time.UTC)
+
+	tests := map[string]struct {
+		spec     string
+		expected time.Time
+	}{
+		"every minute": {
+			spec:     "* * * * *",
+			expected: time.Date(2021, 3, 17, 10, 43, 0, 0, time.UTC),
+		},
+		"step": {
+			spec:     "*/15 * * * *",
+			expected: time.Date(2021, 3, 17, 10, 45, 0, 0, time.UTC),
+		},
+		"list": {
+			spec:     "5,20 * * * *",
+			expected: time.Date(2021, 3, 17, 11, 5, 0, 0, time.UTC),
+		},
+		"range with step": {
+			spec:     "0 8-18/4 * * *",
+			expected: time.Date(2021, 3, 17, 12, 0, 0, 0, time.UTC),
+		},
+		"day of week": {
+			spec:     "30 9 * * 1-5",
+			expected: time.Date(2021, 3, 18, 9, 30, 0, 0, time.UTC),
+		},
+		"sunday as 7": {
+			spec:     "0 0 * * 7",
+			expected: time.Date(2021, 3, 21, 0, 0, 0, 0, time.UTC),
+		},
+		"day of month or day of week": {
+			spec:     "0 0 1 * 5",
+			expected: time.Date(2021, 3, 19, 0, 0, 0, 0, time.UTC),
+		},
+		"month rollover": {
+			spec:     "0 0 1 * *",
+			expected: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"leap day": {
+			spec:     "0 12 29 2 *",
+			expected: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+		},
+		"descriptor": {
+			spec:     "@daily",
+			expected: time.Date(2021, 3, 18, 0, 0, 0, 0, time.UTC),
+		},
+		"every": {
+			spec:     "@every 10m",
+			expected: time.Date(2021, 3, 17, 10, 50, 0, 0, time.UTC),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schedule, err := cron.ParseSchedule(test.spec)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, schedule.Next(now))
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"@every",
+		"@every -1m",
+		"@sometimes",
+	}
+
+	for _, spec := range specs {
+		_, err := cron.ParseSchedule(spec)
+
+		assert.Error(t, err, "spec %q should be invalid", spec)
+	}
+}
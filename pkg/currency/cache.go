@@ -0,0 +1,159 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/refl"
+	"reflect"
+)
+
+const (
+	// number of rates read from the in-process cache
+	metricNameCurrencyCacheHit = "CurrencyCacheHit"
+	// number of rates read from the store because they were not cached
+	metricNameCurrencyCacheMiss = "CurrencyCacheMiss"
+)
+
+// cachedStore keeps the rates read from the store in process for the cache ttl, so not every conversion has to read
+// them from redis or ddb. New rates of the updater are visible after the cached ones expired.
+type cachedStore struct {
+	kvstore.KvStore
+	cache        kvstore.KvStore
+	metricWriter mon.MetricWriter
+}
+
+func NewCachedStore(store kvstore.KvStore, settings *Settings) kvstore.KvStore {
+	cache := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{
+		Name: "currency-cache",
+		Ttl:  settings.CacheTtl,
+		InMemorySettings: kvstore.InMemorySettings{
+			MaxSize: settings.CacheSize,
+		},
+	})
+
+	metricWriter := mon.NewMetricDaemonWriter(getCacheDefaultMetrics()...)
+
+	return NewCachedStoreWithInterfaces(store, cache, metricWriter)
+}
+
+func NewCachedStoreWithInterfaces(store kvstore.KvStore, cache kvstore.KvStore, metricWriter mon.MetricWriter) kvstore.KvStore {
+	return &cachedStore{
+		KvStore:      store,
+		cache:        cache,
+		metricWriter: metricWriter,
+	}
+}
+
+func (s *cachedStore) Contains(ctx context.Context, key interface{}) (bool, error) {
+	if found, err := s.cache.Contains(ctx, key); err == nil && found {
+		s.record(1, 0)
+
+		return true, nil
+	}
+
+	s.record(0, 1)
+
+	return s.KvStore.Contains(ctx, key)
+}
+
+func (s *cachedStore) Get(ctx context.Context, key interface{}, value interface{}) (bool, error) {
+	if found, err := s.cache.Get(ctx, key, value); err == nil && found {
+		s.record(1, 0)
+
+		return true, nil
+	}
+
+	s.record(0, 1)
+
+	found, err := s.KvStore.Get(ctx, key, value)
+
+	if err != nil || !found {
+		return found, err
+	}
+
+	if err = s.cache.Put(ctx, key, value); err != nil {
+		return false, fmt.Errorf("can not cache the rate %v: %w", key, err)
+	}
+
+	return true, nil
+}
+
+func (s *cachedStore) GetBatch(ctx context.Context, keys interface{}, values interface{}) ([]interface{}, error) {
+	keySlice, err := refl.InterfaceToInterfaceSlice(keys)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not morph keys to slice of interfaces: %w", err)
+	}
+
+	missing, err := s.cache.GetBatch(ctx, keySlice, values)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not get the rates from the cache: %w", err)
+	}
+
+	s.record(len(keySlice)-len(missing), len(missing))
+
+	if len(missing) == 0 {
+		return missing, nil
+	}
+
+	// the rates read from the store are collected separately, so only they are added to the cache
+	fetched := reflect.MakeMap(reflect.TypeOf(values))
+
+	if missing, err = s.KvStore.GetBatch(ctx, missing, fetched.Interface()); err != nil {
+		return nil, err
+	}
+
+	if fetched.Len() == 0 {
+		return missing, nil
+	}
+
+	if err = s.cache.PutBatch(ctx, fetched.Interface()); err != nil {
+		return nil, fmt.Errorf("can not cache the rates: %w", err)
+	}
+
+	result := reflect.ValueOf(values)
+	iter := fetched.MapRange()
+
+	for iter.Next() {
+		result.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	return missing, nil
+}
+
+func (s *cachedStore) record(hits int, misses int) {
+	s.metricWriter.Write(mon.MetricData{
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameCurrencyCacheHit,
+			Value:      float64(hits),
+			Unit:       mon.UnitCount,
+		},
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameCurrencyCacheMiss,
+			Value:      float64(misses),
+			Unit:       mon.UnitCount,
+		},
+	})
+}
+
+func getCacheDefaultMetrics() mon.MetricData {
+	return mon.MetricData{
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameCurrencyCacheHit,
+			Unit:       mon.UnitCount,
+			Value:      0.0,
+		},
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameCurrencyCacheMiss,
+			Unit:       mon.UnitCount,
+			Value:      0.0,
+		},
+	}
+}
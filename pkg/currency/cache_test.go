@@ -0,0 +1,116 @@
+package currency_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/currency"
+	"github.com/applike/gosoline/pkg/kvstore"
+	kvStoreMock "github.com/applike/gosoline/pkg/kvstore/mocks"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func buildCachedStore() (*kvStoreMock.KvStore, *monMocks.MetricWriter, kvstore.KvStore) {
+	store := new(kvStoreMock.KvStore)
+	cache := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{
+		Ttl: time.Minute,
+	})
+
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("Write", mock.AnythingOfType("mon.MetricData"))
+
+	return store, metricWriter, currency.NewCachedStoreWithInterfaces(store, cache, metricWriter)
+}
+
+func assertCacheMetrics(t *testing.T, metricWriter *monMocks.MetricWriter, hits float64, misses float64) {
+	actualHits := 0.0
+	actualMisses := 0.0
+
+	for _, call := range metricWriter.Calls {
+		for _, datum := range call.Arguments.Get(0).(mon.MetricData) {
+			switch datum.MetricName {
+			case "CurrencyCacheHit":
+				actualHits += datum.Value
+			case "CurrencyCacheMiss":
+				actualMisses += datum.Value
+			}
+		}
+	}
+
+	assert.Equal(t, hits, actualHits, "the number of cache hits does not match")
+	assert.Equal(t, misses, actualMisses, "the number of cache misses does not match")
+}
+
+func TestCachedStore_Get(t *testing.T) {
+	store, metricWriter, cachedStore := buildCachedStore()
+
+	store.On("Get", mock.Anything, "USD", mock.AnythingOfType("*float64")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*float64)
+		*ptr = 1.09
+	}).Return(true, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		var rate float64
+		found, err := cachedStore.Get(context.Background(), "USD", &rate)
+
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 1.09, rate)
+	}
+
+	store.AssertExpectations(t)
+	assertCacheMetrics(t, metricWriter, 2, 1)
+}
+
+func TestCachedStore_Get_NotFound(t *testing.T) {
+	store, metricWriter, cachedStore := buildCachedStore()
+
+	store.On("Get", mock.Anything, "XXX", mock.AnythingOfType("*float64")).Return(false, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		var rate float64
+		found, err := cachedStore.Get(context.Background(), "XXX", &rate)
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+	}
+
+	store.AssertExpectations(t)
+	assertCacheMetrics(t, metricWriter, 0, 2)
+}
+
+func TestCachedStore_GetBatch(t *testing.T) {
+	store, metricWriter, cachedStore := buildCachedStore()
+
+	store.On("Get", mock.Anything, "USD", mock.AnythingOfType("*float64")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*float64)
+		*ptr = 1.09
+	}).Return(true, nil).Once()
+	store.On("GetBatch", mock.Anything, []interface{}{"JPY", "XXX"}, mock.AnythingOfType("map[string]float64")).Run(func(args mock.Arguments) {
+		values := args.Get(2).(map[string]float64)
+		values["JPY"] = 122.44
+	}).Return([]interface{}{"XXX"}, nil).Once()
+
+	var rate float64
+	_, err := cachedStore.Get(context.Background(), "USD", &rate)
+	assert.NoError(t, err)
+
+	values := make(map[string]float64)
+	missing, err := cachedStore.GetBatch(context.Background(), []string{"USD", "JPY", "XXX"}, values)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"XXX"}, missing)
+	assert.Equal(t, map[string]float64{"USD": 1.09, "JPY": 122.44}, values)
+
+	found, err := cachedStore.Get(context.Background(), "JPY", &rate)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 122.44, rate)
+
+	store.AssertExpectations(t)
+	assertCacheMetrics(t, metricWriter, 2, 3)
+}
@@ -0,0 +1,39 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+// ListCurrenciesHandler answers with the supported currencies and the time of their latest rate, so frontends can
+// populate their currency selectors. Add it to the api definitions with apiserver.CreateHandler.
+type ListCurrenciesHandler struct {
+	service Service
+}
+
+func NewListCurrenciesHandler(config cfg.Config, logger mon.Logger) (*ListCurrenciesHandler, error) {
+	service, err := New(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("can not create currency service: %w", err)
+	}
+
+	return NewListCurrenciesHandlerWithInterfaces(service), nil
+}
+
+func NewListCurrenciesHandlerWithInterfaces(service Service) *ListCurrenciesHandler {
+	return &ListCurrenciesHandler{
+		service: service,
+	}
+}
+
+func (h *ListCurrenciesHandler) Handle(ctx context.Context, _ *apiserver.Request) (*apiserver.Response, error) {
+	currencies, err := h.service.ListCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can not list the currencies: %w", err)
+	}
+
+	return apiserver.NewJsonResponse(currencies), nil
+}
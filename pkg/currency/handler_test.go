@@ -0,0 +1,48 @@
+package currency_test
+
+import (
+	"context"
+	"errors"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/currency"
+	currencyMocks "github.com/applike/gosoline/pkg/currency/mocks"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListCurrenciesHandler_Handle(t *testing.T) {
+	ctx := context.Background()
+	updatedAt := time.Date(2021, time.January, 2, 16, 5, 0, 0, time.UTC)
+	currencies := []currency.CurrencyInfo{
+		{Code: "EUR", UpdatedAt: updatedAt},
+		{Code: "USD", UpdatedAt: updatedAt},
+	}
+
+	service := new(currencyMocks.Service)
+	service.On("ListCurrencies", ctx).Return(currencies, nil)
+
+	handler := currency.NewListCurrenciesHandlerWithInterfaces(service)
+	response, err := handler.Handle(ctx, &apiserver.Request{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, currencies, response.Body)
+
+	service.AssertExpectations(t)
+}
+
+func TestListCurrenciesHandler_Handle_Error(t *testing.T) {
+	ctx := context.Background()
+
+	service := new(currencyMocks.Service)
+	service.On("ListCurrencies", ctx).Return(nil, errors.New("store down"))
+
+	handler := currency.NewListCurrenciesHandlerWithInterfaces(service)
+	_, err := handler.Handle(ctx, &apiserver.Request{})
+
+	assert.EqualError(t, err, "can not list the currencies: store down")
+
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,140 @@
+package currency
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// RoundingMode decides how a converted amount is rounded to the minor units of the target currency
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest minor unit and ties away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest minor unit and ties to the even one (banker's rounding)
+	RoundHalfEven
+	// RoundDown truncates towards zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+// minorUnitDigits lists the currencies which don't have two decimal digits (ISO 4217)
+var minorUnitDigits = map[string]int{
+	"BHD": 3,
+	"BIF": 0,
+	"CLP": 0,
+	"DJF": 0,
+	"GNF": 0,
+	"IQD": 3,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KMF": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"PYG": 0,
+	"RWF": 0,
+	"TND": 3,
+	"UGX": 0,
+	"VND": 0,
+	"VUV": 0,
+	"XAF": 0,
+	"XOF": 0,
+	"XPF": 0,
+}
+
+// MinorUnitDigits returns the number of decimal digits of the minor unit of the currency, e.g. 2 for cents
+func MinorUnitDigits(currency string) int {
+	if digits, ok := minorUnitDigits[currency]; ok {
+		return digits
+	}
+
+	return 2
+}
+
+// rateToRat converts the rate with its shortest decimal representation, so a stored 1.1289 is exactly 11289/10000
+// and not the binary approximation of it
+func rateToRat(rate float64) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %v", rate)
+	}
+
+	return r, nil
+}
+
+// convertMinorUnits converts the amount in minor units of the from currency with the rate of one unit of the from
+// currency in the to currency to minor units of the to currency
+func convertMinorUnits(amount int64, from string, to string, rate *big.Rat, mode RoundingMode) (int64, error) {
+	value := new(big.Rat).SetInt64(amount)
+	value.Mul(value, rate)
+	value.Mul(value, pow10Rat(MinorUnitDigits(to)-MinorUnitDigits(from)))
+
+	rounded, err := roundRat(value, mode)
+	if err != nil {
+		return 0, err
+	}
+
+	if !rounded.IsInt64() {
+		return 0, fmt.Errorf("the converted amount of %d %s in %s overflows", amount, from, to)
+	}
+
+	return rounded.Int64(), nil
+}
+
+func pow10Rat(exponent int) *big.Rat {
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil)
+
+	if exponent < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), pow)
+	}
+
+	return new(big.Rat).SetInt(pow)
+}
+
+func roundRat(value *big.Rat, mode RoundingMode) (*big.Int, error) {
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	if rem.Sign() == 0 {
+		return quo, nil
+	}
+
+	// compares the remainder with half of the denominator
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Lsh(twiceRem, 1)
+	half := twiceRem.Cmp(value.Denom())
+
+	awayFromZero := false
+
+	switch mode {
+	case RoundHalfUp:
+		awayFromZero = half >= 0
+	case RoundHalfEven:
+		awayFromZero = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	case RoundDown:
+		awayFromZero = false
+	case RoundUp:
+		awayFromZero = true
+	default:
+		return nil, fmt.Errorf("unknown rounding mode %d", mode)
+	}
+
+	if awayFromZero {
+		quo.Add(quo, big.NewInt(int64(value.Sign())))
+	}
+
+	return quo, nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
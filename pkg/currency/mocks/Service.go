@@ -3,28 +3,73 @@
 package mocks
 
 import context "context"
-
+import currency "github.com/applike/gosoline/pkg/currency"
 import mock "github.com/stretchr/testify/mock"
+import time "time"
 
 // Service is an autogenerated mock type for the Service type
 type Service struct {
 	mock.Mock
 }
 
-// HasCurrency provides a mock function with given fields: _a0, _a1
-func (_m *Service) HasCurrency(_a0 context.Context, _a1 string) (bool, error) {
-	ret := _m.Called(_a0, _a1)
+// ExchangeRatesInRange provides a mock function with given fields: ctx, to, from, start, end
+func (_m *Service) ExchangeRatesInRange(ctx context.Context, to string, from string, start time.Time, end time.Time) ([]currency.DatedRate, error) {
+	ret := _m.Called(ctx, to, from, start, end)
+
+	var r0 []currency.DatedRate
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, time.Time) []currency.DatedRate); ok {
+		r0 = rf(ctx, to, from, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]currency.DatedRate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, to, from, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasCurrency provides a mock function with given fields: ctx, currency
+func (_m *Service) HasCurrency(ctx context.Context, currency string) (bool, error) {
+	ret := _m.Called(ctx, currency)
 
 	var r0 bool
 	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(_a0, _a1)
+		r0 = rf(ctx, currency)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(_a0, _a1)
+		r1 = rf(ctx, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasCurrencyAtDate provides a mock function with given fields: ctx, currency, date
+func (_m *Service) HasCurrencyAtDate(ctx context.Context, currency string, date time.Time) (bool, error) {
+	ret := _m.Called(ctx, currency, date)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = rf(ctx, currency, date)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, currency, date)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -32,20 +77,43 @@ func (_m *Service) HasCurrency(_a0 context.Context, _a1 string) (bool, error) {
 	return r0, r1
 }
 
-// ToCurrency provides a mock function with given fields: _a0, _a1, _a2, _a3
-func (_m *Service) ToCurrency(_a0 context.Context, _a1 string, _a2 float64, _a3 string) (float64, error) {
-	ret := _m.Called(_a0, _a1, _a2, _a3)
+// ListCurrencies provides a mock function with given fields: ctx
+func (_m *Service) ListCurrencies(ctx context.Context) ([]currency.CurrencyInfo, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []currency.CurrencyInfo
+	if rf, ok := ret.Get(0).(func(context.Context) []currency.CurrencyInfo); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]currency.CurrencyInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToCurrency provides a mock function with given fields: ctx, to, value, from
+func (_m *Service) ToCurrency(ctx context.Context, to string, value float64, from string) (float64, error) {
+	ret := _m.Called(ctx, to, value, from)
 
 	var r0 float64
 	if rf, ok := ret.Get(0).(func(context.Context, string, float64, string) float64); ok {
-		r0 = rf(_a0, _a1, _a2, _a3)
+		r0 = rf(ctx, to, value, from)
 	} else {
 		r0 = ret.Get(0).(float64)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, string, float64, string) error); ok {
-		r1 = rf(_a0, _a1, _a2, _a3)
+		r1 = rf(ctx, to, value, from)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -53,20 +121,104 @@ func (_m *Service) ToCurrency(_a0 context.Context, _a1 string, _a2 float64, _a3
 	return r0, r1
 }
 
-// ToEur provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Service) ToEur(_a0 context.Context, _a1 float64, _a2 string) (float64, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// ToCurrencyAtDate provides a mock function with given fields: ctx, to, value, from, date
+func (_m *Service) ToCurrencyAtDate(ctx context.Context, to string, value float64, from string, date time.Time) (float64, error) {
+	ret := _m.Called(ctx, to, value, from, date)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, string, time.Time) float64); ok {
+		r0 = rf(ctx, to, value, from, date)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, float64, string, time.Time) error); ok {
+		r1 = rf(ctx, to, value, from, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToCurrencyMinorUnits provides a mock function with given fields: ctx, to, amount, from, mode
+func (_m *Service) ToCurrencyMinorUnits(ctx context.Context, to string, amount int64, from string, mode currency.RoundingMode) (int64, error) {
+	ret := _m.Called(ctx, to, amount, from, mode)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, currency.RoundingMode) int64); ok {
+		r0 = rf(ctx, to, amount, from, mode)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, currency.RoundingMode) error); ok {
+		r1 = rf(ctx, to, amount, from, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToCurrencyMinorUnitsAtDate provides a mock function with given fields: ctx, to, amount, from, date, mode
+func (_m *Service) ToCurrencyMinorUnitsAtDate(ctx context.Context, to string, amount int64, from string, date time.Time, mode currency.RoundingMode) (int64, error) {
+	ret := _m.Called(ctx, to, amount, from, date, mode)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, time.Time, currency.RoundingMode) int64); ok {
+		r0 = rf(ctx, to, amount, from, date, mode)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, time.Time, currency.RoundingMode) error); ok {
+		r1 = rf(ctx, to, amount, from, date, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToEur provides a mock function with given fields: ctx, value, from
+func (_m *Service) ToEur(ctx context.Context, value float64, from string) (float64, error) {
+	ret := _m.Called(ctx, value, from)
 
 	var r0 float64
 	if rf, ok := ret.Get(0).(func(context.Context, float64, string) float64); ok {
-		r0 = rf(_a0, _a1, _a2)
+		r0 = rf(ctx, value, from)
 	} else {
 		r0 = ret.Get(0).(float64)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, float64, string) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+		r1 = rf(ctx, value, from)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToEurAtDate provides a mock function with given fields: ctx, value, from, date
+func (_m *Service) ToEurAtDate(ctx context.Context, value float64, from string, date time.Time) (float64, error) {
+	ret := _m.Called(ctx, value, from, date)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context, float64, string, time.Time) float64); ok {
+		r0 = rf(ctx, value, from, date)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, float64, string, time.Time) error); ok {
+		r1 = rf(ctx, value, from, date)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -74,20 +226,41 @@ func (_m *Service) ToEur(_a0 context.Context, _a1 float64, _a2 string) (float64,
 	return r0, r1
 }
 
-// ToUsd provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Service) ToUsd(_a0 context.Context, _a1 float64, _a2 string) (float64, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// ToUsd provides a mock function with given fields: ctx, value, from
+func (_m *Service) ToUsd(ctx context.Context, value float64, from string) (float64, error) {
+	ret := _m.Called(ctx, value, from)
 
 	var r0 float64
 	if rf, ok := ret.Get(0).(func(context.Context, float64, string) float64); ok {
-		r0 = rf(_a0, _a1, _a2)
+		r0 = rf(ctx, value, from)
 	} else {
 		r0 = ret.Get(0).(float64)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, float64, string) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+		r1 = rf(ctx, value, from)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ToUsdAtDate provides a mock function with given fields: ctx, value, from, date
+func (_m *Service) ToUsdAtDate(ctx context.Context, value float64, from string, date time.Time) (float64, error) {
+	ret := _m.Called(ctx, value, from, date)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context, float64, string, time.Time) float64); ok {
+		r0 = rf(ctx, value, from, date)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, float64, string, time.Time) error); ok {
+		r1 = rf(ctx, value, from, date)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/cron"
 	"github.com/applike/gosoline/pkg/kernel"
 	"github.com/applike/gosoline/pkg/mon"
 	"time"
@@ -14,6 +16,10 @@ type Module struct {
 	kernel.ServiceStage
 	updaterService UpdaterService
 	logger         mon.Logger
+	clock          clock.Clock
+	settings       *Settings
+	schedule       cron.Schedule
+	location       *time.Location
 }
 
 func NewCurrencyModule() kernel.ModuleFactory {
@@ -23,9 +29,20 @@ func NewCurrencyModule() kernel.ModuleFactory {
 			return nil, fmt.Errorf("can not create updater: %w", err)
 		}
 
+		settings := ReadSettings(config)
+
+		schedule, location, err := settings.refreshSchedule()
+		if err != nil {
+			return nil, err
+		}
+
 		module := &Module{
 			logger:         logger,
 			updaterService: updater,
+			clock:          clock.Provider,
+			settings:       settings,
+			schedule:       schedule,
+			location:       location,
 		}
 
 		return module, nil
@@ -33,7 +50,9 @@ func NewCurrencyModule() kernel.ModuleFactory {
 }
 
 func (module *Module) Run(ctx context.Context) error {
-	ticker := time.NewTicker(time.Duration(1) * time.Hour)
+	ticker := clock.NewClockTicker(module.clock, module.settings.CheckInterval)
+	defer ticker.Stop()
+
 	module.refresh(ctx)
 	module.importExchangeRates(ctx)
 	for {
@@ -41,12 +60,27 @@ func (module *Module) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 
-		case <-ticker.C:
+		case <-ticker.Tick():
+			module.refresh(ctx)
+
+		case <-module.nextScheduledRefresh():
 			module.refresh(ctx)
 		}
 	}
 }
 
+// nextScheduledRefresh fires at the next activation of the refresh schedule, it never fires without a schedule
+func (module *Module) nextScheduledRefresh() <-chan time.Time {
+	if module.schedule == nil {
+		return nil
+	}
+
+	now := module.clock.Now()
+	next := module.schedule.Next(now.In(module.location))
+
+	return module.clock.After(next.Sub(now))
+}
+
 func (module *Module) refresh(ctx context.Context) {
 	err := module.updaterService.EnsureRecentExchangeRates(ctx)
 	if err != nil {
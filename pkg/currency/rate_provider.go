@@ -0,0 +1,159 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"sort"
+	"strings"
+)
+
+//go:generate mockery -name RateProvider
+type RateProvider interface {
+	// FetchLatestRates returns the current exchange rates of one euro
+	FetchLatestRates(ctx context.Context) ([]Rate, error)
+}
+
+//go:generate mockery -name HistoricalRateProvider
+type HistoricalRateProvider interface {
+	RateProvider
+	// FetchHistoricalRates returns the exchange rates of one euro for each of the recent days
+	FetchHistoricalRates(ctx context.Context) ([]Content, error)
+}
+
+type RateProviderFactory func(config cfg.Config, logger mon.Logger) (RateProvider, error)
+
+func AddRateProvider(name string, factory RateProviderFactory) {
+	rateProviders[name] = factory
+}
+
+var rateProviders = map[string]RateProviderFactory{
+	"ecb":               NewEcbRateProvider,
+	"fixer":             NewFixerRateProvider,
+	"http":              NewHttpRateProvider,
+	"openexchangerates": NewOpenExchangeRatesRateProvider,
+}
+
+type namedRateProvider struct {
+	name     string
+	provider RateProvider
+}
+
+type fallbackRateProvider struct {
+	logger    mon.Logger
+	providers []namedRateProvider
+}
+
+// NewRateProvider creates the rate providers configured at currency.providers. The rates are taken from the first
+// provider returning them, the others are only asked if the ones before them failed.
+func NewRateProvider(config cfg.Config, logger mon.Logger) (HistoricalRateProvider, error) {
+	settings := ReadSettings(config)
+
+	if len(settings.Providers) == 0 {
+		return nil, fmt.Errorf("there are no currency rate providers configured")
+	}
+
+	providers := make([]namedRateProvider, 0, len(settings.Providers))
+
+	for _, name := range settings.Providers {
+		factory, ok := rateProviders[name]
+		if !ok {
+			return nil, fmt.Errorf("no currency rate provider found for name %s", name)
+		}
+
+		provider, err := factory(config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("can not create currency rate provider %s: %w", name, err)
+		}
+
+		providers = append(providers, namedRateProvider{
+			name:     name,
+			provider: provider,
+		})
+	}
+
+	return &fallbackRateProvider{
+		logger:    logger,
+		providers: providers,
+	}, nil
+}
+
+func (p *fallbackRateProvider) FetchLatestRates(ctx context.Context) ([]Rate, error) {
+	errs := make([]string, 0, len(p.providers))
+
+	for _, named := range p.providers {
+		rates, err := named.provider.FetchLatestRates(ctx)
+
+		if err == nil {
+			return rates, nil
+		}
+
+		p.logger.WithContext(ctx).Warnf("can not fetch the latest exchange rates from %s: %s", named.name, err.Error())
+		errs = append(errs, fmt.Sprintf("%s: %s", named.name, err.Error()))
+	}
+
+	return nil, fmt.Errorf("none of the rate providers returned the latest exchange rates: %s", strings.Join(errs, "; "))
+}
+
+func (p *fallbackRateProvider) FetchHistoricalRates(ctx context.Context) ([]Content, error) {
+	errs := make([]string, 0, len(p.providers))
+
+	for _, named := range p.providers {
+		historical, ok := named.provider.(HistoricalRateProvider)
+
+		if !ok {
+			continue
+		}
+
+		rates, err := historical.FetchHistoricalRates(ctx)
+
+		if err == nil {
+			return rates, nil
+		}
+
+		p.logger.WithContext(ctx).Warnf("can not fetch the historical exchange rates from %s: %s", named.name, err.Error())
+		errs = append(errs, fmt.Sprintf("%s: %s", named.name, err.Error()))
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("none of the rate providers supports historical exchange rates")
+	}
+
+	return nil, fmt.Errorf("none of the rate providers returned the historical exchange rates: %s", strings.Join(errs, "; "))
+}
+
+// ratesOfEur converts the rates of one unit of the base currency to the rates of one euro
+func ratesOfEur(base string, rates map[string]float64) ([]Rate, error) {
+	divisor := 1.0
+
+	if base != Eur {
+		eur, ok := rates[Eur]
+
+		if !ok || eur == 0 {
+			return nil, fmt.Errorf("the rates of %s have no rate for %s", base, Eur)
+		}
+
+		divisor = eur
+		rates[base] = 1
+	}
+
+	result := make([]Rate, 0, len(rates))
+
+	for currency, rate := range rates {
+		if currency == Eur {
+			continue
+		}
+
+		result = append(result, Rate{
+			Currency: currency,
+			Rate:     rate / divisor,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Currency < result[j].Currency
+	})
+
+	return result, nil
+}
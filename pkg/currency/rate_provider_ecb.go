@@ -0,0 +1,81 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+type EcbSettings struct {
+	Url           string `cfg:"url" default:"https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"`
+	HistoricalUrl string `cfg:"historical_url" default:"https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"`
+}
+
+type ecbRateProvider struct {
+	client   http.Client
+	settings *EcbSettings
+}
+
+// NewEcbRateProvider reads the reference rates of the european central bank, the historical rates cover the last
+// 90 days
+func NewEcbRateProvider(config cfg.Config, logger mon.Logger) (RateProvider, error) {
+	settings := &EcbSettings{}
+	config.UnmarshalKey(ConfigKey+".ecb", settings)
+
+	client, err := http.ProvideHttpClient(config, logger, "currency")
+	if err != nil {
+		return nil, fmt.Errorf("can not create http client: %w", err)
+	}
+
+	return NewEcbRateProviderWithInterfaces(client, settings), nil
+}
+
+func NewEcbRateProviderWithInterfaces(client http.Client, settings *EcbSettings) HistoricalRateProvider {
+	return &ecbRateProvider{
+		client:   client,
+		settings: settings,
+	}
+}
+
+func (p *ecbRateProvider) FetchLatestRates(ctx context.Context) ([]Rate, error) {
+	exchangeRateResult := ExchangeResponse{}
+
+	if err := p.get(ctx, p.settings.Url, &exchangeRateResult); err != nil {
+		return nil, err
+	}
+
+	return exchangeRateResult.Body.Content.Rates, nil
+}
+
+func (p *ecbRateProvider) FetchHistoricalRates(ctx context.Context) ([]Content, error) {
+	exchangeRateResult := HistoricalExchangeResponse{}
+
+	if err := p.get(ctx, p.settings.HistoricalUrl, &exchangeRateResult); err != nil {
+		return nil, err
+	}
+
+	return exchangeRateResult.Body.Content, nil
+}
+
+func (p *ecbRateProvider) get(ctx context.Context, url string, result interface{}) error {
+	request := p.client.NewRequest().WithUrl(url)
+
+	response, err := p.client.Get(ctx, request)
+
+	if err != nil {
+		return fmt.Errorf("error requesting exchange rates: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return fmt.Errorf("error requesting exchange rates: unexpected status code %d", response.StatusCode)
+	}
+
+	if err = xml.Unmarshal(response.Body, result); err != nil {
+		return fmt.Errorf("error unmarshalling exchange rates: %w", err)
+	}
+
+	return nil
+}
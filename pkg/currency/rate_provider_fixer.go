@@ -0,0 +1,79 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+type FixerSettings struct {
+	Url       string `cfg:"url" default:"http://data.fixer.io/api/latest"`
+	AccessKey string `cfg:"access_key"`
+}
+
+type fixerResponse struct {
+	Success bool               `json:"success"`
+	Base    string             `json:"base"`
+	Rates   map[string]float64 `json:"rates"`
+	Error   struct {
+		Code int    `json:"code"`
+		Type string `json:"type"`
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+type fixerRateProvider struct {
+	client   http.Client
+	settings *FixerSettings
+}
+
+// NewFixerRateProvider reads the latest rates of fixer.io
+func NewFixerRateProvider(config cfg.Config, logger mon.Logger) (RateProvider, error) {
+	settings := &FixerSettings{}
+	config.UnmarshalKey(ConfigKey+".fixer", settings)
+
+	client, err := http.ProvideHttpClient(config, logger, "currency")
+	if err != nil {
+		return nil, fmt.Errorf("can not create http client: %w", err)
+	}
+
+	return NewFixerRateProviderWithInterfaces(client, settings), nil
+}
+
+func NewFixerRateProviderWithInterfaces(client http.Client, settings *FixerSettings) RateProvider {
+	return &fixerRateProvider{
+		client:   client,
+		settings: settings,
+	}
+}
+
+func (p *fixerRateProvider) FetchLatestRates(ctx context.Context) ([]Rate, error) {
+	request := p.client.NewRequest().
+		WithUrl(p.settings.Url).
+		WithQueryParam("access_key", p.settings.AccessKey)
+
+	response, err := p.client.Get(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting exchange rates: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("error requesting exchange rates: unexpected status code %d", response.StatusCode)
+	}
+
+	result := fixerResponse{}
+
+	if err = json.Unmarshal(response.Body, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshalling exchange rates: %w", err)
+	}
+
+	// fixer answers errors with a status code of 200
+	if !result.Success {
+		return nil, fmt.Errorf("error requesting exchange rates: %d %s: %s", result.Error.Code, result.Error.Type, result.Error.Info)
+	}
+
+	return ratesOfEur(result.Base, result.Rates)
+}
@@ -0,0 +1,76 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/tidwall/gjson"
+)
+
+type HttpRateProviderSettings struct {
+	Url     string            `cfg:"url" validate:"required"`
+	Headers map[string]string `cfg:"headers"`
+	// currency the rates of the response are based on
+	Base string `cfg:"base" default:"EUR"`
+	// gjson path of the object with the rates by currency in the json response
+	RatesPath string `cfg:"rates_path" default:"rates"`
+}
+
+type httpRateProvider struct {
+	client   http.Client
+	settings *HttpRateProviderSettings
+}
+
+// NewHttpRateProvider reads the latest rates from a json endpoint of your own, e.g. an internal rate service
+func NewHttpRateProvider(config cfg.Config, logger mon.Logger) (RateProvider, error) {
+	settings := &HttpRateProviderSettings{}
+	config.UnmarshalKey(ConfigKey+".http", settings)
+
+	client, err := http.ProvideHttpClient(config, logger, "currency")
+	if err != nil {
+		return nil, fmt.Errorf("can not create http client: %w", err)
+	}
+
+	return NewHttpRateProviderWithInterfaces(client, settings), nil
+}
+
+func NewHttpRateProviderWithInterfaces(client http.Client, settings *HttpRateProviderSettings) RateProvider {
+	return &httpRateProvider{
+		client:   client,
+		settings: settings,
+	}
+}
+
+func (p *httpRateProvider) FetchLatestRates(ctx context.Context) ([]Rate, error) {
+	request := p.client.NewRequest().WithUrl(p.settings.Url)
+
+	for key, value := range p.settings.Headers {
+		request.WithHeader(key, value)
+	}
+
+	response, err := p.client.Get(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting exchange rates: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("error requesting exchange rates: unexpected status code %d", response.StatusCode)
+	}
+
+	result := gjson.GetBytes(response.Body, p.settings.RatesPath)
+
+	if !result.IsObject() {
+		return nil, fmt.Errorf("there are no exchange rates at %s of the response", p.settings.RatesPath)
+	}
+
+	rates := make(map[string]float64)
+
+	result.ForEach(func(key, value gjson.Result) bool {
+		rates[key.String()] = value.Float()
+		return true
+	})
+
+	return ratesOfEur(p.settings.Base, rates)
+}
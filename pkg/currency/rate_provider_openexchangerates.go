@@ -0,0 +1,69 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+type OpenExchangeRatesSettings struct {
+	Url   string `cfg:"url" default:"https://openexchangerates.org/api/latest.json"`
+	AppId string `cfg:"app_id"`
+}
+
+type openExchangeRatesResponse struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+type openExchangeRatesRateProvider struct {
+	client   http.Client
+	settings *OpenExchangeRatesSettings
+}
+
+// NewOpenExchangeRatesRateProvider reads the latest rates of openexchangerates.org, which are converted to the rates
+// of one euro as the base currency of the free plan is the dollar
+func NewOpenExchangeRatesRateProvider(config cfg.Config, logger mon.Logger) (RateProvider, error) {
+	settings := &OpenExchangeRatesSettings{}
+	config.UnmarshalKey(ConfigKey+".openexchangerates", settings)
+
+	client, err := http.ProvideHttpClient(config, logger, "currency")
+	if err != nil {
+		return nil, fmt.Errorf("can not create http client: %w", err)
+	}
+
+	return NewOpenExchangeRatesRateProviderWithInterfaces(client, settings), nil
+}
+
+func NewOpenExchangeRatesRateProviderWithInterfaces(client http.Client, settings *OpenExchangeRatesSettings) RateProvider {
+	return &openExchangeRatesRateProvider{
+		client:   client,
+		settings: settings,
+	}
+}
+
+func (p *openExchangeRatesRateProvider) FetchLatestRates(ctx context.Context) ([]Rate, error) {
+	request := p.client.NewRequest().
+		WithUrl(p.settings.Url).
+		WithQueryParam("app_id", p.settings.AppId)
+
+	response, err := p.client.Get(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting exchange rates: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("error requesting exchange rates: unexpected status code %d", response.StatusCode)
+	}
+
+	result := openExchangeRatesResponse{}
+
+	if err = json.Unmarshal(response.Body, &result); err != nil {
+		return nil, fmt.Errorf("error unmarshalling exchange rates: %w", err)
+	}
+
+	return ratesOfEur(result.Base, result.Rates)
+}
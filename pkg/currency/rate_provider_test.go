@@ -0,0 +1,157 @@
+package currency_test
+
+import (
+	"context"
+	"errors"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/currency"
+	currencyMocks "github.com/applike/gosoline/pkg/currency/mocks"
+	"github.com/applike/gosoline/pkg/http"
+	httpMock "github.com/applike/gosoline/pkg/http/mocks"
+	"github.com/applike/gosoline/pkg/mon"
+	loggerMock "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func newRateProviderFromMocks(t *testing.T, providers map[string]currency.RateProvider, order ...interface{}) currency.HistoricalRateProvider {
+	for name, provider := range providers {
+		provider := provider
+		currency.AddRateProvider(name, func(config cfg.Config, logger mon.Logger) (currency.RateProvider, error) {
+			return provider, nil
+		})
+	}
+
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigSetting("currency.providers", order))
+	assert.NoError(t, err)
+
+	provider, err := currency.NewRateProvider(config, loggerMock.NewLoggerMockedAll())
+	assert.NoError(t, err)
+
+	return provider
+}
+
+func TestRateProvider_Fallback(t *testing.T) {
+	failing := new(currencyMocks.RateProvider)
+	failing.On("FetchLatestRates", mock.Anything).Return(nil, errors.New("unavailable"))
+
+	rates := []currency.Rate{{Currency: "USD", Rate: 1.2}}
+	working := new(currencyMocks.RateProvider)
+	working.On("FetchLatestRates", mock.Anything).Return(rates, nil)
+
+	unused := new(currencyMocks.RateProvider)
+
+	provider := newRateProviderFromMocks(t, map[string]currency.RateProvider{
+		"test-failing": failing,
+		"test-working": working,
+		"test-unused":  unused,
+	}, "test-failing", "test-working", "test-unused")
+
+	actual, err := provider.FetchLatestRates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, rates, actual)
+
+	failing.AssertExpectations(t)
+	working.AssertExpectations(t)
+	unused.AssertExpectations(t)
+}
+
+func TestRateProvider_AllFailing(t *testing.T) {
+	failing := new(currencyMocks.RateProvider)
+	failing.On("FetchLatestRates", mock.Anything).Return(nil, errors.New("unavailable"))
+
+	provider := newRateProviderFromMocks(t, map[string]currency.RateProvider{
+		"test-failing": failing,
+	}, "test-failing")
+
+	_, err := provider.FetchLatestRates(context.Background())
+	assert.EqualError(t, err, "none of the rate providers returned the latest exchange rates: test-failing: unavailable")
+
+	_, err = provider.FetchHistoricalRates(context.Background())
+	assert.EqualError(t, err, "none of the rate providers supports historical exchange rates")
+}
+
+func TestRateProvider_HistoricalFallback(t *testing.T) {
+	latestOnly := new(currencyMocks.RateProvider)
+
+	content := []currency.Content{{Time: "2021-05-26", Rates: []currency.Rate{{Currency: "USD", Rate: 1.2229}}}}
+	historical := new(currencyMocks.HistoricalRateProvider)
+	historical.On("FetchHistoricalRates", mock.Anything).Return(content, nil)
+
+	provider := newRateProviderFromMocks(t, map[string]currency.RateProvider{
+		"test-latest":     latestOnly,
+		"test-historical": historical,
+	}, "test-latest", "test-historical")
+
+	actual, err := provider.FetchHistoricalRates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, content, actual)
+
+	historical.AssertExpectations(t)
+}
+
+func TestOpenExchangeRatesRateProvider_FetchLatestRates(t *testing.T) {
+	client := new(httpMock.Client)
+	client.On("NewRequest").Return(http.NewRequest(nil))
+	client.On("Get", context.Background(), mock.AnythingOfType("*http.Request")).Return(&http.Response{
+		StatusCode: 200,
+		Body:       []byte(`{"base":"USD","rates":{"EUR":0.5,"GBP":0.25,"USD":1}}`),
+	}, nil)
+
+	provider := currency.NewOpenExchangeRatesRateProviderWithInterfaces(client, &currency.OpenExchangeRatesSettings{
+		Url:   "https://openexchangerates.org/api/latest.json",
+		AppId: "app-id",
+	})
+
+	rates, err := provider.FetchLatestRates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []currency.Rate{
+		{Currency: "GBP", Rate: 0.5},
+		{Currency: "USD", Rate: 2},
+	}, rates)
+
+	client.AssertExpectations(t)
+}
+
+func TestFixerRateProvider_FetchLatestRates_Error(t *testing.T) {
+	client := new(httpMock.Client)
+	client.On("NewRequest").Return(http.NewRequest(nil))
+	client.On("Get", context.Background(), mock.AnythingOfType("*http.Request")).Return(&http.Response{
+		StatusCode: 200,
+		Body:       []byte(`{"success":false,"error":{"code":101,"type":"invalid_access_key","info":"You have not supplied a valid API Access Key."}}`),
+	}, nil)
+
+	provider := currency.NewFixerRateProviderWithInterfaces(client, &currency.FixerSettings{})
+
+	_, err := provider.FetchLatestRates(context.Background())
+
+	assert.EqualError(t, err, "error requesting exchange rates: 101 invalid_access_key: You have not supplied a valid API Access Key.")
+}
+
+func TestHttpRateProvider_FetchLatestRates(t *testing.T) {
+	client := new(httpMock.Client)
+	client.On("NewRequest").Return(http.NewRequest(nil))
+	client.On("Get", context.Background(), mock.AnythingOfType("*http.Request")).Return(&http.Response{
+		StatusCode: 200,
+		Body:       []byte(`{"data":{"rates":{"USD":1.25,"JPY":130}}}`),
+	}, nil)
+
+	provider := currency.NewHttpRateProviderWithInterfaces(client, &currency.HttpRateProviderSettings{
+		Url:       "http://rates.example.com",
+		Base:      currency.Eur,
+		RatesPath: "data.rates",
+	})
+
+	rates, err := provider.FetchLatestRates(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []currency.Rate{
+		{Currency: "JPY", Rate: 130},
+		{Currency: "USD", Rate: 1.25},
+	}, rates)
+}
@@ -6,12 +6,16 @@ import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/kvstore"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/thoas/go-funk"
+	"math/big"
+	"sort"
 	"time"
 )
 
 //go:generate mockery -name Service
 type Service interface {
 	HasCurrency(ctx context.Context, currency string) (bool, error)
+	ListCurrencies(ctx context.Context) ([]CurrencyInfo, error)
 	ToEur(ctx context.Context, value float64, from string) (float64, error)
 	ToUsd(ctx context.Context, value float64, from string) (float64, error)
 	ToCurrency(ctx context.Context, to string, value float64, from string) (float64, error)
@@ -20,10 +24,30 @@ type Service interface {
 	ToEurAtDate(ctx context.Context, value float64, from string, date time.Time) (float64, error)
 	ToUsdAtDate(ctx context.Context, value float64, from string, date time.Time) (float64, error)
 	ToCurrencyAtDate(ctx context.Context, to string, value float64, from string, date time.Time) (float64, error)
+
+	// the minor unit conversions calculate with the exact decimal rates and round the result once with the mode
+	ToCurrencyMinorUnits(ctx context.Context, to string, amount int64, from string, mode RoundingMode) (int64, error)
+	ToCurrencyMinorUnitsAtDate(ctx context.Context, to string, amount int64, from string, date time.Time, mode RoundingMode) (int64, error)
+
+	// ExchangeRatesInRange returns the rates to convert one unit of from into to for every day from start to end
+	ExchangeRatesInRange(ctx context.Context, to string, from string, start time.Time, end time.Time) ([]DatedRate, error)
+}
+
+// CurrencyInfo describes a currency the service can convert
+type CurrencyInfo struct {
+	Code      string    `json:"code"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DatedRate is the exchange rate of a single day
+type DatedRate struct {
+	Date time.Time
+	Rate float64
 }
 
 type currencyService struct {
-	store kvstore.KvStore
+	store    kvstore.KvStore
+	settings *Settings
 }
 
 func New(config cfg.Config, logger mon.Logger) (*currencyService, error) {
@@ -32,22 +56,58 @@ func New(config cfg.Config, logger mon.Logger) (*currencyService, error) {
 		return nil, fmt.Errorf("can not create kvStore: %w", err)
 	}
 
-	return NewWithInterfaces(store), nil
+	settings := ReadSettings(config)
+
+	if settings.MissingRates != MissingRatesPreviousDay && settings.MissingRates != MissingRatesError {
+		return nil, fmt.Errorf("unknown handling of missing rates %s, use %s or %s", settings.MissingRates, MissingRatesPreviousDay, MissingRatesError)
+	}
+
+	if settings.CacheTtl > 0 {
+		store = NewCachedStore(store, settings)
+	}
+
+	return NewWithInterfaces(store, settings), nil
 }
 
-func NewWithInterfaces(store kvstore.KvStore) *currencyService {
+// NewWithInterfaces creates a service for the rates stored by the updater with the same base currency
+func NewWithInterfaces(store kvstore.KvStore, settings *Settings) *currencyService {
 	return &currencyService{
-		store: store,
+		store:    store,
+		settings: settings,
 	}
 }
 
 // returns whether we support converting a given currency or not and whether an error occurred or not
 func (s *currencyService) HasCurrency(ctx context.Context, currency string) (bool, error) {
-	if currency == "EUR" {
+	if currency == s.settings.BaseCurrency {
 		return true, nil
 	}
 
-	return s.store.Contains(ctx, currency)
+	return s.store.Contains(ctx, s.settings.rateKey(currency))
+}
+
+// returns the supported currencies sorted by their code with the time of their latest rate
+func (s *currencyService) ListCurrencies(ctx context.Context) ([]CurrencyInfo, error) {
+	currencies := make(map[string]time.Time)
+
+	if _, err := s.store.Get(ctx, s.settings.supportedCurrenciesKey(), &currencies); err != nil {
+		return nil, fmt.Errorf("CurrencyService: error getting supported currencies: %w", err)
+	}
+
+	infos := make([]CurrencyInfo, 0, len(currencies))
+
+	for code, updatedAt := range currencies {
+		infos = append(infos, CurrencyInfo{
+			Code:      code,
+			UpdatedAt: updatedAt,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Code < infos[j].Code
+	})
+
+	return infos, nil
 }
 
 // returns the euro value for a given value and currency and nil if not error occurred. returns 0 and an error object otherwise.
@@ -56,13 +116,7 @@ func (s *currencyService) ToEur(ctx context.Context, value float64, from string)
 		return value, nil
 	}
 
-	exchangeRate, err := s.getExchangeRate(ctx, from)
-
-	if err != nil {
-		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate: %w", err)
-	}
-
-	return value / exchangeRate, nil
+	return s.ToCurrency(ctx, Eur, value, from)
 }
 
 // returns the us dollar value for a given value and currency and nil if not error occurred. returns 0 and an error object otherwise.
@@ -80,41 +134,16 @@ func (s *currencyService) ToCurrency(ctx context.Context, to string, value float
 		return value, nil
 	}
 
-	exchangeRate, err := s.getExchangeRate(ctx, to)
-
-	if err != nil {
-		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate: %w", err)
-	}
-
-	eur, err := s.ToEur(ctx, value, from)
-
-	if err != nil {
-		return 0, fmt.Errorf("CurrencyService: error converting to eur: %w", err)
-	}
-
-	return eur * exchangeRate, nil
-}
-
-func (s *currencyService) getExchangeRate(ctx context.Context, to string) (float64, error) {
-	var exchangeRate float64
-	exists, err := s.store.Get(ctx, to, &exchangeRate)
-
-	if err != nil {
-		return 0, fmt.Errorf("CurrencyService: error getting exchange rate: %w", err)
-	} else if !exists {
-		return 0, fmt.Errorf("CurrencyService: currency not found: %w", err)
-	}
-
-	return exchangeRate, nil
+	return s.convert(ctx, to, value, from, s.settings.rateKey)
 }
 
 // returns whether we support converting a given currency at the given time or not and whether an error occurred or not
 func (s *currencyService) HasCurrencyAtDate(ctx context.Context, currency string, date time.Time) (bool, error) {
-	if currency == "EUR" {
+	if currency == s.settings.BaseCurrency {
 		return true, nil
 	}
 
-	key := historicalRateKey(date, currency)
+	key := s.settings.historicalRateKey(date, currency)
 	return s.store.Contains(ctx, key)
 }
 
@@ -124,14 +153,7 @@ func (s *currencyService) ToEurAtDate(ctx context.Context, value float64, from s
 		return value, nil
 	}
 
-	key := historicalRateKey(date, from)
-	exchangeRate, err := s.getExchangeRate(ctx, key)
-
-	if err != nil {
-		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate historically: %w", err)
-	}
-
-	return value / exchangeRate, nil
+	return s.ToCurrencyAtDate(ctx, Eur, value, from, date)
 }
 
 // returns the us dollar value for a given value and currency at the given time and nil if not error occurred. returns 0 and an error object otherwise.
@@ -149,18 +171,185 @@ func (s *currencyService) ToCurrencyAtDate(ctx context.Context, to string, value
 		return value, nil
 	}
 
-	key := historicalRateKey(date, to)
-	exchangeRate, err := s.getExchangeRate(ctx, key)
+	return s.convert(ctx, to, value, from, func(currency string) string {
+		return s.settings.historicalRateKey(date, currency)
+	})
+}
+
+// returns the amount in minor units (e.g. cents) of the currency given in the to parameter for a given amount in minor units of the currency given in the from parameter, rounded with the given mode. returns 0 and an error object otherwise.
+func (s *currencyService) ToCurrencyMinorUnits(ctx context.Context, to string, amount int64, from string, mode RoundingMode) (int64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	return s.convertMinorUnits(ctx, to, amount, from, mode, s.settings.rateKey)
+}
+
+// returns the amount in minor units of the currency given in the to parameter for a given amount in minor units of the currency given in the from parameter at the given time, rounded with the given mode. returns 0 and an error object otherwise.
+func (s *currencyService) ToCurrencyMinorUnitsAtDate(ctx context.Context, to string, amount int64, from string, date time.Time, mode RoundingMode) (int64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	return s.convertMinorUnits(ctx, to, amount, from, mode, func(currency string) string {
+		return s.settings.historicalRateKey(date, currency)
+	})
+}
+
+func (s *currencyService) convertMinorUnits(ctx context.Context, to string, amount int64, from string, mode RoundingMode, key func(currency string) string) (int64, error) {
+	fromRate, err := s.getBaseRatRate(ctx, from, key)
+
+	if err != nil {
+		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate of %s: %w", from, err)
+	}
+
+	toRate, err := s.getBaseRatRate(ctx, to, key)
+
+	if err != nil {
+		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate of %s: %w", to, err)
+	}
+
+	rate := new(big.Rat).Quo(toRate, fromRate)
+	converted, err := convertMinorUnits(amount, from, to, rate, mode)
+
+	if err != nil {
+		return 0, fmt.Errorf("CurrencyService: %w", err)
+	}
+
+	return converted, nil
+}
+
+func (s *currencyService) getBaseRatRate(ctx context.Context, currency string, key func(currency string) string) (*big.Rat, error) {
+	if currency == s.settings.BaseCurrency {
+		return big.NewRat(1, 1), nil
+	}
+
+	rate, err := s.getExchangeRate(ctx, key(currency))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rateToRat(rate)
+}
+
+// returns the rates to convert one unit of the currency given in the from parameter into the currency given in the to parameter for every day from start to end. all rates are fetched from the store at once, missing dates are handled as configured in the settings.
+func (s *currencyService) ExchangeRatesInRange(ctx context.Context, to string, from string, start time.Time, end time.Time) ([]DatedRate, error) {
+	start = truncateToDay(start)
+	end = truncateToDay(end)
+
+	if end.Before(start) {
+		return nil, fmt.Errorf("CurrencyService: the end %s of the range is before the start %s", end.Format(YMDLayout), start.Format(YMDLayout))
+	}
+
+	lookback := 0
+
+	if s.settings.MissingRates == MissingRatesPreviousDay {
+		lookback = s.settings.MissingRatesMaxDays
+	}
+
+	currencies := make([]string, 0, 2)
+
+	for _, currency := range []string{from, to} {
+		if currency != s.settings.BaseCurrency && !funk.ContainsString(currencies, currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+
+	keys := make([]string, 0)
+
+	for day := start.AddDate(0, 0, -lookback); !day.After(end); day = day.AddDate(0, 0, 1) {
+		for _, currency := range currencies {
+			keys = append(keys, s.settings.historicalRateKey(day, currency))
+		}
+	}
+
+	stored := make(map[string]float64, len(keys))
+
+	if len(keys) > 0 {
+		if _, err := s.store.GetBatch(ctx, keys, stored); err != nil {
+			return nil, fmt.Errorf("CurrencyService: error getting exchange rates: %w", err)
+		}
+	}
+
+	rates := make([]DatedRate, 0)
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		fromRate, err := s.getRangeRate(stored, from, day, lookback)
+
+		if err != nil {
+			return nil, err
+		}
+
+		toRate, err := s.getRangeRate(stored, to, day, lookback)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rates = append(rates, DatedRate{
+			Date: day,
+			Rate: toRate / fromRate,
+		})
+	}
+
+	return rates, nil
+}
+
+// getRangeRate returns the rate of the currency at the date or of one of the lookback days before it
+func (s *currencyService) getRangeRate(stored map[string]float64, currency string, date time.Time, lookback int) (float64, error) {
+	if currency == s.settings.BaseCurrency {
+		return 1, nil
+	}
+
+	for i := 0; i <= lookback; i++ {
+		if rate, ok := stored[s.settings.historicalRateKey(date.AddDate(0, 0, -i), currency)]; ok {
+			return rate, nil
+		}
+	}
+
+	return 0, fmt.Errorf("CurrencyService: no exchange rate of %s at %s", currency, date.Format(YMDLayout))
+}
+
+// convert converts the value to the base currency and from there to the target currency with the rates stored at
+// the keys of the currencies
+func (s *currencyService) convert(ctx context.Context, to string, value float64, from string, key func(currency string) string) (float64, error) {
+	fromRate, err := s.getBaseRate(ctx, from, key)
 
 	if err != nil {
-		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate historically: %w", err)
+		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate of %s: %w", from, err)
 	}
 
-	eur, err := s.ToEurAtDate(ctx, value, from, date)
+	toRate, err := s.getBaseRate(ctx, to, key)
 
 	if err != nil {
-		return 0, fmt.Errorf("CurrencyService: error converting to eur historically: %w", err)
+		return 0, fmt.Errorf("CurrencyService: error parsing exchange rate of %s: %w", to, err)
 	}
 
-	return eur * exchangeRate, nil
+	return value / fromRate * toRate, nil
+}
+
+func (s *currencyService) getBaseRate(ctx context.Context, currency string, key func(currency string) string) (float64, error) {
+	if currency == s.settings.BaseCurrency {
+		return 1, nil
+	}
+
+	return s.getExchangeRate(ctx, key(currency))
+}
+
+func (s *currencyService) getExchangeRate(ctx context.Context, to string) (float64, error) {
+	var exchangeRate float64
+	exists, err := s.store.Get(ctx, to, &exchangeRate)
+
+	if err != nil {
+		return 0, fmt.Errorf("CurrencyService: error getting exchange rate: %w", err)
+	} else if !exists {
+		return 0, fmt.Errorf("CurrencyService: currency not found: %w", err)
+	}
+
+	return exchangeRate, nil
+}
+
+func truncateToDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 }
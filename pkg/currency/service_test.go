@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"github.com/applike/gosoline/pkg/currency"
+	currencyMocks "github.com/applike/gosoline/pkg/currency/mocks"
 	"github.com/applike/gosoline/pkg/http"
 	httpMock "github.com/applike/gosoline/pkg/http/mocks"
 	kvStoreMock "github.com/applike/gosoline/pkg/kvstore/mocks"
 	loggerMock "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"strings"
 	"testing"
 	"time"
 )
@@ -89,7 +91,7 @@ func TestCurrencyService_ToEur_Calculation(t *testing.T) {
 		*f = 1.09
 	}).Return(true, nil)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	valueUsd := 1.09
 	valueEur := 1.0
@@ -113,7 +115,7 @@ func TestCurrencyService_ToUsd_Calculation(t *testing.T) {
 		*ptr = 1.09
 	}).Return(true, nil)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	valueUsd := 1.09
 	valueEur := 1.0
@@ -135,18 +137,26 @@ func TestUpdaterService_EnsureRecentExchangeRates(t *testing.T) {
 		*ptr = time.Now().AddDate(-1, 0, 0)
 	}).Return(true, nil)
 	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), currency.ExchangeRateDateKey, mock.AnythingOfType("time.Time")).Return(nil)
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), currency.SupportedCurrenciesKey, mock.AnythingOfType("*map[string]time.Time")).Return(false, nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), currency.SupportedCurrenciesKey, mock.AnythingOfType("map[string]time.Time")).Return(nil)
 	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), mock.AnythingOfType("string"), mock.AnythingOfType("float64")).Return(nil)
 
 	r := &http.Response{
-		Body: []byte(response),
+		Body:       []byte(response),
+		StatusCode: 200,
 	}
 
 	client.On("NewRequest").Return(http.NewRequest(nil))
 	client.On("Get", context.Background(), mock.AnythingOfType("*http.Request")).Return(r, nil)
 
-	service := currency.NewUpdaterWithInterfaces(logger, store, client)
+	provider := currency.NewEcbRateProviderWithInterfaces(client, &currency.EcbSettings{})
+	service, err := currency.NewUpdaterWithInterfaces(logger, store, provider, &currency.Settings{
+		BaseCurrency:    currency.Eur,
+		RefreshInterval: 8 * time.Hour,
+	})
+	assert.NoError(t, err)
 
-	err := service.EnsureRecentExchangeRates(context.TODO())
+	err = service.EnsureRecentExchangeRates(context.TODO())
 
 	assert.NoError(t, err)
 
@@ -159,7 +169,7 @@ func TestCurrencyService_HasCurrency(t *testing.T) {
 
 	store.On("Contains", mock.AnythingOfType("*context.emptyCtx"), "USD").Return(true, nil).Times(1)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	hasCurrency, err := service.HasCurrency(context.Background(), "USD")
 
@@ -174,7 +184,7 @@ func TestCurrencyService_HasCurrencyAtDate(t *testing.T) {
 
 	store.On("Contains", mock.AnythingOfType("*context.emptyCtx"), "2021-01-02-USD").Return(true, nil).Times(1)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	date := time.Date(2021, time.January, 2, 0, 0, 0, 0, time.Local)
 	hasCurrency, err := service.HasCurrencyAtDate(context.Background(), "USD", date)
@@ -190,7 +200,7 @@ func TestCurrencyService_HasCurrencyAtDate_NotThere(t *testing.T) {
 
 	store.On("Contains", mock.AnythingOfType("*context.emptyCtx"), "2021-01-02-USD").Return(false, nil).Times(1)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	date := time.Date(2021, time.January, 2, 0, 0, 0, 0, time.Local)
 	hasCurrency, err := service.HasCurrencyAtDate(context.Background(), "USD", date)
@@ -206,7 +216,7 @@ func TestCurrencyService_HasCurrencyAtDate_Error(t *testing.T) {
 
 	store.On("Contains", mock.AnythingOfType("*context.emptyCtx"), historicalRateKey).Return(false, errors.New("lookup error")).Times(1)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	hasCurrency, err := service.HasCurrencyAtDate(context.Background(), "USD", historicalRateDate)
 
@@ -224,7 +234,7 @@ func TestCurrencyService_ToEurAtDate_Calculation(t *testing.T) {
 		*f = 1.09
 	}).Return(true, nil)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	valueUsd := 1.09
 	valueEur := 1.0
@@ -244,7 +254,7 @@ func TestCurrencyService_ToUsdAtDate_Calculation(t *testing.T) {
 		*ptr = 1.09
 	}).Return(true, nil)
 
-	service := currency.NewWithInterfaces(store)
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
 
 	valueUsd := 1.09
 	valueEur := 1.0
@@ -274,18 +284,298 @@ func TestUpdaterService_ImportHistoricalExchangeRates(t *testing.T) {
 	store.On("PutBatch", mock.AnythingOfType("*context.emptyCtx"), keyValyes).Return(nil)
 
 	r := &http.Response{
-		Body: []byte(historicalResponse),
+		Body:       []byte(historicalResponse),
+		StatusCode: 200,
 	}
 
 	client.On("NewRequest").Return(http.NewRequest(nil))
 	client.On("Get", context.Background(), mock.AnythingOfType("*http.Request")).Return(r, nil)
 
-	service := currency.NewUpdaterWithInterfaces(logger, store, client)
+	provider := currency.NewEcbRateProviderWithInterfaces(client, &currency.EcbSettings{})
+	service, err := currency.NewUpdaterWithInterfaces(logger, store, provider, &currency.Settings{
+		BaseCurrency:    currency.Eur,
+		RefreshInterval: 8 * time.Hour,
+	})
+	assert.NoError(t, err)
 
-	err := service.ImportHistoricalExchangeRates(context.TODO())
+	err = service.ImportHistoricalExchangeRates(context.TODO())
 
 	assert.NoError(t, err)
 
 	store.AssertExpectations(t)
 	client.AssertExpectations(t)
 }
+
+func TestUpdaterService_EnsureRecentExchangeRates_Schedule(t *testing.T) {
+	logger := loggerMock.NewLoggerMockedAll()
+	store := new(kvStoreMock.KvStore)
+	provider := new(currencyMocks.HistoricalRateProvider)
+
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), currency.ExchangeRateDateKey, mock.AnythingOfType("*time.Time")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*time.Time)
+		*ptr = time.Now().Add(-time.Hour)
+	}).Return(true, nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), currency.ExchangeRateDateKey, mock.AnythingOfType("time.Time")).Return(nil)
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), currency.SupportedCurrenciesKey, mock.AnythingOfType("*map[string]time.Time")).Return(false, nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), currency.SupportedCurrenciesKey, mock.AnythingOfType("map[string]time.Time")).Return(nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), mock.AnythingOfType("string"), mock.AnythingOfType("float64")).Return(nil)
+
+	provider.On("FetchLatestRates", mock.AnythingOfType("*context.emptyCtx")).Return([]currency.Rate{{Currency: "USD", Rate: 1.25}}, nil)
+
+	service, err := currency.NewUpdaterWithInterfaces(logger, store, provider, &currency.Settings{
+		BaseCurrency:    currency.Eur,
+		RefreshInterval: 8 * time.Hour,
+		RefreshSchedule: "*/5 * * * *",
+		RefreshTimezone: "Europe/Berlin",
+	})
+	assert.NoError(t, err)
+
+	err = service.EnsureRecentExchangeRates(context.Background())
+
+	assert.NoError(t, err)
+
+	store.AssertExpectations(t)
+	provider.AssertExpectations(t)
+}
+
+func TestUpdaterService_EnsureRecentExchangeRates_BaseCurrency(t *testing.T) {
+	logger := loggerMock.NewLoggerMockedAll()
+	store := new(kvStoreMock.KvStore)
+	provider := new(currencyMocks.HistoricalRateProvider)
+
+	// the rates of another base currency than EUR don't overwrite the ones stored with EUR as base
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), "USD-"+currency.ExchangeRateDateKey, mock.AnythingOfType("*time.Time")).Return(false, nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), "USD-"+currency.ExchangeRateDateKey, mock.AnythingOfType("time.Time")).Return(nil)
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), "USD-"+currency.SupportedCurrenciesKey, mock.AnythingOfType("*map[string]time.Time")).Return(false, nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), "USD-"+currency.SupportedCurrenciesKey, mock.AnythingOfType("map[string]time.Time")).Return(nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), "USD-EUR", 0.8).Return(nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), "USD-GBP", 0.72).Return(nil)
+	store.On("Put", mock.AnythingOfType("*context.emptyCtx"), mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "USD-20")
+	}), mock.AnythingOfType("float64")).Return(nil)
+
+	provider.On("FetchLatestRates", mock.AnythingOfType("*context.emptyCtx")).Return([]currency.Rate{
+		{Currency: "USD", Rate: 1.25},
+		{Currency: "GBP", Rate: 0.9},
+	}, nil)
+
+	service, err := currency.NewUpdaterWithInterfaces(logger, store, provider, &currency.Settings{
+		BaseCurrency:    currency.Usd,
+		RefreshInterval: 8 * time.Hour,
+	})
+	assert.NoError(t, err)
+
+	err = service.EnsureRecentExchangeRates(context.Background())
+
+	assert.NoError(t, err)
+
+	store.AssertExpectations(t)
+	provider.AssertExpectations(t)
+}
+
+func TestCurrencyService_ToEur_BaseCurrency(t *testing.T) {
+	store := new(kvStoreMock.KvStore)
+
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), "USD-EUR", mock.AnythingOfType("*float64")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*float64)
+		*ptr = 0.8
+	}).Return(true, nil)
+
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Usd})
+
+	converted, err := service.ToEur(context.Background(), 10, currency.Usd)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8.0, converted)
+
+	hasCurrency, err := service.HasCurrency(context.Background(), currency.Usd)
+
+	assert.NoError(t, err)
+	assert.True(t, hasCurrency, "the base currency should always be supported")
+}
+
+func TestCurrencyService_ToCurrencyMinorUnits(t *testing.T) {
+	store := new(kvStoreMock.KvStore)
+
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), "USD", mock.AnythingOfType("*float64")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*float64)
+		*ptr = 1.1289
+	}).Return(true, nil)
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), "JPY", mock.AnythingOfType("*float64")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*float64)
+		*ptr = 122.44
+	}).Return(true, nil)
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), "GBP", mock.AnythingOfType("*float64")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*float64)
+		*ptr = 1.25
+	}).Return(true, nil)
+
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
+
+	tests := map[string]struct {
+		to       string
+		amount   int64
+		from     string
+		mode     currency.RoundingMode
+		expected int64
+	}{
+		"half up":               {to: currency.Usd, amount: 1000, from: currency.Eur, mode: currency.RoundHalfUp, expected: 1129},
+		"down":                  {to: currency.Usd, amount: 1000, from: currency.Eur, mode: currency.RoundDown, expected: 1128},
+		"up":                    {to: currency.Usd, amount: 1001, from: currency.Eur, mode: currency.RoundUp, expected: 1131},
+		"zero digits":           {to: "JPY", amount: 1050, from: currency.Eur, mode: currency.RoundHalfUp, expected: 1286},
+		"from zero digits":      {to: currency.Eur, amount: 12244, from: "JPY", mode: currency.RoundHalfUp, expected: 10000},
+		"tie half up":           {to: "GBP", amount: 2, from: currency.Eur, mode: currency.RoundHalfUp, expected: 3},
+		"tie half even":         {to: "GBP", amount: 2, from: currency.Eur, mode: currency.RoundHalfEven, expected: 2},
+		"tie half even odd":     {to: "GBP", amount: 6, from: currency.Eur, mode: currency.RoundHalfEven, expected: 8},
+		"negative tie half up":  {to: "GBP", amount: -2, from: currency.Eur, mode: currency.RoundHalfUp, expected: -3},
+		"negative down":         {to: "GBP", amount: -2, from: currency.Eur, mode: currency.RoundDown, expected: -2},
+		"same currency":         {to: currency.Usd, amount: 1234, from: currency.Usd, mode: currency.RoundHalfUp, expected: 1234},
+		"without base currency": {to: "GBP", amount: 1129, from: currency.Usd, mode: currency.RoundHalfUp, expected: 1250},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			converted, err := service.ToCurrencyMinorUnits(context.Background(), test.to, test.amount, test.from, test.mode)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, converted)
+		})
+	}
+}
+
+func TestCurrencyService_ToCurrencyMinorUnitsAtDate(t *testing.T) {
+	store := new(kvStoreMock.KvStore)
+
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), historicalRateKey, mock.AnythingOfType("*float64")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*float64)
+		*ptr = 1.09
+	}).Return(true, nil)
+
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
+
+	converted, err := service.ToCurrencyMinorUnitsAtDate(context.Background(), currency.Eur, 1000, currency.Usd, historicalRateDate, currency.RoundHalfEven)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(917), converted)
+
+	store.AssertExpectations(t)
+}
+
+func TestCurrencyService_ExchangeRatesInRange(t *testing.T) {
+	store := new(kvStoreMock.KvStore)
+
+	keys := []string{"2021-01-01-USD", "2021-01-02-USD", "2021-01-03-USD", "2021-01-04-USD"}
+	store.On("GetBatch", mock.AnythingOfType("*context.emptyCtx"), keys, mock.AnythingOfType("map[string]float64")).Run(func(args mock.Arguments) {
+		values := args.Get(2).(map[string]float64)
+		values["2021-01-01-USD"] = 1.2
+		values["2021-01-02-USD"] = 1.25
+		values["2021-01-04-USD"] = 1.0
+	}).Return([]interface{}{"2021-01-03-USD"}, nil)
+
+	service := currency.NewWithInterfaces(store, &currency.Settings{
+		BaseCurrency:        currency.Eur,
+		MissingRates:        currency.MissingRatesPreviousDay,
+		MissingRatesMaxDays: 1,
+	})
+
+	start := time.Date(2021, time.January, 2, 12, 0, 0, 0, time.Local)
+	end := time.Date(2021, time.January, 4, 0, 0, 0, 0, time.Local)
+
+	rates, err := service.ExchangeRatesInRange(context.Background(), currency.Eur, currency.Usd, start, end)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []currency.DatedRate{
+		{Date: time.Date(2021, time.January, 2, 0, 0, 0, 0, time.Local), Rate: 0.8},
+		{Date: time.Date(2021, time.January, 3, 0, 0, 0, 0, time.Local), Rate: 0.8},
+		{Date: time.Date(2021, time.January, 4, 0, 0, 0, 0, time.Local), Rate: 1.0},
+	}, rates)
+
+	store.AssertExpectations(t)
+}
+
+func TestCurrencyService_ExchangeRatesInRange_MissingRateError(t *testing.T) {
+	store := new(kvStoreMock.KvStore)
+
+	keys := []string{"2021-01-02-USD", "2021-01-03-USD"}
+	store.On("GetBatch", mock.AnythingOfType("*context.emptyCtx"), keys, mock.AnythingOfType("map[string]float64")).Run(func(args mock.Arguments) {
+		values := args.Get(2).(map[string]float64)
+		values["2021-01-02-USD"] = 1.25
+	}).Return([]interface{}{"2021-01-03-USD"}, nil)
+
+	service := currency.NewWithInterfaces(store, &currency.Settings{
+		BaseCurrency: currency.Eur,
+		MissingRates: currency.MissingRatesError,
+	})
+
+	start := time.Date(2021, time.January, 2, 0, 0, 0, 0, time.Local)
+	end := time.Date(2021, time.January, 3, 0, 0, 0, 0, time.Local)
+
+	_, err := service.ExchangeRatesInRange(context.Background(), currency.Eur, currency.Usd, start, end)
+
+	assert.EqualError(t, err, "CurrencyService: no exchange rate of USD at 2021-01-03")
+
+	store.AssertExpectations(t)
+}
+
+func TestCurrencyService_ListCurrencies(t *testing.T) {
+	store := new(kvStoreMock.KvStore)
+
+	updatedAt := time.Date(2021, time.January, 2, 16, 5, 0, 0, time.UTC)
+	store.On("Get", mock.AnythingOfType("*context.emptyCtx"), currency.SupportedCurrenciesKey, mock.AnythingOfType("*map[string]time.Time")).Run(func(args mock.Arguments) {
+		ptr := args.Get(2).(*map[string]time.Time)
+		*ptr = map[string]time.Time{
+			"USD": updatedAt,
+			"EUR": updatedAt,
+			"GBP": updatedAt.AddDate(0, 0, -1),
+		}
+	}).Return(true, nil)
+
+	service := currency.NewWithInterfaces(store, &currency.Settings{BaseCurrency: currency.Eur})
+
+	currencies, err := service.ListCurrencies(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []currency.CurrencyInfo{
+		{Code: "EUR", UpdatedAt: updatedAt},
+		{Code: "GBP", UpdatedAt: updatedAt.AddDate(0, 0, -1)},
+		{Code: "USD", UpdatedAt: updatedAt},
+	}, currencies)
+
+	store.AssertExpectations(t)
+}
+
+func TestUpdaterService_ImportHistoricalExchangeRates_LongRange(t *testing.T) {
+	logger := loggerMock.NewLoggerMockedAll()
+	store := new(kvStoreMock.KvStore)
+	provider := new(currencyMocks.HistoricalRateProvider)
+
+	// a year of rates with gaps at both ends of the range
+	provider.On("FetchHistoricalRates", context.Background()).Return([]currency.Content{
+		{Time: "2020-01-01", Rates: []currency.Rate{{Currency: "USD", Rate: 1.1}}},
+		{Time: "2020-01-03", Rates: []currency.Rate{{Currency: "USD", Rate: 1.2}}},
+		{Time: "2020-12-29", Rates: []currency.Rate{{Currency: "USD", Rate: 1.3}}},
+		{Time: "2020-12-31", Rates: []currency.Rate{{Currency: "USD", Rate: 1.4}}},
+	}, nil)
+
+	var stored map[string]float64
+	store.On("PutBatch", context.Background(), mock.AnythingOfType("map[string]float64")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(map[string]float64)
+	}).Return(nil)
+
+	service, err := currency.NewUpdaterWithInterfaces(logger, store, provider, &currency.Settings{
+		BaseCurrency:    currency.Eur,
+		RefreshInterval: 8 * time.Hour,
+	})
+	assert.NoError(t, err)
+
+	err = service.ImportHistoricalExchangeRates(context.Background())
+	assert.NoError(t, err)
+
+	assert.Len(t, stored, 366, "every day of 2020 should have a rate")
+	assert.Equal(t, 1.1, stored["2020-01-02-USD"])
+	assert.Equal(t, 1.3, stored["2020-12-30-USD"])
+	assert.Equal(t, 1.4, stored["2020-12-31-USD"])
+
+	store.AssertExpectations(t)
+	provider.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package currency
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/cron"
+	"time"
+)
+
+const (
+	ConfigKey = "currency"
+
+	// MissingRatesPreviousDay uses the rate of the last day before a missing date which has a rate
+	MissingRatesPreviousDay = "previous_day"
+	// MissingRatesError fails the range query if the rate of a date is missing
+	MissingRatesError = "error"
+)
+
+type Settings struct {
+	// the rate providers are asked in this order until one of them returns the rates
+	Providers []string `cfg:"providers" default:"ecb"`
+	// the rates are stored as the rates of one unit of the base currency, the keys of other base currencies than EUR
+	// are prefixed with it, so the updater imports all rates again after the base currency changed
+	BaseCurrency string `cfg:"base_currency" default:"EUR"`
+	// the latest rates are fetched again once they are older than the refresh interval
+	RefreshInterval time.Duration `cfg:"refresh_interval" default:"8h"`
+	// cron expression of additional refreshes, e.g. "5 16 * * 1-5" right after the ecb published the rates
+	RefreshSchedule string `cfg:"refresh_schedule"`
+	// time zone the refresh schedule is evaluated in
+	RefreshTimezone string `cfg:"refresh_timezone" default:"Europe/Berlin"`
+	// how often the module checks if the rates have to be refreshed
+	CheckInterval time.Duration `cfg:"check_interval" default:"1h"`
+	// days of historical rates imported at the start, 0 keeps all days returned by the provider
+	HistoricalDays int `cfg:"historical_days" default:"90"`
+	// how the range queries handle dates without a rate, either previous_day or error
+	MissingRates string `cfg:"missing_rates" default:"previous_day"`
+	// days the range queries look back for a previous rate of a missing date
+	MissingRatesMaxDays int `cfg:"missing_rates_max_days" default:"7"`
+	// how long the service keeps the rates in process before reading them from the store again, 0 disables the cache
+	CacheTtl time.Duration `cfg:"cache_ttl" default:"1m"`
+	// maximum number of rates kept in process
+	CacheSize int64 `cfg:"cache_size" default:"5000"`
+}
+
+func ReadSettings(config cfg.Config) *Settings {
+	settings := &Settings{}
+	config.UnmarshalKey(ConfigKey, settings)
+
+	return settings
+}
+
+// refreshSchedule returns the parsed refresh schedule and its time zone or nil if there is none
+func (s *Settings) refreshSchedule() (cron.Schedule, *time.Location, error) {
+	if s.RefreshSchedule == "" {
+		return nil, nil, nil
+	}
+
+	schedule, err := cron.ParseSchedule(s.RefreshSchedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can not parse the refresh schedule: %w", err)
+	}
+
+	location, err := time.LoadLocation(s.RefreshTimezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can not load the time zone %s of the refresh schedule: %w", s.RefreshTimezone, err)
+	}
+
+	return schedule, location, nil
+}
+
+// keyPrefix separates the rates of different base currencies in the store. The rates of EUR are stored without a
+// prefix, so the rates stored before the base currency was configurable stay valid.
+func (s *Settings) keyPrefix() string {
+	if s.BaseCurrency == Eur || s.BaseCurrency == "" {
+		return ""
+	}
+
+	return s.BaseCurrency + "-"
+}
+
+func (s *Settings) rateKey(currency string) string {
+	return s.keyPrefix() + currency
+}
+
+func (s *Settings) historicalRateKey(date time.Time, currency string) string {
+	return s.keyPrefix() + historicalRateKey(date, currency)
+}
+
+func (s *Settings) exchangeRateDateKey() string {
+	return s.keyPrefix() + ExchangeRateDateKey
+}
+
+func (s *Settings) supportedCurrenciesKey() string {
+	return s.keyPrefix() + SupportedCurrenciesKey
+}
@@ -2,20 +2,19 @@ package currency
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
-	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/cron"
 	"github.com/applike/gosoline/pkg/kvstore"
 	"github.com/applike/gosoline/pkg/mon"
 	"time"
 )
 
+// the keys are prefixed with the base currency if it isn't EUR
 const (
-	ExchangeRateRefresh       = 8 * time.Hour
-	ExchangeRateUrl           = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
-	HistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
-	ExchangeRateDateKey       = "currency_exchange_last_refresh"
+	ExchangeRateDateKey = "currency_exchange_last_refresh"
+	// SupportedCurrenciesKey stores the time of the last rate of every currency
+	SupportedCurrenciesKey = "currency_supported_currencies"
 )
 
 const YMDLayout = "2006-01-02"
@@ -27,9 +26,12 @@ type UpdaterService interface {
 }
 
 type updaterService struct {
-	logger mon.Logger
-	http   http.Client
-	store  kvstore.KvStore
+	logger   mon.Logger
+	provider HistoricalRateProvider
+	store    kvstore.KvStore
+	settings *Settings
+	schedule cron.Schedule
+	location *time.Location
 }
 
 func NewUpdater(config cfg.Config, logger mon.Logger) (UpdaterService, error) {
@@ -40,17 +42,28 @@ func NewUpdater(config cfg.Config, logger mon.Logger) (UpdaterService, error) {
 		return nil, fmt.Errorf("can not create kvStore: %w", err)
 	}
 
-	httpClient := http.NewHttpClient(config, logger)
+	provider, err := NewRateProvider(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("can not create rate provider: %w", err)
+	}
 
-	return NewUpdaterWithInterfaces(logger, store, httpClient), nil
+	return NewUpdaterWithInterfaces(logger, store, provider, ReadSettings(config))
 }
 
-func NewUpdaterWithInterfaces(logger mon.Logger, store kvstore.KvStore, httpClient http.Client) UpdaterService {
-	return &updaterService{
-		logger: logger,
-		store:  store,
-		http:   httpClient,
+func NewUpdaterWithInterfaces(logger mon.Logger, store kvstore.KvStore, provider HistoricalRateProvider, settings *Settings) (UpdaterService, error) {
+	schedule, location, err := settings.refreshSchedule()
+	if err != nil {
+		return nil, err
 	}
+
+	return &updaterService{
+		logger:   logger,
+		store:    store,
+		provider: provider,
+		settings: settings,
+		schedule: schedule,
+		location: location,
+	}, nil
 }
 
 func (s *updaterService) EnsureRecentExchangeRates(ctx context.Context) error {
@@ -59,15 +72,19 @@ func (s *updaterService) EnsureRecentExchangeRates(ctx context.Context) error {
 	}
 
 	s.logger.Info("requesting exchange rates")
-	rates, err := s.getCurrencyRates(ctx)
+	rates, err := s.provider.FetchLatestRates(ctx)
 
 	if err != nil {
 		return fmt.Errorf("error getting currency exchange rates: %w", err)
 	}
 
+	if rates, err = toBaseCurrency(s.settings.BaseCurrency, rates); err != nil {
+		return fmt.Errorf("error converting currency exchange rates: %w", err)
+	}
+
 	now := time.Now()
 	for _, rate := range rates {
-		err := s.store.Put(ctx, rate.Currency, rate.Rate)
+		err := s.store.Put(ctx, s.settings.rateKey(rate.Currency), rate.Rate)
 
 		if err != nil {
 			return fmt.Errorf("error setting exchange rate: %w", err)
@@ -75,15 +92,19 @@ func (s *updaterService) EnsureRecentExchangeRates(ctx context.Context) error {
 
 		s.logger.Infof("currency: %s, rate: %f", rate.Currency, rate.Rate)
 
-		historicalRateKey := historicalRateKey(now, rate.Currency)
+		historicalRateKey := s.settings.historicalRateKey(now, rate.Currency)
 		err = s.store.Put(ctx, historicalRateKey, rate.Rate)
 		if err != nil {
 			return fmt.Errorf("error setting historical exchange rate, key: %s %w", historicalRateKey, err)
 		}
 	}
 
+	if err = s.updateSupportedCurrencies(ctx, rates, now); err != nil {
+		return err
+	}
+
 	newTime := time.Now()
-	err = s.store.Put(ctx, ExchangeRateDateKey, newTime)
+	err = s.store.Put(ctx, s.settings.exchangeRateDateKey(), newTime)
 
 	if err != nil {
 		return fmt.Errorf("error setting refresh date %w", err)
@@ -93,9 +114,31 @@ func (s *updaterService) EnsureRecentExchangeRates(ctx context.Context) error {
 	return nil
 }
 
+// updateSupportedCurrencies records the time of the latest rate of every currency. Currencies the provider doesn't
+// return anymore keep the time of their last rate.
+func (s *updaterService) updateSupportedCurrencies(ctx context.Context, rates []Rate, now time.Time) error {
+	currencies := make(map[string]time.Time)
+
+	if _, err := s.store.Get(ctx, s.settings.supportedCurrenciesKey(), &currencies); err != nil {
+		return fmt.Errorf("error getting supported currencies: %w", err)
+	}
+
+	for _, rate := range rates {
+		currencies[rate.Currency] = now
+	}
+
+	currencies[s.settings.BaseCurrency] = now
+
+	if err := s.store.Put(ctx, s.settings.supportedCurrenciesKey(), currencies); err != nil {
+		return fmt.Errorf("error setting supported currencies: %w", err)
+	}
+
+	return nil
+}
+
 func (s *updaterService) needsRefresh(ctx context.Context) bool {
 	var date time.Time
-	exists, err := s.store.Get(ctx, ExchangeRateDateKey, &date)
+	exists, err := s.store.Get(ctx, s.settings.exchangeRateDateKey(), &date)
 
 	if err != nil {
 		s.logger.Info("error fetching date")
@@ -109,44 +152,38 @@ func (s *updaterService) needsRefresh(ctx context.Context) bool {
 		return true
 	}
 
-	comparisonDate := time.Now().Add(-ExchangeRateRefresh)
+	now := time.Now()
+	comparisonDate := now.Add(-s.settings.RefreshInterval)
 
 	if date.Before(comparisonDate) {
-		s.logger.Info("comparison date was more than 8 hours ago")
+		s.logger.Infof("comparison date was more than %s ago", s.settings.RefreshInterval)
 
 		return true
 	}
 
-	return false
-}
-
-func (s *updaterService) getCurrencyRates(ctx context.Context) ([]Rate, error) {
-	request := s.http.NewRequest().WithUrl(ExchangeRateUrl)
-
-	response, err := s.http.Get(ctx, request)
-
-	if err != nil {
-		return nil, fmt.Errorf("error requesting exchange rates: %w", err)
-	}
-
-	exchangeRateResult := ExchangeResponse{}
-	err = xml.Unmarshal(response.Body, &exchangeRateResult)
+	// the rates are published after the last refresh if the schedule had an activation since then
+	if s.schedule != nil && !s.schedule.Next(date.In(s.location)).After(now) {
+		s.logger.Info("the refresh schedule was due since the last refresh")
 
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling exchange rates: %w", err)
+		return true
 	}
 
-	return exchangeRateResult.Body.Content.Rates, nil
+	return false
 }
 
 func (s *updaterService) ImportHistoricalExchangeRates(ctx context.Context) error {
 	s.logger.Info("requesting historical exchange rates")
-	rates, err := s.getCurrencyRatesForLast3Months(ctx)
+	rates, err := s.provider.FetchHistoricalRates(ctx)
 
 	if err != nil {
 		return fmt.Errorf("error getting historical currency exchange rates: %w", err)
 	}
 
+	rates, err = s.filterHistoricalWindow(rates)
+	if err != nil {
+		return fmt.Errorf("error filtering historical exchange rates: %w", err)
+	}
+
 	// the API doesn't return rates for weekends and public holidays at the time of writing this,
 	// so we fill in the missing values using values from previously available days
 	rates, err = fillInGapDays(rates)
@@ -161,8 +198,13 @@ func (s *updaterService) ImportHistoricalExchangeRates(ctx context.Context) erro
 			return fmt.Errorf("error parsing time in historical exchange rates: %w", err)
 		}
 
-		for _, rate := range dayRates.Rates {
-			key := historicalRateKey(date, rate.Currency)
+		dayBaseRates, err := toBaseCurrency(s.settings.BaseCurrency, dayRates.Rates)
+		if err != nil {
+			return fmt.Errorf("error converting historical exchange rates of %s: %w", dayRates.Time, err)
+		}
+
+		for _, rate := range dayBaseRates {
+			key := s.settings.historicalRateKey(date, rate.Currency)
 			keyValues[key] = rate.Rate
 		}
 	}
@@ -176,23 +218,66 @@ func (s *updaterService) ImportHistoricalExchangeRates(ctx context.Context) erro
 	return nil
 }
 
-func (s *updaterService) getCurrencyRatesForLast3Months(ctx context.Context) ([]Content, error) {
-	request := s.http.NewRequest().WithUrl(HistoricalExchangeRateUrl)
+// filterHistoricalWindow drops the days before the configured window
+func (s *updaterService) filterHistoricalWindow(contents []Content) ([]Content, error) {
+	if s.settings.HistoricalDays <= 0 {
+		return contents, nil
+	}
 
-	response, err := s.http.Get(ctx, request)
+	earliest := time.Now().AddDate(0, 0, -s.settings.HistoricalDays).Format(YMDLayout)
+	filtered := make([]Content, 0, len(contents))
 
-	if err != nil {
-		return nil, fmt.Errorf("error requesting historical exchange rates: %w", err)
+	for _, content := range contents {
+		date, err := content.GetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		if date.Format(YMDLayout) >= earliest {
+			filtered = append(filtered, content)
+		}
 	}
 
-	exchangeRateResult := HistoricalExchangeResponse{}
-	err = xml.Unmarshal(response.Body, &exchangeRateResult)
+	return filtered, nil
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling historical exchange rates: %w", err)
+// toBaseCurrency converts the rates of one euro to the rates of one unit of the base currency
+func toBaseCurrency(base string, rates []Rate) ([]Rate, error) {
+	if base == Eur {
+		return rates, nil
 	}
 
-	return exchangeRateResult.Body.Content, nil
+	divisor := 0.0
+
+	for _, rate := range rates {
+		if rate.Currency == base {
+			divisor = rate.Rate
+		}
+	}
+
+	if divisor == 0 {
+		return nil, fmt.Errorf("there is no rate for the base currency %s", base)
+	}
+
+	converted := make([]Rate, 0, len(rates))
+
+	for _, rate := range rates {
+		if rate.Currency == base {
+			continue
+		}
+
+		converted = append(converted, Rate{
+			Currency: rate.Currency,
+			Rate:     rate.Rate / divisor,
+		})
+	}
+
+	converted = append(converted, Rate{
+		Currency: Eur,
+		Rate:     1 / divisor,
+	})
+
+	return converted, nil
 }
 
 func historicalRateKey(time time.Time, currency string) string {
@@ -218,18 +303,9 @@ func fillInGapDays(historicalContent []Content) ([]Content, error) {
 		dailyRates[date.Format(YMDLayout)] = dayRates
 	}
 
-	var date = startDate
-	var lastDay = date
-	counter := 0
-	for {
-		counter++
-		if counter > 180 {
-			break
-		}
-
-		if date.Equal(endDate) || date.After(endDate) {
-			return historicalContent, nil
-		}
+	// the whole range is filled, however long the historical window is
+	var lastDay = startDate
+	for date := startDate; date.Before(endDate); date = date.AddDate(0, 0, 1) {
 		if _, ok := dailyRates[date.Format(YMDLayout)]; !ok {
 			gapContent := dailyRates[lastDay.Format(YMDLayout)]
 			gapContent.Time = date.Format(YMDLayout)
@@ -237,8 +313,6 @@ func fillInGapDays(historicalContent []Content) ([]Content, error) {
 		} else {
 			lastDay = date
 		}
-
-		date = date.AddDate(0, 0, 1)
 	}
 
 	return historicalContent, nil
@@ -0,0 +1,337 @@
+package db_repo
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/jinzhu/gorm"
+	"github.com/jonboulle/clockwork"
+	"reflect"
+	"time"
+)
+
+// AuditAuthorSystem is the author of changes made without an authenticated subject in the context
+const AuditAuthorSystem = "system"
+
+// the timestamps change with every write and are part of the audit record itself
+var auditIgnoredFields = map[string]bool{
+	"UpdatedAt": true,
+	"CreatedAt": true,
+}
+
+type AuditSettings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// the records are written to this stream output if set, to the table otherwise
+	Output     string `cfg:"output"`
+	Table      string `cfg:"table" default:"audit_records"`
+	Connection string `cfg:"connection" default:"default"`
+}
+
+type AuditChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditChanges are the changed fields of a model by their json name. They are stored as json in the audit table.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("can not scan audit changes from %T", value)
+	}
+}
+
+// AuditRecord is written for every change of an audited repository. The audit table needs the columns id,
+// model_id, record_id, action, author, changes (json or text) and created_at.
+type AuditRecord struct {
+	Id        *uint        `gorm:"primary_key;AUTO_INCREMENT" json:"-"`
+	ModelId   string       `json:"modelId"`
+	RecordId  uint         `json:"recordId"`
+	Action    string       `json:"action"`
+	Author    string       `json:"author"`
+	Changes   AuditChanges `json:"changes"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+//go:generate mockery -name AuditWriter
+type AuditWriter interface {
+	Write(ctx context.Context, record *AuditRecord) error
+}
+
+type auditTableWriter struct {
+	orm        *gorm.DB
+	connection string
+	table      string
+}
+
+// NewAuditTableWriter inserts the records into the table. Inside of a transaction on the same connection, the record
+// is part of it.
+func NewAuditTableWriter(orm *gorm.DB, connection string, table string) *auditTableWriter {
+	return &auditTableWriter{
+		orm:        orm,
+		connection: connection,
+		table:      table,
+	}
+}
+
+func (w *auditTableWriter) Write(ctx context.Context, record *AuditRecord) error {
+	orm := w.orm
+
+	if tx, ok := transactionFromContext(ctx); ok && tx.connection == w.connection {
+		orm = tx.orm
+	}
+
+	return orm.New().Table(w.table).Create(record).Error
+}
+
+type auditStreamWriter struct {
+	logger  mon.Logger
+	encoder stream.MessageEncoder
+	output  stream.Output
+}
+
+// NewAuditStreamWriter publishes the records to the output. Inside of a transaction, the record is published after
+// the commit and a failure is only logged.
+func NewAuditStreamWriter(logger mon.Logger, output stream.Output) *auditStreamWriter {
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding: stream.EncodingJson,
+	})
+
+	return &auditStreamWriter{
+		logger:  logger,
+		encoder: encoder,
+		output:  output,
+	}
+}
+
+func (w *auditStreamWriter) Write(ctx context.Context, record *AuditRecord) error {
+	if !InTransaction(ctx) {
+		return w.write(ctx, record)
+	}
+
+	afterCommit(ctx, func() {
+		if err := w.write(ctx, record); err != nil {
+			w.logger.WithContext(ctx).Error(err, err.Error())
+		}
+	})
+
+	return nil
+}
+
+func (w *auditStreamWriter) write(ctx context.Context, record *AuditRecord) error {
+	msg, err := w.encoder.Encode(ctx, record, map[string]interface{}{
+		"type":    record.Action,
+		"modelId": record.ModelId,
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not encode audit record: %w", err)
+	}
+
+	if err = w.output.WriteOne(ctx, msg); err != nil {
+		return fmt.Errorf("can not publish audit record: %w", err)
+	}
+
+	return nil
+}
+
+type auditRepository struct {
+	Repository
+
+	logger mon.Logger
+	clock  clockwork.Clock
+	writer AuditWriter
+}
+
+// NewAuditRepository records who changed what and when for every write of the repository if audit.<model name> is
+// enabled. The author is the name of the authenticated subject of the context. If the audit is disabled, the
+// repository is returned unchanged.
+func NewAuditRepository(config cfg.Config, logger mon.Logger, repo Repository) (Repository, error) {
+	settings := &AuditSettings{}
+	config.UnmarshalKey(fmt.Sprintf("audit.%s", repo.GetMetadata().ModelId.Name), settings)
+
+	if !settings.Enabled {
+		return repo, nil
+	}
+
+	var writer AuditWriter
+
+	if settings.Output != "" {
+		output, err := stream.NewConfigurableOutput(config, logger, settings.Output)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not create audit output %s: %w", settings.Output, err)
+		}
+
+		writer = NewAuditStreamWriter(logger, output)
+	} else {
+		orm, err := NewOrmForConnection(config, logger, settings.Connection)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not create orm for connection %s: %w", settings.Connection, err)
+		}
+
+		writer = NewAuditTableWriter(orm, settings.Connection, settings.Table)
+	}
+
+	return NewAuditRepositoryWithInterfaces(logger, repo, clockwork.NewRealClock(), writer), nil
+}
+
+func NewAuditRepositoryWithInterfaces(logger mon.Logger, repo Repository, clock clockwork.Clock, writer AuditWriter) *auditRepository {
+	return &auditRepository{
+		Repository: repo,
+		logger:     logger,
+		clock:      clock,
+		writer:     writer,
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, value ModelBased) error {
+	if err := r.Repository.Create(ctx, value); err != nil {
+		return err
+	}
+
+	return r.audit(ctx, Create, nil, value)
+}
+
+func (r *auditRepository) Update(ctx context.Context, value ModelBased) error {
+	ctx = WithSession(ctx)
+	old, err := readCurrent(ctx, r.Repository, value)
+
+	if err != nil {
+		return err
+	}
+
+	if err = r.Repository.Update(ctx, value); err != nil {
+		return err
+	}
+
+	return r.audit(ctx, Update, old, value)
+}
+
+func (r *auditRepository) Delete(ctx context.Context, value ModelBased) error {
+	ctx = WithSession(ctx)
+	old, err := readCurrent(ctx, r.Repository, value)
+
+	if err != nil {
+		return err
+	}
+
+	if err = r.Repository.Delete(ctx, value); err != nil {
+		return err
+	}
+
+	return r.audit(ctx, Delete, old, nil)
+}
+
+func (r *auditRepository) audit(ctx context.Context, action string, before ModelBased, after ModelBased) error {
+	modelId := r.GetModelId()
+
+	changes, err := auditDiff(before, after)
+
+	if err != nil {
+		return fmt.Errorf("can not diff model %s on %s: %w", modelId, action, err)
+	}
+
+	changed := after
+	if changed == nil {
+		changed = before
+	}
+
+	record := &AuditRecord{
+		ModelId:   modelId,
+		RecordId:  *changed.GetId(),
+		Action:    action,
+		Author:    auditAuthor(ctx),
+		Changes:   changes,
+		CreatedAt: r.clock.Now(),
+	}
+
+	if err = r.writer.Write(ctx, record); err != nil {
+		return fmt.Errorf("can not write audit record of model %s with id %d on %s: %w", modelId, record.RecordId, action, err)
+	}
+
+	return nil
+}
+
+func auditAuthor(ctx context.Context) string {
+	if subject, ok := reqctx.LookupSubject(ctx); ok && subject.Name != "" {
+		return subject.Name
+	}
+
+	return AuditAuthorSystem
+}
+
+// auditDiff compares the json representations of the models and returns the fields which differ
+func auditDiff(before ModelBased, after ModelBased) (AuditChanges, error) {
+	oldFields, err := auditFields(before)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := auditFields(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(AuditChanges)
+
+	for field, value := range newFields {
+		if !reflect.DeepEqual(oldFields[field], value) {
+			changes[field] = AuditChange{
+				Old: oldFields[field],
+				New: value,
+			}
+		}
+	}
+
+	for field, value := range oldFields {
+		if _, ok := newFields[field]; !ok {
+			changes[field] = AuditChange{
+				Old: value,
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func auditFields(value ModelBased) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	if value == nil {
+		return fields, nil
+	}
+
+	bytes, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(bytes, &fields); err != nil {
+		return nil, err
+	}
+
+	for field := range auditIgnoredFields {
+		delete(fields, field)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,100 @@
+package db_repo_test
+
+import (
+	"context"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/db-repo/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type MyAuditedModel struct {
+	db_repo.Model
+	Name string
+}
+
+func TestAuditRepository_Update(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, _ := getTransactionMocks(t, now)
+
+	dbc.ExpectQuery("SELECT \\* FROM `my_audited_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "name"}).AddRow(id1, &now, &now, "foo"))
+	dbc.ExpectExec("UPDATE `my_audited_models`").WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectQuery("SELECT \\* FROM `my_audited_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "name"}).AddRow(id1, &now, &now, "bar"))
+
+	writer := new(mocks.AuditWriter)
+	writer.On("Write", mock.Anything, &db_repo.AuditRecord{
+		ModelId:  "...",
+		RecordId: 1,
+		Action:   db_repo.Update,
+		Author:   "alice",
+		Changes: db_repo.AuditChanges{
+			"Name": {
+				Old: "foo",
+				New: "bar",
+			},
+		},
+		CreatedAt: now,
+	}).Return(nil).Once()
+
+	auditRepo := db_repo.NewAuditRepositoryWithInterfaces(monMocks.NewLoggerMockedAll(), repo, clockwork.NewFakeClockAt(now), writer)
+
+	err := auditRepo.Update(contextWithSubject("alice"), &MyAuditedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+		Name: "bar",
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	writer.AssertExpectations(t)
+}
+
+func TestAuditRepository_DeleteWithoutSubject(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, _ := getTransactionMocks(t, now)
+
+	dbc.ExpectQuery("SELECT \\* FROM `my_audited_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "name"}).AddRow(id1, &now, &now, "foo"))
+	dbc.ExpectExec("DELETE FROM `my_audited_models`").WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	writer := new(mocks.AuditWriter)
+	writer.On("Write", mock.Anything, &db_repo.AuditRecord{
+		ModelId:  "...",
+		RecordId: 1,
+		Action:   db_repo.Delete,
+		Author:   db_repo.AuditAuthorSystem,
+		Changes: db_repo.AuditChanges{
+			"Id": {
+				Old: float64(1),
+			},
+			"Name": {
+				Old: "foo",
+			},
+		},
+		CreatedAt: now,
+	}).Return(nil).Once()
+
+	auditRepo := db_repo.NewAuditRepositoryWithInterfaces(monMocks.NewLoggerMockedAll(), repo, clockwork.NewFakeClockAt(now), writer)
+
+	err := auditRepo.Delete(context.Background(), &MyAuditedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	writer.AssertExpectations(t)
+}
+
+func contextWithSubject(name string) context.Context {
+	return reqctx.WithSubject(context.Background(), &reqctx.Subject{
+		Name: name,
+	})
+}
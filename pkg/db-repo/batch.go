@@ -0,0 +1,245 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/jinzhu/gorm"
+	"reflect"
+	"strings"
+)
+
+const DefaultBatchSize = 100
+
+// ChunkError is the error of a single chunk of a batch, the values from Offset to Offset+Size were not written
+type ChunkError struct {
+	Offset int
+	Size   int
+	Err    error
+}
+
+func (e ChunkError) Error() string {
+	return fmt.Sprintf("chunk %d to %d: %s", e.Offset, e.Offset+e.Size-1, e.Err)
+}
+
+func (e ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError reports the chunks of a batch which failed, the other chunks were written
+type BatchError struct {
+	modelId string
+	Chunks  []ChunkError
+}
+
+func (e *BatchError) Error() string {
+	errs := make([]string, len(e.Chunks))
+
+	for i, chunk := range e.Chunks {
+		errs[i] = chunk.Error()
+	}
+
+	return fmt.Sprintf("could not write %d chunks of models of type %s: %s", len(e.Chunks), e.modelId, strings.Join(errs, "; "))
+}
+
+// Unwrap returns the error of the first failed chunk
+func (e *BatchError) Unwrap() error {
+	if len(e.Chunks) == 0 {
+		return nil
+	}
+
+	return e.Chunks[0]
+}
+
+// CreateBatch inserts the values with multi row inserts of at most Settings.BatchSize values. The values need to be
+// of the same type. In contrast to Create, the values are not read again, so ids assigned by the database are not
+// set on the values. If some of the chunks fail, a *BatchError is returned.
+func (r *repository) CreateBatch(ctx context.Context, values []ModelBased) error {
+	ctx, span := r.startSubSpan(ctx, "CreateBatch")
+	defer span.Finish()
+
+	return r.writeBatch(ctx, CreateBatch, values, nil)
+}
+
+// Upsert inserts the values like CreateBatch and updates all columns but the primary key, the created at timestamp
+// and the conflict columns of the rows which already exist. Postgres needs the columns of the unique key the values
+// conflict on, mysql ignores them and updates on a conflict with any unique key.
+func (r *repository) Upsert(ctx context.Context, values []ModelBased, conflictColumns ...string) error {
+	ctx, span := r.startSubSpan(ctx, "Upsert")
+	defer span.Finish()
+
+	if r.orm.Dialect().GetName() != "mysql" && len(conflictColumns) == 0 {
+		return fmt.Errorf("can not upsert models of type %s without conflict columns", r.GetModelId())
+	}
+
+	return r.writeBatch(ctx, Upsert, values, conflictColumns)
+}
+
+func (r *repository) writeBatch(ctx context.Context, op string, values []ModelBased, conflictColumns []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	modelId := r.GetModelId()
+	logger := r.logger.WithContext(ctx)
+
+	scope := r.orm.NewScope(values[0])
+	columns, err := r.batchColumns(scope, values)
+
+	if err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+
+	for _, value := range values {
+		value.SetUpdatedAt(&now)
+		value.SetCreatedAt(&now)
+	}
+
+	batchSize := r.settings.BatchSize
+
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	batchErr := &BatchError{
+		modelId: modelId,
+	}
+
+	for offset := 0; offset < len(values); offset += batchSize {
+		end := offset + batchSize
+
+		if end > len(values) {
+			end = len(values)
+		}
+
+		query, args := r.buildBatchInsert(scope, columns, values[offset:end], op, conflictColumns)
+
+		err := r.retryOnDeadlock(ctx, op, func() error {
+			return r.withContext(ctx).Exec(query, args...).Error
+		})
+
+		if err != nil {
+			logger.Errorf(err, "could not %s chunk %d to %d of models of type %s", op, offset, end-1, modelId)
+
+			batchErr.Chunks = append(batchErr.Chunks, ChunkError{
+				Offset: offset,
+				Size:   end - offset,
+				Err:    err,
+			})
+		}
+	}
+
+	reqctx.MarkWritten(ctx)
+
+	if len(batchErr.Chunks) > 0 {
+		return batchErr
+	}
+
+	logger.Infof("wrote %d models of type %s with %s", len(values), modelId, op)
+
+	return nil
+}
+
+// batchColumns returns the columns of the table. The primary key is only written if it is set, so the values either
+// all need a primary key or none of them.
+func (r *repository) batchColumns(scope *gorm.Scope, values []ModelBased) ([]string, error) {
+	modelType := reflect.TypeOf(values[0])
+	withPrimaryKey := !scope.PrimaryKeyZero()
+
+	for _, value := range values {
+		if reflect.TypeOf(value) != modelType {
+			return nil, fmt.Errorf("can not write a batch of different types %s and %T", modelType, value)
+		}
+
+		if r.orm.NewScope(value).PrimaryKeyZero() == withPrimaryKey {
+			return nil, fmt.Errorf("either all or none of the models of type %s in a batch need a primary key", r.GetModelId())
+		}
+	}
+
+	columns := make([]string, 0)
+
+	for _, field := range scope.Fields() {
+		if !field.IsNormal || field.IsIgnored {
+			continue
+		}
+
+		if field.IsPrimaryKey && !withPrimaryKey {
+			continue
+		}
+
+		columns = append(columns, field.DBName)
+	}
+
+	return columns, nil
+}
+
+func (r *repository) buildBatchInsert(scope *gorm.Scope, columns []string, values []ModelBased, op string, conflictColumns []string) (string, []interface{}) {
+	names := make([]string, len(columns))
+
+	for i, column := range columns {
+		names[i] = scope.Quote(column)
+	}
+
+	placeholders := fmt.Sprintf("(%s)", strings.TrimSuffix(strings.Repeat("?,", len(columns)), ","))
+	rows := make([]string, len(values))
+	args := make([]interface{}, 0, len(columns)*len(values))
+
+	for i, value := range values {
+		rows[i] = placeholders
+		valueScope := r.orm.NewScope(value)
+
+		for _, column := range columns {
+			field, _ := valueScope.FieldByName(column)
+			args = append(args, field.Field.Interface())
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", scope.QuotedTableName(), strings.Join(names, ","), strings.Join(rows, ","))
+
+	if op != Upsert {
+		return query, args
+	}
+
+	excluded := map[string]bool{
+		scope.PrimaryKey(): true,
+		"created_at":       true,
+	}
+
+	for _, column := range conflictColumns {
+		excluded[column] = true
+	}
+
+	updates := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		if excluded[column] {
+			continue
+		}
+
+		quoted := scope.Quote(column)
+
+		if r.orm.Dialect().GetName() == "mysql" {
+			updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", quoted, quoted))
+		} else {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", quoted, quoted))
+		}
+	}
+
+	if r.orm.Dialect().GetName() == "mysql" {
+		return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", query, strings.Join(updates, ", ")), args
+	}
+
+	conflicts := make([]string, len(conflictColumns))
+
+	for i, column := range conflictColumns {
+		conflicts[i] = scope.Quote(column)
+	}
+
+	if len(updates) == 0 {
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", query, strings.Join(conflicts, ",")), args
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", query, strings.Join(conflicts, ","), strings.Join(updates, ", ")), args
+}
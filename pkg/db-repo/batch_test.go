@@ -0,0 +1,157 @@
+package db_repo_test
+
+import (
+	"context"
+	"fmt"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRepository_CreateBatch(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getBatchMocks(t, "mysql", now, 2)
+
+	dbc.ExpectExec("INSERT INTO `my_test_models` \\(`id`,`updated_at`,`created_at`\\) VALUES \\(\\?,\\?,\\?\\),\\(\\?,\\?,\\?\\)$").
+		WithArgs(id1, &now, &now, id24, &now, &now).
+		WillReturnResult(goSqlMock.NewResult(0, 2))
+	dbc.ExpectExec("INSERT INTO `my_test_models` \\(`id`,`updated_at`,`created_at`\\) VALUES \\(\\?,\\?,\\?\\)$").
+		WithArgs(id42, &now, &now).
+		WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	values := []db_repo.ModelBased{
+		&MyTestModel{Model: db_repo.Model{Id: id1}},
+		&MyTestModel{Model: db_repo.Model{Id: id24}},
+		&MyTestModel{Model: db_repo.Model{Id: id42}},
+	}
+
+	err := repo.CreateBatch(context.Background(), values)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &now, values[2].(*MyTestModel).CreatedAt)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_CreateBatchWithoutIds(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getBatchMocks(t, "mysql", now, 0)
+
+	dbc.ExpectExec("INSERT INTO `my_test_models` \\(`updated_at`,`created_at`\\) VALUES \\(\\?,\\?\\),\\(\\?,\\?\\)$").
+		WithArgs(&now, &now, &now, &now).
+		WillReturnResult(goSqlMock.NewResult(0, 2))
+
+	err := repo.CreateBatch(context.Background(), []db_repo.ModelBased{
+		&MyTestModel{},
+		&MyTestModel{},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_CreateBatchMixedIds(t *testing.T) {
+	dbc, repo := getBatchMocks(t, "mysql", time.Unix(1549964818, 0), 0)
+
+	err := repo.CreateBatch(context.Background(), []db_repo.ModelBased{
+		&MyTestModel{Model: db_repo.Model{Id: id1}},
+		&MyTestModel{},
+	})
+
+	assert.EqualError(t, err, "either all or none of the models of type ... in a batch need a primary key")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_CreateBatchChunkFails(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getBatchMocks(t, "mysql", now, 1)
+
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id24, &now, &now).WillReturnError(fmt.Errorf("duplicate entry"))
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id42, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	err := repo.CreateBatch(context.Background(), []db_repo.ModelBased{
+		&MyTestModel{Model: db_repo.Model{Id: id1}},
+		&MyTestModel{Model: db_repo.Model{Id: id24}},
+		&MyTestModel{Model: db_repo.Model{Id: id42}},
+	})
+
+	batchErr, ok := err.(*db_repo.BatchError)
+
+	assert.True(t, ok, "the error should be a batch error")
+	assert.Len(t, batchErr.Chunks, 1)
+	assert.Equal(t, 1, batchErr.Chunks[0].Offset)
+	assert.Equal(t, 1, batchErr.Chunks[0].Size)
+	assert.EqualError(t, err, "could not write 1 chunks of models of type ...: chunk 1 to 1: duplicate entry")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_UpsertMysql(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getBatchMocks(t, "mysql", now, 0)
+
+	dbc.ExpectExec("INSERT INTO `my_test_models` \\(`id`,`updated_at`,`created_at`\\) VALUES \\(\\?,\\?,\\?\\),\\(\\?,\\?,\\?\\) "+
+		"ON DUPLICATE KEY UPDATE `updated_at` = VALUES\\(`updated_at`\\)$").
+		WithArgs(id1, &now, &now, id24, &now, &now).
+		WillReturnResult(goSqlMock.NewResult(0, 3))
+
+	err := repo.Upsert(context.Background(), []db_repo.ModelBased{
+		&MyTestModel{Model: db_repo.Model{Id: id1}},
+		&MyTestModel{Model: db_repo.Model{Id: id24}},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_UpsertPostgres(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getBatchMocks(t, "postgres", now, 0)
+
+	dbc.ExpectExec("INSERT INTO \"my_test_models\" \\(\"id\",\"updated_at\",\"created_at\"\\) VALUES \\(\\$1,\\$2,\\$3\\) "+
+		"ON CONFLICT \\(\"id\"\\) DO UPDATE SET \"updated_at\" = EXCLUDED.\"updated_at\"$").
+		WithArgs(id1, &now, &now).
+		WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	err := repo.Upsert(context.Background(), []db_repo.ModelBased{
+		&MyTestModel{Model: db_repo.Model{Id: id1}},
+	}, "id")
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_UpsertPostgresWithoutConflictColumns(t *testing.T) {
+	dbc, repo := getBatchMocks(t, "postgres", time.Unix(1549964818, 0), 0)
+
+	err := repo.Upsert(context.Background(), []db_repo.ModelBased{
+		&MyTestModel{Model: db_repo.Model{Id: id1}},
+	})
+
+	assert.EqualError(t, err, "can not upsert models of type ... without conflict columns")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func getBatchMocks(t *testing.T, driver string, now time.Time, batchSize int) (goSqlMock.Sqlmock, db_repo.Repository) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+
+	db, clientMock, _ := goSqlMock.New()
+
+	orm, err := db_repo.NewOrmWithInterfaces(logger, db, db_repo.OrmSettings{
+		Driver: driver,
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, clockwork.NewFakeClockAt(now), db_repo.Settings{
+		BatchSize: batchSize,
+	})
+
+	return clientMock, repo
+}
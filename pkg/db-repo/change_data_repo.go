@@ -0,0 +1,160 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/applike/gosoline/pkg/stream"
+	"reflect"
+)
+
+type ChangeDataSettings struct {
+	Enabled bool   `cfg:"enabled" default:"false"`
+	Output  string `cfg:"output"`
+}
+
+// ChangeDataEvent is the body of the messages published for every change of a model. Old is nil for a create, New
+// is nil for a delete.
+type ChangeDataEvent struct {
+	Old ModelBased `json:"old"`
+	New ModelBased `json:"new"`
+}
+
+type changeDataRepository struct {
+	Repository
+
+	logger  mon.Logger
+	encoder stream.MessageEncoder
+	output  stream.Output
+}
+
+// NewChangeDataRepository publishes the changes of the repository to a stream output if change_data.<model name>
+// is enabled. The output defaults to change_data_<model name> and is configured like any other stream output.
+// If change data is disabled, the repository is returned unchanged.
+func NewChangeDataRepository(config cfg.Config, logger mon.Logger, repo Repository) (Repository, error) {
+	name := repo.GetMetadata().ModelId.Name
+
+	settings := &ChangeDataSettings{}
+	config.UnmarshalKey(fmt.Sprintf("change_data.%s", name), settings)
+
+	if !settings.Enabled {
+		return repo, nil
+	}
+
+	if settings.Output == "" {
+		settings.Output = fmt.Sprintf("change_data_%s", name)
+	}
+
+	output, err := stream.NewConfigurableOutput(config, logger, settings.Output)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create change data output %s: %w", settings.Output, err)
+	}
+
+	return NewChangeDataRepositoryWithInterfaces(logger, repo, output), nil
+}
+
+func NewChangeDataRepositoryWithInterfaces(logger mon.Logger, repo Repository, output stream.Output) *changeDataRepository {
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding: stream.EncodingJson,
+	})
+
+	return &changeDataRepository{
+		Repository: repo,
+		logger:     logger.WithChannel("db_change_data"),
+		encoder:    encoder,
+		output:     output,
+	}
+}
+
+func (r *changeDataRepository) Create(ctx context.Context, value ModelBased) error {
+	if err := r.Repository.Create(ctx, value); err != nil {
+		return err
+	}
+
+	return r.publish(ctx, Create, nil, value)
+}
+
+func (r *changeDataRepository) Update(ctx context.Context, value ModelBased) error {
+	ctx = WithSession(ctx)
+	old, err := readCurrent(ctx, r.Repository, value)
+
+	if err != nil {
+		return err
+	}
+
+	if err = r.Repository.Update(ctx, value); err != nil {
+		return err
+	}
+
+	return r.publish(ctx, Update, old, value)
+}
+
+func (r *changeDataRepository) Delete(ctx context.Context, value ModelBased) error {
+	ctx = WithSession(ctx)
+	old, err := readCurrent(ctx, r.Repository, value)
+
+	if err != nil {
+		return err
+	}
+
+	if err = r.Repository.Delete(ctx, value); err != nil {
+		return err
+	}
+
+	return r.publish(ctx, Delete, old, nil)
+}
+
+// readCurrent reads the stored state of the model before a change. The read goes to the primary, as the write
+// follows right after and a replica could be behind. The context needs to carry a session.
+func readCurrent(ctx context.Context, repo Repository, value ModelBased) (ModelBased, error) {
+	reqctx.MarkWritten(ctx)
+
+	current := reflect.New(reflect.TypeOf(value).Elem()).Interface().(ModelBased)
+
+	if err := repo.Read(ctx, value.GetId(), current); err != nil {
+		return nil, fmt.Errorf("can not read model %s with id %d before the change: %w", repo.GetModelId(), *value.GetId(), err)
+	}
+
+	return current, nil
+}
+
+// publish writes the event once the change is committed. Inside of a transaction, the event is published after the
+// commit and a failure is only logged, as the change can't be undone anymore.
+func (r *changeDataRepository) publish(ctx context.Context, op string, before ModelBased, after ModelBased) error {
+	if !InTransaction(ctx) {
+		return r.write(ctx, op, before, after)
+	}
+
+	afterCommit(ctx, func() {
+		if err := r.write(ctx, op, before, after); err != nil {
+			r.logger.WithContext(ctx).Error(err, err.Error())
+		}
+	})
+
+	return nil
+}
+
+func (r *changeDataRepository) write(ctx context.Context, op string, before ModelBased, after ModelBased) error {
+	modelId := r.GetModelId()
+
+	msg, err := r.encoder.Encode(ctx, &ChangeDataEvent{
+		Old: before,
+		New: after,
+	}, map[string]interface{}{
+		"type":    op,
+		"modelId": modelId,
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not encode change data event on %s for model %s: %w", op, modelId, err)
+	}
+
+	if err = r.output.WriteOne(ctx, msg); err != nil {
+		return fmt.Errorf("can not publish change data event on %s for model %s: %w", op, modelId, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package db_repo_test
+
+import (
+	"context"
+	"fmt"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/stream"
+	streamMocks "github.com/applike/gosoline/pkg/stream/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func matchChangeDataEvent(op string, hasOld bool, hasNew bool) interface{} {
+	return mock.MatchedBy(func(msg *stream.Message) bool {
+		return msg.Attributes["type"] == op &&
+			strings.Contains(msg.Body, `"old":null`) != hasOld &&
+			strings.Contains(msg.Body, `"new":null`) != hasNew
+	})
+}
+
+func TestChangeDataRepository_Create(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, _ := getTransactionMocks(t, now)
+
+	output := new(streamMocks.Output)
+	output.On("WriteOne", mock.Anything, matchChangeDataEvent(db_repo.Create, false, true)).Return(nil).Once()
+
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+
+	changeDataRepo := db_repo.NewChangeDataRepositoryWithInterfaces(monMocks.NewLoggerMockedAll(), repo, output)
+
+	err := changeDataRepo.Create(context.Background(), &MyTestModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	output.AssertExpectations(t)
+}
+
+func TestChangeDataRepository_UpdateAfterCommit(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, runner := getTransactionMocks(t, now)
+
+	output := new(streamMocks.Output)
+	output.On("WriteOne", mock.Anything, matchChangeDataEvent(db_repo.Update, true, true)).Return(nil).Once()
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+	dbc.ExpectExec("UPDATE `my_test_models`").WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+	dbc.ExpectCommit()
+
+	changeDataRepo := db_repo.NewChangeDataRepositoryWithInterfaces(monMocks.NewLoggerMockedAll(), repo, output)
+
+	err := runner.WithTx(context.Background(), func(ctx context.Context) error {
+		err := changeDataRepo.Update(ctx, &MyTestModel{
+			Model: db_repo.Model{
+				Id: id1,
+			},
+		})
+
+		output.AssertNotCalled(t, "WriteOne", mock.Anything, mock.Anything)
+
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	output.AssertExpectations(t)
+}
+
+func TestChangeDataRepository_DeleteRolledBack(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, runner := getTransactionMocks(t, now)
+
+	output := new(streamMocks.Output)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+	dbc.ExpectExec("DELETE FROM `my_test_models`").WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectRollback()
+
+	changeDataRepo := db_repo.NewChangeDataRepositoryWithInterfaces(monMocks.NewLoggerMockedAll(), repo, output)
+
+	err := runner.WithTx(context.Background(), func(ctx context.Context) error {
+		err := changeDataRepo.Delete(ctx, &MyTestModel{
+			Model: db_repo.Model{
+				Id: id1,
+			},
+		})
+		assert.NoError(t, err)
+
+		return fmt.Errorf("abort")
+	})
+
+	assert.EqualError(t, err, "abort")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	output.AssertNotCalled(t, "WriteOne", mock.Anything, mock.Anything)
+}
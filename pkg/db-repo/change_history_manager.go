@@ -3,6 +3,7 @@ package db_repo
 import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/db"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/jinzhu/gorm"
 	"reflect"
@@ -30,6 +31,11 @@ func newChangeHistoryManager(config cfg.Config, logger mon.Logger, model ModelBa
 		return nil, fmt.Errorf("can not create orm: %w", err)
 	}
 
+	// the history is written by triggers using the syntax of mysql
+	if dialect := orm.Dialect().GetName(); dialect != db.DriverMysql {
+		return nil, fmt.Errorf("the change history is not supported for the driver %s", dialect)
+	}
+
 	settings := &changeHistoryManagerSettings{}
 	config.UnmarshalKey("change_history", settings)
 
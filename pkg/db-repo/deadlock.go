@@ -0,0 +1,88 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/cenkalti/backoff"
+	"time"
+)
+
+const (
+	MetricNameDbDeadlockRetryCount = "DeadlockRetryCount"
+	operationTransaction           = "transaction"
+)
+
+type DeadlockRetrySettings struct {
+	// MaxRetries is the number of retries after a deadlock or a lock wait timeout, 0 disables the retries
+	MaxRetries      int           `cfg:"max_retries" default:"3"`
+	InitialInterval time.Duration `cfg:"initial_interval" default:"50ms"`
+	MaxInterval     time.Duration `cfg:"max_interval" default:"1s"`
+}
+
+func readDeadlockRetrySettings(config cfg.Config, connection string) DeadlockRetrySettings {
+	settings := DeadlockRetrySettings{}
+	config.UnmarshalKey(fmt.Sprintf("db.%s.deadlock_retry", connection), &settings)
+
+	return settings
+}
+
+type deadlockRetrier struct {
+	logger   mon.Logger
+	metric   mon.MetricWriter
+	settings DeadlockRetrySettings
+}
+
+func newDeadlockRetrier(logger mon.Logger, settings DeadlockRetrySettings) *deadlockRetrier {
+	return &deadlockRetrier{
+		logger:   logger,
+		metric:   mon.NewMetricDaemonWriter(),
+		settings: settings,
+	}
+}
+
+// run calls the function again with an exponential backoff as long as it fails due to a deadlock or a lock wait
+// timeout and the retries are not used up. Inside of a transaction, a deadlock aborted the whole transaction and the
+// function is called only once, the transaction runner retries the transaction instead.
+func (d *deadlockRetrier) run(ctx context.Context, dimensions map[string]string, f func() error) error {
+	if d.settings.MaxRetries <= 0 || InTransaction(ctx) {
+		return f()
+	}
+
+	logger := d.logger.WithContext(ctx)
+
+	backoffConfig := backoff.NewExponentialBackOff()
+	backoffConfig.InitialInterval = d.settings.InitialInterval
+	backoffConfig.MaxInterval = d.settings.MaxInterval
+	backoffConfig.MaxElapsedTime = 0
+
+	backoffCtx := backoff.WithMaxRetries(backoff.WithContext(backoffConfig, ctx), uint64(d.settings.MaxRetries))
+
+	notify := func(err error, delay time.Duration) {
+		logger.Warnf("retrying %s in %s after deadlock: %s", dimensions["Operation"], delay, err.Error())
+
+		d.metric.WriteOne(&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: MetricNameDbDeadlockRetryCount,
+			Dimensions: dimensions,
+			Unit:       mon.UnitCount,
+			Value:      1.0,
+		})
+	}
+
+	var err error
+
+	_ = backoff.RetryNotify(func() error {
+		err = f()
+
+		if err != nil && !db.IsDeadlockError(err) {
+			return backoff.Permanent(err)
+		}
+
+		return err
+	}, backoffCtx, notify)
+
+	return err
+}
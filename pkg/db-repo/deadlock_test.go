@@ -0,0 +1,110 @@
+package db_repo_test
+
+import (
+	"context"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/db-repo"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var errDeadlock = &mysql.MySQLError{
+	Number:  1213,
+	Message: "Deadlock found when trying to get lock; try restarting transaction",
+}
+
+func TestRepository_CreateDeadlockRetry(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, _ := getDeadlockMocks(t, now, 2)
+
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WillReturnError(errDeadlock)
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+
+	err := repo.Create(context.Background(), &MyTestModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteDeadlockRetriesExhausted(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, _ := getDeadlockMocks(t, now, 1)
+
+	dbc.ExpectExec("DELETE FROM `my_test_models`").WillReturnError(errDeadlock)
+	dbc.ExpectExec("DELETE FROM `my_test_models`").WillReturnError(errDeadlock)
+
+	err := repo.Delete(context.Background(), &MyTestModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	})
+
+	assert.True(t, db.IsDeadlockError(err))
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestTransactionRunner_WithTx_DeadlockRetry(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, runner := getDeadlockMocks(t, now, 2)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WillReturnError(errDeadlock)
+	dbc.ExpectRollback()
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+	dbc.ExpectCommit()
+
+	calls := 0
+
+	err := runner.WithTx(context.Background(), func(ctx context.Context) error {
+		calls++
+
+		return repo.Create(ctx, &MyTestModel{
+			Model: db_repo.Model{
+				Id: id1,
+			},
+		})
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func getDeadlockMocks(t *testing.T, now time.Time, maxRetries int) (goSqlMock.Sqlmock, db_repo.Repository, db_repo.TransactionRunner) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+
+	dbClient, clientMock, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbClient, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	deadlockRetry := db_repo.DeadlockRetrySettings{
+		MaxRetries:      maxRetries,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	}
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, clockwork.NewFakeClockAt(now), db_repo.Settings{
+		DeadlockRetry: deadlockRetry,
+	})
+	runner := db_repo.NewTransactionRunnerWithInterfaces(logger, orm, db_repo.DefaultConnection, deadlockRetry)
+
+	return clientMock, repo, runner
+}
@@ -54,3 +54,25 @@ func (e NoQueryResultsError) Unwrap() error {
 func IsNoQueryResultsError(err error) bool {
 	return errors.As(err, &NoQueryResultsError{})
 }
+
+type VersionConflictError struct {
+	id      uint
+	modelId string
+	version uint
+}
+
+func NewVersionConflictError(id uint, modelId string, version uint) VersionConflictError {
+	return VersionConflictError{
+		id:      id,
+		modelId: modelId,
+		version: version,
+	}
+}
+
+func (e VersionConflictError) Error() string {
+	return fmt.Sprintf("could not update model of type %s with id %d: version %d is outdated", e.modelId, e.id, e.version)
+}
+
+func IsVersionConflictError(err error) bool {
+	return errors.As(err, &VersionConflictError{})
+}
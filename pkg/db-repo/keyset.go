@@ -0,0 +1,101 @@
+package db_repo
+
+import (
+	"fmt"
+	"github.com/jinzhu/gorm"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const keysetTimeFormat = "2006-01-02 15:04:05.999999"
+
+// keysetCondition selects the rows after the given values of the order fields. For the order a ASC, b DESC this is
+// (a > ?) OR (a = ? AND b < ?).
+func keysetCondition(orderBy []order, after []interface{}) (string, []interface{}, error) {
+	if len(after) != len(orderBy) {
+		return "", nil, fmt.Errorf("the keyset has %d values but the query is ordered by %d fields", len(after), len(orderBy))
+	}
+
+	conditions := make([]string, len(orderBy))
+	args := make([]interface{}, 0)
+
+	for i, o := range orderBy {
+		parts := make([]string, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = ?", orderBy[j].field))
+			args = append(args, after[j])
+		}
+
+		operator := ">"
+		if strings.EqualFold(fmt.Sprint(o.direction), "DESC") {
+			operator = "<"
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s ?", o.field, operator))
+		args = append(args, after[i])
+
+		conditions[i] = fmt.Sprintf("(%s)", strings.Join(parts, " AND "))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(conditions, " OR ")), args, nil
+}
+
+// keysetColumns returns the names of the ordered columns in the table of the model
+func keysetColumns(scope *gorm.Scope, orderBy []order) ([]string, error) {
+	tableName := scope.TableName()
+	columns := make([]string, len(orderBy))
+
+	for i, o := range orderBy {
+		column := o.field
+
+		if dot := strings.LastIndex(column, "."); dot != -1 {
+			if strings.Trim(column[:dot], "`\"") != tableName {
+				return nil, fmt.Errorf("can not page by the column %s of another table than %s", column, tableName)
+			}
+
+			column = column[dot+1:]
+		}
+
+		column = strings.Trim(column, "`\"")
+
+		if _, ok := scope.FieldByName(column); !ok {
+			return nil, fmt.Errorf("can not page by the column %s which is not a field of the model", o.field)
+		}
+
+		columns[i] = column
+	}
+
+	return columns, nil
+}
+
+// nextKeyset reads the values of the ordered columns of the last result. The results are a pointer to a slice of
+// models.
+func nextKeyset(orm *gorm.DB, columns []string, results interface{}, limit int) ([]interface{}, error) {
+	slice := reflect.Indirect(reflect.ValueOf(results))
+
+	if slice.Kind() != reflect.Slice || slice.Len() < limit || slice.Len() == 0 {
+		return nil, nil
+	}
+
+	scope := orm.NewScope(slice.Index(slice.Len() - 1).Interface())
+	keys := make([]interface{}, len(columns))
+
+	for i, column := range columns {
+		field, _ := scope.FieldByName(column)
+		value := reflect.Indirect(field.Field)
+
+		if !value.IsValid() {
+			return nil, fmt.Errorf("can not page by the column %s as it is null", column)
+		}
+
+		keys[i] = value.Interface()
+
+		if t, ok := keys[i].(time.Time); ok {
+			keys[i] = t.UTC().Format(keysetTimeFormat)
+		}
+	}
+
+	return keys, nil
+}
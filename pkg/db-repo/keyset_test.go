@@ -0,0 +1,75 @@
+package db_repo_test
+
+import (
+	"context"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRepository_QueryKeyset(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).
+		AddRow(3, &now, &now).
+		AddRow(7, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` WHERE \\(\\(\\(created_at < \\?\\) OR \\(created_at = \\? AND id > \\?\\)\\)\\) ORDER BY created_at DESC,id ASC LIMIT 2").
+		WithArgs("2019-02-12 09:46:58", "2019-02-12 09:46:58", 1).
+		WillReturnRows(rows)
+
+	qb := db_repo.NewQueryBuilder()
+	qb.OrderBy("created_at", "DESC")
+	qb.OrderBy("id", "ASC")
+	qb.Keyset([]interface{}{"2019-02-12 09:46:58", 1}, 2)
+
+	result := make([]*MyTestModel, 0)
+	err := repo.Query(context.Background(), qb, &result)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	next, ok := qb.NextKeyset()
+
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"2019-02-12 09:46:58", uint(7)}, next)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_QueryKeysetLastPage(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(3, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` +ORDER BY `my_test_models`\\.`id` ASC LIMIT 2").WillReturnRows(rows)
+
+	qb := db_repo.NewQueryBuilder()
+	qb.OrderBy("`my_test_models`.`id`", "ASC")
+	qb.Keyset(nil, 2)
+
+	result := make([]*MyTestModel, 0)
+	err := repo.Query(context.Background(), qb, &result)
+
+	assert.NoError(t, err)
+
+	_, ok := qb.NextKeyset()
+
+	assert.False(t, ok)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_QueryKeysetOtherTable(t *testing.T) {
+	dbc, repo := getMocks(t)
+
+	qb := db_repo.NewQueryBuilder()
+	qb.OrderBy("categories.name", "ASC")
+	qb.Keyset(nil, 2)
+
+	result := make([]*MyTestModel, 0)
+	err := repo.Query(context.Background(), qb, &result)
+
+	assert.EqualError(t, err, "can not page by the column categories.name of another table than my_test_models")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
@@ -37,6 +37,22 @@ func (r metricRepository) Create(ctx context.Context, value ModelBased) error {
 	return err
 }
 
+func (r metricRepository) CreateBatch(ctx context.Context, values []ModelBased) error {
+	start := time.Now()
+	err := r.Repository.CreateBatch(ctx, values)
+	r.writeMetric(CreateBatch, err, start)
+
+	return err
+}
+
+func (r metricRepository) Upsert(ctx context.Context, values []ModelBased, conflictColumns ...string) error {
+	start := time.Now()
+	err := r.Repository.Upsert(ctx, values, conflictColumns...)
+	r.writeMetric(Upsert, err, start)
+
+	return err
+}
+
 func (r metricRepository) Read(ctx context.Context, id *uint, out ModelBased) error {
 	start := time.Now()
 	err := r.Repository.Read(ctx, id, out)
@@ -69,6 +85,14 @@ func (r metricRepository) Query(ctx context.Context, qb *QueryBuilder, result in
 	return err
 }
 
+func (r metricRepository) Stream(ctx context.Context, qb *QueryBuilder, model ModelBased, f StreamFunc) error {
+	start := time.Now()
+	err := r.Repository.Stream(ctx, qb, model, f)
+	r.writeMetric(Stream, err, start)
+
+	return err
+}
+
 func (r metricRepository) writeMetric(op string, err error, start time.Time) {
 	latencyNano := time.Since(start)
 	metricName := MetricNameDbAccessSuccess
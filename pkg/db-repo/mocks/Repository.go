@@ -46,6 +46,20 @@ func (_m *Repository) Create(ctx context.Context, value db_repo.ModelBased) erro
 	return r0
 }
 
+// CreateBatch provides a mock function with given fields: ctx, values
+func (_m *Repository) CreateBatch(ctx context.Context, values []db_repo.ModelBased) error {
+	ret := _m.Called(ctx, values)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []db_repo.ModelBased) error); ok {
+		r0 = rf(ctx, values)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, value
 func (_m *Repository) Delete(ctx context.Context, value db_repo.ModelBased) error {
 	ret := _m.Called(ctx, value)
@@ -116,6 +130,22 @@ func (_m *Repository) Query(ctx context.Context, qb *db_repo.QueryBuilder, resul
 	return r0
 }
 
+// QueryBuilder provides a mock function with given fields:
+func (_m *Repository) QueryBuilder() *db_repo.TypedQueryBuilder {
+	ret := _m.Called()
+
+	var r0 *db_repo.TypedQueryBuilder
+	if rf, ok := ret.Get(0).(func() *db_repo.TypedQueryBuilder); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*db_repo.TypedQueryBuilder)
+		}
+	}
+
+	return r0
+}
+
 // Read provides a mock function with given fields: ctx, id, out
 func (_m *Repository) Read(ctx context.Context, id *uint, out db_repo.ModelBased) error {
 	ret := _m.Called(ctx, id, out)
@@ -130,6 +160,20 @@ func (_m *Repository) Read(ctx context.Context, id *uint, out db_repo.ModelBased
 	return r0
 }
 
+// Stream provides a mock function with given fields: ctx, qb, model, f
+func (_m *Repository) Stream(ctx context.Context, qb *db_repo.QueryBuilder, model db_repo.ModelBased, f db_repo.StreamFunc) error {
+	ret := _m.Called(ctx, qb, model, f)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *db_repo.QueryBuilder, db_repo.ModelBased, db_repo.StreamFunc) error); ok {
+		r0 = rf(ctx, qb, model, f)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, value
 func (_m *Repository) Update(ctx context.Context, value db_repo.ModelBased) error {
 	ret := _m.Called(ctx, value)
@@ -143,3 +187,24 @@ func (_m *Repository) Update(ctx context.Context, value db_repo.ModelBased) erro
 
 	return r0
 }
+
+// Upsert provides a mock function with given fields: ctx, values, conflictColumns
+func (_m *Repository) Upsert(ctx context.Context, values []db_repo.ModelBased, conflictColumns ...string) error {
+	_va := make([]interface{}, len(conflictColumns))
+	for _i := range conflictColumns {
+		_va[_i] = conflictColumns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, values)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []db_repo.ModelBased, ...string) error); ok {
+		r0 = rf(ctx, values, conflictColumns...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
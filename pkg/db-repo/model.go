@@ -5,7 +5,10 @@ import (
 	"time"
 )
 
-const ColumnUpdatedAt = "updated_at"
+const (
+	ColumnUpdatedAt = "updated_at"
+	ColumnVersion   = "version"
+)
 
 type ModelBased interface {
 	mdl.Identifiable
@@ -58,3 +61,22 @@ func EmptyTimestamps() Timestamps {
 		CreatedAt: &time.Time{},
 	}
 }
+
+// A Versionable model is updated with optimistic locking. Every update increments the version and fails with a
+// VersionConflictError if the version in the database is not the one the model was read with.
+type Versionable interface {
+	GetVersion() uint
+	SetVersion(version uint)
+}
+
+type Versioned struct {
+	Version uint
+}
+
+func (m *Versioned) GetVersion() uint {
+	return m.Version
+}
+
+func (m *Versioned) SetVersion(version uint) {
+	m.Version = version
+}
@@ -7,8 +7,12 @@ import (
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/jinzhu/gorm"
 	"strings"
+	"time"
 )
 
+// DefaultConnection is the db client at db.default used by repositories without a connection
+const DefaultConnection = "default"
+
 type OrmMigrationSetting struct {
 	TablePrefixed bool `cfg:"table_prefixed" default:"true"`
 }
@@ -17,16 +21,24 @@ type OrmSettings struct {
 	Migrations  OrmMigrationSetting `cfg:"migrations"`
 	Driver      string              `cfg:"driver" validation:"required"`
 	Application string              `cfg:"application" default:"{app_name}"`
+	// names of the db clients of the read replicas, see NewReplicaSet
+	Replicas            []string      `cfg:"replicas"`
+	ReplicaHealthChecks time.Duration `cfg:"replica_health_checks" default:"10s"`
 }
 
 func NewOrm(config cfg.Config, logger mon.Logger) (*gorm.DB, error) {
-	dbClient, err := db.NewClient(config, logger, "default")
+	return NewOrmForConnection(config, logger, DefaultConnection)
+}
+
+// NewOrmForConnection creates an orm for the db client configured at db.<name>
+func NewOrmForConnection(config cfg.Config, logger mon.Logger, name string) (*gorm.DB, error) {
+	dbClient, err := db.NewClient(config, logger, name)
 	if err != nil {
 		return nil, fmt.Errorf("can not create dbClient: %w", err)
 	}
 
 	settings := OrmSettings{}
-	config.UnmarshalKey("db.default", &settings)
+	config.UnmarshalKey(fmt.Sprintf("db.%s", name), &settings)
 
 	application := settings.Application
 
@@ -49,6 +61,9 @@ func NewOrmWithInterfaces(logger mon.Logger, dbClient gorm.SQLCommon, settings O
 	orm = orm.Set("gorm:auto_preload", true)
 	orm = orm.Set("gorm:save_associations", false)
 
+	registerTracingCallbacks(orm)
+	registerVersionCallbacks(orm)
+
 	if !settings.Migrations.TablePrefixed {
 		return orm, nil
 	}
@@ -15,6 +15,12 @@ type order struct {
 	direction interface{}
 }
 
+type keyset struct {
+	after []interface{}
+	limit int
+	next  []interface{}
+}
+
 type QueryBuilder struct {
 	table   string
 	joins   []string
@@ -23,6 +29,7 @@ type QueryBuilder struct {
 	groupBy []string
 	orderBy []order
 	page    *page
+	keyset  *keyset
 }
 
 func NewQueryBuilder() *QueryBuilder {
@@ -77,3 +84,26 @@ func (qb *QueryBuilder) Page(offset int, size int) db.QueryBuilder {
 
 	return qb
 }
+
+// Keyset pages through the results by the values of the order fields instead of an offset, so the database doesn't
+// need to scan the skipped rows. The page starts after the row with the given values of the order fields, an empty
+// after starts with the first page. The order needs to be unique, so it should end with the primary key, and the
+// ordered columns need to belong to the table of the model and must not be null.
+func (qb *QueryBuilder) Keyset(after []interface{}, limit int) *QueryBuilder {
+	qb.keyset = &keyset{
+		after: after,
+		limit: limit,
+	}
+
+	return qb
+}
+
+// NextKeyset returns the values of the order fields of the last result after a keyset query. They are passed to
+// Keyset to query the next page. False is returned if there is no next page.
+func (qb *QueryBuilder) NextKeyset() ([]interface{}, bool) {
+	if qb.keyset == nil || qb.keyset.next == nil {
+		return nil, false
+	}
+
+	return qb.keyset.next, true
+}
@@ -0,0 +1,155 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/jinzhu/gorm"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithSession starts a session for reading your own writes. Once a repository wrote using the context of the
+// session, all following reads of the session are done on the primary instead of a replica. The api server and the
+// stream consumers already start a session per request or message, see reqctx.WithSession.
+func WithSession(ctx context.Context) context.Context {
+	return reqctx.WithSession(ctx)
+}
+
+type replica struct {
+	name    string
+	orm     *gorm.DB
+	healthy int32
+}
+
+// A ReplicaSet distributes the reads of a repository over the healthy read replicas. The replicas are configured
+// as separate db clients, their names are listed at db.<connection>.replicas. Every replica is checked in the interval
+// of db.<connection>.replica_health_checks and skipped while it is unreachable. Without a healthy replica, reads go
+// to the primary.
+type ReplicaSet struct {
+	logger   mon.Logger
+	replicas []*replica
+	next     uint32
+}
+
+var defaultReplicaSets = struct {
+	lck       sync.Mutex
+	instances map[string]*ReplicaSet
+}{
+	instances: make(map[string]*ReplicaSet),
+}
+
+// ProvideReplicaSet returns the replica set of the connection shared by all repositories of the connection
+func ProvideReplicaSet(config cfg.Config, logger mon.Logger, connection string) (*ReplicaSet, error) {
+	defaultReplicaSets.lck.Lock()
+	defer defaultReplicaSets.lck.Unlock()
+
+	if instance, ok := defaultReplicaSets.instances[connection]; ok {
+		return instance, nil
+	}
+
+	replicaSet, err := NewReplicaSet(config, logger, connection)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defaultReplicaSets.instances[connection] = replicaSet
+
+	return replicaSet, nil
+}
+
+// NewReplicaSet creates the replica set of the replicas listed at db.<connection>.replicas
+func NewReplicaSet(config cfg.Config, logger mon.Logger, connection string) (*ReplicaSet, error) {
+	settings := OrmSettings{}
+	config.UnmarshalKey(fmt.Sprintf("db.%s", connection), &settings)
+
+	orms := make(map[string]*gorm.DB, len(settings.Replicas))
+
+	for _, name := range settings.Replicas {
+		orm, err := NewOrmForConnection(config, logger, name)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not create orm for replica %s: %w", name, err)
+		}
+
+		orms[name] = orm
+	}
+
+	replicaSet := NewReplicaSetWithInterfaces(logger, orms)
+
+	if len(orms) > 0 && settings.ReplicaHealthChecks > 0 {
+		go replicaSet.runHealthChecks(settings.ReplicaHealthChecks)
+	}
+
+	return replicaSet, nil
+}
+
+func NewReplicaSetWithInterfaces(logger mon.Logger, orms map[string]*gorm.DB) *ReplicaSet {
+	replicas := make([]*replica, 0, len(orms))
+
+	for name, orm := range orms {
+		replicas = append(replicas, &replica{
+			name:    name,
+			orm:     orm,
+			healthy: 1,
+		})
+	}
+
+	return &ReplicaSet{
+		logger:   logger.WithChannel("db_replicas"),
+		replicas: replicas,
+	}
+}
+
+// Pick returns the orm of the next healthy replica or false if there is none
+func (s *ReplicaSet) Pick() (*gorm.DB, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	for range s.replicas {
+		i := atomic.AddUint32(&s.next, 1) % uint32(len(s.replicas))
+
+		if atomic.LoadInt32(&s.replicas[i].healthy) == 1 {
+			return s.replicas[i].orm, true
+		}
+	}
+
+	return nil, false
+}
+
+// CheckHealth queries every replica and marks it as healthy or unhealthy
+func (s *ReplicaSet) CheckHealth() {
+	for _, r := range s.replicas {
+		err := r.orm.New().Exec("SELECT 1").Error
+
+		healthy := int32(1)
+		if err != nil {
+			healthy = 0
+		}
+
+		if previous := atomic.SwapInt32(&r.healthy, healthy); previous == healthy {
+			continue
+		}
+
+		if err != nil {
+			s.logger.Warnf("replica %s is unhealthy: %s", r.name, err.Error())
+			continue
+		}
+
+		s.logger.Infof("replica %s is healthy again", r.name)
+	}
+}
+
+func (s *ReplicaSet) runHealthChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.CheckHealth()
+	}
+}
@@ -0,0 +1,106 @@
+package db_repo_test
+
+import (
+	"context"
+	"fmt"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jinzhu/gorm"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRepository_ReadFromReplica(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	primary, replica, repo, _ := getReplicaMocks(t, now)
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	replica.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(rows)
+
+	err := repo.Read(context.Background(), id1, &MyTestModel{})
+
+	assert.NoError(t, err)
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestRepository_ReadYourWrites(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	primary, replica, repo, _ := getReplicaMocks(t, now)
+
+	primary.ExpectExec("INSERT INTO `my_test_models`").WillReturnResult(goSqlMock.NewResult(0, 1))
+	primary.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+	primary.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+
+	ctx := db_repo.WithSession(context.Background())
+
+	err := repo.Create(ctx, &MyTestModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	})
+	assert.NoError(t, err)
+
+	err = repo.Read(ctx, id1, &MyTestModel{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestRepository_ReadFromPrimaryIfReplicaIsUnhealthy(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	primary, replica, repo, replicaSet := getReplicaMocks(t, now)
+
+	replica.ExpectExec("SELECT 1").WillReturnError(fmt.Errorf("connection refused"))
+	replicaSet.CheckHealth()
+
+	primary.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+
+	err := repo.Read(context.Background(), id1, &MyTestModel{})
+	assert.NoError(t, err)
+
+	replica.ExpectExec("SELECT 1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	replicaSet.CheckHealth()
+
+	replica.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+
+	err = repo.Read(context.Background(), id1, &MyTestModel{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func getReplicaMocks(t *testing.T, now time.Time) (goSqlMock.Sqlmock, goSqlMock.Sqlmock, db_repo.Repository, *db_repo.ReplicaSet) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+
+	primaryDb, primaryMock, _ := goSqlMock.New()
+	primaryOrm, err := db_repo.NewOrmWithInterfaces(logger, primaryDb, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	replicaDb, replicaMock, _ := goSqlMock.New()
+	replicaOrm, err := db_repo.NewOrmWithInterfaces(logger, replicaDb, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	replicaSet := db_repo.NewReplicaSetWithInterfaces(logger, map[string]*gorm.DB{
+		"replica": replicaOrm,
+	})
+
+	repo := db_repo.NewReplicatedWithInterfaces(logger, tracer, primaryOrm, replicaSet, clockwork.NewFakeClockAt(now), db_repo.Settings{})
+
+	return primaryMock, replicaMock, repo, replicaSet
+}
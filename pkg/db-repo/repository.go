@@ -2,11 +2,13 @@ package db_repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/db"
 	"github.com/applike/gosoline/pkg/mdl"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/reqctx"
 	"github.com/applike/gosoline/pkg/tracing"
 	"github.com/jinzhu/gorm"
 	"github.com/jonboulle/clockwork"
@@ -17,28 +19,50 @@ import (
 )
 
 const (
-	Create = "create"
-	Read   = "read"
-	Update = "update"
-	Delete = "delete"
-	Query  = "query"
+	Create      = "create"
+	CreateBatch = "create_batch"
+	Read        = "read"
+	Update      = "update"
+	Upsert      = "upsert"
+	Delete      = "delete"
+	Query       = "query"
+	Stream      = "stream"
 )
 
-var operations = []string{Create, Read, Update, Delete, Query}
+var operations = []string{Create, CreateBatch, Read, Update, Upsert, Delete, Query, Stream}
 
 type Settings struct {
 	cfg.AppId
 	Metadata Metadata
+	// BatchSize is the maximum number of values written with a single statement by CreateBatch and Upsert
+	BatchSize int
+	// Connection is the name of the db client at db.<connection> the repository reads and writes, it defaults to
+	// DefaultConnection
+	Connection string
+	// StreamBatchSize is the number of rows Stream fetches at once, it defaults to DefaultStreamBatchSize
+	StreamBatchSize int
+	// DeadlockRetry configures the retries of writes outside of a transaction failing due to a deadlock, New reads
+	// it from db.<connection>.deadlock_retry
+	DeadlockRetry DeadlockRetrySettings
 }
 
 //go:generate mockery -name Repository
 type Repository interface {
 	Create(ctx context.Context, value ModelBased) error
+	// CreateBatch inserts the values in chunks of multi row inserts
+	CreateBatch(ctx context.Context, values []ModelBased) error
+	// Upsert inserts the values in chunks and updates the existing rows which conflict on the given columns
+	Upsert(ctx context.Context, values []ModelBased, conflictColumns ...string) error
 	Read(ctx context.Context, id *uint, out ModelBased) error
 	Update(ctx context.Context, value ModelBased) error
 	Delete(ctx context.Context, value ModelBased) error
 	Query(ctx context.Context, qb *QueryBuilder, result interface{}) error
+	// Stream calls the function for every row of the query with a new model of the type of the given model, the rows
+	// are fetched in batches
+	Stream(ctx context.Context, qb *QueryBuilder, model ModelBased, f StreamFunc) error
 	Count(ctx context.Context, qb *QueryBuilder, model ModelBased) (int, error)
+	// QueryBuilder returns a builder for queries on the fields of the field mappings of the metadata
+	QueryBuilder() *TypedQueryBuilder
 
 	GetModelId() string
 	GetModelName() string
@@ -46,11 +70,13 @@ type Repository interface {
 }
 
 type repository struct {
-	logger   mon.Logger
-	tracer   tracing.Tracer
-	orm      *gorm.DB
-	clock    clockwork.Clock
-	settings Settings
+	logger    mon.Logger
+	tracer    tracing.Tracer
+	orm       *gorm.DB
+	replicas  *ReplicaSet
+	clock     clockwork.Clock
+	deadlocks *deadlockRetrier
+	settings  Settings
 }
 
 func New(config cfg.Config, logger mon.Logger, s Settings) (*repository, error) {
@@ -59,29 +85,43 @@ func New(config cfg.Config, logger mon.Logger, s Settings) (*repository, error)
 		return nil, fmt.Errorf("can not create tracer: %w", err)
 	}
 
-	orm, err := NewOrm(config, logger)
+	if s.Connection == "" {
+		s.Connection = DefaultConnection
+	}
+
+	orm, err := NewOrmForConnection(config, logger, s.Connection)
 	if err != nil {
-		return nil, fmt.Errorf("can not create orm: %w", err)
+		return nil, fmt.Errorf("can not create orm for connection %s: %w", s.Connection, err)
 	}
 
-	orm.Callback().
-		Update().
-		After("gorm:update_time_stamp").
-		Register("gosoline:ignore_created_at_if_needed", ignoreCreatedAtIfNeeded)
+	registerRepositoryCallbacks(orm)
 	clock := clockwork.NewRealClock()
 
+	replicas, err := ProvideReplicaSet(config, logger, s.Connection)
+	if err != nil {
+		return nil, fmt.Errorf("can not create replica set: %w", err)
+	}
+
 	s.PadFromConfig(config)
+	s.DeadlockRetry = readDeadlockRetrySettings(config, s.Connection)
 
-	return NewWithInterfaces(logger, tracer, orm, clock, s), nil
+	return NewReplicatedWithInterfaces(logger, tracer, orm, replicas, clock, s), nil
 }
 
 func NewWithInterfaces(logger mon.Logger, tracer tracing.Tracer, orm *gorm.DB, clock clockwork.Clock, settings Settings) *repository {
+	return NewReplicatedWithInterfaces(logger, tracer, orm, nil, clock, settings)
+}
+
+// NewReplicatedWithInterfaces creates a repository reading from the replicas of the set, the set might be nil
+func NewReplicatedWithInterfaces(logger mon.Logger, tracer tracing.Tracer, orm *gorm.DB, replicas *ReplicaSet, clock clockwork.Clock, settings Settings) *repository {
 	return &repository{
-		logger:   logger,
-		tracer:   tracer,
-		orm:      orm,
-		clock:    clock,
-		settings: settings,
+		logger:    logger,
+		tracer:    tracer,
+		orm:       orm,
+		replicas:  replicas,
+		clock:     clock,
+		deadlocks: newDeadlockRetrier(logger, settings.DeadlockRetry),
+		settings:  settings,
 	}
 }
 
@@ -96,7 +136,9 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 	value.SetUpdatedAt(&now)
 	value.SetCreatedAt(&now)
 
-	err := r.orm.Create(value).Error
+	err := r.retryOnDeadlock(ctx, Create, func() error {
+		return r.withContext(ctx).Create(value).Error
+	})
 
 	if db.IsDuplicateEntryError(err) {
 		logger.Warnf("could not create model of type %s due to duplicate entry error: %s", modelId, err.Error())
@@ -110,7 +152,7 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 		return err
 	}
 
-	err = r.refreshAssociations(value, Create)
+	err = r.refreshAssociations(ctx, value, Create)
 
 	if err != nil {
 		logger.Errorf(err, "could not update associations of model type %v", modelId)
@@ -119,15 +161,19 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 
 	logger.Infof("created model of type %s with id %d", modelId, *value.GetId())
 
+	// read the model from the primary, a replica might not know it yet
+	ctx = WithSession(ctx)
+	reqctx.MarkWritten(ctx)
+
 	return r.Read(ctx, value.GetId(), value)
 }
 
 func (r *repository) Read(ctx context.Context, id *uint, out ModelBased) error {
 	modelId := r.GetModelId()
-	_, span := r.startSubSpan(ctx, "Get")
+	ctx, span := r.startSubSpan(ctx, "Get")
 	defer span.Finish()
 
-	err := r.orm.First(out, *id).Error
+	err := withTraceContext(ctx, r.tracer, r.readerFromContext(ctx)).First(out, *id).Error
 
 	if gorm.IsRecordNotFoundError(err) {
 		return NewRecordNotFoundError(*id, modelId, err)
@@ -146,7 +192,29 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 	now := r.clock.Now()
 	value.SetUpdatedAt(&now)
 
-	err := r.orm.Save(value).Error
+	orm := r.withContext(ctx)
+	versionable, isVersionable := value.(Versionable)
+	version := uint(0)
+
+	if isVersionable {
+		version = versionable.GetVersion()
+		versionable.SetVersion(version + 1)
+
+		orm = orm.Set(ormVersionKey, version).Where(fmt.Sprintf("%s = ?", ColumnVersion), version)
+	}
+
+	err := r.retryOnDeadlock(ctx, Update, func() error {
+		return orm.Save(value).Error
+	})
+
+	if err != nil && isVersionable {
+		versionable.SetVersion(version)
+	}
+
+	if errors.Is(err, errVersionMismatch) {
+		logger.Warnf("could not update model of type %s with id %d due to a version conflict", modelId, mdl.EmptyUintIfNil(value.GetId()))
+		return NewVersionConflictError(mdl.EmptyUintIfNil(value.GetId()), modelId, version)
+	}
 
 	if db.IsDuplicateEntryError(err) {
 		logger.Warnf("could not update model of type %s with id %d due to duplicate entry error: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
@@ -160,7 +228,7 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 		return err
 	}
 
-	err = r.refreshAssociations(value, Update)
+	err = r.refreshAssociations(ctx, value, Update)
 
 	if err != nil {
 		logger.Errorf(err, "could not update associations of model type %s with id %d", modelId, *value.GetId())
@@ -169,6 +237,9 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 
 	logger.Infof("updated model of type %s with id %d", modelId, *value.GetId())
 
+	ctx = WithSession(ctx)
+	reqctx.MarkWritten(ctx)
+
 	return r.Read(ctx, value.GetId(), value)
 }
 
@@ -176,17 +247,20 @@ func (r *repository) Delete(ctx context.Context, value ModelBased) error {
 	modelId := r.GetModelId()
 	logger := r.logger.WithContext(ctx)
 
-	_, span := r.startSubSpan(ctx, "Delete")
+	ctx, span := r.startSubSpan(ctx, "Delete")
 	defer span.Finish()
 
-	err := r.refreshAssociations(value, Delete)
+	err := r.refreshAssociations(ctx, value, Delete)
 
 	if err != nil {
 		logger.Errorf(err, "could not delete associations of model type %s with id %d", modelId, *value.GetId())
 		return err
 	}
 
-	err = r.orm.Delete(value).Error
+	err = r.retryOnDeadlock(ctx, Delete, func() error {
+		return r.withContext(ctx).Delete(value).Error
+	})
+	reqctx.MarkWritten(ctx)
 
 	if err != nil {
 		logger.Errorf(err, "could not delete model of type %s with id %d", modelId, *value.GetId())
@@ -198,10 +272,10 @@ func (r *repository) Delete(ctx context.Context, value ModelBased) error {
 }
 
 func (r *repository) Query(ctx context.Context, qb *QueryBuilder, result interface{}) error {
-	_, span := r.startSubSpan(ctx, "Query")
+	ctx, span := r.startSubSpan(ctx, "Query")
 	defer span.Finish()
 
-	db := r.orm.New()
+	db := withTraceContext(ctx, r.tracer, r.readerFromContext(ctx).New())
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -224,24 +298,52 @@ func (r *repository) Query(ctx context.Context, qb *QueryBuilder, result interfa
 		db = db.Limit(qb.page.limit)
 	}
 
+	var keysetColumnNames []string
+
+	if qb.keyset != nil {
+		var err error
+
+		if keysetColumnNames, err = keysetColumns(r.orm.NewScope(result), qb.orderBy); err != nil {
+			return err
+		}
+
+		if len(qb.keyset.after) > 0 {
+			condition, args, err := keysetCondition(qb.orderBy, qb.keyset.after)
+
+			if err != nil {
+				return err
+			}
+
+			db = db.Where(condition, args...)
+		}
+
+		db = db.Limit(qb.keyset.limit)
+	}
+
 	err := db.Find(result).Error
 
 	if gorm.IsRecordNotFoundError(err) {
 		return NewNoQueryResultsError(r.GetModelId(), err)
 	}
 
+	if err != nil || qb.keyset == nil {
+		return err
+	}
+
+	qb.keyset.next, err = nextKeyset(r.orm, keysetColumnNames, result, qb.keyset.limit)
+
 	return err
 }
 
 func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBased) (int, error) {
-	_, span := r.startSubSpan(ctx, "Count")
+	ctx, span := r.startSubSpan(ctx, "Count")
 	defer span.Finish()
 
 	var result = struct {
 		Count int
 	}{}
 
-	db := r.orm.New()
+	db := withTraceContext(ctx, r.tracer, r.readerFromContext(ctx).New())
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -261,7 +363,11 @@ func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBas
 	return result.Count, err
 }
 
-func (r *repository) refreshAssociations(model interface{}, op string) error {
+func (r *repository) QueryBuilder() *TypedQueryBuilder {
+	return NewTypedQueryBuilder(r.settings.Metadata)
+}
+
+func (r *repository) refreshAssociations(ctx context.Context, model interface{}, op string) error {
 	typeReflection := reflect.TypeOf(model).Elem()
 	valueReflection := reflect.ValueOf(model).Elem()
 
@@ -302,7 +408,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 		case Update:
 			switch scopeField.Relationship.Kind {
 			case "many_to_many":
-				err = r.orm.Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
+				err = r.withContext(ctx).Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
 
 			default:
 				assocIds := readIdsFromReflectValue(values)
@@ -319,7 +425,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 					qry = qry + fmt.Sprintf(" AND %s NOT IN (%s)", "id", strings.Join(assocIds, ","))
 				}
 
-				err = r.orm.Exec(qry).Error
+				err = r.withContext(ctx).Exec(qry).Error
 			}
 
 		case Delete:
@@ -333,10 +439,10 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 				}
 
 				qry := fmt.Sprintf("DELETE FROM %s WHERE %s = %d", tableName, scopeField.Relationship.ForeignDBNames[0], id)
-				err = r.orm.Exec(qry).Error
+				err = r.withContext(ctx).Exec(qry).Error
 
 			default:
-				err = r.orm.Model(model).Association(field.Name).Clear().Error
+				err = r.withContext(ctx).Model(model).Association(field.Name).Clear().Error
 			}
 
 		default:
@@ -363,6 +469,49 @@ func (r *repository) GetMetadata() Metadata {
 	return r.settings.Metadata
 }
 
+func (r *repository) retryOnDeadlock(ctx context.Context, op string, f func() error) error {
+	return r.deadlocks.run(ctx, map[string]string{
+		"Operation": op,
+		"ModelId":   r.GetModelId(),
+	}, f)
+}
+
+func (r *repository) withContext(ctx context.Context) *gorm.DB {
+	return withTraceContext(ctx, r.tracer, r.ormFromContext(ctx))
+}
+
+// ormFromContext returns the orm of the transaction the context carries or the orm of the repository otherwise. A
+// transaction on another connection is ignored.
+func (r *repository) ormFromContext(ctx context.Context) *gorm.DB {
+	if tx, ok := transactionFromContext(ctx); ok && tx.connection == r.connection() {
+		return tx.orm
+	}
+
+	return r.orm
+}
+
+func (r *repository) connection() string {
+	if r.settings.Connection == "" {
+		return DefaultConnection
+	}
+
+	return r.settings.Connection
+}
+
+// readerFromContext returns the orm of a replica if the context neither carries a transaction nor a session
+// which already wrote something
+func (r *repository) readerFromContext(ctx context.Context) *gorm.DB {
+	if InTransaction(ctx) || reqctx.HasWritten(ctx) {
+		return r.ormFromContext(ctx)
+	}
+
+	if orm, ok := r.replicas.Pick(); ok {
+		return orm
+	}
+
+	return r.orm
+}
+
 func (r *repository) startSubSpan(ctx context.Context, action string) (context.Context, tracing.Span) {
 	modelName := r.GetModelId()
 	spanName := fmt.Sprintf("db_repo.%v.%v", modelName, action)
@@ -384,6 +533,13 @@ func readIdsFromReflectValue(values reflect.Value) []string {
 	return ids
 }
 
+func registerRepositoryCallbacks(orm *gorm.DB) {
+	orm.Callback().
+		Update().
+		After("gorm:update_time_stamp").
+		Register("gosoline:ignore_created_at_if_needed", ignoreCreatedAtIfNeeded)
+}
+
 func ignoreCreatedAtIfNeeded(scope *gorm.Scope) {
 	// if you perform an update and do not specify the CreatedAt field on your data, gorm will set it to time.Time{}
 	// (0000-00-00 00:00:00 in mysql). To avoid this, we mark the field as ignored if it is empty
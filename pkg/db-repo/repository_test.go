@@ -8,8 +8,11 @@ import (
 	"github.com/applike/gosoline/pkg/mdl"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/tracing"
+	tracingMocks "github.com/applike/gosoline/pkg/tracing/mocks"
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
 	"testing"
 	"time"
 )
@@ -279,6 +282,47 @@ func TestRepository_CreateHasMany(t *testing.T) {
 	assert.Equal(t, &now, model.CreatedAt)
 }
 
+func TestRepository_ReadTracesQuery(t *testing.T) {
+	ctx := context.Background()
+	logger := monMocks.NewLoggerMockedAll()
+
+	repoSpan := new(tracingMocks.Span)
+	repoSpan.On("AddMetadata", "model", mock.Anything).Once()
+	repoSpan.On("Finish").Once()
+
+	querySpan := new(tracingMocks.Span)
+	querySpan.On("AddAnnotation", "table", "my_test_models").Once()
+	querySpan.On("AddMetadata", "sql", mock.MatchedBy(func(sql string) bool {
+		return strings.HasPrefix(sql, "SELECT * FROM `my_test_models`") && !strings.Contains(sql, "42")
+	})).Once()
+	querySpan.On("AddMetadata", "rows", int64(1)).Once()
+	querySpan.On("Finish").Once()
+
+	tracer := new(tracingMocks.Tracer)
+	tracer.On("StartSubSpan", ctx, mock.AnythingOfType("string")).Return(ctx, repoSpan).Once()
+	tracer.On("StartSubSpan", ctx, "db.query").Return(ctx, querySpan).Once()
+
+	db, dbc, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(logger, db, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	assert.NoError(t, err)
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, clockwork.NewFakeClock(), db_repo.Settings{})
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id42, nil, nil)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(rows)
+
+	model := &MyTestModel{}
+	err = repo.Read(ctx, id42, model)
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	tracer.AssertExpectations(t)
+	repoSpan.AssertExpectations(t)
+	querySpan.AssertExpectations(t)
+}
+
 func TestRepository_Update(t *testing.T) {
 	dbc, repo := getMocks(t)
 	now := time.Unix(1549964818, 0)
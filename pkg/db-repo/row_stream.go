@@ -0,0 +1,74 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// DefaultStreamBatchSize is the number of rows fetched at once by Stream if Settings.StreamBatchSize is not set
+const DefaultStreamBatchSize = 1000
+
+// StreamFunc is called for every row of a stream with a new model of the type the stream was started with
+type StreamFunc func(ctx context.Context, value ModelBased) error
+
+// Stream calls the function for every row matched by the query without loading all of them into memory. The rows are
+// fetched in batches of Settings.StreamBatchSize with keyset pagination, ordered by the order of the query and the
+// primary key. The query must not be paged. The stream stops at the first error returned by the function.
+func (r *repository) Stream(ctx context.Context, qb *QueryBuilder, model ModelBased, f StreamFunc) error {
+	modelId := r.GetModelId()
+
+	if qb.page != nil || qb.keyset != nil {
+		return fmt.Errorf("can not stream a paged query of models of type %s", modelId)
+	}
+
+	ctx, span := r.startSubSpan(ctx, "Stream")
+	defer span.Finish()
+
+	scope := r.orm.NewScope(model)
+	primaryKey := fmt.Sprintf("%s.%s", scope.QuotedTableName(), scope.Quote(scope.PrimaryKey()))
+
+	batchQb := *qb
+	batchQb.orderBy = make([]order, 0, len(qb.orderBy)+1)
+	batchQb.orderBy = append(batchQb.orderBy, qb.orderBy...)
+	batchQb.orderBy = append(batchQb.orderBy, order{
+		field:     primaryKey,
+		direction: "ASC",
+	})
+
+	batchSize := r.settings.StreamBatchSize
+
+	if batchSize <= 0 {
+		batchSize = DefaultStreamBatchSize
+	}
+
+	sliceType := reflect.SliceOf(reflect.TypeOf(model))
+	count := 0
+
+	var after []interface{}
+
+	for {
+		batchQb.Keyset(after, batchSize)
+		results := reflect.New(sliceType)
+
+		if err := r.Query(ctx, &batchQb, results.Interface()); err != nil {
+			return fmt.Errorf("can not query batch of models of type %s after %d rows: %w", modelId, count, err)
+		}
+
+		for i := 0; i < results.Elem().Len(); i++ {
+			if err := f(ctx, results.Elem().Index(i).Interface().(ModelBased)); err != nil {
+				return err
+			}
+
+			count++
+		}
+
+		next, ok := batchQb.NextKeyset()
+
+		if !ok {
+			return nil
+		}
+
+		after = next
+	}
+}
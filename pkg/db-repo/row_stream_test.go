@@ -0,0 +1,93 @@
+package db_repo_test
+
+import (
+	"context"
+	"fmt"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRepository_Stream(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getStreamMocks(t, 2)
+
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` +WHERE \\(updated_at > \\?\\) ORDER BY `my_test_models`\\.`id` ASC LIMIT 2").
+		WithArgs(&now).
+		WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(1, &now, &now).AddRow(2, &now, &now))
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` +WHERE \\(updated_at > \\?\\) AND \\(\\(\\(`my_test_models`\\.`id` > \\?\\)\\)\\) ORDER BY `my_test_models`\\.`id` ASC LIMIT 2").
+		WithArgs(&now, 2).
+		WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(3, &now, &now))
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Where("updated_at > ?", &now)
+
+	ids := make([]uint, 0)
+
+	err := repo.Stream(context.Background(), qb, &MyTestModel{}, func(ctx context.Context, value db_repo.ModelBased) error {
+		ids = append(ids, *value.(*MyTestModel).Id)
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 3}, ids)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_StreamStopsOnError(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getStreamMocks(t, 2)
+
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` +ORDER BY `my_test_models`\\.`id` ASC LIMIT 2").
+		WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(1, &now, &now).AddRow(2, &now, &now))
+
+	calls := 0
+
+	err := repo.Stream(context.Background(), db_repo.NewQueryBuilder(), &MyTestModel{}, func(ctx context.Context, value db_repo.ModelBased) error {
+		calls++
+
+		return fmt.Errorf("export failed")
+	})
+
+	assert.EqualError(t, err, "export failed")
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_StreamPaged(t *testing.T) {
+	_, repo := getStreamMocks(t, 2)
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Page(0, 10)
+
+	err := repo.Stream(context.Background(), qb, &MyTestModel{}, func(ctx context.Context, value db_repo.ModelBased) error {
+		return nil
+	})
+
+	assert.EqualError(t, err, "can not stream a paged query of models of type ...")
+}
+
+func getStreamMocks(t *testing.T, batchSize int) (goSqlMock.Sqlmock, db_repo.Repository) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+
+	dbClient, clientMock, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbClient, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, clockwork.NewFakeClock(), db_repo.Settings{
+		StreamBatchSize: batchSize,
+	})
+
+	return clientMock, repo
+}
@@ -0,0 +1,98 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jinzhu/gorm"
+	"regexp"
+)
+
+const (
+	ormTraceContextKey = "gosoline:trace_context"
+	ormTraceSpanKey    = "gosoline:trace_span"
+)
+
+var (
+	sqlStringLiteral  = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'`)
+	sqlNumericLiteral = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+)
+
+type ormTraceContext struct {
+	ctx    context.Context
+	tracer tracing.Tracer
+}
+
+// withTraceContext attaches the context to the orm, so the queries it performs are traced as sub spans of the context
+func withTraceContext(ctx context.Context, tracer tracing.Tracer, orm *gorm.DB) *gorm.DB {
+	return orm.Set(ormTraceContextKey, &ormTraceContext{
+		ctx:    ctx,
+		tracer: tracer,
+	})
+}
+
+func registerTracingCallbacks(orm *gorm.DB) {
+	callbacks := orm.Callback()
+
+	callbacks.Create().Before("gorm:create").Register("gosoline:trace_before_create", startQuerySpan("create"))
+	callbacks.Create().After("gorm:create").Register("gosoline:trace_after_create", finishQuerySpan)
+	callbacks.Query().Before("gorm:query").Register("gosoline:trace_before_query", startQuerySpan("query"))
+	callbacks.Query().After("gorm:query").Register("gosoline:trace_after_query", finishQuerySpan)
+	callbacks.RowQuery().Before("gorm:row_query").Register("gosoline:trace_before_row_query", startQuerySpan("row_query"))
+	callbacks.RowQuery().After("gorm:row_query").Register("gosoline:trace_after_row_query", finishQuerySpan)
+	callbacks.Update().Before("gorm:update").Register("gosoline:trace_before_update", startQuerySpan("update"))
+	callbacks.Update().After("gorm:update").Register("gosoline:trace_after_update", finishQuerySpan)
+	callbacks.Delete().Before("gorm:delete").Register("gosoline:trace_before_delete", startQuerySpan("delete"))
+	callbacks.Delete().After("gorm:delete").Register("gosoline:trace_after_delete", finishQuerySpan)
+}
+
+func startQuerySpan(operation string) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		value, ok := scope.Get(ormTraceContextKey)
+		if !ok {
+			return
+		}
+
+		traceContext, ok := value.(*ormTraceContext)
+		if !ok || traceContext.tracer == nil {
+			return
+		}
+
+		_, span := traceContext.tracer.StartSubSpan(traceContext.ctx, fmt.Sprintf("db.%s", operation))
+
+		if scope.Value != nil {
+			span.AddAnnotation("table", scope.TableName())
+		}
+
+		scope.Set(ormTraceSpanKey, span)
+	}
+}
+
+func finishQuerySpan(scope *gorm.Scope) {
+	value, ok := scope.Get(ormTraceSpanKey)
+	if !ok {
+		return
+	}
+
+	span, ok := value.(tracing.Span)
+	if !ok {
+		return
+	}
+
+	defer span.Finish()
+
+	span.AddMetadata("sql", sanitizeSql(scope.SQL))
+	span.AddMetadata("rows", scope.DB().RowsAffected)
+
+	if err := scope.DB().Error; err != nil && !gorm.IsRecordNotFoundError(err) {
+		span.AddError(err)
+	}
+}
+
+// sanitizeSql replaces string and numeric literals, so statements built without placeholders don't leak values
+func sanitizeSql(sql string) string {
+	sql = sqlStringLiteral.ReplaceAllString(sql, "?")
+	sql = sqlNumericLiteral.ReplaceAllString(sql, "?")
+
+	return sql
+}
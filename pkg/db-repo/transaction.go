@@ -0,0 +1,191 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jinzhu/gorm"
+)
+
+type contextTransactionKeyType int
+
+var contextTransactionKey = new(contextTransactionKeyType)
+
+// TxFunc is called with a context carrying the transaction. Every repository operation using this context is
+// part of the transaction.
+type TxFunc func(ctx context.Context) error
+
+//go:generate mockery -name TransactionRunner
+type TransactionRunner interface {
+	// WithTx runs the function inside a transaction. The transaction is committed if the function succeeds and rolled
+	// back if it returns an error or panics. If the context already carries a transaction, a savepoint is used
+	// instead, so only the changes of the inner function are rolled back on an error. A transaction failing due to a
+	// deadlock is retried as a whole, so the function might be called more than once.
+	WithTx(ctx context.Context, f TxFunc) error
+}
+
+type transaction struct {
+	orm         *gorm.DB
+	connection  string
+	savepoints  int
+	afterCommit []func()
+}
+
+type transactionRunner struct {
+	logger     mon.Logger
+	orm        *gorm.DB
+	connection string
+	deadlocks  *deadlockRetrier
+}
+
+func NewTransactionRunner(config cfg.Config, logger mon.Logger) (*transactionRunner, error) {
+	return NewTransactionRunnerForConnection(config, logger, DefaultConnection)
+}
+
+// NewTransactionRunnerForConnection runs transactions on the db client at db.<connection>. Only the repositories of
+// the same connection take part in the transactions.
+func NewTransactionRunnerForConnection(config cfg.Config, logger mon.Logger, connection string) (*transactionRunner, error) {
+	orm, err := NewOrmForConnection(config, logger, connection)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create orm for connection %s: %w", connection, err)
+	}
+
+	registerRepositoryCallbacks(orm)
+	deadlockRetry := readDeadlockRetrySettings(config, connection)
+
+	return NewTransactionRunnerWithInterfaces(logger, orm, connection, deadlockRetry), nil
+}
+
+func NewTransactionRunnerWithInterfaces(logger mon.Logger, orm *gorm.DB, connection string, deadlockRetry DeadlockRetrySettings) *transactionRunner {
+	return &transactionRunner{
+		logger:     logger,
+		orm:        orm,
+		connection: connection,
+		deadlocks:  newDeadlockRetrier(logger, deadlockRetry),
+	}
+}
+
+func (r *transactionRunner) WithTx(ctx context.Context, f TxFunc) error {
+	if tx, ok := transactionFromContext(ctx); ok && tx.connection != r.connection {
+		return fmt.Errorf("can not start a transaction on connection %s inside of a transaction on connection %s", r.connection, tx.connection)
+	}
+
+	if tx, ok := transactionFromContext(ctx); ok {
+		return r.withSavepoint(ctx, tx, f)
+	}
+
+	return r.deadlocks.run(ctx, map[string]string{
+		"Operation":  operationTransaction,
+		"Connection": r.connection,
+	}, func() error {
+		return r.withTransaction(ctx, f)
+	})
+}
+
+func (r *transactionRunner) withTransaction(ctx context.Context, f TxFunc) error {
+	orm := r.orm.Begin()
+
+	if orm.Error != nil {
+		return fmt.Errorf("can not begin transaction: %w", orm.Error)
+	}
+
+	tx := &transaction{
+		orm:        orm,
+		connection: r.connection,
+	}
+
+	err := r.run(ctx, tx, f, func() error {
+		return tx.orm.Commit().Error
+	}, func() error {
+		return tx.orm.Rollback().Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range tx.afterCommit {
+		hook()
+	}
+
+	return nil
+}
+
+func (r *transactionRunner) withSavepoint(ctx context.Context, tx *transaction, f TxFunc) error {
+	tx.savepoints++
+	savepoint := fmt.Sprintf("gosoline_savepoint_%d", tx.savepoints)
+
+	if err := tx.orm.Exec(fmt.Sprintf("SAVEPOINT %s", savepoint)).Error; err != nil {
+		return fmt.Errorf("can not create savepoint %s: %w", savepoint, err)
+	}
+
+	hooks := len(tx.afterCommit)
+
+	return r.run(ctx, tx, f, func() error {
+		return tx.orm.Exec(fmt.Sprintf("RELEASE SAVEPOINT %s", savepoint)).Error
+	}, func() error {
+		tx.afterCommit = tx.afterCommit[:hooks]
+
+		return tx.orm.Exec(fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", savepoint)).Error
+	})
+}
+
+func (r *transactionRunner) run(ctx context.Context, tx *transaction, f TxFunc, commit func() error, rollback func() error) (err error) {
+	logger := r.logger.WithContext(ctx)
+
+	defer func() {
+		recovered := recover()
+
+		if recovered == nil {
+			return
+		}
+
+		if rollbackErr := rollback(); rollbackErr != nil {
+			logger.Error(rollbackErr, "can not roll back transaction after panic")
+		}
+
+		panic(recovered)
+	}()
+
+	if err = f(context.WithValue(ctx, contextTransactionKey, tx)); err != nil {
+		if rollbackErr := rollback(); rollbackErr != nil {
+			logger.Error(rollbackErr, "can not roll back transaction")
+		}
+
+		return err
+	}
+
+	if err = commit(); err != nil {
+		return fmt.Errorf("can not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// InTransaction returns true if the context carries a transaction started with a TransactionRunner
+func InTransaction(ctx context.Context) bool {
+	_, ok := transactionFromContext(ctx)
+
+	return ok
+}
+
+// afterCommit runs the hook once the transaction of the context is committed. The hook is dropped if the
+// transaction or the savepoint it was added in is rolled back. Without a transaction, the hook runs immediately.
+func afterCommit(ctx context.Context, hook func()) {
+	tx, ok := transactionFromContext(ctx)
+
+	if !ok {
+		hook()
+		return
+	}
+
+	tx.afterCommit = append(tx.afterCommit, hook)
+}
+
+func transactionFromContext(ctx context.Context) (*transaction, bool) {
+	tx, ok := ctx.Value(contextTransactionKey).(*transaction)
+
+	return tx, ok
+}
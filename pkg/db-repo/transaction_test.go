@@ -0,0 +1,151 @@
+package db_repo_test
+
+import (
+	"context"
+	"fmt"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTransactionRunner_WithTx(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, runner := getTransactionMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(rows)
+	dbc.ExpectCommit()
+
+	err := runner.WithTx(context.Background(), func(ctx context.Context) error {
+		assert.True(t, db_repo.InTransaction(ctx))
+
+		return repo.Create(ctx, &MyTestModel{
+			Model: db_repo.Model{
+				Id: id1,
+			},
+		})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestTransactionRunner_WithTx_Rollback(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, runner := getTransactionMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WillReturnError(fmt.Errorf("connection lost"))
+	dbc.ExpectRollback()
+
+	err := runner.WithTx(context.Background(), func(ctx context.Context) error {
+		return repo.Create(ctx, &MyTestModel{
+			Model: db_repo.Model{
+				Id: id1,
+			},
+		})
+	})
+
+	assert.EqualError(t, err, "connection lost")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestTransactionRunner_WithTx_Panic(t *testing.T) {
+	dbc, _, runner := getTransactionMocks(t, time.Now())
+
+	dbc.ExpectBegin()
+	dbc.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = runner.WithTx(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestTransactionRunner_WithTx_Savepoint(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo, runner := getTransactionMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("SAVEPOINT gosoline_savepoint_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WillReturnError(fmt.Errorf("duplicate"))
+	dbc.ExpectExec("ROLLBACK TO SAVEPOINT gosoline_savepoint_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	dbc.ExpectExec("SAVEPOINT gosoline_savepoint_2").WillReturnResult(goSqlMock.NewResult(0, 0))
+	dbc.ExpectExec("RELEASE SAVEPOINT gosoline_savepoint_2").WillReturnResult(goSqlMock.NewResult(0, 0))
+	dbc.ExpectCommit()
+
+	err := runner.WithTx(context.Background(), func(ctx context.Context) error {
+		err := runner.WithTx(ctx, func(ctx context.Context) error {
+			return repo.Create(ctx, &MyTestModel{
+				Model: db_repo.Model{
+					Id: id1,
+				},
+			})
+		})
+		assert.EqualError(t, err, "duplicate")
+
+		return runner.WithTx(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestTransactionRunner_WithTx_OtherConnection(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, _, runner := getTransactionMocks(t, now)
+
+	logger := monMocks.NewLoggerMockedAll()
+	db, reportingMock, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(logger, db, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	reportingRunner := db_repo.NewTransactionRunnerWithInterfaces(logger, orm, "reporting", db_repo.DeadlockRetrySettings{})
+
+	dbc.ExpectBegin()
+	dbc.ExpectRollback()
+
+	err = runner.WithTx(context.Background(), func(ctx context.Context) error {
+		return reportingRunner.WithTx(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	assert.EqualError(t, err, "can not start a transaction on connection reporting inside of a transaction on connection default")
+	assert.NoError(t, dbc.ExpectationsWereMet())
+	assert.NoError(t, reportingMock.ExpectationsWereMet())
+}
+
+func getTransactionMocks(t *testing.T, now time.Time) (goSqlMock.Sqlmock, db_repo.Repository, db_repo.TransactionRunner) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+
+	db, clientMock, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(logger, db, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, clockwork.NewFakeClockAt(now), db_repo.Settings{})
+	runner := db_repo.NewTransactionRunnerWithInterfaces(logger, orm, db_repo.DefaultConnection, db_repo.DeadlockRetrySettings{})
+
+	return clientMock, repo, runner
+}
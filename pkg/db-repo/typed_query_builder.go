@@ -0,0 +1,237 @@
+package db_repo
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	OpEq        = "="
+	OpNeq       = "!="
+	OpLt        = "<"
+	OpLte       = "<="
+	OpGt        = ">"
+	OpGte       = ">="
+	OpLike      = "LIKE"
+	OpIn        = "IN"
+	OpNotIn     = "NOT IN"
+	OpIsNull    = "IS NULL"
+	OpIsNotNull = "IS NOT NULL"
+
+	OrderAsc  = "ASC"
+	OrderDesc = "DESC"
+)
+
+var typedOperators = map[string]bool{
+	OpEq:        true,
+	OpNeq:       true,
+	OpLt:        true,
+	OpLte:       true,
+	OpGt:        true,
+	OpGte:       true,
+	OpLike:      true,
+	OpIn:        true,
+	OpNotIn:     true,
+	OpIsNull:    true,
+	OpIsNotNull: true,
+}
+
+// TypedQueryBuilder builds queries on the fields of the field mappings of a repository. Only mapped fields can be
+// used, the mapping provides the columns and joins of a field, and values are always passed as arguments. Errors are
+// collected and returned by Build, so the methods can be chained.
+type TypedQueryBuilder struct {
+	metadata Metadata
+	joins    []string
+	where    []string
+	args     [][]interface{}
+	orderBy  []order
+	offset   int
+	limit    *int
+	errors   []string
+}
+
+func NewTypedQueryBuilder(metadata Metadata) *TypedQueryBuilder {
+	return &TypedQueryBuilder{
+		metadata: metadata,
+		joins:    make([]string, 0),
+		where:    make([]string, 0),
+		args:     make([][]interface{}, 0),
+		orderBy:  make([]order, 0),
+		errors:   make([]string, 0),
+	}
+}
+
+// Where adds a condition on the field which is combined with all other conditions by AND. OpIn and OpNotIn take any
+// number of values, OpIsNull and OpIsNotNull none and all other operators exactly one value.
+func (qb *TypedQueryBuilder) Where(field string, operator string, values ...interface{}) *TypedQueryBuilder {
+	mapping, ok := qb.mapping(field)
+
+	if !ok {
+		return qb
+	}
+
+	operator = strings.ToUpper(operator)
+
+	if !typedOperators[operator] {
+		return qb.addError("invalid operator %s for field %s", operator, field)
+	}
+
+	switch operator {
+	case OpIsNull, OpIsNotNull:
+		if len(values) != 0 {
+			return qb.addError("the operator %s of field %s does not take any values", operator, field)
+		}
+	case OpIn, OpNotIn:
+		if len(values) == 0 {
+			return qb.addError("the operator %s of field %s needs at least one value", operator, field)
+		}
+	default:
+		if len(values) != 1 {
+			return qb.addError("the operator %s of field %s takes exactly one value", operator, field)
+		}
+	}
+
+	stmts := make([]string, 0, len(mapping.Columns()))
+	args := make([]interface{}, 0, len(mapping.Columns())*len(values))
+
+	for _, column := range mapping.Columns() {
+		stmt, columnArgs := buildTypedCondition(column, operator, values)
+
+		stmts = append(stmts, stmt)
+		args = append(args, columnArgs...)
+	}
+
+	qb.joins = append(qb.joins, mapping.Joins()...)
+	qb.where = append(qb.where, fmt.Sprintf("(%s)", strings.Join(stmts, fmt.Sprintf(" %s ", mapping.Bool()))))
+	qb.args = append(qb.args, args)
+
+	return qb
+}
+
+func (qb *TypedQueryBuilder) OrderBy(field string, direction string) *TypedQueryBuilder {
+	mapping, ok := qb.mapping(field)
+
+	if !ok {
+		return qb
+	}
+
+	direction = strings.ToUpper(direction)
+
+	if direction != OrderAsc && direction != OrderDesc {
+		return qb.addError("invalid direction %s for order field %s", direction, field)
+	}
+
+	qb.joins = append(qb.joins, mapping.Joins()...)
+	qb.orderBy = append(qb.orderBy, order{
+		field:     strings.Join(mapping.ColumnNames(), ", "),
+		direction: direction,
+	})
+
+	return qb
+}
+
+func (qb *TypedQueryBuilder) Limit(limit int) *TypedQueryBuilder {
+	if limit < 0 {
+		return qb.addError("the limit must not be negative")
+	}
+
+	qb.limit = &limit
+
+	return qb
+}
+
+// Offset skips the first results, it has to be combined with a Limit
+func (qb *TypedQueryBuilder) Offset(offset int) *TypedQueryBuilder {
+	if offset < 0 {
+		return qb.addError("the offset must not be negative")
+	}
+
+	qb.offset = offset
+
+	return qb
+}
+
+// Build returns a QueryBuilder to use with Query and Count of the repository or the errors of the previous calls
+func (qb *TypedQueryBuilder) Build() (*QueryBuilder, error) {
+	if qb.offset > 0 && qb.limit == nil {
+		qb.addError("an offset needs a limit")
+	}
+
+	if len(qb.errors) > 0 {
+		return nil, fmt.Errorf("invalid query for model %s: %s", qb.metadata.ModelId.Name, strings.Join(qb.errors, ", "))
+	}
+
+	result := NewQueryBuilder()
+	result.Table(qb.metadata.TableName)
+	result.Joins(qb.joins)
+
+	for i := range qb.where {
+		result.Where(qb.where[i], qb.args[i]...)
+	}
+
+	// joins can multiply the rows of a model
+	if len(qb.joins) > 0 && qb.metadata.TableName != "" && qb.metadata.PrimaryKey != "" {
+		result.GroupBy(qb.metadata.PrimaryKey)
+	}
+
+	for _, o := range qb.orderBy {
+		result.OrderBy(o.field, o.direction.(string))
+	}
+
+	if qb.limit != nil {
+		result.Page(qb.offset, *qb.limit)
+	}
+
+	return result, nil
+}
+
+func (qb *TypedQueryBuilder) mapping(field string) (FieldMapping, bool) {
+	mapping, ok := qb.metadata.Mappings[field]
+
+	if !ok {
+		qb.addError("the field %s is not mapped", field)
+	}
+
+	return mapping, ok
+}
+
+func (qb *TypedQueryBuilder) addError(format string, args ...interface{}) *TypedQueryBuilder {
+	qb.errors = append(qb.errors, fmt.Sprintf(format, args...))
+
+	return qb
+}
+
+func buildTypedCondition(column FieldMappingColumn, operator string, values []interface{}) (string, []interface{}) {
+	name := column.Name()
+	distinctNull := column.NullMode() == NullModeDistinct
+
+	switch operator {
+	case OpIsNull, OpIsNotNull:
+		return fmt.Sprintf("%s %s", name, operator), []interface{}{}
+
+	case OpIn, OpNotIn:
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
+
+		if operator == OpNotIn && distinctNull {
+			return fmt.Sprintf("(%s NOT IN (%s) OR %s IS NULL)", name, placeholders, name), values
+		}
+
+		return fmt.Sprintf("%s %s (%s)", name, operator, placeholders), values
+
+	case OpEq:
+		if values[0] == nil {
+			return fmt.Sprintf("%s IS NULL", name), []interface{}{}
+		}
+
+	case OpNeq:
+		if values[0] == nil {
+			return fmt.Sprintf("%s IS NOT NULL", name), []interface{}{}
+		}
+
+		if distinctNull {
+			return fmt.Sprintf("(%s != ? OR %s IS NULL)", name, name), values
+		}
+	}
+
+	return fmt.Sprintf("%s %s ?", name, operator), values
+}
@@ -0,0 +1,83 @@
+package db_repo_test
+
+import (
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var typedMetadata = db_repo.Metadata{
+	ModelId: mdl.ModelId{
+		Name: "item",
+	},
+	TableName:  "items",
+	PrimaryKey: "items.id",
+	Mappings: db_repo.FieldMappings{
+		"name":     db_repo.NewFieldMapping("items.name"),
+		"deleted":  db_repo.NewFieldMappingWithMode("items.deleted_at", db_repo.NullModeDistinct),
+		"category": db_repo.NewFieldMapping("categories.name").WithJoin("JOIN categories ON categories.id = items.category_id"),
+		"text":     db_repo.NewFieldMapping("items.title").WithColumn("items.description"),
+	},
+}
+
+func TestTypedQueryBuilder_Build(t *testing.T) {
+	qb, err := db_repo.NewTypedQueryBuilder(typedMetadata).
+		Where("name", db_repo.OpEq, "foo").
+		Where("text", "like", "%bar%").
+		Where("deleted", db_repo.OpNeq, "2020-01-01").
+		OrderBy("name", "desc").
+		Limit(10).
+		Offset(20).
+		Build()
+
+	assert.NoError(t, err)
+
+	expected := db_repo.NewQueryBuilder()
+	expected.Table("items")
+	expected.Joins([]string{})
+	expected.Where("(items.name = ?)", "foo")
+	expected.Where("(items.title LIKE ? OR items.description LIKE ?)", "%bar%", "%bar%")
+	expected.Where("((items.deleted_at != ? OR items.deleted_at IS NULL))", "2020-01-01")
+	expected.OrderBy("items.name", "DESC")
+	expected.Page(20, 10)
+
+	assert.Equal(t, expected, qb)
+}
+
+func TestTypedQueryBuilder_BuildWithJoin(t *testing.T) {
+	qb, err := db_repo.NewTypedQueryBuilder(typedMetadata).
+		Where("category", db_repo.OpIn, "books", "games").
+		Where("name", db_repo.OpIsNotNull).
+		OrderBy("category", db_repo.OrderAsc).
+		Build()
+
+	assert.NoError(t, err)
+
+	expected := db_repo.NewQueryBuilder()
+	expected.Table("items")
+	expected.Joins([]string{"JOIN categories ON categories.id = items.category_id"})
+	expected.Where("(categories.name IN (?,?))", "books", "games")
+	expected.Where("(items.name IS NOT NULL)", []interface{}{}...)
+	expected.GroupBy("items.id")
+	expected.OrderBy("categories.name", "ASC")
+
+	assert.Equal(t, expected, qb)
+}
+
+func TestTypedQueryBuilder_BuildInvalid(t *testing.T) {
+	_, err := db_repo.NewTypedQueryBuilder(typedMetadata).
+		Where("password", db_repo.OpEq, "secret").
+		Where("name", "; DROP TABLE items", "foo").
+		Where("name", db_repo.OpEq).
+		OrderBy("name", "sideways").
+		Offset(5).
+		Build()
+
+	assert.EqualError(t, err, "invalid query for model item: "+
+		"the field password is not mapped, "+
+		"invalid operator ; DROP TABLE ITEMS for field name, "+
+		"the operator = of field name takes exactly one value, "+
+		"invalid direction SIDEWAYS for order field name, "+
+		"an offset needs a limit")
+}
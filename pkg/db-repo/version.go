@@ -0,0 +1,26 @@
+package db_repo
+
+import (
+	"errors"
+	"github.com/jinzhu/gorm"
+)
+
+const ormVersionKey = "gosoline:version"
+
+var errVersionMismatch = errors.New("the version of the model does not match")
+
+func registerVersionCallbacks(orm *gorm.DB) {
+	orm.Callback().Update().After("gorm:update").Register("gosoline:check_version", checkVersion)
+}
+
+// checkVersion fails the update of a Versionable model if no row matched the version, otherwise gorm would treat the
+// update as an insert of a missing row
+func checkVersion(scope *gorm.Scope) {
+	if _, ok := scope.Get(ormVersionKey); !ok || scope.HasError() {
+		return
+	}
+
+	if scope.DB().RowsAffected == 0 {
+		_ = scope.Err(errVersionMismatch)
+	}
+}
@@ -0,0 +1,66 @@
+package db_repo_test
+
+import (
+	"context"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type MyVersionedModel struct {
+	db_repo.Model
+	db_repo.Versioned
+}
+
+func TestRepository_UpdateVersioned(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	dbc.ExpectExec("UPDATE `my_versioned_models` SET .* WHERE `my_versioned_models`\\.`id` = \\? AND \\(\\(version = \\?\\)\\)").
+		WithArgs(goSqlMock.AnyArg(), goSqlMock.AnyArg(), int64(4), id1, int64(3)).
+		WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "version"}).AddRow(id1, &now, &now, 4)
+	dbc.ExpectQuery("SELECT \\* FROM `my_versioned_models`").WillReturnRows(rows)
+
+	model := &MyVersionedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+		Versioned: db_repo.Versioned{
+			Version: 3,
+		},
+	}
+
+	err := repo.Update(context.Background(), model)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), model.Version)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateVersionConflict(t *testing.T) {
+	dbc, repo := getTimedMocks(t, time.Unix(1549964818, 0))
+
+	dbc.ExpectExec("UPDATE `my_versioned_models` SET .* WHERE `my_versioned_models`\\.`id` = \\? AND \\(\\(version = \\?\\)\\)").
+		WithArgs(goSqlMock.AnyArg(), goSqlMock.AnyArg(), int64(4), id1, int64(3)).
+		WillReturnResult(goSqlMock.NewResult(0, 0))
+
+	model := &MyVersionedModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+		Versioned: db_repo.Versioned{
+			Version: 3,
+		},
+	}
+
+	err := repo.Update(context.Background(), model)
+
+	assert.True(t, db_repo.IsVersionConflictError(err), "the error should be a version conflict error")
+	assert.EqualError(t, err, "could not update model of type ... with id 1: version 3 is outdated")
+	assert.Equal(t, uint(3), model.Version)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
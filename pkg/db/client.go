@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/jmoiron/sqlx"
 	"reflect"
 	"strconv"
+	"time"
 )
 
 const (
@@ -27,6 +29,7 @@ type Result []ResultRow
 
 //go:generate mockery -name Client
 type Client interface {
+	Begin() (*sql.Tx, error)
 	GetSingleScalarValue(query string, args ...interface{}) (int, error)
 	GetResult(query string, args ...interface{}) (*Result, error)
 	Exec(query string, args ...interface{}) (sql.Result, error)
@@ -39,8 +42,10 @@ type Client interface {
 }
 
 type ClientSqlx struct {
-	logger mon.Logger
-	db     *sqlx.DB
+	logger             mon.Logger
+	clock              clock.Clock
+	db                 *sqlx.DB
+	slowQueryThreshold time.Duration
 }
 
 func NewClient(config cfg.Config, logger mon.Logger, name string) (Client, error) {
@@ -50,13 +55,17 @@ func NewClient(config cfg.Config, logger mon.Logger, name string) (Client, error
 		return nil, fmt.Errorf("can not connect to sql database: %w", err)
 	}
 
-	return NewClientWithInterfaces(logger, db), nil
+	settings := createSettings(config, name)
+
+	return NewClientWithInterfaces(logger, clock.NewRealClock(), db, settings.SlowQueryThreshold), nil
 }
 
-func NewClientWithInterfaces(logger mon.Logger, db *sqlx.DB) Client {
+func NewClientWithInterfaces(logger mon.Logger, clock clock.Clock, db *sqlx.DB, slowQueryThreshold time.Duration) Client {
 	return &ClientSqlx{
-		logger: logger.WithContext(context.Background()), // TODO: this is not nice, but we don't (yet) have a context when logging in this module
-		db:     db,
+		logger:             logger.WithContext(context.Background()), // TODO: this is not nice, but we don't (yet) have a context when logging in this module
+		clock:              clock,
+		db:                 db,
+		slowQueryThreshold: slowQueryThreshold,
 	}
 }
 
@@ -129,40 +138,61 @@ func (c *ClientSqlx) GetResult(query string, args ...interface{}) (*Result, erro
 	return &out, err
 }
 
+// Begin starts a transaction, gorm relies on it for transactions of the orm
+func (c *ClientSqlx) Begin() (*sql.Tx, error) {
+	c.logger.Debugf("> BEGIN")
+
+	return c.db.Begin()
+}
+
 func (c *ClientSqlx) Exec(query string, args ...interface{}) (sql.Result, error) {
 	c.logger.Debugf("> %s %q", query, args)
 
+	defer c.logSlowQuery(c.clock.Now(), query)
+
 	return c.db.Exec(query, args...)
 }
 
 func (c *ClientSqlx) Prepare(query string) (*sql.Stmt, error) {
+	defer c.logSlowQuery(c.clock.Now(), query)
+
 	return c.db.Prepare(query)
 }
 
 func (c *ClientSqlx) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	c.logger.Debugf("> %s %q", query, args)
 
+	defer c.logSlowQuery(c.clock.Now(), query)
+
 	return c.db.Query(query, args...)
 }
 
 func (c *ClientSqlx) QueryRow(query string, args ...interface{}) *sql.Row {
+	defer c.logSlowQuery(c.clock.Now(), query)
+
 	return c.db.QueryRow(query, args...)
 }
 
 func (c *ClientSqlx) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
 	c.logger.Debugf("> %s %q", query, args)
 
+	defer c.logSlowQuery(c.clock.Now(), query)
+
 	return c.db.Queryx(query, args...)
 }
 
 func (c *ClientSqlx) Select(dest interface{}, query string, args ...interface{}) error {
 	c.logger.Debugf("> %s %q", query, args)
 
+	defer c.logSlowQuery(c.clock.Now(), query)
+
 	return c.db.Select(dest, query, args...)
 }
 
 func (c *ClientSqlx) Get(dest interface{}, query string, args ...interface{}) error {
 	c.logger.Debugf("> %s %q", query, args)
 
+	defer c.logSlowQuery(c.clock.Now(), query)
+
 	return c.db.Get(dest, query, args...)
 }
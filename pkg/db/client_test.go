@@ -2,11 +2,17 @@ package db_test
 
 import (
 	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/clock"
+	clockMocks "github.com/applike/gosoline/pkg/clock/mocks"
 	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/mon"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetResult(t *testing.T) {
@@ -120,12 +126,37 @@ func TestClient_Exec(t *testing.T) {
 	sqlMock.ExpectClose()
 }
 
+func TestSlowQuery(t *testing.T) {
+	dbMock, sqlMock, _ := goSqlMock.New()
+	loggerMock := monMocks.NewLoggerMockedAll()
+
+	now := time.Unix(1549964818, 0)
+	clockMock := new(clockMocks.Clock)
+	clockMock.On("Now").Return(now).Once()
+	clockMock.On("Now").Return(now.Add(1500 * time.Millisecond)).Once()
+
+	client := db.NewClientWithInterfaces(loggerMock, clockMock, sqlx.NewDb(dbMock, "sqlmock"), time.Second)
+
+	sqlMock.ExpectExec("UPDATE Campaign").WithArgs("secret", 2).WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	_, err := client.Exec("UPDATE Campaign SET name = ? WHERE id = ? AND status = 'active'", "secret", 2)
+	assert.NoError(t, err)
+
+	loggerMock.AssertCalled(t, "WithFields", mock.MatchedBy(func(fields mon.Fields) bool {
+		return fields["db_query"] == "UPDATE Campaign SET name = ? WHERE id = ? AND status = ?" &&
+			fields["db_duration_ms"] == int64(1500) &&
+			strings.Contains(fields["db_caller"].(string), "db_test.TestSlowQuery")
+	}))
+	loggerMock.AssertCalled(t, "Warnf", "slow query took %s", time.Duration(1500*time.Millisecond))
+	clockMock.AssertExpectations(t)
+}
+
 func getMocks() (db.Client, goSqlMock.Sqlmock) {
 	dbMock, sqlMock, _ := goSqlMock.New()
 	loggerMock := monMocks.NewLoggerMockedAll()
 	sqlxDB := sqlx.NewDb(dbMock, "sqlmock")
 
-	client := db.NewClientWithInterfaces(loggerMock, sqlxDB)
+	client := db.NewClientWithInterfaces(loggerMock, clock.NewRealClock(), sqlxDB, 0)
 
 	return client, sqlMock
 }
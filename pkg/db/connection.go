@@ -25,6 +25,11 @@ type Settings struct {
 	MaxIdleConnections    int           `cfg:"max_idle_connections" default:"2"` // 0 or negative number=no idle connections, sql driver default=2
 	MaxOpenConnections    int           `cfg:"max_open_connections" default:"0"` // 0 or negative number=unlimited, sql driver default=0
 	ParseTime             bool          `cfg:"parse_time" default:"true"`
+	// sslmode of postgres connections, the driver defaults to require
+	SslMode string `cfg:"ssl_mode"`
+	// queries taking longer are logged with a warning, 0 disables the logging
+	SlowQueryThreshold time.Duration `cfg:"slow_query_threshold" default:"0"`
+	MetricsInterval    time.Duration `cfg:"metrics_interval" default:"1m"`
 
 	Uri        Uri               `cfg:"uri"`
 	Migrations MigrationSettings `cfg:"migrations"`
@@ -73,7 +78,7 @@ func NewConnectionFromSettings(logger mon.Logger, settings Settings) (*sqlx.DB,
 		return nil, fmt.Errorf("can not run migrations: %w", err)
 	}
 
-	publishConnectionMetrics(connection)
+	publishConnectionMetrics(connection, settings.MetricsInterval)
 
 	return connection, nil
 }
@@ -99,6 +104,9 @@ func NewConnectionWithInterfaces(settings Settings) (*sqlx.DB, error) {
 		return nil, fmt.Errorf("can not connect: %w", err)
 	}
 
+	// sqlx derives the placeholders of Rebind and named queries from the name of the driver
+	db = sqlx.NewDb(db.DB, settings.Driver)
+
 	db.SetConnMaxLifetime(settings.ConnectionMaxLifetime)
 	db.SetMaxIdleConns(settings.MaxIdleConnections)
 	db.SetMaxOpenConns(settings.MaxOpenConnections)
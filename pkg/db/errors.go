@@ -5,6 +5,13 @@ import (
 	"fmt"
 	"github.com/VividCortex/mysqlerr"
 	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation  = pq.ErrorCode("23505")
+	pqDeadlockDetected = pq.ErrorCode("40P01")
+	pqLockNotAvailable = pq.ErrorCode("55P03")
 )
 
 type DuplicateEntryError struct {
@@ -42,5 +49,28 @@ func IsDuplicateEntryError(err error) bool {
 		return mysqlErr.Number == mysqlerr.ER_DUP_ENTRY
 	}
 
+	pqErr := &pq.Error{}
+
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqUniqueViolation
+	}
+
 	return errors.Is(err, &DuplicateEntryError{})
 }
+
+// IsDeadlockError returns true if the statement was aborted due to a deadlock or a lock wait timeout and can be retried
+func IsDeadlockError(err error) bool {
+	mysqlErr := &mysql.MySQLError{}
+
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlerr.ER_LOCK_DEADLOCK || mysqlErr.Number == mysqlerr.ER_LOCK_WAIT_TIMEOUT
+	}
+
+	pqErr := &pq.Error{}
+
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqDeadlockDetected || pqErr.Code == pqLockNotAvailable
+	}
+
+	return false
+}
@@ -10,7 +10,10 @@ import (
 )
 
 const (
-	metricNameDbConnectionCount = "DbConnectionCount"
+	metricNameDbConnectionCount        = "DbConnectionCount"
+	metricNameDbConnectionWaitCount    = "DbConnectionWaitCount"
+	metricNameDbConnectionWaitDuration = "DbConnectionWaitDuration"
+	metricNameDbConnectionClosed       = "DbConnectionClosed"
 )
 
 type metricDriver struct {
@@ -47,44 +50,75 @@ func (m *metricDriver) Open(dsn string) (driver.Conn, error) {
 	return m.Driver.Open(dsn)
 }
 
-func publishConnectionMetrics(conn *sqlx.DB) {
+func publishConnectionMetrics(conn *sqlx.DB, interval time.Duration) {
 	output := mon.NewMetricDaemonWriter()
 
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
 	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		previous := conn.Stats()
+
 		for {
 			stats := conn.Stats()
+			output.Write(connectionMetrics(stats, previous))
+			previous = stats
 
-			output.Write(mon.MetricData{
-				&mon.MetricDatum{
-					Priority:   mon.PriorityHigh,
-					MetricName: metricNameDbConnectionCount,
-					Dimensions: map[string]string{
-						"Type": "open",
-					},
-					Unit:  mon.UnitCountAverage,
-					Value: float64(stats.OpenConnections),
-				},
-				&mon.MetricDatum{
-					Priority:   mon.PriorityHigh,
-					MetricName: metricNameDbConnectionCount,
-					Dimensions: map[string]string{
-						"Type": "inUse",
-					},
-					Unit:  mon.UnitCountAverage,
-					Value: float64(stats.InUse),
-				},
-				&mon.MetricDatum{
-					Priority:   mon.PriorityHigh,
-					MetricName: metricNameDbConnectionCount,
-					Dimensions: map[string]string{
-						"Type": "idle",
-					},
-					Unit:  mon.UnitCountAverage,
-					Value: float64(stats.Idle),
-				},
-			})
-
-			time.Sleep(time.Minute)
+			<-ticker.C
 		}
 	}()
 }
+
+// connectionMetrics converts the stats of the connection pool to metrics. The wait and close counters of sql.DBStats
+// are totals since the pool was opened, so only the difference to the previous stats is written.
+func connectionMetrics(stats sql.DBStats, previous sql.DBStats) mon.MetricData {
+	return mon.MetricData{
+		connectionCountMetric("open", float64(stats.OpenConnections)),
+		connectionCountMetric("inUse", float64(stats.InUse)),
+		connectionCountMetric("idle", float64(stats.Idle)),
+		connectionCountMetric("maxOpen", float64(stats.MaxOpenConnections)),
+		&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameDbConnectionWaitCount,
+			Unit:       mon.UnitCount,
+			Value:      float64(stats.WaitCount - previous.WaitCount),
+		},
+		&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameDbConnectionWaitDuration,
+			Unit:       mon.UnitMilliseconds,
+			Value:      float64((stats.WaitDuration - previous.WaitDuration).Milliseconds()),
+		},
+		connectionClosedMetric("maxIdle", float64(stats.MaxIdleClosed-previous.MaxIdleClosed)),
+		connectionClosedMetric("maxIdleTime", float64(stats.MaxIdleTimeClosed-previous.MaxIdleTimeClosed)),
+		connectionClosedMetric("maxLifetime", float64(stats.MaxLifetimeClosed-previous.MaxLifetimeClosed)),
+	}
+}
+
+func connectionCountMetric(typ string, value float64) *mon.MetricDatum {
+	return &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricNameDbConnectionCount,
+		Dimensions: map[string]string{
+			"Type": typ,
+		},
+		Unit:  mon.UnitCountAverage,
+		Value: value,
+	}
+}
+
+func connectionClosedMetric(reason string, value float64) *mon.MetricDatum {
+	return &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricNameDbConnectionClosed,
+		Dimensions: map[string]string{
+			"Reason": reason,
+		},
+		Unit:  mon.UnitCount,
+		Value: value,
+	}
+}
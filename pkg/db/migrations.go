@@ -9,6 +9,10 @@ import (
 	"strings"
 )
 
+// MigrationSettings configure the golang-migrate migrations applied when the connection is opened.
+//
+// Deprecated: use the migrations package, which supports locking, down migrations and migrations written in go. Both
+// record their versions in different tables, see the migrations package on how to move a schema.
 type MigrationSettings struct {
 	Application    string `cfg:"application" default:"{app_name}"`
 	Path           string `cfg:"path"`
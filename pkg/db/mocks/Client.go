@@ -12,6 +12,29 @@ type Client struct {
 	mock.Mock
 }
 
+// Begin provides a mock function with given fields:
+func (_m *Client) Begin() (*sql.Tx, error) {
+	ret := _m.Called()
+
+	var r0 *sql.Tx
+	if rf, ok := ret.Get(0).(func() *sql.Tx); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Tx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Exec provides a mock function with given fields: query, args
 func (_m *Client) Exec(query string, args ...interface{}) (sql.Result, error) {
 	var _ca []interface{}
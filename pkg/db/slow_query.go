@@ -0,0 +1,90 @@
+package db
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const maxSanitizedQueryLength = 1024
+
+var (
+	sanitizeStrings    = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'`)
+	sanitizeNumbers    = regexp.MustCompile(`(^|[^\w$.])-?\d+(?:\.\d+)?\b`)
+	sanitizeLists      = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)+\s*\)`)
+	sanitizeWhitespace = regexp.MustCompile(`\s+`)
+)
+
+// callerSkipPackages are the packages a query passes on its way to the client, the caller is the first frame outside
+var callerSkipPackages = []string{
+	"github.com/applike/gosoline/pkg/db.",
+	"github.com/applike/gosoline/pkg/db-repo.",
+	"github.com/jinzhu/gorm",
+	"github.com/jmoiron/sqlx",
+	"database/sql",
+	"runtime.",
+}
+
+// SanitizeQuery replaces the literals of a query by placeholders, so a logged query contains no values
+func SanitizeQuery(query string) string {
+	query = sanitizeStrings.ReplaceAllString(query, "?")
+	query = sanitizeNumbers.ReplaceAllString(query, "${1}?")
+	query = sanitizeLists.ReplaceAllString(query, "(...)")
+	query = sanitizeWhitespace.ReplaceAllString(query, " ")
+	query = strings.TrimSpace(query)
+
+	if len(query) > maxSanitizedQueryLength {
+		query = query[:maxSanitizedQueryLength] + "..."
+	}
+
+	return query
+}
+
+func (c *ClientSqlx) logSlowQuery(start time.Time, query string) {
+	if c.slowQueryThreshold <= 0 {
+		return
+	}
+
+	took := c.clock.Now().Sub(start)
+
+	if took < c.slowQueryThreshold {
+		return
+	}
+
+	c.logger.WithFields(mon.Fields{
+		"db_query":       SanitizeQuery(query),
+		"db_duration_ms": took.Milliseconds(),
+		"db_caller":      queryCaller(),
+	}).Warnf("slow query took %s", took)
+}
+
+func queryCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if !isSkippedCaller(frame.Function) {
+			return fmt.Sprintf("%s:%d %s", frame.File, frame.Line, frame.Function)
+		}
+
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+func isSkippedCaller(function string) bool {
+	for _, pkg := range callerSkipPackages {
+		if strings.HasPrefix(function, pkg) {
+			return true
+		}
+	}
+
+	return false
+}
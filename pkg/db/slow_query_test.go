@@ -0,0 +1,37 @@
+package db_test
+
+import (
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSanitizeQuery(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		expected string
+	}{
+		"literals": {
+			query:    "SELECT * FROM `t1` WHERE name = 'o''brien' AND x > -4.5 LIMIT 10",
+			expected: "SELECT * FROM `t1` WHERE name = ? AND x > ? LIMIT ?",
+		},
+		"lists": {
+			query:    "SELECT * FROM items WHERE id IN (1, 2,3) OR id IN (?,?)",
+			expected: "SELECT * FROM items WHERE id IN (...) OR id IN (...)",
+		},
+		"postgres placeholders": {
+			query:    "INSERT INTO \"items\" (\"a\",\"b\") VALUES ($1,$2)",
+			expected: "INSERT INTO \"items\" (\"a\",\"b\") VALUES ($1,$2)",
+		},
+		"whitespace": {
+			query:    "UPDATE items\n\tSET a = ?\n\tWHERE items.c2 = 3",
+			expected: "UPDATE items SET a = ? WHERE items.c2 = ?",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, db.SanitizeQuery(test.query))
+		})
+	}
+}
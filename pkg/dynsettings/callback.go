@@ -0,0 +1,47 @@
+package dynsettings
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+)
+
+type changeCallback struct {
+	service Service
+}
+
+// NewChangeCallback applies the changes published by other instances, add it as consumer reading from the
+// output of the configured producer:
+//
+//	kernel.Add("dynsettings", stream.NewConsumer("dynsettings", dynsettings.NewChangeCallback))
+func NewChangeCallback(_ context.Context, config cfg.Config, logger mon.Logger) (stream.ConsumerCallback, error) {
+	service, err := ProvideService(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("can not create dynamic settings service: %w", err)
+	}
+
+	return NewChangeCallbackWithInterfaces(service), nil
+}
+
+func NewChangeCallbackWithInterfaces(service Service) stream.ConsumerCallback {
+	return &changeCallback{
+		service: service,
+	}
+}
+
+func (c *changeCallback) GetModel(_ map[string]interface{}) interface{} {
+	return &Setting{}
+}
+
+func (c *changeCallback) Consume(ctx context.Context, model interface{}, _ map[string]interface{}) (bool, error) {
+	setting, ok := model.(*Setting)
+	if !ok {
+		return false, fmt.Errorf("expected model of type *Setting but got %T", model)
+	}
+
+	c.service.Apply(ctx, setting)
+
+	return true, nil
+}
@@ -0,0 +1,29 @@
+package dynsettings
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+)
+
+// ApplyConfigOverlay reads the settings of the keys from the store and merges them into the config. Later changes
+// are not written into the config, use Service.AddListener to react to them.
+func ApplyConfigOverlay(ctx context.Context, config cfg.GosoConf, service Service, keys []string) error {
+	for _, key := range keys {
+		setting, ok, err := service.Get(ctx, key)
+
+		if err != nil {
+			return fmt.Errorf("can not read setting %s for config overlay: %w", key, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := config.Option(cfg.WithConfigSetting(key, setting.Value)); err != nil {
+			return fmt.Errorf("can not merge setting %s into config: %w", key, err)
+		}
+	}
+
+	return nil
+}
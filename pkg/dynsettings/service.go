@@ -0,0 +1,212 @@
+package dynsettings
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+	"sync"
+	"time"
+)
+
+const ConfigKey = "dynsettings"
+
+type Settings struct {
+	// name of the kvstore the settings are persisted in
+	Store string `cfg:"store" default:"dynsettings" validate:"required"`
+	// name of the producer changes are published with, changes are only visible to this instance if empty
+	Producer string `cfg:"producer"`
+	// settings are cached for this duration before they are read from the store again
+	CacheTtl time.Duration `cfg:"cache_ttl" default:"1m"`
+	// keys which are read from the store at boot and merged into the config
+	Keys []string `cfg:"keys"`
+}
+
+type Setting struct {
+	Key       string      `json:"key"`
+	Value     interface{} `json:"value"`
+	Deleted   bool        `json:"deleted,omitempty"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+type Listener func(ctx context.Context, setting *Setting)
+
+//go:generate mockery -name Service
+type Service interface {
+	// AddListener registers a listener which is called with every change of the setting
+	AddListener(key string, listener Listener)
+	// Apply a change of a setting made by another instance
+	Apply(ctx context.Context, setting *Setting)
+	Delete(ctx context.Context, key string) error
+	Get(ctx context.Context, key string) (*Setting, bool, error)
+	Set(ctx context.Context, key string, value interface{}) error
+}
+
+type cachedSetting struct {
+	setting   *Setting
+	fetchedAt time.Time
+}
+
+type service struct {
+	logger   mon.Logger
+	clock    clock.Clock
+	store    kvstore.KvStore
+	producer stream.Producer
+	settings *Settings
+
+	lck       sync.Mutex
+	cache     map[string]cachedSetting
+	listeners map[string][]Listener
+}
+
+var serviceContainer = struct {
+	sync.Mutex
+	instance Service
+}{}
+
+func ProvideService(config cfg.Config, logger mon.Logger) (Service, error) {
+	serviceContainer.Lock()
+	defer serviceContainer.Unlock()
+
+	if serviceContainer.instance != nil {
+		return serviceContainer.instance, nil
+	}
+
+	instance, err := NewService(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceContainer.instance = instance
+
+	return serviceContainer.instance, nil
+}
+
+func NewService(config cfg.Config, logger mon.Logger) (Service, error) {
+	settings := &Settings{}
+	config.UnmarshalKey(ConfigKey, settings)
+
+	store, err := kvstore.NewConfigurableKvStore(config, logger, settings.Store)
+	if err != nil {
+		return nil, fmt.Errorf("can not create kvstore %s: %w", settings.Store, err)
+	}
+
+	var producer stream.Producer
+
+	if settings.Producer != "" {
+		if producer, err = stream.NewProducer(config, logger, settings.Producer); err != nil {
+			return nil, fmt.Errorf("can not create producer %s: %w", settings.Producer, err)
+		}
+	}
+
+	return NewServiceWithInterfaces(logger, clock.Provider, store, producer, settings), nil
+}
+
+func NewServiceWithInterfaces(logger mon.Logger, clock clock.Clock, store kvstore.KvStore, producer stream.Producer, settings *Settings) Service {
+	return &service{
+		logger:    logger.WithChannel("dynsettings"),
+		clock:     clock,
+		store:     store,
+		producer:  producer,
+		settings:  settings,
+		cache:     make(map[string]cachedSetting),
+		listeners: make(map[string][]Listener),
+	}
+}
+
+func (s *service) AddListener(key string, listener Listener) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	s.listeners[key] = append(s.listeners[key], listener)
+}
+
+func (s *service) Apply(ctx context.Context, setting *Setting) {
+	s.lck.Lock()
+
+	if cached, ok := s.cache[setting.Key]; ok && cached.setting != nil && cached.setting.UpdatedAt.Equal(setting.UpdatedAt) {
+		s.lck.Unlock()
+		return
+	}
+
+	s.cache[setting.Key] = cachedSetting{setting: setting, fetchedAt: s.clock.Now()}
+
+	listeners := append([]Listener{}, s.listeners[setting.Key]...)
+	s.lck.Unlock()
+
+	for _, listener := range listeners {
+		listener(ctx, setting)
+	}
+}
+
+func (s *service) Delete(ctx context.Context, key string) error {
+	if err := s.store.Delete(ctx, key); err != nil {
+		return fmt.Errorf("can not delete setting %s: %w", key, err)
+	}
+
+	return s.publish(ctx, &Setting{
+		Key:       key,
+		Deleted:   true,
+		UpdatedAt: s.clock.Now(),
+	})
+}
+
+// Get returns the setting from the local cache and reads it from the store once the cache ttl expired
+func (s *service) Get(ctx context.Context, key string) (*Setting, bool, error) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	if cached, ok := s.cache[key]; ok && s.clock.Now().Sub(cached.fetchedAt) < s.settings.CacheTtl {
+		if cached.setting == nil || cached.setting.Deleted {
+			return nil, false, nil
+		}
+
+		return cached.setting, true, nil
+	}
+
+	setting := &Setting{}
+	found, err := s.store.Get(ctx, key, setting)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("can not read setting %s: %w", key, err)
+	}
+
+	if !found {
+		setting = nil
+	}
+
+	s.cache[key] = cachedSetting{setting: setting, fetchedAt: s.clock.Now()}
+
+	return setting, found, nil
+}
+
+func (s *service) Set(ctx context.Context, key string, value interface{}) error {
+	setting := &Setting{
+		Key:       key,
+		Value:     value,
+		UpdatedAt: s.clock.Now(),
+	}
+
+	if err := s.store.Put(ctx, key, setting); err != nil {
+		return fmt.Errorf("can not write setting %s: %w", key, err)
+	}
+
+	return s.publish(ctx, setting)
+}
+
+func (s *service) publish(ctx context.Context, setting *Setting) error {
+	s.Apply(ctx, setting)
+
+	if s.producer == nil {
+		return nil
+	}
+
+	if err := s.producer.WriteOne(ctx, setting); err != nil {
+		return fmt.Errorf("can not publish change of setting %s: %w", setting.Key, err)
+	}
+
+	return nil
+}
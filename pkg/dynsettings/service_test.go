@@ -0,0 +1,131 @@
+package dynsettings_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/dynsettings"
+	"github.com/applike/gosoline/pkg/kvstore"
+	kvStoreMocks "github.com/applike/gosoline/pkg/kvstore/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	streamMocks "github.com/applike/gosoline/pkg/stream/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func buildService(store kvstore.KvStore, producer *streamMocks.Producer) (dynsettings.Service, clock.FakeClock) {
+	logger := monMocks.NewLoggerMockedAll()
+	clk := clock.NewFakeClock()
+
+	service := dynsettings.NewServiceWithInterfaces(logger, clk, store, producer, &dynsettings.Settings{
+		CacheTtl: time.Minute,
+	})
+
+	return service, clk
+}
+
+func TestService_SetAndGet(t *testing.T) {
+	ctx := context.Background()
+	store := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{
+		Ttl:       time.Hour,
+		BatchSize: 100,
+	})
+
+	producer := new(streamMocks.Producer)
+	producer.On("WriteOne", ctx, mock.AnythingOfType("*dynsettings.Setting")).Return(nil).Twice()
+
+	service, clk := buildService(store, producer)
+
+	changes := make([]*dynsettings.Setting, 0)
+	service.AddListener("limits.max_items", func(_ context.Context, setting *dynsettings.Setting) {
+		changes = append(changes, setting)
+	})
+
+	_, ok, err := service.Get(ctx, "limits.max_items")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	err = service.Set(ctx, "limits.max_items", 10)
+	assert.NoError(t, err)
+
+	setting, ok, err := service.Get(ctx, "limits.max_items")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 10, setting.Value)
+
+	clk.Advance(2 * time.Minute)
+
+	setting, ok, err = service.Get(ctx, "limits.max_items")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 10, setting.Value)
+
+	err = service.Delete(ctx, "limits.max_items")
+	assert.NoError(t, err)
+
+	_, ok, err = service.Get(ctx, "limits.max_items")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Len(t, changes, 2)
+	assert.True(t, changes[1].Deleted)
+	producer.AssertExpectations(t)
+}
+
+func TestService_Apply(t *testing.T) {
+	ctx := context.Background()
+	store := new(kvStoreMocks.KvStore)
+	service, clk := buildService(store, nil)
+
+	calls := 0
+	service.AddListener("toggle", func(_ context.Context, _ *dynsettings.Setting) {
+		calls++
+	})
+
+	setting := &dynsettings.Setting{
+		Key:       "toggle",
+		Value:     true,
+		UpdatedAt: clk.Now(),
+	}
+
+	service.Apply(ctx, setting)
+	service.Apply(ctx, setting)
+
+	actual, ok, err := service.Get(ctx, "toggle")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, setting, actual)
+	assert.Equal(t, 1, calls)
+	store.AssertExpectations(t)
+}
+
+func TestApplyConfigOverlay(t *testing.T) {
+	ctx := context.Background()
+	store := new(kvStoreMocks.KvStore)
+	store.On("Get", ctx, "limits.max_items", mock.AnythingOfType("*dynsettings.Setting")).Run(func(args mock.Arguments) {
+		setting := args.Get(2).(*dynsettings.Setting)
+		setting.Key = "limits.max_items"
+		setting.Value = 20
+	}).Return(true, nil)
+	store.On("Get", ctx, "limits.missing", mock.AnythingOfType("*dynsettings.Setting")).Return(false, nil)
+
+	service, _ := buildService(store, nil)
+
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"limits": map[string]interface{}{
+			"max_items": 5,
+			"missing":   3,
+		},
+	}))
+	assert.NoError(t, err)
+
+	err = dynsettings.ApplyConfigOverlay(ctx, config, service, []string{"limits.max_items", "limits.missing"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 20, config.GetInt("limits.max_items"))
+	assert.Equal(t, 3, config.GetInt("limits.missing"))
+	store.AssertExpectations(t)
+}
@@ -0,0 +1,210 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/spf13/cast"
+	"sync"
+	"time"
+)
+
+const ConfigKey = "featureflag"
+
+type Settings struct {
+	Provider string `cfg:"provider" default:"config" validate:"required"`
+	// flags are cached for this duration before they are requested from the provider again
+	CacheTtl time.Duration `cfg:"cache_ttl" default:"1m"`
+}
+
+//go:generate mockery -name Client
+type Client interface {
+	Bool(ctx context.Context, key string, defaultValue bool) bool
+	Float64(ctx context.Context, key string, defaultValue float64) float64
+	Int(ctx context.Context, key string, defaultValue int) int
+	String(ctx context.Context, key string, defaultValue string) string
+	// Flags returns all flags of the provider, e.g. to inspect them
+	Flags(ctx context.Context) map[string]*Flag
+}
+
+type client struct {
+	logger   mon.Logger
+	clock    clock.Clock
+	provider Provider
+	settings *Settings
+
+	lck        sync.Mutex
+	flags      map[string]*Flag
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+var clientContainer = struct {
+	sync.Mutex
+	instance Client
+}{}
+
+func ProvideClient(config cfg.Config, logger mon.Logger) (Client, error) {
+	clientContainer.Lock()
+	defer clientContainer.Unlock()
+
+	if clientContainer.instance != nil {
+		return clientContainer.instance, nil
+	}
+
+	instance, err := NewClient(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	clientContainer.instance = instance
+
+	return clientContainer.instance, nil
+}
+
+func NewClient(config cfg.Config, logger mon.Logger) (Client, error) {
+	settings := &Settings{}
+	config.UnmarshalKey(ConfigKey, settings)
+
+	factory, ok := providers[settings.Provider]
+	if !ok {
+		return nil, fmt.Errorf("no feature flag provider found for name %s", settings.Provider)
+	}
+
+	provider, err := factory(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("can not create feature flag provider %s: %w", settings.Provider, err)
+	}
+
+	return NewClientWithInterfaces(logger, clock.Provider, provider, settings), nil
+}
+
+func NewClientWithInterfaces(logger mon.Logger, clock clock.Clock, provider Provider, settings *Settings) Client {
+	return &client{
+		logger:   logger.WithChannel("featureflag"),
+		clock:    clock,
+		provider: provider,
+		settings: settings,
+	}
+}
+
+func (c *client) Bool(ctx context.Context, key string, defaultValue bool) bool {
+	value, ok := c.evaluate(ctx, key)
+	if !ok {
+		return defaultValue
+	}
+
+	result, err := cast.ToBoolE(value)
+	if err != nil {
+		c.logger.WithContext(ctx).Warnf("feature flag %s is not a bool: %s", key, err.Error())
+		return defaultValue
+	}
+
+	return result
+}
+
+func (c *client) Float64(ctx context.Context, key string, defaultValue float64) float64 {
+	value, ok := c.evaluate(ctx, key)
+	if !ok {
+		return defaultValue
+	}
+
+	result, err := cast.ToFloat64E(value)
+	if err != nil {
+		c.logger.WithContext(ctx).Warnf("feature flag %s is not a float: %s", key, err.Error())
+		return defaultValue
+	}
+
+	return result
+}
+
+func (c *client) Int(ctx context.Context, key string, defaultValue int) int {
+	value, ok := c.evaluate(ctx, key)
+	if !ok {
+		return defaultValue
+	}
+
+	result, err := cast.ToIntE(value)
+	if err != nil {
+		c.logger.WithContext(ctx).Warnf("feature flag %s is not an int: %s", key, err.Error())
+		return defaultValue
+	}
+
+	return result
+}
+
+func (c *client) String(ctx context.Context, key string, defaultValue string) string {
+	value, ok := c.evaluate(ctx, key)
+	if !ok {
+		return defaultValue
+	}
+
+	result, err := cast.ToStringE(value)
+	if err != nil {
+		c.logger.WithContext(ctx).Warnf("feature flag %s is not a string: %s", key, err.Error())
+		return defaultValue
+	}
+
+	return result
+}
+
+func (c *client) Flags(ctx context.Context) map[string]*Flag {
+	flags := c.getFlags(ctx)
+	result := make(map[string]*Flag, len(flags))
+
+	for key, flag := range flags {
+		result[key] = flag
+	}
+
+	return result
+}
+
+func (c *client) evaluate(ctx context.Context, key string) (interface{}, bool) {
+	flags := c.getFlags(ctx)
+	flag, ok := flags[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	tenant, _ := reqctx.TenantFromContext(ctx)
+
+	return flag.evaluate(tenant)
+}
+
+// getFlags returns the cached flags and refreshes them once the cache ttl expired. The lock is not held while
+// calling the provider, so a slow provider doesn't block the evaluation of the other callers. They keep using the
+// stale flags until the refresh is done. If the provider fails, the stale flags are used until the next refresh.
+func (c *client) getFlags(ctx context.Context) map[string]*Flag {
+	c.lck.Lock()
+	flags := c.flags
+	expired := flags == nil || c.clock.Now().Sub(c.fetchedAt) >= c.settings.CacheTtl
+
+	if !expired || (c.refreshing && flags != nil) {
+		c.lck.Unlock()
+		return flags
+	}
+
+	c.refreshing = true
+	c.lck.Unlock()
+
+	fetched, err := c.provider.GetFlags(ctx)
+
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	c.refreshing = false
+	c.fetchedAt = c.clock.Now()
+
+	if err != nil {
+		c.logger.WithContext(ctx).Error(err, "can not refresh feature flags")
+		return c.flags
+	}
+
+	c.flags = fetched
+
+	return c.flags
+}
@@ -0,0 +1,150 @@
+package featureflag_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/featureflag"
+	"github.com/applike/gosoline/pkg/featureflag/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func buildClient(provider featureflag.Provider, clk clock.Clock) featureflag.Client {
+	logger := monMocks.NewLoggerMockedAll()
+	settings := &featureflag.Settings{
+		CacheTtl: time.Minute,
+	}
+
+	return featureflag.NewClientWithInterfaces(logger, clk, provider, settings)
+}
+
+func TestClient_Evaluate(t *testing.T) {
+	provider := featureflag.NewConfigProviderWithInterfaces(map[string]*featureflag.Flag{
+		"checkout": {
+			Key:     "checkout",
+			Enabled: true,
+			Tenants: map[string]interface{}{
+				"tenant-a": false,
+			},
+		},
+		"disabled": {
+			Key:     "disabled",
+			Enabled: false,
+			Value:   true,
+		},
+		"page_size": {
+			Key:     "page_size",
+			Enabled: true,
+			Value:   "50",
+		},
+		"ratio": {
+			Key:     "ratio",
+			Enabled: true,
+			Value:   0.25,
+		},
+		"color": {
+			Key:     "color",
+			Enabled: true,
+			Value:   "blue",
+		},
+	})
+
+	client := buildClient(provider, clock.NewFakeClock())
+	ctx := context.Background()
+	tenantCtx := reqctx.WithTenant(ctx, "tenant-a")
+
+	assert.True(t, client.Bool(ctx, "checkout", false))
+	assert.False(t, client.Bool(tenantCtx, "checkout", true))
+	assert.True(t, client.Bool(reqctx.WithTenant(ctx, "tenant-b"), "checkout", false))
+	assert.False(t, client.Bool(ctx, "disabled", false))
+	assert.True(t, client.Bool(ctx, "missing", true))
+	assert.Equal(t, 50, client.Int(ctx, "page_size", 10))
+	assert.Equal(t, 0.25, client.Float64(ctx, "ratio", 1))
+	assert.Equal(t, "blue", client.String(ctx, "color", "red"))
+	assert.Equal(t, 10, client.Int(ctx, "color", 10))
+}
+
+func TestClient_Caching(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFakeClock()
+
+	provider := new(mocks.Provider)
+	provider.On("GetFlags", mock.Anything).Return(map[string]*featureflag.Flag{
+		"checkout": {Key: "checkout", Enabled: true},
+	}, nil).Once()
+
+	client := buildClient(provider, clk)
+
+	assert.True(t, client.Bool(ctx, "checkout", false))
+	clk.Advance(30 * time.Second)
+	assert.True(t, client.Bool(ctx, "checkout", false))
+	provider.AssertNumberOfCalls(t, "GetFlags", 1)
+
+	provider.On("GetFlags", mock.Anything).Return(map[string]*featureflag.Flag{
+		"checkout": {Key: "checkout", Enabled: false},
+	}, nil).Once()
+
+	clk.Advance(time.Minute)
+	assert.False(t, client.Bool(ctx, "checkout", false))
+	provider.AssertExpectations(t)
+}
+
+func TestClient_StaleFlagsOnError(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFakeClock()
+
+	provider := new(mocks.Provider)
+	provider.On("GetFlags", mock.Anything).Return(map[string]*featureflag.Flag{
+		"checkout": {Key: "checkout", Enabled: true},
+	}, nil).Once()
+	provider.On("GetFlags", mock.Anything).Return(nil, fmt.Errorf("provider down")).Once()
+
+	client := buildClient(provider, clk)
+
+	assert.True(t, client.Bool(ctx, "checkout", false))
+	clk.Advance(2 * time.Minute)
+	assert.True(t, client.Bool(ctx, "checkout", false))
+	provider.AssertExpectations(t)
+}
+
+func TestClient_StaleFlagsWhileRefreshing(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFakeClock()
+	release := make(chan struct{})
+	refreshing := make(chan struct{})
+
+	provider := new(mocks.Provider)
+	provider.On("GetFlags", mock.Anything).Return(map[string]*featureflag.Flag{
+		"checkout": {Key: "checkout", Enabled: true},
+	}, nil).Once()
+	provider.On("GetFlags", mock.Anything).Run(func(args mock.Arguments) {
+		close(refreshing)
+		<-release
+	}).Return(map[string]*featureflag.Flag{
+		"checkout": {Key: "checkout", Enabled: false},
+	}, nil).Once()
+
+	client := buildClient(provider, clk)
+
+	assert.True(t, client.Bool(ctx, "checkout", false))
+	clk.Advance(2 * time.Minute)
+
+	done := make(chan bool)
+	go func() {
+		done <- client.Bool(ctx, "checkout", false)
+	}()
+
+	<-refreshing
+	assert.True(t, client.Bool(ctx, "checkout", false), "the stale flags should be used while refreshing")
+
+	close(release)
+	assert.False(t, <-done)
+	assert.False(t, client.Bool(ctx, "checkout", false))
+	assert.Len(t, client.Flags(ctx), 1)
+	provider.AssertExpectations(t)
+}
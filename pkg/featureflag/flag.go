@@ -0,0 +1,27 @@
+package featureflag
+
+// Flag is the provider independent representation of a feature flag. A disabled flag evaluates to the default value
+// given by the caller, an enabled flag to the value of the tenant or its value. Enabled flags without a value
+// evaluate to true.
+type Flag struct {
+	Key     string                 `json:"key"`
+	Enabled bool                   `json:"enabled"`
+	Value   interface{}            `json:"value"`
+	Tenants map[string]interface{} `json:"tenants"`
+}
+
+func (f *Flag) evaluate(tenant string) (interface{}, bool) {
+	if !f.Enabled {
+		return nil, false
+	}
+
+	if value, ok := f.Tenants[tenant]; ok && tenant != "" {
+		return value, true
+	}
+
+	if f.Value == nil {
+		return true, true
+	}
+
+	return f.Value, true
+}
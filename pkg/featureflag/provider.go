@@ -0,0 +1,24 @@
+package featureflag
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+//go:generate mockery -name Provider
+type Provider interface {
+	GetFlags(ctx context.Context) (map[string]*Flag, error)
+}
+
+type ProviderFactory func(config cfg.Config, logger mon.Logger) (Provider, error)
+
+func AddProvider(name string, factory ProviderFactory) {
+	providers[name] = factory
+}
+
+var providers = map[string]ProviderFactory{
+	"appconfig":    NewAppConfigProvider,
+	"config":       NewConfigProvider,
+	"launchdarkly": NewLaunchDarklyProvider,
+}
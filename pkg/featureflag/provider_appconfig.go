@@ -0,0 +1,95 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/cloud"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/appconfig"
+	"github.com/aws/aws-sdk-go/service/appconfig/appconfigiface"
+	"sync"
+)
+
+type AppConfigSettings struct {
+	Application   string `cfg:"application" default:"{app_name}"`
+	Environment   string `cfg:"environment" default:"{env}"`
+	Configuration string `cfg:"configuration" default:"featureflags"`
+	ClientId      string `cfg:"client_id" default:"{app_name}"`
+}
+
+type appConfigProvider struct {
+	client   appconfigiface.AppConfigAPI
+	settings *AppConfigSettings
+
+	lck     sync.Mutex
+	version string
+	flags   map[string]*Flag
+}
+
+// NewAppConfigProvider reads the flags from an AWS AppConfig configuration profile. The content has to be a JSON
+// object of flags by their key, like {"new_checkout": {"enabled": true, "tenants": {"tenant-a": false}}}.
+func NewAppConfigProvider(config cfg.Config, _ mon.Logger) (Provider, error) {
+	settings := &AppConfigSettings{}
+	config.UnmarshalKey(ConfigKey+".appconfig", settings)
+
+	awsConfig := cloud.ConfigTemplate
+	awsConfig.WithEndpoint(config.GetString("aws_appconfig_endpoint", ""))
+	awsConfig.WithMaxRetries(config.GetInt("aws_sdk_retries", 10))
+
+	client := appconfig.New(session.Must(session.NewSession(&awsConfig)))
+
+	return NewAppConfigProviderWithInterfaces(client, settings), nil
+}
+
+func NewAppConfigProviderWithInterfaces(client appconfigiface.AppConfigAPI, settings *AppConfigSettings) Provider {
+	return &appConfigProvider{
+		client:   client,
+		settings: settings,
+		flags:    make(map[string]*Flag),
+	}
+}
+
+func (p *appConfigProvider) GetFlags(ctx context.Context) (map[string]*Flag, error) {
+	p.lck.Lock()
+	defer p.lck.Unlock()
+
+	input := &appconfig.GetConfigurationInput{
+		Application:   aws.String(p.settings.Application),
+		Environment:   aws.String(p.settings.Environment),
+		Configuration: aws.String(p.settings.Configuration),
+		ClientId:      aws.String(p.settings.ClientId),
+	}
+
+	if p.version != "" {
+		input.ClientConfigurationVersion = aws.String(p.version)
+	}
+
+	out, err := p.client.GetConfigurationWithContext(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("can not get appconfig configuration %s: %w", p.settings.Configuration, err)
+	}
+
+	// appconfig only returns content if the configuration changed since the version of the client
+	if len(out.Content) == 0 {
+		return p.flags, nil
+	}
+
+	flags := make(map[string]*Flag)
+
+	if err := json.Unmarshal(out.Content, &flags); err != nil {
+		return nil, fmt.Errorf("can not decode appconfig configuration %s: %w", p.settings.Configuration, err)
+	}
+
+	for key, flag := range flags {
+		flag.Key = key
+	}
+
+	p.version = aws.StringValue(out.ConfigurationVersion)
+	p.flags = flags
+
+	return p.flags, nil
+}
@@ -0,0 +1,50 @@
+package featureflag
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/spf13/cast"
+)
+
+type configProvider struct {
+	flags map[string]*Flag
+}
+
+// NewConfigProvider reads the flags from the featureflag.flags config map, like
+//
+//	featureflag:
+//	  flags:
+//	    new_checkout: { enabled: true, tenants: { tenant-a: false } }
+//	    page_size: { enabled: true, value: 50 }
+func NewConfigProvider(config cfg.Config, _ mon.Logger) (Provider, error) {
+	flags := make(map[string]*Flag)
+	key := ConfigKey + ".flags"
+
+	if !config.IsSet(key) {
+		return NewConfigProviderWithInterfaces(flags), nil
+	}
+
+	for name, value := range config.GetStringMap(key) {
+		settings := cast.ToStringMap(value)
+
+		flags[name] = &Flag{
+			Key:     name,
+			Enabled: cast.ToBool(settings["enabled"]),
+			Value:   settings["value"],
+			Tenants: cast.ToStringMap(settings["tenants"]),
+		}
+	}
+
+	return NewConfigProviderWithInterfaces(flags), nil
+}
+
+func NewConfigProviderWithInterfaces(flags map[string]*Flag) Provider {
+	return &configProvider{
+		flags: flags,
+	}
+}
+
+func (p *configProvider) GetFlags(_ context.Context) (map[string]*Flag, error) {
+	return p.flags, nil
+}
@@ -0,0 +1,128 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/mon"
+	"strings"
+)
+
+type LaunchDarklySettings struct {
+	// url of the LaunchDarkly api or a relay proxy
+	BaseUrl string `cfg:"base_url" default:"https://sdk.launchdarkly.com"`
+	SdkKey  string `cfg:"sdk_key"`
+}
+
+type launchDarklyTarget struct {
+	Values    []string `json:"values"`
+	Variation int      `json:"variation"`
+}
+
+type launchDarklyFallthrough struct {
+	Variation *int `json:"variation"`
+}
+
+type launchDarklyFlag struct {
+	Key          string                  `json:"key"`
+	On           bool                    `json:"on"`
+	Variations   []interface{}           `json:"variations"`
+	OffVariation *int                    `json:"offVariation"`
+	Fallthrough  launchDarklyFallthrough `json:"fallthrough"`
+	Targets      []launchDarklyTarget    `json:"targets"`
+}
+
+type launchDarklyProvider struct {
+	client   http.Client
+	settings *LaunchDarklySettings
+}
+
+// NewLaunchDarklyProvider polls the flags from the LaunchDarkly server side sdk endpoint. Individual user targets are
+// used as tenant targeting, rules and rollouts are not evaluated and fall back to the fallthrough variation.
+func NewLaunchDarklyProvider(config cfg.Config, logger mon.Logger) (Provider, error) {
+	settings := &LaunchDarklySettings{}
+	config.UnmarshalKey(ConfigKey+".launchdarkly", settings)
+
+	client, err := http.ProvideHttpClient(config, logger, "featureflag")
+	if err != nil {
+		return nil, fmt.Errorf("can not create http client: %w", err)
+	}
+
+	return NewLaunchDarklyProviderWithInterfaces(client, settings), nil
+}
+
+func NewLaunchDarklyProviderWithInterfaces(client http.Client, settings *LaunchDarklySettings) Provider {
+	return &launchDarklyProvider{
+		client:   client,
+		settings: settings,
+	}
+}
+
+func (p *launchDarklyProvider) GetFlags(ctx context.Context) (map[string]*Flag, error) {
+	url := fmt.Sprintf("%s/sdk/latest-flags", strings.TrimSuffix(p.settings.BaseUrl, "/"))
+	request := p.client.NewRequest().
+		WithUrl(url).
+		WithHeader("Authorization", p.settings.SdkKey)
+
+	response, err := p.client.Get(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("can not get launchdarkly flags: %w", err)
+	}
+
+	if response.StatusCode != 200 {
+		return nil, fmt.Errorf("can not get launchdarkly flags: unexpected status code %d", response.StatusCode)
+	}
+
+	ldFlags := make(map[string]*launchDarklyFlag)
+
+	if err := json.Unmarshal(response.Body, &ldFlags); err != nil {
+		return nil, fmt.Errorf("can not decode launchdarkly flags: %w", err)
+	}
+
+	flags := make(map[string]*Flag, len(ldFlags))
+
+	for key, ldFlag := range ldFlags {
+		flags[key] = convertLaunchDarklyFlag(key, ldFlag)
+	}
+
+	return flags, nil
+}
+
+func convertLaunchDarklyFlag(key string, ldFlag *launchDarklyFlag) *Flag {
+	flag := &Flag{
+		Key:     key,
+		Tenants: make(map[string]interface{}),
+	}
+
+	variation := func(index *int) (interface{}, bool) {
+		if index == nil || *index < 0 || *index >= len(ldFlag.Variations) {
+			return nil, false
+		}
+
+		return ldFlag.Variations[*index], true
+	}
+
+	if !ldFlag.On {
+		flag.Value, flag.Enabled = variation(ldFlag.OffVariation)
+		return flag
+	}
+
+	flag.Value, flag.Enabled = variation(ldFlag.Fallthrough.Variation)
+
+	for _, target := range ldFlag.Targets {
+		index := target.Variation
+		value, ok := variation(&index)
+
+		if !ok {
+			continue
+		}
+
+		for _, tenant := range target.Values {
+			flag.Tenants[tenant] = value
+		}
+	}
+
+	return flag
+}
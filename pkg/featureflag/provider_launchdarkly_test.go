@@ -0,0 +1,58 @@
+package featureflag_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/featureflag"
+	"github.com/applike/gosoline/pkg/http"
+	httpMocks "github.com/applike/gosoline/pkg/http/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestLaunchDarklyProvider_GetFlags(t *testing.T) {
+	ctx := context.Background()
+	body := `{
+		"checkout": {"key": "checkout", "on": true, "variations": [true, false], "offVariation": 1, "fallthrough": {"variation": 0}, "targets": [{"values": ["tenant-a"], "variation": 1}]},
+		"color": {"key": "color", "on": false, "variations": ["blue", "red"], "offVariation": 1, "fallthrough": {"variation": 0}},
+		"rollout": {"key": "rollout", "on": true, "variations": [true, false], "fallthrough": {}}
+	}`
+
+	client := new(httpMocks.Client)
+	client.On("NewRequest").Return(http.NewRequest(nil))
+	client.On("Get", ctx, mock.MatchedBy(func(request *http.Request) bool {
+		header := request.GetHeader()
+
+		return request.GetUrl() == "https://relay.example.com/sdk/latest-flags" && len(header["Authorization"]) == 1 && header["Authorization"][0] == "sdk-key"
+	})).Return(&http.Response{
+		Body:       []byte(body),
+		StatusCode: 200,
+	}, nil)
+
+	provider := featureflag.NewLaunchDarklyProviderWithInterfaces(client, &featureflag.LaunchDarklySettings{
+		BaseUrl: "https://relay.example.com/",
+		SdkKey:  "sdk-key",
+	})
+
+	flags, err := provider.GetFlags(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &featureflag.Flag{
+		Key:     "checkout",
+		Enabled: true,
+		Value:   true,
+		Tenants: map[string]interface{}{
+			"tenant-a": false,
+		},
+	}, flags["checkout"])
+
+	assert.Equal(t, &featureflag.Flag{
+		Key:     "color",
+		Enabled: true,
+		Value:   "red",
+		Tenants: map[string]interface{}{},
+	}, flags["color"])
+
+	assert.False(t, flags["rollout"].Enabled)
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,206 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/encoding/yaml"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const (
+	FileFormatCsv  = "csv"
+	FileFormatJson = "json"
+	FileFormatYaml = "yaml"
+)
+
+// A FixtureFile contains a list of fixtures which are unmarshalled into the type of the Model, e.g. &Item{} for a
+// list of *Item. The format is detected by the extension of the path (.csv, .json, .yml or .yaml) if it is not
+// set. If Data is set, it is used instead of reading the path, e.g. for files embedded into the binary.
+//
+// The keys of all formats are the json names of the fields. The first row of a csv file contains the keys, empty
+// cells are skipped and the other cells are decoded as json values if the field isn't a string.
+type FixtureFile struct {
+	Path   string
+	Format string
+	Data   []byte
+	Model  interface{}
+}
+
+func readFixtureFile(file *FixtureFile) ([]interface{}, error) {
+	if file.Model == nil {
+		return nil, fmt.Errorf("the fixture file %s is missing a model", file.Path)
+	}
+
+	data := file.Data
+
+	if data == nil {
+		var err error
+
+		if data, err = ioutil.ReadFile(file.Path); err != nil {
+			return nil, fmt.Errorf("can not read fixture file: %w", err)
+		}
+	}
+
+	format := file.Format
+
+	if format == "" {
+		format = fixtureFileFormat(file.Path)
+	}
+
+	modelType := reflect.TypeOf(file.Model)
+	results := reflect.New(reflect.SliceOf(modelType))
+
+	var err error
+
+	switch format {
+	case FileFormatCsv:
+		err = unmarshalCsvFixtures(data, modelType, results.Interface())
+	case FileFormatJson:
+		err = json.Unmarshal(data, results.Interface())
+	case FileFormatYaml:
+		err = unmarshalYamlFixtures(data, results.Interface())
+	default:
+		return nil, fmt.Errorf("the fixture file %s has the unknown format %q", file.Path, format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not unmarshal %s fixture file %s: %w", format, file.Path, err)
+	}
+
+	fixtures := make([]interface{}, results.Elem().Len())
+
+	for i := range fixtures {
+		fixtures[i] = results.Elem().Index(i).Interface()
+	}
+
+	return fixtures, nil
+}
+
+func fixtureFileFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FileFormatCsv
+	case ".json":
+		return FileFormatJson
+	case ".yml", ".yaml":
+		return FileFormatYaml
+	default:
+		return ""
+	}
+}
+
+// unmarshalCsvFixtures converts the rows to json objects and unmarshals them as a json list into the results
+func unmarshalCsvFixtures(data []byte, modelType reflect.Type, results interface{}) error {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	header := rows[0]
+	fields := jsonFieldTypes(modelType)
+	objects := make([]map[string]interface{}, 0, len(rows)-1)
+
+	for _, row := range rows[1:] {
+		object := make(map[string]interface{}, len(header))
+
+		for i, cell := range row {
+			if cell == "" {
+				continue
+			}
+
+			object[header[i]] = csvCellValue(cell, fields[strings.ToLower(header[i])])
+		}
+
+		objects = append(objects, object)
+	}
+
+	body, err := json.Marshal(objects)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, results)
+}
+
+// unmarshalYamlFixtures unmarshals the yaml list as json, so the fields of embedded structs and the json names can be
+// used like in the other formats
+func unmarshalYamlFixtures(data []byte, results interface{}) error {
+	objects := make([]map[string]interface{}, 0)
+
+	if err := yaml.Unmarshal(data, &objects); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(objects)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, results)
+}
+
+func csvCellValue(cell string, fieldType reflect.Type) interface{} {
+	for fieldType != nil && fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType == nil || fieldType.Kind() == reflect.String || !json.Valid([]byte(cell)) {
+		return cell
+	}
+
+	return json.RawMessage(cell)
+}
+
+// jsonFieldTypes returns the types of the fields of a struct by their lower case json names, the fields of embedded
+// structs are included like json does
+func jsonFieldTypes(typ reflect.Type) map[string]reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	fields := make(map[string]reflect.Type)
+
+	if typ.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			for embeddedName, embeddedType := range jsonFieldTypes(field.Type) {
+				if _, ok := fields[embeddedName]; !ok {
+					fields[embeddedName] = embeddedType
+				}
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[strings.ToLower(name)] = field.Type
+	}
+
+	return fields
+}
@@ -0,0 +1,115 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fileTestBase struct {
+	Id        uint       `json:"id"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+type fileTestModel struct {
+	fileTestBase
+	Name    string            `json:"name"`
+	Amount  float64           `json:"amount"`
+	Active  *bool             `json:"active"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Ignored string            `json:"-"`
+	Plain   int
+	private int
+}
+
+func TestJsonFieldTypes(t *testing.T) {
+	tests := map[string]struct {
+		typ      reflect.Type
+		expected map[string]reflect.Type
+	}{
+		"struct with embedded struct": {
+			typ: reflect.TypeOf(&fileTestModel{}),
+			expected: map[string]reflect.Type{
+				"id":        reflect.TypeOf(uint(0)),
+				"createdat": reflect.TypeOf(&time.Time{}),
+				"name":      reflect.TypeOf(""),
+				"amount":    reflect.TypeOf(float64(0)),
+				"active":    reflect.TypeOf(new(bool)),
+				"tags":      reflect.TypeOf([]string{}),
+				"labels":    reflect.TypeOf(map[string]string{}),
+				"plain":     reflect.TypeOf(0),
+			},
+		},
+		"no struct": {
+			typ:      reflect.TypeOf(""),
+			expected: map[string]reflect.Type{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, jsonFieldTypes(test.typ))
+		})
+	}
+}
+
+func TestUnmarshalCsvFixtures(t *testing.T) {
+	active := true
+
+	tests := map[string]struct {
+		data     string
+		expected []*fileTestModel
+		err      bool
+	}{
+		"values are decoded by the field types": {
+			data: "id,name,amount,active,tags\n1,123,1.5,true,\"[\"\"a\"\",\"\"b\"\"]\"\n",
+			expected: []*fileTestModel{
+				{
+					fileTestBase: fileTestBase{Id: 1},
+					Name:         "123",
+					Amount:       1.5,
+					Active:       &active,
+					Tags:         []string{"a", "b"},
+				},
+			},
+		},
+		"empty cells are skipped": {
+			data: "id,name,amount\n1,,\n2,foo,\n",
+			expected: []*fileTestModel{
+				{fileTestBase: fileTestBase{Id: 1}},
+				{fileTestBase: fileTestBase{Id: 2}, Name: "foo"},
+			},
+		},
+		"header is case insensitive": {
+			data: "ID,Name\n3,bar\n",
+			expected: []*fileTestModel{
+				{fileTestBase: fileTestBase{Id: 3}, Name: "bar"},
+			},
+		},
+		"empty file": {
+			data:     "",
+			expected: []*fileTestModel{},
+		},
+		"invalid value": {
+			data: "id\nfoo\n",
+			err:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			results := make([]*fileTestModel, 0)
+			err := unmarshalCsvFixtures([]byte(test.data), reflect.TypeOf(&fileTestModel{}), &results)
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package fixtures
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"math/rand"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const generatorLetters = "abcdefghijklmnopqrstuvwxyz"
+
+var (
+	generatorFirstNames = []string{"Alex", "Charlie", "Dana", "Elia", "Finn", "Jamie", "Kim", "Lou", "Mika", "Noa", "Robin", "Sam"}
+	generatorLastNames  = []string{"Becker", "Fischer", "Hoffmann", "Klein", "Meyer", "Richter", "Schmidt", "Schulz", "Wagner", "Weber"}
+)
+
+// FixtureGenerator creates Count fixtures when the set is loaded, they are written after the static fixtures of the
+// set. The same seed creates the same fixtures.
+type FixtureGenerator struct {
+	Count    int
+	Seed     int64
+	Generate GenerateFunc
+}
+
+// GenerateFunc creates the fixture for the current index of the generator
+type GenerateFunc func(gen *Generator) (interface{}, error)
+
+// A Generator provides deterministic random values, id sequences and template variables from the config to a
+// GenerateFunc.
+type Generator struct {
+	config    cfg.Config
+	rand      *rand.Rand
+	index     int
+	sequences map[string]uint
+}
+
+func NewGenerator(config cfg.Config, seed int64) *Generator {
+	return &Generator{
+		config:    config,
+		rand:      rand.New(rand.NewSource(seed)),
+		sequences: make(map[string]uint),
+	}
+}
+
+// Index returns the index of the fixture which is generated, starting at 0
+func (g *Generator) Index() int {
+	return g.index
+}
+
+// Sequence returns the next value of the sequence with the name, starting at 1
+func (g *Generator) Sequence(name string) uint {
+	g.sequences[name]++
+
+	return g.sequences[name]
+}
+
+// Int returns a number between min and max, both included
+func (g *Generator) Int(min int, max int) int {
+	return min + g.rand.Intn(max-min+1)
+}
+
+// Float returns a number between min and max
+func (g *Generator) Float(min float64, max float64) float64 {
+	return min + g.rand.Float64()*(max-min)
+}
+
+func (g *Generator) Bool() bool {
+	return g.rand.Intn(2) == 1
+}
+
+// Time returns a time between from and to, truncated to seconds
+func (g *Generator) Time(from time.Time, to time.Time) time.Time {
+	seconds := int64(to.Sub(from) / time.Second)
+
+	return from.Add(time.Duration(g.rand.Int63n(seconds+1)) * time.Second)
+}
+
+// Pick returns one of the values
+func (g *Generator) Pick(values ...interface{}) interface{} {
+	return values[g.rand.Intn(len(values))]
+}
+
+// PickString returns one of the values
+func (g *Generator) PickString(values ...string) string {
+	return values[g.rand.Intn(len(values))]
+}
+
+// String returns lower case letters of the given length
+func (g *Generator) String(length int) string {
+	letters := make([]byte, length)
+
+	for i := range letters {
+		letters[i] = generatorLetters[g.rand.Intn(len(generatorLetters))]
+	}
+
+	return string(letters)
+}
+
+// Name returns a first and a last name
+func (g *Generator) Name() string {
+	return fmt.Sprintf("%s %s", g.PickString(generatorFirstNames...), g.PickString(generatorLastNames...))
+}
+
+// Email returns an address of the example.com domain which is unique for every index
+func (g *Generator) Email() string {
+	name := strings.ToLower(strings.Replace(g.Name(), " ", ".", -1))
+
+	return fmt.Sprintf("%s.%d@example.com", name, g.index)
+}
+
+// Template executes the text template with the index as {{ .Index }}. The function config returns the value of a
+// config key, e.g. {{ config "app_project" }}, and sequence returns the next value of a sequence.
+func (g *Generator) Template(text string) (string, error) {
+	tmpl, err := template.New("fixture").Funcs(template.FuncMap{
+		"config":   g.config.GetString,
+		"sequence": g.Sequence,
+	}).Parse(text)
+
+	if err != nil {
+		return "", fmt.Errorf("can not parse fixture template %s: %w", text, err)
+	}
+
+	buf := &bytes.Buffer{}
+	data := map[string]interface{}{
+		"Index": g.index,
+	}
+
+	if err = tmpl.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("can not execute fixture template %s: %w", text, err)
+	}
+
+	return buf.String(), nil
+}
+
+func generateFixtures(config cfg.Config, generator *FixtureGenerator) ([]interface{}, error) {
+	if generator.Generate == nil {
+		return nil, fmt.Errorf("the fixture generator is missing a generate func")
+	}
+
+	gen := NewGenerator(config, generator.Seed)
+	fixtures := make([]interface{}, generator.Count)
+
+	for i := 0; i < generator.Count; i++ {
+		gen.index = i
+		fixture, err := generator.Generate(gen)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not generate fixture %d: %w", i, err)
+		}
+
+		fixtures[i] = fixture
+	}
+
+	return fixtures, nil
+}
@@ -0,0 +1,109 @@
+package fixtures
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type generatorTestModel struct {
+	Id    uint
+	Name  string
+	Email string
+}
+
+func TestGenerateFixtures(t *testing.T) {
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"app_project": "gosoline",
+	}))
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		generator *FixtureGenerator
+		expected  []interface{}
+		err       string
+	}{
+		"templates and sequences": {
+			generator: &FixtureGenerator{
+				Count: 2,
+				Generate: func(gen *Generator) (interface{}, error) {
+					name, err := gen.Template(`{{ config "app_project" }}-{{ .Index }}`)
+
+					return &generatorTestModel{
+						Id:   gen.Sequence("users"),
+						Name: name,
+					}, err
+				},
+			},
+			expected: []interface{}{
+				&generatorTestModel{Id: 1, Name: "gosoline-0"},
+				&generatorTestModel{Id: 2, Name: "gosoline-1"},
+			},
+		},
+		"no fixtures": {
+			generator: &FixtureGenerator{
+				Generate: func(gen *Generator) (interface{}, error) {
+					return nil, fmt.Errorf("should not be called")
+				},
+			},
+			expected: []interface{}{},
+		},
+		"missing generate func": {
+			generator: &FixtureGenerator{
+				Count: 1,
+			},
+			err: "the fixture generator is missing a generate func",
+		},
+		"failing generate func": {
+			generator: &FixtureGenerator{
+				Count: 2,
+				Generate: func(gen *Generator) (interface{}, error) {
+					if gen.Index() == 1 {
+						return nil, fmt.Errorf("boom")
+					}
+
+					return &generatorTestModel{}, nil
+				},
+			},
+			err: "can not generate fixture 1: boom",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fixtures, err := generateFixtures(config, test.generator)
+
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, fixtures)
+		})
+	}
+}
+
+func TestGenerateFixtures_Deterministic(t *testing.T) {
+	generator := &FixtureGenerator{
+		Count: 5,
+		Seed:  42,
+		Generate: func(gen *Generator) (interface{}, error) {
+			return &generatorTestModel{
+				Id:    uint(gen.Int(1, 1000)),
+				Name:  gen.Name(),
+				Email: gen.Email(),
+			}, nil
+		},
+	}
+
+	first, err := generateFixtures(cfg.New(), generator)
+	assert.NoError(t, err)
+
+	second, err := generateFixtures(cfg.New(), generator)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+}
@@ -6,13 +6,20 @@ import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/thoas/go-funk"
 )
 
 type fixtureLoaderSettings struct {
-	Enabled bool `cfg:"enabled" default:"false"`
+	Enabled bool   `cfg:"enabled" default:"false"`
+	Mode    string `cfg:"mode" default:"purge" validate:"oneof=purge incremental"`
+	// only the sets of these profiles are loaded, all sets if empty
+	Profiles []string `cfg:"profiles"`
+	// check if all fixtures of a set were written before its after load hooks are called
+	Verify bool `cfg:"verify" default:"false"`
 }
 
 type fixtureLoader struct {
+	config        cfg.Config
 	logger        mon.Logger
 	settings      *fixtureLoaderSettings
 	writerFactory func(factory FixtureWriterFactory) (FixtureWriter, error)
@@ -25,6 +32,7 @@ func NewFixtureLoader(config cfg.Config, logger mon.Logger) FixtureLoader {
 	config.UnmarshalKey("fixtures", settings)
 
 	return &fixtureLoader{
+		config:   config,
 		logger:   logger,
 		settings: settings,
 		writerFactory: func(factory FixtureWriterFactory) (FixtureWriter, error) {
@@ -39,33 +47,141 @@ func (f *fixtureLoader) Load(fixtureSets []*FixtureSet) error {
 		return nil
 	}
 
-	for _, fs := range fixtureSets {
+	sorted, err := sortFixtureSets(fixtureSets)
+	if err != nil {
+		return fmt.Errorf("can not order fixture sets: %w", err)
+	}
+
+	enabled := make([]*FixtureSet, 0, len(sorted))
+	writers := make([]FixtureWriter, 0, len(sorted))
+
+	for _, fs := range sorted {
 		if !fs.Enabled {
 			f.logger.Info("skipping disabled fixture set")
 			continue
 		}
 
+		if !f.isInProfiles(fs) {
+			f.logger.Infof("skipping fixture set %s which is not part of the profiles %v", fs.Name, f.settings.Profiles)
+			continue
+		}
+
 		if fs.Writer == nil {
 			return fmt.Errorf("fixture set is missing a writer")
 		}
 
+		if len(fs.Files) > 0 || fs.Generator != nil {
+			expanded, err := f.expand(fs)
+			if err != nil {
+				return fmt.Errorf("can not create fixtures of set %s: %w", fs.Name, err)
+			}
+
+			fs = expanded
+		}
+
 		writer, err := f.writerFactory(fs.Writer)
 		if err != nil {
 			return fmt.Errorf("can not create writer: %w", err)
 		}
 
-		if fs.Purge {
-			err := writer.Purge()
+		enabled = append(enabled, fs)
+		writers = append(writers, writer)
+	}
 
-			if err != nil {
-				return fmt.Errorf("error during purging of fixture set: %w", err)
-			}
+	// the dependent sets are purged first, e.g. the child rows of foreign keys
+	for i := len(enabled) - 1; i >= 0; i-- {
+		if !enabled[i].Purge {
+			continue
 		}
 
-		if err = writer.Write(fs); err != nil {
+		if f.settings.Mode == FixtureModeIncremental {
+			f.logger.Infof("skipping purge of fixture set %s in incremental mode", enabled[i].Name)
+			continue
+		}
+
+		if err = writers[i].Purge(); err != nil {
+			return fmt.Errorf("error during purging of fixture set: %w", err)
+		}
+	}
+
+	for i, fs := range enabled {
+		if err = writers[i].Write(fs); err != nil {
 			return fmt.Errorf("error during loading of fixture set: %w", err)
 		}
+
+		if f.settings.Verify {
+			if err = f.verify(writers[i], fs); err != nil {
+				return fmt.Errorf("error during verification of fixture set %s: %w", fs.Name, err)
+			}
+		}
+
+		for _, hook := range fs.AfterLoad {
+			if err = hook(f.config, f.logger, fs); err != nil {
+				return fmt.Errorf("error in after load hook of fixture set %s: %w", fs.Name, err)
+			}
+		}
 	}
 
 	return nil
 }
+
+func (f *fixtureLoader) verify(writer FixtureWriter, fs *FixtureSet) error {
+	verifier, ok := writer.(FixtureVerifier)
+
+	if !ok {
+		f.logger.Warnf("can not verify fixture set %s as the writer %T is no verifier", fs.Name, writer)
+		return nil
+	}
+
+	if err := verifier.Verify(fs); err != nil {
+		return err
+	}
+
+	f.logger.Infof("verified %d fixtures of fixture set %s", len(fs.Fixtures), fs.Name)
+
+	return nil
+}
+
+func (f *fixtureLoader) isInProfiles(fs *FixtureSet) bool {
+	if len(f.settings.Profiles) == 0 || len(fs.Profiles) == 0 {
+		return true
+	}
+
+	for _, profile := range fs.Profiles {
+		if funk.ContainsString(f.settings.Profiles, profile) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expand returns a copy of the set with the fixtures of the files and the generated fixtures appended to the static
+// ones
+func (f *fixtureLoader) expand(fs *FixtureSet) (*FixtureSet, error) {
+	expanded := *fs
+	expanded.Fixtures = make([]interface{}, 0, len(fs.Fixtures))
+	expanded.Fixtures = append(expanded.Fixtures, fs.Fixtures...)
+
+	for _, file := range fs.Files {
+		fixtures, err := readFixtureFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		f.logger.Infof("read %d fixtures from file %s", len(fixtures), file.Path)
+		expanded.Fixtures = append(expanded.Fixtures, fixtures...)
+	}
+
+	if fs.Generator != nil {
+		generated, err := generateFixtures(f.config, fs.Generator)
+		if err != nil {
+			return nil, err
+		}
+
+		f.logger.Infof("generated %d fixtures with seed %d", len(generated), fs.Generator.Seed)
+		expanded.Fixtures = append(expanded.Fixtures, generated...)
+	}
+
+	return &expanded, nil
+}
@@ -0,0 +1,74 @@
+package fixtures
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	fixtureSetUnvisited = iota
+	fixtureSetVisiting
+	fixtureSetSorted
+)
+
+// sortFixtureSets orders the sets so every set comes after the sets it depends on. Otherwise, the order of the sets is
+// kept.
+func sortFixtureSets(fixtureSets []*FixtureSet) ([]*FixtureSet, error) {
+	byName := make(map[string]int, len(fixtureSets))
+
+	for i, fs := range fixtureSets {
+		if fs.Name == "" {
+			continue
+		}
+
+		if _, ok := byName[fs.Name]; ok {
+			return nil, fmt.Errorf("there are multiple fixture sets with the name %s", fs.Name)
+		}
+
+		byName[fs.Name] = i
+	}
+
+	sorted := make([]*FixtureSet, 0, len(fixtureSets))
+	states := make([]int, len(fixtureSets))
+
+	var visit func(i int, path []string) error
+
+	visit = func(i int, path []string) error {
+		fs := fixtureSets[i]
+		path = append(path, fs.Name)
+
+		switch states[i] {
+		case fixtureSetSorted:
+			return nil
+		case fixtureSetVisiting:
+			return fmt.Errorf("the fixture sets have a cyclic dependency: %s", strings.Join(path, " -> "))
+		}
+
+		states[i] = fixtureSetVisiting
+
+		for _, dependency := range fs.DependsOn {
+			j, ok := byName[dependency]
+
+			if !ok {
+				return fmt.Errorf("the fixture set %s depends on the unknown set %s", fs.Name, dependency)
+			}
+
+			if err := visit(j, path); err != nil {
+				return err
+			}
+		}
+
+		states[i] = fixtureSetSorted
+		sorted = append(sorted, fs)
+
+		return nil
+	}
+
+	for i := range fixtureSets {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,71 @@
+package fixtures
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSortFixtureSets(t *testing.T) {
+	tests := map[string]struct {
+		sets     []*FixtureSet
+		expected []string
+		err      string
+	}{
+		"no dependencies keep their order": {
+			sets: []*FixtureSet{
+				{Name: "b"},
+				{Name: "a"},
+				{},
+			},
+			expected: []string{"b", "a", ""},
+		},
+		"dependencies come first": {
+			sets: []*FixtureSet{
+				{Name: "orders", DependsOn: []string{"users", "products"}},
+				{Name: "users"},
+				{Name: "products", DependsOn: []string{"users"}},
+			},
+			expected: []string{"users", "products", "orders"},
+		},
+		"duplicate names": {
+			sets: []*FixtureSet{
+				{Name: "users"},
+				{Name: "users"},
+			},
+			err: "there are multiple fixture sets with the name users",
+		},
+		"unknown dependency": {
+			sets: []*FixtureSet{
+				{Name: "orders", DependsOn: []string{"users"}},
+			},
+			err: "the fixture set orders depends on the unknown set users",
+		},
+		"cyclic dependency": {
+			sets: []*FixtureSet{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			},
+			err: "the fixture sets have a cyclic dependency: a -> b -> a",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sorted, err := sortFixtureSets(test.sets)
+
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			names := make([]string, len(sorted))
+			for i, fs := range sorted {
+				names[i] = fs.Name
+			}
+
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
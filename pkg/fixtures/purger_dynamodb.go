@@ -1,10 +1,12 @@
 package fixtures
 
 import (
+	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/ddb"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 )
@@ -25,11 +27,29 @@ func newDynamodbPurger(config cfg.Config, logger mon.Logger, settings *ddb.Setti
 	}
 }
 
+// purgeDynamodb deletes the table, it is created again by the repository of the writer. A missing table is already
+// purged.
 func (p *dynamodbPurger) purgeDynamodb() error {
 	tableName := ddb.TableName(p.settings)
 	p.logger.Infof("purging table %s", tableName)
+
 	_, err := p.client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeResourceNotFoundException {
+		p.logger.Infof("table %s does not exist, nothing to purge", tableName)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can not delete table %s: %w", tableName, err)
+	}
+
+	// the table can't be created again as long as it is being deleted
+	if err = p.client.WaitUntilTableNotExists(&dynamodb.DescribeTableInput{TableName: aws.String(tableName)}); err != nil {
+		return fmt.Errorf("can not wait for the deletion of table %s: %w", tableName, err)
+	}
+
 	p.logger.Infof("purging table %s done", tableName)
 
-	return err
+	return nil
 }
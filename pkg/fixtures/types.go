@@ -1,17 +1,42 @@
 package fixtures
 
 import (
+	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
 )
 
+const (
+	// FixtureModePurge purges the sets with Purge before they are written
+	FixtureModePurge = "purge"
+	// FixtureModeIncremental never purges, the writers insert the new and update the existing fixtures, so data
+	// created by others is kept
+	FixtureModeIncremental = "incremental"
+)
+
 type FixtureSet struct {
+	// Name identifies the set for the DependsOn of other sets
+	Name string
+	// DependsOn are the names of the sets which are written before and purged after this set, e.g. the parent rows
+	// of foreign keys
+	DependsOn []string
+	// Profiles are the names of the groups of sets the set belongs to, e.g. minimal, demo or loadtest. A set without
+	// profiles is part of every profile.
+	Profiles []string
 	Enabled  bool
 	Purge    bool
 	Writer   FixtureWriterFactory
 	Fixtures []interface{}
+	// Files are read at load time, their fixtures are written after the static fixtures
+	Files []*FixtureFile
+	// Generator creates more fixtures at load time, they are written after the static fixtures
+	Generator *FixtureGenerator
+	// AfterLoad is called after the set was written, e.g. to recalculate aggregates or to warm caches
+	AfterLoad []FixtureHook
 }
 
+type FixtureHook func(config cfg.Config, logger mon.Logger, fs *FixtureSet) error
+
 type FixtureLoader interface {
 	Load(fixtureSets []*FixtureSet) error
 }
@@ -21,4 +46,28 @@ type FixtureWriter interface {
 	Write(fixture *FixtureSet) error
 }
 
+// A FixtureVerifier checks if the fixtures of a set were written to the destination of the writer
+type FixtureVerifier interface {
+	Verify(fs *FixtureSet) error
+}
+
 type FixtureWriterFactory func(config cfg.Config, logger mon.Logger) (FixtureWriter, error)
+
+// PartialLoadError is returned by the verification if not all fixtures of a set were found in the destination
+type PartialLoadError struct {
+	Destination string
+	Expected    int
+	Found       int
+}
+
+func NewPartialLoadError(destination string, expected int, found int) *PartialLoadError {
+	return &PartialLoadError{
+		Destination: destination,
+		Expected:    expected,
+		Found:       found,
+	}
+}
+
+func (e *PartialLoadError) Error() string {
+	return fmt.Sprintf("only %d of %d fixtures were found in %s", e.Found, e.Expected, e.Destination)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/applike/gosoline/pkg/mon"
 )
 
+// ddbRepoFactory creates the repository once the table is purged, so the repository creates the table again
 type ddbRepoFactory func() (ddb.Repository, error)
 
 type dynamoDbFixtureWriter struct {
@@ -16,6 +17,8 @@ type dynamoDbFixtureWriter struct {
 	purger  *dynamodbPurger
 }
 
+// DynamoDbFixtureWriterFactory writes the fixtures with BatchPutItems to the table of the settings, which is created
+// if it doesn't exist. Purging deletes the table.
 func DynamoDbFixtureWriterFactory(settings *ddb.Settings, options ...DdbWriterOption) FixtureWriterFactory {
 	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
 		settings := &ddb.Settings{
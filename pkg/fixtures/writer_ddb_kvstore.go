@@ -18,12 +18,13 @@ type KvStoreFixture struct {
 }
 
 type dynamoDbKvStoreFixtureWriter struct {
-	logger  mon.Logger
-	factory ddbKvstoreFactory
-	purger  *dynamodbPurger
+	logger   mon.Logger
+	factory  ddbKvstoreFactory
+	purger   *dynamodbPurger
+	settings *kvStoreWriterSettings
 }
 
-func DynamoDbKvStoreFixtureWriterFactory(modelId *mdl.ModelId) FixtureWriterFactory {
+func DynamoDbKvStoreFixtureWriterFactory(modelId *mdl.ModelId, options ...KvStoreWriterOption) FixtureWriterFactory {
 	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
 		settings := &kvstore.Settings{
 			AppId: cfg.AppId{
@@ -46,15 +47,16 @@ func DynamoDbKvStoreFixtureWriterFactory(modelId *mdl.ModelId) FixtureWriterFact
 			ModelId: kvstoreModel,
 		})
 
-		return NewDynamoDbKvStoreFixtureWriterWithInterfaces(logger, factory, purger), nil
+		return NewDynamoDbKvStoreFixtureWriterWithInterfaces(logger, factory, purger, options...), nil
 	}
 }
 
-func NewDynamoDbKvStoreFixtureWriterWithInterfaces(logger mon.Logger, factory ddbKvstoreFactory, purger *dynamodbPurger) FixtureWriter {
+func NewDynamoDbKvStoreFixtureWriterWithInterfaces(logger mon.Logger, factory ddbKvstoreFactory, purger *dynamodbPurger, options ...KvStoreWriterOption) FixtureWriter {
 	return &dynamoDbKvStoreFixtureWriter{
-		logger:  logger,
-		factory: factory,
-		purger:  purger,
+		logger:   logger,
+		factory:  factory,
+		purger:   purger,
+		settings: newKvStoreWriterSettings(options),
 	}
 }
 
@@ -72,11 +74,9 @@ func (d *dynamoDbKvStoreFixtureWriter) Write(fs *FixtureSet) error {
 		return fmt.Errorf("can not create store: %w", err)
 	}
 
-	m := map[interface{}]interface{}{}
-
-	for _, item := range fs.Fixtures {
-		kvItem := item.(*KvStoreFixture)
-		m[kvItem.Key] = kvItem.Value
+	m, err := kvStoreFixtureValues(fs.Fixtures, d.settings)
+	if err != nil {
+		return err
 	}
 
 	if err = store.PutBatch(context.Background(), m); err != nil {
@@ -87,3 +87,16 @@ func (d *dynamoDbKvStoreFixtureWriter) Write(fs *FixtureSet) error {
 
 	return nil
 }
+
+func (d *dynamoDbKvStoreFixtureWriter) Verify(fs *FixtureSet) error {
+	if len(fs.Fixtures) == 0 {
+		return nil
+	}
+
+	store, err := d.factory()
+	if err != nil {
+		return fmt.Errorf("can not create store: %w", err)
+	}
+
+	return verifyKvStoreFixtures(store, "dynamodb kvstore", fs.Fixtures, d.settings)
+}
@@ -0,0 +1,131 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+type kvStoreFixtureWriter struct {
+	logger   mon.Logger
+	store    kvstore.KvStore
+	name     string
+	settings *kvStoreWriterSettings
+}
+
+// KvStoreFixtureWriterFactory writes the fixtures to the kvstore configured at kvstore.<name>, e.g. a chain of redis
+// and ddb. Purging removes all keys of the store from every element of the chain.
+func KvStoreFixtureWriterFactory(name string, options ...KvStoreWriterOption) FixtureWriterFactory {
+	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
+		store, err := kvstore.NewConfigurableKvStore(config, logger, name)
+		if err != nil {
+			return nil, fmt.Errorf("can not create kvstore %s: %w", name, err)
+		}
+
+		return NewKvStoreFixtureWriterWithInterfaces(logger, store, name, options...), nil
+	}
+}
+
+func NewKvStoreFixtureWriterWithInterfaces(logger mon.Logger, store kvstore.KvStore, name string, options ...KvStoreWriterOption) FixtureWriter {
+	return &kvStoreFixtureWriter{
+		logger:   logger,
+		store:    store,
+		name:     name,
+		settings: newKvStoreWriterSettings(options),
+	}
+}
+
+func (w *kvStoreFixtureWriter) Purge() error {
+	flusher, ok := w.store.(kvstore.PrefixFlusher)
+
+	if !ok {
+		return fmt.Errorf("can not purge kvstore %s of type %T", w.name, w.store)
+	}
+
+	w.logger.Infof("purging kvstore %s", w.name)
+
+	if err := flusher.FlushPrefix(context.Background(), ""); err != nil {
+		return fmt.Errorf("can not purge kvstore %s: %w", w.name, err)
+	}
+
+	w.logger.Infof("purging kvstore %s done", w.name)
+
+	return nil
+}
+
+func (w *kvStoreFixtureWriter) Write(fs *FixtureSet) error {
+	if len(fs.Fixtures) == 0 {
+		return nil
+	}
+
+	m, err := kvStoreFixtureValues(fs.Fixtures, w.settings)
+	if err != nil {
+		return fmt.Errorf("can not write fixtures to kvstore %s: %w", w.name, err)
+	}
+
+	if err = w.store.PutBatch(context.Background(), m); err != nil {
+		return err
+	}
+
+	w.logger.Infof("loaded %d kvstore fixtures to %s", len(fs.Fixtures), w.name)
+
+	return nil
+}
+
+// kvStoreFixtureValues maps the keys to the values of *KvStoreFixture items and models with a key func
+func kvStoreFixtureValues(fixtures []interface{}, settings *kvStoreWriterSettings) (map[interface{}]interface{}, error) {
+	m := make(map[interface{}]interface{}, len(fixtures))
+
+	for _, item := range fixtures {
+		if kvItem, ok := item.(*KvStoreFixture); ok {
+			m[kvItem.Key] = kvItem.Value
+			continue
+		}
+
+		if settings.keyFunc == nil {
+			return nil, fmt.Errorf("the fixture of type %T is no *KvStoreFixture and there is no key func to get its key", item)
+		}
+
+		key, err := settings.keyFunc(item)
+		if err != nil {
+			return nil, fmt.Errorf("can not get key of fixture of type %T: %w", item, err)
+		}
+
+		m[key] = item
+	}
+
+	return m, nil
+}
+
+func (w *kvStoreFixtureWriter) Verify(fs *FixtureSet) error {
+	return verifyKvStoreFixtures(w.store, fmt.Sprintf("kvstore %s", w.name), fs.Fixtures, w.settings)
+}
+
+// verifyKvStoreFixtures checks if the store contains the keys of all fixtures
+func verifyKvStoreFixtures(store kvstore.KvStore, destination string, fixtures []interface{}, settings *kvStoreWriterSettings) error {
+	m, err := kvStoreFixtureValues(fixtures, settings)
+	if err != nil {
+		return fmt.Errorf("can not verify fixtures of %s: %w", destination, err)
+	}
+
+	found := 0
+
+	for key := range m {
+		ok, err := store.Contains(context.Background(), key)
+		if err != nil {
+			return fmt.Errorf("can not check key %v of %s: %w", key, destination, err)
+		}
+
+		if ok {
+			found++
+		}
+	}
+
+	if found != len(m) {
+		return NewPartialLoadError(destination, len(m), found)
+	}
+
+	return nil
+}
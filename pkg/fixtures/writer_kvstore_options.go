@@ -0,0 +1,27 @@
+package fixtures
+
+// KvStoreKeyFunc returns the key of a fixture which is written to a kvstore as it is instead of a *KvStoreFixture
+type KvStoreKeyFunc func(fixture interface{}) (interface{}, error)
+
+type kvStoreWriterSettings struct {
+	keyFunc KvStoreKeyFunc
+}
+
+type KvStoreWriterOption func(settings *kvStoreWriterSettings)
+
+// WithKvStoreKey allows to use the models as fixtures directly, the key of every model is returned by the function
+func WithKvStoreKey(keyFunc KvStoreKeyFunc) KvStoreWriterOption {
+	return func(settings *kvStoreWriterSettings) {
+		settings.keyFunc = keyFunc
+	}
+}
+
+func newKvStoreWriterSettings(options []KvStoreWriterOption) *kvStoreWriterSettings {
+	settings := &kvStoreWriterSettings{}
+
+	for _, opt := range options {
+		opt(settings)
+	}
+
+	return settings
+}
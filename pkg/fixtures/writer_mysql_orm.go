@@ -6,6 +6,9 @@ import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jinzhu/gorm"
+	"reflect"
+	"strings"
 )
 
 type mysqlOrmFixtureWriter struct {
@@ -13,9 +16,10 @@ type mysqlOrmFixtureWriter struct {
 	metadata *db_repo.Metadata
 	repo     db_repo.Repository
 	purger   *mysqlPurger
+	settings *mysqlOrmWriterSettings
 }
 
-func MysqlOrmFixtureWriterFactory(metadata *db_repo.Metadata) FixtureWriterFactory {
+func MysqlOrmFixtureWriterFactory(metadata *db_repo.Metadata, options ...MysqlOrmWriterOption) FixtureWriterFactory {
 	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
 		metadata.ModelId.PadFromConfig(config)
 
@@ -34,16 +38,23 @@ func MysqlOrmFixtureWriterFactory(metadata *db_repo.Metadata) FixtureWriterFacto
 			return nil, fmt.Errorf("can not create purger: %w", err)
 		}
 
-		return NewMysqlFixtureWriterWithInterfaces(logger, metadata, repo, purger), nil
+		return NewMysqlFixtureWriterWithInterfaces(logger, metadata, repo, purger, options...), nil
 	}
 }
 
-func NewMysqlFixtureWriterWithInterfaces(logger mon.Logger, metadata *db_repo.Metadata, repo db_repo.Repository, purger *mysqlPurger) FixtureWriter {
+func NewMysqlFixtureWriterWithInterfaces(logger mon.Logger, metadata *db_repo.Metadata, repo db_repo.Repository, purger *mysqlPurger, options ...MysqlOrmWriterOption) FixtureWriter {
+	settings := &mysqlOrmWriterSettings{}
+
+	for _, opt := range options {
+		opt(settings)
+	}
+
 	return &mysqlOrmFixtureWriter{
 		logger:   logger,
 		metadata: metadata,
 		repo:     repo,
 		purger:   purger,
+		settings: settings,
 	}
 }
 
@@ -64,6 +75,10 @@ func (m *mysqlOrmFixtureWriter) Purge() error {
 func (m *mysqlOrmFixtureWriter) Write(fs *FixtureSet) error {
 	ctx := context.Background()
 
+	if len(m.settings.naturalKey) > 0 {
+		return m.upsert(ctx, fs)
+	}
+
 	for _, item := range fs.Fixtures {
 		model := item.(db_repo.ModelBased)
 
@@ -78,3 +93,168 @@ func (m *mysqlOrmFixtureWriter) Write(fs *FixtureSet) error {
 
 	return nil
 }
+
+func (m *mysqlOrmFixtureWriter) upsert(ctx context.Context, fs *FixtureSet) error {
+	models := make([]db_repo.ModelBased, len(fs.Fixtures))
+
+	for i, item := range fs.Fixtures {
+		models[i] = item.(db_repo.ModelBased)
+	}
+
+	if err := m.repo.Upsert(ctx, models, m.settings.naturalKey...); err != nil {
+		return err
+	}
+
+	m.logger.Infof("upserted %d mysql fixtures by %v", len(fs.Fixtures), m.settings.naturalKey)
+
+	return nil
+}
+
+// Verify counts the rows with the ids of the fixtures. With a natural key, the rows are counted by the values of the
+// natural key columns instead, so the fixtures don't need ids.
+func (m *mysqlOrmFixtureWriter) Verify(fs *FixtureSet) error {
+	if len(fs.Fixtures) == 0 {
+		return nil
+	}
+
+	var err error
+	var expected int
+	var qb *db_repo.QueryBuilder
+
+	if len(m.settings.naturalKey) > 0 {
+		qb, expected, err = m.naturalKeyQuery(fs)
+	} else {
+		qb, expected, err = m.idQuery(fs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	count, err := m.repo.Count(context.Background(), qb, fs.Fixtures[0].(db_repo.ModelBased))
+	if err != nil {
+		return fmt.Errorf("can not count the mysql fixtures: %w", err)
+	}
+
+	if count != expected {
+		return NewPartialLoadError(fmt.Sprintf("table %s", m.metadata.TableName), expected, count)
+	}
+
+	return nil
+}
+
+func (m *mysqlOrmFixtureWriter) idQuery(fs *FixtureSet) (*db_repo.QueryBuilder, int, error) {
+	ids := make([]uint, 0, len(fs.Fixtures))
+	seen := make(map[uint]bool, len(fs.Fixtures))
+
+	for _, item := range fs.Fixtures {
+		id := item.(db_repo.ModelBased).GetId()
+
+		if id == nil {
+			return nil, 0, fmt.Errorf("can not verify a mysql fixture of type %T without an id", item)
+		}
+
+		if !seen[*id] {
+			seen[*id] = true
+			ids = append(ids, *id)
+		}
+	}
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Where(fmt.Sprintf("%s.id IN (?)", m.metadata.TableName), ids)
+
+	return qb, len(ids), nil
+}
+
+// naturalKeyQuery matches the rows with any of the distinct combinations of the natural key values of the fixtures
+func (m *mysqlOrmFixtureWriter) naturalKeyQuery(fs *FixtureSet) (*db_repo.QueryBuilder, int, error) {
+	conditions := make([]string, 0, len(m.settings.naturalKey))
+
+	for _, column := range m.settings.naturalKey {
+		conditions = append(conditions, fmt.Sprintf("%s.%s = ?", m.metadata.TableName, column))
+	}
+
+	condition := fmt.Sprintf("(%s)", strings.Join(conditions, " AND "))
+	rows := make([]string, 0, len(fs.Fixtures))
+	args := make([]interface{}, 0, len(fs.Fixtures)*len(m.settings.naturalKey))
+	seen := make(map[string]bool, len(fs.Fixtures))
+
+	for _, item := range fs.Fixtures {
+		values := make([]interface{}, 0, len(m.settings.naturalKey))
+
+		for _, column := range m.settings.naturalKey {
+			value, ok := mysqlColumnValue(reflect.ValueOf(item), column)
+
+			if !ok {
+				return nil, 0, fmt.Errorf("the mysql fixture of type %T has no field for the natural key column %s", item, column)
+			}
+
+			values = append(values, value)
+		}
+
+		key := fmt.Sprintf("%v", values)
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		rows = append(rows, condition)
+		args = append(args, values...)
+	}
+
+	qb := db_repo.NewQueryBuilder()
+	qb.Where(strings.Join(rows, " OR "), args...)
+
+	return qb, len(rows), nil
+}
+
+// mysqlColumnValue returns the value of the field stored in the column, following the naming of gorm and the
+// fields of embedded structs like db_repo.Model
+func mysqlColumnValue(value reflect.Value, column string) (interface{}, bool) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, false
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if field.Anonymous {
+			if result, ok := mysqlColumnValue(value.Field(i), column); ok {
+				return result, true
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if mysqlColumnName(field) == column {
+			return value.Field(i).Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+func mysqlColumnName(field reflect.StructField) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		parts := strings.SplitN(setting, ":", 2)
+
+		if len(parts) == 2 && strings.EqualFold(strings.TrimSpace(parts[0]), "column") {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+
+	return gorm.ToDBName(field.Name)
+}
@@ -0,0 +1,15 @@
+package fixtures
+
+type mysqlOrmWriterSettings struct {
+	naturalKey []string
+}
+
+type MysqlOrmWriterOption func(settings *mysqlOrmWriterSettings)
+
+// WithMysqlNaturalKey upserts the fixtures by the columns of a unique key instead of updating them by their ids, so
+// fixtures without ids are inserted only once
+func WithMysqlNaturalKey(columns ...string) MysqlOrmWriterOption {
+	return func(settings *mysqlOrmWriterSettings) {
+		settings.naturalKey = columns
+	}
+}
@@ -0,0 +1,71 @@
+package fixtures_test
+
+import (
+	"github.com/applike/gosoline/pkg/db-repo"
+	dbRepoMocks "github.com/applike/gosoline/pkg/db-repo/mocks"
+	"github.com/applike/gosoline/pkg/fixtures"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type mysqlUser struct {
+	db_repo.Model
+	Email  string
+	Tenant string `gorm:"column:tenant_name"`
+}
+
+func TestMysqlOrmFixtureWriter_VerifyNaturalKey(t *testing.T) {
+	metadata := &db_repo.Metadata{
+		TableName: "users",
+	}
+
+	fs := &fixtures.FixtureSet{
+		Fixtures: []interface{}{
+			&mysqlUser{Email: "a@example.com", Tenant: "a"},
+			&mysqlUser{Email: "b@example.com", Tenant: "a"},
+			&mysqlUser{Email: "a@example.com", Tenant: "a"},
+		},
+	}
+
+	tests := map[string]struct {
+		count int
+		err   error
+	}{
+		"all found": {
+			count: 2,
+		},
+		"partially found": {
+			count: 1,
+			err:   fixtures.NewPartialLoadError("table users", 2, 1),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := new(dbRepoMocks.Repository)
+			repo.On("Count", mock.Anything, mock.AnythingOfType("*db_repo.QueryBuilder"), fs.Fixtures[0]).Return(test.count, nil).Once()
+
+			writer := fixtures.NewMysqlFixtureWriterWithInterfaces(monMocks.NewLoggerMockedAll(), metadata, repo, nil, fixtures.WithMysqlNaturalKey("email", "tenant_name"))
+			err := writer.(fixtures.FixtureVerifier).Verify(fs)
+
+			assert.Equal(t, test.err, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMysqlOrmFixtureWriter_VerifyNaturalKeyUnknownColumn(t *testing.T) {
+	repo := new(dbRepoMocks.Repository)
+	writer := fixtures.NewMysqlFixtureWriterWithInterfaces(monMocks.NewLoggerMockedAll(), &db_repo.Metadata{}, repo, nil, fixtures.WithMysqlNaturalKey("name"))
+
+	err := writer.(fixtures.FixtureVerifier).Verify(&fixtures.FixtureSet{
+		Fixtures: []interface{}{
+			&mysqlUser{Email: "a@example.com"},
+		},
+	})
+
+	assert.EqualError(t, err, "the mysql fixture of type *fixtures_test.mysqlUser has no field for the natural key column name")
+	repo.AssertNotCalled(t, "Count")
+}
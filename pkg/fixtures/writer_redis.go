@@ -90,3 +90,32 @@ func (d *redisFixtureWriter) Write(fs *FixtureSet) error {
 
 	return nil
 }
+
+func (d *redisFixtureWriter) Verify(fs *FixtureSet) error {
+	keys := make([]string, 0, len(fs.Fixtures))
+	seen := make(map[string]bool, len(fs.Fixtures))
+
+	for _, item := range fs.Fixtures {
+		key := item.(*RedisFixture).Key
+
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	found, err := d.client.Exists(context.Background(), keys...)
+	if err != nil {
+		return fmt.Errorf("can not check the keys of the redis fixtures: %w", err)
+	}
+
+	if int(found) != len(keys) {
+		return NewPartialLoadError("redis", len(keys), int(found))
+	}
+
+	return nil
+}
@@ -10,12 +10,13 @@ import (
 )
 
 type redisKvStoreFixtureWriter struct {
-	logger mon.Logger
-	store  kvstore.KvStore
-	purger *redisPurger
+	logger   mon.Logger
+	store    kvstore.KvStore
+	purger   *redisPurger
+	settings *kvStoreWriterSettings
 }
 
-func RedisKvStoreFixtureWriterFactory(modelId *mdl.ModelId) FixtureWriterFactory {
+func RedisKvStoreFixtureWriterFactory(modelId *mdl.ModelId, options ...KvStoreWriterOption) FixtureWriterFactory {
 	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
 		settings := &kvstore.Settings{
 			AppId: cfg.AppId{
@@ -39,15 +40,16 @@ func RedisKvStoreFixtureWriterFactory(modelId *mdl.ModelId) FixtureWriterFactory
 			return nil, fmt.Errorf("can not create redis purger: %w", err)
 		}
 
-		return NewRedisKvStoreFixtureWriterWithInterfaces(logger, store, purger), nil
+		return NewRedisKvStoreFixtureWriterWithInterfaces(logger, store, purger, options...), nil
 	}
 }
 
-func NewRedisKvStoreFixtureWriterWithInterfaces(logger mon.Logger, store kvstore.KvStore, purger *redisPurger) FixtureWriter {
+func NewRedisKvStoreFixtureWriterWithInterfaces(logger mon.Logger, store kvstore.KvStore, purger *redisPurger, options ...KvStoreWriterOption) FixtureWriter {
 	return &redisKvStoreFixtureWriter{
-		logger: logger,
-		store:  store,
-		purger: purger,
+		logger:   logger,
+		store:    store,
+		purger:   purger,
+		settings: newKvStoreWriterSettings(options),
 	}
 }
 
@@ -60,15 +62,12 @@ func (d *redisKvStoreFixtureWriter) Write(fs *FixtureSet) error {
 		return nil
 	}
 
-	m := map[interface{}]interface{}{}
-
-	for _, item := range fs.Fixtures {
-		kvItem := item.(*KvStoreFixture)
-		m[kvItem.Key] = kvItem.Value
+	m, err := kvStoreFixtureValues(fs.Fixtures, d.settings)
+	if err != nil {
+		return err
 	}
 
-	err := d.store.PutBatch(context.Background(), m)
-	if err != nil {
+	if err = d.store.PutBatch(context.Background(), m); err != nil {
 		return err
 	}
 
@@ -76,3 +75,7 @@ func (d *redisKvStoreFixtureWriter) Write(fs *FixtureSet) error {
 
 	return nil
 }
+
+func (d *redisKvStoreFixtureWriter) Verify(fs *FixtureSet) error {
+	return verifyKvStoreFixtures(d.store, "redis kvstore", fs.Fixtures, d.settings)
+}
@@ -0,0 +1,87 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+)
+
+// StreamFixture is a message published by the StreamFixtureWriterFactory. The body is encoded with the encoding of the
+// writer, a *stream.Message is written as it is.
+type StreamFixture struct {
+	Body       interface{}
+	Attributes map[string]interface{}
+}
+
+type streamFixtureWriter struct {
+	logger  mon.Logger
+	encoder stream.MessageEncoder
+	output  stream.Output
+	name    string
+}
+
+// StreamFixtureWriterFactory publishes the fixtures to the output configured at stream.output.<name>, e.g. a sqs
+// queue or a sns topic, so consumers start with a known backlog. The bodies are encoded as json. The messages of an
+// output can't be purged.
+func StreamFixtureWriterFactory(name string) FixtureWriterFactory {
+	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
+		output, err := stream.NewConfigurableOutput(config, logger, name)
+		if err != nil {
+			return nil, fmt.Errorf("can not create output %s: %w", name, err)
+		}
+
+		encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+			Encoding: stream.EncodingJson,
+		})
+
+		return NewStreamFixtureWriterWithInterfaces(logger, encoder, output, name), nil
+	}
+}
+
+func NewStreamFixtureWriterWithInterfaces(logger mon.Logger, encoder stream.MessageEncoder, output stream.Output, name string) FixtureWriter {
+	return &streamFixtureWriter{
+		logger:  logger,
+		encoder: encoder,
+		output:  output,
+		name:    name,
+	}
+}
+
+func (w *streamFixtureWriter) Purge() error {
+	return fmt.Errorf("can not purge the messages of output %s", w.name)
+}
+
+func (w *streamFixtureWriter) Write(fs *FixtureSet) error {
+	if len(fs.Fixtures) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	messages := make([]stream.WritableMessage, len(fs.Fixtures))
+
+	for i, item := range fs.Fixtures {
+		switch fixture := item.(type) {
+		case *stream.Message:
+			messages[i] = fixture
+		case *StreamFixture:
+			msg, err := w.encoder.Encode(ctx, fixture.Body, fixture.Attributes)
+			if err != nil {
+				return fmt.Errorf("can not encode fixture %d for output %s: %w", i, w.name, err)
+			}
+
+			messages[i] = msg
+		default:
+			return fmt.Errorf("the fixture of type %T is neither a *StreamFixture nor a *stream.Message", item)
+		}
+	}
+
+	if err := w.output.Write(ctx, messages); err != nil {
+		return fmt.Errorf("can not write fixtures to output %s: %w", w.name, err)
+	}
+
+	w.logger.Infof("published %d stream fixtures to %s", len(fs.Fixtures), w.name)
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"errors"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+
+	metricCircuitBreakerState    = "HttpClientCircuitBreakerState"
+	metricCircuitBreakerRejected = "HttpClientCircuitBreakerRejected"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitBreakerSettings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// number of consecutive failures (errors or 5XX responses) after which the circuit of a host opens
+	FailureThreshold int `cfg:"failure_threshold" default:"5"`
+	// time requests to a host fail fast before a single trial request is let through again
+	OpenDuration time.Duration `cfg:"open_duration" default:"30s"`
+}
+
+type circuit struct {
+	state    string
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// circuitBreaker keeps a separate circuit per host, so a failing dependency doesn't affect requests to other hosts.
+type circuitBreaker struct {
+	logger   mon.Logger
+	clock    clock.Clock
+	mo       mon.MetricWriter
+	settings CircuitBreakerSettings
+
+	lck      sync.Mutex
+	circuits map[string]*circuit
+}
+
+func newCircuitBreaker(logger mon.Logger, clock clock.Clock, mo mon.MetricWriter, settings CircuitBreakerSettings) *circuitBreaker {
+	return &circuitBreaker{
+		logger:   logger.WithChannel("http_circuit_breaker"),
+		clock:    clock,
+		mo:       mo,
+		settings: settings,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// allow returns ErrCircuitOpen if requests to the host should fail fast. Every allowed request has to be
+// completed by calling done.
+func (b *circuitBreaker) allow(host string) error {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	c := b.getCircuit(host)
+
+	switch c.state {
+	case CircuitOpen:
+		if b.clock.Now().Sub(c.openedAt) < b.settings.OpenDuration {
+			b.writeMetric(metricCircuitBreakerRejected, host)
+			return ErrCircuitOpen
+		}
+
+		b.changeState(host, c, CircuitHalfOpen)
+		c.trial = true
+	case CircuitHalfOpen:
+		if c.trial {
+			b.writeMetric(metricCircuitBreakerRejected, host)
+			return ErrCircuitOpen
+		}
+
+		c.trial = true
+	}
+
+	return nil
+}
+
+// done records the outcome of a request. Canceled requests only release the trial of a half open circuit.
+func (b *circuitBreaker) done(host string, statusCode int, err error, canceled bool) {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	c := b.getCircuit(host)
+	c.trial = false
+
+	if canceled {
+		return
+	}
+
+	if err == nil && statusCode < http.StatusInternalServerError {
+		c.failures = 0
+
+		if c.state != CircuitClosed {
+			b.changeState(host, c, CircuitClosed)
+		}
+
+		return
+	}
+
+	c.failures++
+
+	if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= b.settings.FailureThreshold) {
+		c.openedAt = b.clock.Now()
+		b.changeState(host, c, CircuitOpen)
+	}
+}
+
+func (b *circuitBreaker) getCircuit(host string) *circuit {
+	if _, ok := b.circuits[host]; !ok {
+		b.circuits[host] = &circuit{
+			state: CircuitClosed,
+		}
+	}
+
+	return b.circuits[host]
+}
+
+func (b *circuitBreaker) changeState(host string, c *circuit, state string) {
+	b.logger.WithFields(mon.Fields{
+		"host":     host,
+		"from":     c.state,
+		"to":       state,
+		"failures": c.failures,
+	}).Warnf("circuit breaker for host %s changed from %s to %s", host, c.state, state)
+
+	c.state = state
+
+	b.mo.WriteOne(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		Timestamp:  b.clock.Now(),
+		MetricName: metricCircuitBreakerState,
+		Dimensions: mon.MetricDimensions{
+			"Host":  host,
+			"State": state,
+		},
+		Unit:  mon.UnitCount,
+		Value: 1.0,
+	})
+}
+
+func (b *circuitBreaker) writeMetric(metricName string, host string) {
+	b.mo.WriteOne(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		Timestamp:  b.clock.Now(),
+		MetricName: metricName,
+		Dimensions: mon.MetricDimensions{
+			"Host": host,
+		},
+		Unit:  mon.UnitCount,
+		Value: 1.0,
+	})
+}
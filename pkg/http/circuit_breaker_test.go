@@ -0,0 +1,56 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/http"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	netHttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("WriteOne", mock.Anything)
+	fakeClock := clock.NewFakeClock()
+
+	status := netHttp.StatusInternalServerError
+	calls := 0
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		calls++
+		res.WriteHeader(status)
+	}))
+	defer testServer.Close()
+
+	client := http.NewHttpClientWithInterfaces(logger, fakeClock, metricWriter, resty.New(), http.WithSettings(&http.Settings{
+		CircuitBreaker: http.CircuitBreakerSettings{
+			Enabled:          true,
+			FailureThreshold: 2,
+			OpenDuration:     time.Minute,
+		},
+	}))
+
+	get := func() error {
+		_, err := client.Get(context.Background(), client.NewRequest().WithUrl(testServer.URL))
+		return err
+	}
+
+	assert.NoError(t, get())
+	assert.NoError(t, get())
+	assert.True(t, errors.Is(get(), http.ErrCircuitOpen), "the circuit should be open after 2 failures")
+	assert.Equal(t, 2, calls)
+
+	fakeClock.Advance(time.Minute)
+	status = netHttp.StatusOK
+
+	assert.NoError(t, get(), "a trial request should be let through after the open duration")
+	assert.NoError(t, get(), "the circuit should be closed after a successful trial")
+	assert.Equal(t, 4, calls)
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/clock"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/tracing"
 	"github.com/go-resty/resty/v2"
 	"net/http"
 	netUrl "net/url"
+	"strconv"
 	"time"
 )
 
@@ -32,6 +34,7 @@ type Client interface {
 	Patch(ctx context.Context, request *Request) (*Response, error)
 	Post(ctx context.Context, request *Request) (*Response, error)
 	Put(ctx context.Context, request *Request) (*Response, error)
+	Stream(ctx context.Context, method string, request *Request) (*StreamResponse, error)
 	SetTimeout(timeout time.Duration)
 	SetUserAgent(ua string)
 	SetProxyUrl(p string)
@@ -63,14 +66,34 @@ type client struct {
 	defaultHeaders headers
 	http           restyClient
 	mo             mon.MetricWriter
+	tracer         tracing.Tracer
+	settings       *Settings
+	baseUrl        *netUrl.URL
+
+	retryConditions []RetryConditionFunc
+	budget          *retryBudget
+	hedging         *hedging
+	breaker         *circuitBreaker
 }
 
 type Settings struct {
-	RetryCount       int
-	Timeout          time.Duration
-	RetryWaitTime    time.Duration `cfg:"retry_wait_time" default:"100ms"`
-	RetryMaxWaitTime time.Duration `cfg:"retry_max_wait_time" default:"2000ms"`
-	FollowRedirect   bool          `cfg:"follow_redirects"`
+	// name of the client, used as dimension of the metrics
+	Name string
+	// relative request urls are resolved against the base url
+	BaseUrl string `cfg:"base_url"`
+	// headers added to every request of the client
+	Headers          map[string]string      `cfg:"headers"`
+	ProxyUrl         string                 `cfg:"proxy_url"`
+	RetryCount       int                    `cfg:"retry_count"`
+	Timeout          time.Duration          `cfg:"request_timeout"`
+	RetryWaitTime    time.Duration          `cfg:"retry_wait_time" default:"100ms"`
+	RetryMaxWaitTime time.Duration          `cfg:"retry_max_wait_time" default:"2000ms"`
+	FollowRedirect   bool                   `cfg:"follow_redirects"`
+	Retry            RetrySettings          `cfg:"retry"`
+	Hedging          HedgingSettings        `cfg:"hedging"`
+	CircuitBreaker   CircuitBreakerSettings `cfg:"circuit_breaker"`
+	SigV4            SigV4Settings          `cfg:"sigv4"`
+	Recorder         RecorderSettings       `cfg:"recorder"`
 }
 
 func NewHttpClient(config cfg.Config, logger mon.Logger) Client {
@@ -81,27 +104,83 @@ func NewHttpClient(config cfg.Config, logger mon.Logger) Client {
 	settings := &Settings{}
 	config.UnmarshalKey("http_client", settings)
 
+	settings.Name = "default"
 	settings.RetryCount = config.GetInt("http_client_retry_count")
 	settings.Timeout = config.GetDuration("http_client_request_timeout")
 
+	tracer, err := tracing.ProvideTracer(config, logger)
+	if err != nil {
+		logger.Error(err, "can not create tracer, outgoing requests are not traced")
+		tracer = tracing.NewNoopTracer()
+	}
+
+	return newHttpClientFromSettings(logger, c, mo, tracer, settings)
+}
+
+func newHttpClientFromSettings(logger mon.Logger, c clock.Clock, mo mon.MetricWriter, tracer tracing.Tracer, settings *Settings) Client {
 	httpClient := resty.New()
 	httpClient.SetRedirectPolicy(resty.FlexibleRedirectPolicy(10))
-	httpClient.SetRetryCount(settings.RetryCount)
 	httpClient.SetTimeout(settings.Timeout)
-	httpClient.SetRetryWaitTime(settings.RetryWaitTime)
-	httpClient.SetRetryMaxWaitTime(settings.RetryMaxWaitTime)
 
-	return NewHttpClientWithInterfaces(logger, c, mo, httpClient)
+	if settings.ProxyUrl != "" {
+		httpClient.SetProxy(settings.ProxyUrl)
+	}
+
+	if settings.SigV4.Enabled {
+		signer := newSigV4Signer(settings.SigV4)
+		httpClient.SetPreRequestHook(sigV4PreRequestHook(signer, c, settings.SigV4))
+	}
+
+	if settings.Recorder.Enabled {
+		httpClient.SetTransport(NewRecordingTransport(httpClient.GetClient().Transport, settings.Recorder))
+	}
+
+	return NewHttpClientWithInterfaces(logger, c, mo, httpClient, WithTracer(tracer), WithSettings(settings))
 }
 
-func NewHttpClientWithInterfaces(logger mon.Logger, c clock.Clock, mo mon.MetricWriter, httpClient restyClient) Client {
-	return &client{
+func NewHttpClientWithInterfaces(logger mon.Logger, c clock.Clock, mo mon.MetricWriter, httpClient restyClient, options ...ClientOption) Client {
+	opts := buildClientOptions(options)
+	settings := opts.settings
+
+	client := &client{
 		logger:         logger,
 		clock:          c,
 		defaultHeaders: make(headers),
 		http:           httpClient,
 		mo:             mo,
+		tracer:         opts.tracer,
+		settings:       settings,
+
+		retryConditions: make([]RetryConditionFunc, 0),
+	}
+
+	for key, value := range settings.Headers {
+		client.defaultHeaders[key] = value
 	}
+
+	if settings.BaseUrl != "" {
+		baseUrl, err := netUrl.Parse(settings.BaseUrl)
+
+		if err != nil {
+			logger.Errorf(err, "can not parse base url %s of http client, using request urls as they are", settings.BaseUrl)
+		} else {
+			client.baseUrl = baseUrl
+		}
+	}
+
+	if settings.Retry.BudgetRatio > 0 {
+		client.budget = newRetryBudget(c, settings.Retry)
+	}
+
+	if settings.Hedging.Enabled {
+		client.hedging = newHedging(settings.Hedging)
+	}
+
+	if settings.CircuitBreaker.Enabled {
+		client.breaker = newCircuitBreaker(logger, c, mo, settings.CircuitBreaker)
+	}
+
+	return client
 }
 
 func (c *client) NewRequest() *Request {
@@ -140,10 +219,9 @@ func (c *client) SetProxyUrl(p string) {
 	c.http.SetProxy(p)
 }
 
+// AddRetryCondition adds a condition to retry a request on. Requests failing with an error are always retried.
 func (c *client) AddRetryCondition(f RetryConditionFunc) {
-	c.http.AddRetryCondition(func(r *resty.Response, e error) bool {
-		return f(buildResponse(r, nil), e)
-	})
+	c.retryConditions = append(c.retryConditions, f)
 }
 
 func (c *client) SetRedirectValidator(allowRequest func(request *http.Request) bool) {
@@ -180,6 +258,16 @@ func (c *client) Put(ctx context.Context, request *Request) (*Response, error) {
 }
 
 func (c *client) do(ctx context.Context, method string, request *Request) (*Response, error) {
+	resp, totalDuration, err := c.execute(ctx, method, request, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildResponse(resp, &totalDuration), nil
+}
+
+func (c *client) execute(ctx context.Context, method string, request *Request, stream bool) (*resty.Response, time.Duration, error) {
 	req, url, err := request.build()
 	logger := c.logger.WithContext(ctx).WithFields(mon.Fields{
 		"url":    url,
@@ -188,22 +276,83 @@ func (c *client) do(ctx context.Context, method string, request *Request) (*Resp
 
 	if err != nil {
 		logger.Error(err, "failed to assemble request")
-		return nil, fmt.Errorf("failed to assemble request: %w", err)
+		return nil, 0, fmt.Errorf("failed to assemble request: %w", err)
 	}
 
-	req.SetContext(ctx)
 	req.SetHeaders(c.defaultHeaders)
 
+	if requestId, ok := mon.RequestIdFromContext(ctx); ok && req.Header.Get(mon.HeaderRequestId) == "" {
+		req.SetHeader(mon.HeaderRequestId, requestId)
+	}
+
 	if request.outputFile != nil {
 		req.SetOutput(*request.outputFile)
 	}
 
-	c.writeMetric(metricRequest, method, mon.UnitCount, 1.0)
+	if request.uploadProgress != nil {
+		req.SetBody(newProgressReader(request.bodyReader, request.bodySize, request.uploadProgress))
+	}
+
+	host := request.url.Host
+
+	if host == "" && c.baseUrl != nil {
+		url = c.baseUrl.ResolveReference(request.url).String()
+		host = c.baseUrl.Host
+	}
+
+	ctx, span := c.tracer.StartSubSpan(ctx, host)
+	defer span.Finish()
+
+	span.AddAnnotation("http_client", c.settings.Name)
+	span.AddMetadata("method", method)
+	span.AddMetadata("url", url)
+
+	tracing.InjectHeaders(ctx, req.Header)
+
+	if c.breaker != nil {
+		if err := c.breaker.allow(host); err != nil {
+			return nil, 0, fmt.Errorf("can not perform %s request to %s: %w", method, url, err)
+		}
+	}
+
+	if c.budget != nil {
+		c.budget.request()
+	}
+
+	c.writeMetric(metricRequest, method, host, mon.UnitCount, 1.0)
 	start := c.clock.Now()
-	resp, err := req.Execute(method, url)
+
+	var resp *resty.Response
+
+	if stream {
+		req.SetDoNotParseResponse(true)
+	}
+
+	// a body read from a reader can't be sent a second time
+	if stream || request.bodyReader != nil {
+		resp, err = c.executeOnce(ctx, method, url, host, req)
+	} else {
+		resp, err = c.executeWithRetries(ctx, method, url, host, req, request.outputFile == nil)
+	}
+
+	if c.breaker != nil {
+		statusCode := 0
+
+		if resp != nil {
+			statusCode = resp.StatusCode()
+		}
+
+		c.breaker.done(host, statusCode, err, errors.Is(err, context.Canceled))
+	}
+
+	if err != nil {
+		span.AddError(err)
+	} else {
+		span.AddAnnotation("status_code", strconv.Itoa(resp.StatusCode()))
+	}
 
 	if errors.Is(err, context.Canceled) {
-		return nil, err
+		return nil, 0, err
 	}
 
 	totalDuration := c.clock.Now().Sub(start)
@@ -212,31 +361,31 @@ func (c *client) do(ctx context.Context, method string, request *Request) (*Resp
 	// Otherwise a user might spam our error logs by just canceling a lot of requests
 	// (or many users spam us because sometimes they cancel requests)
 	if err != nil {
-		c.writeMetric(metricError, method, mon.UnitCount, 1.0)
-		return nil, fmt.Errorf("failed to perform %s request to %s: %w", request.restyRequest.Method, request.url.String(), err)
+		c.writeMetric(metricError, method, host, mon.UnitCount, 1.0)
+		return nil, 0, fmt.Errorf("failed to perform %s request to %s: %w", request.restyRequest.Method, request.url.String(), err)
 	}
 
 	metricName := fmt.Sprintf("%s%dXX", metricResponseCode, resp.StatusCode()/100)
-	c.writeMetric(metricName, method, mon.UnitCount, 1.0)
-
-	response := buildResponse(resp, &totalDuration)
+	c.writeMetric(metricName, method, host, mon.UnitCount, 1.0)
 
 	// Only log the duration if we did not get an error.
 	// If we get an error, we might not actually have send anything,
 	// so the duration will be very low. If we get back an error (e.g., status 500),
 	// we log the duration as this is just a valid http response.
 	requestDurationMs := float64(resp.Time() / time.Millisecond)
-	c.writeMetric(metricRequestDuration, method, mon.UnitMillisecondsAverage, requestDurationMs)
+	c.writeMetric(metricRequestDuration, method, host, mon.UnitMillisecondsAverage, requestDurationMs)
 
-	return response, nil
+	return resp, totalDuration, nil
 }
 
-func (c *client) writeMetric(metricName string, method string, unit string, value float64) {
+func (c *client) writeMetric(metricName string, method string, host string, unit string, value float64) {
 	c.mo.WriteOne(&mon.MetricDatum{
 		Priority:   mon.PriorityHigh,
 		Timestamp:  time.Now(),
 		MetricName: metricName,
 		Dimensions: mon.MetricDimensions{
+			"Client": c.settings.Name,
+			"Host":   host,
 			"Method": method,
 		},
 		Unit:  unit,
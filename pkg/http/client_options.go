@@ -0,0 +1,39 @@
+package http
+
+import (
+	"github.com/applike/gosoline/pkg/tracing"
+)
+
+type clientOptions struct {
+	tracer   tracing.Tracer
+	settings *Settings
+}
+
+type ClientOption func(options *clientOptions)
+
+// WithTracer traces every request of the client with the given tracer. Without it, requests are not traced.
+func WithTracer(tracer tracing.Tracer) ClientOption {
+	return func(options *clientOptions) {
+		options.tracer = tracer
+	}
+}
+
+// WithSettings configures the name, base url, default headers, retries, hedging and circuit breaker of the client.
+func WithSettings(settings *Settings) ClientOption {
+	return func(options *clientOptions) {
+		options.settings = settings
+	}
+}
+
+func buildClientOptions(options []ClientOption) *clientOptions {
+	opts := &clientOptions{
+		tracer:   tracing.NewNoopTracer(),
+		settings: &Settings{},
+	}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	return opts
+}
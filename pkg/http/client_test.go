@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	cfgMocks "github.com/applike/gosoline/pkg/cfg/mocks"
+	"github.com/applike/gosoline/pkg/clock"
 	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/mon"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	tracingMocks "github.com/applike/gosoline/pkg/tracing/mocks"
+	"github.com/go-resty/resty/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	netHttp "net/http"
@@ -50,6 +54,7 @@ func runTestServer(t *testing.T, method string, status int, delay time.Duration,
 func getConfig(retries int, timeout int) *cfgMocks.Config {
 	config := new(cfgMocks.Config)
 	config.On("UnmarshalKey", mock.AnythingOfType("string"), mock.AnythingOfType("*http.Settings"))
+	config.On("UnmarshalKey", "tracing", mock.AnythingOfType("*tracing.TracerSettings")).Maybe()
 	config.On("GetInt", "http_client_retry_count").Return(retries)
 	config.On("GetDuration", "http_client_request_timeout").Return(time.Duration(timeout) * time.Second)
 
@@ -90,6 +95,27 @@ func TestClient_Get(t *testing.T) {
 	config.AssertExpectations(t)
 }
 
+func TestClient_ForwardsRequestId(t *testing.T) {
+	config := getConfig(1, 1)
+	logger := monMocks.NewLoggerMockedAll()
+
+	requestId := ""
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		requestId = req.Header.Get(mon.HeaderRequestId)
+	}))
+	defer testServer.Close()
+
+	client := http.NewHttpClient(config, logger)
+	request := client.NewRequest().
+		WithUrl(testServer.URL)
+
+	ctx := mon.WithRequestId(context.Background(), "abc-123")
+	_, err := client.Get(ctx, request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc-123", requestId)
+}
+
 func TestClient_GetTimeout(t *testing.T) {
 	config := getConfig(0, 1)
 	logger := monMocks.NewLoggerMockedAll()
@@ -148,3 +174,42 @@ func TestClient_Post(t *testing.T) {
 
 	config.AssertExpectations(t)
 }
+
+func TestClient_MetricsAndTracing(t *testing.T) {
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		res.WriteHeader(netHttp.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	host := testServer.Listener.Addr().String()
+	logger := monMocks.NewLoggerMockedAll()
+
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("WriteOne", mock.MatchedBy(func(datum *mon.MetricDatum) bool {
+		return datum.Dimensions["Client"] == "payments" && datum.Dimensions["Host"] == host && datum.Dimensions["Method"] == "GET"
+	}))
+
+	span := new(tracingMocks.Span)
+	span.On("AddAnnotation", "http_client", "payments").Once()
+	span.On("AddMetadata", "method", "GET").Once()
+	span.On("AddMetadata", "url", testServer.URL).Once()
+	span.On("AddAnnotation", "status_code", "404").Once()
+	span.On("Finish").Once()
+
+	tracer := new(tracingMocks.Tracer)
+	tracer.On("StartSubSpan", mock.Anything, host).Return(context.Background(), span).Once()
+
+	client := http.NewHttpClientWithInterfaces(logger, clock.NewRealClock(), metricWriter, resty.New(), http.WithTracer(tracer), http.WithSettings(&http.Settings{
+		Name: "payments",
+	}))
+
+	response, err := client.Get(context.Background(), client.NewRequest().WithUrl(testServer.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, netHttp.StatusNotFound, response.StatusCode)
+
+	metricWriter.AssertCalled(t, "WriteOne", mock.MatchedBy(func(datum *mon.MetricDatum) bool {
+		return datum.MetricName == "HttpClientResponseCode4XX"
+	}))
+	span.AssertExpectations(t)
+	tracer.AssertExpectations(t)
+}
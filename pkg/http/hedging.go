@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/go-resty/resty/v2"
+	"math"
+	"net/http"
+	netUrl "net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const metricHedgedRequest = "HttpClientHedgedRequest"
+
+type HedgingSettings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// a second request is sent if the first one didn't finish within this percentile of the recent durations
+	Percentile float64 `cfg:"percentile" default:"95"`
+	// number of recent request durations per host the percentile is calculated from
+	Samples int `cfg:"samples" default:"100"`
+	// hedging starts as soon as this many durations have been recorded for a host
+	MinSamples int `cfg:"min_samples" default:"20"`
+}
+
+type hedgingSamples struct {
+	durations []time.Duration
+	next      int
+}
+
+// hedging tracks the recent request durations per host to decide after which delay a hedged request is sent.
+type hedging struct {
+	settings HedgingSettings
+
+	lck   sync.Mutex
+	hosts map[string]*hedgingSamples
+}
+
+type hedgingResult struct {
+	resp *resty.Response
+	err  error
+}
+
+func newHedging(settings HedgingSettings) *hedging {
+	return &hedging{
+		settings: settings,
+		hosts:    make(map[string]*hedgingSamples),
+	}
+}
+
+func (h *hedging) observe(host string, duration time.Duration) {
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	samples, ok := h.hosts[host]
+
+	if !ok {
+		samples = &hedgingSamples{
+			durations: make([]time.Duration, 0, h.settings.Samples),
+		}
+		h.hosts[host] = samples
+	}
+
+	if len(samples.durations) < h.settings.Samples {
+		samples.durations = append(samples.durations, duration)
+		return
+	}
+
+	samples.durations[samples.next] = duration
+	samples.next = (samples.next + 1) % h.settings.Samples
+}
+
+func (h *hedging) delay(host string) (time.Duration, bool) {
+	h.lck.Lock()
+	defer h.lck.Unlock()
+
+	samples, ok := h.hosts[host]
+
+	if !ok || len(samples.durations) == 0 || len(samples.durations) < h.settings.MinSamples {
+		return 0, false
+	}
+
+	sorted := make([]time.Duration, len(samples.durations))
+	copy(sorted, samples.durations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	idx := int(math.Ceil(h.settings.Percentile/100*float64(len(sorted)))) - 1
+
+	if idx < 0 {
+		idx = 0
+	}
+
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+
+	return sorted[idx], true
+}
+
+// executeWithHedging sends a second copy of an idempotent request if the first one takes longer than the configured
+// percentile of the recent durations for the host. The first successful response wins, the other request is canceled.
+// Requests with a body or an output file are not hedged, as both copies would share them.
+func (c *client) executeWithHedging(ctx context.Context, method string, url string, host string, req *resty.Request, hedgeable bool) (*resty.Response, error) {
+	if c.hedging == nil || !hedgeable || !isIdempotent(method) || req.Body != nil {
+		return c.executeOnce(ctx, method, url, host, req)
+	}
+
+	delay, ok := c.hedging.delay(host)
+
+	if !ok {
+		return c.executeOnce(ctx, method, url, host, req)
+	}
+
+	// copy the request before executing it as resty modifies the request while executing it
+	hedged := c.cloneForHedging(req)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgingResult, 2)
+	launch := func(r *resty.Request) {
+		go func() {
+			resp, err := c.executeOnce(ctx, method, url, host, r)
+			results <- hedgingResult{resp: resp, err: err}
+		}()
+	}
+
+	launch(req)
+
+	select {
+	case res := <-results:
+		return res.resp, res.err
+	case <-c.clock.After(delay):
+	}
+
+	c.writeMetric(metricHedgedRequest, method, host, mon.UnitCount, 1.0)
+	launch(hedged)
+
+	res := <-results
+
+	if res.err != nil {
+		if second := <-results; second.err == nil {
+			return second.resp, second.err
+		}
+	}
+
+	return res.resp, res.err
+}
+
+// cloneForHedging creates a new request of the client with copies of everything a request without a body is built
+// from, so the two requests don't share anything resty modifies while executing them
+func (c *client) cloneForHedging(req *resty.Request) *resty.Request {
+	hedged := c.http.NewRequest()
+	hedged.Header = req.Header.Clone()
+	hedged.QueryParam = cloneValues(req.QueryParam)
+	hedged.FormData = cloneValues(req.FormData)
+	hedged.Token = req.Token
+	hedged.UserInfo = req.UserInfo
+	hedged.Cookies = append(make([]*http.Cookie, 0, len(req.Cookies)), req.Cookies...)
+
+	return hedged
+}
+
+func cloneValues(values netUrl.Values) netUrl.Values {
+	cloned := make(netUrl.Values, len(values))
+
+	for key, value := range values {
+		cloned[key] = append(make([]string, 0, len(value)), value...)
+	}
+
+	return cloned
+}
+
+func (c *client) executeOnce(ctx context.Context, method string, url string, host string, req *resty.Request) (*resty.Response, error) {
+	resp, err := req.SetContext(ctx).Execute(method, url)
+
+	if c.hedging != nil && err == nil {
+		c.hedging.observe(host, resp.Time())
+	}
+
+	return resp, err
+}
@@ -215,3 +215,26 @@ func (_m *Client) SetTimeout(timeout time.Duration) {
 func (_m *Client) SetUserAgent(ua string) {
 	_m.Called(ua)
 }
+
+// Stream provides a mock function with given fields: ctx, method, request
+func (_m *Client) Stream(ctx context.Context, method string, request *http.Request) (*http.StreamResponse, error) {
+	ret := _m.Called(ctx, method, request)
+
+	var r0 *http.StreamResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, *http.Request) *http.StreamResponse); ok {
+		r0 = rf(ctx, method, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*http.StreamResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *http.Request) error); ok {
+		r1 = rf(ctx, method, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/tracing"
+	netUrl "net/url"
+	"sync"
+)
+
+var httpClients = struct {
+	sync.Mutex
+	instances map[string]Client
+}{
+	instances: map[string]Client{},
+}
+
+// ProvideHttpClient returns the client configured at http_clients.<name>, creating it on first use. All clients
+// share the same instance per env and name.
+func ProvideHttpClient(config cfg.Config, logger mon.Logger, name string) (Client, error) {
+	httpClients.Lock()
+	defer httpClients.Unlock()
+
+	key := fmt.Sprintf("%s/%s", config.GetString("env", ""), name)
+
+	if client, ok := httpClients.instances[key]; ok {
+		return client, nil
+	}
+
+	client, err := NewNamedHttpClient(config, logger, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	httpClients.instances[key] = client
+
+	return client, nil
+}
+
+// ResetHttpClients drops the provided clients, e.g. if tests change the base urls of the clients between two runs
+// of the application
+func ResetHttpClients() {
+	httpClients.Lock()
+	defer httpClients.Unlock()
+
+	httpClients.instances = map[string]Client{}
+}
+
+// NewNamedHttpClient creates a client with the settings at http_clients.<name>. Settings missing there are taken
+// from http_clients.default and the global http_client settings.
+func NewNamedHttpClient(config cfg.Config, logger mon.Logger, name string) (Client, error) {
+	settings := ReadNamedSettings(config, name)
+
+	if settings.BaseUrl != "" {
+		if _, err := netUrl.Parse(settings.BaseUrl); err != nil {
+			return nil, fmt.Errorf("can not parse base url of http client %s: %w", name, err)
+		}
+	}
+
+	tracer, err := tracing.ProvideTracer(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("can not create tracer: %w", err)
+	}
+
+	logger = logger.WithFields(mon.Fields{
+		"http_client": name,
+	})
+
+	return newHttpClientFromSettings(logger, clock.NewRealClock(), mon.NewMetricDaemonWriter(), tracer, settings), nil
+}
+
+func ReadNamedSettings(config cfg.Config, name string) *Settings {
+	defaults := []cfg.UnmarshalDefaults{
+		cfg.UnmarshalWithDefaultsFromKey("http_client", "."),
+	}
+
+	// the legacy settings for the retry count and timeout take precedence over the ones at http_client
+	if config.IsSet("http_client_retry_count") {
+		defaults = append(defaults, cfg.UnmarshalWithDefaultForKey("retry_count", config.GetInt("http_client_retry_count")))
+	}
+
+	if config.IsSet("http_client_request_timeout") {
+		defaults = append(defaults, cfg.UnmarshalWithDefaultForKey("request_timeout", config.GetDuration("http_client_request_timeout")))
+	}
+
+	defaults = append(defaults, cfg.UnmarshalWithDefaultsFromKey("http_clients.default", "."))
+
+	key := fmt.Sprintf("http_clients.%s", name)
+	settings := &Settings{}
+	config.UnmarshalKey(key, settings, defaults...)
+	settings.Name = name
+
+	return settings
+}
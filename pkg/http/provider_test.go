@@ -0,0 +1,82 @@
+package http_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/http"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	netHttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadNamedSettings(t *testing.T) {
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"http_client_retry_count":     3,
+		"http_client_request_timeout": "5s",
+		"http_client": map[string]interface{}{
+			"retry_wait_time": "200ms",
+		},
+		"http_clients": map[string]interface{}{
+			"default": map[string]interface{}{
+				"headers": map[string]interface{}{
+					"X-Source": "gosoline",
+				},
+			},
+			"payments": map[string]interface{}{
+				"base_url":        "https://payments.example.com/api/",
+				"request_timeout": "1s",
+			},
+		},
+	}))
+	assert.NoError(t, err)
+
+	settings := http.ReadNamedSettings(config, "payments")
+
+	assert.Equal(t, "https://payments.example.com/api/", settings.BaseUrl)
+	assert.Equal(t, time.Second, settings.Timeout)
+	assert.Equal(t, 3, settings.RetryCount)
+	assert.Equal(t, 200*time.Millisecond, settings.RetryWaitTime)
+	assert.Equal(t, map[string]string{"X-Source": "gosoline"}, settings.Headers)
+}
+
+func TestProvideHttpClient(t *testing.T) {
+	var path, source string
+
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		path = req.URL.Path
+		source = req.Header.Get("X-Source")
+	}))
+	defer testServer.Close()
+
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigMap(map[string]interface{}{
+		"http_clients": map[string]interface{}{
+			"test": map[string]interface{}{
+				"base_url": testServer.URL + "/api/",
+				"headers": map[string]interface{}{
+					"X-Source": "gosoline",
+				},
+			},
+		},
+	}))
+	assert.NoError(t, err)
+
+	logger := monMocks.NewLoggerMockedAll()
+
+	client, err := http.ProvideHttpClient(config, logger, "test")
+	assert.NoError(t, err)
+
+	same, err := http.ProvideHttpClient(config, logger, "test")
+	assert.NoError(t, err)
+	assert.True(t, client == same, "the client should only be created once")
+
+	response, err := client.Get(context.Background(), client.NewRequest().WithUrl("items/1"))
+	assert.NoError(t, err)
+	assert.Equal(t, netHttp.StatusOK, response.StatusCode)
+	assert.Equal(t, "/api/items/1", path)
+	assert.Equal(t, "gosoline", source)
+}
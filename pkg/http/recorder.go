@@ -0,0 +1,340 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// RecorderModeRecord sends every request and stores the interactions in the cassette
+	RecorderModeRecord = "record"
+	// RecorderModeReplay answers every request from the cassette and fails for unknown requests
+	RecorderModeReplay = "replay"
+	// RecorderModeReplayOrRecord answers known requests from the cassette and records all others
+	RecorderModeReplayOrRecord = "replay_or_record"
+
+	recorderRedacted = "<redacted>"
+)
+
+type RecorderSettings struct {
+	Enabled bool   `cfg:"enabled" default:"false"`
+	Mode    string `cfg:"mode" default:"replay" validate:"oneof=record replay replay_or_record"`
+	// path of the cassette file the interactions are stored in
+	Cassette string `cfg:"cassette"`
+	// headers which have to be equal for a recorded interaction to match a request
+	MatchHeaders []string `cfg:"match_headers"`
+	MatchBody    bool     `cfg:"match_body" default:"true"`
+	// headers which are not written to the cassette
+	RedactHeaders []string `cfg:"redact_headers" default:"Authorization,Cookie,Set-Cookie"`
+	// json fields (at any depth) of request and response bodies which are not written to the cassette
+	RedactBodyFields []string `cfg:"redact_body_fields"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Url    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// RequestMatcher decides if a recorded request matches the request currently performed.
+type RequestMatcher func(req *http.Request, body []byte, recorded *RecordedRequest) bool
+
+// RecordingTransport records the interactions of a client with third party services to a cassette file and replays
+// them in later runs, so tests don't depend on the availability and state of these services.
+type RecordingTransport struct {
+	base     http.RoundTripper
+	settings RecorderSettings
+	matchers []RequestMatcher
+
+	lck      sync.Mutex
+	cassette *Cassette
+	replayed map[*Interaction]bool
+}
+
+func NewRecordingTransport(base http.RoundTripper, settings RecorderSettings, matchers ...RequestMatcher) *RecordingTransport {
+	transport := &RecordingTransport{
+		base:     base,
+		settings: settings,
+		replayed: make(map[*Interaction]bool),
+	}
+
+	transport.matchers = append([]RequestMatcher{transport.matchDefault}, matchers...)
+
+	return transport
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.settings.Mode != RecorderModeRecord {
+		if interaction := t.find(req, body); interaction != nil {
+			t.replayed[interaction] = true
+
+			return buildRecordedResponse(req, interaction), nil
+		}
+	}
+
+	if t.settings.Mode == RecorderModeReplay {
+		return nil, fmt.Errorf("no recorded interaction for %s %s in cassette %s", req.Method, req.URL.String(), t.settings.Cassette)
+	}
+
+	return t.record(req, body)
+}
+
+func (t *RecordingTransport) load() error {
+	if t.cassette != nil {
+		return nil
+	}
+
+	t.cassette = &Cassette{
+		Interactions: make([]*Interaction, 0),
+	}
+
+	if t.settings.Mode == RecorderModeRecord {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(t.settings.Cassette)
+
+	if os.IsNotExist(err) && t.settings.Mode == RecorderModeReplayOrRecord {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("can not read cassette %s: %w", t.settings.Cassette, err)
+	}
+
+	if err = json.Unmarshal(data, t.cassette); err != nil {
+		return fmt.Errorf("can not decode cassette %s: %w", t.settings.Cassette, err)
+	}
+
+	return nil
+}
+
+func (t *RecordingTransport) find(req *http.Request, body []byte) *Interaction {
+	// interactions are replayed in the recorded order if several of them match the same request
+	var fallback *Interaction
+
+	for _, interaction := range t.cassette.Interactions {
+		if !t.matches(req, body, &interaction.Request) {
+			continue
+		}
+
+		if !t.replayed[interaction] {
+			return interaction
+		}
+
+		fallback = interaction
+	}
+
+	return fallback
+}
+
+func (t *RecordingTransport) matches(req *http.Request, body []byte, recorded *RecordedRequest) bool {
+	for _, matcher := range t.matchers {
+		if !matcher(req, body, recorded) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *RecordingTransport) matchDefault(req *http.Request, body []byte, recorded *RecordedRequest) bool {
+	if req.Method != recorded.Method || req.URL.String() != recorded.Url {
+		return false
+	}
+
+	for _, header := range t.settings.MatchHeaders {
+		if req.Header.Get(header) != recorded.Header.Get(header) {
+			return false
+		}
+	}
+
+	if t.settings.MatchBody && string(redactBody(body, t.settings.RedactBodyFields)) != recorded.Body {
+		return false
+	}
+
+	return true
+}
+
+func (t *RecordingTransport) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can not read response body to record it: %w", err)
+	}
+
+	if err = resp.Body.Close(); err != nil {
+		return nil, fmt.Errorf("can not close response body: %w", err)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Url:    req.URL.String(),
+			Header: redactHeader(req.Header, t.settings.RedactHeaders),
+			Body:   string(redactBody(body, t.settings.RedactBodyFields)),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header, t.settings.RedactHeaders),
+			Body:       string(redactBody(respBody, t.settings.RedactBodyFields)),
+		},
+	}
+
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	t.replayed[interaction] = true
+
+	if err = t.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (t *RecordingTransport) save() error {
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can not encode cassette: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(t.settings.Cassette), 0755); err != nil {
+		return fmt.Errorf("can not create directory of cassette %s: %w", t.settings.Cassette, err)
+	}
+
+	if err = ioutil.WriteFile(t.settings.Cassette, data, 0644); err != nil {
+		return fmt.Errorf("can not write cassette %s: %w", t.settings.Cassette, err)
+	}
+
+	return nil
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can not read request body: %w", err)
+	}
+
+	if err = req.Body.Close(); err != nil {
+		return nil, fmt.Errorf("can not close request body: %w", err)
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func buildRecordedResponse(req *http.Request, interaction *Interaction) *http.Response {
+	header := interaction.Response.Header
+
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}
+}
+
+func redactHeader(header http.Header, redact []string) http.Header {
+	redacted := header.Clone()
+
+	for _, key := range redact {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, recorderRedacted)
+		}
+	}
+
+	return redacted
+}
+
+func redactBody(body []byte, fields []string) []byte {
+	if len(fields) == 0 || !json.Valid(body) {
+		return body
+	}
+
+	var data interface{}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body
+	}
+
+	redacted, err := json.Marshal(redactJsonFields(data, fields))
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+func redactJsonFields(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = redactJsonFields(elem, fields)
+
+			for _, field := range fields {
+				if key == field {
+					v[key] = recorderRedacted
+				}
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJsonFields(v[i], fields)
+		}
+	}
+
+	return value
+}
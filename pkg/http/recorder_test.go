@@ -0,0 +1,89 @@
+package http_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/http"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	netHttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newRecordingClient(settings http.RecorderSettings) http.Client {
+	logger := monMocks.NewLoggerMockedAll()
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("WriteOne", mock.Anything)
+
+	httpClient := resty.New()
+	httpClient.SetTransport(http.NewRecordingTransport(netHttp.DefaultTransport, settings))
+
+	return http.NewHttpClientWithInterfaces(logger, clock.NewRealClock(), metricWriter, httpClient)
+}
+
+func TestRecordingTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	calls := 0
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		calls++
+		res.Header().Set("Content-Type", "application/json")
+		_, _ = res.Write([]byte(`{"id":1,"token":"secret"}`))
+	}))
+
+	settings := http.RecorderSettings{
+		Enabled:          true,
+		Mode:             http.RecorderModeRecord,
+		Cassette:         filepath.Join(dir, "cassette.json"),
+		MatchBody:        true,
+		RedactHeaders:    []string{"Authorization"},
+		RedactBodyFields: []string{"token"},
+	}
+
+	client := newRecordingClient(settings)
+	request := client.NewRequest().
+		WithUrl(testServer.URL + "/items").
+		WithAuthToken("my-token").
+		WithBody(`{"name":"foo"}`)
+
+	response, err := client.Post(context.Background(), request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"token":"secret"}`, string(response.Body))
+	assert.Equal(t, 1, calls)
+
+	cassette, err := ioutil.ReadFile(settings.Cassette)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(cassette), "my-token")
+	assert.NotContains(t, string(cassette), "secret")
+
+	url := testServer.URL
+	testServer.Close()
+
+	settings.Mode = http.RecorderModeReplay
+	client = newRecordingClient(settings)
+
+	request = client.NewRequest().
+		WithUrl(url + "/items").
+		WithBody(`{"name":"foo"}`)
+
+	response, err = client.Post(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, netHttp.StatusOK, response.StatusCode)
+	assert.JSONEq(t, `{"id":1,"token":"<redacted>"}`, string(response.Body))
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+
+	request = client.NewRequest().
+		WithUrl(url + "/items").
+		WithBody(`{"name":"bar"}`)
+
+	_, err = client.Post(context.Background(), request)
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/google/go-querystring/query"
 	"github.com/hashicorp/go-multierror"
@@ -19,8 +20,14 @@ const ContentTypeApplicationXml = "application/xml"
 const ContentTypeApplicationFormUrlencoded = "application/x-www-form-urlencoded"
 
 type Request struct {
-	errs         error
-	outputFile   *string
+	errs       error
+	outputFile *string
+	bodyReader io.Reader
+	bodySize   int64
+
+	uploadProgress   ProgressCallback
+	downloadProgress ProgressCallback
+
 	queryParams  url.Values
 	restyRequest *resty.Request
 	url          *url.URL
@@ -149,6 +156,33 @@ func (r *Request) WithBody(body interface{}) *Request {
 	return r
 }
 
+// WithBodyReader streams the body from the reader instead of buffering it. Pass -1 as size if it is unknown.
+func (r *Request) WithBodyReader(body io.Reader, size int64) *Request {
+	r.bodyReader = body
+	r.bodySize = size
+	r.restyRequest.SetBody(body)
+
+	return r
+}
+
+// WithUploadProgress reports the progress of reading a body set by WithBodyReader.
+func (r *Request) WithUploadProgress(callback ProgressCallback) *Request {
+	if r.bodyReader == nil {
+		r.errs = multierror.Append(r.errs, fmt.Errorf("an upload progress requires a body set by WithBodyReader"))
+	}
+
+	r.uploadProgress = callback
+
+	return r
+}
+
+// WithDownloadProgress reports the progress of reading the body of a StreamResponse.
+func (r *Request) WithDownloadProgress(callback ProgressCallback) *Request {
+	r.downloadProgress = callback
+
+	return r
+}
+
 func (r *Request) WithMultipartFile(param, fileName string, reader io.Reader) *Request {
 	r.restyRequest.SetFileReader(param, fileName, reader)
 
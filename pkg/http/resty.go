@@ -7,7 +7,6 @@ import (
 )
 
 type restyClient interface {
-	AddRetryCondition(conditionFunc resty.RetryConditionFunc) *resty.Client
 	NewRequest() *resty.Request
 	SetCookie(cookie *http.Cookie) *resty.Client
 	SetCookies(cookies []*http.Cookie) *resty.Client
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/go-resty/resty/v2"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const metricRetry = "HttpClientRetry"
+
+type RetrySettings struct {
+	// only retry requests with an idempotent method (GET, HEAD, OPTIONS, PUT and DELETE)
+	IdempotentOnly bool `cfg:"idempotent_only" default:"false"`
+	// share of retries compared to requests allowed within the budget window, 0 disables the budget
+	BudgetRatio float64 `cfg:"budget_ratio" default:"0"`
+	// retries allowed within the budget window regardless of the ratio
+	BudgetMinRetries int           `cfg:"budget_min_retries" default:"10"`
+	BudgetWindow     time.Duration `cfg:"budget_window" default:"10s"`
+}
+
+// retryBudget limits the retries of a client to a share of its requests within a window. This prevents retries
+// from multiplying the load on a downstream service which is failing anyway.
+type retryBudget struct {
+	clock    clock.Clock
+	settings RetrySettings
+
+	lck         sync.Mutex
+	windowStart time.Time
+	requests    int
+	retries     int
+}
+
+func newRetryBudget(clock clock.Clock, settings RetrySettings) *retryBudget {
+	return &retryBudget{
+		clock:       clock,
+		settings:    settings,
+		windowStart: clock.Now(),
+	}
+}
+
+func (b *retryBudget) request() {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	b.advance()
+	b.requests++
+}
+
+func (b *retryBudget) allowRetry() bool {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	b.advance()
+
+	if b.retries >= b.settings.BudgetMinRetries && float64(b.retries) >= b.settings.BudgetRatio*float64(b.requests) {
+		return false
+	}
+
+	b.retries++
+
+	return true
+}
+
+func (b *retryBudget) advance() {
+	if b.clock.Now().Sub(b.windowStart) < b.settings.BudgetWindow {
+		return
+	}
+
+	b.windowStart = b.clock.Now()
+	b.requests = 0
+	b.retries = 0
+}
+
+func (c *client) executeWithRetries(ctx context.Context, method string, url string, host string, req *resty.Request, hedgeable bool) (*resty.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.executeWithHedging(ctx, method, url, host, req, hedgeable)
+
+		if attempt >= c.settings.RetryCount || !c.shouldRetry(ctx, method, resp, err) {
+			return resp, err
+		}
+
+		c.writeMetric(metricRetry, method, host, mon.UnitCount, 1.0)
+
+		select {
+		case <-ctx.Done():
+			return resp, err
+		case <-c.clock.After(c.retryWaitTime(attempt)):
+		}
+	}
+}
+
+func (c *client) shouldRetry(ctx context.Context, method string, resp *resty.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if c.settings.Retry.IdempotentOnly && !isIdempotent(method) {
+		return false
+	}
+
+	needsRetry := err != nil
+
+	for _, condition := range c.retryConditions {
+		if needsRetry {
+			break
+		}
+
+		needsRetry = condition(buildResponse(resp, nil), err)
+	}
+
+	if !needsRetry {
+		return false
+	}
+
+	if c.budget != nil && !c.budget.allowRetry() {
+		c.logger.WithContext(ctx).Warnf("retry budget exhausted, not retrying %s request", method)
+		return false
+	}
+
+	return true
+}
+
+// retryWaitTime doubles the wait time with every attempt up to the max wait time and adds jitter
+func (c *client) retryWaitTime(attempt int) time.Duration {
+	wait := c.settings.RetryWaitTime << uint(attempt)
+
+	if wait <= 0 || wait > c.settings.RetryMaxWaitTime {
+		wait = c.settings.RetryMaxWaitTime
+	}
+
+	if wait <= 1 {
+		return wait
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
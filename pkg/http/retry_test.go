@@ -0,0 +1,171 @@
+package http_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/http"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	netHttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(settings *http.Settings) http.Client {
+	logger := monMocks.NewLoggerMockedAll()
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("WriteOne", mock.Anything)
+
+	client := http.NewHttpClientWithInterfaces(logger, clock.NewRealClock(), metricWriter, resty.New(), http.WithSettings(settings))
+	client.AddRetryCondition(func(response *http.Response, err error) bool {
+		return response != nil && response.StatusCode >= netHttp.StatusInternalServerError
+	})
+
+	return client
+}
+
+func TestClient_RetryIdempotentOnly(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		atomic.AddInt32(&calls, 1)
+		res.WriteHeader(netHttp.StatusBadGateway)
+	}))
+	defer testServer.Close()
+
+	client := newRetryTestClient(&http.Settings{
+		RetryCount: 2,
+		Retry: http.RetrySettings{
+			IdempotentOnly: true,
+		},
+	})
+
+	response, err := client.Post(context.Background(), client.NewRequest().WithUrl(testServer.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, netHttp.StatusBadGateway, response.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "a post request should not be retried")
+
+	response, err = client.Get(context.Background(), client.NewRequest().WithUrl(testServer.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, netHttp.StatusBadGateway, response.StatusCode)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls), "a get request should be retried twice")
+}
+
+func TestClient_RetryBudget(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		atomic.AddInt32(&calls, 1)
+		res.WriteHeader(netHttp.StatusServiceUnavailable)
+	}))
+	defer testServer.Close()
+
+	client := newRetryTestClient(&http.Settings{
+		RetryCount: 3,
+		Retry: http.RetrySettings{
+			BudgetRatio:      0.1,
+			BudgetMinRetries: 2,
+			BudgetWindow:     time.Minute,
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Get(context.Background(), client.NewRequest().WithUrl(testServer.URL))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls), "only 2 retries should be allowed by the budget")
+}
+
+func TestClient_Hedging(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			time.Sleep(time.Second)
+		}
+	}))
+	defer testServer.Close()
+
+	client := newRetryTestClient(&http.Settings{
+		Hedging: http.HedgingSettings{
+			Enabled:    true,
+			Percentile: 90,
+			Samples:    10,
+			MinSamples: 2,
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(context.Background(), client.NewRequest().WithUrl(testServer.URL))
+		assert.NoError(t, err)
+	}
+
+	start := time.Now()
+	response, err := client.Get(context.Background(), client.NewRequest().WithUrl(testServer.URL))
+
+	assert.NoError(t, err)
+	assert.Equal(t, netHttp.StatusOK, response.StatusCode)
+	assert.True(t, time.Since(start) < 500*time.Millisecond, "the hedged request should have answered first")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestClient_HedgingCopiesHeaders(t *testing.T) {
+	var calls int32
+	var missingHeaders int32
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		if req.Header.Get("X-Test") != "value" || req.URL.Query().Get("foo") != "bar" {
+			atomic.AddInt32(&missingHeaders, 1)
+		}
+
+		if atomic.AddInt32(&calls, 1) == 3 {
+			time.Sleep(time.Second)
+		}
+	}))
+	defer testServer.Close()
+
+	client := newRetryTestClient(&http.Settings{
+		Hedging: http.HedgingSettings{
+			Enabled:    true,
+			Percentile: 90,
+			Samples:    10,
+			MinSamples: 2,
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		request := client.NewRequest().WithUrl(testServer.URL).WithHeader("X-Test", "value").WithQueryParam("foo", "bar")
+		_, err := client.Get(context.Background(), request)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&missingHeaders), "the hedged request should have the same headers and query")
+}
+
+func TestClient_HedgingWithoutBody(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			time.Sleep(time.Millisecond * 200)
+		}
+	}))
+	defer testServer.Close()
+
+	client := newRetryTestClient(&http.Settings{
+		Hedging: http.HedgingSettings{
+			Enabled:    true,
+			Percentile: 90,
+			Samples:    10,
+			MinSamples: 2,
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Put(context.Background(), client.NewRequest().WithUrl(testServer.URL).WithBody("body"))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "a request with a body should not be hedged")
+}
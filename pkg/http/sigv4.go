@@ -0,0 +1,61 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
+	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/go-resty/resty/v2"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+type SigV4Settings struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+	// the signing name of the service, e.g. execute-api for API Gateway or es for OpenSearch
+	Service string `cfg:"service" default:"execute-api"`
+	Region  string `cfg:"region" default:"eu-central-1"`
+}
+
+// newSigV4Signer uses the credentials of the default provider chain (environment, shared config, instance or task role)
+func newSigV4Signer(settings SigV4Settings) *v4.Signer {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials:                   gosoAws.GetDefaultCredentials(),
+		CredentialsChainVerboseErrors: aws.Bool(true),
+		Region:                        aws.String(settings.Region),
+	}))
+
+	return v4.NewSigner(sess.Config.Credentials)
+}
+
+// sigV4PreRequestHook signs every request right before it is sent, so retried and hedged requests get a fresh signature
+func sigV4PreRequestHook(signer *v4.Signer, clock clock.Clock, settings SigV4Settings) resty.PreRequestHook {
+	return func(_ *resty.Client, req *http.Request) error {
+		var body io.ReadSeeker
+
+		if req.Body != nil {
+			data, err := ioutil.ReadAll(req.Body)
+
+			if err != nil {
+				return fmt.Errorf("can not read request body to sign it: %w", err)
+			}
+
+			if err = req.Body.Close(); err != nil {
+				return fmt.Errorf("can not close request body: %w", err)
+			}
+
+			// the signer attaches the body to the request again
+			body = bytes.NewReader(data)
+		}
+
+		if _, err := signer.Sign(req, body, settings.Service, settings.Region, clock.Now()); err != nil {
+			return fmt.Errorf("can not sign request: %w", err)
+		}
+
+		return nil
+	}
+}
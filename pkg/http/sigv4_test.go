@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/clock"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_SigV4(t *testing.T) {
+	var authorization, body string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		data, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		authorization = req.Header.Get("Authorization")
+		body = string(data)
+	}))
+	defer testServer.Close()
+
+	logger := monMocks.NewLoggerMockedAll()
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("WriteOne", mock.Anything)
+	fakeClock := clock.NewFakeClockAt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	settings := SigV4Settings{
+		Enabled: true,
+		Service: "execute-api",
+		Region:  "eu-central-1",
+	}
+	signer := v4.NewSigner(credentials.NewStaticCredentials("id", "secret", ""))
+
+	httpClient := resty.New()
+	httpClient.SetPreRequestHook(sigV4PreRequestHook(signer, fakeClock, settings))
+
+	client := NewHttpClientWithInterfaces(logger, fakeClock, metricWriter, httpClient)
+	request := client.NewRequest().
+		WithUrl(testServer.URL).
+		WithBody(`{"foo":"bar"}`)
+
+	_, err := client.Post(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=id/20200101/eu-central-1/execute-api/aws4_request"), authorization)
+	assert.Equal(t, `{"foo":"bar"}`, body)
+}
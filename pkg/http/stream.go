@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// ProgressCallback is called after every chunk read from a streamed body. total is -1 if the size is unknown.
+type ProgressCallback func(transferred int64, total int64)
+
+// StreamResponse is returned by Client.Stream. The body is not buffered, it has to be read and closed by the caller.
+type StreamResponse struct {
+	Body       io.ReadCloser
+	Header     http.Header
+	Cookies    []*http.Cookie
+	StatusCode int
+}
+
+type progressReader struct {
+	reader      io.Reader
+	total       int64
+	transferred int64
+	callback    ProgressCallback
+}
+
+func newProgressReader(reader io.Reader, total int64, callback ProgressCallback) *progressReader {
+	return &progressReader{
+		reader:   reader,
+		total:    total,
+		callback: callback,
+	}
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	if n > 0 {
+		r.transferred += int64(n)
+		r.callback(r.transferred, r.total)
+	}
+
+	return n, err
+}
+
+func (r *progressReader) Close() error {
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Stream performs the request without buffering the response body. As the request body can only be read once,
+// streamed requests are neither retried nor hedged.
+func (c *client) Stream(ctx context.Context, method string, request *Request) (*StreamResponse, error) {
+	resp, _, err := c.execute(ctx, method, request, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadCloser = resp.RawBody()
+
+	if request.downloadProgress != nil {
+		body = newProgressReader(body, resp.RawResponse.ContentLength, request.downloadProgress)
+	}
+
+	return &StreamResponse{
+		Body:       body,
+		Header:     resp.Header(),
+		Cookies:    resp.Cookies(),
+		StatusCode: resp.StatusCode(),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/http"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	netHttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Stream(t *testing.T) {
+	var uploaded []byte
+
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		var err error
+		uploaded, err = ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		res.Header().Set("Content-Length", "1024")
+		_, err = res.Write(bytes.Repeat([]byte("a"), 1024))
+		assert.NoError(t, err)
+	}))
+	defer testServer.Close()
+
+	logger := monMocks.NewLoggerMockedAll()
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("WriteOne", mock.Anything)
+
+	client := http.NewHttpClientWithInterfaces(logger, clock.NewRealClock(), metricWriter, resty.New())
+
+	var uploadedBytes, uploadTotal, downloadedBytes, downloadTotal int64
+	request := client.NewRequest().
+		WithUrl(testServer.URL).
+		WithBodyReader(strings.NewReader("streamed body"), 13).
+		WithUploadProgress(func(transferred int64, total int64) {
+			uploadedBytes, uploadTotal = transferred, total
+		}).
+		WithDownloadProgress(func(transferred int64, total int64) {
+			downloadedBytes, downloadTotal = transferred, total
+		})
+
+	response, err := client.Stream(context.Background(), http.PostRequest, request)
+	assert.NoError(t, err)
+	assert.Equal(t, netHttp.StatusOK, response.StatusCode)
+
+	body, err := ioutil.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+
+	assert.Equal(t, "streamed body", string(uploaded))
+	assert.Equal(t, int64(13), uploadedBytes)
+	assert.Equal(t, int64(13), uploadTotal)
+
+	assert.Len(t, body, 1024)
+	assert.Equal(t, int64(1024), downloadedBytes)
+	assert.Equal(t, int64(1024), downloadTotal)
+}
@@ -5,6 +5,7 @@ const (
 	TypeEssential    = "essential"
 	TypeForeground   = "foreground"
 	TypeBackground   = "background"
+	TypeTask         = "task"
 	StageEssential   = 0
 	StageService     = 0x400
 	StageApplication = 0x800
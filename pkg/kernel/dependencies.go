@@ -0,0 +1,146 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validateDependencies makes sure every dependency of a module exists in the same or an earlier stage and the
+// dependencies within a stage are free of cycles.
+func (k *kernel) validateDependencies() error {
+	moduleStages := make(map[string]int)
+
+	for index, stage := range k.stages {
+		for name := range stage.modules.modules {
+			moduleStages[name] = index
+		}
+	}
+
+	for index, stage := range k.stages {
+		for name, ms := range stage.modules.modules {
+			for _, dependency := range ms.Config.DependsOn {
+				dependencyStage, ok := moduleStages[dependency]
+
+				if !ok {
+					return fmt.Errorf("module %s depends on the unknown module %s", name, dependency)
+				}
+
+				if dependencyStage > index {
+					return fmt.Errorf("module %s of stage %d depends on the module %s of the later stage %d", name, index, dependency, dependencyStage)
+				}
+			}
+		}
+
+		if _, err := stage.startOrder(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (k *kernel) getModuleState(name string) *ModuleState {
+	for _, stage := range k.stages {
+		if ms, ok := stage.modules.modules[name]; ok {
+			return ms
+		}
+	}
+
+	return nil
+}
+
+// waitDependencies blocks until all dependencies of the module are ready. It returns false if the stage is
+// stopped before.
+func (k *kernel) waitDependencies(ctx context.Context, name string, ms *ModuleState) bool {
+	for _, dependency := range ms.Config.DependsOn {
+		dependencyState := k.getModuleState(dependency)
+
+		select {
+		case <-dependencyState.ready.Channel():
+		case <-ctx.Done():
+			k.logger.Infof("stopped waiting for dependency %s of module %s", dependency, name)
+			return false
+		}
+	}
+
+	return true
+}
+
+// startOrder sorts the modules of the stage topologically, so every module comes after its dependencies
+func (s *stage) startOrder() ([]string, error) {
+	names := make([]string, 0, len(s.modules.modules))
+	pending := make(map[string]int)
+
+	for name := range s.modules.modules {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		pending[name] = len(s.dependenciesInStage(name))
+	}
+
+	order := make([]string, 0, len(names))
+
+	for len(order) < len(names) {
+		progress := false
+
+		for _, name := range names {
+			if pending[name] != 0 {
+				continue
+			}
+
+			pending[name] = -1
+			order = append(order, name)
+			progress = true
+
+			for _, dependent := range s.dependents(name) {
+				pending[dependent]--
+			}
+		}
+
+		if !progress {
+			cycle := make([]string, 0)
+
+			for _, name := range names {
+				if pending[name] > 0 {
+					cycle = append(cycle, name)
+				}
+			}
+
+			return nil, fmt.Errorf("dependency cycle between the modules %s", strings.Join(cycle, ", "))
+		}
+	}
+
+	return order, nil
+}
+
+func (s *stage) dependenciesInStage(name string) []string {
+	dependencies := make([]string, 0)
+
+	for _, dependency := range s.modules.modules[name].Config.DependsOn {
+		if _, ok := s.modules.modules[dependency]; ok {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	return dependencies
+}
+
+func (s *stage) dependents(name string) []string {
+	dependents := make([]string, 0)
+
+	for dependent, ms := range s.modules.modules {
+		for _, dependency := range ms.Config.DependsOn {
+			if dependency == name {
+				dependents = append(dependents, dependent)
+				break
+			}
+		}
+	}
+
+	return dependents
+}
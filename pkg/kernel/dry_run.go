@@ -0,0 +1,103 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/coffin"
+	"time"
+)
+
+const dryRunValidationTimeout = time.Second * 30
+
+type dryRunCheck struct {
+	name string
+	err  error
+}
+
+type dryRunReport struct {
+	checks []dryRunCheck
+}
+
+func (r *dryRunReport) add(name string, err error) bool {
+	r.checks = append(r.checks, dryRunCheck{
+		name: name,
+		err:  err,
+	})
+
+	return err == nil
+}
+
+func (r *dryRunReport) failed() int {
+	failed := 0
+
+	for _, check := range r.checks {
+		if check.err != nil {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// runDryRun builds all modules and validates the config, the module dependencies and every ValidatingModule
+// without running any module. The kernel exits with code 1 if any of the checks failed.
+func (k *kernel) runDryRun() {
+	report := &dryRunReport{}
+
+	k.checkDryRun(report)
+
+	for _, check := range report.checks {
+		if check.err != nil {
+			k.logger.Errorf(check.err, "dry run: %s failed", check.name)
+			continue
+		}
+
+		k.logger.Infof("dry run: %s ok", check.name)
+	}
+
+	failed := report.failed()
+
+	if failed > 0 {
+		k.logger.Warnf("dry run: %d of %d checks failed", failed, len(report.checks))
+		k.forceExit(1)
+
+		return
+	}
+
+	k.logger.Infof("dry run: all %d checks passed", len(report.checks))
+}
+
+func (k *kernel) checkDryRun(report *dryRunReport) {
+	if !report.add("building modules by multiFactories", k.runMultiFactories()) {
+		return
+	}
+
+	if !report.add("building modules", k.runFactories()) {
+		return
+	}
+
+	report.add("module dependencies", k.validateDependencies())
+	report.add("config audit", cfg.AuditConfig(k.config, k.logger))
+
+	for _, stageIndex := range k.getStageIndices() {
+		for name, ms := range k.stages[stageIndex].modules.modules {
+			if validatingModule, ok := ms.Module.(ValidatingModule); ok {
+				report.add(fmt.Sprintf("validation of module %s", name), k.validateModule(validatingModule))
+			}
+		}
+	}
+}
+
+func (k *kernel) validateModule(module ValidatingModule) (err error) {
+	defer func() {
+		if panicErr := coffin.ResolveRecovery(recover()); panicErr != nil {
+			err = panicErr
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dryRunValidationTimeout)
+	defer cancel()
+
+	return module.Validate(ctx)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/applike/gosoline/pkg/coffin"
 	"github.com/applike/gosoline/pkg/conc"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/uuid"
 	"golang.org/x/sys/unix"
 	"os"
 	"os/signal"
@@ -20,6 +21,7 @@ import (
 type Kernel interface {
 	Add(name string, moduleFactory ModuleFactory, opts ...ModuleOption)
 	AddFactory(factory MultiModuleFactory)
+	Ready() <-chan struct{}
 	Running() <-chan struct{}
 	Run()
 	Stop(reason string)
@@ -43,11 +45,22 @@ type kernel struct {
 	stagesLck         conc.PoisonedLock
 	started           conc.PoisonedLock
 	running           chan struct{}
+	ready             conc.SignalOnce
 	stopped           sync.Once
+	shutdown          conc.SignalOnce
 	foregroundModules int32
-
-	killTimeout time.Duration
-	forceExit   func(code int)
+	taskModules       int32
+	exitCode          int32
+
+	killTimeout    time.Duration
+	healthInterval time.Duration
+	forceExit      func(code int)
+	metric         *moduleMetricWriter
+
+	dryRun                bool
+	memberId              string
+	singletonInterval     time.Duration
+	leaderElectionFactory conc.LeaderElectionFactory
 }
 
 func New(config cfg.Config, logger mon.Logger, options ...Option) (*kernel, error) {
@@ -58,13 +71,20 @@ func New(config cfg.Config, logger mon.Logger, options ...Option) (*kernel, erro
 		stages:    make(map[int]*stage),
 		stagesLck: conc.NewPoisonedLock(),
 		running:   make(chan struct{}),
+		ready:     conc.NewSignalOnce(),
+		shutdown:  conc.NewSignalOnce(),
 		started:   conc.NewPoisonedLock(),
 
 		config: config,
 		logger: logger.WithChannel("kernel"),
 
-		killTimeout: time.Second * 10,
-		forceExit:   os.Exit,
+		killTimeout:    time.Second * 10,
+		healthInterval: time.Minute,
+		forceExit:      os.Exit,
+
+		memberId:              uuid.New().NewV4(),
+		singletonInterval:     time.Second * 10,
+		leaderElectionFactory: conc.NewLeaderElection,
 	}
 
 	if err := k.Option(options...); err != nil {
@@ -82,6 +102,45 @@ func KillTimeout(killTimeout time.Duration) Option {
 	}
 }
 
+// HealthInterval sets the interval in which the running state of every module
+// is written as a metric. A value of 0 disables the heartbeat.
+func HealthInterval(healthInterval time.Duration) Option {
+	return func(k *kernel) error {
+		k.healthInterval = healthInterval
+
+		return nil
+	}
+}
+
+// DryRun builds all modules and validates the config and modules instead of running them. The kernel
+// exits with code 1 if any check fails, which makes it usable as a smoke test before a deployment.
+func DryRun(dryRun bool) Option {
+	return func(k *kernel) error {
+		k.dryRun = dryRun
+
+		return nil
+	}
+}
+
+// SingletonInterval sets the interval in which singleton modules renew their leadership
+// or check whether they can take over.
+func SingletonInterval(singletonInterval time.Duration) Option {
+	return func(k *kernel) error {
+		k.singletonInterval = singletonInterval
+
+		return nil
+	}
+}
+
+// LeaderElectionFactory replaces the factory used to create the leader elections of singleton modules.
+func LeaderElectionFactory(factory conc.LeaderElectionFactory) Option {
+	return func(k *kernel) error {
+		k.leaderElectionFactory = factory
+
+		return nil
+	}
+}
+
 func ForceExit(forceExit func(code int)) Option {
 	return func(k *kernel) error {
 		k.forceExit = forceExit
@@ -119,6 +178,11 @@ func (k *kernel) AddFactory(factory MultiModuleFactory) {
 	k.multiFactories = append(k.multiFactories, factory)
 }
 
+// Ready is closed once all essential modules are ready
+func (k *kernel) Ready() <-chan struct{} {
+	return k.ready.Channel()
+}
+
 func (k *kernel) Running() <-chan struct{} {
 	return k.running
 }
@@ -128,6 +192,14 @@ func (k *kernel) Run() {
 	k.started.Poison()
 
 	defer k.logger.Info("leaving kernel")
+
+	if k.dryRun {
+		k.logger.Info("starting kernel in dry run mode")
+		k.runDryRun()
+
+		return
+	}
+
 	k.logger.Info("starting kernel")
 
 	if err := k.runMultiFactories(); err != nil {
@@ -150,6 +222,14 @@ func (k *kernel) Run() {
 
 	k.logger.Info("all modules created")
 
+	if err := k.validateDependencies(); err != nil {
+		k.logger.Error(err, "invalid module dependencies")
+		close(k.running)
+		return
+	}
+
+	k.metric = newModuleMetricWriter(k.getModuleNames())
+
 	// poison our stages so any other thread trying to add a new stage will
 	// panic instead of hanging
 	k.stagesLck.Poison()
@@ -161,6 +241,7 @@ func (k *kernel) Run() {
 	}
 
 	k.foregroundModules = int32(k.countForegroundModules())
+	k.taskModules = int32(k.countModules(TypeTask))
 	if k.foregroundModules == 0 {
 		k.logger.Info("no foreground modules")
 		return
@@ -171,6 +252,12 @@ func (k *kernel) Run() {
 
 	k.debugConfig()
 
+	if err := cfg.AuditConfig(k.config, k.logger); err != nil {
+		k.logger.Error(err, "config audit failed")
+		close(k.running)
+		return
+	}
+
 	for _, stageIndex := range k.getStageIndices() {
 		k.stages[stageIndex].run(k)
 		k.logger.Infof("stage %d up and running", stageIndex)
@@ -178,6 +265,12 @@ func (k *kernel) Run() {
 
 	k.logger.Info("kernel up and running")
 	close(k.running)
+	k.watchKernelReadiness()
+
+	heartbeatDone := conc.NewSignalOnce()
+	defer heartbeatDone.Signal()
+
+	go k.runHeartbeat(heartbeatDone)
 
 	select {
 	case <-k.waitAllStagesDone().Channel():
@@ -188,19 +281,29 @@ func (k *kernel) Run() {
 	}
 
 	k.waitStopped()
+
+	if exitCode := atomic.LoadInt32(&k.exitCode); exitCode != 0 {
+		k.logger.Warnf("exiting with code %d as a task module failed", exitCode)
+		k.forceExit(int(exitCode))
+	}
 }
 
 func (k *kernel) Stop(reason string) {
 	k.stopped.Do(func() {
 		go func() {
+			defer k.shutdown.Signal()
+
 			k.logger.Infof("stopping kernel due to: %s", reason)
-			indices := k.getStageIndices()
+			hooks := takeShutdownHooks()
 
-			for i := len(indices) - 1; i >= 0; i-- {
-				stageIndex := indices[i]
-				k.logger.Infof("stopping stage %d", stageIndex)
-				k.stages[stageIndex].stopWait(stageIndex, k.logger)
-				k.logger.Infof("stopped stage %d", stageIndex)
+			for _, stageIndex := range k.getShutdownIndices(hooks) {
+				if stage, ok := k.stages[stageIndex]; ok {
+					k.logger.Infof("stopping stage %d", stageIndex)
+					stage.stopWait(stageIndex, k.logger)
+					k.logger.Infof("stopped stage %d", stageIndex)
+				}
+
+				k.runShutdownHooks(stageIndex, hooks[stageIndex])
 			}
 		}()
 	})
@@ -271,14 +374,26 @@ func (k *kernel) runFactories() error {
 
 func (k *kernel) addModuleToStage(name string, module Module, opts []ModuleOption) error {
 	ms := &ModuleState{
-		Module:    module,
-		Config:    getModuleConfig(module),
-		IsRunning: false,
-		Err:       nil,
+		Module:     module,
+		Config:     getModuleConfig(module),
+		IsRunning:  false,
+		Err:        nil,
+		ready:      conc.NewSignalOnce(),
+		stopped:    conc.NewSignalOnce(),
+		goroutines: coffin.NewTracker(),
 	}
 
 	MergeOptions(opts)(&ms.Config)
 
+	if ms.Config.Singleton != "" {
+		singleton, err := k.newSingletonModule(name, module, ms.Config.Singleton)
+		if err != nil {
+			return err
+		}
+
+		ms.Module = singleton
+	}
+
 	// lock the stagesLck even if we are just reading from the map
 	// we are not allowed to read and write a map concurrently
 	k.stagesLck.Lock()
@@ -326,6 +441,19 @@ func (k *kernel) hasModules() bool {
 	return false
 }
 
+func (k *kernel) getModuleNames() []string {
+	names := make([]string, 0)
+
+	// no need to iterate in order as we are only collecting
+	for _, stage := range k.stages {
+		for name := range stage.modules.modules {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
 func (k *kernel) countForegroundModules() int {
 	count := 0
 
@@ -341,6 +469,21 @@ func (k *kernel) countForegroundModules() int {
 	return count
 }
 
+func (k *kernel) countModules(moduleType string) int {
+	count := 0
+
+	// no need to iterate in order as we are only counting
+	for _, stage := range k.stages {
+		for _, m := range stage.modules.modules {
+			if m.Config.Type == moduleType {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
 func (k *kernel) debugConfig() {
 	debugErr := cfg.DebugConfig(k.config, k.logger)
 
@@ -349,12 +492,83 @@ func (k *kernel) debugConfig() {
 	}
 }
 
-func (k *kernel) runModule(ctx context.Context, name string, ms *ModuleState) (moduleErr error) {
+func (k *kernel) runModule(ctx context.Context, name string, ms *ModuleState) error {
 	defer k.logger.Infof("stopped %s module %s", ms.Config.Type, name)
 
 	k.logger.Infof("running %s module %s in stage %d", ms.Config.Type, name, ms.Config.Stage)
 
-	ms.IsRunning = true
+	attempt := 0
+
+	for {
+		startedAt := time.Now()
+		panicked := k.runModuleOnce(ctx, name, ms)
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		policy := ms.Config.RestartPolicy
+		restartOnPanic := panicked && ms.Config.RestartOnPanic
+
+		// a panic counts as a failure to restart on, the maximum number of restarts of the policy still applies
+		if restartOnPanic && policy.Policy != RestartAlways {
+			policy.Policy = RestartOnFailure
+		}
+
+		if !policy.shouldRestart(ms.Err != nil, ms.Restarts) {
+			break
+		}
+
+		if time.Since(startedAt) >= policy.resetsAfter() {
+			attempt = 0
+		}
+
+		// restarts after a panic back off like any other restart, so a module panicking right away doesn't spin
+		attempt++
+		backoff := policy.backoff(attempt)
+
+		ms.Restarts++
+		k.metric.writeRestart(name)
+
+		if restartOnPanic {
+			k.logger.Warnf("restarting %s module %s after panic in %s", ms.Config.Type, name, backoff)
+		} else {
+			k.logger.Warnf("restarting %s module %s in %s", ms.Config.Type, name, backoff)
+		}
+
+		if !k.waitBackoff(ctx, backoff) {
+			break
+		}
+	}
+
+	switch ms.Config.Type {
+	case TypeEssential:
+		k.essentialModuleExited(name)
+	case TypeForeground:
+		k.foregroundModuleExited()
+	case TypeTask:
+		k.taskModuleExited(name, ms.Err)
+	}
+
+	return ms.Err
+}
+
+func (k *kernel) waitBackoff(ctx context.Context, backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (k *kernel) runModuleOnce(ctx context.Context, name string, ms *ModuleState) (panicked bool) {
+	ms.setRunning(true)
+	k.watchReadiness(ctx, name, ms)
+	k.metric.writeHeartbeat(name, ms)
 
 	defer func(ms *ModuleState) {
 		// recover any crash from the module - if we let the coffin handle this,
@@ -363,28 +577,25 @@ func (k *kernel) runModule(ctx context.Context, name string, ms *ModuleState) (m
 		panicErr := coffin.ResolveRecovery(recover())
 
 		if panicErr != nil {
+			panicked = true
 			ms.Err = panicErr
+			k.metric.writePanic(name)
+			coffin.ReportPanic(panicErr)
 		}
 
 		if ms.Err != nil {
+			ms.setLastErrAt(time.Now())
+			k.metric.writeError(name)
 			k.logger.Errorf(ms.Err, "error running %s module %s", ms.Config.Type, name)
 		}
 
-		ms.IsRunning = false
-		switch ms.Config.Type {
-		case TypeEssential:
-			k.essentialModuleExited(name)
-		case TypeForeground:
-			k.foregroundModuleExited()
-		}
-
-		// make sure we are returning the correct error to our caller
-		moduleErr = ms.Err
+		ms.setRunning(false)
+		k.metric.writeHeartbeat(name, ms)
 	}(ms)
 
 	ms.Err = ms.Module.Run(ctx)
 
-	return ms.Err
+	return false
 }
 
 func (k *kernel) essentialModuleExited(name string) {
@@ -402,6 +613,25 @@ func (k *kernel) foregroundModuleExited() {
 	}
 }
 
+func (k *kernel) taskModuleExited(name string, err error) {
+	// like essential modules, task modules are also counted as foreground modules, but
+	// the kernel stops on its own once the tasks are done
+	if err != nil {
+		atomic.StoreInt32(&k.exitCode, 1)
+
+		reason := fmt.Sprintf("the task module [%s] failed", name)
+		k.Stop(reason)
+
+		return
+	}
+
+	remaining := atomic.AddInt32(&k.taskModules, -1)
+
+	if remaining == 0 {
+		k.Stop("all task modules completed")
+	}
+}
+
 func (k *kernel) waitStopped() {
 	done := conc.NewSignalOnce()
 	defer done.Signal()
@@ -419,7 +649,7 @@ func (k *kernel) waitStopped() {
 			for _, stageIndex := range k.getStageIndices() {
 				s := k.stages[stageIndex]
 				for name, ms := range s.modules.modules {
-					if ms.IsRunning {
+					if isRunning, _ := ms.health(); isRunning {
 						k.logger.Infof("module in stage %d blocking the shutdown: %s", stageIndex, name)
 					}
 				}
@@ -435,6 +665,37 @@ func (k *kernel) waitStopped() {
 	for _, stage := range k.stages {
 		<-stage.terminated.Channel()
 	}
+
+	// the shutdown hooks of the last stage run after it terminated
+	<-k.shutdown.Channel()
+}
+
+func (k *kernel) runHeartbeat(done conc.SignalOnce) {
+	if k.healthInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(k.healthInterval)
+	defer ticker.Stop()
+
+	stats := &runtimeStats{}
+	stats.collect()
+
+	for {
+		select {
+		case <-done.Channel():
+			return
+		case <-ticker.C:
+			stats.collect()
+			k.metric.writeRuntime(stats)
+
+			for _, stage := range k.stages {
+				for name, ms := range stage.modules.modules {
+					k.metric.writeHeartbeat(name, ms)
+				}
+			}
+		}
+	}
 }
 
 func (k *kernel) getStageIndices() []int {
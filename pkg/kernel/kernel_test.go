@@ -7,6 +7,7 @@ import (
 	cfgMocks "github.com/applike/gosoline/pkg/cfg/mocks"
 	"github.com/applike/gosoline/pkg/coffin"
 	"github.com/applike/gosoline/pkg/conc"
+	concMocks "github.com/applike/gosoline/pkg/conc/mocks"
 	"github.com/applike/gosoline/pkg/kernel"
 	kernelMocks "github.com/applike/gosoline/pkg/kernel/mocks"
 	"github.com/applike/gosoline/pkg/mon"
@@ -17,6 +18,7 @@ import (
 	"golang.org/x/sys/unix"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -25,6 +27,7 @@ func createMocks() (*cfgMocks.Config, *monMocks.Logger, *kernelMocks.FullModule)
 	config := new(cfgMocks.Config)
 	config.On("AllSettings").Return(map[string]interface{}{})
 	config.On("UnmarshalKey", "kernel", mock.AnythingOfType("*kernel.Settings")).Return(map[string]interface{}{})
+	config.On("UnmarshalKey", "config_audit", mock.AnythingOfType("*cfg.UsageAuditSettings"))
 
 	logger := new(monMocks.Logger)
 	logger.On("WithChannel", mock.Anything).Return(logger)
@@ -480,3 +483,545 @@ func TestModuleFastShutdown(t *testing.T) {
 		k.Run()
 	})
 }
+
+func TestModuleRestartPolicy(t *testing.T) {
+	config, logger, module := createMocks()
+
+	logger.On("Errorf", mock.Anything, "error running %s module %s", kernel.TypeForeground, "module")
+	logger.On("Warnf", "restarting %s module %s in %s", kernel.TypeForeground, "module", mock.AnythingOfType("time.Duration"))
+
+	module.On("GetStage").Return(kernel.StageApplication)
+	module.On("Run", mock.Anything).Return(fmt.Errorf("failure")).Twice()
+	module.On("Run", mock.Anything).Return(nil).Once()
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("module", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	}, kernel.ModuleRestartPolicy(kernel.RestartPolicy{
+		Policy:         kernel.RestartOnFailure,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond * 10,
+	}))
+	k.Run()
+
+	module.AssertNumberOfCalls(t, "Run", 3)
+	logger.AssertNumberOfCalls(t, "Warnf", 2)
+}
+
+func TestModuleRestartPolicyMaxRestarts(t *testing.T) {
+	config, logger, module := createMocks()
+
+	logger.On("Errorf", mock.Anything, "error running %s module %s", kernel.TypeForeground, "module")
+	logger.On("Errorf", mock.Anything, "error during the execution of stage %d", kernel.StageApplication)
+	logger.On("Warnf", "restarting %s module %s in %s", kernel.TypeForeground, "module", mock.AnythingOfType("time.Duration"))
+
+	module.On("GetStage").Return(kernel.StageApplication)
+	module.On("Run", mock.Anything).Return(fmt.Errorf("failure"))
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("module", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	}, kernel.ModuleRestartPolicy(kernel.RestartPolicy{
+		Policy:         kernel.RestartAlways,
+		InitialBackoff: time.Millisecond,
+		MaxRestarts:    2,
+	}))
+	k.Run()
+
+	module.AssertNumberOfCalls(t, "Run", 3)
+}
+
+func TestModuleRestartOnPanic(t *testing.T) {
+	config, logger, module := createMocks()
+
+	logger.On("Errorf", mock.Anything, "error running %s module %s", kernel.TypeForeground, "module")
+	logger.On("Warnf", "restarting %s module %s after panic in %s", kernel.TypeForeground, "module", mock.AnythingOfType("time.Duration"))
+
+	reported := int32(0)
+	remove := coffin.AddPanicHandler(func(err error) {
+		if strings.Contains(err.Error(), "module panic") {
+			atomic.AddInt32(&reported, 1)
+		}
+	})
+	defer remove()
+
+	module.On("GetStage").Return(kernel.StageApplication)
+	module.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+		panic("module panic")
+	}).Return(nil).Twice()
+	module.On("Run", mock.Anything).Return(nil).Once()
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("module", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	}, kernel.ModuleRestartOnPanic(true), kernel.ModuleRestartPolicy(kernel.RestartPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond * 10,
+	}))
+	k.Run()
+
+	module.AssertNumberOfCalls(t, "Run", 3)
+	logger.AssertCalled(t, "Warnf", "restarting %s module %s after panic in %s", kernel.TypeForeground, "module", time.Millisecond)
+	logger.AssertCalled(t, "Warnf", "restarting %s module %s after panic in %s", kernel.TypeForeground, "module", time.Millisecond*2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&reported))
+}
+
+func TestModuleRestartOnPanicMaxRestarts(t *testing.T) {
+	config, logger, module := createMocks()
+
+	logger.On("Errorf", mock.Anything, "error running %s module %s", kernel.TypeForeground, "module")
+	logger.On("Errorf", mock.Anything, "error during the execution of stage %d", kernel.StageApplication)
+	logger.On("Warnf", "restarting %s module %s after panic in %s", kernel.TypeForeground, "module", mock.AnythingOfType("time.Duration"))
+
+	module.On("GetStage").Return(kernel.StageApplication)
+	module.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+		panic("module panic")
+	}).Return(nil)
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("module", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	}, kernel.ModuleRestartOnPanic(true), kernel.ModuleRestartPolicy(kernel.RestartPolicy{
+		InitialBackoff: time.Millisecond,
+		MaxRestarts:    2,
+	}))
+	k.Run()
+
+	module.AssertNumberOfCalls(t, "Run", 3)
+}
+
+func TestModuleHeartbeatWhileRestarting(t *testing.T) {
+	config, logger, module := createMocks()
+
+	logger.On("Errorf", mock.Anything, "error running %s module %s", kernel.TypeForeground, "module")
+	logger.On("Warnf", "restarting %s module %s in %s", kernel.TypeForeground, "module", mock.AnythingOfType("time.Duration"))
+
+	module.On("GetStage").Return(kernel.StageApplication)
+	module.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+		time.Sleep(time.Millisecond * 5)
+	}).Return(fmt.Errorf("failure")).Times(5)
+	module.On("Run", mock.Anything).Return(nil).Once()
+
+	// the heartbeat reads the module state while the module is restarted, run with -race to detect unguarded access
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second), kernel.HealthInterval(time.Millisecond))
+	assert.NoError(t, err)
+
+	k.Add("module", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	}, kernel.ModuleRestartPolicy(kernel.RestartPolicy{
+		Policy:         kernel.RestartOnFailure,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}))
+	k.Run()
+
+	module.AssertNumberOfCalls(t, "Run", 6)
+}
+
+type dependencyModule struct {
+	kernel.BackgroundModule
+	kernel.ApplicationStage
+	name   string
+	events chan string
+	stop   kernel.Kernel
+}
+
+func (m *dependencyModule) GetType() string {
+	if m.stop != nil {
+		return kernel.TypeForeground
+	}
+
+	return kernel.TypeBackground
+}
+
+func (m *dependencyModule) Run(ctx context.Context) error {
+	if m.stop != nil {
+		m.stop.Stop("done")
+	}
+
+	<-ctx.Done()
+	m.events <- m.name
+
+	return nil
+}
+
+func TestModuleDependsOn(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Infof", mock.Anything, mock.Anything)
+	logger.On("Infof", mock.Anything, mock.Anything, mock.Anything)
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	events := make(chan string, 3)
+	addModule := func(name string, stop kernel.Kernel, dependencies ...string) {
+		k.Add(name, func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+			return &dependencyModule{
+				name:   name,
+				events: events,
+				stop:   stop,
+			}, nil
+		}, kernel.ModuleDependsOn(dependencies...))
+	}
+
+	addModule("db", nil)
+	addModule("cache", nil, "db")
+	addModule("api", k, "cache", "db")
+	k.Run()
+
+	assert.Equal(t, "api", <-events)
+	assert.Equal(t, "cache", <-events)
+	assert.Equal(t, "db", <-events)
+}
+
+func TestModuleDependsOnInvalid(t *testing.T) {
+	for name, dependencies := range map[string]map[string][]string{
+		"unknown": {
+			"api": {"db"},
+		},
+		"cycle": {
+			"api":   {"cache"},
+			"cache": {"api"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config, logger, module := createMocks()
+			logger.On("Error", mock.Anything, "invalid module dependencies").Once()
+
+			module.On("GetStage").Return(kernel.StageApplication)
+
+			k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+			assert.NoError(t, err)
+
+			for moduleName, moduleDependencies := range dependencies {
+				k.Add(moduleName, func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+					return module, nil
+				}, kernel.ModuleDependsOn(moduleDependencies...))
+			}
+
+			k.Run()
+
+			logger.AssertCalled(t, "Error", mock.Anything, "invalid module dependencies")
+			module.AssertNotCalled(t, "Run", mock.Anything)
+		})
+	}
+}
+
+type readinessModule struct {
+	kernel.EssentialModule
+	kernel.ApplicationStage
+	ready conc.SignalOnce
+	delay time.Duration
+}
+
+func (m *readinessModule) Ready() <-chan struct{} {
+	return m.ready.Channel()
+}
+
+func (m *readinessModule) Run(ctx context.Context) error {
+	if m.delay > 0 {
+		time.AfterFunc(m.delay, m.ready.Signal)
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestModuleReadiness(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Infof", mock.Anything, mock.Anything)
+	logger.On("Infof", mock.Anything, mock.Anything, mock.Anything)
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	module := &readinessModule{
+		ready: conc.NewSignalOnce(),
+		delay: time.Millisecond * 50,
+	}
+
+	k.Add("db", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	})
+
+	go func() {
+		<-k.Running()
+
+		select {
+		case <-k.Ready():
+			assert.Fail(t, "the kernel should not be ready before the essential module")
+		default:
+		}
+
+		<-k.Ready()
+		assert.True(t, module.ready.Signaled())
+
+		k.Stop("ready")
+	}()
+
+	k.Run()
+}
+
+func TestModuleStartupTimeout(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Infof", mock.Anything, mock.Anything)
+	logger.On("Infof", mock.Anything, mock.Anything, mock.Anything)
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	k.Add("db", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return &readinessModule{
+			ready: conc.NewSignalOnce(),
+		}, nil
+	}, kernel.ModuleStartupTimeout(time.Millisecond*10))
+	k.Run()
+
+	logger.AssertCalled(t, "Infof", "stopping kernel due to: %s", "module db did not become ready within 10ms")
+
+	select {
+	case <-k.Ready():
+		assert.Fail(t, "the kernel should not be ready")
+	default:
+	}
+}
+
+type singletonModule struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+	kernel kernel.Kernel
+	runs   int
+}
+
+func (m *singletonModule) Run(ctx context.Context) error {
+	m.runs++
+	m.kernel.Stop("singleton running")
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func TestModuleSingleton(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Infof", mock.Anything, mock.Anything)
+	logger.On("Infof", mock.Anything, mock.Anything, mock.Anything)
+
+	election := new(concMocks.LeaderElection)
+	election.On("IsLeader", mock.Anything, mock.AnythingOfType("string")).Return(false, nil).Once()
+	election.On("IsLeader", mock.Anything, mock.AnythingOfType("string")).Return(true, nil)
+	election.On("Resign", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
+
+	k, err := kernel.New(config, logger,
+		kernel.KillTimeout(time.Second),
+		kernel.SingletonInterval(time.Millisecond*10),
+		kernel.LeaderElectionFactory(func(config cfg.Config, logger mon.Logger, name string) (conc.LeaderElection, error) {
+			assert.Equal(t, "scheduler", name)
+
+			return election, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	module := &singletonModule{
+		kernel: k,
+	}
+
+	k.Add("scheduler", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	}, kernel.ModuleSingleton("scheduler"))
+	k.Run()
+
+	assert.Equal(t, 1, module.runs)
+	election.AssertExpectations(t)
+}
+
+type trackedModule struct {
+	kernel.ForegroundModule
+	kernel.ApplicationStage
+	running int
+}
+
+func (m *trackedModule) Run(ctx context.Context) error {
+	cfn, cfnCtx := coffin.WithContext(ctx)
+	release := make(chan struct{})
+
+	for i := 0; i < 2; i++ {
+		cfn.GoWithContext(cfnCtx, func(ctx context.Context) error {
+			<-release
+
+			return nil
+		})
+	}
+
+	m.running = coffin.TrackerFromContext(ctx).Running()
+	close(release)
+
+	return cfn.Wait()
+}
+
+func TestModuleGoroutineTracking(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Infof", mock.Anything, mock.Anything)
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	module := &trackedModule{}
+
+	k.Add("tracked", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return module, nil
+	})
+	k.Run()
+
+	assert.Equal(t, 2, module.running)
+}
+
+func TestShutdownHooks(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Infof", mock.Anything, mock.Anything)
+	logger.On("Infof", mock.Anything, mock.Anything, mock.Anything)
+	logger.On("Errorf", mock.Anything, "error during the shutdown hooks of stage %d", mock.Anything)
+
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second))
+	assert.NoError(t, err)
+
+	calls := make([]string, 0)
+	hook := func(name string, err error) kernel.ShutdownHook {
+		return func(ctx context.Context) error {
+			calls = append(calls, name)
+
+			return err
+		}
+	}
+
+	kernel.OnShutdown("pool", hook("pool", nil), kernel.StageService)
+	kernel.OnShutdown("buffer", hook("buffer", fmt.Errorf("flush failed")), kernel.StageService)
+	kernel.OnShutdown("client", hook("client", nil), kernel.StageApplication)
+	kernel.OnShutdown("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}, kernel.StageEssential, kernel.ShutdownTimeout(time.Millisecond))
+
+	k.Add("main", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return &fastExitModule{}, nil
+	}, kernel.ModuleType(kernel.TypeForeground))
+	k.Run()
+
+	assert.Equal(t, []string{"client", "buffer", "pool"}, calls)
+	logger.AssertCalled(t, "Errorf", mock.Anything, "error during the shutdown hooks of stage %d", kernel.StageEssential)
+	logger.AssertCalled(t, "Errorf", mock.Anything, "error during the shutdown hooks of stage %d", kernel.StageService)
+}
+
+func TestDryRun(t *testing.T) {
+	config, logger, _ := createMocks()
+	logger.On("Infof", mock.Anything, mock.Anything)
+	logger.On("Errorf", mock.Anything, "dry run: %s failed", "validation of module invalid")
+	logger.On("Warnf", "dry run: %d of %d checks failed", 1, 6)
+
+	exitCode := -1
+	k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second), kernel.DryRun(true), kernel.ForceExit(func(code int) {
+		exitCode = code
+	}))
+	assert.NoError(t, err)
+
+	valid := new(kernelMocks.ValidatingModule)
+	valid.On("Validate", mock.Anything).Return(nil)
+
+	invalid := new(kernelMocks.ValidatingModule)
+	invalid.On("Validate", mock.Anything).Return(fmt.Errorf("queue does not exist"))
+
+	k.Add("valid", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return &validatingModule{ValidatingModule: valid}, nil
+	})
+	k.Add("invalid", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		return &validatingModule{ValidatingModule: invalid}, nil
+	})
+	k.Run()
+
+	assert.Equal(t, 1, exitCode)
+	valid.AssertExpectations(t)
+	invalid.AssertExpectations(t)
+	logger.AssertCalled(t, "Errorf", mock.Anything, "dry run: %s failed", "validation of module invalid")
+	logger.AssertCalled(t, "Warnf", "dry run: %d of %d checks failed", 1, 6)
+}
+
+type validatingModule struct {
+	kernel.ValidatingModule
+	kernel.BackgroundModule
+	kernel.ApplicationStage
+}
+
+func (m *validatingModule) Run(_ context.Context) error {
+	panic("modules must not run in dry run mode")
+}
+
+type taskModule struct {
+	kernel.TaskModule
+	kernel.ApplicationStage
+	err error
+}
+
+func (m *taskModule) Run(_ context.Context) error {
+	return m.err
+}
+
+func TestTaskModule(t *testing.T) {
+	for name, test := range map[string]struct {
+		err      error
+		exitCode int
+	}{
+		"completed": {
+			err:      nil,
+			exitCode: 0,
+		},
+		"failed": {
+			err:      fmt.Errorf("migration failed"),
+			exitCode: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config, logger, _ := createMocks()
+			logger.On("Errorf", mock.Anything, mock.Anything, mock.Anything).Maybe()
+			logger.On("Errorf", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+			logger.On("Warnf", mock.Anything, mock.Anything).Maybe()
+
+			exitCode := 0
+			k, err := kernel.New(config, logger, kernel.KillTimeout(time.Second), kernel.ForceExit(func(code int) {
+				exitCode = code
+			}))
+			assert.NoError(t, err)
+
+			background := new(kernelMocks.FullModule)
+			background.On("GetType").Return(kernel.TypeBackground)
+			background.On("GetStage").Return(kernel.StageService)
+			background.On("Run", mock.Anything).Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).Return(nil)
+
+			k.Add("background", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+				return background, nil
+			})
+			k.Add("task", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+				return &taskModule{err: test.err}, nil
+			})
+			k.Add("other task", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+				return &taskModule{}, nil
+			})
+			k.Run()
+
+			assert.Equal(t, test.exitCode, exitCode)
+			background.AssertExpectations(t)
+		})
+	}
+}
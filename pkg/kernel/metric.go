@@ -0,0 +1,147 @@
+package kernel
+
+import "github.com/applike/gosoline/pkg/mon"
+
+const (
+	// 1 if the module is currently running, 0 otherwise
+	metricNameModuleRunning = "KernelModuleRunning"
+	// number of times a module returned an error or panicked
+	metricNameModuleError = "KernelModuleError"
+	// number of times a module has been restarted
+	metricNameModuleRestart = "KernelModuleRestart"
+	// unix timestamp of the last error of a module
+	metricNameModuleLastError = "KernelModuleLastError"
+	// number of recovered panics, either of a module or any other go routine managed by a coffin
+	metricNamePanicCount = "PanicCount"
+	// number of go routines a module started with a coffin using its context
+	metricNameModuleGoroutines = "KernelModuleGoroutines"
+	// number of go routines of the whole process
+	metricNameRuntimeGoroutines = "KernelRuntimeGoroutines"
+	// bytes of allocated heap objects
+	metricNameRuntimeHeapAlloc = "KernelRuntimeHeapAlloc"
+	// number of allocated heap objects
+	metricNameRuntimeHeapObjects = "KernelRuntimeHeapObjects"
+	// number of garbage collections since the last sample
+	metricNameRuntimeGcCount = "KernelRuntimeGcCount"
+	// time spent in stop-the-world gc pauses since the last sample
+	metricNameRuntimeGcPause = "KernelRuntimeGcPause"
+	// cpu usage of the process since the last sample, 100 equals one fully used core
+	metricNameRuntimeCpu = "KernelRuntimeCpu"
+)
+
+type moduleMetricWriter struct {
+	writer mon.MetricWriter
+}
+
+func newModuleMetricWriter(names []string) *moduleMetricWriter {
+	defaults := make(mon.MetricData, 0, len(names)*3)
+
+	for _, name := range names {
+		defaults = append(defaults, getModuleDefaultMetrics(name)...)
+	}
+
+	return &moduleMetricWriter{
+		writer: mon.NewMetricDaemonWriter(defaults...),
+	}
+}
+
+func (w *moduleMetricWriter) writeHeartbeat(name string, ms *ModuleState) {
+	isRunning, lastErrAt := ms.health()
+
+	running := 0.0
+	if isRunning {
+		running = 1.0
+	}
+
+	data := mon.MetricData{
+		w.datum(metricNameModuleRunning, name, running, mon.UnitCountAverage),
+		w.datum(metricNameModuleGoroutines, name, float64(ms.goroutines.Running()), mon.UnitCountAverage),
+	}
+
+	if !lastErrAt.IsZero() {
+		data = append(data, w.datum(metricNameModuleLastError, name, float64(lastErrAt.Unix()), mon.UnitSecondsAverage))
+	}
+
+	w.writer.Write(data)
+}
+
+func (w *moduleMetricWriter) writeError(name string) {
+	w.writer.WriteOne(w.datum(metricNameModuleError, name, 1.0, mon.UnitCount))
+}
+
+func (w *moduleMetricWriter) writePanic(name string) {
+	w.writer.WriteOne(w.datum(metricNamePanicCount, name, 1.0, mon.UnitCount))
+}
+
+func (w *moduleMetricWriter) writeRestart(name string) {
+	w.writer.WriteOne(w.datum(metricNameModuleRestart, name, 1.0, mon.UnitCount))
+}
+
+func (w *moduleMetricWriter) writeRuntime(stats *runtimeStats) {
+	data := mon.MetricData{
+		w.runtimeDatum(metricNameRuntimeGoroutines, float64(stats.goroutines), mon.UnitCountAverage),
+		w.runtimeDatum(metricNameRuntimeHeapAlloc, float64(stats.heapAlloc), mon.UnitBytesAverage),
+		w.runtimeDatum(metricNameRuntimeHeapObjects, float64(stats.heapObjects), mon.UnitCountAverage),
+		w.runtimeDatum(metricNameRuntimeGcCount, float64(stats.gcCount), mon.UnitCount),
+		w.runtimeDatum(metricNameRuntimeGcPause, float64(stats.gcPause.Milliseconds()), mon.UnitMilliseconds),
+	}
+
+	if stats.hasCpuUsage {
+		data = append(data, w.runtimeDatum(metricNameRuntimeCpu, stats.cpuPercent, mon.UnitPercentAverage))
+	}
+
+	w.writer.Write(data)
+}
+
+func (w *moduleMetricWriter) runtimeDatum(metricName string, value float64, unit string) *mon.MetricDatum {
+	return &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricName,
+		Value:      value,
+		Unit:       unit,
+	}
+}
+
+func (w *moduleMetricWriter) datum(metricName string, module string, value float64, unit string) *mon.MetricDatum {
+	return &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricName,
+		Dimensions: mon.MetricDimensions{
+			"Module": module,
+		},
+		Value: value,
+		Unit:  unit,
+	}
+}
+
+func getModuleDefaultMetrics(name string) mon.MetricData {
+	return mon.MetricData{
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameModuleError,
+			Dimensions: mon.MetricDimensions{
+				"Module": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNamePanicCount,
+			Dimensions: mon.MetricDimensions{
+				"Module": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameModuleRestart,
+			Dimensions: mon.MetricDimensions{
+				"Module": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+	}
+}
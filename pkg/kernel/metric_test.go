@@ -0,0 +1,99 @@
+package kernel
+
+import (
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestModuleMetricWriter_WriteHeartbeat(t *testing.T) {
+	lastErrAt := time.Unix(1600000000, 0)
+
+	tests := map[string]struct {
+		isRunning bool
+		lastErrAt time.Time
+		expected  map[string]float64
+	}{
+		"running": {
+			isRunning: true,
+			expected: map[string]float64{
+				metricNameModuleRunning:    1,
+				metricNameModuleGoroutines: 0,
+			},
+		},
+		"stopped after an error": {
+			isRunning: false,
+			lastErrAt: lastErrAt,
+			expected: map[string]float64{
+				metricNameModuleRunning:    0,
+				metricNameModuleGoroutines: 0,
+				metricNameModuleLastError:  1600000000,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			writer := new(monMocks.MetricWriter)
+			writer.On("Write", mock.AnythingOfType("mon.MetricData")).Once()
+
+			ms := &ModuleState{
+				IsRunning:  test.isRunning,
+				LastErrAt:  test.lastErrAt,
+				goroutines: coffin.NewTracker(),
+			}
+
+			w := &moduleMetricWriter{writer: writer}
+			w.writeHeartbeat("module", ms)
+
+			actual := map[string]float64{}
+			for _, datum := range writer.Calls[0].Arguments.Get(0).(mon.MetricData) {
+				assert.Equal(t, "module", datum.Dimensions["Module"])
+				actual[datum.MetricName] = datum.Value
+			}
+
+			assert.Equal(t, test.expected, actual)
+			writer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModuleMetricWriter_WriteHeartbeatConcurrently(t *testing.T) {
+	writer := new(monMocks.MetricWriter)
+	writer.On("Write", mock.AnythingOfType("mon.MetricData"))
+
+	ms := &ModuleState{
+		goroutines: coffin.NewTracker(),
+	}
+
+	w := &moduleMetricWriter{writer: writer}
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	// run with -race to detect unguarded access to the module state
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			ms.setRunning(i%2 == 0)
+			ms.setLastErrAt(time.Now())
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			w.writeHeartbeat("module", ms)
+		}
+	}()
+
+	wg.Wait()
+
+	writer.AssertNumberOfCalls(t, "Write", 100)
+}
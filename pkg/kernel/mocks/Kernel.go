@@ -34,6 +34,22 @@ func (_m *Kernel) Run() {
 	_m.Called()
 }
 
+// Ready provides a mock function with given fields:
+func (_m *Kernel) Ready() <-chan struct{} {
+	ret := _m.Called()
+
+	var r0 <-chan struct{}
+	if rf, ok := ret.Get(0).(func() <-chan struct{}); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	return r0
+}
+
 // Running provides a mock function with given fields:
 func (_m *Kernel) Running() <-chan struct{} {
 	ret := _m.Called()
@@ -3,14 +3,19 @@ package kernel
 import (
 	"context"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/conc"
 	"github.com/applike/gosoline/pkg/kernel/common"
 	"github.com/applike/gosoline/pkg/mon"
+	"sync"
+	"time"
 )
 
 const (
 	TypeEssential  = common.TypeEssential
 	TypeForeground = common.TypeForeground
 	TypeBackground = common.TypeBackground
+	TypeTask       = common.TypeTask
 
 	// The kernel is split into three stages.
 	//  * Essential: Starts first and shuts down last. Includes metric writers and anything else which gets data from other
@@ -63,17 +68,52 @@ type ModuleState struct {
 	Config    ModuleConfig
 	IsRunning bool
 	Err       error
+	LastErrAt time.Time
+	Restarts  int
+
+	// the heartbeat reads IsRunning and LastErrAt while the module is running, so they are only accessed with the lock
+	lck        sync.RWMutex
+	ready      conc.SignalOnce
+	stopped    conc.SignalOnce
+	goroutines *coffin.Tracker
+}
+
+func (ms *ModuleState) setRunning(running bool) {
+	ms.lck.Lock()
+	defer ms.lck.Unlock()
+
+	ms.IsRunning = running
+}
+
+func (ms *ModuleState) setLastErrAt(lastErrAt time.Time) {
+	ms.lck.Lock()
+	defer ms.lck.Unlock()
+
+	ms.LastErrAt = lastErrAt
+}
+
+func (ms *ModuleState) health() (isRunning bool, lastErrAt time.Time) {
+	ms.lck.RLock()
+	defer ms.lck.RUnlock()
+
+	return ms.IsRunning, ms.LastErrAt
 }
 
 type ModuleConfig struct {
-	Type  string
-	Stage int
+	Type           string
+	Stage          int
+	RestartOnPanic bool
+	RestartPolicy  RestartPolicy
+	DependsOn      []string
+	StartupTimeout time.Duration
+	Singleton      string
 }
 
 // A module provides a single function or service for your application.
 // For example, an HTTP server would be a single module (see "apiserver")
 // while a daemon writing metrics in the background would be a separate
 // module (see "mon").
+//
 //go:generate mockery -name=Module
 type Module interface {
 	// Execute the module. If the provided context is canceled you have a
@@ -85,11 +125,13 @@ type Module interface {
 	Run(ctx context.Context) error
 }
 
-// A module can be associated with a type of TypeEssential, TypeForeground or
-// TypeBackground. An essential module always causes a kernel shutdown upon
-// normal termination, a foreground module only after the last foreground module
-// and a background module never. If you don't implement TypedModule it will
-// default to TypeForeground.
+// A module can be associated with a type of TypeEssential, TypeForeground,
+// TypeBackground or TypeTask. An essential module always causes a kernel shutdown
+// upon normal termination, a foreground module only after the last foreground module
+// and a background module never. A task module causes a kernel shutdown after the
+// last task module completed or as soon as one of them failed. If you don't
+// implement TypedModule it will default to TypeForeground.
+//
 //go:generate mockery -name=TypedModule
 type TypedModule interface {
 	GetType() string
@@ -100,12 +142,32 @@ type TypedModule interface {
 // and shut down later. You should use the StageEssential, StageService and
 // StageApplication constants unless you have very specific needs and know what
 // you are doing.
+//
 //go:generate mockery -name=StagedModule
 type StagedModule interface {
 	GetStage() int
 }
 
+// A module can signal when it is ready to do its work, e.g. once a server is listening
+// or a cache is warmed up. Modules depending on it are started only afterwards and the
+// kernel is ready once all essential modules are ready. A module not implementing
+// ReadinessModule is ready as soon as it is running.
+//
+//go:generate mockery -name=ReadinessModule
+type ReadinessModule interface {
+	Ready() <-chan struct{}
+}
+
+// A module can validate its setup without running, e.g. check that the resources it depends on exist. The
+// kernel only calls Validate in dry run mode (see DryRun).
+//
+//go:generate mockery -name=ValidatingModule
+type ValidatingModule interface {
+	Validate(ctx context.Context) error
+}
+
 // A full module provides all the methods a module can have and thus never relies on defaults.
+//
 //go:generate mockery -name=FullModule
 type FullModule interface {
 	Module
@@ -143,6 +205,15 @@ func (m BackgroundModule) GetType() string {
 	return TypeBackground
 }
 
+// A task module runs to completion, e.g. a batch job or a migration. The kernel shuts down once the last task module
+// completed or as soon as one of them failed. In the latter case the application exits with code 1 after the shutdown.
+type TaskModule struct {
+}
+
+func (m TaskModule) GetType() string {
+	return TypeTask
+}
+
 type EssentialStage struct{}
 
 func (s EssentialStage) GetStage() int {
@@ -163,9 +234,9 @@ func (s ApplicationStage) GetStage() int {
 
 // The default module type you could use for your application code.
 // Your module will
-//  * Run at the application stage
-//  * Be a foreground module and can therefore shut down the kernel if you don't run other foreground modules
-//  * Implement any future methods we might add to the Module interface with some reasonable default values
+//   - Run at the application stage
+//   - Be a foreground module and can therefore shut down the kernel if you don't run other foreground modules
+//   - Implement any future methods we might add to the Module interface with some reasonable default values
 type DefaultModule struct {
 	ForegroundModule
 	ApplicationStage
@@ -1,5 +1,7 @@
 package kernel
 
+import "time"
+
 type ModuleOption func(ms *ModuleConfig)
 
 // Overwrite the type a module specifies by something else.
@@ -27,6 +29,58 @@ func ModuleStage(moduleStage int) ModuleOption {
 	}
 }
 
+// Restart a module if it panics instead of stopping it (and, depending
+// on the type of the module, the kernel). The panic is still logged,
+// counted and reported to the panic handlers of the coffin, but the
+// module is run again as long as the kernel is not stopping. Restarts
+// back off exponentially and are limited to the maximum number of
+// restarts as configured by the restart policy of the module.
+func ModuleRestartOnPanic(restart bool) ModuleOption {
+	return func(ms *ModuleConfig) {
+		ms.RestartOnPanic = restart
+	}
+}
+
+// Restart a module according to the policy after it returned, e.g.
+//
+//	k.Add("your module", NewYourModule(), kernel.ModuleRestartPolicy(kernel.RestartPolicy{
+//		Policy: kernel.RestartOnFailure,
+//	}))
+//
+// restarts a crashed module with an exponential backoff instead of stopping it.
+// Every restart is counted in the ModuleRestart metric.
+func ModuleRestartPolicy(policy RestartPolicy) ModuleOption {
+	return func(ms *ModuleConfig) {
+		ms.RestartPolicy = policy
+	}
+}
+
+// Start a module only after the named modules are ready and stop it before
+// them. Dependencies have to be part of the same or an earlier stage.
+func ModuleDependsOn(names ...string) ModuleOption {
+	return func(ms *ModuleConfig) {
+		ms.DependsOn = append(ms.DependsOn, names...)
+	}
+}
+
+// Limit the time a ReadinessModule has to become ready after it started. An
+// essential module exceeding the timeout stops the kernel, for other modules a
+// warning is logged. A timeout of 0 waits forever.
+func ModuleStartupTimeout(timeout time.Duration) ModuleOption {
+	return func(ms *ModuleConfig) {
+		ms.StartupTimeout = timeout
+	}
+}
+
+// Run a module on only one instance of the application at a time, namely the leader of the named leader
+// election (see conc.NewLeaderElection). The other instances keep the module on standby and take over once
+// the lease of the leader expires. A singleton module counts as ready while standing by.
+func ModuleSingleton(leaderElection string) ModuleOption {
+	return func(ms *ModuleConfig) {
+		ms.Singleton = leaderElection
+	}
+}
+
 // Combine a list of options by applying them in order.
 func MergeOptions(options []ModuleOption) ModuleOption {
 	return func(ms *ModuleConfig) {
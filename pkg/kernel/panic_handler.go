@@ -0,0 +1,30 @@
+package kernel
+
+import (
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+// NewPanicHandler creates a coffin.PanicHandler which logs every recovered panic as an
+// error (thus forwarding it to any error hook like sentry) and counts it in the PanicCount
+// metric. Register it with coffin.AddPanicHandler.
+func NewPanicHandler(logger mon.Logger) coffin.PanicHandler {
+	logger = logger.WithChannel("panic")
+	writer := mon.NewMetricDaemonWriter(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricNamePanicCount,
+		Unit:       mon.UnitCount,
+		Value:      0.0,
+	})
+
+	return func(err error) {
+		logger.Error(err, "recovered from panic")
+
+		writer.WriteOne(&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNamePanicCount,
+			Unit:       mon.UnitCount,
+			Value:      1.0,
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// watchReadiness marks the module as ready once it signals its readiness. Modules not implementing ReadinessModule
+// are ready as soon as they are running. An essential module not becoming ready within its startup timeout stops
+// the kernel, for any other module a warning is logged.
+func (k *kernel) watchReadiness(ctx context.Context, name string, ms *ModuleState) {
+	if ms.ready.Signaled() {
+		return
+	}
+
+	readinessModule, ok := ms.Module.(ReadinessModule)
+
+	if !ok {
+		ms.ready.Signal()
+		return
+	}
+
+	go func() {
+		var timeout <-chan time.Time
+
+		if ms.Config.StartupTimeout > 0 {
+			timer := time.NewTimer(ms.Config.StartupTimeout)
+			defer timer.Stop()
+
+			timeout = timer.C
+		}
+
+		select {
+		case <-readinessModule.Ready():
+			ms.ready.Signal()
+			return
+		case <-ctx.Done():
+			return
+		case <-timeout:
+		}
+
+		reason := fmt.Sprintf("module %s did not become ready within %s", name, ms.Config.StartupTimeout)
+
+		if ms.Config.Type == TypeEssential {
+			k.Stop(reason)
+			return
+		}
+
+		k.logger.Warn(reason)
+
+		select {
+		case <-readinessModule.Ready():
+			ms.ready.Signal()
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// watchKernelReadiness marks the kernel as ready once all essential modules are ready
+func (k *kernel) watchKernelReadiness() {
+	essentials := make([]*ModuleState, 0)
+
+	for _, stage := range k.stages {
+		for _, ms := range stage.modules.modules {
+			if ms.Config.Type == TypeEssential {
+				essentials = append(essentials, ms)
+			}
+		}
+	}
+
+	go func() {
+		for _, ms := range essentials {
+			select {
+			case <-ms.ready.Channel():
+			case <-ms.stopped.Channel():
+				return
+			}
+		}
+
+		k.logger.Info("all essential modules are ready")
+		k.ready.Signal()
+	}()
+}
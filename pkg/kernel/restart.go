@@ -0,0 +1,70 @@
+package kernel
+
+import (
+	"time"
+)
+
+const (
+	// RestartNever keeps a module stopped once it returned, this is the default
+	RestartNever = "never"
+	// RestartOnFailure restarts a module if it returned an error or panicked
+	RestartOnFailure = "on-failure"
+	// RestartAlways restarts a module whenever it returned
+	RestartAlways = "always"
+)
+
+type RestartPolicy struct {
+	Policy string
+	// backoff before the first restart, doubled with every consecutive restart. Defaults to a second.
+	InitialBackoff time.Duration
+	// upper bound of the backoff. A module running longer than this resets the backoff. Defaults to a minute.
+	MaxBackoff time.Duration
+	// maximum number of restarts, 0 for unlimited restarts
+	MaxRestarts int
+}
+
+func (p RestartPolicy) shouldRestart(failed bool, restarts int) bool {
+	if p.MaxRestarts > 0 && restarts >= p.MaxRestarts {
+		return false
+	}
+
+	switch p.Policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return failed
+	default:
+		return false
+	}
+}
+
+func (p RestartPolicy) backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	maxBackoff := p.MaxBackoff
+
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+
+	return backoff
+}
+
+func (p RestartPolicy) resetsAfter() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return time.Minute
+	}
+
+	return p.MaxBackoff
+}
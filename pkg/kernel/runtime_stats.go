@@ -0,0 +1,59 @@
+package kernel
+
+import (
+	"golang.org/x/sys/unix"
+	"runtime"
+	"time"
+)
+
+type runtimeStats struct {
+	goroutines  int
+	heapAlloc   uint64
+	heapObjects uint64
+	gcCount     uint32
+	gcPause     time.Duration
+	cpuPercent  float64
+	hasCpuUsage bool
+
+	lastNumGc    uint32
+	lastGcPause  uint64
+	lastCpuTime  time.Duration
+	lastSampleAt time.Time
+}
+
+// collect samples the runtime of the process. The gc and cpu stats cover the time since the last sample.
+func (s *runtimeStats) collect() {
+	memStats := &runtime.MemStats{}
+	runtime.ReadMemStats(memStats)
+
+	s.goroutines = runtime.NumGoroutine()
+	s.heapAlloc = memStats.HeapAlloc
+	s.heapObjects = memStats.HeapObjects
+	s.gcCount = memStats.NumGC - s.lastNumGc
+	s.gcPause = time.Duration(memStats.PauseTotalNs - s.lastGcPause)
+	s.lastNumGc = memStats.NumGC
+	s.lastGcPause = memStats.PauseTotalNs
+
+	now := time.Now()
+	cpuTime, err := processCpuTime()
+
+	// we need two samples to calculate the cpu usage in between
+	s.hasCpuUsage = err == nil && !s.lastSampleAt.IsZero()
+
+	if s.hasCpuUsage {
+		s.cpuPercent = float64(cpuTime-s.lastCpuTime) / float64(now.Sub(s.lastSampleAt)) * 100
+	}
+
+	s.lastCpuTime = cpuTime
+	s.lastSampleAt = now
+}
+
+func processCpuTime() (time.Duration, error) {
+	usage := &unix.Rusage{}
+
+	if err := unix.Getrusage(unix.RUSAGE_SELF, usage); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), nil
+}
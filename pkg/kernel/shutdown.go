@@ -0,0 +1,141 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/hashicorp/go-multierror"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultShutdownTimeout = time.Second * 5
+
+// A ShutdownHook releases a resource which was created outside of a module, e.g. a connection pool or a buffer
+// which needs to be flushed. The context is canceled once the timeout of the hook is exceeded.
+type ShutdownHook func(ctx context.Context) error
+
+type ShutdownHookOption func(hook *shutdownHook)
+
+// Limit the time a shutdown hook may take, defaults to 5 seconds.
+func ShutdownTimeout(timeout time.Duration) ShutdownHookOption {
+	return func(hook *shutdownHook) {
+		hook.timeout = timeout
+	}
+}
+
+type shutdownHook struct {
+	name    string
+	hook    ShutdownHook
+	stage   int
+	timeout time.Duration
+}
+
+var shutdownHooks = struct {
+	sync.Mutex
+	hooks []*shutdownHook
+}{}
+
+// OnShutdown registers a hook which is called once all modules of the given stage are stopped. As stages are
+// stopped in reverse order, the hooks of later stages run first. The hooks of a stage run in the reverse order
+// of their registration, so a resource built on top of another one is closed before it.
+func OnShutdown(name string, hook ShutdownHook, stage int, opts ...ShutdownHookOption) {
+	h := &shutdownHook{
+		name:    name,
+		hook:    hook,
+		stage:   stage,
+		timeout: defaultShutdownTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	shutdownHooks.Lock()
+	defer shutdownHooks.Unlock()
+
+	shutdownHooks.hooks = append(shutdownHooks.hooks, h)
+}
+
+// takeShutdownHooks removes all registered hooks and groups them by stage
+func takeShutdownHooks() map[int][]*shutdownHook {
+	shutdownHooks.Lock()
+	defer shutdownHooks.Unlock()
+
+	hooks := make(map[int][]*shutdownHook)
+
+	for i := len(shutdownHooks.hooks) - 1; i >= 0; i-- {
+		hook := shutdownHooks.hooks[i]
+		hooks[hook.stage] = append(hooks[hook.stage], hook)
+	}
+
+	shutdownHooks.hooks = nil
+
+	return hooks
+}
+
+// getShutdownIndices returns the stages with modules or hooks in the order they have to be stopped
+func (k *kernel) getShutdownIndices(hooks map[int][]*shutdownHook) []int {
+	indices := k.getStageIndices()
+
+	for index := range hooks {
+		if _, ok := k.stages[index]; !ok {
+			indices = append(indices, index)
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(indices)))
+
+	return indices
+}
+
+func (k *kernel) runShutdownHooks(stageIndex int, hooks []*shutdownHook) {
+	if len(hooks) == 0 {
+		return
+	}
+
+	var result error
+
+	for _, hook := range hooks {
+		if err := k.runShutdownHook(hook); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if result != nil {
+		k.logger.Errorf(result, "error during the shutdown hooks of stage %d", stageIndex)
+		return
+	}
+
+	k.logger.Infof("ran %d shutdown hooks of stage %d", len(hooks), stageIndex)
+}
+
+func (k *kernel) runShutdownHook(hook *shutdownHook) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), hook.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if panicErr := coffin.ResolveRecovery(recover()); panicErr != nil {
+				done <- panicErr
+			}
+		}()
+
+		done <- hook.hook(ctx)
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", hook.timeout)
+	}
+
+	if err != nil {
+		return fmt.Errorf("shutdown hook %s failed: %w", hook.name, err)
+	}
+
+	return nil
+}
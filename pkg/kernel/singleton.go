@@ -0,0 +1,140 @@
+package kernel
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/conc"
+	"github.com/applike/gosoline/pkg/mon"
+	"time"
+)
+
+// singletonModule runs the wrapped module only while this instance is the leader of the configured leader election.
+// The other instances stand by and check in every interval whether the lease of the leader expired, so one of them
+// takes over if the leader dies. A module losing the leadership is stopped and started again once it is regained.
+type singletonModule struct {
+	Module
+
+	logger   mon.Logger
+	election conc.LeaderElection
+	memberId string
+	interval time.Duration
+}
+
+func (k *kernel) newSingletonModule(name string, module Module, leaderElection string) (*singletonModule, error) {
+	election, err := k.leaderElectionFactory(k.config, k.logger, leaderElection)
+	if err != nil {
+		return nil, fmt.Errorf("can not create leader election %s for module %s: %w", leaderElection, name, err)
+	}
+
+	return &singletonModule{
+		Module:   module,
+		logger:   k.logger.WithFields(mon.Fields{"module": name}),
+		election: election,
+		memberId: k.memberId,
+		interval: k.singletonInterval,
+	}, nil
+}
+
+func (m *singletonModule) Run(ctx context.Context) error {
+	defer m.resign()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		isLeader, err := m.isLeader(ctx)
+		if err != nil {
+			return err
+		}
+
+		if isLeader {
+			m.logger.Info("acquired leadership, starting singleton module")
+
+			lost, err := m.runAsLeader(ctx, ticker.C)
+			if !lost {
+				return err
+			}
+
+			m.logger.Warn("lost leadership, stopped singleton module")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runAsLeader runs the module until it returns or we lose the leadership
+func (m *singletonModule) runAsLeader(ctx context.Context, tick <-chan time.Time) (lost bool, err error) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	panicked := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			// the kernel can only recover panics of the go routine running the module, so we hand them over
+			if panicErr := coffin.ResolveRecovery(recover()); panicErr != nil {
+				panicked <- panicErr
+			}
+		}()
+
+		done <- m.Module.Run(leaderCtx)
+	}()
+
+	for {
+		select {
+		case err = <-done:
+			return false, err
+		case panicErr := <-panicked:
+			panic(panicErr)
+		case <-tick:
+		}
+
+		isLeader, leaderErr := m.isLeader(ctx)
+
+		if leaderErr == nil && isLeader {
+			continue
+		}
+
+		cancel()
+
+		select {
+		case <-done:
+		case panicErr := <-panicked:
+			panic(panicErr)
+		}
+
+		return leaderErr == nil, leaderErr
+	}
+}
+
+// isLeader reports transient errors as not being the leader, as we can't be sure we still hold the lease
+func (m *singletonModule) isLeader(ctx context.Context) (bool, error) {
+	isLeader, err := m.election.IsLeader(ctx, m.memberId)
+
+	if conc.IsLeaderElectionFatalError(err) {
+		return false, err
+	}
+
+	if err != nil {
+		m.logger.Warnf("can not check leadership: %s", err.Error())
+		return false, nil
+	}
+
+	return isLeader, nil
+}
+
+func (m *singletonModule) resign() {
+	// the module context is already canceled at this point
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	if err := m.election.Resign(ctx, m.memberId); err != nil {
+		m.logger.Warnf("can not resign leadership: %s", err.Error())
+	}
+}
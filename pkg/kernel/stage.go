@@ -45,7 +45,13 @@ func newStage() *stage {
 func (s *stage) run(k *kernel) {
 	s.modules.lck.Poison()
 
-	for name, ms := range s.modules.modules {
+	// the order only matters for the logs, every module waits for its dependencies on its own
+	order, _ := s.startOrder()
+
+	for _, name := range order {
+		ms := s.modules.modules[name]
+		ctx, cancel := context.WithCancel(coffin.WithTracker(context.Background(), ms.goroutines))
+
 		s.cfn.Gof(func(name string, ms *ModuleState) func() error {
 			return func() error {
 				// wait until every routine of the stage was spawned
@@ -53,10 +59,31 @@ func (s *stage) run(k *kernel) {
 				// regarding the precondition of tomb.Go (namely that no
 				// new routine may be added after the last one exited)
 				<-s.running.Channel()
+				defer ms.stopped.Signal()
+
+				if !k.waitDependencies(s.ctx, name, ms) {
+					return nil
+				}
 
-				return k.runModule(s.ctx, name, ms)
+				return k.runModule(ctx, name, ms)
 			}
 		}(name, ms), "panic during running of module %s", name)
+
+		// stop the module once the stage is stopping and all modules depending on it are stopped,
+		// so modules are shut down in the reverse order of their dependencies
+		s.cfn.Go(func(name string, cancel context.CancelFunc) func() error {
+			return func() error {
+				<-s.ctx.Done()
+
+				for _, dependent := range s.dependents(name) {
+					<-s.modules.modules[dependent].stopped.Channel()
+				}
+
+				cancel()
+
+				return nil
+			}
+		}(name, cancel))
 	}
 
 	s.running.Signal()
@@ -62,3 +62,28 @@ func getBatch(ctx context.Context, keys interface{}, result interface{}, getChun
 
 	return missing, nil
 }
+
+// GetBatchTyped reads a set of values from the store like GetBatch, but writes the missing keys
+// to missing, which has to be a pointer to a slice of the type of the keys, e.g. *[]string. This
+// allows to fetch the missing values from somewhere else and fill them into the store afterwards.
+func GetBatchTyped(ctx context.Context, store KvStore, keys interface{}, values interface{}, missing interface{}) error {
+	missingSlice, err := refl.SliceOf(missing)
+
+	if err != nil {
+		return fmt.Errorf("can not use provided missing value: %w", err)
+	}
+
+	missingKeys, err := store.GetBatch(ctx, keys, values)
+
+	if err != nil {
+		return err
+	}
+
+	for _, key := range missingKeys {
+		if err := missingSlice.Append(key); err != nil {
+			return fmt.Errorf("can not append missing key %v: %w", key, err)
+		}
+	}
+
+	return nil
+}
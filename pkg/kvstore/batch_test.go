@@ -0,0 +1,36 @@
+package kvstore_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGetBatchTyped(t *testing.T) {
+	ctx := context.Background()
+	store := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{
+		Name:      "test",
+		Ttl:       time.Hour,
+		BatchSize: 2,
+	})
+
+	err := store.PutBatch(ctx, map[int]Item{
+		1: {Id: "1", Body: "foo"},
+		3: {Id: "3", Body: "bar"},
+	})
+	assert.NoError(t, err)
+
+	values := make(map[int]Item)
+	missing := make([]int, 0)
+
+	err = kvstore.GetBatchTyped(ctx, store, []int{1, 2, 3, 4}, values, &missing)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[int]Item{
+		1: {Id: "1", Body: "foo"},
+		3: {Id: "3", Body: "bar"},
+	}, values)
+	assert.Equal(t, []int{2, 4}, missing)
+}
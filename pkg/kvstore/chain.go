@@ -6,6 +6,8 @@ import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/refl"
+	"golang.org/x/sync/singleflight"
+	"time"
 )
 
 type chainKvStore struct {
@@ -15,18 +17,22 @@ type chainKvStore struct {
 	settings *Settings
 
 	missingCache KvStore
+	invalidation InvalidationPublisher
+	options      *chainOptions
+	loading      singleflight.Group
 }
 
 var noValue = &struct{}{}
 
-func NewChainKvStore(config cfg.Config, logger mon.Logger, missingCacheEnabled bool, settings *Settings) (*chainKvStore, error) {
+func NewChainKvStore(config cfg.Config, logger mon.Logger, missingCacheEnabled bool, settings *Settings, options ...ChainOption) (*chainKvStore, error) {
 	settings.PadFromConfig(config)
 	factory := buildFactory(config, logger)
 
 	var err error
 	var missingCache KvStore
 
-	if missingCacheEnabled {
+	// the negative caching of the loader needs a missing value cache
+	if missingCacheEnabled || buildChainOptions(options).notFoundTtl > 0 {
 		missingCacheSettings := *settings
 		missingCacheSettings.Name = fmt.Sprintf("%s-missingCache", settings.Name)
 
@@ -37,19 +43,28 @@ func NewChainKvStore(config cfg.Config, logger mon.Logger, missingCacheEnabled b
 		missingCache = NewEmptyKvStore()
 	}
 
-	return NewChainKvStoreWithInterfaces(logger, factory, missingCache, settings), nil
+	return NewChainKvStoreWithInterfaces(logger, factory, missingCache, settings, options...), nil
 }
 
-func NewChainKvStoreWithInterfaces(logger mon.Logger, factory func(Factory, *Settings) (KvStore, error), missingCache KvStore, settings *Settings) *chainKvStore {
+func NewChainKvStoreWithInterfaces(logger mon.Logger, factory func(Factory, *Settings) (KvStore, error), missingCache KvStore, settings *Settings, options ...ChainOption) *chainKvStore {
 	return &chainKvStore{
 		logger:       logger,
 		factory:      factory,
 		chain:        make([]KvStore, 0),
 		settings:     settings,
 		missingCache: missingCache,
+		invalidation: noopInvalidationPublisher{},
+		options:      buildChainOptions(options),
 	}
 }
 
+// EnableInvalidation publishes the keys changed by this store with the publisher. The store evicts
+// the keys received by the invalidation consumer from all its elements besides the last one.
+func (s *chainKvStore) EnableInvalidation(publisher InvalidationPublisher) {
+	s.invalidation = publisher
+	registerInvalidationTarget(s)
+}
+
 func (s *chainKvStore) Add(elementFactory Factory) error {
 	store, err := s.factory(elementFactory, s.settings)
 	if err != nil {
@@ -96,11 +111,19 @@ func (s *chainKvStore) Contains(ctx context.Context, key interface{}) (bool, err
 		}
 	}
 
-	// Cache empty value if no result was found
-	if err := s.missingCache.Put(ctx, key, noValue); err != nil {
-		s.logger.WithContext(ctx).Warnf("failed to write to missing value cache: %s", err.Error())
+	if s.options.loader != nil {
+		result, err := s.load(ctx, key)
+
+		if err != nil {
+			return false, err
+		}
+
+		return result.found, nil
 	}
 
+	// Cache empty value if no result was found
+	s.cacheMissing(ctx, key)
+
 	return false, nil
 }
 
@@ -139,11 +162,27 @@ func (s *chainKvStore) Get(ctx context.Context, key interface{}, value interface
 		}
 	}
 
+	if !exists && s.options.loader != nil {
+		result, err := s.load(ctx, key)
+
+		if err != nil {
+			return false, err
+		}
+
+		if !result.found {
+			return false, nil
+		}
+
+		if err := assignLoadedValue(value, result.value); err != nil {
+			return false, fmt.Errorf("can not assign loaded value of %s: %w", key, err)
+		}
+
+		return true, nil
+	}
+
 	// Cache empty value if no result was found
 	if !exists {
-		if err := s.missingCache.Put(ctx, key, noValue); err != nil {
-			s.logger.WithContext(ctx).Warnf("failed to write to missing value cache: %s", err.Error())
-		}
+		s.cacheMissing(ctx, key)
 
 		return false, nil
 	}
@@ -161,22 +200,27 @@ func (s *chainKvStore) Get(ctx context.Context, key interface{}, value interface
 }
 
 func (s *chainKvStore) GetBatch(ctx context.Context, keys interface{}, values interface{}) ([]interface{}, error) {
-	todo, err := refl.InterfaceToInterfaceSlice(keys)
-	var cachedMissing []interface{}
+	keySlice, err := refl.InterfaceToInterfaceSlice(keys)
 
 	if err != nil {
 		return nil, fmt.Errorf("can not morph keys to slice of interfaces: %w", err)
 	}
 
-	cachedMissingMap := make(map[string]interface{})
-	todo, err = s.missingCache.GetBatch(ctx, todo, cachedMissingMap)
+	cachedMissingMap := make(map[interface{}]interface{})
+	todo, err := s.missingCache.GetBatch(ctx, keySlice, cachedMissingMap)
 
 	if err != nil {
 		s.logger.WithContext(ctx).Warnf("failed to read from missing value cache: %s", err.Error())
 	}
 
-	for k := range cachedMissingMap {
-		cachedMissing = append(cachedMissing, k)
+	// report the cached missing keys in the order and type they were requested in
+	cachedMissing := make([]interface{}, 0, len(cachedMissingMap))
+
+	for _, key := range keySlice {
+		if _, ok := cachedMissingMap[key]; ok {
+			cachedMissing = append(cachedMissing, key)
+			delete(cachedMissingMap, key)
+		}
 	}
 
 	if len(todo) == 0 {
@@ -241,8 +285,13 @@ func (s *chainKvStore) GetBatch(ctx context.Context, keys interface{}, values in
 		}
 	}
 
-	// store missing keys
-	if len(todo) > 0 {
+	if len(todo) > 0 && s.options.loader != nil {
+		// the loader caches the keys it doesn't know on its own
+		if todo, err = s.loadBatch(ctx, todo, values); err != nil {
+			return nil, err
+		}
+	} else if len(todo) > 0 {
+		// store missing keys
 		missingValues := make(map[interface{}]interface{}, len(todo))
 
 		for _, key := range todo {
@@ -264,10 +313,24 @@ func (s *chainKvStore) GetBatch(ctx context.Context, keys interface{}, values in
 }
 
 func (s *chainKvStore) Put(ctx context.Context, key interface{}, value interface{}) error {
+	return s.put(ctx, key, func(store KvStore) error {
+		return store.Put(ctx, key, value)
+	})
+}
+
+// PutWithTtl writes the value with the same ttl to all elements of the chain. Values propagated
+// to the lower levels of the chain on read are written with the ttl of the store again.
+func (s *chainKvStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	return s.put(ctx, key, func(store KvStore) error {
+		return store.PutWithTtl(ctx, key, value, ttl)
+	})
+}
+
+func (s *chainKvStore) put(ctx context.Context, key interface{}, put func(store KvStore) error) error {
 	lastElementIndex := len(s.chain) - 1
 
 	for i := 0; i <= lastElementIndex; i++ {
-		err := s.chain[i].Put(ctx, key, value)
+		err := put(s.chain[i])
 
 		if err != nil {
 			// return error only if last element fails
@@ -286,6 +349,8 @@ func (s *chainKvStore) Put(ctx context.Context, key interface{}, value interface
 		s.logger.WithContext(ctx).Warnf("could not erase cached empty value for key %s: %s", key, err.Error())
 	}
 
+	s.publishInvalidation(ctx, []interface{}{key})
+
 	return nil
 }
 
@@ -311,12 +376,18 @@ func (s *chainKvStore) PutBatch(ctx context.Context, values interface{}) error {
 		}
 	}
 
+	keys := make([]interface{}, 0, len(mii))
+
 	for key := range mii {
+		keys = append(keys, key)
+
 		if err := s.missingCache.Delete(ctx, key); err != nil {
 			s.logger.WithContext(ctx).Warnf("could not erase cached empty value for key %T %v: %s", key, key, err.Error())
 		}
 	}
 
+	s.publishInvalidation(ctx, keys)
+
 	return nil
 }
 
@@ -332,6 +403,8 @@ func (s *chainKvStore) Delete(ctx context.Context, key interface{}) error {
 		}
 	}
 
+	s.publishInvalidation(ctx, []interface{}{key})
+
 	return nil
 }
 
@@ -347,5 +420,62 @@ func (s *chainKvStore) DeleteBatch(ctx context.Context, keys interface{}) error
 		}
 	}
 
+	keySlice, err := refl.InterfaceToInterfaceSlice(keys)
+
+	if err != nil {
+		return fmt.Errorf("can not morph keys to slice of interfaces: %w", err)
+	}
+
+	s.publishInvalidation(ctx, keySlice)
+
+	return nil
+}
+
+// the change is already persisted, so a failed invalidation only leaves other instances with stale values until they expire
+func (s *chainKvStore) publishInvalidation(ctx context.Context, keys []interface{}) {
+	if len(keys) == 0 {
+		return
+	}
+
+	if err := s.invalidation.Publish(ctx, keys); err != nil {
+		s.logger.WithContext(ctx).Warnf("could not publish invalidation of %d keys: %s", len(keys), err.Error())
+	}
+}
+
+// evict removes the keys from all elements besides the last one, which is shared by all instances
+func (s *chainKvStore) evict(ctx context.Context, keys []string) {
+	lastElementIndex := len(s.chain) - 1
+
+	for i := 0; i < lastElementIndex; i++ {
+		if err := s.chain[i].DeleteBatch(ctx, keys); err != nil {
+			s.logger.WithContext(ctx).Warnf("could not evict %d keys from kvstore %T: %s", len(keys), s.chain[i], err.Error())
+		}
+	}
+
+	if err := s.missingCache.DeleteBatch(ctx, keys); err != nil {
+		s.logger.WithContext(ctx).Warnf("could not evict %d keys from missing value cache: %s", len(keys), err.Error())
+	}
+}
+
+// FlushPrefix flushes the prefix from all elements of the chain and the missing value cache. Other
+// instances are not invalidated, as we don't know the flushed keys.
+func (s *chainKvStore) FlushPrefix(ctx context.Context, prefix string) error {
+	stores := make([]KvStore, 0, len(s.chain)+1)
+	stores = append(stores, s.chain...)
+	stores = append(stores, s.missingCache)
+
+	for _, store := range stores {
+		flusher, ok := store.(PrefixFlusher)
+
+		if !ok {
+			return fmt.Errorf("the kvstore %T can not flush a prefix", store)
+		}
+
+		// like on delete, we can't leave something in a cache but not in the backend store
+		if err := flusher.FlushPrefix(ctx, prefix); err != nil {
+			return fmt.Errorf("could not flush prefix %s from kvstore %T: %w", prefix, store, err)
+		}
+	}
+
 	return nil
 }
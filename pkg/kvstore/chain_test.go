@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestChainKvStore_Contains(t *testing.T) {
@@ -370,6 +371,25 @@ func TestChainKvStore_Put(t *testing.T) {
 	element1.AssertExpectations(t)
 }
 
+func TestChainKvStore_PutWithTtl(t *testing.T) {
+	ctx := context.Background()
+	item := Item{
+		Id:   "foo",
+		Body: "bar",
+	}
+
+	store, element0, element1 := buildTestableChainStore(false)
+
+	element0.On("PutWithTtl", ctx, "foo", item, time.Minute).Return(nil).Once()
+	element1.On("PutWithTtl", ctx, "foo", item, time.Minute).Return(nil).Once()
+
+	err := store.PutWithTtl(ctx, "foo", item, time.Minute)
+
+	assert.NoError(t, err)
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+}
+
 func TestChainKvStore_PutBatch(t *testing.T) {
 	ctx := context.Background()
 	items := map[string]Item{
@@ -0,0 +1,205 @@
+package kvstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/klauspost/compress/zstd"
+	"io/ioutil"
+)
+
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+const (
+	// marshaled size of the written values
+	metricNameKvStoreValueSize = "kvStoreValueSize"
+	// stored size of the compressed values in percent of their marshaled size
+	metricNameKvStoreCompressionRatio = "kvStoreCompressionRatio"
+)
+
+// CompressionSettings configure the compression of the values of stores serializing them, like the redis and ddb
+// stores. Values are compressed only if their marshaled size reaches the threshold. Compressed values are stored
+// as binary data prefixed with a marker of the algorithm, so stores can read them after changing the settings, too.
+type CompressionSettings struct {
+	Algorithm string
+	Threshold int
+}
+
+type valueCompressor interface {
+	compress(data []byte) ([]byte, error)
+	decompress(data []byte) ([]byte, error)
+}
+
+var valueCompressors = map[string]valueCompressor{
+	CompressionGzip: gzipValueCompressor{},
+	CompressionZstd: zstdValueCompressor{},
+}
+
+// no valid json value starts with a tilde, so we can't confuse an uncompressed value with a compressed one
+func compressionMarker(algorithm string) []byte {
+	return []byte(fmt.Sprintf("~%s:", algorithm))
+}
+
+func checkCompressionSettings(settings CompressionSettings) error {
+	if settings.Algorithm == "" || settings.Algorithm == CompressionNone {
+		return nil
+	}
+
+	if _, ok := valueCompressors[settings.Algorithm]; !ok {
+		return fmt.Errorf("there is no kvstore compression %s", settings.Algorithm)
+	}
+
+	return nil
+}
+
+type valueCodec struct {
+	settings     CompressionSettings
+	compressor   valueCompressor
+	metricWriter mon.MetricWriter
+	model        string
+	store        string
+}
+
+func newValueCodec(settings *Settings, store string) *valueCodec {
+	codec := &valueCodec{
+		settings:   settings.Compression,
+		compressor: valueCompressors[settings.Compression.Algorithm],
+		store:      store,
+	}
+
+	if settings.MetricsEnabled {
+		codec.metricWriter = mon.NewMetricDaemonWriter()
+		codec.model = (&mdl.ModelId{
+			Project:     settings.Project,
+			Environment: settings.Environment,
+			Family:      settings.Family,
+			Application: settings.Application,
+			Name:        settings.Name,
+		}).String()
+	}
+
+	return codec
+}
+
+// encode marshals the value and compresses it if configured. It returns whether the value was compressed, as
+// compressed values are binary data, which has to be stored in a binary attribute by some stores.
+func (c *valueCodec) encode(value interface{}) ([]byte, bool, error) {
+	data, err := Marshal(value)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if c.compressor == nil || len(data) < c.settings.Threshold {
+		c.record(len(data), 0)
+
+		return data, false, nil
+	}
+
+	compressed, err := c.compressor.compress(data)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("can not compress value with %s: %w", c.settings.Algorithm, err)
+	}
+
+	encoded := append(compressionMarker(c.settings.Algorithm), compressed...)
+	c.record(len(data), len(encoded))
+
+	return encoded, true, nil
+}
+
+func (c *valueCodec) decode(data []byte, value interface{}) error {
+	for algorithm, compressor := range valueCompressors {
+		marker := compressionMarker(algorithm)
+
+		if !bytes.HasPrefix(data, marker) {
+			continue
+		}
+
+		decompressed, err := compressor.decompress(data[len(marker):])
+
+		if err != nil {
+			return fmt.Errorf("can not decompress value with %s: %w", algorithm, err)
+		}
+
+		return Unmarshal(decompressed, value)
+	}
+
+	return Unmarshal(data, value)
+}
+
+func (c *valueCodec) record(size int, storedSize int) {
+	if c.metricWriter == nil {
+		return
+	}
+
+	data := mon.MetricData{
+		c.datum(metricNameKvStoreValueSize, float64(size), mon.UnitBytesAverage),
+	}
+
+	if storedSize > 0 && size > 0 {
+		data = append(data, c.datum(metricNameKvStoreCompressionRatio, float64(storedSize)/float64(size)*100, mon.UnitPercentAverage))
+	}
+
+	c.metricWriter.Write(data)
+}
+
+func (c *valueCodec) datum(metric string, value float64, unit string) *mon.MetricDatum {
+	return &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metric,
+		Dimensions: map[string]string{
+			"model": c.model,
+			"store": c.store,
+		},
+		Value: value,
+		Unit:  unit,
+	}
+}
+
+type gzipValueCompressor struct{}
+
+func (g gzipValueCompressor) compress(data []byte) ([]byte, error) {
+	out := &bytes.Buffer{}
+	writer := gzip.NewWriter(out)
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("can not write to gzip writer: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("can not close gzip writer: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+func (g gzipValueCompressor) decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create gzip reader: %w", err)
+	}
+
+	return ioutil.ReadAll(reader)
+}
+
+// the encoder and decoder are safe for concurrent use of EncodeAll and DecodeAll, so they are shared by all stores
+var zstdEncoder, _ = zstd.NewWriter(nil)
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+type zstdValueCompressor struct{}
+
+func (z zstdValueCompressor) compress(data []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(data, nil), nil
+}
+
+func (z zstdValueCompressor) decompress(data []byte) ([]byte, error) {
+	return zstdDecoder.DecodeAll(data, nil)
+}
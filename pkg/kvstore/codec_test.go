@@ -0,0 +1,170 @@
+package kvstore_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"github.com/applike/gosoline/pkg/ddb"
+	ddbMocks "github.com/applike/gosoline/pkg/ddb/mocks"
+	"github.com/applike/gosoline/pkg/kvstore"
+	redisMocks "github.com/applike/gosoline/pkg/redis/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedisKvStore_Compression(t *testing.T) {
+	tests := map[string]struct {
+		algorithm string
+		body      string
+		prefix    string
+	}{
+		"gzip": {
+			algorithm: kvstore.CompressionGzip,
+			body:      strings.Repeat("bar", 100),
+			prefix:    "~gzip:",
+		},
+		"zstd": {
+			algorithm: kvstore.CompressionZstd,
+			body:      strings.Repeat("bar", 100),
+			prefix:    "~zstd:",
+		},
+		"below threshold": {
+			algorithm: kvstore.CompressionGzip,
+			body:      "bar",
+			prefix:    `{"id":"foo"`,
+		},
+		"none": {
+			algorithm: kvstore.CompressionNone,
+			body:      strings.Repeat("bar", 100),
+			prefix:    `{"id":"foo"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := new(redisMocks.Client)
+
+			store := kvstore.NewRedisKvStoreWithInterfaces(client, &kvstore.Settings{
+				Name:      "test",
+				BatchSize: 100,
+				Compression: kvstore.CompressionSettings{
+					Algorithm: test.algorithm,
+					Threshold: 100,
+				},
+			})
+
+			var stored []byte
+
+			client.On("Set", ctx, "---kvstore-test-foo", mock.AnythingOfType("[]uint8"), time.Duration(0)).Run(func(args mock.Arguments) {
+				stored = args.Get(2).([]byte)
+			}).Return(nil).Once()
+
+			item := Item{
+				Id:   "foo",
+				Body: test.body,
+			}
+
+			err := store.Put(ctx, "foo", item)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(stored), test.prefix), "the stored value %s should start with %s", stored, test.prefix)
+
+			client.On("Get", ctx, "---kvstore-test-foo").Return(string(stored), nil).Once()
+
+			read := Item{}
+			found, err := store.Get(ctx, "foo", &read)
+
+			assert.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, item, read)
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisKvStore_Compression_ReadOtherFormats(t *testing.T) {
+	ctx := context.Background()
+	client := new(redisMocks.Client)
+
+	store := kvstore.NewRedisKvStoreWithInterfaces(client, &kvstore.Settings{
+		Name:      "test",
+		BatchSize: 100,
+	})
+
+	compressed := &bytes.Buffer{}
+	writer := gzip.NewWriter(compressed)
+	_, err := writer.Write([]byte(`{"id":"foo","body":"bar"}`))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	client.On("Get", ctx, "---kvstore-test-foo").Return("~gzip:"+compressed.String(), nil).Once()
+	client.On("Get", ctx, "---kvstore-test-fuu").Return(`{"id":"fuu","body":"baz"}`, nil).Once()
+
+	item := Item{}
+	found, err := store.Get(ctx, "foo", &item)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Item{Id: "foo", Body: "bar"}, item)
+
+	found, err = store.Get(ctx, "fuu", &item)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Item{Id: "fuu", Body: "baz"}, item)
+
+	client.AssertExpectations(t)
+}
+
+func TestDdbKvStore_Compression(t *testing.T) {
+	ctx := context.Background()
+	repo := new(ddbMocks.Repository)
+
+	store := kvstore.NewDdbKvStoreWithInterfaces(repo, &kvstore.Settings{
+		Name:      "test",
+		BatchSize: 100,
+		Compression: kvstore.CompressionSettings{
+			Algorithm: kvstore.CompressionZstd,
+			Threshold: 100,
+		},
+	})
+
+	var stored *kvstore.DdbItem
+
+	repo.On("PutItem", ctx, nil, mock.AnythingOfType("*kvstore.DdbItem")).Run(func(args mock.Arguments) {
+		stored = args.Get(2).(*kvstore.DdbItem)
+	}).Return(nil, nil).Once()
+
+	item := Item{
+		Id:   "foo",
+		Body: strings.Repeat("bar", 100),
+	}
+
+	err := store.Put(ctx, "foo", item)
+	assert.NoError(t, err)
+	assert.Empty(t, stored.Value)
+	assert.True(t, bytes.HasPrefix(stored.CompressedValue, []byte("~zstd:")), "the stored value should start with the zstd marker")
+
+	builder := new(ddbMocks.GetItemBuilder)
+	builder.On("WithHash", "foo").Return(builder).Once()
+	builder.On("DisableTtlFilter").Return(builder)
+
+	repo.On("GetItemBuilder").Return(builder)
+	repo.On("GetItem", ctx, builder, mock.AnythingOfType("*kvstore.DdbItem")).Run(func(args mock.Arguments) {
+		*args.Get(2).(*kvstore.DdbItem) = *stored
+	}).Return(&ddb.GetItemResult{
+		IsFound: true,
+	}, nil).Once()
+
+	read := Item{}
+	found, err := store.Get(ctx, "foo", &read)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, item, read)
+	repo.AssertExpectations(t)
+	builder.AssertExpectations(t)
+}
@@ -15,16 +15,19 @@ const (
 )
 
 type ChainConfiguration struct {
-	Project             string                `cfg:"project"`
-	Family              string                `cfg:"family"`
-	Application         string                `cfg:"application"`
-	Type                string                `cfg:"type" default:"chain" validate:"eq=chain"`
-	Elements            []string              `cfg:"elements" validate:"min=1"`
-	Ttl                 time.Duration         `cfg:"ttl"`
-	BatchSize           int                   `cfg:"batch_size" default:"100" validate:"min=1"`
-	MissingCacheEnabled bool                  `cfg:"missing_cache_enabled" default:"false"`
-	MetricsEnabled      bool                  `cfg:"metrics_enabled" default:"false"`
-	InMemory            InMemoryConfiguration `cfg:"in_memory"`
+	Project             string                    `cfg:"project"`
+	Family              string                    `cfg:"family"`
+	Application         string                    `cfg:"application"`
+	Type                string                    `cfg:"type" default:"chain" validate:"eq=chain"`
+	Elements            []string                  `cfg:"elements" validate:"min=1"`
+	Ttl                 time.Duration             `cfg:"ttl"`
+	BatchSize           int                       `cfg:"batch_size" default:"100" validate:"min=1"`
+	MissingCacheEnabled bool                      `cfg:"missing_cache_enabled" default:"false"`
+	MetricsEnabled      bool                      `cfg:"metrics_enabled" default:"false"`
+	InMemory            InMemoryConfiguration     `cfg:"in_memory"`
+	Redis               RedisConfiguration        `cfg:"redis"`
+	Invalidation        InvalidationConfiguration `cfg:"invalidation"`
+	Compression         CompressionConfiguration  `cfg:"compression"`
 }
 
 type InMemoryConfiguration struct {
@@ -36,19 +39,33 @@ type InMemoryConfiguration struct {
 	GetsPerPromote int32  `cfg:"gets_per_promote" default:"3"`
 }
 
-func NewConfigurableKvStore(config cfg.Config, logger mon.Logger, name string) (KvStore, error) {
+// The redis mode, addresses and master name default to the settings of the redis client kvstore_<name>
+type RedisConfiguration struct {
+	Mode          string   `cfg:"mode"`
+	Addresses     []string `cfg:"addresses"`
+	MasterName    string   `cfg:"master_name"`
+	KeySerializer string   `cfg:"key_serializer" default:"default"`
+}
+
+// Compression of the values stored by the redis and ddb elements
+type CompressionConfiguration struct {
+	Algorithm string `cfg:"algorithm" default:"none" validate:"oneof=none gzip zstd"`
+	Threshold int    `cfg:"threshold" default:"1024" validate:"min=0"`
+}
+
+func NewConfigurableKvStore(config cfg.Config, logger mon.Logger, name string, options ...ChainOption) (KvStore, error) {
 	key := fmt.Sprintf("kvstore.%s.type", name)
 	t := config.GetString(key)
 
 	switch t {
 	case TypeChain:
-		return newKvStoreChainFromConfig(config, logger, name)
+		return newKvStoreChainFromConfig(config, logger, name, options...)
 	}
 
 	return nil, fmt.Errorf("invalid kvstore %s of type %s", name, t)
 }
 
-func newKvStoreChainFromConfig(config cfg.Config, logger mon.Logger, name string) (KvStore, error) {
+func newKvStoreChainFromConfig(config cfg.Config, logger mon.Logger, name string, options ...ChainOption) (KvStore, error) {
 	key := GetConfigurableKey(name)
 
 	configuration := ChainConfiguration{}
@@ -72,7 +89,17 @@ func newKvStoreChainFromConfig(config cfg.Config, logger mon.Logger, name string
 			PromoteBuffer:  configuration.InMemory.PromoteBuffer,
 			GetsPerPromote: configuration.InMemory.GetsPerPromote,
 		},
-	})
+		Redis: RedisSettings{
+			Mode:          configuration.Redis.Mode,
+			Addresses:     configuration.Redis.Addresses,
+			MasterName:    configuration.Redis.MasterName,
+			KeySerializer: configuration.Redis.KeySerializer,
+		},
+		Compression: CompressionSettings{
+			Algorithm: configuration.Compression.Algorithm,
+			Threshold: configuration.Compression.Threshold,
+		},
+	}, options...)
 	if err != nil {
 		return nil, fmt.Errorf("can not create chain store: %w", err)
 	}
@@ -97,6 +124,15 @@ func newKvStoreChainFromConfig(config cfg.Config, logger mon.Logger, name string
 
 	}
 
+	if configuration.Invalidation.Enabled {
+		publisher, err := NewInvalidationPublisher(config, logger, name)
+		if err != nil {
+			return nil, fmt.Errorf("can not create invalidation publisher: %w", err)
+		}
+
+		store.EnableInvalidation(publisher)
+	}
+
 	return store, nil
 }
 
@@ -4,16 +4,35 @@ import (
 	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
 	"github.com/applike/gosoline/pkg/ddb"
 	"github.com/applike/gosoline/pkg/mdl"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/refl"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"sort"
+	"time"
 )
 
 type DdbItem struct {
 	Key   string `json:"key" ddb:"key=hash"`
 	Value string `json:"value"`
+	// compressed values are binary data, which can't be stored in the string attribute
+	CompressedValue []byte `json:"compressedValue,omitempty"`
+	// unix timestamp after which the item expires, only set for values written with PutWithTtl
+	Ttl int64 `json:"ttl,omitempty" ddb:"ttl=enabled"`
+}
+
+func (i *DdbItem) expired(now time.Time) bool {
+	return i.Ttl != 0 && i.Ttl <= now.Unix()
+}
+
+func (i *DdbItem) data() []byte {
+	if len(i.CompressedValue) > 0 {
+		return i.CompressedValue
+	}
+
+	return []byte(i.Value)
 }
 
 type DdbDeleteItem struct {
@@ -22,6 +41,8 @@ type DdbDeleteItem struct {
 
 type ddbKvStore struct {
 	repository ddb.Repository
+	clock      clock.Clock
+	codec      *valueCodec
 	settings   *Settings
 }
 
@@ -33,6 +54,10 @@ func NewDdbKvStore(config cfg.Config, logger mon.Logger, settings *Settings) (Kv
 	settings.PadFromConfig(config)
 	name := DdbBaseName(settings)
 
+	if err := checkCompressionSettings(settings.Compression); err != nil {
+		return nil, err
+	}
+
 	repository, err := ddb.NewRepository(config, logger, &ddb.Settings{
 		ModelId: mdl.ModelId{
 			Project:     settings.Project,
@@ -57,6 +82,8 @@ func NewDdbKvStore(config cfg.Config, logger mon.Logger, settings *Settings) (Kv
 func NewDdbKvStoreWithInterfaces(repository ddb.Repository, settings *Settings) KvStore {
 	return NewMetricStoreWithInterfaces(&ddbKvStore{
 		repository: repository,
+		clock:      clock.Provider,
+		codec:      newValueCodec(settings, "*kvstore.ddbKvStore"),
 		settings:   settings,
 	}, settings)
 }
@@ -69,14 +96,14 @@ func (s *ddbKvStore) Contains(ctx context.Context, key interface{}) (bool, error
 	}
 
 	item := &DdbItem{}
-	qb := s.repository.GetItemBuilder().WithHash(keyStr)
+	qb := s.getItemBuilder(keyStr)
 	res, err := s.repository.GetItem(ctx, qb, item)
 
 	if err != nil {
 		return false, fmt.Errorf("can not check if ddb store contains the key %s: %w", keyStr, err)
 	}
 
-	return res.IsFound, nil
+	return res.IsFound && !item.expired(s.clock.Now()), nil
 }
 
 func (s *ddbKvStore) Get(ctx context.Context, key interface{}, value interface{}) (bool, error) {
@@ -86,7 +113,7 @@ func (s *ddbKvStore) Get(ctx context.Context, key interface{}, value interface{}
 		return false, fmt.Errorf("can not cast key %T %v to string: %w", key, key, err)
 	}
 
-	qb := s.repository.GetItemBuilder().WithHash(keyStr)
+	qb := s.getItemBuilder(keyStr)
 
 	item := &DdbItem{}
 	res, err := s.repository.GetItem(ctx, qb, item)
@@ -95,12 +122,11 @@ func (s *ddbKvStore) Get(ctx context.Context, key interface{}, value interface{}
 		return false, fmt.Errorf("can not get item %s from ddb store: %w", keyStr, err)
 	}
 
-	if !res.IsFound {
+	if !res.IsFound || item.expired(s.clock.Now()) {
 		return false, nil
 	}
 
-	bytes := []byte(item.Value)
-	err = Unmarshal(bytes, value)
+	err = s.codec.decode(item.data(), value)
 
 	if err != nil {
 		return false, fmt.Errorf("can not unmarshal value for item %s: %w", keyStr, err)
@@ -130,8 +156,10 @@ func (s *ddbKvStore) getChunk(ctx context.Context, resultMap *refl.Map, keys []i
 		keyMapToOriginal[keyStr] = keys[i]
 	}
 
+	// items without ttl would be filtered by the ttl filter, so we check the expiration on our own
 	qb := s.repository.BatchGetItemsBuilder()
 	qb.WithHashKeys(keyStrings)
+	qb.DisableTtlFilter()
 	items := make([]DdbItem, 0)
 
 	_, err = s.repository.BatchGetItems(ctx, qb, &items)
@@ -141,12 +169,17 @@ func (s *ddbKvStore) getChunk(ctx context.Context, resultMap *refl.Map, keys []i
 	}
 
 	found := make(map[string]bool)
+	now := s.clock.Now()
 
 	for i := 0; i < len(items); i++ {
+		if items[i].expired(now) {
+			continue
+		}
+
 		found[items[i].Key] = true
 
 		element := resultMap.NewElement()
-		err = Unmarshal([]byte(items[i].Value), element)
+		err = s.codec.decode(items[i].data(), element)
 
 		if err != nil {
 			return nil, fmt.Errorf("can not unmarshal item: %w", err)
@@ -170,21 +203,30 @@ func (s *ddbKvStore) getChunk(ctx context.Context, resultMap *refl.Map, keys []i
 }
 
 func (s *ddbKvStore) Put(ctx context.Context, key interface{}, value interface{}) error {
+	return s.put(ctx, key, value, 0)
+}
+
+// PutWithTtl writes the expiration to the ttl attribute of the item, so dynamodb removes the item some time after
+// it expired. Until then, we filter it on read. The ttl of the store is not applied to ddb stores.
+func (s *ddbKvStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	return s.put(ctx, key, value, ttl)
+}
+
+func (s *ddbKvStore) put(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
 	keyStr, err := CastKeyToString(key)
 
 	if err != nil {
 		return fmt.Errorf("can not cast key %T %v to string: %w", key, key, err)
 	}
 
-	bytes, err := Marshal(value)
+	item, err := s.buildItem(keyStr, value)
 
 	if err != nil {
-		return fmt.Errorf("can not marshal value %s: %w", keyStr, err)
+		return err
 	}
 
-	item := &DdbItem{
-		Key:   keyStr,
-		Value: string(bytes),
+	if ttl > 0 {
+		item.Ttl = s.clock.Now().Add(ttl).Unix()
 	}
 
 	_, err = s.repository.PutItem(ctx, nil, item)
@@ -224,18 +266,13 @@ func (s *ddbKvStore) PutBatch(ctx context.Context, values interface{}) error {
 		key := keyMap[keyStr]
 		value := mii[key]
 
-		bytes, err := Marshal(value)
+		item, err := s.buildItem(keyStr, value)
 
 		if err != nil {
-			return fmt.Errorf("can not marshal value %s: %w", keyStr, err)
+			return err
 		}
 
-		item := DdbItem{
-			Key:   keyStr,
-			Value: string(bytes),
-		}
-
-		items = append(items, item)
+		items = append(items, *item)
 	}
 
 	_, err = s.repository.BatchPutItems(ctx, items)
@@ -247,6 +284,31 @@ func (s *ddbKvStore) PutBatch(ctx context.Context, values interface{}) error {
 	return nil
 }
 
+func (s *ddbKvStore) buildItem(keyStr string, value interface{}) (*DdbItem, error) {
+	data, compressed, err := s.codec.encode(value)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal value %s: %w", keyStr, err)
+	}
+
+	if compressed {
+		return &DdbItem{
+			Key:             keyStr,
+			CompressedValue: data,
+		}, nil
+	}
+
+	return &DdbItem{
+		Key:   keyStr,
+		Value: string(data),
+	}, nil
+}
+
+func (s *ddbKvStore) getItemBuilder(keyStr string) ddb.GetItemBuilder {
+	// items without ttl would be filtered by the ttl filter, so we check the expiration on our own
+	return s.repository.GetItemBuilder().WithHash(keyStr).DisableTtlFilter()
+}
+
 func (s *ddbKvStore) Delete(ctx context.Context, key interface{}) error {
 	keyStr, err := CastKeyToString(key)
 
@@ -294,3 +356,26 @@ func (s *ddbKvStore) DeleteBatch(ctx context.Context, keys interface{}) error {
 
 	return nil
 }
+
+// FlushPrefix scans the whole table for the keys with the prefix, so it is expensive for large stores
+func (s *ddbKvStore) FlushPrefix(ctx context.Context, prefix string) error {
+	qb := s.repository.ScanBuilder().
+		WithFilter(expression.Name("key").BeginsWith(prefix)).
+		DisableTtlFilter()
+
+	items := make([]*DdbDeleteItem, 0)
+
+	if _, err := s.repository.Scan(ctx, qb, &items); err != nil {
+		return fmt.Errorf("can not scan for keys with prefix %s: %w", prefix, err)
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	if _, err := s.repository.BatchDeleteItems(ctx, items); err != nil {
+		return fmt.Errorf("can not delete keys with prefix %s from ddb store: %w", prefix, err)
+	}
+
+	return nil
+}
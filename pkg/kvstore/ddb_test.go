@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestDdbKvStore_Contains(t *testing.T) {
@@ -16,6 +17,7 @@ func TestDdbKvStore_Contains(t *testing.T) {
 
 	builder := new(ddbMocks.GetItemBuilder)
 	builder.On("WithHash", "foo").Return(builder).Once()
+	builder.On("DisableTtlFilter").Return(builder)
 
 	repo.On("GetItemBuilder").Return(builder)
 	repo.On("GetItem", mock.AnythingOfType("*context.emptyCtx"), builder, mock.AnythingOfType("*kvstore.DdbItem")).Return(&ddb.GetItemResult{
@@ -44,6 +46,7 @@ func TestDdbKvStore_Get(t *testing.T) {
 
 	builder := new(ddbMocks.GetItemBuilder)
 	builder.On("WithHash", "foo").Return(builder).Once()
+	builder.On("DisableTtlFilter").Return(builder)
 
 	ddbItem := &kvstore.DdbItem{
 		Key:   "",
@@ -79,6 +82,7 @@ func TestDdbKvStore_GetBatch(t *testing.T) {
 
 	builder := new(ddbMocks.BatchGetItemsBuilder)
 	builder.On("WithHashKeys", keys).Return(builder)
+	builder.On("DisableTtlFilter").Return(builder)
 
 	items := make([]kvstore.DdbItem, 0)
 
@@ -113,6 +117,7 @@ func TestDdbKvStore_GetBatch_ReturnedKeysInDifferentOrder(t *testing.T) {
 
 	builder := new(ddbMocks.BatchGetItemsBuilder)
 	builder.On("WithHashKeys", []string{"foo", "fuu"}).Return(builder)
+	builder.On("DisableTtlFilter").Return(builder)
 
 	items := make([]kvstore.DdbItem, 0)
 
@@ -156,6 +161,7 @@ func TestDdbKvStore_GetBatch_WithDuplicateKeys(t *testing.T) {
 
 	builder := new(ddbMocks.BatchGetItemsBuilder)
 	builder.On("WithHashKeys", []string{"foo", "fuu"}).Return(builder)
+	builder.On("DisableTtlFilter").Return(builder)
 
 	items := make([]kvstore.DdbItem, 0)
 
@@ -204,6 +210,55 @@ func TestDdbKvStore_Put(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestDdbKvStore_PutWithTtl(t *testing.T) {
+	store, repo := buildTestableDdbStore()
+
+	repo.On("PutItem", mock.AnythingOfType("*context.emptyCtx"), nil, mock.AnythingOfType("*kvstore.DdbItem")).Run(func(args mock.Arguments) {
+		ddbItem := args[2].(*kvstore.DdbItem)
+
+		assert.Equal(t, "foo", ddbItem.Key)
+		assert.Equal(t, `{"id":"foo","body":"bar"}`, ddbItem.Value)
+		assert.InDelta(t, time.Now().Add(time.Hour).Unix(), ddbItem.Ttl, 5)
+	}).Return(nil, nil)
+
+	item := &Item{
+		Id:   "foo",
+		Body: "bar",
+	}
+
+	err := store.PutWithTtl(context.Background(), "foo", item, time.Hour)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDdbKvStore_Get_Expired(t *testing.T) {
+	store, repo := buildTestableDdbStore()
+
+	builder := new(ddbMocks.GetItemBuilder)
+	builder.On("WithHash", "foo").Return(builder).Once()
+	builder.On("DisableTtlFilter").Return(builder)
+
+	repo.On("GetItemBuilder").Return(builder)
+	repo.On("GetItem", mock.AnythingOfType("*context.emptyCtx"), builder, mock.AnythingOfType("*kvstore.DdbItem")).Run(func(args mock.Arguments) {
+		ddbItem := args[2].(*kvstore.DdbItem)
+		ddbItem.Key = "foo"
+		ddbItem.Value = `{"id":"foo","body":"bar"}`
+		ddbItem.Ttl = time.Now().Add(-time.Minute).Unix()
+	}).Return(&ddb.GetItemResult{
+		IsFound: true,
+	}, nil).Once()
+
+	item := &Item{}
+	found, err := store.Get(context.Background(), "foo", item)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	builder.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
 func TestDdbKvStore_PutBatch(t *testing.T) {
 	store, repo := buildTestableDdbStore()
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/refl"
+	"time"
 )
 
 type emptyKvStore struct {
@@ -39,6 +40,10 @@ func (s *emptyKvStore) Put(_ context.Context, _ interface{}, _ interface{}) erro
 	return nil
 }
 
+func (s *emptyKvStore) PutWithTtl(_ context.Context, _ interface{}, _ interface{}, _ time.Duration) error {
+	return nil
+}
+
 func (s *emptyKvStore) PutBatch(_ context.Context, _ interface{}) error {
 	return nil
 }
@@ -50,3 +55,7 @@ func (s *emptyKvStore) Delete(_ context.Context, _ interface{}) error {
 func (s *emptyKvStore) DeleteBatch(_ context.Context, _ interface{}) error {
 	return nil
 }
+
+func (s *emptyKvStore) FlushPrefix(_ context.Context, _ string) error {
+	return nil
+}
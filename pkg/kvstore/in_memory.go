@@ -10,6 +10,7 @@ import (
 	"github.com/karlseguin/ccache"
 	"math/bits"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -18,6 +19,8 @@ type InMemoryKvStore struct {
 	cache     *ccache.Cache
 	settings  *Settings
 	cacheSize *int64
+	// serializes the conditional puts, as the cache has no atomic operation for them
+	putIfAbsentLck sync.Mutex
 }
 
 func NewInMemoryKvStore(_ cfg.Config, _ mon.Logger, settings *Settings) (KvStore, error) {
@@ -147,12 +150,12 @@ func (s *InMemoryKvStore) getChunk(ctx context.Context, resultMap *refl.Map, key
 		}
 
 		if !ok {
-			missing = append(missing, keyString)
+			missing = append(missing, key)
 
 			continue
 		}
 
-		if err := resultMap.Set(keyString, element); err != nil {
+		if err := resultMap.Set(key, element); err != nil {
 			return nil, fmt.Errorf("can not set new element on result map: %w", err)
 		}
 	}
@@ -161,6 +164,18 @@ func (s *InMemoryKvStore) getChunk(ctx context.Context, resultMap *refl.Map, key
 }
 
 func (s *InMemoryKvStore) Put(_ context.Context, key interface{}, value interface{}) error {
+	return s.put(key, value, s.settings.Ttl)
+}
+
+func (s *InMemoryKvStore) PutWithTtl(_ context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = s.settings.Ttl
+	}
+
+	return s.put(key, value, ttl)
+}
+
+func (s *InMemoryKvStore) put(key interface{}, value interface{}, ttl time.Duration) error {
 	keyStr, err := CastKeyToString(key)
 
 	if err != nil {
@@ -175,13 +190,34 @@ func (s *InMemoryKvStore) Put(_ context.Context, key interface{}, value interfac
 		value = rv.Interface()
 	}
 
-	s.cache.Set(keyStr, value, s.settings.Ttl)
+	s.cache.Set(keyStr, value, ttl)
 
 	atomic.AddInt64(s.cacheSize, 1)
 
 	return nil
 }
 
+func (s *InMemoryKvStore) PutIfAbsent(_ context.Context, key interface{}, value interface{}, ttl time.Duration) (bool, error) {
+	keyStr, err := CastKeyToString(key)
+
+	if err != nil {
+		return false, fmt.Errorf("can not build string key %T %v: %w", key, key, err)
+	}
+
+	s.putIfAbsentLck.Lock()
+	defer s.putIfAbsentLck.Unlock()
+
+	if item := s.cache.Get(keyStr); item != nil && !item.Expired() {
+		return false, nil
+	}
+
+	if ttl == 0 {
+		ttl = s.settings.Ttl
+	}
+
+	return true, s.put(key, value, ttl)
+}
+
 func (s *InMemoryKvStore) PutBatch(ctx context.Context, values interface{}) error {
 	mii, err := refl.InterfaceToMapInterfaceInterface(values)
 
@@ -229,3 +265,12 @@ func (s *InMemoryKvStore) DeleteBatch(ctx context.Context, keys interface{}) err
 
 	return nil
 }
+
+// FlushPrefix clears the whole store, as the cache can't look up the keys with the prefix. This
+// is fine for a cache, which only has to read the values of the other prefixes again.
+func (s *InMemoryKvStore) FlushPrefix(_ context.Context, _ string) error {
+	s.cache.Clear()
+	atomic.StoreInt64(s.cacheSize, 0)
+
+	return nil
+}
@@ -87,6 +87,47 @@ func (s *InMemoryKvStoreTestSuite) TestStoreStruct() {
 	s.Equal("d", missing[0], "element d should be missing")
 }
 
+func (s *InMemoryKvStoreTestSuite) TestStorePutWithTtl() {
+	ctx := context.Background()
+
+	err := s.store.PutWithTtl(ctx, "short", 1, time.Millisecond)
+	s.NoError(err, "there should be no error on PutWithTtl")
+
+	err = s.store.PutWithTtl(ctx, "long", 2, time.Minute)
+	s.NoError(err, "there should be no error on PutWithTtl")
+
+	time.Sleep(time.Millisecond * 5)
+
+	var v int
+	ok, err := s.store.Get(ctx, "short", &v)
+	s.NoError(err, "there should be no error on Get")
+	s.False(ok, "the item should be expired")
+
+	ok, err = s.store.Get(ctx, "long", &v)
+	s.NoError(err, "there should be no error on Get")
+	s.True(ok, "the item should be in the store")
+	s.Equal(2, v)
+}
+
+func (s *InMemoryKvStoreTestSuite) TestStorePutIfAbsent() {
+	ctx := context.Background()
+	putter := s.store.(kvstore.ConditionalPutter)
+
+	written, err := putter.PutIfAbsent(ctx, "key", 1, 0)
+	s.NoError(err, "there should be no error on PutIfAbsent")
+	s.True(written, "the item should be written")
+
+	written, err = putter.PutIfAbsent(ctx, "key", 2, 0)
+	s.NoError(err, "there should be no error on PutIfAbsent")
+	s.False(written, "the item should not be overwritten")
+
+	var v int
+	ok, err := s.store.Get(ctx, "key", &v)
+	s.NoError(err, "there should be no error on Get")
+	s.True(ok, "the item should be in the store")
+	s.Equal(1, v)
+}
+
 func TestInMemoryKvStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(InMemoryKvStoreTestSuite))
 }
@@ -0,0 +1,197 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/applike/gosoline/pkg/uuid"
+	"sync"
+)
+
+const attributeInvalidationInstance = "kvstoreInstance"
+
+// identifies this process, so we can skip the invalidations we sent on our own
+var invalidationInstance = uuid.New().NewV4()
+
+// An InvalidationMessage is published by a chain store after it changed some keys. All other instances
+// evict these keys from the local layers of their chain store with the same name.
+type InvalidationMessage struct {
+	Store string   `json:"store"`
+	Keys  []string `json:"keys"`
+}
+
+type InvalidationConfiguration struct {
+	Enabled bool `cfg:"enabled" default:"false"`
+}
+
+//go:generate mockery -name InvalidationPublisher
+type InvalidationPublisher interface {
+	Publish(ctx context.Context, keys []interface{}) error
+}
+
+type invalidationPublisher struct {
+	producer stream.Producer
+	store    string
+}
+
+// NewInvalidationPublisher writes the invalidations of the store to the producer kvstore_<name>_invalidation
+func NewInvalidationPublisher(config cfg.Config, logger mon.Logger, store string) (*invalidationPublisher, error) {
+	producer, err := stream.NewProducer(config, logger, InvalidationName(store))
+	if err != nil {
+		return nil, fmt.Errorf("can not create invalidation producer for kvstore %s: %w", store, err)
+	}
+
+	return NewInvalidationPublisherWithInterfaces(producer, store), nil
+}
+
+func NewInvalidationPublisherWithInterfaces(producer stream.Producer, store string) *invalidationPublisher {
+	return &invalidationPublisher{
+		producer: producer,
+		store:    store,
+	}
+}
+
+func (p *invalidationPublisher) Publish(ctx context.Context, keys []interface{}) error {
+	msg := &InvalidationMessage{
+		Store: p.store,
+		Keys:  make([]string, len(keys)),
+	}
+
+	for i, key := range keys {
+		keyStr, err := CastKeyToString(key)
+		if err != nil {
+			return fmt.Errorf("can not cast key %T %v to string: %w", key, key, err)
+		}
+
+		msg.Keys[i] = keyStr
+	}
+
+	return p.producer.WriteOne(ctx, msg, map[string]interface{}{
+		attributeInvalidationInstance: invalidationInstance,
+	})
+}
+
+type noopInvalidationPublisher struct{}
+
+func (p noopInvalidationPublisher) Publish(_ context.Context, _ []interface{}) error {
+	return nil
+}
+
+var invalidationTargets = struct {
+	sync.Mutex
+	stores map[string][]*chainKvStore
+}{
+	stores: make(map[string][]*chainKvStore),
+}
+
+func registerInvalidationTarget(store *chainKvStore) {
+	invalidationTargets.Lock()
+	defer invalidationTargets.Unlock()
+
+	name := store.settings.Name
+	invalidationTargets.stores[name] = append(invalidationTargets.stores[name], store)
+}
+
+func getInvalidationTargets(name string) []*chainKvStore {
+	invalidationTargets.Lock()
+	defer invalidationTargets.Unlock()
+
+	return invalidationTargets.stores[name]
+}
+
+type invalidationCallback struct {
+	logger mon.Logger
+	store  string
+}
+
+// NewInvalidationCallback evicts the keys of the received invalidations from all chain stores with the given name
+func NewInvalidationCallback(logger mon.Logger, store string) *invalidationCallback {
+	return &invalidationCallback{
+		logger: logger,
+		store:  store,
+	}
+}
+
+func (c *invalidationCallback) GetModel(_ map[string]interface{}) interface{} {
+	return &InvalidationMessage{}
+}
+
+func (c *invalidationCallback) Consume(ctx context.Context, model interface{}, attributes map[string]interface{}) (bool, error) {
+	msg, ok := model.(*InvalidationMessage)
+	if !ok {
+		return false, fmt.Errorf("expected an invalidation message but got %T", model)
+	}
+
+	if instance, ok := attributes[attributeInvalidationInstance]; ok && instance == invalidationInstance {
+		return true, nil
+	}
+
+	if msg.Store != c.store {
+		c.logger.WithContext(ctx).Warnf("skipping invalidation of kvstore %s in consumer of kvstore %s", msg.Store, c.store)
+		return true, nil
+	}
+
+	for _, store := range getInvalidationTargets(msg.Store) {
+		store.evict(ctx, msg.Keys)
+	}
+
+	return true, nil
+}
+
+// runs the invalidation consumer in the background, so it doesn't keep the application alive on its own
+type invalidationModule struct {
+	kernel.Module
+	kernel.BackgroundModule
+	kernel.ApplicationStage
+}
+
+// NewInvalidationModuleFactory creates a consumer for every chain store with invalidation enabled. The consumer
+// kvstore_<name>_invalidation has to read from an input every instance of the application receives all messages on.
+func NewInvalidationModuleFactory(config cfg.Config, _ mon.Logger) (map[string]kernel.ModuleFactory, error) {
+	modules := make(map[string]kernel.ModuleFactory)
+
+	if !config.IsSet("kvstore") {
+		return modules, nil
+	}
+
+	for name := range config.GetStringMap("kvstore") {
+		if config.GetString(fmt.Sprintf("kvstore.%s.type", name)) != TypeChain {
+			continue
+		}
+
+		configuration := ChainConfiguration{}
+		config.UnmarshalKey(GetConfigurableKey(name), &configuration)
+
+		if !configuration.Invalidation.Enabled {
+			continue
+		}
+
+		modules[InvalidationName(name)] = newInvalidationModuleFactory(name)
+	}
+
+	return modules, nil
+}
+
+func newInvalidationModuleFactory(store string) kernel.ModuleFactory {
+	consumerFactory := stream.NewConsumer(InvalidationName(store), func(ctx context.Context, config cfg.Config, logger mon.Logger) (stream.ConsumerCallback, error) {
+		return NewInvalidationCallback(logger, store), nil
+	})
+
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		consumer, err := consumerFactory(ctx, config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("can not create invalidation consumer for kvstore %s: %w", store, err)
+		}
+
+		return &invalidationModule{
+			Module: consumer,
+		}, nil
+	}
+}
+
+func InvalidationName(store string) string {
+	return fmt.Sprintf("kvstore_%s_invalidation", store)
+}
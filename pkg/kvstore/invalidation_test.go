@@ -0,0 +1,144 @@
+package kvstore_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kvstore"
+	kvStoreMocks "github.com/applike/gosoline/pkg/kvstore/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	streamMocks "github.com/applike/gosoline/pkg/stream/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestInvalidationPublisher_Publish(t *testing.T) {
+	ctx := context.Background()
+
+	producer := new(streamMocks.Producer)
+	producer.On("WriteOne", ctx, &kvstore.InvalidationMessage{
+		Store: "test",
+		Keys:  []string{"foo", "1"},
+	}, mock.AnythingOfType("map[string]interface {}")).Return(nil).Once()
+
+	publisher := kvstore.NewInvalidationPublisherWithInterfaces(producer, "test")
+	err := publisher.Publish(ctx, []interface{}{"foo", 1})
+
+	assert.NoError(t, err)
+	producer.AssertExpectations(t)
+}
+
+func TestChainKvStore_Invalidation_Publish(t *testing.T) {
+	ctx := context.Background()
+	item := Item{
+		Id:   "foo",
+		Body: "bar",
+	}
+
+	store, element0, element1, publisher := buildTestableInvalidatingChainStore("publish")
+
+	element0.On("Put", ctx, "foo", item).Return(nil).Once()
+	element1.On("Put", ctx, "foo", item).Return(nil).Once()
+	element0.On("Delete", ctx, "foo").Return(nil).Once()
+	element1.On("Delete", ctx, "foo").Return(nil).Once()
+	publisher.On("Publish", ctx, []interface{}{"foo"}).Return(nil).Twice()
+
+	err := store.Put(ctx, "foo", item)
+	assert.NoError(t, err)
+
+	err = store.Delete(ctx, "foo")
+	assert.NoError(t, err)
+
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestChainKvStore_Invalidation_PublishFailed(t *testing.T) {
+	ctx := context.Background()
+	items := []string{"fuu", "foo"}
+
+	store, element0, element1, publisher := buildTestableInvalidatingChainStore("publish-failed")
+
+	element0.On("DeleteBatch", ctx, items).Return(nil).Once()
+	element1.On("DeleteBatch", ctx, items).Return(nil).Once()
+	publisher.On("Publish", ctx, []interface{}{"fuu", "foo"}).Return(fmt.Errorf("fail")).Once()
+
+	err := store.DeleteBatch(ctx, items)
+
+	assert.NoError(t, err, "the keys are deleted even if we can't publish the invalidation")
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestInvalidationCallback_Consume(t *testing.T) {
+	ctx := context.Background()
+	logger := monMocks.NewLoggerMockedAll()
+	keys := []string{"foo", "bar"}
+
+	_, element0, element1, _ := buildTestableInvalidatingChainStore("consume")
+
+	element0.On("DeleteBatch", ctx, keys).Return(nil).Once()
+
+	callback := kvstore.NewInvalidationCallback(logger, "consume")
+	model := callback.GetModel(map[string]interface{}{})
+
+	msg, ok := model.(*kvstore.InvalidationMessage)
+	assert.True(t, ok)
+
+	msg.Store = "consume"
+	msg.Keys = keys
+
+	acknowledge, err := callback.Consume(ctx, msg, map[string]interface{}{})
+
+	assert.NoError(t, err)
+	assert.True(t, acknowledge)
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+}
+
+func TestInvalidationCallback_Consume_OtherStore(t *testing.T) {
+	ctx := context.Background()
+	logger := monMocks.NewLoggerMockedAll()
+
+	_, element0, element1, _ := buildTestableInvalidatingChainStore("consume-other")
+
+	callback := kvstore.NewInvalidationCallback(logger, "consume-other")
+	acknowledge, err := callback.Consume(ctx, &kvstore.InvalidationMessage{
+		Store: "something-else",
+		Keys:  []string{"foo"},
+	}, map[string]interface{}{})
+
+	assert.NoError(t, err)
+	assert.True(t, acknowledge)
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+}
+
+func buildTestableInvalidatingChainStore(name string) (kvstore.KvStore, *kvStoreMocks.KvStore, *kvStoreMocks.KvStore, *kvStoreMocks.InvalidationPublisher) {
+	logger := monMocks.NewLoggerMockedAll()
+
+	element0 := new(kvStoreMocks.KvStore)
+	element1 := new(kvStoreMocks.KvStore)
+	publisher := new(kvStoreMocks.InvalidationPublisher)
+
+	settings := &kvstore.Settings{
+		AppId: cfg.AppId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "kvstore",
+		},
+		Name:      name,
+		BatchSize: 100,
+	}
+
+	store := kvstore.NewChainKvStoreWithInterfaces(logger, nilFactory, kvstore.NewEmptyKvStore(), settings)
+	store.AddStore(element0)
+	store.AddStore(element1)
+	store.EnableInvalidation(publisher)
+
+	return store, element0, element1, publisher
+}
@@ -17,6 +17,8 @@ type Settings struct {
 	BatchSize      int
 	MetricsEnabled bool
 	InMemorySettings
+	Redis       RedisSettings
+	Compression CompressionSettings
 }
 
 type InMemorySettings struct {
@@ -28,6 +30,14 @@ type InMemorySettings struct {
 	GetsPerPromote int32
 }
 
+// RedisSettings overwrite the settings of the redis client of the store if set
+type RedisSettings struct {
+	Mode          string
+	Addresses     []string
+	MasterName    string
+	KeySerializer string
+}
+
 //go:generate mockery -name KvStore
 type KvStore interface {
 	// Check if a key exists in the store.
@@ -41,6 +51,9 @@ type KvStore interface {
 	GetBatch(ctx context.Context, keys interface{}, values interface{}) ([]interface{}, error)
 	// Write a value to the store
 	Put(ctx context.Context, key interface{}, value interface{}) error
+	// Write a value to the store which expires after the given ttl instead of
+	// the ttl of the store. A ttl of 0 behaves like Put.
+	PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error
 	// Write a batch of values to the store. Values should be something which
 	// can be converted to map[interface{}]interface{}.
 	PutBatch(ctx context.Context, values interface{}) error
@@ -58,6 +71,13 @@ type SizedStore interface {
 	EstimateSize() *int64
 }
 
+// A ConditionalPutter writes a value only if there is no value for the key yet. Checking the key and writing the
+// value happens atomically, so only one of several concurrent writers of the same key succeeds. The value expires
+// after the given ttl, a ttl of 0 uses the ttl of the store.
+type ConditionalPutter interface {
+	PutIfAbsent(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) (bool, error)
+}
+
 type Factory func(config cfg.Config, logger mon.Logger, settings *Settings) (KvStore, error)
 
 func buildFactory(config cfg.Config, logger mon.Logger) func(factory Factory, settings *Settings) (KvStore, error) {
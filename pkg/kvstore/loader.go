@@ -0,0 +1,268 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/refl"
+	"reflect"
+	"time"
+)
+
+// A Loader is called for a key missing in all elements of a chain store. It returns the value to store
+// (like you would pass it to Put) or false if the key does not exist at all.
+type Loader func(ctx context.Context, key interface{}) (value interface{}, found bool, err error)
+
+// A BatchLoader is called once for all keys of a GetBatch missing in all elements of a chain store. It returns the
+// values to store by their keys, keys missing in the result do not exist at all.
+type BatchLoader func(ctx context.Context, keys []interface{}) (values map[interface{}]interface{}, err error)
+
+type chainOptions struct {
+	loader      Loader
+	batchLoader BatchLoader
+	notFoundTtl time.Duration
+}
+
+type ChainOption func(options *chainOptions)
+
+// WithLoader reads missing keys through the loader. Concurrent misses of the same key call the loader only
+// once. Loaded values are written to all elements of the chain. If notFoundTtl is set, keys unknown to the
+// loader are cached in the missing value cache for that long, which is enabled for this if needed.
+func WithLoader(loader Loader, notFoundTtl time.Duration) ChainOption {
+	return func(options *chainOptions) {
+		options.loader = loader
+		options.notFoundTtl = notFoundTtl
+	}
+}
+
+// WithBatchLoader reads the missing keys of a GetBatch with a single call of the batch loader. Single keys are read
+// with the batch loader, too, unless a loader is given with WithLoader. Without a batch loader, the keys of a GetBatch
+// are read one by one with the loader.
+func WithBatchLoader(batchLoader BatchLoader, notFoundTtl time.Duration) ChainOption {
+	return func(options *chainOptions) {
+		options.batchLoader = batchLoader
+		options.notFoundTtl = notFoundTtl
+	}
+}
+
+func buildChainOptions(options []ChainOption) *chainOptions {
+	opts := &chainOptions{}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.loader == nil && opts.batchLoader != nil {
+		opts.loader = singleKeyLoader(opts.batchLoader)
+	}
+
+	return opts
+}
+
+func singleKeyLoader(batchLoader BatchLoader) Loader {
+	return func(ctx context.Context, key interface{}) (interface{}, bool, error) {
+		values, err := batchLoader(ctx, []interface{}{key})
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		value, found := values[key]
+
+		return value, found, nil
+	}
+}
+
+// detachedContext keeps the values of a context but not its cancellation. A load is shared by all callers of a key,
+// so the first caller giving up must not fail the load of the others.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+type loadResult struct {
+	value interface{}
+	found bool
+}
+
+// load calls the loader for the key, shared with all concurrent calls for the same key. The loader is called with a
+// context detached from the caller, every caller stops waiting for the result once its own context is done.
+func (s *chainKvStore) load(ctx context.Context, key interface{}) (*loadResult, error) {
+	keyStr, err := CastKeyToString(key)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not build string key %T %v: %w", key, key, err)
+	}
+
+	loadCtx := detachedContext{parent: ctx}
+
+	results := s.loading.DoChan(keyStr, func() (interface{}, error) {
+		value, found, err := s.options.loader(loadCtx, key)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not load %s: %w", keyStr, err)
+		}
+
+		if !found {
+			s.cacheMissing(loadCtx, key)
+
+			return &loadResult{}, nil
+		}
+
+		for _, element := range s.chain {
+			if err := element.Put(loadCtx, key, value); err != nil {
+				s.logger.WithContext(loadCtx).Warnf("could not put loaded %s to kvstore %T: %s", key, element, err.Error())
+			}
+		}
+
+		return &loadResult{
+			value: value,
+			found: true,
+		}, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("can not load %s: %w", keyStr, ctx.Err())
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		return result.Val.(*loadResult), nil
+	}
+}
+
+func (s *chainKvStore) loadBatch(ctx context.Context, keys []interface{}, values interface{}) ([]interface{}, error) {
+	m, err := refl.MapOf(values)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not write loaded values to %T: %w", values, err)
+	}
+
+	if s.options.batchLoader != nil {
+		return s.loadWithBatchLoader(ctx, keys, m)
+	}
+
+	missing := make([]interface{}, 0)
+
+	for _, key := range keys {
+		result, err := s.load(ctx, key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !result.found {
+			missing = append(missing, key)
+			continue
+		}
+
+		element := m.NewElement()
+
+		if err := assignLoadedValue(element, result.value); err != nil {
+			return nil, fmt.Errorf("can not assign loaded value of %v: %w", key, err)
+		}
+
+		if err := m.Set(key, element); err != nil {
+			return nil, fmt.Errorf("can not set loaded value of %v: %w", key, err)
+		}
+	}
+
+	return missing, nil
+}
+
+// loadWithBatchLoader reads all keys with a single call of the batch loader and writes the found values to all elements
+func (s *chainKvStore) loadWithBatchLoader(ctx context.Context, keys []interface{}, m *refl.Map) ([]interface{}, error) {
+	loaded, err := s.options.batchLoader(ctx, keys)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not load %d keys: %w", len(keys), err)
+	}
+
+	missing := make([]interface{}, 0)
+	found := make(map[interface{}]interface{}, len(loaded))
+
+	for _, key := range keys {
+		value, ok := loaded[key]
+
+		if !ok {
+			s.cacheMissing(ctx, key)
+			missing = append(missing, key)
+
+			continue
+		}
+
+		element := m.NewElement()
+
+		if err := assignLoadedValue(element, value); err != nil {
+			return nil, fmt.Errorf("can not assign loaded value of %v: %w", key, err)
+		}
+
+		if err := m.Set(key, element); err != nil {
+			return nil, fmt.Errorf("can not set loaded value of %v: %w", key, err)
+		}
+
+		found[key] = value
+	}
+
+	if len(found) == 0 {
+		return missing, nil
+	}
+
+	for _, element := range s.chain {
+		if err := element.PutBatch(ctx, found); err != nil {
+			s.logger.WithContext(ctx).Warnf("could not put loaded batch to kvstore %T: %s", element, err.Error())
+		}
+	}
+
+	return missing, nil
+}
+
+func (s *chainKvStore) cacheMissing(ctx context.Context, key interface{}) {
+	if err := s.missingCache.PutWithTtl(ctx, key, noValue, s.options.notFoundTtl); err != nil {
+		s.logger.WithContext(ctx).Warnf("failed to write to missing value cache: %s", err.Error())
+	}
+}
+
+// assignLoadedValue writes the loaded value, which may be a pointer to it as well, to the value pointer
+func assignLoadedValue(value interface{}, loaded interface{}) error {
+	rv := reflect.ValueOf(value)
+
+	if rv.Kind() != reflect.Ptr {
+		return fmt.Errorf("the output value has to be a pointer, was %T", value)
+	}
+
+	rv = rv.Elem()
+	rl := reflect.ValueOf(loaded)
+
+	if !rl.IsValid() {
+		return fmt.Errorf("the loader returned nil")
+	}
+
+	if !rl.Type().AssignableTo(rv.Type()) && rl.Kind() == reflect.Ptr {
+		rl = rl.Elem()
+	}
+
+	if !rl.Type().AssignableTo(rv.Type()) {
+		return fmt.Errorf("the loaded value of type %T can not be assigned to %T", loaded, value)
+	}
+
+	rv.Set(rl)
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package kvstore_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kvstore"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChainKvStore_Loader_Get(t *testing.T) {
+	ctx := context.Background()
+	calls := int32(0)
+
+	loader := func(ctx context.Context, key interface{}) (interface{}, bool, error) {
+		atomic.AddInt32(&calls, 1)
+
+		return Item{Id: key.(string), Body: "loaded"}, true, nil
+	}
+
+	store, element0, element1 := buildLoadingChainStore(kvstore.WithLoader(loader, 0))
+
+	item := Item{}
+	found, err := store.Get(ctx, "foo", &item)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Item{Id: "foo", Body: "loaded"}, item)
+
+	for _, element := range []kvstore.KvStore{element0, element1} {
+		stored := Item{}
+		found, err = element.Get(ctx, "foo", &stored)
+
+		assert.NoError(t, err)
+		assert.True(t, found, "the loaded value should be written to all elements")
+		assert.Equal(t, item, stored)
+	}
+
+	found, err = store.Get(ctx, "foo", &item)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestChainKvStore_Loader_Singleflight(t *testing.T) {
+	ctx := context.Background()
+	calls := int32(0)
+	release := make(chan struct{})
+
+	loader := func(ctx context.Context, key interface{}) (interface{}, bool, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+
+		return &Item{Id: key.(string), Body: "loaded"}, true, nil
+	}
+
+	store, _, _ := buildLoadingChainStore(kvstore.WithLoader(loader, 0))
+
+	wg := &sync.WaitGroup{}
+	items := make([]Item, 10)
+
+	for i := range items {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			found, err := store.Get(ctx, "foo", &items[i])
+
+			assert.NoError(t, err)
+			assert.True(t, found)
+		}(i)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	for _, item := range items {
+		assert.Equal(t, Item{Id: "foo", Body: "loaded"}, item)
+	}
+}
+
+func TestChainKvStore_Loader_NotFound(t *testing.T) {
+	ctx := context.Background()
+	calls := int32(0)
+
+	loader := func(ctx context.Context, key interface{}) (interface{}, bool, error) {
+		atomic.AddInt32(&calls, 1)
+
+		return nil, false, nil
+	}
+
+	store, _, _ := buildLoadingChainStore(kvstore.WithLoader(loader, time.Millisecond*100))
+
+	for i := 0; i < 2; i++ {
+		found, err := store.Contains(ctx, "foo")
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "the missing key should be cached")
+
+	time.Sleep(time.Millisecond * 150)
+
+	found, err := store.Get(ctx, "foo", &Item{})
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "the cached missing key should be expired")
+}
+
+func TestChainKvStore_Loader_Error(t *testing.T) {
+	ctx := context.Background()
+
+	loader := func(ctx context.Context, key interface{}) (interface{}, bool, error) {
+		return nil, false, fmt.Errorf("unavailable")
+	}
+
+	store, _, _ := buildLoadingChainStore(kvstore.WithLoader(loader, time.Minute))
+
+	found, err := store.Get(ctx, "foo", &Item{})
+
+	assert.EqualError(t, err, "can not load foo: unavailable")
+	assert.False(t, found)
+}
+
+func TestChainKvStore_Loader_GetBatch(t *testing.T) {
+	ctx := context.Background()
+
+	loader := func(ctx context.Context, key interface{}) (interface{}, bool, error) {
+		if key == "bar" {
+			return nil, false, nil
+		}
+
+		return Item{Id: key.(string), Body: "loaded"}, true, nil
+	}
+
+	store, element0, _ := buildLoadingChainStore(kvstore.WithLoader(loader, time.Minute))
+
+	err := element0.Put(ctx, "foo", Item{Id: "foo", Body: "cached"})
+	assert.NoError(t, err)
+
+	items := make(map[string]Item)
+	missing, err := store.GetBatch(ctx, []string{"foo", "fuu", "bar"}, items)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"bar"}, missing)
+	assert.Equal(t, map[string]Item{
+		"foo": {Id: "foo", Body: "cached"},
+		"fuu": {Id: "fuu", Body: "loaded"},
+	}, items)
+}
+
+func TestChainKvStore_Loader_CanceledCaller(t *testing.T) {
+	release := make(chan struct{})
+	loaderErr := make(chan error, 1)
+
+	loader := func(ctx context.Context, key interface{}) (interface{}, bool, error) {
+		<-release
+		loaderErr <- ctx.Err()
+
+		return Item{Id: key.(string), Body: "loaded"}, true, nil
+	}
+
+	store, _, _ := buildLoadingChainStore(kvstore.WithLoader(loader, 0))
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+
+	go func() {
+		_, err := store.Get(canceledCtx, "foo", &Item{})
+		canceled <- err
+	}()
+
+	// wait until the first caller started the load, the second one shares it
+	time.Sleep(time.Millisecond * 50)
+
+	waiting := make(chan error, 1)
+	item := Item{}
+
+	go func() {
+		_, err := store.Get(context.Background(), "foo", &item)
+		waiting <- err
+	}()
+
+	time.Sleep(time.Millisecond * 50)
+	cancel()
+
+	assert.EqualError(t, <-canceled, "can not load foo: context canceled")
+
+	close(release)
+
+	assert.NoError(t, <-waiting, "the canceled caller should not fail the shared load")
+	assert.NoError(t, <-loaderErr, "the loader should not see the cancellation of the first caller")
+	assert.Equal(t, Item{Id: "foo", Body: "loaded"}, item)
+}
+
+func TestChainKvStore_BatchLoader_GetBatch(t *testing.T) {
+	ctx := context.Background()
+	calls := int32(0)
+
+	batchLoader := func(ctx context.Context, keys []interface{}) (map[interface{}]interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, []interface{}{"fuu", "bar"}, keys)
+
+		return map[interface{}]interface{}{
+			"fuu": Item{Id: "fuu", Body: "loaded"},
+		}, nil
+	}
+
+	store, element0, element1 := buildLoadingChainStore(kvstore.WithBatchLoader(batchLoader, time.Minute))
+
+	err := element0.Put(ctx, "foo", Item{Id: "foo", Body: "cached"})
+	assert.NoError(t, err)
+
+	items := make(map[string]Item)
+	missing, err := store.GetBatch(ctx, []string{"foo", "fuu", "bar"}, items)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "the missing keys should be loaded at once")
+	assert.Equal(t, []interface{}{"bar"}, missing)
+	assert.Equal(t, map[string]Item{
+		"foo": {Id: "foo", Body: "cached"},
+		"fuu": {Id: "fuu", Body: "loaded"},
+	}, items)
+
+	stored := Item{}
+	found, err := element1.Get(ctx, "fuu", &stored)
+
+	assert.NoError(t, err)
+	assert.True(t, found, "the loaded value should be written to all elements")
+	assert.Equal(t, Item{Id: "fuu", Body: "loaded"}, stored)
+
+	found, err = store.Contains(ctx, "bar")
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "the missing key should be cached")
+}
+
+func buildLoadingChainStore(options ...kvstore.ChainOption) (kvstore.KvStore, kvstore.KvStore, kvstore.KvStore) {
+	logger := monMocks.NewLoggerMockedAll()
+
+	settings := &kvstore.Settings{
+		AppId: cfg.AppId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "kvstore",
+		},
+		Name:      "loader",
+		Ttl:       time.Hour,
+		BatchSize: 100,
+	}
+
+	element0 := kvstore.NewInMemoryKvStoreWithInterfaces(settings)
+	element1 := kvstore.NewInMemoryKvStoreWithInterfaces(settings)
+	missingCache := kvstore.NewInMemoryKvStoreWithInterfaces(settings)
+
+	store := kvstore.NewChainKvStoreWithInterfaces(logger, nilFactory, missingCache, settings, options...)
+	store.AddStore(element0)
+	store.AddStore(element1)
+
+	return store, element0, element1
+}
@@ -110,6 +110,32 @@ func (s *MetricStore) Put(ctx context.Context, key interface{}, value interface{
 	return nil
 }
 
+func (s *MetricStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	err := s.KvStore.PutWithTtl(ctx, key, value, ttl)
+
+	if err == nil {
+		s.recordWrites(1)
+	}
+
+	return err
+}
+
+func (s *MetricStore) PutIfAbsent(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) (bool, error) {
+	putter, ok := s.KvStore.(ConditionalPutter)
+
+	if !ok {
+		return false, fmt.Errorf("the kvstore %T can not put a value conditionally", s.KvStore)
+	}
+
+	written, err := putter.PutIfAbsent(ctx, key, value, ttl)
+
+	if err == nil && written {
+		s.recordWrites(1)
+	}
+
+	return written, err
+}
+
 func (s *MetricStore) PutBatch(ctx context.Context, values interface{}) error {
 	mii, err := refl.InterfaceToMapInterfaceInterface(values)
 
@@ -239,3 +265,13 @@ func getDefaultMetrics(model string, store string) mon.MetricData {
 		},
 	}
 }
+
+func (s *MetricStore) FlushPrefix(ctx context.Context, prefix string) error {
+	flusher, ok := s.KvStore.(PrefixFlusher)
+
+	if !ok {
+		return fmt.Errorf("the kvstore %T can not flush a prefix", s.KvStore)
+	}
+
+	return flusher.FlushPrefix(ctx, prefix)
+}
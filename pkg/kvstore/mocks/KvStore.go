@@ -6,6 +6,8 @@ import context "context"
 
 import mock "github.com/stretchr/testify/mock"
 
+import time "time"
+
 // KvStore is an autogenerated mock type for the KvStore type
 type KvStore struct {
 	mock.Mock
@@ -131,3 +133,17 @@ func (_m *KvStore) PutBatch(ctx context.Context, values interface{}) error {
 
 	return r0
 }
+
+// PutWithTtl provides a mock function with given fields: ctx, key, value, ttl
+func (_m *KvStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, value, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, time.Duration) error); ok {
+		r0 = rf(ctx, key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
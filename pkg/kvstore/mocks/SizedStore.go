@@ -6,6 +6,8 @@ import context "context"
 
 import mock "github.com/stretchr/testify/mock"
 
+import time "time"
+
 // SizedStore is an autogenerated mock type for the SizedStore type
 type SizedStore struct {
 	mock.Mock
@@ -147,3 +149,17 @@ func (_m *SizedStore) PutBatch(ctx context.Context, values interface{}) error {
 
 	return r0
 }
+
+// PutWithTtl provides a mock function with given fields: ctx, key, value, ttl
+func (_m *SizedStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, value, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, interface{}, time.Duration) error); ok {
+		r0 = rf(ctx, key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -7,12 +7,65 @@ import (
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/redis"
 	"github.com/applike/gosoline/pkg/refl"
+	baseRedis "github.com/go-redis/redis/v8"
 	"strings"
+	"time"
 )
 
+const (
+	RedisKeySerializerDefault = "default"
+	RedisKeySerializerPlain   = "plain"
+)
+
+// number of keys scanned per call while flushing a prefix
+const redisFlushScanCount = 1000
+
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// A RedisKeySerializer builds the key of a value in redis from the key in the store
+type RedisKeySerializer func(settings *Settings, key string) string
+
+var redisKeySerializers = map[string]RedisKeySerializer{
+	RedisKeySerializerDefault: redisKeySerializerDefault,
+	RedisKeySerializerPlain:   redisKeySerializerPlain,
+}
+
+// prefixes the key with the app id and the name of the store to avoid collisions with other stores
+func redisKeySerializerDefault(settings *Settings, key string) string {
+	return strings.Join([]string{
+		settings.Project,
+		settings.Family,
+		settings.Application,
+		"kvstore",
+		settings.Name,
+		key,
+	}, "-")
+}
+
+// uses the key as it is, e.g. to share values with applications not using a kvstore
+func redisKeySerializerPlain(_ *Settings, key string) string {
+	return key
+}
+
+func (s RedisSettings) apply(settings *redis.Settings) {
+	if s.Mode != "" {
+		settings.Mode = s.Mode
+	}
+
+	if len(s.Addresses) > 0 {
+		settings.Addresses = s.Addresses
+	}
+
+	if s.MasterName != "" {
+		settings.MasterName = s.MasterName
+	}
+}
+
 type redisKvStore struct {
-	client   redis.Client
-	settings *Settings
+	client        redis.Client
+	codec         *valueCodec
+	settings      *Settings
+	keySerializer RedisKeySerializer
 }
 
 func RedisBasename(settings *Settings) string {
@@ -23,7 +76,21 @@ func NewRedisKvStore(config cfg.Config, logger mon.Logger, settings *Settings) (
 	settings.PadFromConfig(config)
 	redisName := RedisBasename(settings)
 
-	client, err := redis.ProvideClient(config, logger, redisName)
+	if _, ok := redisKeySerializers[settings.Redis.KeySerializer]; !ok && settings.Redis.KeySerializer != "" {
+		return nil, fmt.Errorf("there is no redis key serializer %s", settings.Redis.KeySerializer)
+	}
+
+	if err := checkCompressionSettings(settings.Compression); err != nil {
+		return nil, err
+	}
+
+	redisSettings := redis.ReadSettings(config, redisName)
+	settings.Redis.apply(redisSettings)
+
+	// the store needs to know the effective mode to avoid reading keys from different cluster nodes with MGET
+	settings.Redis.Mode = redisSettings.Mode
+
+	client, err := redis.ProvideClientWithSettings(logger, redisSettings)
 	if err != nil {
 		return nil, fmt.Errorf("can not create redis client: %w", err)
 	}
@@ -32,9 +99,17 @@ func NewRedisKvStore(config cfg.Config, logger mon.Logger, settings *Settings) (
 }
 
 func NewRedisKvStoreWithInterfaces(client redis.Client, settings *Settings) KvStore {
+	keySerializer, ok := redisKeySerializers[settings.Redis.KeySerializer]
+
+	if !ok {
+		keySerializer = redisKeySerializerDefault
+	}
+
 	return NewMetricStoreWithInterfaces(&redisKvStore{
-		client:   client,
-		settings: settings,
+		client:        client,
+		codec:         newValueCodec(settings, "*kvstore.redisKvStore"),
+		settings:      settings,
+		keySerializer: keySerializer,
 	}, settings)
 }
 
@@ -71,7 +146,7 @@ func (s *redisKvStore) Get(ctx context.Context, key interface{}, value interface
 		return false, fmt.Errorf("can not get value from redis store: %w", err)
 	}
 
-	err = Unmarshal([]byte(data), value)
+	err = s.codec.decode([]byte(data), value)
 
 	if err != nil {
 		return false, fmt.Errorf("can not unmarshal value from redis store: %w", err)
@@ -98,7 +173,7 @@ func (s *redisKvStore) getChunk(ctx context.Context, resultMap *refl.Map, keys [
 		}
 	}
 
-	items, err := s.client.MGet(ctx, keyStrings...)
+	items, err := s.getItems(ctx, keyStrings)
 
 	if err != nil {
 		return nil, fmt.Errorf("can not get batch from redis: %w", err)
@@ -119,7 +194,7 @@ func (s *redisKvStore) getChunk(ctx context.Context, resultMap *refl.Map, keys [
 		}
 
 		element := resultMap.NewElement()
-		err = Unmarshal([]byte(item), element)
+		err = s.codec.decode([]byte(item), element)
 
 		if err != nil {
 			return nil, fmt.Errorf("can not unmarshal item: %w", err)
@@ -133,8 +208,58 @@ func (s *redisKvStore) getChunk(ctx context.Context, resultMap *refl.Map, keys [
 	return missing, nil
 }
 
+// getItems reads the keys with MGET. The keys might belong to different nodes of a cluster, which fails a MGET,
+// so we pipeline single reads in cluster mode instead. Like MGET, it returns nil for missing keys.
+func (s *redisKvStore) getItems(ctx context.Context, keyStrings []string) ([]interface{}, error) {
+	if s.settings.Redis.Mode != redis.ModeCluster {
+		return s.client.MGet(ctx, keyStrings...)
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*baseRedis.StringCmd, len(keyStrings))
+
+	for i, keyStr := range keyStrings {
+		cmds[i] = pipe.Get(ctx, keyStr)
+	}
+
+	// a missing key fails its command with redis.Nil, which is returned as the error of the pipeline, too
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, len(cmds))
+
+	for i, cmd := range cmds {
+		item, err := cmd.Result()
+
+		if err == redis.Nil {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = item
+	}
+
+	return items, nil
+}
+
 func (s *redisKvStore) Put(ctx context.Context, key interface{}, value interface{}) error {
-	bytes, err := Marshal(value)
+	return s.put(ctx, key, value, s.settings.Ttl)
+}
+
+func (s *redisKvStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = s.settings.Ttl
+	}
+
+	return s.put(ctx, key, value, ttl)
+}
+
+func (s *redisKvStore) put(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	data, _, err := s.codec.encode(value)
 
 	if err != nil {
 		return fmt.Errorf("can not marshal value %T %v: %w", value, value, err)
@@ -146,7 +271,7 @@ func (s *redisKvStore) Put(ctx context.Context, key interface{}, value interface
 		return fmt.Errorf("can not get key to write value to redis: %w", err)
 	}
 
-	err = s.client.Set(ctx, keyStr, bytes, s.settings.Ttl)
+	err = s.client.Set(ctx, keyStr, data, ttl)
 
 	if err != nil {
 		return fmt.Errorf("can not set value in redis store: %w", err)
@@ -155,6 +280,32 @@ func (s *redisKvStore) Put(ctx context.Context, key interface{}, value interface
 	return nil
 }
 
+func (s *redisKvStore) PutIfAbsent(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) (bool, error) {
+	data, _, err := s.codec.encode(value)
+
+	if err != nil {
+		return false, fmt.Errorf("can not marshal value %T %v: %w", value, value, err)
+	}
+
+	keyStr, err := s.key(key)
+
+	if err != nil {
+		return false, fmt.Errorf("can not get key to write value to redis: %w", err)
+	}
+
+	if ttl == 0 {
+		ttl = s.settings.Ttl
+	}
+
+	written, err := s.client.SetNX(ctx, keyStr, data, ttl)
+
+	if err != nil {
+		return false, fmt.Errorf("can not set value in redis store: %w", err)
+	}
+
+	return written, nil
+}
+
 func (s *redisKvStore) PutBatch(ctx context.Context, values interface{}) error {
 	mii, err := refl.InterfaceToMapInterfaceInterface(values)
 
@@ -162,10 +313,26 @@ func (s *redisKvStore) PutBatch(ctx context.Context, values interface{}) error {
 		return fmt.Errorf("could not convert values from %T to map[interface{}]interface{}", values)
 	}
 
+	pipe := s.client.Pipeline()
+
 	for k, v := range mii {
-		if err = s.Put(ctx, k, v); err != nil {
-			return fmt.Errorf("failed to write batch to redis: %w", err)
+		data, _, err := s.codec.encode(v)
+
+		if err != nil {
+			return fmt.Errorf("can not marshal value %T %v: %w", v, v, err)
+		}
+
+		keyStr, err := s.key(k)
+
+		if err != nil {
+			return fmt.Errorf("can not get key to write value to redis: %w", err)
 		}
+
+		pipe.Set(ctx, keyStr, data, s.settings.Ttl)
+	}
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write batch to redis: %w", err)
 	}
 
 	return nil
@@ -232,14 +399,36 @@ func (s *redisKvStore) key(key interface{}) (string, error) {
 		return "", fmt.Errorf("can not cast key %T %v to string: %w", key, key, err)
 	}
 
-	keyStr = strings.Join([]string{
-		s.settings.Project,
-		s.settings.Family,
-		s.settings.Application,
-		"kvstore",
-		s.settings.Name,
-		keyStr,
-	}, "-")
+	return s.keySerializer(s.settings, keyStr), nil
+}
 
-	return keyStr, nil
+// FlushPrefix deletes the keys with the prefix found by SCAN, which is not supported in the cluster mode, as
+// the keys are spread over many nodes.
+func (s *redisKvStore) FlushPrefix(ctx context.Context, prefix string) error {
+	if s.settings.Redis.Mode == redis.ModeCluster {
+		return fmt.Errorf("can not flush the prefix %s of a redis store in cluster mode", prefix)
+	}
+
+	match := redisGlobEscaper.Replace(s.keySerializer(s.settings, prefix)) + "*"
+	cursor := uint64(0)
+
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, match, redisFlushScanCount)
+
+		if err != nil {
+			return fmt.Errorf("can not scan for keys with prefix %s: %w", prefix, err)
+		}
+
+		if len(keys) > 0 {
+			if _, err = s.client.Del(ctx, keys...); err != nil {
+				return fmt.Errorf("can not delete keys with prefix %s: %w", prefix, err)
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+
+		cursor = next
+	}
 }
@@ -2,10 +2,13 @@ package kvstore_test
 
 import (
 	"context"
+	"github.com/alicebob/miniredis"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/kvstore"
 	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/redis"
 	redisMocks "github.com/applike/gosoline/pkg/redis/mocks"
+	baseRedis "github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -73,6 +76,41 @@ func TestRedisKvStore_GetBatch(t *testing.T) {
 	client.AssertExpectations(t)
 }
 
+func TestRedisKvStore_GetBatch_Cluster(t *testing.T) {
+	client := new(redisMocks.Client)
+	store := kvstore.NewRedisKvStoreWithInterfaces(client, &kvstore.Settings{
+		Name:      "test",
+		BatchSize: 100,
+		Redis: kvstore.RedisSettings{
+			Mode:          redis.ModeCluster,
+			KeySerializer: kvstore.RedisKeySerializerPlain,
+		},
+	})
+
+	server, base := buildMiniRedis(t)
+	defer server.Close()
+
+	err := server.Set("foo", `{"id":"foo","body":"bar"}`)
+	assert.NoError(t, err)
+
+	client.On("Pipeline").Return(base.Pipeline())
+
+	keys := []string{"foo", "fuu"}
+	result := make(map[string]Item)
+
+	missing, err := store.GetBatch(context.Background(), keys, result)
+
+	assert.NoError(t, err)
+	assert.Contains(t, result, "foo")
+	assert.Equal(t, "foo", result["foo"].Id)
+	assert.Equal(t, "bar", result["foo"].Body)
+
+	assert.Len(t, missing, 1)
+	assert.Contains(t, missing, "fuu")
+
+	client.AssertExpectations(t)
+}
+
 func TestRedisKvStore_Put(t *testing.T) {
 	store, client := buildTestableRedisStore()
 	client.On("Set", mock.AnythingOfType("*context.emptyCtx"), "applike-gosoline-kvstore-kvstore-test-foo", []byte(`{"id":"foo","body":"bar"}`), time.Duration(0)).Return(nil)
@@ -88,10 +126,35 @@ func TestRedisKvStore_Put(t *testing.T) {
 	client.AssertExpectations(t)
 }
 
+func TestRedisKvStore_PutIfAbsent(t *testing.T) {
+	store, client := buildTestableRedisStore()
+	client.On("SetNX", mock.AnythingOfType("*context.emptyCtx"), "applike-gosoline-kvstore-kvstore-test-foo", []byte(`{"id":"foo","body":"bar"}`), time.Duration(0)).Return(true, nil).Once()
+	client.On("SetNX", mock.AnythingOfType("*context.emptyCtx"), "applike-gosoline-kvstore-kvstore-test-foo", []byte(`{"id":"foo","body":"bar"}`), time.Minute).Return(false, nil).Once()
+
+	item := &Item{
+		Id:   "foo",
+		Body: "bar",
+	}
+
+	putter := store.(kvstore.ConditionalPutter)
+
+	written, err := putter.PutIfAbsent(context.Background(), "foo", item, 0)
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = putter.PutIfAbsent(context.Background(), "foo", item, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, written)
+
+	client.AssertExpectations(t)
+}
+
 func TestRedisKvStore_PutBatch(t *testing.T) {
 	store, client := buildTestableRedisStore()
-	client.On("Set", mock.AnythingOfType("*context.emptyCtx"), "applike-gosoline-kvstore-kvstore-test-foo", []byte(`{"id":"foo","body":"bar"}`), time.Duration(0)).Return(nil)
-	client.On("Set", mock.AnythingOfType("*context.emptyCtx"), "applike-gosoline-kvstore-kvstore-test-fuu", []byte(`{"id":"fuu","body":"baz"}`), time.Duration(0)).Return(nil)
+	server, base := buildMiniRedis(t)
+	defer server.Close()
+
+	client.On("Pipeline").Return(base.Pipeline())
 
 	items := map[string]Item{
 		"foo": {
@@ -105,8 +168,11 @@ func TestRedisKvStore_PutBatch(t *testing.T) {
 	}
 
 	err := store.PutBatch(context.Background(), items)
-
 	assert.NoError(t, err)
+
+	server.CheckGet(t, "applike-gosoline-kvstore-kvstore-test-foo", `{"id":"foo","body":"bar"}`)
+	server.CheckGet(t, "applike-gosoline-kvstore-kvstore-test-fuu", `{"id":"fuu","body":"baz"}`)
+
 	client.AssertExpectations(t)
 }
 
@@ -142,6 +208,38 @@ func TestRedisKvStore_DeleteBatch(t *testing.T) {
 	client.AssertExpectations(t)
 }
 
+func TestRedisKvStore_KeySerializerPlain(t *testing.T) {
+	client := new(redisMocks.Client)
+	client.On("Exists", mock.AnythingOfType("*context.emptyCtx"), "foo").Return(int64(1), nil)
+
+	store := kvstore.NewRedisKvStoreWithInterfaces(client, &kvstore.Settings{
+		Name: "test",
+		Redis: kvstore.RedisSettings{
+			KeySerializer: kvstore.RedisKeySerializerPlain,
+		},
+	})
+
+	exists, err := store.Contains(context.Background(), "foo")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	client.AssertExpectations(t)
+}
+
+func buildMiniRedis(t *testing.T) (*miniredis.Miniredis, *baseRedis.Client) {
+	server, err := miniredis.Run()
+
+	if err != nil {
+		assert.FailNow(t, err.Error(), "can not start miniredis")
+	}
+
+	base := baseRedis.NewClient(&baseRedis.Options{
+		Addr: server.Addr(),
+	})
+
+	return server, base
+}
+
 func buildTestableRedisStore() (kvstore.KvStore, *redisMocks.Client) {
 	client := new(redisMocks.Client)
 
@@ -159,3 +257,17 @@ func buildTestableRedisStore() (kvstore.KvStore, *redisMocks.Client) {
 
 	return store, client
 }
+
+func TestRedisKvStore_FlushPrefix(t *testing.T) {
+	store, client := buildTestableRedisStore()
+	match := `applike-gosoline-kvstore-kvstore-test-a\*b:*`
+
+	client.On("Scan", mock.AnythingOfType("*context.emptyCtx"), uint64(0), match, int64(1000)).Return([]string{"applike-gosoline-kvstore-kvstore-test-a*b:foo"}, uint64(12), nil).Once()
+	client.On("Scan", mock.AnythingOfType("*context.emptyCtx"), uint64(12), match, int64(1000)).Return([]string{}, uint64(0), nil).Once()
+	client.On("Del", mock.AnythingOfType("*context.emptyCtx"), "applike-gosoline-kvstore-kvstore-test-a*b:foo").Return(int64(1), nil).Once()
+
+	err := store.(kvstore.PrefixFlusher).FlushPrefix(context.Background(), "a*b:")
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
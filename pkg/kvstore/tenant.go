@@ -0,0 +1,259 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/refl"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// separates the tenant from the key
+const tenantSeparator = ":"
+
+// you tried to access a tenant store with a context without a tenant
+var ErrTenantMissing = errors.New("there is no tenant in the context")
+
+// A PrefixFlusher removes all values with keys starting with the prefix from the store
+type PrefixFlusher interface {
+	FlushPrefix(ctx context.Context, prefix string) error
+}
+
+// A TenantResolver returns the tenant of the current request or false if there is none
+type TenantResolver func(ctx context.Context) (string, bool)
+
+// TenantKvStore prefixes every key with the tenant of the context. Without a tenant in the context every
+// operation fails with ErrTenantMissing, so a tenant can't read or write the values of another one by accident.
+type TenantKvStore struct {
+	store    KvStore
+	resolver TenantResolver
+}
+
+func NewTenantKvStore(store KvStore, resolver TenantResolver) *TenantKvStore {
+	if resolver == nil {
+		resolver = reqctx.TenantFromContext
+	}
+
+	return &TenantKvStore{
+		store:    store,
+		resolver: resolver,
+	}
+}
+
+func (s *TenantKvStore) Contains(ctx context.Context, key interface{}) (bool, error) {
+	tenantKey, err := s.key(ctx, key)
+
+	if err != nil {
+		return false, err
+	}
+
+	return s.store.Contains(ctx, tenantKey)
+}
+
+func (s *TenantKvStore) Get(ctx context.Context, key interface{}, value interface{}) (bool, error) {
+	tenantKey, err := s.key(ctx, key)
+
+	if err != nil {
+		return false, err
+	}
+
+	return s.store.Get(ctx, tenantKey, value)
+}
+
+func (s *TenantKvStore) GetBatch(ctx context.Context, keys interface{}, values interface{}) ([]interface{}, error) {
+	prefix, err := s.prefix(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keySlice, err := refl.InterfaceToInterfaceSlice(keys)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not morph keys to slice of interfaces: %w", err)
+	}
+
+	resultMap, err := refl.MapOf(values)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not write values to %T: %w", values, err)
+	}
+
+	tenantKeys := make([]string, len(keySlice))
+	originalKeys := make(map[string]interface{}, len(keySlice))
+
+	for i, key := range keySlice {
+		if tenantKeys[i], err = prefixKey(prefix, key); err != nil {
+			return nil, err
+		}
+
+		originalKeys[tenantKeys[i]] = key
+	}
+
+	// read into a map with the keys of the tenant and move the values to the keys of the caller afterwards
+	elementType := reflect.TypeOf(values)
+
+	if elementType.Kind() == reflect.Ptr {
+		elementType = elementType.Elem()
+	}
+
+	tenantValues := reflect.MakeMap(reflect.MapOf(reflect.TypeOf(""), elementType.Elem()))
+	tenantMissing, err := s.store.GetBatch(ctx, tenantKeys, tenantValues.Interface())
+
+	if err != nil {
+		return nil, err
+	}
+
+	iter := tenantValues.MapRange()
+
+	for iter.Next() {
+		if err := resultMap.Set(originalKeys[iter.Key().String()], iter.Value().Interface()); err != nil {
+			return nil, fmt.Errorf("can not set value of %s on result map: %w", iter.Key().String(), err)
+		}
+	}
+
+	missing := make([]interface{}, len(tenantMissing))
+
+	for i, key := range tenantMissing {
+		missing[i] = originalKeys[key.(string)]
+	}
+
+	return missing, nil
+}
+
+func (s *TenantKvStore) Put(ctx context.Context, key interface{}, value interface{}) error {
+	tenantKey, err := s.key(ctx, key)
+
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(ctx, tenantKey, value)
+}
+
+func (s *TenantKvStore) PutWithTtl(ctx context.Context, key interface{}, value interface{}, ttl time.Duration) error {
+	tenantKey, err := s.key(ctx, key)
+
+	if err != nil {
+		return err
+	}
+
+	return s.store.PutWithTtl(ctx, tenantKey, value, ttl)
+}
+
+func (s *TenantKvStore) PutBatch(ctx context.Context, values interface{}) error {
+	prefix, err := s.prefix(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	mii, err := refl.InterfaceToMapInterfaceInterface(values)
+
+	if err != nil {
+		return fmt.Errorf("can not cast values from %T to map[interface{}]interface{}: %w", values, err)
+	}
+
+	tenantValues := make(map[interface{}]interface{}, len(mii))
+
+	for key, value := range mii {
+		tenantKey, err := prefixKey(prefix, key)
+
+		if err != nil {
+			return err
+		}
+
+		tenantValues[tenantKey] = value
+	}
+
+	return s.store.PutBatch(ctx, tenantValues)
+}
+
+func (s *TenantKvStore) Delete(ctx context.Context, key interface{}) error {
+	tenantKey, err := s.key(ctx, key)
+
+	if err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, tenantKey)
+}
+
+func (s *TenantKvStore) DeleteBatch(ctx context.Context, keys interface{}) error {
+	prefix, err := s.prefix(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	keySlice, err := refl.InterfaceToInterfaceSlice(keys)
+
+	if err != nil {
+		return fmt.Errorf("can not morph keys to slice of interfaces: %w", err)
+	}
+
+	tenantKeys := make([]string, len(keySlice))
+
+	for i, key := range keySlice {
+		if tenantKeys[i], err = prefixKey(prefix, key); err != nil {
+			return err
+		}
+	}
+
+	return s.store.DeleteBatch(ctx, tenantKeys)
+}
+
+// Flush removes all values of the tenant of the context. The underlying store has to be a PrefixFlusher.
+func (s *TenantKvStore) Flush(ctx context.Context) error {
+	prefix, err := s.prefix(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	flusher, ok := s.store.(PrefixFlusher)
+
+	if !ok {
+		return fmt.Errorf("the kvstore %T can not flush the values of a tenant", s.store)
+	}
+
+	return flusher.FlushPrefix(ctx, prefix)
+}
+
+func (s *TenantKvStore) key(ctx context.Context, key interface{}) (string, error) {
+	prefix, err := s.prefix(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	return prefixKey(prefix, key)
+}
+
+func (s *TenantKvStore) prefix(ctx context.Context) (string, error) {
+	tenant, ok := s.resolver(ctx)
+
+	if !ok {
+		return "", ErrTenantMissing
+	}
+
+	// otherwise the prefix of one tenant might be the prefix of another one
+	if strings.Contains(tenant, tenantSeparator) {
+		return "", fmt.Errorf("the tenant %s must not contain %s", tenant, tenantSeparator)
+	}
+
+	return tenant + tenantSeparator, nil
+}
+
+func prefixKey(prefix string, key interface{}) (string, error) {
+	keyStr, err := CastKeyToString(key)
+
+	if err != nil {
+		return "", fmt.Errorf("can not build string key %T %v: %w", key, key, err)
+	}
+
+	return prefix + keyStr, nil
+}
@@ -0,0 +1,145 @@
+package kvstore_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/kvstore"
+	kvStoreMocks "github.com/applike/gosoline/pkg/kvstore/mocks"
+	"github.com/applike/gosoline/pkg/reqctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type TenantKvStoreTestSuite struct {
+	suite.Suite
+	store *kvstore.TenantKvStore
+	ctxA  context.Context
+	ctxB  context.Context
+}
+
+func (s *TenantKvStoreTestSuite) SetupTest() {
+	base := kvstore.NewInMemoryKvStoreWithInterfaces(&kvstore.Settings{
+		Name:      "tenant",
+		Ttl:       time.Hour,
+		BatchSize: 100,
+	})
+
+	s.store = kvstore.NewTenantKvStore(base, nil)
+	s.ctxA = reqctx.WithTenant(context.Background(), "a")
+	s.ctxB = reqctx.WithTenant(context.Background(), "b")
+}
+
+func (s *TenantKvStoreTestSuite) TestIsolation() {
+	err := s.store.Put(s.ctxA, "foo", Item{Id: "foo", Body: "a"})
+	s.NoError(err)
+
+	item := Item{}
+	found, err := s.store.Get(s.ctxA, "foo", &item)
+	s.NoError(err)
+	s.True(found)
+	s.Equal(Item{Id: "foo", Body: "a"}, item)
+
+	found, err = s.store.Contains(s.ctxB, "foo")
+	s.NoError(err)
+	s.False(found, "tenant b should not see the values of tenant a")
+
+	err = s.store.Delete(s.ctxB, "foo")
+	s.NoError(err)
+
+	found, err = s.store.Contains(s.ctxA, "foo")
+	s.NoError(err)
+	s.True(found, "tenant b should not delete the values of tenant a")
+}
+
+func (s *TenantKvStoreTestSuite) TestBatch() {
+	err := s.store.PutBatch(s.ctxA, map[int]Item{
+		1: {Id: "1", Body: "a"},
+		2: {Id: "2", Body: "a"},
+	})
+	s.NoError(err)
+
+	err = s.store.PutBatch(s.ctxB, map[int]Item{
+		3: {Id: "3", Body: "b"},
+	})
+	s.NoError(err)
+
+	items := make(map[int]Item)
+	missing, err := s.store.GetBatch(s.ctxA, []int{1, 2, 3}, items)
+
+	s.NoError(err)
+	s.Equal([]interface{}{3}, missing)
+	s.Equal(map[int]Item{
+		1: {Id: "1", Body: "a"},
+		2: {Id: "2", Body: "a"},
+	}, items)
+
+	err = s.store.DeleteBatch(s.ctxA, []int{1, 2})
+	s.NoError(err)
+
+	items = make(map[int]Item)
+	missing, err = s.store.GetBatch(s.ctxA, []int{1, 2}, items)
+
+	s.NoError(err)
+	s.Equal([]interface{}{1, 2}, missing)
+	s.Empty(items)
+}
+
+func (s *TenantKvStoreTestSuite) TestFlush() {
+	err := s.store.Put(s.ctxA, "foo", Item{Id: "foo", Body: "a"})
+	s.NoError(err)
+
+	err = s.store.Flush(s.ctxA)
+	s.NoError(err)
+
+	found, err := s.store.Contains(s.ctxA, "foo")
+	s.NoError(err)
+	s.False(found)
+}
+
+func (s *TenantKvStoreTestSuite) TestMissingTenant() {
+	ctx := context.Background()
+
+	_, err := s.store.Get(ctx, "foo", &Item{})
+	s.Equal(kvstore.ErrTenantMissing, err)
+
+	err = s.store.Put(ctx, "foo", Item{})
+	s.Equal(kvstore.ErrTenantMissing, err)
+
+	_, err = s.store.GetBatch(ctx, []string{"foo"}, map[string]Item{})
+	s.Equal(kvstore.ErrTenantMissing, err)
+
+	err = s.store.Flush(ctx)
+	s.Equal(kvstore.ErrTenantMissing, err)
+}
+
+func (s *TenantKvStoreTestSuite) TestInvalidTenant() {
+	ctx := reqctx.WithTenant(context.Background(), "a:b")
+
+	err := s.store.Put(ctx, "foo", Item{})
+	s.EqualError(err, "the tenant a:b must not contain :")
+}
+
+func TestTenantKvStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(TenantKvStoreTestSuite))
+}
+
+func TestTenantKvStore_Flush(t *testing.T) {
+	ctx := reqctx.WithTenant(context.Background(), "a")
+
+	base := new(kvStoreMocks.KvStore)
+	store := kvstore.NewTenantKvStore(base, nil)
+
+	err := store.Flush(ctx)
+	assert.EqualError(t, err, "the kvstore *mocks.KvStore can not flush the values of a tenant")
+
+	chain, element0, element1 := buildTestableChainStore(false)
+	store = kvstore.NewTenantKvStore(chain, func(ctx context.Context) (string, bool) {
+		return "b", true
+	})
+
+	err = store.Flush(ctx)
+	assert.EqualError(t, err, "the kvstore *mocks.KvStore can not flush a prefix")
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+}
@@ -0,0 +1,137 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// A Locker makes sure only one instance of an application migrates the database at a time
+//
+//go:generate mockery -name Locker
+type Locker interface {
+	// Lock blocks until the lock is acquired or the timeout is exceeded. The returned function releases the lock.
+	Lock(ctx context.Context, name string, timeout time.Duration) (func() error, error)
+}
+
+func newLocker(driver string, connection *sqlx.DB) (Locker, error) {
+	switch driver {
+	case db.DriverMysql:
+		return NewMysqlLocker(connection), nil
+	case db.DriverPostgres:
+		return NewPostgresLocker(connection), nil
+	}
+
+	return nil, fmt.Errorf("there is no migration lock for the driver %s", driver)
+}
+
+type mysqlLocker struct {
+	db *sqlx.DB
+}
+
+// NewMysqlLocker uses a named lock of mysql, which is bound to the connection holding it
+func NewMysqlLocker(db *sqlx.DB) *mysqlLocker {
+	return &mysqlLocker{
+		db: db,
+	}
+}
+
+func (l *mysqlLocker) Lock(ctx context.Context, name string, timeout time.Duration) (func() error, error) {
+	// the lock belongs to the connection, so we have to release it with the same one
+	conn, err := l.db.Conn(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not get a connection for the lock %s: %w", name, err)
+	}
+
+	var acquired sql.NullInt64
+
+	if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, int(timeout.Seconds())).Scan(&acquired); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("can not acquire the lock %s: %w", name, err)
+	}
+
+	if !acquired.Valid || acquired.Int64 != 1 {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("can not acquire the lock %s within %s", name, timeout)
+	}
+
+	unlock := func() error {
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", name); err != nil {
+			return fmt.Errorf("can not release the lock %s: %w", name, err)
+		}
+
+		return nil
+	}
+
+	return unlock, nil
+}
+
+type postgresLocker struct {
+	db *sqlx.DB
+}
+
+// NewPostgresLocker uses a session level advisory lock of postgres, which is bound to the connection holding it
+func NewPostgresLocker(db *sqlx.DB) *postgresLocker {
+	return &postgresLocker{
+		db: db,
+	}
+}
+
+func (l *postgresLocker) Lock(ctx context.Context, name string, timeout time.Duration) (func() error, error) {
+	conn, err := l.db.Conn(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not get a connection for the lock %s: %w", name, err)
+	}
+
+	// pg_advisory_lock waits without a timeout, so we poll instead
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var acquired bool
+
+		if err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&acquired); err != nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("can not acquire the lock %s: %w", name, err)
+		}
+
+		if acquired {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("can not acquire the lock %s within %s", name, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	unlock := func() error {
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", name); err != nil {
+			return fmt.Errorf("can not release the lock %s: %w", name, err)
+		}
+
+		return nil
+	}
+
+	return unlock, nil
+}
@@ -0,0 +1,189 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// A MigrationFunc changes the schema or the data of the database. All changes done with the transaction are
+// committed together with the new version. Keep in mind that some databases, like mysql, commit DDL statements
+// immediately, so a failing migration might be applied partially.
+type MigrationFunc func(ctx context.Context, tx *sql.Tx) error
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      MigrationFunc
+	// reverts the migration, a migration without Down can't be migrated down
+	Down MigrationFunc
+}
+
+// A Source provides a set of migrations, e.g. sql files embedded into the binary or migrations written in go
+type Source interface {
+	Migrations() ([]*Migration, error)
+}
+
+type goSource struct {
+	migrations []*Migration
+}
+
+// GoSource provides migrations written in go
+func GoSource(migrations ...*Migration) Source {
+	return &goSource{
+		migrations: migrations,
+	}
+}
+
+func (s *goSource) Migrations() ([]*Migration, error) {
+	return s.migrations, nil
+}
+
+// <version>_<name>.up.sql or <version>_<name>.down.sql
+var sqlFileRegexp = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type sqlSource struct {
+	fs  http.FileSystem
+	dir string
+}
+
+// SqlSource reads the sql migrations from the directory of the file system. An http.FileSystem is provided by most
+// tools embedding files into a binary as well as by http.Dir for files on disk. The files are named like
+// 1_create_users.up.sql and 1_create_users.down.sql, the down file is optional.
+func SqlSource(fs http.FileSystem, dir string) Source {
+	return &sqlSource{
+		fs:  fs,
+		dir: dir,
+	}
+}
+
+func (s *sqlSource) Migrations() ([]*Migration, error) {
+	dir, err := s.fs.Open(s.dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not open the migration directory %s: %w", s.dir, err)
+	}
+
+	defer dir.Close()
+
+	files, err := dir.Readdir(-1)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not read the migration directory %s: %w", s.dir, err)
+	}
+
+	migrations := make(map[int64]*Migration)
+
+	for _, file := range files {
+		matches := sqlFileRegexp.FindStringSubmatch(file.Name())
+
+		if file.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not parse the version of the migration %s: %w", file.Name(), err)
+		}
+
+		statement, err := s.read(file.Name())
+
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrations[version]
+
+		if !ok {
+			migration = &Migration{
+				Version: version,
+				Name:    matches[2],
+			}
+			migrations[version] = migration
+		}
+
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("the migrations %s and %s have the same version %d", migration.Name, matches[2], version)
+		}
+
+		if matches[3] == "up" {
+			migration.Up = sqlMigrationFunc(statement)
+		} else {
+			migration.Down = sqlMigrationFunc(statement)
+		}
+	}
+
+	result := make([]*Migration, 0, len(migrations))
+
+	for _, migration := range migrations {
+		result = append(result, migration)
+	}
+
+	return result, nil
+}
+
+func (s *sqlSource) read(name string) (string, error) {
+	file, err := s.fs.Open(path.Join(s.dir, name))
+
+	if err != nil {
+		return "", fmt.Errorf("can not open the migration %s: %w", name, err)
+	}
+
+	defer file.Close()
+
+	content, err := ioutil.ReadAll(file)
+
+	if err != nil {
+		return "", fmt.Errorf("can not read the migration %s: %w", name, err)
+	}
+
+	return string(content), nil
+}
+
+func sqlMigrationFunc(statement string) MigrationFunc {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, statement)
+
+		return err
+	}
+}
+
+// collectMigrations reads the migrations of all sources sorted by their version
+func collectMigrations(sources []Source) ([]*Migration, error) {
+	versions := make(map[int64]*Migration)
+	migrations := make([]*Migration, 0)
+
+	for _, source := range sources {
+		sourceMigrations, err := source.Migrations()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, migration := range sourceMigrations {
+			if existing, ok := versions[migration.Version]; ok {
+				return nil, fmt.Errorf("the migrations %s and %s have the same version %d", existing.Name, migration.Name, migration.Version)
+			}
+
+			if migration.Up == nil {
+				return nil, fmt.Errorf("the migration %d_%s has no up migration", migration.Version, migration.Name)
+			}
+
+			versions[migration.Version] = migration
+			migrations = append(migrations, migration)
+		}
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,370 @@
+// Package migrations applies versioned migrations written in go or sql and records them in its own table. It replaces
+// the golang-migrate based migrations configured at db.<client>.migrations, which are still run when the connection is
+// opened. Both use different tables, so they don't know about each other: don't migrate the same schema with both and
+// move a schema by adding its current state as the first migration of this package before disabling the old ones.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jmoiron/sqlx"
+	"sort"
+	"time"
+)
+
+const (
+	DirectionUp     = "up"
+	DirectionDown   = "down"
+	DirectionStatus = "status"
+)
+
+type Settings struct {
+	// name of the db client, the connection is configured at db.<client>
+	Client string `cfg:"client" default:"default"`
+	// table storing the applied versions
+	Table       string        `cfg:"table" default:"gosoline_migrations"`
+	LockTimeout time.Duration `cfg:"lock_timeout" default:"1m"`
+	// what a migration task does, the startup module always migrates up
+	Direction string `cfg:"direction" default:"up" validate:"oneof=up down status"`
+	// version to migrate down to, 0 reverts all migrations
+	TargetVersion int64 `cfg:"target_version" default:"0"`
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// the migration is applied but unknown to the sources, e.g. after a rollback of the application
+	Unknown    bool
+	Reversible bool
+}
+
+//go:generate mockery -name Migrator
+type Migrator interface {
+	// Up applies all migrations which are not applied yet in the order of their versions
+	Up(ctx context.Context) error
+	// Down reverts all applied migrations with a version greater than the target version, starting with the latest one
+	Down(ctx context.Context, targetVersion int64) error
+	// Status returns the state of every known and every applied migration ordered by version. It doesn't change the
+	// database, so all migrations are pending if the migration table doesn't exist yet.
+	Status(ctx context.Context) ([]Status, error)
+}
+
+type appliedMigration struct {
+	Version   int64     `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+type migrator struct {
+	logger     mon.Logger
+	db         *sqlx.DB
+	locker     Locker
+	clock      clock.Clock
+	settings   *Settings
+	migrations []*Migration
+}
+
+func ReadSettings(config cfg.Config, name string) *Settings {
+	settings := &Settings{}
+	config.UnmarshalKey(fmt.Sprintf("migrations.%s", name), settings)
+
+	return settings
+}
+
+func NewMigrator(config cfg.Config, logger mon.Logger, name string, sources ...Source) (*migrator, error) {
+	settings := ReadSettings(config, name)
+
+	connection, err := db.ProvideConnection(config, logger, settings.Client)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not connect to the db %s: %w", settings.Client, err)
+	}
+
+	driver := config.GetString(fmt.Sprintf("db.%s.driver", settings.Client))
+
+	return NewMigratorForConnection(logger, name, connection, driver, settings, sources...)
+}
+
+// NewMigratorForConnection creates a migrator for a connection which isn't configured at db.<client>, e.g. the one
+// of a test database
+func NewMigratorForConnection(logger mon.Logger, name string, connection *sqlx.DB, driver string, settings *Settings, sources ...Source) (*migrator, error) {
+	migrations, err := collectMigrations(sources)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not read the migrations of %s: %w", name, err)
+	}
+
+	locker, err := newLocker(driver, connection)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create the migration lock: %w", err)
+	}
+
+	logger = logger.WithChannel("migrations").WithFields(mon.Fields{
+		"migrations": name,
+	})
+
+	return NewMigratorWithInterfaces(logger, connection, locker, clock.Provider, settings, migrations), nil
+}
+
+func NewMigratorWithInterfaces(logger mon.Logger, db *sqlx.DB, locker Locker, clock clock.Clock, settings *Settings, migrations []*Migration) *migrator {
+	return &migrator{
+		logger:     logger,
+		db:         db,
+		locker:     locker,
+		clock:      clock,
+		settings:   settings,
+		migrations: migrations,
+	}
+}
+
+func (m *migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(applied map[int64]appliedMigration) error {
+		count := 0
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, migration, migration.Up, m.insertVersion); err != nil {
+				return fmt.Errorf("can not migrate up to %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			m.logger.Infof("migrated up to %d_%s", migration.Version, migration.Name)
+			count++
+		}
+
+		m.logger.Infof("applied %d of %d migrations", count, len(m.migrations))
+
+		return nil
+	})
+}
+
+func (m *migrator) Down(ctx context.Context, targetVersion int64) error {
+	known := make(map[int64]*Migration, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	return m.locked(ctx, func(applied map[int64]appliedMigration) error {
+		reverts := make([]*Migration, 0)
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok && m.migrations[i].Version > targetVersion {
+				reverts = append(reverts, m.migrations[i])
+			}
+		}
+
+		// check everything before reverting anything, so we don't end up somewhere between the versions
+		for version, migration := range applied {
+			if version <= targetVersion {
+				continue
+			}
+
+			if _, ok := known[version]; !ok {
+				return fmt.Errorf("the applied migration %d_%s is unknown and can not be reverted", version, migration.Name)
+			}
+
+			if known[version].Down == nil {
+				return fmt.Errorf("the migration %d_%s is not reversible", version, migration.Name)
+			}
+		}
+
+		for _, migration := range reverts {
+			if err := m.apply(ctx, migration, migration.Down, m.deleteVersion); err != nil {
+				return fmt.Errorf("can not migrate down from %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			m.logger.Infof("migrated down from %d_%s", migration.Version, migration.Name)
+		}
+
+		m.logger.Infof("reverted %d migrations to the version %d", len(reverts), targetVersion)
+
+		return nil
+	})
+}
+
+func (m *migrator) Status(ctx context.Context) ([]Status, error) {
+	exists, err := m.tableExists(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]appliedMigration)
+
+	if exists {
+		if applied, err = m.readApplied(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		status := Status{
+			Version:    migration.Version,
+			Name:       migration.Name,
+			Reversible: migration.Down != nil,
+		}
+
+		if appliedMigration, ok := applied[migration.Version]; ok {
+			appliedAt := appliedMigration.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+
+			delete(applied, migration.Version)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for _, appliedMigration := range applied {
+		appliedAt := appliedMigration.AppliedAt
+
+		statuses = append(statuses, Status{
+			Version:   appliedMigration.Version,
+			Name:      appliedMigration.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Unknown:   true,
+		})
+	}
+
+	sortStatuses(statuses)
+
+	return statuses, nil
+}
+
+// locked runs the function while holding the migration lock with the migrations applied at the time we got the lock
+func (m *migrator) locked(ctx context.Context, f func(applied map[int64]appliedMigration) error) error {
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := m.locker.Lock(ctx, m.settings.Table, m.settings.LockTimeout)
+
+	if err != nil {
+		return fmt.Errorf("can not lock the migrations: %w", err)
+	}
+
+	defer func() {
+		if err := unlock(); err != nil {
+			m.logger.Error(err, "can not unlock the migrations")
+		}
+	}()
+
+	applied, err := m.readApplied(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return f(applied)
+}
+
+func (m *migrator) apply(ctx context.Context, migration *Migration, f MigrationFunc, record func(ctx context.Context, tx *sql.Tx, migration *Migration) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return fmt.Errorf("can not begin transaction: %w", err)
+	}
+
+	if err = f(ctx, tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if err = record(ctx, tx, migration); err != nil {
+		_ = tx.Rollback()
+
+		return fmt.Errorf("can not record the version: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("can not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (m *migrator) createTable(ctx context.Context) error {
+	// postgres has no DATETIME and TIMESTAMP of mysql ends in 2038
+	timeType := "DATETIME"
+
+	if m.db.DriverName() == db.DriverPostgres {
+		timeType = "TIMESTAMP"
+	}
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at %s NOT NULL)", m.settings.Table, timeType)
+
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("can not create the migration table %s: %w", m.settings.Table, err)
+	}
+
+	return nil
+}
+
+func (m *migrator) tableExists(ctx context.Context) (bool, error) {
+	schema := "DATABASE()"
+
+	if m.db.DriverName() == db.DriverPostgres {
+		schema = "current_schema()"
+	}
+
+	var count int
+	query := m.db.Rebind(fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = %s AND table_name = ?", schema))
+
+	if err := m.db.GetContext(ctx, &count, query, m.settings.Table); err != nil {
+		return false, fmt.Errorf("can not check if the migration table %s exists: %w", m.settings.Table, err)
+	}
+
+	return count > 0, nil
+}
+
+func (m *migrator) readApplied(ctx context.Context) (map[int64]appliedMigration, error) {
+	rows := make([]appliedMigration, 0)
+	query := fmt.Sprintf("SELECT version, name, applied_at FROM %s", m.settings.Table)
+
+	if err := m.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("can not read the applied migrations: %w", err)
+	}
+
+	applied := make(map[int64]appliedMigration, len(rows))
+
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+func (m *migrator) insertVersion(ctx context.Context, tx *sql.Tx, migration *Migration) error {
+	query := m.db.Rebind(fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (?, ?, ?)", m.settings.Table))
+	_, err := tx.ExecContext(ctx, query, migration.Version, migration.Name, m.clock.Now().UTC())
+
+	return err
+}
+
+func (m *migrator) deleteVersion(ctx context.Context, tx *sql.Tx, migration *Migration) error {
+	query := m.db.Rebind(fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.settings.Table))
+	_, err := tx.ExecContext(ctx, query, migration.Version)
+
+	return err
+}
+
+func sortStatuses(statuses []Status) {
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+}
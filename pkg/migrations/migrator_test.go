@@ -0,0 +1,264 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/migrations"
+	migrationsMocks "github.com/applike/gosoline/pkg/migrations/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type MigratorTestSuite struct {
+	suite.Suite
+
+	ctx      context.Context
+	sqlMock  goSqlMock.Sqlmock
+	db       *sqlx.DB
+	locker   *migrationsMocks.Locker
+	clock    clock.FakeClock
+	unlocked bool
+}
+
+func (s *MigratorTestSuite) SetupTest() {
+	dbMock, sqlMock, err := goSqlMock.New()
+	s.NoError(err)
+
+	s.ctx = context.Background()
+	s.sqlMock = sqlMock
+	s.db = sqlx.NewDb(dbMock, "sqlmock")
+	s.locker = new(migrationsMocks.Locker)
+	s.clock = clock.NewFakeClockAt(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC))
+	s.unlocked = false
+}
+
+func (s *MigratorTestSuite) TearDownTest() {
+	s.NoError(s.sqlMock.ExpectationsWereMet())
+	s.locker.AssertExpectations(s.T())
+}
+
+func (s *MigratorTestSuite) migrator(migrationList ...*migrations.Migration) migrations.Migrator {
+	settings := &migrations.Settings{
+		Table:       "gosoline_migrations",
+		LockTimeout: time.Minute,
+	}
+
+	return migrations.NewMigratorWithInterfaces(monMocks.NewLoggerMockedAll(), s.db, s.locker, s.clock, settings, migrationList)
+}
+
+func (s *MigratorTestSuite) expectLock() {
+	unlock := func() error {
+		s.unlocked = true
+
+		return nil
+	}
+
+	s.locker.On("Lock", s.ctx, "gosoline_migrations", time.Minute).Return(unlock, nil).Once()
+}
+
+func (s *MigratorTestSuite) expectApplied(versions ...int64) {
+	rows := goSqlMock.NewRows([]string{"version", "name", "applied_at"})
+
+	for _, version := range versions {
+		rows.AddRow(version, fmt.Sprintf("migration_%d", version), s.clock.Now())
+	}
+
+	s.sqlMock.ExpectExec("CREATE TABLE IF NOT EXISTS gosoline_migrations").WillReturnResult(goSqlMock.NewResult(0, 0))
+	s.sqlMock.ExpectQuery("SELECT version, name, applied_at FROM gosoline_migrations").WillReturnRows(rows)
+}
+
+func (s *MigratorTestSuite) expectTableExists(exists bool) {
+	count := 0
+	if exists {
+		count = 1
+	}
+
+	rows := goSqlMock.NewRows([]string{"count"}).AddRow(count)
+	s.sqlMock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM information_schema.tables").WithArgs("gosoline_migrations").WillReturnRows(rows)
+}
+
+func (s *MigratorTestSuite) TestUp() {
+	s.expectLock()
+	s.expectApplied(1)
+
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec("CREATE TABLE foo").WillReturnResult(goSqlMock.NewResult(0, 0))
+	s.sqlMock.ExpectExec("INSERT INTO gosoline_migrations").WithArgs(2, "migration_2", s.clock.Now()).WillReturnResult(goSqlMock.NewResult(0, 1))
+	s.sqlMock.ExpectCommit()
+
+	migrator := s.migrator(
+		sqlMigration(1, "CREATE TABLE bar", ""),
+		sqlMigration(2, "CREATE TABLE foo", ""),
+	)
+
+	err := migrator.Up(s.ctx)
+	s.NoError(err)
+	s.True(s.unlocked)
+}
+
+func (s *MigratorTestSuite) TestUp_Failed() {
+	s.expectLock()
+	s.expectApplied()
+
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec("CREATE TABLE bar").WillReturnResult(goSqlMock.NewResult(0, 0))
+	s.sqlMock.ExpectExec("INSERT INTO gosoline_migrations").WillReturnResult(goSqlMock.NewResult(0, 1))
+	s.sqlMock.ExpectCommit()
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec("CREATE TABLE foo").WillReturnError(fmt.Errorf("table foo exists"))
+	s.sqlMock.ExpectRollback()
+
+	migrator := s.migrator(
+		sqlMigration(1, "CREATE TABLE bar", ""),
+		sqlMigration(2, "CREATE TABLE foo", ""),
+		sqlMigration(3, "CREATE TABLE baz", ""),
+	)
+
+	err := migrator.Up(s.ctx)
+	s.EqualError(err, "can not migrate up to 2_migration_2: table foo exists")
+	s.True(s.unlocked)
+}
+
+func (s *MigratorTestSuite) TestUp_LockFailed() {
+	s.sqlMock.ExpectExec("CREATE TABLE IF NOT EXISTS gosoline_migrations").WillReturnResult(goSqlMock.NewResult(0, 0))
+	s.locker.On("Lock", s.ctx, "gosoline_migrations", time.Minute).Return(nil, fmt.Errorf("timeout")).Once()
+
+	migrator := s.migrator(sqlMigration(1, "CREATE TABLE bar", ""))
+
+	err := migrator.Up(s.ctx)
+	s.EqualError(err, "can not lock the migrations: timeout")
+}
+
+func (s *MigratorTestSuite) TestDown() {
+	s.expectLock()
+	s.expectApplied(1, 2, 3)
+
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec("DROP TABLE baz").WillReturnResult(goSqlMock.NewResult(0, 0))
+	s.sqlMock.ExpectExec("DELETE FROM gosoline_migrations WHERE version = ?").WithArgs(3).WillReturnResult(goSqlMock.NewResult(0, 1))
+	s.sqlMock.ExpectCommit()
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec("DROP TABLE foo").WillReturnResult(goSqlMock.NewResult(0, 0))
+	s.sqlMock.ExpectExec("DELETE FROM gosoline_migrations WHERE version = ?").WithArgs(2).WillReturnResult(goSqlMock.NewResult(0, 1))
+	s.sqlMock.ExpectCommit()
+
+	migrator := s.migrator(
+		sqlMigration(1, "CREATE TABLE bar", ""),
+		sqlMigration(2, "CREATE TABLE foo", "DROP TABLE foo"),
+		sqlMigration(3, "CREATE TABLE baz", "DROP TABLE baz"),
+	)
+
+	err := migrator.Down(s.ctx, 1)
+	s.NoError(err)
+	s.True(s.unlocked)
+}
+
+func (s *MigratorTestSuite) TestDown_NotReversible() {
+	s.expectLock()
+	s.expectApplied(1, 2)
+
+	migrator := s.migrator(
+		sqlMigration(1, "CREATE TABLE bar", ""),
+		sqlMigration(2, "CREATE TABLE foo", "DROP TABLE foo"),
+	)
+
+	err := migrator.Down(s.ctx, 0)
+	s.EqualError(err, "the migration 1_migration_1 is not reversible")
+	s.True(s.unlocked)
+}
+
+func (s *MigratorTestSuite) TestStatus() {
+	rows := goSqlMock.NewRows([]string{"version", "name", "applied_at"})
+	rows.AddRow(1, "migration_1", s.clock.Now())
+	rows.AddRow(4, "migration_4", s.clock.Now())
+
+	s.expectTableExists(true)
+	s.sqlMock.ExpectQuery("SELECT version, name, applied_at FROM gosoline_migrations").WillReturnRows(rows)
+
+	migrator := s.migrator(
+		sqlMigration(1, "CREATE TABLE bar", ""),
+		sqlMigration(2, "CREATE TABLE foo", "DROP TABLE foo"),
+	)
+
+	statuses, err := migrator.Status(s.ctx)
+	s.NoError(err)
+
+	appliedAt := s.clock.Now()
+	s.Equal([]migrations.Status{
+		{Version: 1, Name: "migration_1", Applied: true, AppliedAt: &appliedAt},
+		{Version: 2, Name: "migration_2", Reversible: true},
+		{Version: 4, Name: "migration_4", Applied: true, AppliedAt: &appliedAt, Unknown: true},
+	}, statuses)
+}
+
+func (s *MigratorTestSuite) TestStatusWithoutTable() {
+	s.expectTableExists(false)
+
+	migrator := s.migrator(
+		sqlMigration(1, "CREATE TABLE bar", ""),
+		sqlMigration(2, "CREATE TABLE foo", "DROP TABLE foo"),
+	)
+
+	statuses, err := migrator.Status(s.ctx)
+	s.NoError(err)
+
+	s.Equal([]migrations.Status{
+		{Version: 1, Name: "migration_1"},
+		{Version: 2, Name: "migration_2", Reversible: true},
+	}, statuses)
+}
+
+func TestMigratorTestSuite(t *testing.T) {
+	suite.Run(t, new(MigratorTestSuite))
+}
+
+func TestSqlSource(t *testing.T) {
+	source := migrations.SqlSource(http.Dir("testdata"), "migrations")
+
+	migrationList, err := source.Migrations()
+	assert.NoError(t, err)
+	assert.Len(t, migrationList, 2)
+
+	byVersion := map[int64]*migrations.Migration{}
+
+	for _, migration := range migrationList {
+		byVersion[migration.Version] = migration
+	}
+
+	assert.Equal(t, "create_users", byVersion[1].Name)
+	assert.NotNil(t, byVersion[1].Up)
+	assert.NotNil(t, byVersion[1].Down)
+	assert.Equal(t, "add_email", byVersion[2].Name)
+	assert.NotNil(t, byVersion[2].Up)
+	assert.Nil(t, byVersion[2].Down)
+}
+
+func sqlMigration(version int64, up string, down string) *migrations.Migration {
+	migration := &migrations.Migration{
+		Version: version,
+		Name:    fmt.Sprintf("migration_%d", version),
+		Up:      exec(up),
+	}
+
+	if down != "" {
+		migration.Down = exec(down)
+	}
+
+	return migration
+}
+
+func exec(statement string) migrations.MigrationFunc {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, statement)
+
+		return err
+	}
+}
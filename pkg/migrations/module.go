@@ -0,0 +1,159 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/conc"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+// Module migrates the database up on startup. It is ready once all migrations are applied, so modules depending on
+// the new schema should be added with kernel.ModuleDependsOn(<name of the module>). An error while migrating stops
+// the kernel.
+type Module struct {
+	kernel.EssentialModule
+	kernel.EssentialStage
+
+	logger   mon.Logger
+	migrator Migrator
+	ready    conc.SignalOnce
+}
+
+func NewModule(name string, sources ...Source) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		migrator, err := NewMigrator(config, logger, name, sources...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return NewModuleWithInterfaces(logger, migrator), nil
+	}
+}
+
+func NewModuleWithInterfaces(logger mon.Logger, migrator Migrator) *Module {
+	return &Module{
+		logger:   logger,
+		migrator: migrator,
+		ready:    conc.NewSignalOnce(),
+	}
+}
+
+func (m *Module) Run(ctx context.Context) error {
+	if err := m.migrator.Up(ctx); err != nil {
+		return fmt.Errorf("can not migrate up: %w", err)
+	}
+
+	m.ready.Signal()
+	<-ctx.Done()
+
+	return nil
+}
+
+func (m *Module) Ready() <-chan struct{} {
+	return m.ready.Channel()
+}
+
+// Validate checks that the database is reachable and knows every applied migration without changing the database
+func (m *Module) Validate(ctx context.Context) error {
+	return validate(ctx, m.migrator)
+}
+
+// TaskModule migrates up, down or prints the status of the migrations depending on the direction configured at
+// migrations.<name>.direction and stops afterwards.
+type TaskModule struct {
+	kernel.TaskModule
+	kernel.ApplicationStage
+
+	logger   mon.Logger
+	migrator Migrator
+	settings *Settings
+}
+
+func NewTaskModule(name string, sources ...Source) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		migrator, err := NewMigrator(config, logger, name, sources...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		settings := ReadSettings(config, name)
+
+		return NewTaskModuleWithInterfaces(logger, migrator, settings), nil
+	}
+}
+
+func NewTaskModuleWithInterfaces(logger mon.Logger, migrator Migrator, settings *Settings) *TaskModule {
+	return &TaskModule{
+		logger:   logger,
+		migrator: migrator,
+		settings: settings,
+	}
+}
+
+func (m *TaskModule) Run(ctx context.Context) error {
+	switch m.settings.Direction {
+	case DirectionUp:
+		if err := m.migrator.Up(ctx); err != nil {
+			return fmt.Errorf("can not migrate up: %w", err)
+		}
+	case DirectionDown:
+		if err := m.migrator.Down(ctx, m.settings.TargetVersion); err != nil {
+			return fmt.Errorf("can not migrate down to %d: %w", m.settings.TargetVersion, err)
+		}
+	case DirectionStatus:
+	default:
+		return fmt.Errorf("unknown migration direction %s", m.settings.Direction)
+	}
+
+	return m.logStatus(ctx)
+}
+
+func (m *TaskModule) Validate(ctx context.Context) error {
+	return validate(ctx, m.migrator)
+}
+
+func (m *TaskModule) logStatus(ctx context.Context) error {
+	statuses, err := m.migrator.Status(ctx)
+
+	if err != nil {
+		return fmt.Errorf("can not read the status of the migrations: %w", err)
+	}
+
+	for _, status := range statuses {
+		state := "pending"
+
+		switch {
+		case status.Unknown:
+			state = fmt.Sprintf("applied at %s but unknown", status.AppliedAt.Format("2006-01-02 15:04:05"))
+		case status.Applied:
+			state = fmt.Sprintf("applied at %s", status.AppliedAt.Format("2006-01-02 15:04:05"))
+		}
+
+		m.logger.WithFields(mon.Fields{
+			"version":    status.Version,
+			"reversible": status.Reversible,
+		}).Infof("migration %d_%s: %s", status.Version, status.Name, state)
+	}
+
+	return nil
+}
+
+func validate(ctx context.Context, migrator Migrator) error {
+	statuses, err := migrator.Status(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.Unknown {
+			return fmt.Errorf("the applied migration %d_%s is unknown", status.Version, status.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1 @@
+CREATE TABLE users (id INT NOT NULL PRIMARY KEY);
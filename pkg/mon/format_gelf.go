@@ -7,6 +7,18 @@ import (
 )
 
 func formatterGelf(timestamp string, level string, msg string, err error, data *Metadata) ([]byte, error) {
+	gelf := buildGelfFields(level, msg, err, data)
+	gelf["timestamp"] = timestamp
+
+	serialized, err := json.Marshal(gelf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %v", err)
+	}
+
+	return append(serialized, '\n'), nil
+}
+
+func buildGelfFields(level string, msg string, err error, data *Metadata) Fields {
 	gelf := make(Fields, 8)
 
 	if err != nil {
@@ -23,16 +35,10 @@ func formatterGelf(timestamp string, level string, msg string, err error, data *
 
 	gelf["version"] = "1.1"
 	gelf["short_message"] = msg
-	gelf["timestamp"] = timestamp
 	gelf["_channel"] = data.Channel
 	gelf["level"] = levels[level]
 	gelf["level_name"] = level
 	gelf["_pid"] = os.Getpid()
 
-	serialized, err := json.Marshal(gelf)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal fields to JSON, %v", err)
-	}
-
-	return append(serialized, '\n'), nil
+	return gelf
 }
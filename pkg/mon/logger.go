@@ -8,6 +8,7 @@ import (
 	"os"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -31,6 +32,23 @@ func levelPriority(level string) int {
 	return levels[level]
 }
 
+func levelName(priority int) string {
+	for name, p := range levels {
+		if p == priority {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// LevelSwitcher is implemented by the gosoline logger and allows to change the level of a logger and all
+// loggers derived from it (e.g. by WithChannel or WithFields) at runtime.
+type LevelSwitcher interface {
+	Level() string
+	SetLevel(level string) error
+}
+
 const (
 	ChannelDefault   = "default"
 	FormatConsole    = "console"
@@ -44,6 +62,10 @@ type ConfigValues map[string]interface{}
 type Fields map[string]interface{}
 type EcsMetadata map[string]interface{}
 
+// LazyField can be used as the value of a field if computing the value is expensive. The function is
+// only evaluated if the log message is actually written, so it is skipped for filtered levels.
+type LazyField func() interface{}
+
 type Metadata struct {
 	Channel       string
 	Context       context.Context
@@ -90,7 +112,7 @@ type logger struct {
 	ctxResolver []ContextFieldsResolver
 	hooks       []LoggerHook
 
-	level           int
+	level           *int32
 	format          string
 	timestampFormat string
 
@@ -108,7 +130,7 @@ func NewLoggerWithInterfaces(clock clockwork.Clock, out io.Writer) *logger {
 		outputLck:       &sync.Mutex{},
 		ctxResolver:     make([]ContextFieldsResolver, 0),
 		hooks:           make([]LoggerHook, 0),
-		level:           levelPriority(Info),
+		level:           newLevel(Info),
 		format:          FormatConsole,
 		timestampFormat: "15:04:05.000",
 		data: Metadata{
@@ -122,6 +144,32 @@ func NewLoggerWithInterfaces(clock clockwork.Clock, out io.Writer) *logger {
 	return logger
 }
 
+func newLevel(level string) *int32 {
+	priority := int32(levelPriority(level))
+
+	return &priority
+}
+
+func (l *logger) getLevel() int {
+	return int(atomic.LoadInt32(l.level))
+}
+
+func (l *logger) Level() string {
+	return levelName(l.getLevel())
+}
+
+func (l *logger) SetLevel(level string) error {
+	priority, ok := levels[level]
+
+	if !ok {
+		return fmt.Errorf("unknown log level: %s", level)
+	}
+
+	atomic.StoreInt32(l.level, int32(priority))
+
+	return nil
+}
+
 func (l *logger) copy() *logger {
 	return &logger{
 		clock:           l.clock,
@@ -163,7 +211,7 @@ func (l *logger) WithContext(ctx context.Context) Logger {
 
 	for _, r := range l.ctxResolver {
 		newContextFields := r(ctx)
-		cpy.data.ContextFields = mergeMapStringInterface(cpy.data.ContextFields, newContextFields)
+		cpy.data.ContextFields = copyFields(cpy.data.ContextFields, newContextFields)
 	}
 
 	return cpy
@@ -171,7 +219,7 @@ func (l *logger) WithContext(ctx context.Context) Logger {
 
 func (l *logger) WithFields(fields Fields) Logger {
 	cpy := l.copy()
-	cpy.data.Fields = mergeMapStringInterface(l.data.Fields, fields)
+	cpy.data.Fields = copyFields(l.data.Fields, fields)
 
 	return cpy
 }
@@ -185,7 +233,7 @@ func (l *logger) Infof(msg string, args ...interface{}) {
 }
 
 func (l *logger) Debug(args ...interface{}) {
-	if l.level > levels[Debug] {
+	if l.getLevel() > levels[Debug] {
 		return
 	}
 
@@ -193,7 +241,7 @@ func (l *logger) Debug(args ...interface{}) {
 }
 
 func (l *logger) Debugf(msg string, args ...interface{}) {
-	if l.level > levels[Debug] {
+	if l.getLevel() > levels[Debug] {
 		return
 	}
 
@@ -225,12 +273,13 @@ func (l *logger) logError(level string, err error, msg string) {
 func (l *logger) log(level string, msg string, logErr error, fields Fields) {
 	levelNo := levels[level]
 
-	if levelNo < l.level {
+	if levelNo < l.getLevel() {
 		return
 	}
 
 	cpyData := l.data
-	cpyData.Fields = mergeMapStringInterface(cpyData.Fields, fields)
+	cpyData.Fields = resolveLazyFields(mergeMapStringInterface(cpyData.Fields, fields))
+	cpyData.ContextFields = resolveLazyFields(mergeMapStringInterface(cpyData.ContextFields, nil))
 
 	for _, h := range l.hooks {
 		if err := h.Fire(level, msg, logErr, &cpyData); err != nil {
@@ -249,8 +298,12 @@ func (l *logger) log(level string, msg string, logErr error, fields Fields) {
 }
 
 func (l *logger) err(err error) {
+	cpyData := l.data
+	cpyData.Fields = resolveLazyFields(mergeMapStringInterface(cpyData.Fields, nil))
+	cpyData.ContextFields = resolveLazyFields(mergeMapStringInterface(cpyData.ContextFields, nil))
+
 	timestamp := l.clock.Now().Format(l.timestampFormat)
-	buffer, err := formatters[l.format](timestamp, Error, err.Error(), err, &l.data)
+	buffer, err := formatters[l.format](timestamp, Error, err.Error(), err, &cpyData)
 
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
@@ -270,6 +323,22 @@ func (l *logger) write(buffer []byte) {
 	}
 }
 
+// copyFields merges the fields without preparing them for the log. This is deferred
+// until a message passes the level check, so filtered messages don't pay for it.
+func copyFields(receiver map[string]interface{}, input map[string]interface{}) map[string]interface{} {
+	newMap := make(map[string]interface{}, len(receiver)+len(input))
+
+	for k, v := range receiver {
+		newMap[k] = v
+	}
+
+	for k, v := range input {
+		newMap[k] = v
+	}
+
+	return newMap
+}
+
 func mergeMapStringInterface(receiver map[string]interface{}, input map[string]interface{}) map[string]interface{} {
 	newMap := make(map[string]interface{}, len(receiver)+len(input))
 
@@ -284,8 +353,46 @@ func mergeMapStringInterface(receiver map[string]interface{}, input map[string]i
 	return newMap
 }
 
+// resolveLazyFields evaluates all lazy fields. As long as there is no lazy field
+// the input map is returned as it is.
+func resolveLazyFields(fields map[string]interface{}) map[string]interface{} {
+	var resolved map[string]interface{}
+
+	for k, v := range fields {
+		var value interface{}
+
+		switch t := v.(type) {
+		case LazyField:
+			value = prepareForLog(t())
+		case func() interface{}:
+			value = prepareForLog(t())
+		default:
+			continue
+		}
+
+		if resolved == nil {
+			resolved = make(map[string]interface{}, len(fields))
+
+			for k, v := range fields {
+				resolved[k] = v
+			}
+		}
+
+		resolved[k] = value
+	}
+
+	if resolved == nil {
+		return fields
+	}
+
+	return resolved
+}
+
 func prepareForLog(v interface{}) interface{} {
 	switch t := v.(type) {
+	case LazyField, func() interface{}:
+		// lazy fields are only evaluated once we know the message is written
+		return t
 	case error:
 		// Otherwise errors are ignored by `encoding/json`
 		return t.Error()
@@ -0,0 +1,142 @@
+package mon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	ContainerMetadataSourceEcs        = "ecs"
+	ContainerMetadataSourceKubernetes = "kubernetes"
+
+	ecsContainerMetadataUriEnv = "ECS_CONTAINER_METADATA_URI_V4"
+)
+
+type ContainerMetadataKubernetesSettings struct {
+	PodNameEnv      string `cfg:"pod_name_env" default:"POD_NAME"`
+	PodNamespaceEnv string `cfg:"pod_namespace_env" default:"POD_NAMESPACE"`
+	NodeNameEnv     string `cfg:"node_name_env" default:"NODE_NAME"`
+}
+
+type ContainerMetadataSettings struct {
+	Enabled    bool                                `cfg:"enabled" default:"false"`
+	Source     string                              `cfg:"source" default:"ecs" validate:"oneof=ecs kubernetes"`
+	Timeout    time.Duration                       `cfg:"timeout" default:"2s"`
+	Kubernetes ContainerMetadataKubernetesSettings `cfg:"kubernetes"`
+}
+
+type ecsContainerMetadata struct {
+	DockerId string            `json:"DockerId"`
+	Name     string            `json:"Name"`
+	Labels   map[string]string `json:"Labels"`
+}
+
+// NewContainerMetadataFieldsResolver reads the metadata of the container the application is running in
+// once and returns a resolver adding them to the context fields of every log message. On ECS the metadata
+// is fetched from the task metadata endpoint (or the metadata file if the endpoint is not available), on
+// Kubernetes it is read from the environment variables populated by the downward API.
+func NewContainerMetadataFieldsResolver(settings *ContainerMetadataSettings) (ContextFieldsResolver, error) {
+	var err error
+	var fields map[string]interface{}
+
+	switch settings.Source {
+	case ContainerMetadataSourceEcs:
+		if fields, err = readEcsContainerFields(settings); err != nil {
+			return nil, fmt.Errorf("can not read ecs container metadata: %w", err)
+		}
+	case ContainerMetadataSourceKubernetes:
+		fields = readKubernetesContainerFields(settings)
+	default:
+		return nil, fmt.Errorf("unknown container metadata source: %s", settings.Source)
+	}
+
+	return func(ctx context.Context) map[string]interface{} {
+		return fields
+	}, nil
+}
+
+func readEcsContainerFields(settings *ContainerMetadataSettings) (map[string]interface{}, error) {
+	uri, ok := os.LookupEnv(ecsContainerMetadataUriEnv)
+
+	if !ok || len(uri) == 0 {
+		return readEcsContainerFieldsFromFile()
+	}
+
+	client := &http.Client{
+		Timeout: settings.Timeout,
+	}
+
+	response, err := client.Get(uri)
+	if err != nil {
+		return nil, fmt.Errorf("can not request metadata endpoint: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata endpoint responded with status %d", response.StatusCode)
+	}
+
+	metadata := &ecsContainerMetadata{}
+	if err = json.NewDecoder(response.Body).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("can not decode metadata: %w", err)
+	}
+
+	return map[string]interface{}{
+		"container_id":   metadata.DockerId,
+		"container_name": metadata.Name,
+		"task_arn":       metadata.Labels["com.amazonaws.ecs.task-arn"],
+		"cluster":        metadata.Labels["com.amazonaws.ecs.cluster"],
+	}, nil
+}
+
+func readEcsContainerFieldsFromFile() (map[string]interface{}, error) {
+	metadata, err := ReadEcsMetadata()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+
+	if metadata == nil {
+		return fields, nil
+	}
+
+	for field, key := range map[string]string{
+		"container_id":   "ContainerID",
+		"container_name": "ContainerName",
+		"task_arn":       "TaskARN",
+		"cluster":        "Cluster",
+	} {
+		if value, ok := metadata[key]; ok {
+			fields[field] = value
+		}
+	}
+
+	return fields, nil
+}
+
+func readKubernetesContainerFields(settings *ContainerMetadataSettings) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	for field, env := range map[string]string{
+		"pod_name":      settings.Kubernetes.PodNameEnv,
+		"pod_namespace": settings.Kubernetes.PodNamespaceEnv,
+		"node_name":     settings.Kubernetes.NodeNameEnv,
+	} {
+		if value, ok := os.LookupEnv(env); ok && len(value) > 0 {
+			fields[field] = value
+		}
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		fields["container_id"] = hostname
+	}
+
+	return fields
+}
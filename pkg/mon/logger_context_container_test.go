@@ -0,0 +1,58 @@
+package mon_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestContainerMetadataFieldsResolver_Ecs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"DockerId":"abc123","Name":"app","Labels":{"com.amazonaws.ecs.task-arn":"arn:task","com.amazonaws.ecs.cluster":"cluster"}}`))
+	}))
+	defer server.Close()
+
+	_ = os.Setenv("ECS_CONTAINER_METADATA_URI_V4", server.URL)
+	defer os.Unsetenv("ECS_CONTAINER_METADATA_URI_V4")
+
+	resolver, err := mon.NewContainerMetadataFieldsResolver(&mon.ContainerMetadataSettings{
+		Source:  mon.ContainerMetadataSourceEcs,
+		Timeout: time.Second,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"container_id":   "abc123",
+		"container_name": "app",
+		"task_arn":       "arn:task",
+		"cluster":        "cluster",
+	}, resolver(context.Background()))
+}
+
+func TestContainerMetadataFieldsResolver_Kubernetes(t *testing.T) {
+	_ = os.Setenv("POD_NAME", "app-5d8f")
+	_ = os.Setenv("NODE_NAME", "node-1")
+	defer os.Unsetenv("POD_NAME")
+	defer os.Unsetenv("NODE_NAME")
+
+	resolver, err := mon.NewContainerMetadataFieldsResolver(&mon.ContainerMetadataSettings{
+		Source: mon.ContainerMetadataSourceKubernetes,
+		Kubernetes: mon.ContainerMetadataKubernetesSettings{
+			PodNameEnv:      "POD_NAME",
+			PodNamespaceEnv: "POD_NAMESPACE",
+			NodeNameEnv:     "NODE_NAME",
+		},
+	})
+
+	assert.NoError(t, err)
+
+	fields := resolver(context.Background())
+	assert.Equal(t, "app-5d8f", fields["pod_name"])
+	assert.Equal(t, "node-1", fields["node_name"])
+	assert.NotContains(t, fields, "pod_namespace")
+}
@@ -109,7 +109,7 @@ func (l *ContextEnforcingLogger) checkContext(level string) {
 
 	levelNo := levels[level]
 
-	if levelNo < base.level {
+	if levelNo < base.getLevel() {
 		return
 	}
 
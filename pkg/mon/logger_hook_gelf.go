@@ -0,0 +1,89 @@
+package mon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	GelfProtocolTcp = "tcp"
+	GelfProtocolUdp = "udp"
+)
+
+type GelfSettings struct {
+	Protocol  string        `cfg:"protocol" default:"udp" validate:"oneof=tcp udp"`
+	Address   string        `cfg:"address" default:"127.0.0.1:12201" validate:"required"`
+	Tls       bool          `cfg:"tls" default:"false"`
+	ChunkSize int           `cfg:"chunk_size" default:"1420" validate:"min=13"`
+	Timeout   time.Duration `cfg:"timeout" default:"1s"`
+}
+
+//go:generate mockery -name GelfTransport
+type GelfTransport interface {
+	Send(message []byte) error
+	Close() error
+}
+
+// GelfHook ships every log message to a Graylog input. The message is built the
+// same way as the gelf format does it, so you can switch between a sidecar reading
+// stdout and the hook without changing your Graylog setup.
+type GelfHook struct {
+	clock     func() time.Time
+	host      string
+	transport GelfTransport
+}
+
+func NewGelfHook(settings *GelfSettings) (*GelfHook, error) {
+	var err error
+	var transport GelfTransport
+
+	switch settings.Protocol {
+	case GelfProtocolTcp:
+		transport = NewGelfTcpTransport(settings)
+	case GelfProtocolUdp:
+		if transport, err = NewGelfUdpTransport(settings); err != nil {
+			return nil, fmt.Errorf("can not create gelf udp transport: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown gelf protocol: %s", settings.Protocol)
+	}
+
+	return NewGelfHookWithInterfaces(transport), nil
+}
+
+func NewGelfHookWithInterfaces(transport GelfTransport) *GelfHook {
+	host, err := os.Hostname()
+
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &GelfHook{
+		clock:     time.Now,
+		host:      host,
+		transport: transport,
+	}
+}
+
+func (h *GelfHook) Fire(level string, msg string, err error, data *Metadata) error {
+	gelf := buildGelfFields(level, msg, err, data)
+	gelf["host"] = h.host
+	gelf["timestamp"] = float64(h.clock().UnixNano()) / float64(time.Second)
+
+	serialized, err := json.Marshal(gelf)
+	if err != nil {
+		return fmt.Errorf("failed to marshal gelf message to JSON: %w", err)
+	}
+
+	if err = h.transport.Send(serialized); err != nil {
+		return fmt.Errorf("can not send gelf message: %w", err)
+	}
+
+	return nil
+}
+
+func (h *GelfHook) Close() error {
+	return h.transport.Close()
+}
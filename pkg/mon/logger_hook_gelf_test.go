@@ -0,0 +1,115 @@
+package mon_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestGelfHook_Fire(t *testing.T) {
+	transport := new(monMocks.GelfTransport)
+	transport.On("Send", mock.AnythingOfType("[]uint8")).Run(func(args mock.Arguments) {
+		gelf := make(map[string]interface{})
+		err := json.Unmarshal(args.Get(0).([]byte), &gelf)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.1", gelf["version"])
+		assert.Equal(t, "something failed", gelf["short_message"])
+		assert.Equal(t, "boom", gelf["_err"])
+		assert.Equal(t, "bar", gelf["_foo"])
+		assert.IsType(t, float64(0), gelf["timestamp"])
+		assert.NotEmpty(t, gelf["host"])
+	}).Return(nil)
+
+	hook := mon.NewGelfHookWithInterfaces(transport)
+	err := hook.Fire(mon.Error, "something failed", fmt.Errorf("boom"), &mon.Metadata{
+		Channel: "default",
+		Fields: mon.Fields{
+			"foo": "bar",
+		},
+	})
+
+	assert.NoError(t, err)
+	transport.AssertExpectations(t)
+}
+
+func TestGelfUdpTransport_Chunking(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+
+	transport := mon.NewGelfUdpTransportWithInterfaces(client, 20)
+	message := bytes.Repeat([]byte("a"), 24)
+
+	chunks := make(chan []byte, 3)
+	go func() {
+		for i := 0; i < 3; i++ {
+			buf := make([]byte, 20)
+			n, _ := server.Read(buf)
+			chunks <- buf[:n]
+		}
+	}()
+
+	err := transport.Send(message)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		chunk := <-chunks
+
+		assert.Equal(t, []byte{0x1e, 0x0f}, chunk[:2])
+		assert.Equal(t, byte(i), chunk[10])
+		assert.Equal(t, byte(3), chunk[11])
+	}
+}
+
+func TestGelfUdpTransport_TooManyChunks(t *testing.T) {
+	_, client := net.Pipe()
+	transport := mon.NewGelfUdpTransportWithInterfaces(client, 13)
+
+	err := transport.Send(bytes.Repeat([]byte("a"), 129))
+	assert.Error(t, err)
+}
+
+func TestGelfUdpTransport_ChunkSizeTooSmall(t *testing.T) {
+	_, err := mon.NewGelfUdpTransport(&mon.GelfSettings{
+		Address:   "127.0.0.1:12201",
+		ChunkSize: 12,
+	})
+
+	assert.EqualError(t, err, "the chunk size of 12 bytes has to be larger than the chunk header of 12 bytes")
+}
+
+func TestGelfTcpTransport_Reconnect(t *testing.T) {
+	dials := 0
+	received := make(chan []byte, 1)
+
+	transport := mon.NewGelfTcpTransportWithInterfaces(func() (net.Conn, error) {
+		dials++
+		server, client := net.Pipe()
+
+		if dials == 1 {
+			_ = server.Close()
+
+			return client, nil
+		}
+
+		go func() {
+			buf := make([]byte, 64)
+			n, _ := server.Read(buf)
+			received <- buf[:n]
+		}()
+
+		return client, nil
+	}, time.Second)
+
+	err := transport.Send([]byte(`{"version":"1.1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, dials)
+	assert.Equal(t, append([]byte(`{"version":"1.1"}`), 0), <-received)
+}
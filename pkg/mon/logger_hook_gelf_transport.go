@@ -0,0 +1,198 @@
+package mon
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	gelfChunkHeaderSize = 12
+	gelfChunkMaxCount   = 128
+)
+
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+type gelfUdpTransport struct {
+	conn      net.Conn
+	chunkSize int
+}
+
+// NewGelfUdpTransport sends every message as a single datagram or, if it does not
+// fit into one datagram, as a sequence of gelf chunks. Every chunk starts with a 12 byte
+// header, so the chunk size has to be larger than that.
+func NewGelfUdpTransport(settings *GelfSettings) (*gelfUdpTransport, error) {
+	if settings.ChunkSize <= gelfChunkHeaderSize {
+		return nil, fmt.Errorf("the chunk size of %d bytes has to be larger than the chunk header of %d bytes", settings.ChunkSize, gelfChunkHeaderSize)
+	}
+
+	conn, err := net.DialTimeout("udp", settings.Address, settings.Timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not dial %s: %w", settings.Address, err)
+	}
+
+	return NewGelfUdpTransportWithInterfaces(conn, settings.ChunkSize), nil
+}
+
+func NewGelfUdpTransportWithInterfaces(conn net.Conn, chunkSize int) *gelfUdpTransport {
+	return &gelfUdpTransport{
+		conn:      conn,
+		chunkSize: chunkSize,
+	}
+}
+
+func (t *gelfUdpTransport) Send(message []byte) error {
+	if len(message) <= t.chunkSize {
+		_, err := t.conn.Write(message)
+
+		return err
+	}
+
+	payloadSize := t.chunkSize - gelfChunkHeaderSize
+	count := (len(message) + payloadSize - 1) / payloadSize
+
+	if count > gelfChunkMaxCount {
+		return fmt.Errorf("message of %d bytes needs %d chunks, but only %d are allowed", len(message), count, gelfChunkMaxCount)
+	}
+
+	messageId := make([]byte, 8)
+	if _, err := rand.Read(messageId); err != nil {
+		return fmt.Errorf("can not generate message id: %w", err)
+	}
+
+	chunk := bytes.NewBuffer(make([]byte, 0, t.chunkSize))
+
+	for i := 0; i < count; i++ {
+		start := i * payloadSize
+		end := start + payloadSize
+
+		if end > len(message) {
+			end = len(message)
+		}
+
+		chunk.Reset()
+		chunk.Write(gelfChunkMagic)
+		chunk.Write(messageId)
+		chunk.WriteByte(byte(i))
+		chunk.WriteByte(byte(count))
+		chunk.Write(message[start:end])
+
+		if _, err := t.conn.Write(chunk.Bytes()); err != nil {
+			return fmt.Errorf("can not write chunk %d of %d: %w", i+1, count, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *gelfUdpTransport) Close() error {
+	return t.conn.Close()
+}
+
+type gelfDialer func() (net.Conn, error)
+
+type gelfTcpTransport struct {
+	lck     sync.Mutex
+	dial    gelfDialer
+	conn    net.Conn
+	timeout time.Duration
+}
+
+// NewGelfTcpTransport sends null byte delimited messages over a (optionally TLS
+// secured) tcp connection. The connection is established lazily and re-established
+// once if writing a message fails.
+func NewGelfTcpTransport(settings *GelfSettings) *gelfTcpTransport {
+	dialer := &net.Dialer{
+		Timeout: settings.Timeout,
+	}
+
+	dial := func() (net.Conn, error) {
+		if settings.Tls {
+			return tls.DialWithDialer(dialer, "tcp", settings.Address, &tls.Config{})
+		}
+
+		return dialer.Dial("tcp", settings.Address)
+	}
+
+	return NewGelfTcpTransportWithInterfaces(dial, settings.Timeout)
+}
+
+func NewGelfTcpTransportWithInterfaces(dial gelfDialer, timeout time.Duration) *gelfTcpTransport {
+	return &gelfTcpTransport{
+		dial:    dial,
+		timeout: timeout,
+	}
+}
+
+func (t *gelfTcpTransport) Send(message []byte) error {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	frame := make([]byte, len(message)+1)
+	copy(frame, message)
+
+	err := t.write(frame)
+
+	if err == nil {
+		return nil
+	}
+
+	// the connection might have been closed by the server, reconnect and retry once
+	t.reset()
+
+	if err = t.write(frame); err != nil {
+		t.reset()
+
+		return fmt.Errorf("can not write message: %w", err)
+	}
+
+	return nil
+}
+
+func (t *gelfTcpTransport) Close() error {
+	t.lck.Lock()
+	defer t.lck.Unlock()
+
+	if t.conn == nil {
+		return nil
+	}
+
+	err := t.conn.Close()
+	t.conn = nil
+
+	return err
+}
+
+func (t *gelfTcpTransport) write(frame []byte) error {
+	var err error
+
+	if t.conn == nil {
+		if t.conn, err = t.dial(); err != nil {
+			return fmt.Errorf("can not connect: %w", err)
+		}
+	}
+
+	if t.timeout > 0 {
+		if err = t.conn.SetWriteDeadline(time.Now().Add(t.timeout)); err != nil {
+			return fmt.Errorf("can not set write deadline: %w", err)
+		}
+	}
+
+	_, err = t.conn.Write(frame)
+
+	return err
+}
+
+func (t *gelfTcpTransport) reset() {
+	if t.conn == nil {
+		return
+	}
+
+	_ = t.conn.Close()
+	t.conn = nil
+}
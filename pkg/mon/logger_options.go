@@ -3,6 +3,7 @@ package mon
 import (
 	"fmt"
 	"io"
+	"sync/atomic"
 )
 
 type LoggerOption func(logger *logger) error
@@ -37,7 +38,7 @@ func WithHook(hook LoggerHook) LoggerOption {
 
 func WithLevel(level string) LoggerOption {
 	return func(logger *logger) error {
-		logger.level = levelPriority(level)
+		atomic.StoreInt32(logger.level, int32(levelPriority(level)))
 
 		return nil
 	}
@@ -253,3 +253,86 @@ func getLogger() (mon.GosoLog, *bytes.Buffer) {
 
 	return client, out
 }
+
+func TestLogger_LazyFields(t *testing.T) {
+	logger, out := getLogger()
+	evaluated := 0
+
+	fields := mon.Fields{
+		"lazy": mon.LazyField(func() interface{} {
+			evaluated++
+			return map[int]string{1: "one"}
+		}),
+		"plain": func() interface{} {
+			evaluated++
+			return "value"
+		},
+	}
+
+	logger.WithFields(fields).Debug("filtered")
+	assert.Equal(t, 0, evaluated)
+	assert.Empty(t, out.String())
+
+	logger.WithFields(fields).Info("written")
+	assert.Equal(t, 2, evaluated)
+
+	log := make(map[string]interface{})
+	err := json.Unmarshal(out.Bytes(), &log)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"lazy":  map[string]interface{}{"1": "one"},
+		"plain": "value",
+	}, log["fields"])
+}
+
+type countingError struct {
+	calls int
+}
+
+func (e *countingError) Error() string {
+	e.calls++
+
+	return "counted"
+}
+
+func TestLogger_WithFieldsProcessedAfterLevelCheck(t *testing.T) {
+	logger, out := getLogger()
+	err := &countingError{}
+
+	fieldLogger := logger.WithFields(mon.Fields{
+		"err": err,
+	})
+	assert.Equal(t, 0, err.calls)
+
+	fieldLogger.Debug("filtered")
+	assert.Equal(t, 0, err.calls)
+	assert.Empty(t, out.String())
+
+	fieldLogger.Info("written")
+	assert.Equal(t, 1, err.calls)
+
+	expected := `{"fields":{"err":"counted"},"context":{},"channel": "default", "level":2,"level_name":"info","message":"written","timestamp":"1984-04-04T00:00:00Z"}`
+	assert.JSONEq(t, expected, out.String(), "output should match")
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	logger, out := getLogger()
+	channelLogger := logger.WithChannel("channel")
+
+	channelLogger.Debug("filtered")
+	assert.Empty(t, out.String())
+
+	switcher := logger.(mon.LevelSwitcher)
+	assert.Equal(t, mon.Info, switcher.Level())
+
+	err := switcher.SetLevel(mon.Debug)
+	assert.NoError(t, err)
+	assert.Equal(t, mon.Debug, channelLogger.(mon.LevelSwitcher).Level())
+
+	channelLogger.Debug("written")
+	assert.Contains(t, out.String(), `"message":"written"`)
+
+	err = switcher.SetLevel("verbose")
+	assert.EqualError(t, err, "unknown log level: verbose")
+}
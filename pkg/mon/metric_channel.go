@@ -22,6 +22,15 @@ func ProviderMetricChannel() *metricChannel {
 	return metricChannelContainer.instance
 }
 
+// ResetMetricChannel drops the channel, so the next metric daemon gets a new one after the old one was closed on the
+// shutdown of an application, e.g. if tests run the application more than once
+func ResetMetricChannel() {
+	metricChannelContainer.Lock()
+	defer metricChannelContainer.Unlock()
+
+	metricChannelContainer.instance = nil
+}
+
 type metricChannel struct {
 	lck     sync.RWMutex
 	logger  Logger
@@ -63,6 +72,11 @@ func (c *metricChannel) close() {
 	c.lck.Lock()
 	defer c.lck.Unlock()
 
+	// more than one application can run in the same process, e.g. in tests, and all of them close the channel
+	if c.closed {
+		return
+	}
+
 	close(c.c)
 	c.closed = true
 }
@@ -11,6 +11,24 @@ import (
 
 const defaultTimeFormat = "2006-01-02T15:04Z07:00"
 
+var metricSnapshot = struct {
+	sync.Mutex
+	data MetricData
+}{
+	data: make(MetricData, 0),
+}
+
+// GetMetricSnapshot returns the data points most recently published by the metric daemon.
+func GetMetricSnapshot() MetricData {
+	metricSnapshot.Lock()
+	defer metricSnapshot.Unlock()
+
+	snapshot := make(MetricData, len(metricSnapshot.data))
+	copy(snapshot, metricSnapshot.data)
+
+	return snapshot
+}
+
 type MetricSettings struct {
 	cfg.AppId
 	Enabled  bool          `cfg:"enabled" default:"false"`
@@ -201,6 +219,10 @@ func (d *MetricDaemon) publish() {
 		w.Write(data)
 	}
 
+	metricSnapshot.Lock()
+	metricSnapshot.data = data
+	metricSnapshot.Unlock()
+
 	d.logger.Infof("published %d data points in %d metrics", d.dataPointCount, size)
 	d.resetBatch()
 }
@@ -239,6 +261,12 @@ func (d *MetricDaemon) calcValue(unit string, values []float64) (string, float64
 	case UnitSecondsAverage:
 		unit = UnitSeconds
 		value = average(values)
+	case UnitBytesAverage:
+		unit = UnitBytes
+		value = average(values)
+	case UnitPercentAverage:
+		unit = UnitPercent
+		value = average(values)
 	default:
 		value = sum(values)
 	}
@@ -23,6 +23,10 @@ const (
 	UnitSecondsAverage      = "UnitSecondsAverage"
 	UnitMilliseconds        = cloudwatch.StandardUnitMilliseconds
 	UnitMillisecondsAverage = "UnitMillisecondsAverage"
+	UnitBytes               = cloudwatch.StandardUnitBytes
+	UnitBytesAverage        = "UnitBytesAverage"
+	UnitPercent             = cloudwatch.StandardUnitPercent
+	UnitPercentAverage      = "UnitPercentAverage"
 
 	chunkSizeCloudWatch = 20
 	minusOneWeek        = -1 * 7 * 24 * time.Hour
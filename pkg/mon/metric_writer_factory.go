@@ -6,8 +6,9 @@ import (
 )
 
 const (
-	MetricWriterTypeCw = "cw"
-	MetricWriterTypeES = "es"
+	MetricWriterTypeCw     = "cw"
+	MetricWriterTypeES     = "es"
+	MetricWriterTypeMemory = "memory"
 )
 
 func ProvideMetricWriterByType(config cfg.Config, logger Logger, typ string) (MetricWriter, error) {
@@ -16,6 +17,8 @@ func ProvideMetricWriterByType(config cfg.Config, logger Logger, typ string) (Me
 		return NewMetricCwWriter(config, logger)
 	case MetricWriterTypeES:
 		return NewMetricEsWriter(config, logger)
+	case MetricWriterTypeMemory:
+		return ProvideNamespacedMemoryMetricWriter(config.GetString(MemoryMetricWriterNamespaceKey, "")), nil
 	}
 
 	return nil, fmt.Errorf("metric writer type of %s not found", typ)
@@ -0,0 +1,74 @@
+package mon
+
+import "sync"
+
+// MemoryMetricWriterNamespaceKey is the config key of the namespace of the memory metric writer
+const MemoryMetricWriterNamespaceKey = "mon.metric.namespace"
+
+var metricMemoryWriterContainer = struct {
+	sync.Mutex
+	instances map[string]*MemoryMetricWriter
+}{
+	instances: map[string]*MemoryMetricWriter{},
+}
+
+// MemoryMetricWriter keeps the data published by the metric daemon, e.g. to assert on the metrics of an application
+// in tests. All writers of the memory type with the same namespace share the same instance.
+type MemoryMetricWriter struct {
+	lck  sync.Mutex
+	data MetricData
+}
+
+func ProvideMemoryMetricWriter() *MemoryMetricWriter {
+	return ProvideNamespacedMemoryMetricWriter("")
+}
+
+// ProvideNamespacedMemoryMetricWriter returns the writer of the namespace configured at mon.metric.namespace, so
+// applications running in parallel in the same process, e.g. tests, don't reset the data of each other.
+func ProvideNamespacedMemoryMetricWriter(namespace string) *MemoryMetricWriter {
+	metricMemoryWriterContainer.Lock()
+	defer metricMemoryWriterContainer.Unlock()
+
+	if instance, ok := metricMemoryWriterContainer.instances[namespace]; ok {
+		return instance
+	}
+
+	metricMemoryWriterContainer.instances[namespace] = &MemoryMetricWriter{
+		data: make(MetricData, 0),
+	}
+
+	return metricMemoryWriterContainer.instances[namespace]
+}
+
+func (w *MemoryMetricWriter) GetPriority() int {
+	return PriorityLow
+}
+
+func (w *MemoryMetricWriter) WriteOne(data *MetricDatum) {
+	w.Write(MetricData{data})
+}
+
+func (w *MemoryMetricWriter) Write(batch MetricData) {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	w.data = append(w.data, batch...)
+}
+
+// Data returns all data written since the writer was created or reset
+func (w *MemoryMetricWriter) Data() MetricData {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	data := make(MetricData, len(w.data))
+	copy(data, w.data)
+
+	return data
+}
+
+func (w *MemoryMetricWriter) Reset() {
+	w.lck.Lock()
+	defer w.lck.Unlock()
+
+	w.data = make(MetricData, 0)
+}
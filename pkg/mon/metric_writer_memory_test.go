@@ -0,0 +1,29 @@
+package mon_test
+
+import (
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMemoryMetricWriter_Namespaces(t *testing.T) {
+	first := mon.ProvideNamespacedMemoryMetricWriter("first")
+	second := mon.ProvideNamespacedMemoryMetricWriter("second")
+
+	assert.Same(t, first, mon.ProvideNamespacedMemoryMetricWriter("first"))
+
+	first.WriteOne(&mon.MetricDatum{
+		MetricName: "foo",
+		Value:      1,
+	})
+	second.WriteOne(&mon.MetricDatum{
+		MetricName: "bar",
+		Value:      2,
+	})
+
+	second.Reset()
+
+	assert.Len(t, first.Data(), 1)
+	assert.Equal(t, "foo", first.Data()[0].MetricName)
+	assert.Len(t, second.Data(), 0)
+}
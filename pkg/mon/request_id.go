@@ -0,0 +1,63 @@
+package mon
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	HeaderRequestId           = "X-Request-Id"
+	MessageAttributeRequestId = "requestId"
+	LoggerFieldRequestId      = "request_id"
+
+	requestIdKey key = 1
+)
+
+// WithRequestId returns a new context carrying the request id. The id is added to the logger context fields,
+// so every log message written with the context contains it.
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	ctx = context.WithValue(ctx, requestIdKey, requestId)
+
+	return AppendLoggerContextField(ctx, map[string]interface{}{
+		LoggerFieldRequestId: requestId,
+	})
+}
+
+// RequestIdFromContext returns the request id stored by WithRequestId.
+func RequestIdFromContext(ctx context.Context) (string, bool) {
+	requestId, ok := ctx.Value(requestIdKey).(string)
+
+	return requestId, ok && requestId != ""
+}
+
+// MessageWithRequestIdEncoder forwards the request id of the context as message attribute to the consumers.
+type MessageWithRequestIdEncoder struct{}
+
+func NewMessageWithRequestIdEncoder() *MessageWithRequestIdEncoder {
+	return &MessageWithRequestIdEncoder{}
+}
+
+func (m MessageWithRequestIdEncoder) Encode(ctx context.Context, _ interface{}, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
+	if requestId, ok := RequestIdFromContext(ctx); ok {
+		attributes[MessageAttributeRequestId] = requestId
+	}
+
+	return ctx, attributes, nil
+}
+
+func (m MessageWithRequestIdEncoder) Decode(ctx context.Context, _ interface{}, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
+	if _, ok := attributes[MessageAttributeRequestId]; !ok {
+		return ctx, attributes, nil
+	}
+
+	requestId, ok := attributes[MessageAttributeRequestId].(string)
+
+	if !ok {
+		return ctx, attributes, fmt.Errorf("the %s attribute should be of type string but is %T", MessageAttributeRequestId, attributes[MessageAttributeRequestId])
+	}
+
+	ctx = WithRequestId(ctx, requestId)
+	delete(attributes, MessageAttributeRequestId)
+
+	return ctx, attributes, nil
+}
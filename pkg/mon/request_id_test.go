@@ -0,0 +1,26 @@
+package mon_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessageWithRequestIdEncoder(t *testing.T) {
+	encoder := mon.NewMessageWithRequestIdEncoder()
+	ctx := mon.WithRequestId(context.Background(), "abc-123")
+
+	_, attributes, err := encoder.Encode(ctx, nil, map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{mon.MessageAttributeRequestId: "abc-123"}, attributes)
+
+	ctx, attributes, err = encoder.Decode(context.Background(), nil, attributes)
+	assert.NoError(t, err)
+	assert.Empty(t, attributes)
+
+	requestId, ok := mon.RequestIdFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "abc-123", requestId)
+	assert.Equal(t, map[string]interface{}{mon.LoggerFieldRequestId: "abc-123"}, mon.ContextLoggerFieldsResolver(ctx))
+}
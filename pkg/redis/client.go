@@ -32,9 +32,13 @@ type Client interface {
 	Get(ctx context.Context, key string) (string, error)
 	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
 	MSet(ctx context.Context, pairs ...interface{}) error
+	Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error)
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
 
+	// Eval runs the lua script atomically with the keys and args available as KEYS and ARGV
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+
 	BLPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error)
 	LPop(ctx context.Context, key string) (string, error)
 	LLen(ctx context.Context, key string) (int64, error)
@@ -70,24 +74,61 @@ type redisClient struct {
 func NewClient(config cfg.Config, logger mon.Logger, name string) (Client, error) {
 	settings := ReadSettings(config, name)
 
+	return NewClientWithSettings(logger, settings)
+}
+
+func NewClientWithSettings(logger mon.Logger, settings *Settings) (Client, error) {
 	logger = logger.WithFields(mon.Fields{
-		"redis": name,
+		"redis": settings.Name,
 	})
 
-	executor := NewExecutor(logger, settings.BackoffSettings, name)
+	executor := NewExecutor(logger, settings.BackoffSettings, settings.Name)
 
-	if _, ok := dialers[settings.Dialer]; !ok {
-		return nil, fmt.Errorf("there is no redis dialer of type %s", settings.Dialer)
+	baseClient, err := newBaseClient(logger, settings)
+	if err != nil {
+		return nil, err
 	}
 
-	dialer := dialers[settings.Dialer](logger, settings)
-	baseClient := baseRedis.NewClient(&baseRedis.Options{
-		Dialer: dialer,
-	})
-
 	return NewClientWithInterfaces(logger, baseClient, executor, settings), nil
 }
 
+func newBaseClient(logger mon.Logger, settings *Settings) (baseRedis.Cmdable, error) {
+	switch settings.Mode {
+	case ModeStandalone, "":
+		if _, ok := dialers[settings.Dialer]; !ok {
+			return nil, fmt.Errorf("there is no redis dialer of type %s", settings.Dialer)
+		}
+
+		dialer := dialers[settings.Dialer](logger, settings)
+
+		return baseRedis.NewClient(&baseRedis.Options{
+			Dialer: dialer,
+		}), nil
+
+	case ModeCluster:
+		logger.Infof("using cluster nodes %v for redis %s", settings.GetAddresses(), settings.Name)
+
+		// the nodes of a cluster are discovered from the given ones, so we can't use our dialers here
+		return baseRedis.NewClusterClient(&baseRedis.ClusterOptions{
+			Addrs: settings.GetAddresses(),
+		}), nil
+
+	case ModeSentinel:
+		if settings.MasterName == "" {
+			return nil, fmt.Errorf("there is no master name for the redis sentinel mode of %s", settings.Name)
+		}
+
+		logger.Infof("using sentinels %v with master %s for redis %s", settings.GetAddresses(), settings.MasterName, settings.Name)
+
+		return baseRedis.NewFailoverClient(&baseRedis.FailoverOptions{
+			MasterName:    settings.MasterName,
+			SentinelAddrs: settings.GetAddresses(),
+		}), nil
+	}
+
+	return nil, fmt.Errorf("there is no redis mode %s", settings.Mode)
+}
+
 func NewClientWithInterfaces(logger mon.Logger, baseRedis baseRedis.Cmdable, executor exec.Executor, settings *Settings) Client {
 	return &redisClient{
 		logger:   logger,
@@ -195,6 +236,16 @@ func (c *redisClient) Del(ctx context.Context, keys ...string) (int64, error) {
 	return cmd.(*baseRedis.IntCmd).Val(), err
 }
 
+func (c *redisClient) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	cmd, err := c.execute(ctx, func() ErrCmder {
+		return c.base.Scan(ctx, cursor, match, count)
+	})
+
+	keys, next := cmd.(*baseRedis.ScanCmd).Val()
+
+	return keys, next, err
+}
+
 func (c *redisClient) BLPop(ctx context.Context, timeout time.Duration, keys ...string) ([]string, error) {
 	cmd, err := c.execute(ctx, func() ErrCmder {
 		return c.base.BLPop(ctx, timeout, keys...)
@@ -327,6 +378,14 @@ func (c *redisClient) Expire(ctx context.Context, key string, ttl time.Duration)
 	return cmd.(*baseRedis.BoolCmd).Val(), err
 }
 
+func (c *redisClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	cmd, err := c.execute(ctx, func() ErrCmder {
+		return c.base.Eval(ctx, script, keys, args...)
+	})
+
+	return cmd.(*baseRedis.Cmd).Val(), err
+}
+
 func (c *redisClient) IsAlive(ctx context.Context) bool {
 	cmd, err := c.execute(ctx, func() ErrCmder {
 		return c.base.Ping(ctx)
@@ -8,31 +8,59 @@ import (
 	"sync"
 )
 
+const (
+	ModeStandalone = "standalone"
+	ModeCluster    = "cluster"
+	ModeSentinel   = "sentinel"
+)
+
 type Settings struct {
 	cfg.AppId
-	Name            string               `cfg:"name"`
-	Dialer          string               `cfg:"dialer" default:"tcp"`
-	Address         string               `cfg:"address" default:"127.0.0.1:6379"`
+	Name    string `cfg:"name"`
+	Mode    string `cfg:"mode" default:"standalone"`
+	Dialer  string `cfg:"dialer" default:"tcp"`
+	Address string `cfg:"address" default:"127.0.0.1:6379"`
+	// the nodes of a cluster or the sentinels watching the master, defaults to the address
+	Addresses []string `cfg:"addresses"`
+	// the name of the master monitored by the sentinels
+	MasterName      string               `cfg:"master_name"`
 	BackoffSettings exec.BackoffSettings `cfg:"backoff"`
 }
 
+func (s *Settings) GetAddresses() []string {
+	if len(s.Addresses) > 0 {
+		return s.Addresses
+	}
+
+	return []string{s.Address}
+}
+
 var mutex sync.Mutex
 var clients = map[string]Client{}
 
 func ProvideClient(config cfg.Config, logger mon.Logger, name string) (Client, error) {
+	settings := ReadSettings(config, name)
+
+	return ProvideClientWithSettings(logger, settings)
+}
+
+// ProvideClientWithSettings returns the client with the name of the settings and creates it on the first call.
+func ProvideClientWithSettings(logger mon.Logger, settings *Settings) (Client, error) {
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	if client, ok := clients[name]; ok {
+	if client, ok := clients[settings.Name]; ok {
 		return client, nil
 	}
 
-	var err error
-	if clients[name], err = NewClient(config, logger, name); err != nil {
+	client, err := NewClientWithSettings(logger, settings)
+	if err != nil {
 		return nil, err
 	}
 
-	return clients[name], nil
+	clients[settings.Name] = client
+
+	return client, nil
 }
 
 func ReadSettings(config cfg.Config, name string) *Settings {
@@ -3,6 +3,7 @@ package redis_test
 import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/redis"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -43,9 +44,11 @@ func (s *FactoryTestSuite) TestDefault() {
 			Family:      "test",
 			Application: "redis",
 		},
-		Name:    "default",
-		Dialer:  "tcp",
-		Address: "127.0.0.1:6379",
+		Name:      "default",
+		Mode:      "standalone",
+		Dialer:    "tcp",
+		Address:   "127.0.0.1:6379",
+		Addresses: []string{},
 		BackoffSettings: exec.BackoffSettings{
 			Enabled:             false,
 			Blocking:            false,
@@ -83,9 +86,11 @@ func (s *FactoryTestSuite) TestDedicated() {
 			Family:      "test",
 			Application: "redis",
 		},
-		Name:    "dedicated",
-		Dialer:  "srv",
-		Address: "dedicated.address",
+		Name:      "dedicated",
+		Mode:      "standalone",
+		Dialer:    "srv",
+		Address:   "dedicated.address",
+		Addresses: []string{},
 		BackoffSettings: exec.BackoffSettings{
 			Enabled:             false,
 			Blocking:            false,
@@ -125,9 +130,11 @@ func (s *FactoryTestSuite) TestWithDefaults() {
 			Family:      "test",
 			Application: "redis",
 		},
-		Name:    "partial",
-		Dialer:  "srv",
-		Address: "partial.address",
+		Name:      "partial",
+		Mode:      "standalone",
+		Dialer:    "srv",
+		Address:   "partial.address",
+		Addresses: []string{},
 		BackoffSettings: exec.BackoffSettings{
 			Enabled:             false,
 			Blocking:            false,
@@ -143,6 +150,38 @@ func (s *FactoryTestSuite) TestWithDefaults() {
 	s.Equal(expected, settings)
 }
 
+func (s *FactoryTestSuite) TestCluster() {
+	s.initConfig(map[string]interface{}{
+		"redis": map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"mode":      "cluster",
+				"addresses": []string{"node-1:6379", "node-2:6379"},
+			},
+		},
+	})
+
+	settings := redis.ReadSettings(s.config, "cluster")
+
+	s.Equal("cluster", settings.Mode)
+	s.Equal([]string{"node-1:6379", "node-2:6379"}, settings.GetAddresses())
+}
+
+func (s *FactoryTestSuite) TestSentinelWithoutMasterName() {
+	s.initConfig(map[string]interface{}{
+		"redis": map[string]interface{}{
+			"sentinel": map[string]interface{}{
+				"mode": "sentinel",
+			},
+		},
+	})
+
+	settings := redis.ReadSettings(s.config, "sentinel")
+	s.Equal([]string{"127.0.0.1:6379"}, settings.GetAddresses())
+
+	_, err := redis.NewClientWithSettings(mocks.NewLoggerMockedAll(), settings)
+	s.EqualError(err, "there is no master name for the redis sentinel mode of sentinel")
+}
+
 func TestFactoryTestSuite(t *testing.T) {
 	suite.Run(t, new(FactoryTestSuite))
 }
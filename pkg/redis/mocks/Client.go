@@ -134,6 +134,32 @@ func (_m *Client) Del(ctx context.Context, keys ...string) (int64, error) {
 	return r0, r1
 }
 
+// Eval provides a mock function with given fields: ctx, script, keys, args
+func (_m *Client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, script, keys)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, ...interface{}) interface{}); ok {
+		r0 = rf(ctx, script, keys, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, ...interface{}) error); ok {
+		r1 = rf(ctx, script, keys, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Exists provides a mock function with given fields: ctx, keys
 func (_m *Client) Exists(ctx context.Context, keys ...string) (int64, error) {
 	_va := make([]interface{}, len(keys))
@@ -605,6 +631,36 @@ func (_m *Client) RPush(ctx context.Context, key string, values ...interface{})
 	return r0, r1
 }
 
+// Scan provides a mock function with given fields: ctx, cursor, match, count
+func (_m *Client) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	ret := _m.Called(ctx, cursor, match, count)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, int64) []string); ok {
+		r0 = rf(ctx, cursor, match, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 uint64
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, string, int64) uint64); ok {
+		r1 = rf(ctx, cursor, match, count)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, string, int64) error); ok {
+		r2 = rf(ctx, cursor, match, count)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Set provides a mock function with given fields: ctx, key, value, ttl
 func (_m *Client) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	ret := _m.Called(ctx, key, value, ttl)
@@ -79,7 +79,7 @@ func (m *Map) NewElement() interface{} {
 func (m *Map) Set(key interface{}, value interface{}) error {
 	keyValue := reflect.ValueOf(key)
 
-	if keyValue.Type() != m.keyType {
+	if !keyValue.Type().AssignableTo(m.keyType) {
 		return fmt.Errorf("provided key should be of type %v but instead is %v", m.keyType, keyValue.Type())
 	}
 
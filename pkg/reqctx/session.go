@@ -0,0 +1,41 @@
+// Package reqctx holds the values scoped to a single request or message which are carried in its context and read by
+// several packages, so none of them has to depend on the package setting the value.
+package reqctx
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type sessionKeyType int
+
+var sessionKey = new(sessionKeyType)
+
+type session struct {
+	written int32
+}
+
+// WithSession starts a session for reading your own writes. Once something was written using the context of the
+// session, reads of the session should be done on the primary instead of a replica, so they don't miss the write
+// because of the replication lag. The api server and the stream consumers start a session per request or message.
+func WithSession(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(sessionKey).(*session); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, sessionKey, &session{})
+}
+
+// MarkWritten records a write in the session of the context, it does nothing without a session
+func MarkWritten(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey).(*session); ok {
+		atomic.StoreInt32(&s.written, 1)
+	}
+}
+
+// HasWritten returns whether something was written in the session of the context
+func HasWritten(ctx context.Context) bool {
+	s, ok := ctx.Value(sessionKey).(*session)
+
+	return ok && atomic.LoadInt32(&s.written) == 1
+}
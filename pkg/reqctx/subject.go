@@ -0,0 +1,28 @@
+package reqctx
+
+import "context"
+
+type subjectKeyType int
+
+var subjectKey = new(subjectKeyType)
+
+// Subject is the authenticated caller of a request. It is stored by the authenticators of the api server and read by
+// everything recording who did something, like the audit trail of the repositories.
+type Subject struct {
+	Name            string
+	Anonymous       bool
+	AuthenticatedBy string
+	Attributes      map[string]interface{}
+}
+
+// WithSubject stores the subject of the request in the context
+func WithSubject(ctx context.Context, subject *Subject) context.Context {
+	return context.WithValue(ctx, subjectKey, subject)
+}
+
+// LookupSubject returns the subject of the request or false if the request was not authenticated
+func LookupSubject(ctx context.Context) (*Subject, bool) {
+	subject, ok := ctx.Value(subjectKey).(*Subject)
+
+	return subject, ok && subject != nil
+}
@@ -0,0 +1,20 @@
+package reqctx
+
+import "context"
+
+type tenantKeyType int
+
+var tenantKey = new(tenantKeyType)
+
+// WithTenant stores the tenant of the request or message in the context. It is used by the tenant kv store to
+// prefix its keys and by the feature flags to evaluate the value of the tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// TenantFromContext returns the tenant stored with WithTenant or false if there is none
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey).(string)
+
+	return tenant, ok && tenant != ""
+}
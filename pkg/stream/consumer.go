@@ -6,6 +6,7 @@ import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/kernel"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/reqctx"
 	"sync/atomic"
 )
 
@@ -126,6 +127,9 @@ func (c *Consumer) processSingleMessage(ctx context.Context, msg *Message) {
 func (c *Consumer) process(ctx context.Context, msg *Message) bool {
 	defer c.recover()
 
+	// every message reads its own writes, even if the database has replicas
+	ctx = reqctx.WithSession(ctx)
+
 	var err error
 	var ack bool
 	var model interface{}
@@ -6,6 +6,7 @@ import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/kernel"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/reqctx"
 	"github.com/applike/gosoline/pkg/tracing"
 	"sync/atomic"
 	"time"
@@ -150,8 +151,11 @@ func (c *BatchConsumer) consumeBatch(kernelCtx context.Context, batch []*Message
 
 	start := c.clock.Now()
 
+	// the batch reads its own writes, even if the database has replicas
+	sessionCtx := reqctx.WithSession(kernelCtx)
+
 	// make sure to create new context as we can't rely on the tracer to create a new one
-	batchCtx, cancel := context.WithCancel(kernelCtx)
+	batchCtx, cancel := context.WithCancel(sessionCtx)
 	defer cancel()
 
 	var span tracing.Span
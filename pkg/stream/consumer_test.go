@@ -89,7 +89,7 @@ func (s *ConsumerTestSuite) TestRun() {
 	s.input.On("Stop")
 
 	consumed := make([]*string, 0)
-	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), map[string]interface{}{}).
+	s.callback.On("Consume", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*string"), map[string]interface{}{}).
 		Run(func(args mock.Arguments) {
 			consumed = append(consumed, args[1].(*string))
 		}).Return(true, nil)
@@ -207,7 +207,7 @@ func (s *ConsumerTestSuite) TestRun_CallbackRunPanic() {
 	s.callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).
 		Return(nil)
 
-	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), map[string]interface{}{}).
+	s.callback.On("Consume", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*string"), map[string]interface{}{}).
 		Run(func(args mock.Arguments) {
 			ptr := args.Get(1).(*string)
 			consumed = append(consumed, ptr)
@@ -262,7 +262,7 @@ func (s *ConsumerTestSuite) TestRun_AggregateMessage() {
 		Return(nil)
 
 	expectedAttributes1 := map[string]interface{}{"attr1": "a"}
-	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), expectedAttributes1).
+	s.callback.On("Consume", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*string"), expectedAttributes1).
 		Run(func(args mock.Arguments) {
 			ptr := args.Get(1).(*string)
 			consumed = append(consumed, *ptr)
@@ -274,7 +274,7 @@ func (s *ConsumerTestSuite) TestRun_AggregateMessage() {
 		Return(mdl.String(""))
 
 	expectedAttributes2 := map[string]interface{}{"attr1": "b"}
-	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), expectedAttributes2).
+	s.callback.On("Consume", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*string"), expectedAttributes2).
 		Run(func(args mock.Arguments) {
 			ptr := args.Get(1).(*string)
 			consumed = append(consumed, *ptr)
@@ -84,7 +84,7 @@ func newInMemoryInputFromConfig(config cfg.Config, _ mon.Logger, name string) (I
 	settings := &InMemorySettings{}
 	config.UnmarshalKey(key, settings)
 
-	return ProvideInMemoryInput(name, settings), nil
+	return ProvideInMemoryInput(NamespacedName(config, name), settings), nil
 }
 
 type kinesisInputConfiguration struct {
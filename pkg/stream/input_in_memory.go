@@ -8,12 +8,15 @@ import (
 var inMemoryInputsLock sync.Mutex
 var inMemoryInputs = make(map[string]*InMemoryInput)
 
-func ResetInMemoryInputs() {
+// ResetInMemoryInputs resets the inputs of the namespaces or all inputs if no namespace is given
+func ResetInMemoryInputs(namespaces ...string) {
 	inMemoryInputsLock.Lock()
 	defer inMemoryInputsLock.Unlock()
 
-	for _, inp := range inMemoryInputs {
-		inp.Reset()
+	for name, inp := range inMemoryInputs {
+		if inNamespaces(name, namespaces) {
+			inp.Reset()
+		}
 	}
 }
 
@@ -23,8 +26,10 @@ type InMemorySettings struct {
 
 type InMemoryInput struct {
 	once     sync.Once
+	lck      sync.Mutex
 	channel  chan *Message
 	stopped  chan struct{}
+	acked    []*Message
 	settings *InMemorySettings
 }
 
@@ -45,6 +50,7 @@ func NewInMemoryInput(settings *InMemorySettings) *InMemoryInput {
 	return &InMemoryInput{
 		channel:  make(chan *Message, settings.Size),
 		stopped:  make(chan struct{}),
+		acked:    make([]*Message, 0),
 		settings: settings,
 	}
 }
@@ -53,6 +59,10 @@ func (i *InMemoryInput) Reset() {
 	i.once = sync.Once{}
 	i.channel = make(chan *Message, i.settings.Size)
 	i.stopped = make(chan struct{})
+
+	i.lck.Lock()
+	i.acked = make([]*Message, 0)
+	i.lck.Unlock()
 }
 
 func (i *InMemoryInput) Publish(messages ...*Message) {
@@ -80,3 +90,28 @@ func (i *InMemoryInput) Stop() {
 func (i *InMemoryInput) Data() chan *Message {
 	return i.channel
 }
+
+// Ack records the message as acknowledged, so tests can check which messages a consumer has processed
+func (i *InMemoryInput) Ack(msg *Message) error {
+	return i.AckBatch([]*Message{msg})
+}
+
+func (i *InMemoryInput) AckBatch(msgs []*Message) error {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	i.acked = append(i.acked, msgs...)
+
+	return nil
+}
+
+// Acknowledged returns the messages acknowledged since the input was created or reset
+func (i *InMemoryInput) Acknowledged() []*Message {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	acked := make([]*Message, len(i.acked))
+	copy(acked, i.acked)
+
+	return acked
+}
@@ -39,6 +39,19 @@ func (s *InMemoryInputTestSuite) TestRun() {
 	s.Equal("content", msg.Body, "message body should contain content")
 }
 
+func (s *InMemoryInputTestSuite) TestAck() {
+	msg1 := stream.NewMessage("1")
+	msg2 := stream.NewMessage("2")
+	msg3 := stream.NewMessage("3")
+
+	s.NoError(s.input.Ack(msg1))
+	s.NoError(s.input.AckBatch([]*stream.Message{msg2, msg3}))
+	s.Equal([]*stream.Message{msg1, msg2, msg3}, s.input.Acknowledged())
+
+	s.input.Reset()
+	s.Len(s.input.Acknowledged(), 0, "there should be no acknowledged messages after a reset")
+}
+
 func TestInMemoryInputSuite(t *testing.T) {
 	suite.Run(t, new(InMemoryInputTestSuite))
 }
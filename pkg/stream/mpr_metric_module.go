@@ -85,7 +85,7 @@ func NewMessagesPerRunnerMetricWriter(settings *MessagesPerRunnerMetricSettings)
 
 		cwClient := mon.ProvideCloudWatchClient(config)
 		metricWriter := mon.NewMetricDaemonWriter()
-		ticker := clock.NewRealTicker(settings.Period)
+		ticker := clock.NewTicker(settings.Period)
 
 		return NewMessagesPerRunnerMetricWriterWithInterfaces(logger, leaderElection, cwClient, metricWriter, clock.Provider, ticker, writerSettings)
 	}
@@ -0,0 +1,38 @@
+package stream
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"strings"
+)
+
+// NamespaceKey is the config key of the namespace of the in memory inputs and outputs and the producer daemons
+const NamespaceKey = "stream.namespace"
+
+// NamespacedName prefixes the name of an in memory input or output or a producer daemon with the namespace configured
+// at stream.namespace. These are shared per name in the process, so applications running in parallel in the same
+// process, e.g. tests, need different namespaces to not share them.
+func NamespacedName(config cfg.Config, name string) string {
+	namespace := config.GetString(NamespaceKey, "")
+
+	if namespace == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// inNamespaces is true for all names if no namespaces are given
+func inNamespaces(name string, namespaces []string) bool {
+	if len(namespaces) == 0 {
+		return true
+	}
+
+	for _, namespace := range namespaces {
+		if strings.HasPrefix(name, namespace+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -66,8 +66,8 @@ func newFileOutputFromConfig(config cfg.Config, logger mon.Logger, name string)
 	return NewFileOutput(config, logger, settings), nil
 }
 
-func newInMemoryOutputFromConfig(_ cfg.Config, _ mon.Logger, name string) (Output, error) {
-	return ProvideInMemoryOutput(name), nil
+func newInMemoryOutputFromConfig(config cfg.Config, _ mon.Logger, name string) (Output, error) {
+	return ProvideInMemoryOutput(NamespacedName(config, name)), nil
 }
 
 type kinesisOutputConfiguration struct {
@@ -8,12 +8,15 @@ import (
 var inMemoryOutputsLock sync.Mutex
 var inMemoryOutputs = make(map[string]*InMemoryOutput)
 
-func ResetInMemoryOutputs() {
+// ResetInMemoryOutputs clears the outputs of the namespaces or all outputs if no namespace is given
+func ResetInMemoryOutputs(namespaces ...string) {
 	inMemoryOutputsLock.Lock()
 	defer inMemoryOutputsLock.Unlock()
 
-	for _, inp := range inMemoryOutputs {
-		inp.Clear()
+	for name, inp := range inMemoryOutputs {
+		if inNamespaces(name, namespaces) {
+			inp.Clear()
+		}
 	}
 }
 
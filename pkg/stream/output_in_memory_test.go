@@ -2,6 +2,7 @@ package stream_test
 
 import (
 	"context"
+	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/stream"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -29,6 +30,29 @@ func (s *InMemoryOutputTestSuite) TestWrite() {
 	s.Equal("content", written.Body, "the body of the message should match")
 }
 
+func (s *InMemoryOutputTestSuite) TestResetNamespace() {
+	stream.ResetInMemoryOutputs()
+
+	config := cfg.New()
+	err := config.Option(cfg.WithConfigSetting(stream.NamespaceKey, "a"))
+	s.NoError(err)
+
+	namespaced := stream.ProvideInMemoryOutput(stream.NamespacedName(config, "test"))
+	s.NotSame(s.output, namespaced, "the namespaced output should not be shared with the plain one")
+
+	s.NoError(s.output.WriteOne(context.Background(), stream.NewMessage("plain")))
+	s.NoError(namespaced.WriteOne(context.Background(), stream.NewMessage("namespaced")))
+
+	stream.ResetInMemoryOutputs("a")
+
+	s.Equal(1, s.output.Len(), "the output outside of the namespace should be kept")
+	s.Equal(0, namespaced.Len(), "the output of the namespace should be cleared")
+
+	stream.ResetInMemoryOutputs()
+
+	s.Equal(0, s.output.Len(), "all outputs should be cleared")
+}
+
 func TestInMemoryOutputTestSuite(t *testing.T) {
 	suite.Run(t, new(InMemoryOutputTestSuite))
 }
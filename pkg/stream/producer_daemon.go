@@ -53,29 +53,36 @@ type ProducerDaemon struct {
 	settings      ProducerDaemonSettings
 }
 
-func ResetProducerDaemons() {
+// ResetProducerDaemons drops the daemons of the namespaces or all daemons if no namespace is given
+func ResetProducerDaemons(namespaces ...string) {
 	producerDaemonLock.Lock()
 	defer producerDaemonLock.Unlock()
 
-	producerDaemons = map[string]*ProducerDaemon{}
+	for name := range producerDaemons {
+		if inNamespaces(name, namespaces) {
+			delete(producerDaemons, name)
+		}
+	}
 }
 
 func ProvideProducerDaemon(config cfg.Config, logger mon.Logger, name string) (*ProducerDaemon, error) {
 	producerDaemonLock.Lock()
 	defer producerDaemonLock.Unlock()
 
-	if _, ok := producerDaemons[name]; ok {
-		return producerDaemons[name], nil
+	key := NamespacedName(config, name)
+
+	if _, ok := producerDaemons[key]; ok {
+		return producerDaemons[key], nil
 	}
 
 	var err error
-	producerDaemons[name], err = NewProducerDaemon(config, logger, name)
+	producerDaemons[key], err = NewProducerDaemon(config, logger, name)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return producerDaemons[name], nil
+	return producerDaemons[key], nil
 }
 
 func NewProducerDaemon(config cfg.Config, logger mon.Logger, name string) (*ProducerDaemon, error) {
@@ -96,7 +103,7 @@ func NewProducerDaemon(config cfg.Config, logger mon.Logger, name string) (*Prod
 		batch:         make([]WritableMessage, 0, settings.Daemon.BatchSize),
 		outCh:         NewOutputChannel(logger, settings.Daemon.BufferSize),
 		output:        output,
-		tickerFactory: clock.NewRealTicker,
+		tickerFactory: clock.NewTicker,
 		marshaller:    MarshalJsonMessage,
 		settings:      settings.Daemon,
 	}, nil
@@ -0,0 +1,60 @@
+package chaos
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/http"
+)
+
+type HttpClient struct {
+	http.Client
+	injector *Injector
+}
+
+// NewHttpClient wraps the client, so the requests are delayed or fail like described by the faults of the injector.
+// Streams and the settings of the client are passed through untouched.
+func NewHttpClient(client http.Client, injector *Injector) *HttpClient {
+	return &HttpClient{
+		Client:   client,
+		injector: injector,
+	}
+}
+
+func (c *HttpClient) Delete(ctx context.Context, request *http.Request) (*http.Response, error) {
+	if err := c.injector.call(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.Client.Delete(ctx, request)
+}
+
+func (c *HttpClient) Get(ctx context.Context, request *http.Request) (*http.Response, error) {
+	if err := c.injector.call(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.Client.Get(ctx, request)
+}
+
+func (c *HttpClient) Patch(ctx context.Context, request *http.Request) (*http.Response, error) {
+	if err := c.injector.call(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.Client.Patch(ctx, request)
+}
+
+func (c *HttpClient) Post(ctx context.Context, request *http.Request) (*http.Response, error) {
+	if err := c.injector.call(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.Client.Post(ctx, request)
+}
+
+func (c *HttpClient) Put(ctx context.Context, request *http.Request) (*http.Response, error) {
+	if err := c.injector.call(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.Client.Put(ctx, request)
+}
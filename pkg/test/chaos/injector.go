@@ -0,0 +1,154 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrInjected is returned by the wrapped outputs and clients if Faults.Err is not set
+var ErrInjected = errors.New("injected fault")
+
+// Faults describes which faults are injected into the calls of a wrapped output or client. The percentages are
+// between 0 and 100. Every call is delayed by Latency with the probability of LatencyPercentage and fails with the
+// probability of ErrorPercentage. The messages of a batch fail one by one with the probability of
+// PartialFailurePercentage. A Seed other than 0 makes the injected faults reproducible.
+type Faults struct {
+	Latency                  time.Duration
+	LatencyPercentage        float64
+	ErrorPercentage          float64
+	Err                      error
+	PartialFailurePercentage float64
+	Seed                     int64
+}
+
+// Stats counts the calls of a wrapped output or client and the faults injected into them
+type Stats struct {
+	Calls          int
+	Delayed        int
+	Failed         int
+	PartialFailed  int
+	FailedMessages int
+}
+
+// Injector decides which faults are injected into a call. It is shared by all wrappers which should draw from the
+// same faults and stats.
+type Injector struct {
+	lck    sync.Mutex
+	faults Faults
+	rand   *rand.Rand
+	stats  Stats
+}
+
+func NewInjector(faults Faults) *Injector {
+	seed := faults.Seed
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	if faults.Err == nil {
+		faults.Err = ErrInjected
+	}
+
+	return &Injector{
+		faults: faults,
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// SetFaults replaces the faults, e.g. to let a dependency recover in the middle of a test
+func (i *Injector) SetFaults(faults Faults) {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	if faults.Err == nil {
+		faults.Err = ErrInjected
+	}
+
+	i.faults = faults
+}
+
+func (i *Injector) Stats() Stats {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	return i.stats
+}
+
+// call injects the latency and the error of a single call. The latency is waited for in real time, as the clock
+// provider is usually a fake clock in tests.
+func (i *Injector) call(ctx context.Context) error {
+	i.lck.Lock()
+	i.stats.Calls++
+
+	delay := time.Duration(0)
+
+	if i.draw(i.faults.LatencyPercentage) {
+		delay = i.faults.Latency
+		i.stats.Delayed++
+	}
+
+	var err error
+
+	if i.draw(i.faults.ErrorPercentage) {
+		err = i.faults.Err
+		i.stats.Failed++
+	}
+
+	i.lck.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
+
+// partial returns for every of the n elements of a batch if it fails
+func (i *Injector) partial(n int) []bool {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	failed := make([]bool, n)
+	failedCount := 0
+
+	for j := range failed {
+		failed[j] = i.draw(i.faults.PartialFailurePercentage)
+
+		if failed[j] {
+			failedCount++
+		}
+	}
+
+	if failedCount > 0 {
+		i.stats.PartialFailed++
+		i.stats.FailedMessages += failedCount
+	}
+
+	return failed
+}
+
+func (i *Injector) err() error {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	return i.faults.Err
+}
+
+func (i *Injector) draw(percentage float64) bool {
+	if percentage <= 0 {
+		return false
+	}
+
+	return i.rand.Float64()*100 < percentage
+}
@@ -0,0 +1,71 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/stream"
+)
+
+// PartialFailureError is returned if only some of the messages of a batch were written
+type PartialFailureError struct {
+	Failed int
+	Total  int
+	Err    error
+}
+
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("%d of %d messages could not be written: %s", e.Failed, e.Total, e.Err.Error())
+}
+
+func (e *PartialFailureError) Unwrap() error {
+	return e.Err
+}
+
+type Output struct {
+	stream.Output
+	injector *Injector
+}
+
+// NewOutput wraps the output, so the writes are delayed or fail like described by the faults of the injector. Failing
+// messages of a batch are not written, the others are and the error is a PartialFailureError.
+func NewOutput(output stream.Output, injector *Injector) *Output {
+	return &Output{
+		Output:   output,
+		injector: injector,
+	}
+}
+
+func (o *Output) WriteOne(ctx context.Context, msg stream.WritableMessage) error {
+	return o.Write(ctx, []stream.WritableMessage{msg})
+}
+
+func (o *Output) Write(ctx context.Context, batch []stream.WritableMessage) error {
+	if err := o.injector.call(ctx); err != nil {
+		return err
+	}
+
+	failed := o.injector.partial(len(batch))
+	written := make([]stream.WritableMessage, 0, len(batch))
+
+	for i, msg := range batch {
+		if !failed[i] {
+			written = append(written, msg)
+		}
+	}
+
+	if len(written) > 0 {
+		if err := o.Output.Write(ctx, written); err != nil {
+			return err
+		}
+	}
+
+	if len(written) == len(batch) {
+		return nil
+	}
+
+	return &PartialFailureError{
+		Failed: len(batch) - len(written),
+		Total:  len(batch),
+		Err:    o.injector.err(),
+	}
+}
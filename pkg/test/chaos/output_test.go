@@ -0,0 +1,78 @@
+package chaos_test
+
+import (
+	"context"
+	"errors"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/applike/gosoline/pkg/test/chaos"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOutput_Error(t *testing.T) {
+	inner := stream.NewInMemoryOutput()
+	injector := chaos.NewInjector(chaos.Faults{
+		ErrorPercentage: 100,
+	})
+	output := chaos.NewOutput(inner, injector)
+
+	err := output.WriteOne(context.Background(), stream.NewMessage("body"))
+
+	assert.True(t, errors.Is(err, chaos.ErrInjected))
+	assert.Equal(t, 0, inner.Len(), "no message should be written")
+	assert.Equal(t, chaos.Stats{Calls: 1, Failed: 1}, injector.Stats())
+}
+
+func TestOutput_PartialFailure(t *testing.T) {
+	inner := stream.NewInMemoryOutput()
+	injector := chaos.NewInjector(chaos.Faults{
+		PartialFailurePercentage: 50,
+		Seed:                     1,
+	})
+	output := chaos.NewOutput(inner, injector)
+
+	batch := make([]stream.WritableMessage, 100)
+	for i := range batch {
+		batch[i] = stream.NewMessage("body")
+	}
+
+	err := output.Write(context.Background(), batch)
+
+	partialErr := &chaos.PartialFailureError{}
+	if assert.True(t, errors.As(err, &partialErr)) {
+		assert.Equal(t, 100, partialErr.Total)
+		assert.Equal(t, 100-partialErr.Failed, inner.Len(), "the messages which didn't fail should be written")
+		assert.True(t, partialErr.Failed > 0 && partialErr.Failed < 100, "only some messages should fail")
+	}
+
+	stats := injector.Stats()
+	assert.Equal(t, 1, stats.PartialFailed)
+	assert.Equal(t, partialErr.Failed, stats.FailedMessages)
+}
+
+func TestOutput_Latency(t *testing.T) {
+	inner := stream.NewInMemoryOutput()
+	output := chaos.NewOutput(inner, chaos.NewInjector(chaos.Faults{
+		Latency:           time.Hour,
+		LatencyPercentage: 100,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := output.WriteOne(ctx, stream.NewMessage("body"))
+
+	assert.Equal(t, context.DeadlineExceeded, err, "the latency should be cut short by the context")
+	assert.Equal(t, 0, inner.Len())
+}
+
+func TestOutput_NoFaults(t *testing.T) {
+	inner := stream.NewInMemoryOutput()
+	output := chaos.NewOutput(inner, chaos.NewInjector(chaos.Faults{}))
+
+	err := output.WriteOne(context.Background(), stream.NewMessage("body"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.Len())
+}
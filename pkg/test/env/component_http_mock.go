@@ -0,0 +1,238 @@
+package env
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	netHttp "net/http"
+	"net/http/httptest"
+	netUrl "net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HttpMockFaultConnectionReset closes the connection without writing a response
+const HttpMockFaultConnectionReset = "connection_reset"
+
+// HttpMockRoute answers the requests matching the method, the path and all of the query parameters and headers. A
+// path ending with * matches all paths with the prefix. If Times is set, the route answers only that many requests.
+type HttpMockRoute struct {
+	Method   string            `cfg:"method"`
+	Path     string            `cfg:"path"`
+	Query    map[string]string `cfg:"query"`
+	Headers  map[string]string `cfg:"headers"`
+	Times    int               `cfg:"times"`
+	Response HttpMockResponse  `cfg:"response"`
+}
+
+// HttpMockResponse is written after the Latency passed. A status code of 0 is answered with 200.
+type HttpMockResponse struct {
+	StatusCode int               `cfg:"status_code"`
+	Headers    map[string]string `cfg:"headers"`
+	Body       string            `cfg:"body"`
+	Latency    time.Duration     `cfg:"latency"`
+	Fault      string            `cfg:"fault"`
+}
+
+type HttpMockCall struct {
+	Method  string
+	Path    string
+	Query   netUrl.Values
+	Headers netHttp.Header
+	Body    string
+	Matched bool
+}
+
+type httpMockRoute struct {
+	HttpMockRoute
+	calls int
+}
+
+type httpMockComponent struct {
+	baseComponent
+	lck      sync.Mutex
+	server   *httptest.Server
+	basePath string
+	initial  []HttpMockRoute
+	routes   []*httpMockRoute
+	calls    []HttpMockCall
+}
+
+func (c *httpMockComponent) CfgOptions() []cfg.Option {
+	key := fmt.Sprintf("http_clients.%s.base_url", c.name)
+
+	return []cfg.Option{
+		cfg.WithConfigSetting(key, c.Address()+c.basePath),
+	}
+}
+
+func (c *httpMockComponent) Address() string {
+	return c.server.URL
+}
+
+func (c *httpMockComponent) Close() error {
+	c.server.Close()
+
+	return nil
+}
+
+// Route adds routes to the mock. If more than one route matches a request, the last added one answers it.
+func (c *httpMockComponent) Route(routes ...HttpMockRoute) {
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	for _, route := range routes {
+		c.routes = append(c.routes, &httpMockRoute{
+			HttpMockRoute: route,
+		})
+	}
+}
+
+// Reset removes the calls and the routes added by Route, the routes of the config are kept
+func (c *httpMockComponent) Reset() {
+	c.lck.Lock()
+	c.routes = make([]*httpMockRoute, 0, len(c.initial))
+	c.calls = make([]HttpMockCall, 0)
+	c.lck.Unlock()
+
+	c.Route(c.initial...)
+}
+
+// Calls returns all requests received by the mock, including the ones without a matching route
+func (c *httpMockComponent) Calls() []HttpMockCall {
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	calls := make([]HttpMockCall, len(c.calls))
+	copy(calls, c.calls)
+
+	return calls
+}
+
+func (c *httpMockComponent) CallCount(method string, path string) int {
+	count := 0
+
+	for _, call := range c.Calls() {
+		if call.Method == method && matchHttpMockPath(path, call.Path) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (c *httpMockComponent) AssertCalled(method string, path string, times int) {
+	assert.Equal(c.t, times, c.CallCount(method, path), "the http mock %s should have received %d %s requests to %s", c.name, times, method, path)
+}
+
+func (c *httpMockComponent) AssertNoUnmatchedCalls() {
+	for _, call := range c.Calls() {
+		assert.True(c.t, call.Matched, "the http mock %s received a %s request to %s without a matching route", c.name, call.Method, call.Path)
+	}
+}
+
+func (c *httpMockComponent) handle(w netHttp.ResponseWriter, r *netHttp.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	route := c.match(r, string(body))
+
+	if route == nil {
+		netHttp.Error(w, fmt.Sprintf("the http mock %s has no route for %s %s", c.name, r.Method, r.URL.Path), netHttp.StatusNotFound)
+		return
+	}
+
+	response := route.Response
+
+	if response.Latency > 0 {
+		time.Sleep(response.Latency)
+	}
+
+	if response.Fault == HttpMockFaultConnectionReset {
+		if hijacker, ok := w.(netHttp.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+
+	for key, value := range response.Headers {
+		w.Header().Set(key, value)
+	}
+
+	statusCode := response.StatusCode
+
+	if statusCode == 0 {
+		statusCode = netHttp.StatusOK
+	}
+
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(response.Body))
+}
+
+func (c *httpMockComponent) match(r *netHttp.Request, body string) *httpMockRoute {
+	c.lck.Lock()
+	defer c.lck.Unlock()
+
+	call := HttpMockCall{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   r.URL.Query(),
+		Headers: r.Header,
+		Body:    body,
+	}
+
+	var matched *httpMockRoute
+
+	for i := len(c.routes) - 1; i >= 0; i-- {
+		if c.routes[i].matches(r) {
+			matched = c.routes[i]
+			matched.calls++
+			call.Matched = true
+			break
+		}
+	}
+
+	c.calls = append(c.calls, call)
+
+	return matched
+}
+
+func (r *httpMockRoute) matches(request *netHttp.Request) bool {
+	if r.Times > 0 && r.calls >= r.Times {
+		return false
+	}
+
+	if r.Method != "" && !strings.EqualFold(r.Method, request.Method) {
+		return false
+	}
+
+	if !matchHttpMockPath(r.Path, request.URL.Path) {
+		return false
+	}
+
+	query := request.URL.Query()
+
+	for key, value := range r.Query {
+		if query.Get(key) != value {
+			return false
+		}
+	}
+
+	for key, value := range r.Headers {
+		if request.Header.Get(key) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchHttpMockPath(pattern string, path string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(path, strings.TrimSuffix(pattern, "*"))
+	}
+
+	return pattern == "" || pattern == path
+}
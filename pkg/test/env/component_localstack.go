@@ -3,9 +3,14 @@ package env
 import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/thoas/go-funk"
 )
 
@@ -30,6 +35,20 @@ func (c *localstackComponent) CfgOptions() []cfg.Option {
 		}))
 	}
 
+	if funk.ContainsString(c.services, localstackServiceDynamoDb) {
+		options = append(options, cfg.WithConfigMap(map[string]interface{}{
+			"aws_dynamoDb_endpoint":   c.Address(),
+			"aws_dynamoDb_autoCreate": true,
+		}), cfg.WithConfigSetting("cloud.aws.dynamodb.clients.default.endpoint", c.Address()))
+	}
+
+	if funk.ContainsString(c.services, localstackServiceKinesis) {
+		options = append(options, cfg.WithConfigMap(map[string]interface{}{
+			"aws_kinesis_endpoint":   c.Address(),
+			"aws_kinesis_autoCreate": true,
+		}))
+	}
+
 	if funk.ContainsString(c.services, localstackServiceSns) {
 		options = append(options, cfg.WithConfigMap(map[string]interface{}{
 			"aws_sns_endpoint":      c.Address(),
@@ -58,12 +77,74 @@ func (c *localstackComponent) Address() string {
 	return fmt.Sprintf("http://%s:%s", c.binding.host, c.binding.port)
 }
 
+func (c *localstackComponent) DynamoDbClient() *dynamodb.DynamoDB {
+	return dynamodb.New(c.session())
+}
+
+func (c *localstackComponent) KinesisClient() *kinesis.Kinesis {
+	return kinesis.New(c.session())
+}
+
+func (c *localstackComponent) S3Client() *s3.S3 {
+	return s3.New(c.session(), aws.NewConfig().WithS3ForcePathStyle(true))
+}
+
 func (c *localstackComponent) SnsClient() *sns.SNS {
-	sess := session.Must(session.NewSession(&aws.Config{
-		Region:     aws.String(c.region),
-		Endpoint:   aws.String(c.Address()),
-		MaxRetries: aws.Int(0),
+	return sns.New(c.session())
+}
+
+func (c *localstackComponent) SqsClient() *sqs.SQS {
+	return sqs.New(c.session())
+}
+
+func (c *localstackComponent) session() *session.Session {
+	return session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String(c.region),
+		Endpoint:    aws.String(c.Address()),
+		MaxRetries:  aws.Int(0),
+		Credentials: gosoAws.GetDefaultCredentials(),
 	}))
+}
+
+// provision creates the resources declared in the settings of the component. Existing resources are kept, so a
+// reused container can be provisioned again.
+func (c *localstackComponent) provision(resources localstackResourcesSettings) error {
+	for _, name := range resources.SnsTopics {
+		if _, err := c.SnsClient().CreateTopic(&sns.CreateTopicInput{Name: aws.String(name)}); err != nil {
+			return fmt.Errorf("can not create sns topic %s: %w", name, err)
+		}
+	}
+
+	for _, name := range resources.SqsQueues {
+		if _, err := c.SqsClient().CreateQueue(&sqs.CreateQueueInput{QueueName: aws.String(name)}); err != nil {
+			return fmt.Errorf("can not create sqs queue %s: %w", name, err)
+		}
+	}
+
+	for _, name := range resources.S3Buckets {
+		_, err := c.S3Client().CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(name)})
+
+		if err != nil && !gosoAws.IsAwsError(err, s3.ErrCodeBucketAlreadyOwnedByYou) {
+			return fmt.Errorf("can not create s3 bucket %s: %w", name, err)
+		}
+	}
+
+	client := c.KinesisClient()
+
+	for _, name := range resources.KinesisStreams {
+		_, err := client.CreateStream(&kinesis.CreateStreamInput{
+			StreamName: aws.String(name),
+			ShardCount: aws.Int64(1),
+		})
+
+		if err != nil && !gosoAws.IsAwsError(err, kinesis.ErrCodeResourceInUseException) {
+			return fmt.Errorf("can not create kinesis stream %s: %w", name, err)
+		}
+
+		if err = client.WaitUntilStreamExists(&kinesis.DescribeStreamInput{StreamName: aws.String(name)}); err != nil {
+			return fmt.Errorf("kinesis stream %s did not become active: %w", name, err)
+		}
+	}
 
-	return sns.New(sess)
+	return nil
 }
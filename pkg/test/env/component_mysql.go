@@ -1,15 +1,26 @@
 package env
 
 import (
+	"context"
+	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/db"
+	db_repo "github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/fixtures"
+	"github.com/applike/gosoline/pkg/migrations"
+	"github.com/applike/gosoline/pkg/mon"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
+	"strings"
 )
 
 type mysqlComponent struct {
 	baseComponent
+	config      cfg.Config
+	logger      mon.Logger
 	client      *sqlx.DB
+	root        *sqlx.DB
 	credentials mysqlCredentials
 	binding     containerBinding
 }
@@ -60,3 +71,166 @@ func (c *mysqlComponent) AssertRowCount(table string, expectedCount int) {
 
 	assert.Equal(c.t, expectedCount, actualCount, "row count doesn't match for table %s", table)
 }
+
+// Migrate applies the migrations of the sources which are not applied yet
+func (c *mysqlComponent) Migrate(sources ...migrations.Source) error {
+	settings := &migrations.Settings{}
+	c.config.UnmarshalDefaults(settings)
+	settings.Client = c.name
+
+	migrator, err := migrations.NewMigratorForConnection(c.logger, c.name, c.client, db.DriverMysql, settings, sources...)
+
+	if err != nil {
+		return fmt.Errorf("can not create migrator for mysql %s: %w", c.name, err)
+	}
+
+	if err = migrator.Up(context.Background()); err != nil {
+		return fmt.Errorf("can not migrate mysql %s: %w", c.name, err)
+	}
+
+	return nil
+}
+
+// LoadFixtures writes the fixture sets with the config of the environment, the loader has to be enabled at
+// fixtures.enabled and the tests built with the fixtures tag like for an application
+func (c *mysqlComponent) LoadFixtures(fixtureSets []*fixtures.FixtureSet) error {
+	if err := fixtures.NewFixtureLoader(c.config, c.logger).Load(fixtureSets); err != nil {
+		return fmt.Errorf("can not load fixtures into mysql %s: %w", c.name, err)
+	}
+
+	return nil
+}
+
+// Repository returns a repository reading and writing the database of the component
+func (c *mysqlComponent) Repository(settings db_repo.Settings) db_repo.Repository {
+	if settings.Connection == "" {
+		settings.Connection = c.name
+	}
+
+	repo, err := db_repo.New(c.config, c.logger, settings)
+
+	if err != nil {
+		assert.FailNow(c.t, err.Error(), "can not create repository for mysql %s", c.name)
+	}
+
+	return repo
+}
+
+// Snapshot copies all tables of the database into a snapshot database, so Restore can reset the database to the
+// current state, e.g. after the migrations and fixtures were applied once for all tests
+func (c *mysqlComponent) Snapshot() error {
+	tables, err := c.tables(c.credentials.DatabaseName)
+
+	if err != nil {
+		return err
+	}
+
+	source := quoteMysqlName(c.credentials.DatabaseName)
+	snapshot := quoteMysqlName(c.snapshotDatabase())
+
+	statements := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", snapshot),
+		fmt.Sprintf("CREATE DATABASE %s", snapshot),
+	}
+
+	for _, table := range tables {
+		table = quoteMysqlName(table)
+
+		statements = append(statements,
+			fmt.Sprintf("CREATE TABLE %s.%s LIKE %s.%s", snapshot, table, source, table),
+			fmt.Sprintf("INSERT INTO %s.%s SELECT * FROM %s.%s", snapshot, table, source, table),
+		)
+	}
+
+	if err = c.execRoot(statements); err != nil {
+		return fmt.Errorf("can not snapshot mysql %s: %w", c.name, err)
+	}
+
+	return nil
+}
+
+// Restore resets the database to the state of the last Snapshot. The rows of all tables are replaced by the ones of
+// the snapshot and the tables created after the snapshot are dropped.
+func (c *mysqlComponent) Restore() error {
+	current, err := c.tables(c.credentials.DatabaseName)
+
+	if err != nil {
+		return err
+	}
+
+	snapshotTables, err := c.tables(c.snapshotDatabase())
+
+	if err != nil {
+		return err
+	}
+
+	if len(snapshotTables) == 0 {
+		return fmt.Errorf("there is no snapshot of mysql %s to restore", c.name)
+	}
+
+	target := quoteMysqlName(c.credentials.DatabaseName)
+	snapshot := quoteMysqlName(c.snapshotDatabase())
+
+	inSnapshot := make(map[string]bool, len(snapshotTables))
+	for _, table := range snapshotTables {
+		inSnapshot[table] = true
+	}
+
+	existing := make(map[string]bool, len(current))
+	statements := []string{"SET FOREIGN_KEY_CHECKS = 0"}
+
+	for _, table := range current {
+		existing[table] = true
+
+		if !inSnapshot[table] {
+			statements = append(statements, fmt.Sprintf("DROP TABLE %s.%s", target, quoteMysqlName(table)))
+			continue
+		}
+
+		statements = append(statements, fmt.Sprintf("TRUNCATE TABLE %s.%s", target, quoteMysqlName(table)))
+	}
+
+	for _, table := range snapshotTables {
+		table, quoted := table, quoteMysqlName(table)
+
+		if !existing[table] {
+			statements = append(statements, fmt.Sprintf("CREATE TABLE %s.%s LIKE %s.%s", target, quoted, snapshot, quoted))
+		}
+
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s.%s SELECT * FROM %s.%s", target, quoted, snapshot, quoted))
+	}
+
+	statements = append(statements, "SET FOREIGN_KEY_CHECKS = 1")
+
+	if err = c.execRoot(statements); err != nil {
+		return fmt.Errorf("can not restore mysql %s: %w", c.name, err)
+	}
+
+	return nil
+}
+
+func (c *mysqlComponent) snapshotDatabase() string {
+	return fmt.Sprintf("%s_snapshot", c.credentials.DatabaseName)
+}
+
+func (c *mysqlComponent) tables(database string) ([]string, error) {
+	tables := make([]string, 0)
+	qry := "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'"
+
+	if err := c.root.Select(&tables, qry, database); err != nil {
+		return nil, fmt.Errorf("can not read the tables of database %s: %w", database, err)
+	}
+
+	return tables, nil
+}
+
+// execRoot executes the statements at once, so session variables like the foreign key checks apply to all of them
+func (c *mysqlComponent) execRoot(statements []string) error {
+	_, err := c.root.Exec(strings.Join(statements, ";\n"))
+
+	return err
+}
+
+func quoteMysqlName(name string) string {
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+}
@@ -63,6 +63,11 @@ func (s *streamInputComponent) PublishFromJsonFile(fileName string) {
 	s.input.Stop()
 }
 
+// Acknowledged returns the messages acknowledged by the consumer of the input
+func (s *streamInputComponent) Acknowledged() []*stream.Message {
+	return s.input.Acknowledged()
+}
+
 func (s *streamInputComponent) Stop() {
 	s.input.Stop()
 }
@@ -78,6 +78,12 @@ type ComponentCfgOptionAware interface {
 	CfgOptions() []cfg.Option
 }
 
+// ComponentCloseAware components are closed when the environment stops, e.g. to shut down servers running in the
+// test process
+type ComponentCloseAware interface {
+	Close() error
+}
+
 type baseComponent struct {
 	t    *testing.T
 	name string
@@ -1,6 +1,7 @@
 package env
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/coffin"
@@ -9,6 +10,7 @@ import (
 	"github.com/cenkalti/backoff"
 	"github.com/ory/dockertest"
 	"github.com/ory/dockertest/docker"
+	"github.com/thoas/go-funk"
 	"net"
 	"strings"
 	"sync"
@@ -71,6 +73,9 @@ type containerRunnerSettings struct {
 	HealthCheck healthCheckSettings `cfg:"health_check"`
 	ExpireAfter time.Duration       `cfg:"expire_after"`
 	Auth        authSettings        `cfg:"auth"`
+	// Reuse keeps the containers running after the tests and uses them again in the next tests with the same
+	// container config, e.g. in other test packages. They are removed after they expired.
+	Reuse bool `cfg:"reuse" default:"false"`
 }
 
 type containerRunner struct {
@@ -141,10 +146,109 @@ func (r *containerRunner) RunContainers(skeletons []*componentSkeleton) error {
 }
 
 func (r *containerRunner) RunContainer(skeleton *componentSkeleton, name string, description *componentContainerDescription) (*container, error) {
-	containerName := fmt.Sprintf("%s-%s-%s-%s", r.settings.NamePrefix, r.id, skeleton.id(), name)
-	r.logger.Debugf("run container %s %s", skeleton.typ, containerName)
+	var err error
+	var resource *dockertest.Resource
 
 	config := description.containerConfig
+	containerName := r.containerName(skeleton, name, config)
+
+	if resource, err = r.runOrReuseContainer(skeleton, containerName, config); err != nil {
+		return nil, err
+	}
+
+	resolvedBindings, err := r.resolveBindings(resource, config.PortBindings)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not resolve bindings: %w", err)
+	}
+
+	container := &container{
+		typ:      skeleton.typ,
+		name:     containerName,
+		bindings: resolvedBindings,
+	}
+
+	if err = r.waitUntilHealthy(container, description.healthCheck); err != nil {
+		return nil, fmt.Errorf("healthcheck failed on container for component %s: %w", skeleton.id(), err)
+	}
+
+	return container, err
+}
+
+// containerName is unique for every run of the tests. Reused containers are named by a hash of their config instead,
+// so only the tests with the same container config share them.
+func (r *containerRunner) containerName(skeleton *componentSkeleton, name string, config *containerConfig) string {
+	if !r.settings.Reuse {
+		return fmt.Sprintf("%s-%s-%s-%s", r.settings.NamePrefix, r.id, skeleton.id(), name)
+	}
+
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s:%s %v %v %v %v %v", config.Repository, config.Tag, config.Env, config.Cmd, config.PortBindings, config.ExposedPorts, config.Tmpfs)
+
+	return fmt.Sprintf("%s-%x-%s-%s", r.settings.NamePrefix, hash.Sum(nil)[:6], skeleton.id(), name)
+}
+
+func (r *containerRunner) runOrReuseContainer(skeleton *componentSkeleton, containerName string, config *containerConfig) (*dockertest.Resource, error) {
+	if !r.settings.Reuse {
+		return r.runNewContainer(skeleton, containerName, config)
+	}
+
+	if resource, ok, err := r.findRunningContainer(containerName); err != nil || ok {
+		return resource, err
+	}
+
+	resource, err := r.runNewContainer(skeleton, containerName, config)
+
+	if err == nil {
+		return resource, nil
+	}
+
+	// the tests of another package might have started the same container in the meantime
+	if resource, ok, findErr := r.findRunningContainer(containerName); findErr == nil && ok {
+		return resource, nil
+	}
+
+	return nil, err
+}
+
+func (r *containerRunner) findRunningContainer(containerName string) (*dockertest.Resource, bool, error) {
+	containers, err := r.pool.Client.ListContainers(docker.ListContainersOptions{
+		Filters: map[string][]string{
+			"name": {containerName},
+		},
+	})
+
+	if err != nil {
+		return nil, false, fmt.Errorf("can not list containers with name %s: %w", containerName, err)
+	}
+
+	for _, listed := range containers {
+		// the name filter matches parts of the names as well
+		if !funk.ContainsString(listed.Names, "/"+containerName) {
+			continue
+		}
+
+		inspected, err := r.pool.Client.InspectContainer(listed.ID)
+
+		if err != nil {
+			return nil, false, fmt.Errorf("can not inspect container %s: %w", containerName, err)
+		}
+
+		if !inspected.State.Running {
+			continue
+		}
+
+		r.logger.Debugf("reusing container %s", containerName)
+
+		return &dockertest.Resource{Container: inspected}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (r *containerRunner) runNewContainer(skeleton *componentSkeleton, containerName string, config *containerConfig) (*dockertest.Resource, error) {
+	r.logger.Debugf("run container %s %s", skeleton.typ, containerName)
+
 	bindings := make(map[docker.Port][]docker.PortBinding)
 
 	for containerPort, hostPort := range config.PortBindings {
@@ -177,32 +281,18 @@ func (r *containerRunner) RunContainer(skeleton *componentSkeleton, name string,
 		return nil, fmt.Errorf("can not run container %s: %w", skeleton.id(), err)
 	}
 
-	resourceId := fmt.Sprintf("%s-%s", skeleton.id(), name)
-	r.resourcesLck.Lock()
-	r.resources[resourceId] = resource
-	r.resourcesLck.Unlock()
+	// reused containers are kept running for the next tests
+	if !r.settings.Reuse {
+		r.resourcesLck.Lock()
+		r.resources[containerName] = resource
+		r.resourcesLck.Unlock()
+	}
 
 	if err = r.expireAfter(resource, config.ExpireAfter); err != nil {
 		return nil, fmt.Errorf("could not set expiry on container %s: %w", containerName, err)
 	}
 
-	resolvedBindings, err := r.resolveBindings(resource, config.PortBindings)
-
-	if err != nil {
-		return nil, fmt.Errorf("can not resolve bindings: %w", err)
-	}
-
-	container := &container{
-		typ:      skeleton.typ,
-		name:     containerName,
-		bindings: resolvedBindings,
-	}
-
-	if err = r.waitUntilHealthy(container, description.healthCheck); err != nil {
-		return nil, fmt.Errorf("healthcheck failed on container for component %s: %w", skeleton.id(), err)
-	}
-
-	return container, err
+	return resource, nil
 }
 
 func (r *containerRunner) getTmpfsConfig(settings []TmpfsSettings) map[string]string {
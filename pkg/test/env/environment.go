@@ -114,6 +114,20 @@ func (e *Environment) addLoggerOption(opt LoggerOption) {
 }
 
 func (e *Environment) Stop() error {
+	if e.components != nil {
+		for _, component := range e.components.GetAll() {
+			closer, ok := component.(ComponentCloseAware)
+
+			if !ok {
+				continue
+			}
+
+			if err := closer.Close(); err != nil {
+				return fmt.Errorf("can not close component: %w", err)
+			}
+		}
+	}
+
 	return e.runner.Stop()
 }
 
@@ -148,6 +162,10 @@ func (e *Environment) DynamoDb(name string) *DdbComponent {
 	return e.Component(componentDdb, name).(*DdbComponent)
 }
 
+func (e *Environment) HttpMock(name string) *httpMockComponent {
+	return e.Component(componentHttpMock, name).(*httpMockComponent)
+}
+
 func (e *Environment) Localstack(name string) *localstackComponent {
 	return e.Component(ComponentLocalstack, name).(*localstackComponent)
 }
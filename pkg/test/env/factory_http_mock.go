@@ -0,0 +1,89 @@
+package env
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	netHttp "net/http"
+	"net/http/httptest"
+	netUrl "net/url"
+)
+
+func init() {
+	componentFactories[componentHttpMock] = new(httpMockFactory)
+}
+
+const componentHttpMock = "httpMock"
+
+type httpMockSettings struct {
+	ComponentBaseSettings
+	Routes []HttpMockRoute `cfg:"routes"`
+}
+
+type httpMockFactory struct {
+}
+
+// Detect adds a mock server for every named http client, so the app under test doesn't call the real services
+func (f *httpMockFactory) Detect(config cfg.Config, manager *ComponentsConfigManager) error {
+	if !manager.ShouldAutoDetect(componentHttpMock) {
+		return nil
+	}
+
+	clients := config.GetStringMap("http_clients", map[string]interface{}{})
+
+	for clientName := range clients {
+		if clientName == "default" || manager.Has(componentHttpMock, clientName) {
+			continue
+		}
+
+		settings := &httpMockSettings{}
+		config.UnmarshalDefaults(settings)
+
+		settings.Name = clientName
+		settings.Type = componentHttpMock
+
+		if err := manager.Add(settings); err != nil {
+			return fmt.Errorf("could not add http mock %s: %w", clientName, err)
+		}
+	}
+
+	return nil
+}
+
+func (f *httpMockFactory) GetSettingsSchema() ComponentBaseSettingsAware {
+	return &httpMockSettings{}
+}
+
+func (f *httpMockFactory) DescribeContainers(settings interface{}) componentContainerDescriptions {
+	return nil
+}
+
+func (f *httpMockFactory) Component(config cfg.Config, _ mon.Logger, _ map[string]*container, settings interface{}) (Component, error) {
+	s := settings.(*httpMockSettings)
+
+	// the mock keeps the path of the base url, so the routes are declared with the paths the requests of the client have
+	basePath := ""
+	baseUrlKey := fmt.Sprintf("http_clients.%s.base_url", s.Name)
+
+	if config.IsSet(baseUrlKey) {
+		baseUrl, err := netUrl.Parse(config.GetString(baseUrlKey))
+
+		if err != nil {
+			return nil, fmt.Errorf("can not parse base url of http client %s: %w", s.Name, err)
+		}
+
+		basePath = baseUrl.Path
+	}
+
+	component := &httpMockComponent{
+		baseComponent: baseComponent{
+			name: s.Name,
+		},
+		basePath: basePath,
+		initial:  s.Routes,
+	}
+	component.Reset()
+	component.server = httptest.NewServer(netHttp.HandlerFunc(component.handle))
+
+	return component, nil
+}
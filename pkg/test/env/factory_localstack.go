@@ -17,6 +17,8 @@ func init() {
 const (
 	ComponentLocalstack         = "localstack"
 	localstackServiceCloudWatch = "cloudwatch"
+	localstackServiceDynamoDb   = "dynamodb"
+	localstackServiceKinesis    = "kinesis"
 	localstackServiceS3         = "s3"
 	localstackServiceSns        = "sns"
 	localstackServiceSqs        = "sqs"
@@ -25,9 +27,19 @@ const (
 type localstackSettings struct {
 	ComponentBaseSettings
 	ComponentContainerSettings
-	Port     int      `cfg:"port" default:"0"`
-	Region   string   `cfg:"region" default:"eu-central-1"`
-	Services []string `cfg:"services"`
+	Port      int                         `cfg:"port" default:"0"`
+	Region    string                      `cfg:"region" default:"eu-central-1"`
+	Services  []string                    `cfg:"services"`
+	Resources localstackResourcesSettings `cfg:"resources"`
+}
+
+// localstackResourcesSettings are the resources created after the container is healthy, e.g. for applications which
+// don't create their resources themselves
+type localstackResourcesSettings struct {
+	KinesisStreams []string `cfg:"kinesis_streams"`
+	S3Buckets      []string `cfg:"s3_buckets"`
+	SnsTopics      []string `cfg:"sns_topics"`
+	SqsQueues      []string `cfg:"sqs_queues"`
 }
 
 type localstackFactory struct {
@@ -48,6 +60,10 @@ func (f *localstackFactory) Detect(config cfg.Config, manager *ComponentsConfigM
 		services = append(services, localstackServiceCloudWatch)
 	}
 
+	if config.IsSet("aws_kinesis_endpoint") {
+		services = append(services, localstackServiceKinesis)
+	}
+
 	if config.IsSet("aws_s3_endpoint") {
 		services = append(services, localstackServiceS3)
 	}
@@ -160,5 +176,9 @@ func (f *localstackFactory) Component(config cfg.Config, logger mon.Logger, cont
 		region:   s.Region,
 	}
 
+	if err := component.provision(s.Resources); err != nil {
+		return nil, fmt.Errorf("can not provision the localstack resources: %w", err)
+	}
+
 	return component, nil
 }
@@ -3,9 +3,11 @@ package env
 import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/migrations"
 	"github.com/applike/gosoline/pkg/mon"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	netHttp "net/http"
 	"net/url"
 )
 
@@ -28,6 +30,8 @@ type mysqlSettings struct {
 	Port        int              `cfg:"port" default:"0"`
 	Version     string           `cfg:"version" default:"8.0"`
 	Credentials mysqlCredentials `cfg:"credentials"`
+	// directories with sql migrations which are applied once the container is up
+	Migrations []string `cfg:"migrations"`
 }
 
 type mysqlFactory struct {
@@ -86,6 +90,8 @@ func (f mysqlFactory) configureContainer(settings interface{}) *containerConfig
 		fmt.Sprintf("MYSQL_USER=%s", s.Credentials.UserName),
 		fmt.Sprintf("MYSQL_PASSWORD=%s", s.Credentials.UserPassword),
 		fmt.Sprintf("MYSQL_ROOT_PASSWORD=%s", s.Credentials.RootPassword),
+		// the snapshots are created by root from outside of the container
+		"MYSQL_ROOT_HOST=%",
 	}
 
 	if len(s.Tmpfs) == 0 {
@@ -121,7 +127,7 @@ func (f mysqlFactory) healthCheck(settings interface{}) ComponentHealthCheck {
 	}
 }
 
-func (f mysqlFactory) Component(_ cfg.Config, _ mon.Logger, containers map[string]*container, settings interface{}) (Component, error) {
+func (f mysqlFactory) Component(config cfg.Config, logger mon.Logger, containers map[string]*container, settings interface{}) (Component, error) {
 	s := settings.(*mysqlSettings)
 	binding := containers["main"].bindings["3306/tcp"]
 	client, err := f.connection(s, binding)
@@ -130,21 +136,46 @@ func (f mysqlFactory) Component(_ cfg.Config, _ mon.Logger, containers map[strin
 		return nil, fmt.Errorf("can not create client: %w", err)
 	}
 
+	root, err := f.connectionAs(s, binding, "root", s.Credentials.RootPassword)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create root client: %w", err)
+	}
+
 	component := &mysqlComponent{
 		baseComponent: baseComponent{
 			name: s.Name,
 		},
+		config:      config,
+		logger:      logger,
 		client:      client,
+		root:        root,
 		credentials: s.Credentials,
 		binding:     binding,
 	}
 
+	sources := make([]migrations.Source, len(s.Migrations))
+
+	for i, dir := range s.Migrations {
+		sources[i] = migrations.SqlSource(netHttp.Dir(dir), ".")
+	}
+
+	if len(sources) > 0 {
+		if err = component.Migrate(sources...); err != nil {
+			return nil, err
+		}
+	}
+
 	return component, nil
 }
 
 func (f mysqlFactory) connection(settings *mysqlSettings, binding containerBinding) (*sqlx.DB, error) {
+	return f.connectionAs(settings, binding, settings.Credentials.UserName, settings.Credentials.UserPassword)
+}
+
+func (f mysqlFactory) connectionAs(settings *mysqlSettings, binding containerBinding, user string, password string) (*sqlx.DB, error) {
 	dsn := url.URL{
-		User: url.UserPassword(settings.Credentials.UserName, settings.Credentials.UserPassword),
+		User: url.UserPassword(user, password),
 		Host: fmt.Sprintf("tcp(%s:%v)", binding.host, binding.port),
 		Path: settings.Credentials.DatabaseName,
 	}
@@ -46,12 +46,12 @@ func (f streamInputFactory) DescribeContainers(settings interface{}) componentCo
 	return nil
 }
 
-func (f streamInputFactory) Component(_ cfg.Config, _ mon.Logger, _ map[string]*container, settings interface{}) (Component, error) {
+func (f streamInputFactory) Component(config cfg.Config, _ mon.Logger, _ map[string]*container, settings interface{}) (Component, error) {
 	s := settings.(*streamInputSettings)
 
 	component := &streamInputComponent{
 		name: s.Name,
-		input: stream.ProvideInMemoryInput(s.Name, &stream.InMemorySettings{
+		input: stream.ProvideInMemoryInput(stream.NamespacedName(config, s.Name), &stream.InMemorySettings{
 			Size: 10,
 		}),
 	}
@@ -46,12 +46,12 @@ func (f streamOutputFactory) DescribeContainers(settings interface{}) componentC
 	return nil
 }
 
-func (f streamOutputFactory) Component(_ cfg.Config, _ mon.Logger, _ map[string]*container, settings interface{}) (Component, error) {
+func (f streamOutputFactory) Component(config cfg.Config, _ mon.Logger, _ map[string]*container, settings interface{}) (Component, error) {
 	s := settings.(*streamOutputSettings)
 
 	component := &streamOutputComponent{
 		name:   s.Name,
-		output: stream.ProvideInMemoryOutput(s.Name),
+		output: stream.ProvideInMemoryOutput(stream.NamespacedName(config, s.Name)),
 	}
 
 	return component, nil
@@ -0,0 +1,137 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	// set this environment variable to true to rewrite all golden files with the current responses
+	GoldenUpdateEnv = "GOSOLINE_UPDATE_GOLDEN"
+	goldenRedacted  = "<redacted>"
+)
+
+var goldenFileNameReplacer = regexp.MustCompile(`[^\w\-]+`)
+
+type goldenSettings struct {
+	dir        string
+	redactions []string
+}
+
+// WithGoldenFiles compares the response body of every ApiServerTestCase without ExpectedResult and ExpectedBody
+// against a golden file in dir named after the test. Missing golden files are recorded on the first run, set
+// GOSOLINE_UPDATE_GOLDEN to true to record all of them again. JSON bodies are compared semantically and the given redactions (dot separated
+// paths like data.createdAt, use * to match every key or array element) are replaced before storing and comparing.
+func WithGoldenFiles(dir string, redactions ...string) Option {
+	return func(s *suiteOptions) {
+		s.golden = &goldenSettings{
+			dir:        dir,
+			redactions: redactions,
+		}
+	}
+}
+
+func assertGoldenFile(t *testing.T, settings *goldenSettings, name string, body []byte) {
+	body, err := redactGoldenBody(body, settings.redactions)
+	if err != nil {
+		assert.FailNow(t, err.Error(), "can not redact response body")
+		return
+	}
+
+	fileName := goldenFileNameReplacer.ReplaceAllString(name, "_") + ".golden"
+	path := filepath.Join(settings.dir, fileName)
+
+	expected, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) || os.Getenv(GoldenUpdateEnv) == "true" {
+		if err := writeGoldenFile(path, body); err != nil {
+			assert.FailNow(t, err.Error(), "can not write golden file")
+		}
+
+		return
+	}
+
+	if err != nil {
+		assert.FailNow(t, err.Error(), "can not read golden file %s", path)
+		return
+	}
+
+	if json.Valid(expected) && json.Valid(body) {
+		assert.JSONEq(t, string(expected), string(body), "response body should match golden file %s", path)
+		return
+	}
+
+	assert.Equal(t, string(expected), string(body), "response body should match golden file %s", path)
+}
+
+func writeGoldenFile(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("can not create directory for golden file %s: %w", path, err)
+	}
+
+	if json.Valid(body) {
+		buf := &bytes.Buffer{}
+
+		if err := json.Indent(buf, body, "", "  "); err != nil {
+			return fmt.Errorf("can not indent golden file %s: %w", path, err)
+		}
+
+		body = buf.Bytes()
+	}
+
+	if err := ioutil.WriteFile(path, body, 0644); err != nil {
+		return fmt.Errorf("can not write golden file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func redactGoldenBody(body []byte, redactions []string) ([]byte, error) {
+	if len(redactions) == 0 || !json.Valid(body) {
+		return body, nil
+	}
+
+	var data interface{}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("can not decode response body: %w", err)
+	}
+
+	for _, redaction := range redactions {
+		data = redactGoldenValue(data, strings.Split(redaction, "."))
+	}
+
+	return json.Marshal(data)
+}
+
+func redactGoldenValue(value interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return goldenRedacted
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			if path[0] == "*" || path[0] == key {
+				v[key] = redactGoldenValue(elem, path[1:])
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			if path[0] == "*" || path[0] == strconv.Itoa(i) {
+				v[i] = redactGoldenValue(elem, path[1:])
+			}
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,134 @@
+package suite
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+const (
+	goldenNormalizedId        = "<id>"
+	goldenNormalizedTimestamp = "<timestamp>"
+)
+
+var (
+	goldenIdPattern        = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	goldenTimestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?$`)
+)
+
+type goldenMessage struct {
+	Attributes map[string]interface{} `json:"attributes"`
+	Body       interface{}            `json:"body"`
+}
+
+// AssertGoldenStreamOutput compares the messages written to the in memory output with the golden file in dir named
+// after the test and the output. Json bodies are stored decoded, uuids and timestamps are normalized and the
+// redactions work like the ones of WithGoldenFiles with the paths starting at the list of messages, e.g.
+// *.body.price.
+func (s *Suite) AssertGoldenStreamOutput(dir string, outputName string, redactions ...string) {
+	output := s.Env().StreamOutput(outputName)
+	messages := make([]*stream.Message, 0, output.Len())
+
+	for i := 0; i < output.Len(); i++ {
+		msg, _ := output.Get(i)
+		messages = append(messages, msg)
+	}
+
+	AssertGoldenStreamMessages(s.T(), dir, fmt.Sprintf("%s_%s", s.T().Name(), outputName), messages, redactions...)
+}
+
+// AssertGoldenMetrics compares the metric data published to the memory metric writer with the golden file in dir
+// named after the test. The data is sorted by name and dimensions and the timestamps are normalized.
+func (s *Suite) AssertGoldenMetrics(dir string, redactions ...string) {
+	data := memoryMetricWriter(s.Env()).Data()
+
+	AssertGoldenMetricData(s.T(), dir, fmt.Sprintf("%s_metrics", s.T().Name()), data, redactions...)
+}
+
+func AssertGoldenStreamMessages(t *testing.T, dir string, name string, messages []*stream.Message, redactions ...string) {
+	snapshot := make([]goldenMessage, len(messages))
+
+	for i, msg := range messages {
+		snapshot[i] = goldenMessage{
+			Attributes: msg.Attributes,
+			Body:       msg.Body,
+		}
+
+		var body interface{}
+
+		if err := json.Unmarshal([]byte(msg.Body), &body); err == nil {
+			snapshot[i].Body = body
+		}
+	}
+
+	assertGoldenSnapshot(t, dir, name, snapshot, redactions)
+}
+
+func AssertGoldenMetricData(t *testing.T, dir string, name string, data mon.MetricData, redactions ...string) {
+	sorted := make(mon.MetricData, len(data))
+	copy(sorted, data)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].MetricName != sorted[j].MetricName {
+			return sorted[i].MetricName < sorted[j].MetricName
+		}
+
+		return sorted[i].DimensionKey() < sorted[j].DimensionKey()
+	})
+
+	assertGoldenSnapshot(t, dir, name, sorted, redactions)
+}
+
+func assertGoldenSnapshot(t *testing.T, dir string, name string, snapshot interface{}, redactions []string) {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		assert.FailNow(t, err.Error(), "can not encode snapshot %s", name)
+		return
+	}
+
+	var data interface{}
+
+	if err = json.Unmarshal(body, &data); err != nil {
+		assert.FailNow(t, err.Error(), "can not decode snapshot %s", name)
+		return
+	}
+
+	if body, err = json.Marshal(normalizeGoldenValue(data)); err != nil {
+		assert.FailNow(t, err.Error(), "can not encode snapshot %s", name)
+		return
+	}
+
+	assertGoldenFile(t, &goldenSettings{
+		dir:        dir,
+		redactions: redactions,
+	}, name, body)
+}
+
+// normalizeGoldenValue replaces the uuids and timestamps, which differ in every run, with placeholders
+func normalizeGoldenValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = normalizeGoldenValue(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = normalizeGoldenValue(elem)
+		}
+	case string:
+		if goldenIdPattern.MatchString(v) {
+			return goldenNormalizedId
+		}
+
+		if goldenTimestampPattern.MatchString(v) {
+			return goldenNormalizedTimestamp
+		}
+	}
+
+	return value
+}
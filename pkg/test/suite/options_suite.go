@@ -18,10 +18,13 @@ type suiteOptions struct {
 	envOptions  []env.Option
 	envSetup    []func() error
 	envIsShared bool
+	isolated    bool
 
 	appOptions   []application.Option
 	appModules   map[string]kernel.ModuleFactory
 	appFactories []kernel.MultiModuleFactory
+
+	golden *goldenSettings
 }
 
 func newSuiteOptions() *suiteOptions {
@@ -44,6 +47,8 @@ func (s *suiteOptions) addEnvOption(opt env.Option) {
 
 type Option func(s *suiteOptions)
 
+// WithClockProvider replaces the clock provider of the whole process, so suites using it must not run in parallel
+// with other suites, not even with WithIsolatedResources.
 func WithClockProvider(clk clock.Clock) Option {
 	return func(s *suiteOptions) {
 		s.envSetup = append(s.envSetup, func() error {
@@ -53,6 +58,8 @@ func WithClockProvider(clk clock.Clock) Option {
 	}
 }
 
+// WithClockProviderAt replaces the clock provider of the whole process with a fake clock at the datetime, so suites
+// using it must not run in parallel with other suites, not even with WithIsolatedResources.
 func WithClockProviderAt(datetime string) Option {
 	return func(s *suiteOptions) {
 		s.envSetup = append(s.envSetup, func() error {
@@ -144,6 +151,17 @@ func WithModuleFactory(factory kernel.MultiModuleFactory) Option {
 	}
 }
 
+// WithIsolatedResources runs every test with an env and a stream namespace of its own, so the names of the queues,
+// tables and other resources derived from the env and the in memory inputs, outputs and producer daemons don't clash
+// with the ones of other suites running in parallel with t.Parallel(). The memory metric writer gets the namespace, too.
+// Only the resources of the test are reset after it. The clock provider and the metric channel are still shared by
+// the whole process, so suites asserting on metrics or changing the clock must not run in parallel.
+func WithIsolatedResources() Option {
+	return func(s *suiteOptions) {
+		s.isolated = true
+	}
+}
+
 func WithSharedEnvironment() Option {
 	return func(s *suiteOptions) {
 		s.envIsShared = true
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/http"
+	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/stream"
 	"github.com/applike/gosoline/pkg/test/env"
+	"github.com/applike/gosoline/pkg/uuid"
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"strings"
@@ -92,10 +95,25 @@ func runTestCaseWithSharedEnvironment(t *testing.T, suite TestingSuite, suiteOpt
 		env.WithLoggerSettingsFromConfig,
 	}
 	envOptions = append(envOptions, suiteOptions.envOptions...)
+
+	// the namespace is part of the env, so all resource names derived from it are unique for every environment
+	namespace := ""
+	environmentName := "test"
+
+	if suiteOptions.isolated {
+		namespace = strings.ReplaceAll(uuid.New().NewV4(), "-", "")[:12]
+		environmentName = fmt.Sprintf("test-%s", namespace)
+	}
+
 	envOptions = append(envOptions, env.WithConfigMap(map[string]interface{}{
-		"env": "test",
+		"env": environmentName,
 	}))
 
+	if namespace != "" {
+		envOptions = append(envOptions, env.WithConfigSetting(stream.NamespaceKey, namespace))
+		envOptions = append(envOptions, env.WithConfigSetting(mon.MemoryMetricWriterNamespaceKey, namespace))
+	}
+
 	environment, err := env.NewEnvironment(t, envOptions...)
 
 	if err != nil {
@@ -134,12 +152,30 @@ func runTestCaseWithSharedEnvironment(t *testing.T, suite TestingSuite, suiteOpt
 			}
 		}
 
-		stream.ResetInMemoryInputs()
-		stream.ResetInMemoryOutputs()
-		stream.ResetProducerDaemons()
+		resetTestCase(namespace)
 	}
 }
 
+// resetTestCase resets the global state of the test. With a namespace only the state of the namespace is reset, as
+// other suites could be running in parallel.
+func resetTestCase(namespace string) {
+	if namespace != "" {
+		stream.ResetInMemoryInputs(namespace)
+		stream.ResetInMemoryOutputs(namespace)
+		stream.ResetProducerDaemons(namespace)
+		mon.ProvideNamespacedMemoryMetricWriter(namespace).Reset()
+
+		return
+	}
+
+	stream.ResetInMemoryInputs()
+	stream.ResetInMemoryOutputs()
+	stream.ResetProducerDaemons()
+	http.ResetHttpClients()
+	mon.ResetMetricChannel()
+	mon.ProvideMemoryMetricWriter().Reset()
+}
+
 func runTestCaseWithIsolatedEnvironment(t *testing.T, suite TestingSuite, suiteOptions *suiteOptions, testCases map[string]testCaseRunner) {
 	for name, testCase := range testCases {
 		runTestCaseWithSharedEnvironment(t, suite, suiteOptions, map[string]testCaseRunner{
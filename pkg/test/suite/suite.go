@@ -1,6 +1,7 @@
 package suite
 
 import (
+	"github.com/applike/gosoline/pkg/clock"
 	"github.com/applike/gosoline/pkg/test/env"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -56,3 +57,15 @@ func (s *Suite) SetT(t *testing.T) {
 func (s *Suite) T() *testing.T {
 	return s.t
 }
+
+// Clock returns the fake clock used by the modules under test. Advancing it fires the tickers and timers of the
+// modules, e.g. the flushes of the producer daemon, expirations of ttls or cron modules, without sleeping in the test.
+func (s *Suite) Clock() clock.FakeClock {
+	fakeClock, ok := clock.Provider.(clock.FakeClock)
+
+	if !ok {
+		assert.FailNow(s.t, "the clock is no fake clock", "the clock provider is of type %T, use the WithClockProvider option to set a fake clock", clock.Provider)
+	}
+
+	return fakeClock
+}
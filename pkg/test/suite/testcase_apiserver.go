@@ -1,7 +1,9 @@
 package suite
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/application"
@@ -11,6 +13,7 @@ import (
 	"github.com/applike/gosoline/pkg/test/env"
 	"github.com/go-resty/resty/v2"
 	"github.com/stretchr/testify/assert"
+	netHttp "net/http"
 	"reflect"
 	"testing"
 )
@@ -26,14 +29,34 @@ type TestingSuiteApiDefinitionsAware interface {
 	SetupApiDefinitions() apiserver.Definer
 }
 
+// ApiServerTestCaseFile is sent as a file part of a multipart/form-data request
+type ApiServerTestCaseFile struct {
+	Param       string
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// ApiServerTestCase sends a request to the api server of the suite. If FormData or Files are set, the request is sent
+// as multipart/form-data instead of the Body. ExpectedBody is compared with the response body decoded into the type of
+// ExpectedBody, a string or a byte slice is compared with the raw body. Setup runs before the application is started
+// and TearDown after it stopped.
 type ApiServerTestCase struct {
 	Method             string
 	Url                string
 	Headers            map[string]string
+	Cookies            map[string]string
 	Body               interface{}
+	FormData           map[string]string
+	Files              []ApiServerTestCaseFile
 	ExpectedStatusCode int
+	ExpectedHeaders    map[string]string
+	ExpectedCookies    map[string]string
+	ExpectedBody       interface{}
 	ExpectedResult     interface{}
 	ExpectedErr        error
+	Setup              func() error
+	TearDown           func() error
 	Assert             func() error
 }
 
@@ -44,7 +67,20 @@ func (c ApiServerTestCase) request(client *resty.Client) (*resty.Response, error
 		req.SetHeaders(c.Headers)
 	}
 
-	if c.Body != nil {
+	for name, value := range c.Cookies {
+		req.SetCookie(&netHttp.Cookie{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	if c.FormData != nil || len(c.Files) > 0 {
+		req.SetMultipartFormData(c.FormData)
+
+		for _, file := range c.Files {
+			req.SetMultipartField(file.Param, file.FileName, file.ContentType, bytes.NewReader(file.Content))
+		}
+	} else if c.Body != nil {
 		req.SetBody(c.Body)
 	}
 
@@ -55,6 +91,72 @@ func (c ApiServerTestCase) request(client *resty.Client) (*resty.Response, error
 	return req.Execute(c.Method, c.Url)
 }
 
+func (c ApiServerTestCase) assertResponse(t *testing.T, response *resty.Response) {
+	for name, value := range c.ExpectedHeaders {
+		assert.Equal(t, value, response.Header().Get(name), "response header %s should match", name)
+	}
+
+	if len(c.ExpectedCookies) > 0 {
+		cookies := make(map[string]string)
+
+		for _, cookie := range response.Cookies() {
+			cookies[cookie.Name] = cookie.Value
+		}
+
+		for name, value := range c.ExpectedCookies {
+			actual, ok := cookies[name]
+
+			if assert.True(t, ok, "response should set the cookie %s", name) {
+				assert.Equal(t, value, actual, "response cookie %s should match", name)
+			}
+		}
+	}
+
+	if c.ExpectedBody != nil {
+		assertApiServerResponseBody(t, c.ExpectedBody, response.Body())
+	}
+}
+
+// assertApiServerResponseBody decodes the body into the type of the expected body and compares both as indented
+// json, so a mismatch is reported as a diff of the json documents
+func assertApiServerResponseBody(t *testing.T, expected interface{}, body []byte) {
+	switch e := expected.(type) {
+	case string:
+		assert.Equal(t, e, string(body), "response body should match")
+		return
+	case []byte:
+		assert.Equal(t, string(e), string(body), "response body should match")
+		return
+	}
+
+	typ := reflect.TypeOf(expected)
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	actual := reflect.New(typ).Interface()
+
+	if err := json.Unmarshal(body, actual); err != nil {
+		assert.Fail(t, fmt.Sprintf("can not decode response body into %T: %s", expected, err.Error()), "response body: %s", string(body))
+		return
+	}
+
+	expectedJson, err := json.MarshalIndent(expected, "", "  ")
+	if err != nil {
+		assert.FailNow(t, err.Error(), "can not encode expected body")
+		return
+	}
+
+	actualJson, err := json.MarshalIndent(actual, "", "  ")
+	if err != nil {
+		assert.FailNow(t, err.Error(), "can not encode response body")
+		return
+	}
+
+	assert.Equal(t, string(expectedJson), string(actualJson), "response body should match")
+}
+
 func isTestCaseApiServer(method reflect.Method) bool {
 	if method.Func.Type().NumIn() != 1 {
 		return false
@@ -87,6 +189,21 @@ func buildTestCaseApiServer(suite TestingSuite, method reflect.Method) (testCase
 	return func(t *testing.T, suite TestingSuite, suiteOptions *suiteOptions, environment *env.Environment) {
 		suite.SetT(t)
 
+		if tc.Setup != nil {
+			if err := tc.Setup(); err != nil {
+				assert.FailNow(t, err.Error(), "there should be no error on setup")
+				return
+			}
+		}
+
+		if tc.TearDown != nil {
+			defer func() {
+				if err := tc.TearDown(); err != nil {
+					assert.Fail(t, err.Error(), "there should be no error on tear down")
+				}
+			}()
+		}
+
 		suiteOptions.appModules["api"] = func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
 			module, err := apiserver.New(apiDefinitions)(ctx, config, logger)
 
@@ -116,6 +233,7 @@ func buildTestCaseApiServer(suite TestingSuite, method reflect.Method) (testCase
 			response, err := tc.request(client)
 
 			assert.Equal(t, tc.ExpectedStatusCode, response.StatusCode(), "response status code should match")
+			tc.assertResponse(t, response)
 
 			if tc.ExpectedErr == nil {
 				assert.NoError(t, err)
@@ -123,6 +241,10 @@ func buildTestCaseApiServer(suite TestingSuite, method reflect.Method) (testCase
 				assert.EqualError(t, err, tc.ExpectedErr.Error())
 			}
 
+			if suiteOptions.golden != nil && tc.ExpectedResult == nil && tc.ExpectedBody == nil && err == nil {
+				assertGoldenFile(t, suiteOptions.golden, t.Name(), response.Body())
+			}
+
 			app.Stop()
 			app.WaitDone()
 
@@ -37,16 +37,16 @@ func buildTestCaseApplication(suite TestingSuite, method reflect.Method) (testCa
 }
 
 func runTestCaseApplication(t *testing.T, suite TestingSuite, suiteOptions *suiteOptions, environment *env.Environment, testcase func(aut *appUnderTest)) {
+	config := environment.Config()
+	logger := environment.Logger()
+
 	appOptions := append(suiteOptions.appOptions, []application.Option{
 		application.WithProducerDaemon,
 		application.WithConfigMap(map[string]interface{}{
-			"env": "test",
+			"env": config.GetString("env", "test"),
 		}),
 	}...)
 
-	config := environment.Config()
-	logger := environment.Logger()
-
 	app, err := application.NewWithInterfaces(config, logger, appOptions...)
 
 	if err != nil {
@@ -0,0 +1,172 @@
+package suite
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/application"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/applike/gosoline/pkg/test/env"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+const consumerTestCaseName = "default"
+
+func init() {
+	testCaseDefinitions["consumer"] = testCaseDefinition{
+		matcher: isTestCaseConsumer,
+		builder: buildTestCaseConsumer,
+	}
+}
+
+type TestingSuiteConsumerAware interface {
+	SetupConsumer() stream.ConsumerCallbackFactory
+}
+
+type TestingConsumerSuite interface {
+	TestingSuite
+	TestingSuiteConsumerAware
+}
+
+type ConsumerTestCaseMetric struct {
+	Name       string
+	Dimensions map[string]string
+	Value      float64
+}
+
+// ConsumerTestCase publishes the Input on the input of the consumer of the suite and asserts on the messages the
+// consumer acknowledged, the messages left for a retry by the input, the messages written to the outputs and the
+// metrics. The values of all data points of an expected metric with the same dimensions are summed up.
+type ConsumerTestCase struct {
+	Input           []StreamTestCaseInput
+	ExpectedAcks    int
+	ExpectedRetries int
+	Output          map[string][]StreamTestCaseOutput
+	ExpectedMetrics []ConsumerTestCaseMetric
+	Assert          func() error
+}
+
+// RunConsumerTestSuite runs the test cases of a suite which sets up a consumer. The input of the consumer has to be
+// configured, e.g. in the config file of the suite, so the environment provides it in memory.
+func RunConsumerTestSuite(t *testing.T, suite TestingConsumerSuite, extraOptions ...Option) {
+	Run(t, suite, extraOptions...)
+}
+
+func isTestCaseConsumer(method reflect.Method) bool {
+	if method.Func.Type().NumIn() != 1 {
+		return false
+	}
+
+	if method.Func.Type().NumOut() != 1 {
+		return false
+	}
+
+	actualType0 := method.Func.Type().Out(0)
+	expectedType := reflect.TypeOf((*ConsumerTestCase)(nil))
+
+	return actualType0 == expectedType
+}
+
+func buildTestCaseConsumer(suite TestingSuite, method reflect.Method) (testCaseRunner, error) {
+	var ok bool
+	var consumerAware TestingSuiteConsumerAware
+
+	out := method.Func.Call([]reflect.Value{reflect.ValueOf(suite)})
+	tc := out[0].Interface().(*ConsumerTestCase)
+
+	if consumerAware, ok = suite.(TestingSuiteConsumerAware); !ok {
+		return nil, fmt.Errorf("the suite has to implement the TestingSuiteConsumerAware interface to be able to run consumer test cases")
+	}
+
+	callback := consumerAware.SetupConsumer()
+
+	return func(t *testing.T, suite TestingSuite, suiteOptions *suiteOptions, environment *env.Environment) {
+		suite.SetT(t)
+
+		options := *suiteOptions
+		options.appOptions = append([]application.Option{}, suiteOptions.appOptions...)
+		options.appOptions = append(options.appOptions, application.WithMetricDaemon, application.WithConfigMap(map[string]interface{}{
+			"mon": map[string]interface{}{
+				"metric": map[string]interface{}{
+					"enabled":   true,
+					"interval":  "1h",
+					"writers":   []string{mon.MetricWriterTypeMemory},
+					"namespace": environment.Config().GetString(mon.MemoryMetricWriterNamespaceKey, ""),
+				},
+			},
+		}))
+
+		options.appModules = make(map[string]kernel.ModuleFactory, len(suiteOptions.appModules)+1)
+
+		for name, module := range suiteOptions.appModules {
+			options.appModules[name] = module
+		}
+
+		options.appModules[fmt.Sprintf("consumer-%s", consumerTestCaseName)] = stream.NewConsumer(consumerTestCaseName, callback)
+
+		consumerKey := stream.ConfigurableConsumerKey(consumerTestCaseName)
+		inputName := environment.Config().GetString(fmt.Sprintf("%s.input", consumerKey), "consumer")
+
+		runTestCaseApplication(t, suite, &options, environment, func(app *appUnderTest) {
+			input := suite.Env().StreamInput(inputName)
+
+			for _, d := range tc.Input {
+				input.Publish(d.Body, d.Attributes)
+			}
+
+			input.Stop()
+			app.WaitDone()
+
+			acks := len(input.Acknowledged())
+			assert.Equal(t, tc.ExpectedAcks, acks, "the number of acknowledged messages does not match")
+			assert.Equal(t, tc.ExpectedRetries, len(tc.Input)-acks, "the number of messages left for a retry does not match")
+
+			for outputName, data := range tc.Output {
+				output := suite.Env().StreamOutput(outputName)
+
+				assert.Equal(t, len(data), output.Len(), "the number of messages written to output %s does not match", outputName)
+
+				for i, d := range data {
+					model := d.Model
+					attrs := output.Unmarshal(i, model)
+
+					assert.Equal(t, d.ExpectedAttributes, attrs, "attributes do not match")
+					assert.Equal(t, d.ExpectedBody, model, "body does not match")
+				}
+			}
+
+			metrics := memoryMetricWriter(suite.Env()).Data()
+
+			for _, expected := range tc.ExpectedMetrics {
+				assert.Equal(t, expected.Value, sumConsumerTestCaseMetric(metrics, expected), "the value of metric %s with the dimensions %v does not match", expected.Name, expected.Dimensions)
+			}
+
+			if tc.Assert != nil {
+				if err := tc.Assert(); err != nil {
+					assert.NoError(t, err, "there should be no error happening on assert")
+				}
+			}
+		})
+	}, nil
+}
+
+// memoryMetricWriter returns the memory metric writer of the namespace of the environment
+func memoryMetricWriter(environment *env.Environment) *mon.MemoryMetricWriter {
+	return mon.ProvideNamespacedMemoryMetricWriter(environment.Config().GetString(mon.MemoryMetricWriterNamespaceKey, ""))
+}
+
+func sumConsumerTestCaseMetric(metrics mon.MetricData, expected ConsumerTestCaseMetric) float64 {
+	value := 0.0
+
+	for _, datum := range metrics {
+		if datum.MetricName != expected.Name || !reflect.DeepEqual(map[string]string(datum.Dimensions), expected.Dimensions) {
+			continue
+		}
+
+		value += datum.Value
+	}
+
+	return value
+}
@@ -0,0 +1,174 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/test/env"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const grpcTestCaseDefaultTimeout = 5 * time.Second
+
+func init() {
+	testCaseDefinitions["grpcServer"] = testCaseDefinition{
+		matcher: isTestCaseGrpcServer,
+		builder: buildTestCaseGrpcServer,
+	}
+}
+
+// GrpcServerModule is the module serving grpc requests. It has to listen on a random port in tests and return it
+// once the module is booted.
+type GrpcServerModule interface {
+	kernel.Module
+	GetPort() (*int, error)
+}
+
+type TestingSuiteGrpcServerAware interface {
+	SetupGrpcServer() kernel.ModuleFactory
+}
+
+// GrpcTestCase calls the grpc server of the suite. The context passed to Call has the deadline of Timeout (5 seconds
+// by default) and carries the Metadata to the server. The code of the error returned by Call is compared with
+// ExpectedCode and the response with ExpectedResponse if set.
+type GrpcTestCase struct {
+	Timeout          time.Duration
+	Metadata         map[string]string
+	Call             func(ctx context.Context, conn *grpc.ClientConn) (proto.Message, error)
+	ExpectedCode     codes.Code
+	ExpectedResponse proto.Message
+	Assert           func() error
+}
+
+func (c GrpcTestCase) context() (context.Context, context.CancelFunc) {
+	timeout := c.Timeout
+
+	if timeout == 0 {
+		timeout = grpcTestCaseDefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	if len(c.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(c.Metadata))
+	}
+
+	return ctx, cancel
+}
+
+// DialGrpcServer connects to the grpc server listening on the port on localhost. The typed clients generated for the
+// services are created from the returned connection.
+func DialGrpcServer(ctx context.Context, port int, options ...grpc.DialOption) (*grpc.ClientConn, error) {
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	options = append([]grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock()}, options...)
+
+	conn, err := grpc.DialContext(ctx, address, options...)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not dial grpc server at %s: %w", address, err)
+	}
+
+	return conn, nil
+}
+
+func isTestCaseGrpcServer(method reflect.Method) bool {
+	if method.Func.Type().NumIn() != 1 {
+		return false
+	}
+
+	if method.Func.Type().NumOut() != 1 {
+		return false
+	}
+
+	actualType0 := method.Func.Type().Out(0)
+	expectedType := reflect.TypeOf((*GrpcTestCase)(nil))
+
+	return actualType0 == expectedType
+}
+
+func buildTestCaseGrpcServer(suite TestingSuite, method reflect.Method) (testCaseRunner, error) {
+	var ok bool
+	var grpcServerAware TestingSuiteGrpcServerAware
+	var server GrpcServerModule
+
+	out := method.Func.Call([]reflect.Value{reflect.ValueOf(suite)})
+	tc := out[0].Interface().(*GrpcTestCase)
+
+	if grpcServerAware, ok = suite.(TestingSuiteGrpcServerAware); !ok {
+		return nil, fmt.Errorf("the suite has to implement the TestingSuiteGrpcServerAware interface to be able to run grpc server test cases")
+	}
+
+	if tc.Call == nil {
+		return nil, fmt.Errorf("the grpc server test case has no call")
+	}
+
+	serverFactory := grpcServerAware.SetupGrpcServer()
+
+	return func(t *testing.T, suite TestingSuite, suiteOptions *suiteOptions, environment *env.Environment) {
+		suite.SetT(t)
+
+		suiteOptions.appModules["grpc"] = func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+			module, err := serverFactory(ctx, config, logger)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if server, ok = module.(GrpcServerModule); !ok {
+				return nil, fmt.Errorf("the grpc server module of type %T has to implement the GrpcServerModule interface", module)
+			}
+
+			return server, nil
+		}
+
+		runTestCaseApplication(t, suite, suiteOptions, environment, func(app *appUnderTest) {
+			port, err := server.GetPort()
+
+			if err != nil {
+				assert.FailNow(t, err.Error(), "can not get port of grpc server")
+				return
+			}
+
+			ctx, cancel := tc.context()
+			defer cancel()
+
+			conn, err := DialGrpcServer(ctx, *port)
+
+			if err != nil {
+				assert.FailNow(t, err.Error(), "can not connect to grpc server")
+				return
+			}
+
+			defer func() {
+				_ = conn.Close()
+			}()
+
+			response, err := tc.Call(ctx, conn)
+
+			assert.Equal(t, tc.ExpectedCode.String(), status.Code(err).String(), "the grpc status code should match: %v", err)
+
+			if tc.ExpectedResponse != nil {
+				assert.True(t, proto.Equal(tc.ExpectedResponse, response), "the response should match\nexpected: %v\nactual  : %v", tc.ExpectedResponse, response)
+			}
+
+			app.Stop()
+			app.WaitDone()
+
+			if tc.Assert != nil {
+				if err := tc.Assert(); err != nil {
+					assert.FailNowf(t, err.Error(), "there should be no error on assert")
+				}
+			}
+		})
+	}, nil
+}
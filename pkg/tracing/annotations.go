@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+// AddAnnotation adds an indexed annotation to the span of the context. Nothing happens if there is no span.
+func AddAnnotation(ctx context.Context, key string, value string) {
+	if span := GetSpanFromContext(ctx); span != nil {
+		span.AddAnnotation(key, value)
+	}
+}
+
+// AddMetadata adds non indexed metadata to the span of the context. Nothing happens if there is no span.
+func AddMetadata(ctx context.Context, key string, value interface{}) {
+	if span := GetSpanFromContext(ctx); span != nil {
+		span.AddMetadata(key, value)
+	}
+}
+
+// LoggerFieldsHook mirrors the selected logger fields as annotations onto the span of the logged context, so the
+// traces can be searched for them.
+type LoggerFieldsHook struct {
+	fields []string
+}
+
+func NewLoggerFieldsHook(fields []string) *LoggerFieldsHook {
+	return &LoggerFieldsHook{
+		fields: fields,
+	}
+}
+
+func (l LoggerFieldsHook) Fire(_ string, _ string, _ error, data *mon.Metadata) error {
+	if data.Context == nil || len(l.fields) == 0 {
+		return nil
+	}
+
+	span := GetSpanFromContext(data.Context)
+
+	if span == nil {
+		return nil
+	}
+
+	for _, field := range l.fields {
+		if value, ok := data.ContextFields[field]; ok {
+			span.AddAnnotation(field, fmt.Sprint(value))
+		}
+
+		if value, ok := data.Fields[field]; ok {
+			span.AddAnnotation(field, fmt.Sprint(value))
+		}
+	}
+
+	return nil
+}
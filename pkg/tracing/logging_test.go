@@ -53,6 +53,37 @@ func (s *LoggingSuite) TestLoggerErrorHook() {
 	s.span.AssertExpectations(s.T())
 }
 
+func (s *LoggingSuite) TestLoggerFieldsHook() {
+	s.span.On("AddAnnotation", "user_id", "42").Once()
+	s.span.On("AddAnnotation", "order_id", "abc").Once()
+
+	hook := tracing.NewLoggerFieldsHook([]string{"user_id", "order_id", "missing"})
+	err := hook.Fire("info", "msg", nil, &mon.Metadata{
+		Context: s.ctx,
+		ContextFields: mon.Fields{
+			"user_id": 42,
+			"other":   "value",
+		},
+		Fields: mon.Fields{
+			"order_id": "abc",
+		},
+	})
+
+	s.NoError(err)
+	s.span.AssertExpectations(s.T())
+}
+
+func (s *LoggingSuite) TestAddAnnotation() {
+	s.span.On("AddAnnotation", "key", "value").Once()
+	s.span.On("AddMetadata", "meta", 1).Once()
+
+	tracing.AddAnnotation(s.ctx, "key", "value")
+	tracing.AddMetadata(s.ctx, "meta", 1)
+	tracing.AddAnnotation(context.Background(), "key", "value")
+
+	s.span.AssertExpectations(s.T())
+}
+
 func TestLoggingSuite(t *testing.T) {
 	suite.Run(t, new(LoggingSuite))
 }
@@ -16,11 +16,7 @@ func NewMessageWithTraceEncoder(strategy TraceIdErrorStrategy) *MessageWithTrace
 }
 
 func (m MessageWithTraceEncoder) Encode(ctx context.Context, _ interface{}, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
-	var trace *Trace
-
-	if span := GetSpanFromContext(ctx); span != nil {
-		trace = span.GetTrace()
-	}
+	trace := TraceFromContext(ctx)
 
 	if trace == nil {
 		return ctx, attributes, nil
@@ -28,6 +24,10 @@ func (m MessageWithTraceEncoder) Encode(ctx context.Context, _ interface{}, attr
 
 	attributes["traceId"] = TraceToString(trace)
 
+	if traceParent, ok := TraceToTraceParent(trace); ok {
+		attributes[HeaderTraceParent] = traceParent
+	}
+
 	return ctx, attributes, nil
 }
 
@@ -36,7 +36,7 @@ func (m MessageWithTraceEncoder) Decode(ctx context.Context, _ interface{}, attr
 	var traceId string
 
 	if _, ok = attributes["traceId"]; !ok {
-		return ctx, attributes, nil
+		return m.decodeTraceParent(ctx, attributes)
 	}
 
 	if traceId, ok = attributes["traceId"].(string); !ok {
@@ -57,6 +57,36 @@ func (m MessageWithTraceEncoder) Decode(ctx context.Context, _ interface{}, attr
 
 	ctx = ContextWithTrace(ctx, trace)
 	delete(attributes, "traceId")
+	delete(attributes, HeaderTraceParent)
+
+	return ctx, attributes, nil
+}
+
+func (m MessageWithTraceEncoder) decodeTraceParent(ctx context.Context, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
+	if _, ok := attributes[HeaderTraceParent]; !ok {
+		return ctx, attributes, nil
+	}
+
+	traceParent, ok := attributes[HeaderTraceParent].(string)
+
+	if !ok {
+		err := fmt.Errorf("the traceparent attribute should be of type string to decode it")
+		err = m.strategy.TraceIdInvalid(err)
+
+		return ctx, attributes, err
+	}
+
+	trace, err := TraceParentToTrace(traceParent)
+
+	if err != nil {
+		err := fmt.Errorf("the traceparent attribute is invalid: %w", err)
+		err = m.strategy.TraceIdInvalid(err)
+
+		return ctx, attributes, err
+	}
+
+	ctx = ContextWithTrace(ctx, trace)
+	delete(attributes, HeaderTraceParent)
 
 	return ctx, attributes, nil
 }
@@ -70,3 +70,38 @@ func TestMessageWithTraceEncoder_Decode_Warning(t *testing.T) {
 
 	logger.AssertExpectations(t)
 }
+
+func TestMessageWithTraceEncoder_EncodePropagatedTrace(t *testing.T) {
+	encoder := tracing.NewMessageWithTraceEncoder(tracing.TraceIdErrorReturnStrategy{})
+
+	ctx := tracing.ContextWithTrace(context.Background(), &tracing.Trace{
+		TraceId:  "1-5e3d557d-d06c248cc50169bd71b44fec",
+		ParentId: "af297a5da6453826",
+		Sampled:  true,
+	})
+
+	_, attributes, err := encoder.Encode(ctx, nil, map[string]interface{}{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Root=1-5e3d557d-d06c248cc50169bd71b44fec;Parent=af297a5da6453826;Sampled=1", attributes["traceId"])
+	assert.Equal(t, "00-5e3d557dd06c248cc50169bd71b44fec-af297a5da6453826-01", attributes["traceparent"])
+}
+
+func TestMessageWithTraceEncoder_DecodeTraceParent(t *testing.T) {
+	attributes := map[string]interface{}{
+		"traceparent": "00-5e3d557dd06c248cc50169bd71b44fec-af297a5da6453826-01",
+	}
+
+	encoder := tracing.NewMessageWithTraceEncoder(tracing.TraceIdErrorReturnStrategy{})
+	ctx, decodedAttributes, err := encoder.Decode(context.Background(), nil, attributes)
+
+	expected := &tracing.Trace{
+		TraceId:  "1-5e3d557d-d06c248cc50169bd71b44fec",
+		ParentId: "af297a5da6453826",
+		Sampled:  true,
+	}
+
+	assert.NoError(t, err)
+	assert.NotContains(t, decodedAttributes, "traceparent")
+	assert.Equal(t, expected, tracing.GetTraceFromContext(ctx))
+}
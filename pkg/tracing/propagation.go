@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	HeaderAmznTraceId = "X-Amzn-Trace-Id"
+	HeaderTraceParent = "traceparent"
+
+	traceParentVersion = "00"
+)
+
+var (
+	traceParentPattern = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+	xrayTraceIdPattern = regexp.MustCompile(`^1-([0-9a-f]{8})-([0-9a-f]{24})$`)
+)
+
+// TraceFromContext returns the trace of the current span or, if there is none, the trace which was propagated
+// to the context by an incoming request or message.
+func TraceFromContext(ctx context.Context) *Trace {
+	if span := GetSpanFromContext(ctx); span != nil {
+		if trace := span.GetTrace(); trace != nil && trace.TraceId != "" {
+			return trace
+		}
+	}
+
+	if trace := GetTraceFromContext(ctx); trace != nil && trace.TraceId != "" {
+		// the span id of the sender is the parent id of the propagated trace, so it's the one to pass on
+		return &Trace{
+			TraceId: trace.TraceId,
+			Id:      trace.ParentId,
+			Sampled: trace.Sampled,
+		}
+	}
+
+	return nil
+}
+
+// InjectHeaders writes the trace of the context as X-Amzn-Trace-Id and W3C traceparent header. Headers which are
+// already set are kept.
+func InjectHeaders(ctx context.Context, header http.Header) {
+	trace := TraceFromContext(ctx)
+
+	if trace == nil {
+		return
+	}
+
+	if header.Get(HeaderAmznTraceId) == "" {
+		header.Set(HeaderAmznTraceId, TraceToString(trace))
+	}
+
+	if traceParent, ok := TraceToTraceParent(trace); ok && header.Get(HeaderTraceParent) == "" {
+		header.Set(HeaderTraceParent, traceParent)
+	}
+}
+
+// ExtractHeaders reads the trace from a W3C traceparent or X-Amzn-Trace-Id header and stores it in the context.
+func ExtractHeaders(ctx context.Context, header http.Header) context.Context {
+	if traceParent := header.Get(HeaderTraceParent); traceParent != "" {
+		if trace, err := TraceParentToTrace(traceParent); err == nil {
+			return ContextWithTrace(ctx, trace)
+		}
+	}
+
+	if traceId := header.Get(HeaderAmznTraceId); traceId != "" {
+		if trace, err := StringToTrace(traceId); err == nil {
+			return ContextWithTrace(ctx, trace)
+		}
+	}
+
+	return ctx
+}
+
+// PropagationHandler stores the trace of incoming requests in the request context, so it is passed on to outgoing
+// requests and messages even if the tracer didn't start a span for the request.
+func PropagationHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if GetSpanFromContext(ctx) == nil && GetTraceFromContext(ctx) == nil {
+			r = r.WithContext(ExtractHeaders(ctx, r.Header))
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// TraceToTraceParent formats the trace as W3C traceparent. X-Ray trace ids are converted, other ids which can't be
+// represented as traceparent are reported by returning false.
+func TraceToTraceParent(trace *Trace) (string, bool) {
+	traceId := trace.TraceId
+
+	if matches := xrayTraceIdPattern.FindStringSubmatch(traceId); matches != nil {
+		traceId = matches[1] + matches[2]
+	}
+
+	flags := "00"
+	if trace.Sampled {
+		flags = "01"
+	}
+
+	traceParent := strings.Join([]string{traceParentVersion, traceId, trace.Id, flags}, "-")
+
+	if !traceParentPattern.MatchString(traceParent) {
+		return "", false
+	}
+
+	return traceParent, true
+}
+
+// TraceParentToTrace parses a W3C traceparent. The trace id is converted to the X-Ray format, so it can be used
+// by every tracer.
+func TraceParentToTrace(traceParent string) (*Trace, error) {
+	matches := traceParentPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(traceParent)))
+
+	if matches == nil {
+		return nil, fmt.Errorf("the traceparent [%s] seems malformed", traceParent)
+	}
+
+	traceId := matches[2]
+
+	return &Trace{
+		TraceId:  fmt.Sprintf("1-%s-%s", traceId[:8], traceId[8:]),
+		ParentId: matches[3],
+		Sampled:  matches[4] == "01",
+	}, nil
+}
@@ -0,0 +1,88 @@
+package tracing_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInjectHeaders(t *testing.T) {
+	tracer := getTracer(t)
+
+	ctx, span := tracer.StartSpan("test_trans")
+	defer span.Finish()
+
+	header := http.Header{}
+	tracing.InjectHeaders(ctx, header)
+
+	trace := span.GetTrace()
+	traceParent, ok := tracing.TraceToTraceParent(trace)
+
+	assert.True(t, ok)
+	assert.Equal(t, tracing.TraceToString(trace), header.Get(tracing.HeaderAmznTraceId))
+	assert.Equal(t, traceParent, header.Get(tracing.HeaderTraceParent))
+}
+
+func TestInjectHeaders_WithoutTrace(t *testing.T) {
+	header := http.Header{}
+	tracing.InjectHeaders(context.Background(), header)
+
+	assert.Empty(t, header)
+}
+
+func TestExtractHeaders(t *testing.T) {
+	expected := &tracing.Trace{
+		TraceId:  "1-5e3d557d-d06c248cc50169bd71b44fec",
+		ParentId: "af297a5da6453826",
+		Sampled:  true,
+	}
+
+	header := http.Header{}
+	header.Set(tracing.HeaderTraceParent, "00-5e3d557dd06c248cc50169bd71b44fec-af297a5da6453826-01")
+
+	ctx := tracing.ExtractHeaders(context.Background(), header)
+	assert.Equal(t, expected, tracing.GetTraceFromContext(ctx))
+
+	header = http.Header{}
+	header.Set(tracing.HeaderAmznTraceId, "Root=1-5e3d557d-d06c248cc50169bd71b44fec;Parent=af297a5da6453826;Sampled=1")
+
+	ctx = tracing.ExtractHeaders(context.Background(), header)
+	assert.Equal(t, expected, tracing.GetTraceFromContext(ctx))
+}
+
+func TestTraceParentToTrace_Invalid(t *testing.T) {
+	_, err := tracing.TraceParentToTrace("00-5e3d557d-af297a5da6453826-01")
+
+	assert.Error(t, err)
+}
+
+func TestPropagationHandler(t *testing.T) {
+	var received http.Header
+
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer downstream.Close()
+
+	handler := tracing.PropagationHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+		assert.NoError(t, err)
+
+		tracing.InjectHeaders(r.Context(), req.Header)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(tracing.HeaderTraceParent, "00-5e3d557dd06c248cc50169bd71b44fec-af297a5da6453826-01")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "00-5e3d557dd06c248cc50169bd71b44fec-af297a5da6453826-01", received.Get(tracing.HeaderTraceParent))
+	assert.Equal(t, "Root=1-5e3d557d-d06c248cc50169bd71b44fec;Parent=af297a5da6453826;Sampled=1", received.Get(tracing.HeaderAmznTraceId))
+}
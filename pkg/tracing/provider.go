@@ -12,5 +12,9 @@ func AddProvider(name string, provider Provider) {
 }
 
 var providers = map[string]Provider{
+	"noop": func(config cfg.Config, logger mon.Logger) (Tracer, error) {
+		return NewNoopTracer(), nil
+	},
+	"otel": NewOtelTracer,
 	"xray": NewAwsTracer,
 }
@@ -1,9 +1,22 @@
 package tracing
 
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+)
+
 type SamplingConfiguration struct {
 	Version int          `json:"version" cfg:"version" default:"1"`
 	Default SampleRule   `json:"default" cfg:"default"`
 	Rules   []SampleRule `json:"rules" cfg:"rules"`
+	// rules matched against the name of stream consumers, they take precedence over the route rules
+	Consumers []ConsumerSampleRule `json:"-" cfg:"consumers"`
+	// keep traces containing an error even if they were not sampled
+	KeepErrors bool `json:"-" cfg:"keep_errors" default:"true"`
 }
 
 type SampleRule struct {
@@ -14,3 +27,173 @@ type SampleRule struct {
 	FixedTarget uint64  `json:"fixed_target" cfg:"fixed_target" default:"1"`
 	Rate        float64 `json:"rate" cfg:"rate" default:"0.05"`
 }
+
+type ConsumerSampleRule struct {
+	Description string `cfg:"description" default:"consumer"`
+	// name of the consumer, * and ? can be used as wildcards
+	Consumer    string  `cfg:"consumer" validate:"required"`
+	FixedTarget uint64  `cfg:"fixed_target" default:"1"`
+	Rate        float64 `cfg:"rate" default:"0.05"`
+}
+
+type SamplingRequest struct {
+	ServiceName string
+	HttpMethod  string
+	UrlPath     string
+	Consumer    string
+}
+
+type samplingRule struct {
+	fixedTarget uint64
+	rate        float64
+	second      int64
+	used        uint64
+}
+
+type samplingMatcher struct {
+	patterns []*regexp.Regexp
+	rule     *samplingRule
+}
+
+// Sampler decides if a trace is sampled by the first matching rule. Every rule samples the first fixed_target traces
+// per second and the given rate of the remaining ones.
+type Sampler struct {
+	clock      clock.Clock
+	keepErrors bool
+
+	lck       sync.Mutex
+	consumers []samplingMatcher
+	routes    []samplingMatcher
+	fallback  *samplingRule
+}
+
+func NewSampler(config SamplingConfiguration) (*Sampler, error) {
+	return NewSamplerWithInterfaces(clock.Provider, config)
+}
+
+func NewSamplerWithInterfaces(clock clock.Clock, config SamplingConfiguration) (*Sampler, error) {
+	sampler := &Sampler{
+		clock:      clock,
+		keepErrors: config.KeepErrors,
+		consumers:  make([]samplingMatcher, 0, len(config.Consumers)),
+		routes:     make([]samplingMatcher, 0, len(config.Rules)),
+		fallback:   newSamplingRule(config.Default.FixedTarget, config.Default.Rate),
+	}
+
+	for _, rule := range config.Consumers {
+		matcher, err := newSamplingMatcher(newSamplingRule(rule.FixedTarget, rule.Rate), rule.Consumer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consumer sampling rule %s: %w", rule.Description, err)
+		}
+
+		sampler.consumers = append(sampler.consumers, matcher)
+	}
+
+	for _, rule := range config.Rules {
+		matcher, err := newSamplingMatcher(newSamplingRule(rule.FixedTarget, rule.Rate), rule.ServiceName, rule.HttpMethod, rule.UrlPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling rule %s: %w", rule.Description, err)
+		}
+
+		sampler.routes = append(sampler.routes, matcher)
+	}
+
+	return sampler, nil
+}
+
+// KeepErrors reports if traces containing an error should be kept regardless of the sampling decision.
+func (s *Sampler) KeepErrors() bool {
+	return s.keepErrors
+}
+
+// SampleConsumer returns the sampling decision for a consumer and if any consumer rule matched the name at all.
+func (s *Sampler) SampleConsumer(consumer string) (sampled bool, matched bool) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	for _, matcher := range s.consumers {
+		if matcher.matches(consumer) {
+			return s.sample(matcher.rule), true
+		}
+	}
+
+	return false, false
+}
+
+func (s *Sampler) ShouldSample(request SamplingRequest) bool {
+	if request.Consumer != "" {
+		if sampled, matched := s.SampleConsumer(request.Consumer); matched {
+			return sampled
+		}
+	}
+
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	for _, matcher := range s.routes {
+		if matcher.matches(request.ServiceName, request.HttpMethod, request.UrlPath) {
+			return s.sample(matcher.rule)
+		}
+	}
+
+	return s.sample(s.fallback)
+}
+
+func (s *Sampler) sample(rule *samplingRule) bool {
+	now := s.clock.Now().Unix()
+
+	if rule.second != now {
+		rule.second = now
+		rule.used = 0
+	}
+
+	if rule.used < rule.fixedTarget {
+		rule.used++
+		return true
+	}
+
+	return rand.Float64() < rule.rate
+}
+
+func newSamplingRule(fixedTarget uint64, rate float64) *samplingRule {
+	return &samplingRule{
+		fixedTarget: fixedTarget,
+		rate:        rate,
+	}
+}
+
+func newSamplingMatcher(rule *samplingRule, patterns ...string) (samplingMatcher, error) {
+	matcher := samplingMatcher{
+		patterns: make([]*regexp.Regexp, len(patterns)),
+		rule:     rule,
+	}
+
+	for i, pattern := range patterns {
+		if pattern == "" {
+			pattern = "*"
+		}
+
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+
+		compiled, err := regexp.Compile("(?i)^" + expr + "$")
+		if err != nil {
+			return matcher, fmt.Errorf("can not compile pattern %s: %w", pattern, err)
+		}
+
+		matcher.patterns[i] = compiled
+	}
+
+	return matcher, nil
+}
+
+func (m samplingMatcher) matches(values ...string) bool {
+	for i, pattern := range m.patterns {
+		if !pattern.MatchString(values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"sync"
+)
+
+const otelErrorProcessorMaxTraces = 10000
+
+type otelSampler struct {
+	sampler     *Sampler
+	serviceName string
+}
+
+// NewOtelSampler applies the sampling rules to new traces. Spans of traces which are not sampled are still recorded
+// if errors should be kept, so the error processor can decide to export them once the trace finished.
+func NewOtelSampler(appId cfg.AppId, sampler *Sampler) sdktrace.Sampler {
+	return &otelSampler{
+		sampler:     sampler,
+		serviceName: fmt.Sprintf("%v-%v-%v-%v", appId.Project, appId.Environment, appId.Family, appId.Application),
+	}
+}
+
+func (s *otelSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanContextFromContext(p.ParentContext)
+
+	if parent.IsValid() {
+		return s.result(parent.IsSampled(), parent.TraceState())
+	}
+
+	request := SamplingRequest{
+		ServiceName: s.serviceName,
+	}
+
+	for _, attr := range p.Attributes {
+		switch attr.Key {
+		case semconv.HTTPMethodKey:
+			request.HttpMethod = attr.Value.AsString()
+		case semconv.HTTPTargetKey:
+			request.UrlPath = attr.Value.AsString()
+		}
+	}
+
+	if request.HttpMethod == "" {
+		request.Consumer = p.Name
+	}
+
+	return s.result(s.sampler.ShouldSample(request), trace.TraceState{})
+}
+
+func (s *otelSampler) Description() string {
+	return "GosolineSampler"
+}
+
+func (s *otelSampler) result(sampled bool, state trace.TraceState) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+
+	switch {
+	case sampled:
+		decision = sdktrace.RecordAndSample
+	case s.sampler.KeepErrors():
+		decision = sdktrace.RecordOnly
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: state,
+	}
+}
+
+type otelErrorTrace struct {
+	spans  []sdktrace.ReadOnlySpan
+	failed bool
+}
+
+type otelErrorProcessor struct {
+	logger   mon.Logger
+	sampler  *Sampler
+	exporter sdktrace.SpanExporter
+
+	lck     sync.Mutex
+	traces  map[trace.TraceID]*otelErrorTrace
+	dropped int
+}
+
+// NewOtelErrorProcessor buffers the spans of traces which were not sampled until their local root span ended and
+// exports them if any of the spans recorded an error. Nothing is buffered if the sampler doesn't keep errors. At most
+// 10000 traces are buffered, the spans of further traces are dropped and counted until a buffered trace ended.
+func NewOtelErrorProcessor(logger mon.Logger, sampler *Sampler, exporter sdktrace.SpanExporter) sdktrace.SpanProcessor {
+	return &otelErrorProcessor{
+		logger:   logger,
+		sampler:  sampler,
+		exporter: exporter,
+		traces:   make(map[trace.TraceID]*otelErrorTrace),
+	}
+}
+
+func (p *otelErrorProcessor) OnStart(_ context.Context, _ sdktrace.ReadWriteSpan) {
+}
+
+func (p *otelErrorProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	// sampled spans are exported by the batcher, the others only if errors should be kept
+	if s.SpanContext().IsSampled() || !p.sampler.KeepErrors() {
+		return
+	}
+
+	errorTrace, dropped := p.add(s)
+
+	if dropped > 0 {
+		p.logger.Warnf("dropped %d spans of unsampled traces as %d traces were buffered already", dropped, otelErrorProcessorMaxTraces)
+	}
+
+	if errorTrace == nil || !errorTrace.failed {
+		return
+	}
+
+	if err := p.exporter.ExportSpans(context.Background(), errorTrace.spans); err != nil {
+		p.logger.Warnf("can not export trace containing an error: %s", err.Error())
+	}
+}
+
+func (p *otelErrorProcessor) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func (p *otelErrorProcessor) ForceFlush(_ context.Context) error {
+	return nil
+}
+
+// add buffers the span and returns the whole trace as soon as its local root span ended. Once a trace left the full
+// buffer again, the number of spans dropped in the meantime is returned.
+func (p *otelErrorProcessor) add(s sdktrace.ReadOnlySpan) (*otelErrorTrace, int) {
+	p.lck.Lock()
+	defer p.lck.Unlock()
+
+	traceId := s.SpanContext().TraceID()
+	errorTrace, ok := p.traces[traceId]
+
+	if !ok {
+		if len(p.traces) >= otelErrorProcessorMaxTraces {
+			p.dropped++
+
+			return nil, 0
+		}
+
+		errorTrace = &otelErrorTrace{
+			spans: make([]sdktrace.ReadOnlySpan, 0),
+		}
+		p.traces[traceId] = errorTrace
+	}
+
+	errorTrace.spans = append(errorTrace.spans, s)
+	errorTrace.failed = errorTrace.failed || s.Status().Code == codes.Error
+
+	if s.Parent().IsValid() && !s.Parent().IsRemote() {
+		return nil, 0
+	}
+
+	delete(p.traces, traceId)
+
+	dropped := p.dropped
+	p.dropped = 0
+
+	return errorTrace, dropped
+}
+
+func otelHttpAttributes(method string, path string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(method),
+		semconv.HTTPTargetKey.String(path),
+	}
+}
@@ -0,0 +1,66 @@
+package tracing_test
+
+import (
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func getSampler(t *testing.T) (*tracing.Sampler, clock.FakeClock) {
+	fakeClock := clock.NewFakeClock()
+
+	sampler, err := tracing.NewSamplerWithInterfaces(fakeClock, tracing.SamplingConfiguration{
+		Default: tracing.SampleRule{
+			FixedTarget: 0,
+			Rate:        1,
+		},
+		Rules: []tracing.SampleRule{
+			{
+				ServiceName: "*",
+				HttpMethod:  "GET",
+				UrlPath:     "/health*",
+				FixedTarget: 0,
+				Rate:        0,
+			},
+		},
+		Consumers: []tracing.ConsumerSampleRule{
+			{
+				Consumer:    "event-?",
+				FixedTarget: 2,
+				Rate:        0,
+			},
+		},
+		KeepErrors: true,
+	})
+	assert.NoError(t, err)
+
+	return sampler, fakeClock
+}
+
+func TestSampler_Routes(t *testing.T) {
+	sampler, _ := getSampler(t)
+
+	assert.False(t, sampler.ShouldSample(tracing.SamplingRequest{HttpMethod: "GET", UrlPath: "/health/ready"}))
+	assert.False(t, sampler.ShouldSample(tracing.SamplingRequest{HttpMethod: "get", UrlPath: "/health"}))
+	assert.True(t, sampler.ShouldSample(tracing.SamplingRequest{HttpMethod: "POST", UrlPath: "/health"}))
+	assert.True(t, sampler.ShouldSample(tracing.SamplingRequest{HttpMethod: "GET", UrlPath: "/users"}))
+	assert.True(t, sampler.KeepErrors())
+}
+
+func TestSampler_ConsumerFixedTarget(t *testing.T) {
+	sampler, fakeClock := getSampler(t)
+
+	assert.True(t, sampler.ShouldSample(tracing.SamplingRequest{Consumer: "event-a"}))
+	assert.True(t, sampler.ShouldSample(tracing.SamplingRequest{Consumer: "event-b"}))
+	assert.False(t, sampler.ShouldSample(tracing.SamplingRequest{Consumer: "event-c"}), "the fixed target should be used up")
+
+	fakeClock.Advance(time.Second)
+	assert.True(t, sampler.ShouldSample(tracing.SamplingRequest{Consumer: "event-a"}), "the fixed target should be reset every second")
+
+	sampled, matched := sampler.SampleConsumer("other")
+	assert.False(t, sampled)
+	assert.False(t, matched)
+	assert.True(t, sampler.ShouldSample(tracing.SamplingRequest{Consumer: "other"}), "the default rule should apply")
+}
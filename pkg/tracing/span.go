@@ -18,8 +18,9 @@ type Span interface {
 }
 
 type awsSpan struct {
-	enabled bool
-	segment *xray.Segment
+	enabled    bool
+	keepErrors bool
+	segment    *xray.Segment
 }
 
 func (s awsSpan) GetId() string {
@@ -58,6 +59,10 @@ func (s awsSpan) AddError(err error) {
 	}
 
 	_ = s.segment.AddError(err)
+
+	if s.keepErrors {
+		s.sampleRoot()
+	}
 }
 
 func (s awsSpan) AddMetadata(key string, value interface{}) {
@@ -76,6 +81,19 @@ func (s awsSpan) Finish() {
 	s.segment.Close(nil)
 }
 
+// sampleRoot marks the whole trace as sampled, so traces containing an error are sent even if they were not sampled
+func (s awsSpan) sampleRoot() {
+	root := s.segment
+	for root.ParentSegment != root {
+		root = root.ParentSegment
+	}
+
+	root.Lock()
+	defer root.Unlock()
+
+	root.Sampled = true
+}
+
 func newSpan(ctx context.Context, seg *xray.Segment, app cfg.AppId) (context.Context, *awsSpan) {
 	span := &awsSpan{
 		enabled: true,
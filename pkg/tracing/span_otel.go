@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type otelSpan struct {
+	span     trace.Span
+	parentId string
+}
+
+func (s *otelSpan) GetId() string {
+	return s.span.SpanContext().SpanID().String()
+}
+
+func (s *otelSpan) GetTrace() *Trace {
+	spanContext := s.span.SpanContext()
+
+	return &Trace{
+		TraceId:  spanContext.TraceID().String(),
+		Id:       spanContext.SpanID().String(),
+		ParentId: s.parentId,
+		Sampled:  spanContext.IsSampled(),
+	}
+}
+
+func (s *otelSpan) AddAnnotation(key string, value string) {
+	s.span.SetAttributes(attribute.String(key, value))
+}
+
+func (s *otelSpan) AddError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *otelSpan) AddMetadata(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case fmt.Stringer:
+		s.span.SetAttributes(attribute.String(key, v.String()))
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			s.span.SetAttributes(attribute.String(key, fmt.Sprintf("%v", value)))
+			return
+		}
+
+		s.span.SetAttributes(attribute.String(key, string(encoded)))
+	}
+}
+
+func (s *otelSpan) Finish() {
+	s.span.End()
+}
+
+func newOtelSpan(ctx context.Context, span trace.Span, parentId string, app cfg.AppId) (context.Context, *otelSpan) {
+	s := &otelSpan{
+		span:     span,
+		parentId: parentId,
+	}
+
+	appFamily := fmt.Sprintf("%v-%v-%v", app.Project, app.Environment, app.Family)
+	appId := fmt.Sprintf("%v-%v-%v-%v", app.Project, app.Environment, app.Family, app.Application)
+	s.AddAnnotation("appFamily", appFamily)
+	s.AddAnnotation("appId", appId)
+
+	return ContextWithSpan(ctx, s), s
+}
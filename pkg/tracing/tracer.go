@@ -49,6 +49,25 @@ func ProvideTracer(config cfg.Config, logger mon.Logger) (Tracer, error) {
 	return tracerContainer.instance, nil
 }
 
+// A ShutdownAware tracer buffers spans, which have to be flushed before the application exits
+type ShutdownAware interface {
+	Shutdown(ctx context.Context) error
+}
+
+// ShutdownTracer flushes and stops the tracer provided by ProvideTracer if it buffers spans, like the otel tracer
+func ShutdownTracer(ctx context.Context) error {
+	tracerContainer.Lock()
+	defer tracerContainer.Unlock()
+
+	tracer, ok := tracerContainer.instance.(ShutdownAware)
+
+	if !ok {
+		return nil
+	}
+
+	return tracer.Shutdown(ctx)
+}
+
 func NewTracer(config cfg.Config, logger mon.Logger) (Tracer, error) {
 	settings := &TracerSettings{}
 	config.UnmarshalKey("tracing", settings)
@@ -23,12 +23,14 @@ type XRaySettings struct {
 	Address                     string
 	CtxMissingStrategy          ctxmissing.Strategy
 	SamplingStrategy            sampling.Strategy
+	Sampler                     *Sampler
 	StreamingMaxSubsegmentCount int
 }
 
 type awsTracer struct {
 	cfg.AppId
 	enabled bool
+	sampler *Sampler
 }
 
 func NewAwsTracer(config cfg.Config, logger mon.Logger) (Tracer, error) {
@@ -46,11 +48,17 @@ func NewAwsTracer(config cfg.Config, logger mon.Logger) (Tracer, error) {
 		return nil, fmt.Errorf("could not load sampling strategy: %w", err)
 	}
 
+	sampler, err := NewSampler(settings.Sampling)
+	if err != nil {
+		return nil, fmt.Errorf("could not load sampling rules: %w", err)
+	}
+
 	xRaySettings := &XRaySettings{
 		Enabled:                     settings.Enabled,
 		Address:                     addr,
 		CtxMissingStrategy:          ctxMissingStrategy,
 		SamplingStrategy:            samplingStrategy,
+		Sampler:                     sampler,
 		StreamingMaxSubsegmentCount: settings.StreamingMaxSubsegmentCount,
 	}
 
@@ -86,6 +94,7 @@ func NewAwsTracerWithInterfaces(logger mon.Logger, appId cfg.AppId, settings *XR
 	return &awsTracer{
 		AppId:   appId,
 		enabled: settings.Enabled,
+		sampler: settings.Sampler,
 	}, nil
 }
 
@@ -102,7 +111,7 @@ func (t *awsTracer) StartSubSpan(ctx context.Context, name string) (context.Cont
 		return ctx, disabledSpan()
 	}
 
-	ctxWithSpan, span = newSpan(ctxWithSegment, segment, t.AppId)
+	ctxWithSpan, span = t.newSpan(ctxWithSegment, segment)
 
 	return ctxWithSpan, span
 }
@@ -112,7 +121,10 @@ func (t *awsTracer) StartSpan(name string) (context.Context, Span) {
 		return context.Background(), disabledRootSpan()
 	}
 
-	return newRootSpan(context.Background(), name, t.AppId)
+	ctx, transaction := t.newRootSpan(context.Background(), name)
+	t.sampleConsumer(name, transaction)
+
+	return ctx, transaction
 }
 
 func (t *awsTracer) StartSpanFromContext(ctx context.Context, name string) (context.Context, Span) {
@@ -121,7 +133,7 @@ func (t *awsTracer) StartSpanFromContext(ctx context.Context, name string) (cont
 	}
 
 	parentSpan := GetSpanFromContext(ctx)
-	ctx, transaction := newRootSpan(ctx, name, t.AppId)
+	ctx, transaction := t.newRootSpan(ctx, name)
 
 	if parentSpan != nil {
 		parentTrace := parentSpan.GetTrace()
@@ -142,6 +154,8 @@ func (t *awsTracer) StartSpanFromContext(ctx context.Context, name string) (cont
 		return ctx, transaction
 	}
 
+	t.sampleConsumer(name, transaction)
+
 	return ctx, transaction
 }
 
@@ -155,7 +169,7 @@ func (t *awsTracer) HttpHandler(h http.Handler) http.Handler {
 		ctx := r.Context()
 		seg := xray.GetSegment(r.Context())
 
-		ctx, _ = newSpan(ctx, seg, t.AppId)
+		ctx, _ = t.newSpan(ctx, seg)
 		r = r.WithContext(ctx)
 
 		h.ServeHTTP(w, r)
@@ -164,6 +178,33 @@ func (t *awsTracer) HttpHandler(h http.Handler) http.Handler {
 	return xray.Handler(xray.NewFixedSegmentNamer(name), handlerFunc)
 }
 
+func (t *awsTracer) newSpan(ctx context.Context, seg *xray.Segment) (context.Context, *awsSpan) {
+	ctx, span := newSpan(ctx, seg, t.AppId)
+	span.keepErrors = t.sampler != nil && t.sampler.KeepErrors()
+
+	return ctx, span
+}
+
+func (t *awsTracer) newRootSpan(ctx context.Context, name string) (context.Context, *awsRootSpan) {
+	ctx, transaction := newRootSpan(ctx, name, t.AppId)
+	transaction.keepErrors = t.sampler != nil && t.sampler.KeepErrors()
+
+	return ctx, transaction
+}
+
+// sampleConsumer overrides the sampling decision of xray for new traces if a consumer rule matches the name
+func (t *awsTracer) sampleConsumer(name string, transaction *awsRootSpan) {
+	if t.sampler == nil {
+		return
+	}
+
+	if sampled, matched := t.sampler.SampleConsumer(name); matched {
+		transaction.segment.Lock()
+		transaction.segment.Sampled = sampled
+		transaction.segment.Unlock()
+	}
+}
+
 func lookupAddr(appId cfg.AppId, settings *TracerSettings) string {
 	addressValue := settings.AddressValue
 
@@ -0,0 +1,229 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strings"
+)
+
+const otelInstrumentationName = "github.com/applike/gosoline/pkg/tracing"
+
+type OtelSettings struct {
+	// address of the OTLP gRPC receiver, usually an OpenTelemetry collector
+	Endpoint string `cfg:"endpoint" default:"localhost:4317"`
+	Insecure bool   `cfg:"insecure" default:"true"`
+}
+
+type otelTracer struct {
+	cfg.AppId
+	provider   trace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+func NewOtelTracer(config cfg.Config, logger mon.Logger) (Tracer, error) {
+	appId := cfg.AppId{}
+	appId.PadFromConfig(config)
+
+	tracerSettings := &TracerSettings{}
+	config.UnmarshalKey("tracing", tracerSettings)
+
+	settings := &OtelSettings{}
+	config.UnmarshalKey("tracing.otel", settings)
+
+	sampler, err := NewSampler(tracerSettings.Sampling)
+	if err != nil {
+		return nil, fmt.Errorf("could not load sampling rules: %w", err)
+	}
+
+	ctx := context.Background()
+	options := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(settings.Endpoint),
+	}
+
+	if settings.Insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("can not create otlp trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(otelResourceAttributes(appId)...))
+	if err != nil {
+		return nil, fmt.Errorf("can not create otel resource: %w", err)
+	}
+
+	providerOptions := []sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(NewOtelSampler(appId, sampler)),
+	}
+
+	if sampler.KeepErrors() {
+		providerOptions = append(providerOptions, sdktrace.WithSpanProcessor(NewOtelErrorProcessor(logger, sampler, exporter)))
+	}
+
+	provider := sdktrace.NewTracerProvider(providerOptions...)
+
+	logger.Infof("exporting otel traces to %s", settings.Endpoint)
+
+	return NewOtelTracerWithInterfaces(appId, provider), nil
+}
+
+func NewOtelTracerWithInterfaces(appId cfg.AppId, provider trace.TracerProvider) *otelTracer {
+	return &otelTracer{
+		AppId:      appId,
+		provider:   provider,
+		tracer:     provider.Tracer(otelInstrumentationName),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+// Shutdown exports the spans kept by the batcher of the provider and stops it
+func (t *otelTracer) Shutdown(ctx context.Context) error {
+	provider, ok := t.provider.(interface {
+		Shutdown(ctx context.Context) error
+	})
+
+	if !ok {
+		return nil
+	}
+
+	return provider.Shutdown(ctx)
+}
+
+func (t *otelTracer) StartSubSpan(ctx context.Context, name string) (context.Context, Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, disabledSpan()
+	}
+
+	parentId := ""
+	if parent := GetSpanFromContext(ctx); parent != nil {
+		parentId = parent.GetId()
+	}
+
+	return t.start(ctx, name, parentId, trace.SpanKindInternal)
+}
+
+func (t *otelTracer) StartSpan(name string) (context.Context, Span) {
+	return t.start(context.Background(), name, "", trace.SpanKindServer, trace.WithNewRoot())
+}
+
+func (t *otelTracer) StartSpanFromContext(ctx context.Context, name string) (context.Context, Span) {
+	if parentSpan := GetSpanFromContext(ctx); parentSpan != nil {
+		return t.startFromTrace(ctx, name, parentSpan.GetTrace().TraceId, parentSpan.GetTrace().Id, parentSpan.GetTrace().Sampled)
+	}
+
+	if parentTrace := GetTraceFromContext(ctx); parentTrace != nil {
+		return t.startFromTrace(ctx, name, parentTrace.TraceId, parentTrace.ParentId, parentTrace.Sampled)
+	}
+
+	return t.start(ctx, name, "", trace.SpanKindServer, trace.WithNewRoot())
+}
+
+func (t *otelTracer) HttpHandler(h http.Handler) http.Handler {
+	name := fmt.Sprintf("%v-%v-%v-%v", t.Project, t.Environment, t.Family, t.Application)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := t.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		parentId := ""
+
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			ctx = t.extractAmznTraceId(ctx, r.Header)
+		}
+
+		if remote := trace.SpanContextFromContext(ctx); remote.IsValid() {
+			parentId = remote.SpanID().String()
+		}
+
+		attributes := otelHttpAttributes(r.Method, r.URL.Path)
+		ctx, span := t.start(ctx, name, parentId, trace.SpanKindServer, trace.WithAttributes(attributes...))
+		defer span.Finish()
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (t *otelTracer) extractAmznTraceId(ctx context.Context, header http.Header) context.Context {
+	amznTrace, err := StringToTrace(header.Get(HeaderAmznTraceId))
+	if err != nil {
+		return ctx
+	}
+
+	remote, err := otelRemoteSpanContext(amznTrace.TraceId, amznTrace.ParentId, amznTrace.Sampled)
+	if err != nil {
+		return ctx
+	}
+
+	return trace.ContextWithRemoteSpanContext(ctx, remote)
+}
+
+func (t *otelTracer) startFromTrace(ctx context.Context, name string, traceId string, parentId string, sampled bool) (context.Context, Span) {
+	remote, err := otelRemoteSpanContext(traceId, parentId, sampled)
+	if err != nil {
+		return t.start(ctx, name, "", trace.SpanKindServer, trace.WithNewRoot())
+	}
+
+	ctx = trace.ContextWithRemoteSpanContext(ctx, remote)
+
+	return t.start(ctx, name, parentId, trace.SpanKindServer)
+}
+
+func (t *otelTracer) start(ctx context.Context, name string, parentId string, kind trace.SpanKind, options ...trace.SpanStartOption) (context.Context, Span) {
+	options = append(options, trace.WithSpanKind(kind))
+	ctx, span := t.tracer.Start(ctx, name, options...)
+
+	return newOtelSpan(ctx, span, parentId, t.AppId)
+}
+
+func otelResourceAttributes(appId cfg.AppId) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.ServiceNameKey.String(appId.Application),
+		semconv.ServiceNamespaceKey.String(fmt.Sprintf("%v-%v", appId.Project, appId.Family)),
+		semconv.DeploymentEnvironmentKey.String(appId.Environment),
+		attribute.String("gosoline.project", appId.Project),
+		attribute.String("gosoline.family", appId.Family),
+		attribute.String("gosoline.application", appId.Application),
+	}
+}
+
+// otelRemoteSpanContext builds the parent span context from a trace id in W3C (hex) or X-Ray (1-<time>-<id>) format
+func otelRemoteSpanContext(traceId string, parentId string, sampled bool) (trace.SpanContext, error) {
+	if parts := strings.Split(traceId, "-"); len(parts) == 3 {
+		traceId = parts[1] + parts[2]
+	}
+
+	tid, err := trace.TraceIDFromHex(traceId)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid trace id %s: %w", traceId, err)
+	}
+
+	sid, err := trace.SpanIDFromHex(parentId)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid parent id %s: %w", parentId, err)
+	}
+
+	var flags trace.TraceFlags
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	}), nil
+}
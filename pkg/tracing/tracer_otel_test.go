@@ -0,0 +1,196 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func getOtelTracer() (tracing.Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	appId := cfg.AppId{
+		Project:     "project",
+		Environment: "test",
+		Family:      "family",
+		Application: "app",
+	}
+
+	return tracing.NewOtelTracerWithInterfaces(appId, provider), recorder
+}
+
+func TestOtelTracer_StartSubSpan(t *testing.T) {
+	tracer, recorder := getOtelTracer()
+
+	ctx, trans := tracer.StartSpan("test_trans")
+	_, span := tracer.StartSubSpan(ctx, "test_span")
+
+	span.AddAnnotation("key", "value")
+	span.AddError(errors.New("error"))
+	span.Finish()
+	trans.Finish()
+
+	assert.Equal(t, trans.GetTrace().TraceId, span.GetTrace().TraceId, "the trace ids should match")
+	assert.NotEqual(t, trans.GetTrace().Id, span.GetTrace().Id, "the span ids should be different")
+	assert.Equal(t, trans.GetTrace().Id, span.GetTrace().ParentId, "the parent of the span should be the transaction")
+
+	ended := recorder.Ended()
+	assert.Len(t, ended, 2)
+	assert.Equal(t, "test_span", ended[0].Name())
+	assert.Equal(t, trans.GetTrace().Id, ended[0].Parent().SpanID().String())
+	assert.Len(t, ended[0].Events(), 1, "the error should be recorded as event")
+
+	attributes := map[string]string{}
+	for _, attr := range ended[0].Attributes() {
+		attributes[string(attr.Key)] = attr.Value.AsString()
+	}
+
+	assert.Equal(t, "value", attributes["key"])
+	assert.Equal(t, "project-test-family-app", attributes["appId"])
+}
+
+func TestOtelTracer_StartSubSpanWithoutTransaction(t *testing.T) {
+	tracer, recorder := getOtelTracer()
+
+	_, span := tracer.StartSubSpan(context.Background(), "test_span")
+	span.Finish()
+
+	assert.Empty(t, recorder.Ended(), "no span should be recorded without a transaction")
+}
+
+func TestOtelTracer_StartSpanFromContextWithTrace(t *testing.T) {
+	tracer, _ := getOtelTracer()
+
+	ctx := tracing.ContextWithTrace(context.Background(), &tracing.Trace{
+		TraceId:  "1-5e3d557d-d06c248cc50169bd71b44fec",
+		ParentId: "bb9d0e1b6e5c1d8b",
+		Sampled:  true,
+	})
+
+	_, trans := tracer.StartSpanFromContext(ctx, "test_trans")
+
+	assert.Equal(t, "5e3d557dd06c248cc50169bd71b44fec", trans.GetTrace().TraceId, "the x-ray trace id should be converted")
+	assert.Equal(t, "bb9d0e1b6e5c1d8b", trans.GetTrace().ParentId)
+	assert.True(t, trans.GetTrace().Sampled)
+}
+
+func TestOtelTracer_HttpHandler(t *testing.T) {
+	tracer, recorder := getOtelTracer()
+
+	var span tracing.Span
+	handler := tracer.HttpHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = tracing.GetSpanFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-5e3d557dd06c248cc50169bd71b44fec-bb9d0e1b6e5c1d8b-01")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NotNil(t, span)
+	assert.Equal(t, "5e3d557dd06c248cc50169bd71b44fec", span.GetTrace().TraceId)
+	assert.Equal(t, "bb9d0e1b6e5c1d8b", span.GetTrace().ParentId)
+	assert.Len(t, recorder.Ended(), 1)
+}
+
+func TestOtelTracer_KeepErrors(t *testing.T) {
+	sampler, err := tracing.NewSampler(tracing.SamplingConfiguration{
+		Default: tracing.SampleRule{
+			FixedTarget: 0,
+			Rate:        0,
+		},
+		KeepErrors: true,
+	})
+	assert.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(tracing.NewOtelSampler(cfg.AppId{}, sampler)),
+		sdktrace.WithSpanProcessor(tracing.NewOtelErrorProcessor(mocks.NewLoggerMockedAll(), sampler, exporter)),
+	)
+	tracer := tracing.NewOtelTracerWithInterfaces(cfg.AppId{}, provider)
+
+	ctx, trans := tracer.StartSpan("ok")
+	_, span := tracer.StartSubSpan(ctx, "sub")
+	span.Finish()
+	trans.Finish()
+
+	assert.False(t, trans.GetTrace().Sampled)
+	assert.Empty(t, exporter.GetSpans(), "traces without errors should be dropped")
+
+	ctx, trans = tracer.StartSpan("failed")
+	_, span = tracer.StartSubSpan(ctx, "sub")
+	span.AddError(errors.New("error"))
+	span.Finish()
+	trans.Finish()
+
+	assert.Len(t, exporter.GetSpans(), 2, "traces containing an error should be kept")
+}
+
+func TestOtelTracer_KeepErrorsDropsSpansOfFullBuffer(t *testing.T) {
+	sampler, err := tracing.NewSampler(tracing.SamplingConfiguration{
+		Default: tracing.SampleRule{
+			FixedTarget: 0,
+			Rate:        0,
+		},
+		KeepErrors: true,
+	})
+	assert.NoError(t, err)
+
+	logger := new(mocks.Logger)
+	logger.On("Warnf", "dropped %d spans of unsampled traces as %d traces were buffered already", 1, 10000).Once()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(tracing.NewOtelSampler(cfg.AppId{}, sampler)),
+		sdktrace.WithSpanProcessor(tracing.NewOtelErrorProcessor(logger, sampler, exporter)),
+	)
+	tracer := tracing.NewOtelTracerWithInterfaces(cfg.AppId{}, provider)
+
+	// every trace stays buffered until its root span ended
+	transactions := make([]tracing.Span, 0, 10001)
+
+	for i := 0; i < 10001; i++ {
+		ctx, trans := tracer.StartSpan("pending")
+		_, span := tracer.StartSubSpan(ctx, "sub")
+		span.Finish()
+
+		transactions = append(transactions, trans)
+	}
+
+	transactions[0].Finish()
+
+	logger.AssertExpectations(t)
+}
+
+// keepingExporter keeps the exported spans after the shutdown, the in memory exporter drops them
+type keepingExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (e keepingExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+func TestOtelTracer_Shutdown(t *testing.T) {
+	exporter := keepingExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	tracer := tracing.NewOtelTracerWithInterfaces(cfg.AppId{}, provider)
+
+	_, trans := tracer.StartSpan("test_trans")
+	trans.Finish()
+
+	err := tracer.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, exporter.GetSpans(), 1, "the buffered span should be exported on shutdown")
+}
@@ -40,3 +40,29 @@ func (r Repository) Update(ctx context.Context, value db_repo.ModelBased) error
 
 	return err
 }
+
+func (r Repository) CreateBatch(ctx context.Context, values []db_repo.ModelBased) error {
+	if err := r.validateAll(ctx, values); err != nil {
+		return err
+	}
+
+	return r.Repository.CreateBatch(ctx, values)
+}
+
+func (r Repository) Upsert(ctx context.Context, values []db_repo.ModelBased, conflictColumns ...string) error {
+	if err := r.validateAll(ctx, values); err != nil {
+		return err
+	}
+
+	return r.Repository.Upsert(ctx, values, conflictColumns...)
+}
+
+func (r Repository) validateAll(ctx context.Context, values []db_repo.ModelBased) error {
+	for _, value := range values {
+		if err := r.validator.IsValid(ctx, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}